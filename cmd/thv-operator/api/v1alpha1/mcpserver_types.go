@@ -3,6 +3,9 @@ package v1alpha1
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/stacklok/toolhive/pkg/audit"
+	"github.com/stacklok/toolhive/pkg/ratelimit"
 )
 
 // Condition types for MCPServer
@@ -138,6 +141,13 @@ type MCPServerSpec struct {
 	// +optional
 	OIDCConfig *OIDCConfigRef `json:"oidcConfig,omitempty"`
 
+	// EmbeddedAuthServer configures an OAuth 2.1 authorization server served by the proxy.
+	// Users authenticate with an upstream OIDC provider. Unless OIDCConfig is set, the proxy
+	// validates the access tokens issued by this server.
+	// Requires the sse or streamable-http transport.
+	// +optional
+	EmbeddedAuthServer *EmbeddedAuthServerConfig `json:"embeddedAuthServer,omitempty"`
+
 	// AuthzConfig defines authorization policy configuration for the MCP server
 	// +optional
 	AuthzConfig *AuthzConfigRef `json:"authzConfig,omitempty"`
@@ -146,6 +156,11 @@ type MCPServerSpec struct {
 	// +optional
 	Audit *AuditConfig `json:"audit,omitempty"`
 
+	// RateLimit defines token bucket limits for MCP requests to the server.
+	// Rejected requests receive HTTP 429 with a Retry-After header.
+	// +optional
+	RateLimit *ratelimit.Config `json:"rateLimit,omitempty"`
+
 	// ToolsFilter is the filter on tools applied to the MCP server
 	// Deprecated: Use ToolConfigRef instead
 	// +optional
@@ -180,12 +195,136 @@ type MCPServerSpec struct {
 	// +optional
 	EndpointPrefix string `json:"endpointPrefix,omitempty"`
 
+	// SessionStorage configures where the proxy keeps MCP session state.
+	// Use a shared backend such as Redis when running more than one proxy replica.
+	// +optional
+	SessionStorage *SessionStorageConfig `json:"sessionStorage,omitempty"`
+
 	// GroupRef is the name of the MCPGroup this server belongs to
 	// Must reference an existing MCPGroup in the same namespace
 	// +optional
 	GroupRef string `json:"groupRef,omitempty"`
 }
 
+// Session storage types
+const (
+	// SessionStorageTypeLocal keeps sessions in the proxy's memory
+	SessionStorageTypeLocal = "local"
+	// SessionStorageTypeRedis keeps sessions in a Redis or Valkey server
+	SessionStorageTypeRedis = "redis"
+)
+
+// SessionStorageConfig defines the session storage backend for the proxy
+type SessionStorageConfig struct {
+	// Type is the session storage backend
+	// +kubebuilder:validation:Enum=local;redis
+	// +kubebuilder:default=local
+	// +optional
+	Type string `json:"type,omitempty"`
+
+	// Redis configures the Redis/Valkey backend
+	// Required when Type is "redis"
+	// +optional
+	Redis *RedisSessionStorageConfig `json:"redis,omitempty"`
+}
+
+// RedisSessionStorageConfig defines the connection to a Redis or Valkey server
+type RedisSessionStorageConfig struct {
+	// Address is the Redis server address (host:port)
+	// +kubebuilder:validation:Required
+	Address string `json:"address"`
+
+	// DB is the Redis database number
+	// +kubebuilder:default=0
+	// +optional
+	DB int32 `json:"db,omitempty"`
+
+	// KeyPrefix is prepended to every session key
+	// +optional
+	KeyPrefix string `json:"keyPrefix,omitempty"`
+
+	// PasswordRef references a secret containing the Redis password
+	// +optional
+	PasswordRef *SecretKeyRef `json:"passwordRef,omitempty"`
+
+	// TLS enables TLS for the Redis connection
+	// +kubebuilder:default=false
+	// +optional
+	TLS bool `json:"tls,omitempty"`
+}
+
+// EmbeddedAuthServerConfig defines the authorization server served by the proxy.
+// Its state is kept in the proxy's memory, so issued tokens do not survive proxy restarts.
+type EmbeddedAuthServerConfig struct {
+	// Issuer is the issuer identifier and public base URL of the authorization server,
+	// typically the externally reachable URL of the proxy (e.g., https://mcp.example.com)
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^https?://`
+	Issuer string `json:"issuer"`
+
+	// AllowedAudiences are the resource servers tokens can be issued for, typically the MCP server URL.
+	// The first audience is validated by the proxy.
+	// +kubebuilder:validation:MinItems=1
+	AllowedAudiences []string `json:"allowedAudiences"`
+
+	// ScopesSupported are the scopes clients may request
+	// Defaults to openid, profile and email
+	// +optional
+	ScopesSupported []string `json:"scopesSupported,omitempty"`
+
+	// AccessTokenLifespan is the lifespan of access tokens (e.g., "1h")
+	// +optional
+	AccessTokenLifespan string `json:"accessTokenLifespan,omitempty"`
+
+	// RefreshTokenLifespan is the lifespan of refresh tokens (e.g., "720h")
+	// +optional
+	RefreshTokenLifespan string `json:"refreshTokenLifespan,omitempty"`
+
+	// AuthCodeLifespan is the lifespan of authorization codes (e.g., "10m")
+	// +optional
+	AuthCodeLifespan string `json:"authCodeLifespan,omitempty"`
+
+	// SigningKeyRef references a secret containing a PEM encoded RSA or EC private key used to
+	// sign access tokens. If unset, an ephemeral key is generated when the proxy starts.
+	// +optional
+	SigningKeyRef *SecretKeyRef `json:"signingKeyRef,omitempty"`
+
+	// HMACSecretRefs reference secrets used to sign authorization codes and refresh tokens.
+	// The first is the current secret, the rest are rotated secrets still accepted for verification.
+	// If empty, a random secret is generated when the proxy starts.
+	// +optional
+	HMACSecretRefs []SecretKeyRef `json:"hmacSecretRefs,omitempty"`
+
+	// Upstream configures the OIDC provider users authenticate with
+	// +kubebuilder:validation:Required
+	Upstream EmbeddedAuthServerUpstreamConfig `json:"upstream"`
+}
+
+// EmbeddedAuthServerUpstreamConfig defines the upstream OIDC provider of the embedded authorization server
+type EmbeddedAuthServerUpstreamConfig struct {
+	// Issuer is the issuer URL of the upstream OIDC provider
+	// +kubebuilder:validation:Required
+	Issuer string `json:"issuer"`
+
+	// ClientID is the client ID registered with the upstream provider
+	// +kubebuilder:validation:Required
+	ClientID string `json:"clientId"`
+
+	// ClientSecretRef references a secret containing the upstream client secret
+	// +optional
+	ClientSecretRef *SecretKeyRef `json:"clientSecretRef,omitempty"`
+
+	// Scopes are the scopes requested from the upstream provider
+	// Defaults to openid, profile and email
+	// +optional
+	Scopes []string `json:"scopes,omitempty"`
+
+	// RedirectURI is the callback URL registered with the upstream provider
+	// Defaults to the issuer followed by /oauth/callback
+	// +optional
+	RedirectURI string `json:"redirectUri,omitempty"`
+}
+
 // ResourceOverrides defines overrides for annotations and labels on created resources
 type ResourceOverrides struct {
 	// ProxyDeployment defines overrides for the Proxy Deployment resource (toolhive proxy)
@@ -596,6 +735,11 @@ type AuditConfig struct {
 	// +kubebuilder:default=false
 	// +optional
 	Enabled bool `json:"enabled,omitempty"`
+
+	// Sinks are additional destinations of the audit events, such as syslog servers,
+	// HTTP webhooks or OTLP collectors. Events are still written to the container logs.
+	// +optional
+	Sinks []audit.SinkConfig `json:"sinks,omitempty"`
 }
 
 // TelemetryConfig defines observability configuration for the MCP server
@@ -21,6 +21,8 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"github.com/stacklok/toolhive/pkg/audit"
+	"github.com/stacklok/toolhive/pkg/ratelimit"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -63,6 +65,13 @@ func (in *APIStatus) DeepCopy() *APIStatus {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AuditConfig) DeepCopyInto(out *AuditConfig) {
 	*out = *in
+	if in.Sinks != nil {
+		in, out := &in.Sinks, &out.Sinks
+		*out = make([]audit.SinkConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuditConfig.
@@ -166,6 +175,67 @@ func (in *DiscoveredBackend) DeepCopy() *DiscoveredBackend {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EmbeddedAuthServerConfig) DeepCopyInto(out *EmbeddedAuthServerConfig) {
+	*out = *in
+	if in.AllowedAudiences != nil {
+		in, out := &in.AllowedAudiences, &out.AllowedAudiences
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ScopesSupported != nil {
+		in, out := &in.ScopesSupported, &out.ScopesSupported
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SigningKeyRef != nil {
+		in, out := &in.SigningKeyRef, &out.SigningKeyRef
+		*out = new(SecretKeyRef)
+		**out = **in
+	}
+	if in.HMACSecretRefs != nil {
+		in, out := &in.HMACSecretRefs, &out.HMACSecretRefs
+		*out = make([]SecretKeyRef, len(*in))
+		copy(*out, *in)
+	}
+	in.Upstream.DeepCopyInto(&out.Upstream)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EmbeddedAuthServerConfig.
+func (in *EmbeddedAuthServerConfig) DeepCopy() *EmbeddedAuthServerConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(EmbeddedAuthServerConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EmbeddedAuthServerUpstreamConfig) DeepCopyInto(out *EmbeddedAuthServerUpstreamConfig) {
+	*out = *in
+	if in.ClientSecretRef != nil {
+		in, out := &in.ClientSecretRef, &out.ClientSecretRef
+		*out = new(SecretKeyRef)
+		**out = **in
+	}
+	if in.Scopes != nil {
+		in, out := &in.Scopes, &out.Scopes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EmbeddedAuthServerUpstreamConfig.
+func (in *EmbeddedAuthServerUpstreamConfig) DeepCopy() *EmbeddedAuthServerUpstreamConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(EmbeddedAuthServerUpstreamConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EnvVar) DeepCopyInto(out *EnvVar) {
 	*out = *in
@@ -879,7 +949,7 @@ func (in *MCPRemoteProxySpec) DeepCopyInto(out *MCPRemoteProxySpec) {
 	if in.Audit != nil {
 		in, out := &in.Audit, &out.Audit
 		*out = new(AuditConfig)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.ToolConfigRef != nil {
 		in, out := &in.ToolConfigRef, &out.ToolConfigRef
@@ -1039,6 +1109,11 @@ func (in *MCPServerSpec) DeepCopyInto(out *MCPServerSpec) {
 		*out = new(OIDCConfigRef)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.EmbeddedAuthServer != nil {
+		in, out := &in.EmbeddedAuthServer, &out.EmbeddedAuthServer
+		*out = new(EmbeddedAuthServerConfig)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.AuthzConfig != nil {
 		in, out := &in.AuthzConfig, &out.AuthzConfig
 		*out = new(AuthzConfigRef)
@@ -1047,7 +1122,12 @@ func (in *MCPServerSpec) DeepCopyInto(out *MCPServerSpec) {
 	if in.Audit != nil {
 		in, out := &in.Audit, &out.Audit
 		*out = new(AuditConfig)
-		**out = **in
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RateLimit != nil {
+		in, out := &in.RateLimit, &out.RateLimit
+		*out = new(ratelimit.Config)
+		(*in).DeepCopyInto(*out)
 	}
 	if in.ToolsFilter != nil {
 		in, out := &in.ToolsFilter, &out.ToolsFilter
@@ -1069,6 +1149,11 @@ func (in *MCPServerSpec) DeepCopyInto(out *MCPServerSpec) {
 		*out = new(TelemetryConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.SessionStorage != nil {
+		in, out := &in.SessionStorage, &out.SessionStorage
+		*out = new(SessionStorageConfig)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPServerSpec.
@@ -1497,6 +1582,26 @@ func (in *ProxyDeploymentOverrides) DeepCopy() *ProxyDeploymentOverrides {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RedisSessionStorageConfig) DeepCopyInto(out *RedisSessionStorageConfig) {
+	*out = *in
+	if in.PasswordRef != nil {
+		in, out := &in.PasswordRef, &out.PasswordRef
+		*out = new(SecretKeyRef)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisSessionStorageConfig.
+func (in *RedisSessionStorageConfig) DeepCopy() *RedisSessionStorageConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(RedisSessionStorageConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RegistryFilter) DeepCopyInto(out *RegistryFilter) {
 	*out = *in
@@ -1638,6 +1743,26 @@ func (in *SecretRef) DeepCopy() *SecretRef {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SessionStorageConfig) DeepCopyInto(out *SessionStorageConfig) {
+	*out = *in
+	if in.Redis != nil {
+		in, out := &in.Redis, &out.Redis
+		*out = new(RedisSessionStorageConfig)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SessionStorageConfig.
+func (in *SessionStorageConfig) DeepCopy() *SessionStorageConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(SessionStorageConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StorageReference) DeepCopyInto(out *StorageReference) {
 	*out = *in
@@ -26,7 +26,7 @@ func (r *MCPRemoteProxyReconciler) deploymentForMCPRemoteProxy(
 
 	// Build deployment components using helper functions
 	args := r.buildContainerArgs()
-	volumeMounts, volumes := r.buildVolumesForProxy(proxy)
+	volumeMounts, volumes := r.buildVolumesForProxy(ctx, proxy)
 	env := r.buildEnvVarsForProxy(ctx, proxy)
 	resources := ctrlutil.BuildResourceRequirements(proxy.Spec.Resources)
 	deploymentLabels, deploymentAnnotations := r.buildDeploymentMetadata(ls, proxy)
@@ -87,8 +87,8 @@ func (*MCPRemoteProxyReconciler) buildContainerArgs() []string {
 }
 
 // buildVolumesForProxy builds volumes and volume mounts for the proxy
-func (*MCPRemoteProxyReconciler) buildVolumesForProxy(
-	proxy *mcpv1alpha1.MCPRemoteProxy,
+func (r *MCPRemoteProxyReconciler) buildVolumesForProxy(
+	ctx context.Context, proxy *mcpv1alpha1.MCPRemoteProxy,
 ) ([]corev1.VolumeMount, []corev1.Volume) {
 	volumeMounts := []corev1.VolumeMount{}
 	volumes := []corev1.Volume{}
@@ -118,6 +118,12 @@ func (*MCPRemoteProxyReconciler) buildVolumesForProxy(
 		volumeMounts = append(volumeMounts, *authzVolumeMount)
 		volumes = append(volumes, *authzVolume)
 	}
+	opaBundleVolumeMount, opaBundleVolume := ctrlutil.GenerateOPABundleVolumeConfig(
+		ctx, r.Client, proxy.Namespace, proxy.Spec.AuthzConfig)
+	if opaBundleVolumeMount != nil {
+		volumeMounts = append(volumeMounts, *opaBundleVolumeMount)
+		volumes = append(volumes, *opaBundleVolume)
+	}
 
 	return volumeMounts, volumes
 }
@@ -1007,6 +1007,28 @@ func (r *MCPServerReconciler) deploymentForMCPServer(
 		}
 	}
 
+	// Add embedded authorization server upstream client secret environment variable
+	authServerEnvVars, err := ctrlutil.GenerateEmbeddedAuthServerEnvVars(
+		ctx, r.Client, m.Namespace, m.Spec.EmbeddedAuthServer,
+	)
+	if err != nil {
+		ctxLogger := log.FromContext(ctx)
+		ctxLogger.Error(err, "Failed to generate embedded authorization server environment variables")
+	} else {
+		env = append(env, authServerEnvVars...)
+	}
+
+	// Add Redis session storage password environment variable if a secret is referenced
+	sessionPasswordEnvVar, err := ctrlutil.GenerateSessionStoragePasswordEnvVar(
+		ctx, r.Client, m.Namespace, m.Spec.SessionStorage,
+	)
+	if err != nil {
+		ctxLogger := log.FromContext(ctx)
+		ctxLogger.Error(err, "Failed to generate session storage password environment variable")
+	} else if sessionPasswordEnvVar != nil {
+		env = append(env, *sessionPasswordEnvVar)
+	}
+
 	// Add user-specified proxy environment variables from ResourceOverrides
 	if m.Spec.ResourceOverrides != nil && m.Spec.ResourceOverrides.ProxyDeployment != nil {
 		for _, envVar := range m.Spec.ResourceOverrides.ProxyDeployment.Env {
@@ -1061,6 +1083,19 @@ func (r *MCPServerReconciler) deploymentForMCPServer(
 		volumeMounts = append(volumeMounts, *authzVolumeMount)
 		volumes = append(volumes, *authzVolume)
 	}
+	opaBundleVolumeMount, opaBundleVolume := ctrlutil.GenerateOPABundleVolumeConfig(
+		ctx, r.Client, m.Namespace, m.Spec.AuthzConfig)
+	if opaBundleVolumeMount != nil {
+		volumeMounts = append(volumeMounts, *opaBundleVolumeMount)
+		volumes = append(volumes, *opaBundleVolume)
+	}
+
+	// Add volume mounts for the embedded authorization server secrets
+	authServerVolumeMount, authServerVolume := ctrlutil.GenerateEmbeddedAuthServerVolumeConfig(m.Spec.EmbeddedAuthServer)
+	if authServerVolumeMount != nil {
+		volumeMounts = append(volumeMounts, *authServerVolumeMount)
+		volumes = append(volumes, *authServerVolume)
+	}
 
 	// Prepare container resources
 	resources := corev1.ResourceRequirements{}
@@ -1499,6 +1534,28 @@ func (r *MCPServerReconciler) deploymentNeedsUpdate(
 			}
 		}
 
+		// Add embedded authorization server upstream client secret environment variable
+		authServerEnvVars, err := ctrlutil.GenerateEmbeddedAuthServerEnvVars(
+			ctx, r.Client, mcpServer.Namespace, mcpServer.Spec.EmbeddedAuthServer,
+		)
+		if err != nil {
+			// If we can't generate env vars, consider the deployment needs update
+			return true
+		}
+		expectedProxyEnv = append(expectedProxyEnv, authServerEnvVars...)
+
+		// Add Redis session storage password environment variable if a secret is referenced
+		sessionPasswordEnvVar, err := ctrlutil.GenerateSessionStoragePasswordEnvVar(
+			ctx, r.Client, mcpServer.Namespace, mcpServer.Spec.SessionStorage,
+		)
+		if err != nil {
+			// If we can't generate env var, consider the deployment needs update
+			return true
+		}
+		if sessionPasswordEnvVar != nil {
+			expectedProxyEnv = append(expectedProxyEnv, *sessionPasswordEnvVar)
+		}
+
 		// Add user-specified environment variables
 		if mcpServer.Spec.ResourceOverrides != nil && mcpServer.Spec.ResourceOverrides.ProxyDeployment != nil {
 			for _, envVar := range mcpServer.Spec.ResourceOverrides.ProxyDeployment.Env {
@@ -184,6 +184,11 @@ func (r *MCPServerReconciler) createRunConfigFromMCPServer(m *mcpv1alpha1.MCPSer
 		return nil, fmt.Errorf("failed to process OIDCConfig: %w", err)
 	}
 
+	// Add embedded authorization server configuration if specified
+	if err := ctrlutil.AddEmbeddedAuthServerConfigOptions(m, &options); err != nil {
+		return nil, fmt.Errorf("failed to process EmbeddedAuthServer: %w", err)
+	}
+
 	// Add external auth configuration if specified
 	if err := ctrlutil.AddExternalAuthConfigOptions(ctx, r.Client, m.Namespace, m.Spec.ExternalAuthConfigRef, &options); err != nil {
 		return nil, fmt.Errorf("failed to process ExternalAuthConfig: %w", err)
@@ -192,6 +197,14 @@ func (r *MCPServerReconciler) createRunConfigFromMCPServer(m *mcpv1alpha1.MCPSer
 	// Add audit configuration if specified
 	runconfig.AddAuditConfigOptions(&options, m.Spec.Audit)
 
+	// Add rate limit configuration if specified
+	if m.Spec.RateLimit != nil {
+		options = append(options, runner.WithRateLimitConfig(m.Spec.RateLimit))
+	}
+
+	// Add session storage configuration if specified
+	ctrlutil.AddSessionStorageConfigOptions(&options, m.Spec.SessionStorage)
+
 	// Check for Vault Agent Injection and add env-file-dir if needed
 	vaultDetected := false
 
@@ -20,6 +20,7 @@ import (
 	mcpv1alpha1 "github.com/stacklok/toolhive/cmd/thv-operator/api/v1alpha1"
 	ctrlutil "github.com/stacklok/toolhive/cmd/thv-operator/pkg/controllerutil"
 	"github.com/stacklok/toolhive/cmd/thv-operator/pkg/runconfig/configmap/checksum"
+	"github.com/stacklok/toolhive/pkg/audit"
 	"github.com/stacklok/toolhive/pkg/container/kubernetes"
 	"github.com/stacklok/toolhive/pkg/vmcp/workloads"
 )
@@ -57,6 +58,12 @@ var vmcpRBACRules = []rbacv1.PolicyRule{
 		Resources: []string{"configmaps", "secrets"},
 		Verbs:     []string{"get", "list", "watch"},
 	},
+	{
+		// Needed by the configmap workflow state store
+		APIGroups: []string{""},
+		Resources: []string{"configmaps"},
+		Verbs:     []string{"create", "update", "delete"},
+	},
 	{
 		APIGroups: []string{"toolhive.stacklok.dev"},
 		Resources: []string{"mcpgroups", "mcpservers", "mcpremoteproxies", "mcpexternalauthconfigs"},
@@ -221,6 +228,9 @@ func (r *VirtualMCPServerReconciler) buildEnvVarsForVmcp(
 	// Mount outgoing auth secrets
 	env = append(env, r.buildOutgoingAuthEnvVars(ctx, vmcp, typedWorkloads)...)
 
+	// Mount the audit log signing key
+	env = append(env, buildAuditEnvVars(vmcp)...)
+
 	// Note: Other secrets (Redis passwords, service account credentials) may be added here in the future
 	// following the same pattern of mounting from Kubernetes Secrets as environment variables.
 
@@ -277,6 +287,38 @@ func (*VirtualMCPServerReconciler) buildOIDCEnvVars(vmcp *mcpv1alpha1.VirtualMCP
 	return env
 }
 
+// buildAuditEnvVars builds the environment variable carrying the key signing the audit checkpoints.
+// The secrets provider used outside Kubernetes is not available in the vmcp pod.
+func buildAuditEnvVars(vmcp *mcpv1alpha1.VirtualMCPServer) []corev1.EnvVar {
+	secretRef := auditSigningKeySecretRef(vmcp)
+	if secretRef == nil {
+		return nil
+	}
+	return []corev1.EnvVar{{
+		Name: audit.SigningKeyEnvVar,
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: secretRef.Name,
+				},
+				Key: secretRef.Key,
+			},
+		},
+	}}
+}
+
+// auditSigningKeySecretRef returns the Secret holding the key signing the audit checkpoints,
+// or nil if the audit log is not tamper-evident.
+func auditSigningKeySecretRef(vmcp *mcpv1alpha1.VirtualMCPServer) *mcpv1alpha1.SecretKeyRef {
+	if vmcp.Spec.Config.Audit == nil || vmcp.Spec.Config.Audit.Integrity == nil {
+		return nil
+	}
+	return &mcpv1alpha1.SecretKeyRef{
+		Name: vmcp.Spec.Config.Audit.Integrity.SigningKeySecret,
+		Key:  audit.SigningKeySecretKey,
+	}
+}
+
 // buildOutgoingAuthEnvVars builds environment variables for outgoing auth secrets.
 func (r *VirtualMCPServerReconciler) buildOutgoingAuthEnvVars(
 	ctx context.Context,
@@ -603,6 +645,7 @@ func getVmcpImage() string {
 // Validated secrets include:
 // - OIDC client secrets (IncomingAuth.OIDCConfig.Inline.ClientSecretRef)
 // - Service account credentials (OutgoingAuth.*.ServiceAccount.CredentialsRef)
+// - The audit log signing key (Config.Audit.Integrity.SigningKeySecret)
 //
 // This follows the pattern from ctrlutil.GenerateOIDCClientSecretEnvVar() which validates secrets
 // exist before pod creation.
@@ -624,6 +667,12 @@ func (r *VirtualMCPServerReconciler) validateSecretReferences(
 		}
 	}
 
+	// Validate the audit log signing key if the audit log is tamper-evident
+	if err := r.validateSecretKeyRef(ctx, vmcp.Namespace, auditSigningKeySecretRef(vmcp),
+		"audit signing key"); err != nil {
+		return err
+	}
+
 	// Validate service account credentials in default backend auth
 	if vmcp.Spec.OutgoingAuth != nil && vmcp.Spec.OutgoingAuth.Default != nil {
 		if err := r.validateBackendAuthSecrets(ctx, vmcp.Namespace, vmcp.Spec.OutgoingAuth.Default, "default backend"); err != nil {
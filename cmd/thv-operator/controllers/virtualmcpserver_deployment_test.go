@@ -24,10 +24,12 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	mcpv1alpha1 "github.com/stacklok/toolhive/cmd/thv-operator/api/v1alpha1"
 	ctrlutil "github.com/stacklok/toolhive/cmd/thv-operator/pkg/controllerutil"
 	"github.com/stacklok/toolhive/cmd/thv-operator/pkg/runconfig/configmap/checksum"
+	"github.com/stacklok/toolhive/pkg/audit"
 	vmcpconfig "github.com/stacklok/toolhive/pkg/vmcp/config"
 	"github.com/stacklok/toolhive/pkg/vmcp/workloads"
 )
@@ -194,6 +196,59 @@ func TestBuildEnvVarsForVmcp(t *testing.T) {
 	assert.True(t, foundNamespace, "Should have VMCP_NAMESPACE env var")
 }
 
+// TestVmcpAuditSigningKey tests that the audit signing key is mounted from a Kubernetes Secret
+func TestVmcpAuditSigningKey(t *testing.T) {
+	t.Parallel()
+
+	vmcp := &mcpv1alpha1.VirtualMCPServer{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-vmcp",
+			Namespace: "test-namespace",
+		},
+		Spec: mcpv1alpha1.VirtualMCPServerSpec{
+			Config: vmcpconfig.Config{
+				Group: "test-group",
+				Audit: &audit.Config{
+					LogFile:   "/var/log/vmcp/audit.log",
+					Integrity: &audit.IntegrityConfig{SigningKeySecret: "audit-signing-key"},
+				},
+			},
+		},
+	}
+
+	r := &VirtualMCPServerReconciler{}
+	env := r.buildEnvVarsForVmcp(context.Background(), vmcp, []workloads.TypedWorkload{})
+	var signingKeyEnv *corev1.EnvVar
+	for i := range env {
+		if env[i].Name == audit.SigningKeyEnvVar {
+			signingKeyEnv = &env[i]
+		}
+	}
+	require.NotNil(t, signingKeyEnv, "Should have the audit signing key env var")
+	require.NotNil(t, signingKeyEnv.ValueFrom)
+	require.NotNil(t, signingKeyEnv.ValueFrom.SecretKeyRef)
+	assert.Equal(t, "audit-signing-key", signingKeyEnv.ValueFrom.SecretKeyRef.Name)
+	assert.Equal(t, audit.SigningKeySecretKey, signingKeyEnv.ValueFrom.SecretKeyRef.Key)
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	// The Secret must exist and hold the key
+	r = &VirtualMCPServerReconciler{Client: fake.NewClientBuilder().WithScheme(scheme).Build(), Scheme: scheme}
+	err := r.validateSecretReferences(context.Background(), vmcp)
+	assert.ErrorContains(t, err, "audit signing key secret test-namespace/audit-signing-key")
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "audit-signing-key", Namespace: "test-namespace"},
+		Data:       map[string][]byte{audit.SigningKeySecretKey: []byte("key")},
+	}
+	r = &VirtualMCPServerReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build(),
+		Scheme: scheme,
+	}
+	assert.NoError(t, r.validateSecretReferences(context.Background(), vmcp))
+}
+
 // TestBuildDeploymentMetadataForVmcp tests deployment metadata generation
 func TestBuildDeploymentMetadataForVmcp(t *testing.T) {
 	t.Parallel()
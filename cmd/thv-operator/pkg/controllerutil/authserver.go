@@ -0,0 +1,194 @@
+package controllerutil
+
+import (
+	"context"
+	"fmt"
+	"path"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	mcpv1alpha1 "github.com/stacklok/toolhive/cmd/thv-operator/api/v1alpha1"
+	"github.com/stacklok/toolhive/pkg/authserver"
+	"github.com/stacklok/toolhive/pkg/runner"
+	transporttypes "github.com/stacklok/toolhive/pkg/transport/types"
+)
+
+const (
+	// EmbeddedAuthServerSecretsVolumeName is the name of the proxy volume holding the signing
+	// key and HMAC secrets of the embedded authorization server
+	EmbeddedAuthServerSecretsVolumeName = "embedded-auth-server-secrets"
+
+	// EmbeddedAuthServerSecretsMountPath is where the embedded authorization server secrets are
+	// mounted in the proxy container. Each secret key is mounted at <secret name>/<key>.
+	EmbeddedAuthServerSecretsMountPath = "/etc/toolhive/authserver"
+)
+
+// AddEmbeddedAuthServerConfigOptions adds the embedded authorization server configuration to the
+// builder options. Unless the MCPServer configures OIDC, the proxy validates the tokens issued by
+// the embedded server.
+func AddEmbeddedAuthServerConfigOptions(
+	m *mcpv1alpha1.MCPServer,
+	options *[]runner.RunConfigBuilderOption,
+) error {
+	authServerConfig := m.Spec.EmbeddedAuthServer
+	if authServerConfig == nil {
+		return nil
+	}
+	// stdio proxies do not expose the authorization server endpoints
+	if m.Spec.Transport == "" || m.Spec.Transport == transporttypes.TransportTypeStdio.String() {
+		return fmt.Errorf("the embedded authorization server requires the sse or streamable-http transport")
+	}
+
+	config := &authserver.RunConfig{
+		Issuer:               authServerConfig.Issuer,
+		AllowedAudiences:     authServerConfig.AllowedAudiences,
+		ScopesSupported:      authServerConfig.ScopesSupported,
+		AccessTokenLifespan:  authServerConfig.AccessTokenLifespan,
+		RefreshTokenLifespan: authServerConfig.RefreshTokenLifespan,
+		AuthCodeLifespan:     authServerConfig.AuthCodeLifespan,
+		Upstream: &authserver.UpstreamRunConfig{
+			Issuer:      authServerConfig.Upstream.Issuer,
+			ClientID:    authServerConfig.Upstream.ClientID,
+			Scopes:      authServerConfig.Upstream.Scopes,
+			RedirectURI: authServerConfig.Upstream.RedirectURI,
+		},
+	}
+	// The upstream client secret is read from the environment, see GenerateEmbeddedAuthServerEnvVars
+	if authServerConfig.SigningKeyRef != nil {
+		config.SigningKeyFile = embeddedAuthServerSecretPath(*authServerConfig.SigningKeyRef)
+	}
+	for _, ref := range authServerConfig.HMACSecretRefs {
+		config.HMACSecretFiles = append(config.HMACSecretFiles, embeddedAuthServerSecretPath(ref))
+	}
+
+	*options = append(*options, runner.WithEmbeddedAuthServer(config))
+
+	if m.Spec.OIDCConfig == nil {
+		oidcConfig := config.TokenValidatorConfig()
+		*options = append(*options, runner.WithOIDCConfig(
+			oidcConfig.Issuer,
+			oidcConfig.Audience,
+			oidcConfig.JWKSURL,
+			"",
+			"",
+			"",
+			"",
+			"",
+			oidcConfig.ResourceURL,
+			oidcConfig.AllowPrivateIP,
+			oidcConfig.InsecureAllowHTTP,
+			oidcConfig.Scopes,
+		))
+	}
+	return nil
+}
+
+// GenerateEmbeddedAuthServerEnvVars generates the proxy environment variable carrying the upstream
+// client secret of the embedded authorization server. It returns nil if no secret is referenced.
+func GenerateEmbeddedAuthServerEnvVars(
+	ctx context.Context,
+	c client.Client,
+	namespace string,
+	authServerConfig *mcpv1alpha1.EmbeddedAuthServerConfig,
+) ([]corev1.EnvVar, error) {
+	if authServerConfig == nil || authServerConfig.Upstream.ClientSecretRef == nil {
+		return nil, nil
+	}
+	clientSecretRef := authServerConfig.Upstream.ClientSecretRef
+
+	// Validate that the referenced secret exists
+	var secret corev1.Secret
+	if err := c.Get(ctx, types.NamespacedName{
+		Namespace: namespace,
+		Name:      clientSecretRef.Name,
+	}, &secret); err != nil {
+		return nil, fmt.Errorf("failed to get upstream client secret %s/%s: %w",
+			namespace, clientSecretRef.Name, err)
+	}
+
+	// Validate that the key exists in the secret
+	if _, ok := secret.Data[clientSecretRef.Key]; !ok {
+		return nil, fmt.Errorf("upstream client secret %s/%s is missing key %q",
+			namespace, clientSecretRef.Name, clientSecretRef.Key)
+	}
+
+	return []corev1.EnvVar{{
+		Name: authserver.UpstreamClientSecretEnvVar,
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: clientSecretRef.Name,
+				},
+				Key: clientSecretRef.Key,
+			},
+		},
+	}}, nil
+}
+
+// GenerateEmbeddedAuthServerVolumeConfig generates the volume mount and volume that expose the
+// signing key and HMAC secrets of the embedded authorization server to the proxy.
+// It returns nil if no secret is referenced.
+func GenerateEmbeddedAuthServerVolumeConfig(
+	authServerConfig *mcpv1alpha1.EmbeddedAuthServerConfig,
+) (*corev1.VolumeMount, *corev1.Volume) {
+	if authServerConfig == nil {
+		return nil, nil
+	}
+
+	var refs []mcpv1alpha1.SecretKeyRef
+	if authServerConfig.SigningKeyRef != nil {
+		refs = append(refs, *authServerConfig.SigningKeyRef)
+	}
+	refs = append(refs, authServerConfig.HMACSecretRefs...)
+	if len(refs) == 0 {
+		return nil, nil
+	}
+
+	// Project every referenced key once, grouping the keys of each secret
+	var sources []corev1.VolumeProjection
+	sourceIndex := map[string]int{}
+	seen := map[mcpv1alpha1.SecretKeyRef]bool{}
+	for _, ref := range refs {
+		if seen[ref] {
+			continue
+		}
+		seen[ref] = true
+
+		idx, ok := sourceIndex[ref.Name]
+		if !ok {
+			idx = len(sources)
+			sourceIndex[ref.Name] = idx
+			sources = append(sources, corev1.VolumeProjection{
+				Secret: &corev1.SecretProjection{
+					LocalObjectReference: corev1.LocalObjectReference{Name: ref.Name},
+				},
+			})
+		}
+		sources[idx].Secret.Items = append(sources[idx].Secret.Items, corev1.KeyToPath{
+			Key:  ref.Key,
+			Path: path.Join(ref.Name, ref.Key),
+		})
+	}
+
+	volumeMount := &corev1.VolumeMount{
+		Name:      EmbeddedAuthServerSecretsVolumeName,
+		MountPath: EmbeddedAuthServerSecretsMountPath,
+		ReadOnly:  true,
+	}
+	volume := &corev1.Volume{
+		Name: EmbeddedAuthServerSecretsVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			Projected: &corev1.ProjectedVolumeSource{
+				Sources: sources,
+			},
+		},
+	}
+	return volumeMount, volume
+}
+
+// embeddedAuthServerSecretPath returns the path of a secret key mounted by GenerateEmbeddedAuthServerVolumeConfig
+func embeddedAuthServerSecretPath(ref mcpv1alpha1.SecretKeyRef) string {
+	return path.Join(EmbeddedAuthServerSecretsMountPath, ref.Name, ref.Key)
+}
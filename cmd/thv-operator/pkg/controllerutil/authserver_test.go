@@ -0,0 +1,222 @@
+package controllerutil
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	mcpv1alpha1 "github.com/stacklok/toolhive/cmd/thv-operator/api/v1alpha1"
+	"github.com/stacklok/toolhive/pkg/authserver"
+	"github.com/stacklok/toolhive/pkg/runner"
+)
+
+func testEmbeddedAuthServerConfig() *mcpv1alpha1.EmbeddedAuthServerConfig {
+	return &mcpv1alpha1.EmbeddedAuthServerConfig{
+		Issuer:           "https://mcp.example.com",
+		AllowedAudiences: []string{"https://mcp.example.com/mcp"},
+		SigningKeyRef:    &mcpv1alpha1.SecretKeyRef{Name: "authserver-keys", Key: "signing.pem"},
+		HMACSecretRefs: []mcpv1alpha1.SecretKeyRef{
+			{Name: "authserver-keys", Key: "hmac"},
+			{Name: "authserver-old-keys", Key: "hmac"},
+		},
+		Upstream: mcpv1alpha1.EmbeddedAuthServerUpstreamConfig{
+			Issuer:          "https://idp.example.com",
+			ClientID:        "toolhive",
+			ClientSecretRef: &mcpv1alpha1.SecretKeyRef{Name: "idp-client", Key: "secret"},
+		},
+	}
+}
+
+func TestAddEmbeddedAuthServerConfigOptions(t *testing.T) {
+	t.Parallel()
+
+	newMCPServer := func(transport string) *mcpv1alpha1.MCPServer {
+		return &mcpv1alpha1.MCPServer{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-server", Namespace: "default"},
+			Spec: mcpv1alpha1.MCPServerSpec{
+				Image:              "test-image",
+				Transport:          transport,
+				EmbeddedAuthServer: testEmbeddedAuthServerConfig(),
+			},
+		}
+	}
+
+	t.Run("nil config adds no option", func(t *testing.T) {
+		t.Parallel()
+
+		var options []runner.RunConfigBuilderOption
+		require.NoError(t, AddEmbeddedAuthServerConfigOptions(&mcpv1alpha1.MCPServer{}, &options))
+		assert.Empty(t, options)
+	})
+
+	t.Run("stdio transport is rejected", func(t *testing.T) {
+		t.Parallel()
+
+		var options []runner.RunConfigBuilderOption
+		err := AddEmbeddedAuthServerConfigOptions(newMCPServer("stdio"), &options)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "requires the sse or streamable-http transport")
+	})
+
+	t.Run("secrets are referenced by mounted files and tokens are validated", func(t *testing.T) {
+		t.Parallel()
+
+		var options []runner.RunConfigBuilderOption
+		require.NoError(t, AddEmbeddedAuthServerConfigOptions(newMCPServer("streamable-http"), &options))
+
+		config, err := runner.NewOperatorRunConfigBuilder(context.Background(), nil, nil, nil, options...)
+		require.NoError(t, err)
+		require.NotNil(t, config.EmbeddedAuthServerConfig)
+		assert.Equal(t, "https://mcp.example.com", config.EmbeddedAuthServerConfig.Issuer)
+		assert.Equal(t, "/etc/toolhive/authserver/authserver-keys/signing.pem",
+			config.EmbeddedAuthServerConfig.SigningKeyFile)
+		assert.Equal(t, []string{
+			"/etc/toolhive/authserver/authserver-keys/hmac",
+			"/etc/toolhive/authserver/authserver-old-keys/hmac",
+		}, config.EmbeddedAuthServerConfig.HMACSecretFiles)
+		assert.Empty(t, config.EmbeddedAuthServerConfig.Upstream.ClientSecretFile)
+
+		require.NotNil(t, config.OIDCConfig)
+		assert.Equal(t, "https://mcp.example.com", config.OIDCConfig.Issuer)
+		assert.Equal(t, "https://mcp.example.com/.well-known/jwks.json", config.OIDCConfig.JWKSURL)
+		assert.Equal(t, "https://mcp.example.com/mcp", config.OIDCConfig.Audience)
+		assert.True(t, config.OIDCConfig.AllowPrivateIP)
+	})
+
+	t.Run("explicit OIDC configuration is kept", func(t *testing.T) {
+		t.Parallel()
+
+		m := newMCPServer("sse")
+		m.Spec.OIDCConfig = &mcpv1alpha1.OIDCConfigRef{Type: mcpv1alpha1.OIDCConfigTypeKubernetes}
+
+		var options []runner.RunConfigBuilderOption
+		require.NoError(t, AddEmbeddedAuthServerConfigOptions(m, &options))
+
+		config, err := runner.NewOperatorRunConfigBuilder(context.Background(), nil, nil, nil, options...)
+		require.NoError(t, err)
+		require.NotNil(t, config.EmbeddedAuthServerConfig)
+		assert.Nil(t, config.OIDCConfig)
+	})
+}
+
+func TestGenerateEmbeddedAuthServerEnvVars(t *testing.T) {
+	t.Parallel()
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "idp-client",
+			Namespace: "default",
+		},
+		Data: map[string][]byte{
+			"secret": []byte("client-secret"),
+		},
+	}
+
+	withKey := func(key string) *mcpv1alpha1.EmbeddedAuthServerConfig {
+		cfg := testEmbeddedAuthServerConfig()
+		cfg.Upstream.ClientSecretRef.Key = key
+		return cfg
+	}
+	withoutSecret := testEmbeddedAuthServerConfig()
+	withoutSecret.Upstream.ClientSecretRef = nil
+
+	tests := []struct {
+		name             string
+		authServerConfig *mcpv1alpha1.EmbeddedAuthServerConfig
+		expectError      bool
+		errContains      string
+		expectEnvVar     bool
+	}{
+		{
+			name:             "nil config returns nil",
+			authServerConfig: nil,
+		},
+		{
+			name:             "no client secret returns nil",
+			authServerConfig: withoutSecret,
+		},
+		{
+			name:             "valid client secret ref generates env var",
+			authServerConfig: withKey("secret"),
+			expectEnvVar:     true,
+		},
+		{
+			name:             "missing key in secret returns error",
+			authServerConfig: withKey("wrong-key"),
+			expectError:      true,
+			errContains:      "is missing key",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			scheme := runtime.NewScheme()
+			require.NoError(t, corev1.AddToScheme(scheme))
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret.DeepCopy()).Build()
+
+			envVars, err := GenerateEmbeddedAuthServerEnvVars(context.TODO(), fakeClient, "default", tt.authServerConfig)
+			if tt.expectError {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContains)
+				return
+			}
+			require.NoError(t, err)
+			if !tt.expectEnvVar {
+				assert.Empty(t, envVars)
+				return
+			}
+			require.Len(t, envVars, 1)
+			assert.Equal(t, authserver.UpstreamClientSecretEnvVar, envVars[0].Name)
+			require.NotNil(t, envVars[0].ValueFrom)
+			require.NotNil(t, envVars[0].ValueFrom.SecretKeyRef)
+			assert.Equal(t, "idp-client", envVars[0].ValueFrom.SecretKeyRef.Name)
+			assert.Equal(t, "secret", envVars[0].ValueFrom.SecretKeyRef.Key)
+		})
+	}
+}
+
+func TestGenerateEmbeddedAuthServerVolumeConfig(t *testing.T) {
+	t.Parallel()
+
+	volumeMount, volume := GenerateEmbeddedAuthServerVolumeConfig(nil)
+	assert.Nil(t, volumeMount)
+	assert.Nil(t, volume)
+
+	noSecrets := testEmbeddedAuthServerConfig()
+	noSecrets.SigningKeyRef = nil
+	noSecrets.HMACSecretRefs = nil
+	volumeMount, volume = GenerateEmbeddedAuthServerVolumeConfig(noSecrets)
+	assert.Nil(t, volumeMount)
+	assert.Nil(t, volume)
+
+	cfg := testEmbeddedAuthServerConfig()
+	// A key referenced twice is projected once
+	cfg.HMACSecretRefs = append(cfg.HMACSecretRefs, mcpv1alpha1.SecretKeyRef{Name: "authserver-keys", Key: "hmac"})
+
+	volumeMount, volume = GenerateEmbeddedAuthServerVolumeConfig(cfg)
+	require.NotNil(t, volumeMount)
+	require.NotNil(t, volume)
+	assert.Equal(t, EmbeddedAuthServerSecretsMountPath, volumeMount.MountPath)
+	assert.True(t, volumeMount.ReadOnly)
+	assert.Equal(t, volumeMount.Name, volume.Name)
+
+	require.NotNil(t, volume.Projected)
+	require.Len(t, volume.Projected.Sources, 2)
+	assert.Equal(t, "authserver-keys", volume.Projected.Sources[0].Secret.Name)
+	assert.Equal(t, []corev1.KeyToPath{
+		{Key: "signing.pem", Path: "authserver-keys/signing.pem"},
+		{Key: "hmac", Path: "authserver-keys/hmac"},
+	}, volume.Projected.Sources[0].Secret.Items)
+	assert.Equal(t, "authserver-old-keys", volume.Projected.Sources[1].Secret.Name)
+	assert.Equal(t, []corev1.KeyToPath{
+		{Key: "hmac", Path: "authserver-old-keys/hmac"},
+	}, volume.Projected.Sources[1].Secret.Items)
+}
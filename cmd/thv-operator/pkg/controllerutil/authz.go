@@ -3,8 +3,10 @@ package controllerutil
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"path"
 	"strings"
 
 	corev1 "k8s.io/api/core/v1"
@@ -18,12 +20,16 @@ import (
 	"github.com/stacklok/toolhive/cmd/thv-operator/pkg/kubernetes/configmaps"
 	"github.com/stacklok/toolhive/pkg/authz"
 	"github.com/stacklok/toolhive/pkg/authz/authorizers/cedar"
+	"github.com/stacklok/toolhive/pkg/authz/authorizers/opa"
 	"github.com/stacklok/toolhive/pkg/runner"
 )
 
 const (
 	// DefaultAuthzKey is the default key for authorization policies in ConfigMaps
 	DefaultAuthzKey = "authz.json"
+
+	// AuthzConfigMountPath is the directory in which the authorization configuration is mounted
+	AuthzConfigMountPath = "/etc/toolhive/authz"
 )
 
 // GenerateAuthzVolumeConfig generates volume mount and volume for authorization policies
@@ -43,7 +49,7 @@ func GenerateAuthzVolumeConfig(
 
 		volumeMount := &corev1.VolumeMount{
 			Name:      "authz-config",
-			MountPath: "/etc/toolhive/authz",
+			MountPath: AuthzConfigMountPath,
 			ReadOnly:  true,
 		}
 
@@ -78,7 +84,7 @@ func GenerateAuthzVolumeConfig(
 
 		volumeMount := &corev1.VolumeMount{
 			Name:      "authz-config",
-			MountPath: "/etc/toolhive/authz",
+			MountPath: AuthzConfigMountPath,
 			ReadOnly:  true,
 		}
 
@@ -210,55 +216,36 @@ func AddAuthzConfigOptions(
 		return addAuthzInlineConfigOptions(authzRef, options)
 
 	case mcpv1alpha1.AuthzConfigTypeConfigMap:
-		// Validate reference
-		if authzRef.ConfigMap == nil || authzRef.ConfigMap.Name == "" {
-			return fmt.Errorf("configMap authz config type specified but reference is missing name")
-		}
-		key := authzRef.ConfigMap.Key
-		if key == "" {
-			key = DefaultAuthzKey
-		}
-
-		// Ensure we have a Kubernetes client to fetch the ConfigMap
-		if c == nil {
-			return fmt.Errorf("kubernetes client is not configured for ConfigMap authz resolution")
-		}
-
-		// Fetch the ConfigMap
-		var cm corev1.ConfigMap
-		if err := c.Get(ctx, types.NamespacedName{
-			Namespace: namespace,
-			Name:      authzRef.ConfigMap.Name,
-		}, &cm); err != nil {
-			return fmt.Errorf("failed to get Authz ConfigMap %s/%s: %w", namespace, authzRef.ConfigMap.Name, err)
+		cfg, err := loadAuthzConfigMap(ctx, c, namespace, authzRef)
+		if err != nil {
+			return err
 		}
-
-		raw, ok := cm.Data[key]
-		if !ok {
-			return fmt.Errorf("authz ConfigMap %s/%s is missing key %q", namespace, authzRef.ConfigMap.Name, key)
-		}
-		if len(strings.TrimSpace(raw)) == 0 {
-			return fmt.Errorf("authz ConfigMap %s/%s key %q is empty", namespace, authzRef.ConfigMap.Name, key)
+		authzOptions, err := authz.ParseOptions(cfg)
+		if err != nil {
+			return fmt.Errorf("invalid authz config from ConfigMap %s/%s: %w", namespace, authzRef.ConfigMap.Name, err)
 		}
+		reload := authzOptions.Reload != nil
 
-		// Unmarshal into authz.Config supporting YAML or JSON
-		var cfg authz.Config
-		// Try YAML first (it also handles JSON)
-		if err := yaml.Unmarshal([]byte(raw), &cfg); err != nil {
-			// Fallback to JSON explicitly for clearer error paths
-			if err2 := json.Unmarshal([]byte(raw), &cfg); err2 != nil {
-				return fmt.Errorf("failed to parse authz config from ConfigMap %s/%s key %q: %w; json fallback error: %w",
-					namespace, authzRef.ConfigMap.Name, key, err, err2)
+		if cfg.Type == opa.ConfigType {
+			resolved, err := resolveOPABundleConfigMap(ctx, c, namespace, cfg)
+			if err != nil {
+				return err
+			}
+			// When reloading, the bundle is read from the mounted ConfigMap, so that its changes are reloaded
+			if !reload {
+				cfg = resolved
 			}
 		}
 
-		// Validate the config
-		if err := cfg.Validate(); err != nil {
-			return fmt.Errorf("invalid authz config from ConfigMap %s/%s key %q: %w",
-				namespace, authzRef.ConfigMap.Name, key, err)
+		// The configuration is passed inline to the proxy runner, which reloads it from the mounted ConfigMap
+		if reload && authzOptions.Reload.Path == "" {
+			cfg, err = withReloadPath(cfg, path.Join(AuthzConfigMountPath, DefaultAuthzKey))
+			if err != nil {
+				return err
+			}
 		}
 
-		*options = append(*options, runner.WithAuthzConfig(&cfg))
+		*options = append(*options, runner.WithAuthzConfig(cfg))
 		return nil
 
 	default:
@@ -266,3 +253,167 @@ func AddAuthzConfigOptions(
 		return fmt.Errorf("unknown authz config type: %s", authzRef.Type)
 	}
 }
+
+// resolveOPABundleConfigMap inlines the OPA bundle referenced by bundle_configmap into the
+// authorization config, so that the proxy runner does not read it from the mounted ConfigMap
+// unless the policies are reloaded. Bundles are binary, so
+// they are read from the binaryData of the ConfigMap, falling back to its data.
+func resolveOPABundleConfigMap(
+	ctx context.Context,
+	c client.Client,
+	namespace string,
+	cfg *authz.Config,
+) (*authz.Config, error) {
+	opaCfg, err := opa.ExtractConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	ref := opaCfg.Options.BundleConfigMap
+	if ref == nil {
+		return cfg, nil
+	}
+	key := ref.Key
+	if key == "" {
+		key = opa.DefaultBundleConfigMapKey
+	}
+
+	var cm corev1.ConfigMap
+	if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: ref.Name}, &cm); err != nil {
+		return nil, fmt.Errorf("failed to get OPA bundle ConfigMap %s/%s: %w", namespace, ref.Name, err)
+	}
+	content, ok := cm.BinaryData[key]
+	if !ok {
+		data, found := cm.Data[key]
+		if !found {
+			return nil, fmt.Errorf("OPA bundle ConfigMap %s/%s is missing key %q", namespace, ref.Name, key)
+		}
+		content = []byte(data)
+	}
+
+	opaCfg.Options.Bundle = base64.StdEncoding.EncodeToString(content)
+	opaCfg.Options.BundleConfigMap = nil
+	resolved, err := authz.NewConfig(opaCfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create authz config: %w", err)
+	}
+	return resolved, nil
+}
+
+// loadAuthzConfigMap reads and validates the authorization configuration of a ConfigMap reference.
+func loadAuthzConfigMap(
+	ctx context.Context,
+	c client.Client,
+	namespace string,
+	authzRef *mcpv1alpha1.AuthzConfigRef,
+) (*authz.Config, error) {
+	// Validate reference
+	if authzRef.ConfigMap == nil || authzRef.ConfigMap.Name == "" {
+		return nil, fmt.Errorf("configMap authz config type specified but reference is missing name")
+	}
+	key := authzRef.ConfigMap.Key
+	if key == "" {
+		key = DefaultAuthzKey
+	}
+
+	// Ensure we have a Kubernetes client to fetch the ConfigMap
+	if c == nil {
+		return nil, fmt.Errorf("kubernetes client is not configured for ConfigMap authz resolution")
+	}
+
+	// Fetch the ConfigMap
+	var cm corev1.ConfigMap
+	if err := c.Get(ctx, types.NamespacedName{
+		Namespace: namespace,
+		Name:      authzRef.ConfigMap.Name,
+	}, &cm); err != nil {
+		return nil, fmt.Errorf("failed to get Authz ConfigMap %s/%s: %w", namespace, authzRef.ConfigMap.Name, err)
+	}
+
+	raw, ok := cm.Data[key]
+	if !ok {
+		return nil, fmt.Errorf("authz ConfigMap %s/%s is missing key %q", namespace, authzRef.ConfigMap.Name, key)
+	}
+	if len(strings.TrimSpace(raw)) == 0 {
+		return nil, fmt.Errorf("authz ConfigMap %s/%s key %q is empty", namespace, authzRef.ConfigMap.Name, key)
+	}
+
+	// Unmarshal into authz.Config supporting YAML or JSON
+	var cfg authz.Config
+	// Try YAML first (it also handles JSON)
+	if err := yaml.Unmarshal([]byte(raw), &cfg); err != nil {
+		// Fallback to JSON explicitly for clearer error paths
+		if err2 := json.Unmarshal([]byte(raw), &cfg); err2 != nil {
+			return nil, fmt.Errorf("failed to parse authz config from ConfigMap %s/%s key %q: %w; json fallback error: %w",
+				namespace, authzRef.ConfigMap.Name, key, err, err2)
+		}
+	}
+
+	// Validate the config
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid authz config from ConfigMap %s/%s key %q: %w",
+			namespace, authzRef.ConfigMap.Name, key, err)
+	}
+
+	return &cfg, nil
+}
+
+// withReloadPath sets the path from which the authorization configuration is reloaded.
+func withReloadPath(cfg *authz.Config, reloadPath string) (*authz.Config, error) {
+	var raw map[string]any
+	if err := json.Unmarshal(cfg.RawConfig(), &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse authz config: %w", err)
+	}
+	reload, _ := raw["reload"].(map[string]any)
+	if reload == nil {
+		reload = map[string]any{}
+	}
+	reload["path"] = reloadPath
+	raw["reload"] = reload
+
+	resolved, err := authz.NewConfig(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create authz config: %w", err)
+	}
+	return resolved, nil
+}
+
+// GenerateOPABundleVolumeConfig generates the volume mount and volume of the ConfigMap referenced by
+// the bundle_configmap of an OPA authorization configuration, from which the proxy runner reloads the bundle.
+func GenerateOPABundleVolumeConfig(
+	ctx context.Context,
+	c client.Client,
+	namespace string,
+	authzRef *mcpv1alpha1.AuthzConfigRef,
+) (*corev1.VolumeMount, *corev1.Volume) {
+	if authzRef == nil || authzRef.Type != mcpv1alpha1.AuthzConfigTypeConfigMap || c == nil {
+		return nil, nil
+	}
+
+	cfg, err := loadAuthzConfigMap(ctx, c, namespace, authzRef)
+	// Invalid configurations are reported when the RunConfig is generated
+	if err != nil || cfg.Type != opa.ConfigType {
+		return nil, nil
+	}
+	opaCfg, err := opa.ExtractConfig(cfg)
+	if err != nil || opaCfg.Options.BundleConfigMap == nil {
+		return nil, nil
+	}
+	ref := opaCfg.Options.BundleConfigMap
+
+	volumeMount := &corev1.VolumeMount{
+		Name:      "authz-opa-bundle",
+		MountPath: path.Join(opa.BundleConfigMapMountPath, ref.Name),
+		ReadOnly:  true,
+	}
+	volume := &corev1.Volume{
+		Name: "authz-opa-bundle",
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: ref.Name,
+				},
+			},
+		},
+	}
+	return volumeMount, volume
+}
@@ -2,6 +2,7 @@ package controllerutil
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"testing"
 
@@ -13,6 +14,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	mcpv1alpha1 "github.com/stacklok/toolhive/cmd/thv-operator/api/v1alpha1"
+	"github.com/stacklok/toolhive/pkg/authz"
+	"github.com/stacklok/toolhive/pkg/authz/authorizers/opa"
 	"github.com/stacklok/toolhive/pkg/runner"
 )
 
@@ -672,6 +675,148 @@ func TestAddAuthzConfigOptions(t *testing.T) {
 	})
 }
 
+func TestResolveOPABundleConfigMap(t *testing.T) {
+	t.Parallel()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	bundle := []byte{0x1f, 0x8b, 0x08, 0x00}
+	client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "opa-bundle", Namespace: "default"},
+		BinaryData: map[string][]byte{opa.DefaultBundleConfigMapKey: bundle},
+	}).Build()
+
+	newConfig := func(ref *opa.ConfigMapRef) *authz.Config {
+		cfg, err := authz.NewConfig(opa.Config{
+			Version: "1.0",
+			Type:    opa.ConfigType,
+			Options: &opa.ConfigOptions{BundleConfigMap: ref, Entrypoint: "toolhive/authz/allow"},
+		})
+		require.NoError(t, err)
+		return cfg
+	}
+
+	resolved, err := resolveOPABundleConfigMap(context.Background(), client, "default",
+		newConfig(&opa.ConfigMapRef{Name: "opa-bundle"}))
+	require.NoError(t, err)
+	opaCfg, err := opa.ExtractConfig(resolved)
+	require.NoError(t, err)
+	assert.Nil(t, opaCfg.Options.BundleConfigMap)
+	assert.Equal(t, base64.StdEncoding.EncodeToString(bundle), opaCfg.Options.Bundle)
+	assert.Equal(t, "toolhive/authz/allow", opaCfg.Options.Entrypoint)
+	assert.NoError(t, resolved.Validate())
+
+	_, err = resolveOPABundleConfigMap(context.Background(), client, "default",
+		newConfig(&opa.ConfigMapRef{Name: "opa-bundle", Key: "policy.wasm"}))
+	assert.ErrorContains(t, err, `is missing key "policy.wasm"`)
+
+	_, err = resolveOPABundleConfigMap(context.Background(), client, "default",
+		newConfig(&opa.ConfigMapRef{Name: "missing"}))
+	assert.ErrorContains(t, err, "failed to get OPA bundle ConfigMap")
+}
+
+func TestAddAuthzConfigOptions_Reload(t *testing.T) {
+	t.Parallel()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	cedarConfig := `{"version": "1.0", "type": "cedarv1", "reload": {"interval": "30s"},
+		"cedar": {"policies": ["permit(principal, action, resource);"], "entities_json": "[]"}}`
+	opaConfig := `{"version": "1.0", "type": "opa", "reload": {},
+		"opa": {"bundle_configmap": {"name": "opa-bundle"}, "entrypoint": "toolhive/authz/allow"}}`
+	client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "cedar-authz", Namespace: "default"},
+			Data:       map[string]string{DefaultAuthzKey: cedarConfig},
+		},
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "opa-authz", Namespace: "default"},
+			Data:       map[string]string{DefaultAuthzKey: opaConfig},
+		},
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "opa-bundle", Namespace: "default"},
+			Data:       map[string]string{opa.DefaultBundleConfigMapKey: "package toolhive.authz\n\nallow := true"},
+		},
+	).Build()
+
+	buildConfig := func(t *testing.T, name string) *authz.Config {
+		t.Helper()
+		var options []runner.RunConfigBuilderOption
+		require.NoError(t, AddAuthzConfigOptions(context.Background(), client, "default", &mcpv1alpha1.AuthzConfigRef{
+			Type:      mcpv1alpha1.AuthzConfigTypeConfigMap,
+			ConfigMap: &mcpv1alpha1.ConfigMapAuthzRef{Name: name},
+		}, &options))
+		config, err := runner.NewOperatorRunConfigBuilder(context.Background(), nil, nil, nil, options...)
+		require.NoError(t, err)
+		require.NotNil(t, config.AuthzConfig)
+		return config.AuthzConfig
+	}
+
+	// The configuration is reloaded from the mounted ConfigMap
+	cfg := buildConfig(t, "cedar-authz")
+	options, err := authz.ParseOptions(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, &authz.ReloadOptions{Path: "/etc/toolhive/authz/authz.json", Interval: "30s"}, options.Reload)
+	assert.NoError(t, cfg.Validate())
+
+	// The OPA bundle is not inlined, but read from the mounted bundle ConfigMap
+	cfg = buildConfig(t, "opa-authz")
+	options, err = authz.ParseOptions(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, "/etc/toolhive/authz/authz.json", options.Reload.Path)
+	opaCfg, err := opa.ExtractConfig(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, &opa.ConfigMapRef{Name: "opa-bundle"}, opaCfg.Options.BundleConfigMap)
+	assert.Empty(t, opaCfg.Options.Bundle)
+}
+
+func TestGenerateOPABundleVolumeConfig(t *testing.T) {
+	t.Parallel()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "opa-authz", Namespace: "default"},
+			Data: map[string]string{DefaultAuthzKey: `{"version": "1.0", "type": "opa",
+				"opa": {"bundle_configmap": {"name": "opa-bundle", "key": "policy.rego"}, "entrypoint": "toolhive/authz/allow"}}`},
+		},
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "cedar-authz", Namespace: "default"},
+			Data: map[string]string{DefaultAuthzKey: `{"version": "1.0", "type": "cedarv1",
+				"cedar": {"policies": ["permit(principal, action, resource);"], "entities_json": "[]"}}`},
+		},
+	).Build()
+	configMapRef := func(name string) *mcpv1alpha1.AuthzConfigRef {
+		return &mcpv1alpha1.AuthzConfigRef{
+			Type:      mcpv1alpha1.AuthzConfigTypeConfigMap,
+			ConfigMap: &mcpv1alpha1.ConfigMapAuthzRef{Name: name},
+		}
+	}
+
+	volumeMount, volume := GenerateOPABundleVolumeConfig(context.Background(), client, "default", configMapRef("opa-authz"))
+	require.NotNil(t, volumeMount)
+	require.NotNil(t, volume)
+	assert.Equal(t, "/etc/toolhive/authz-bundles/opa-bundle", volumeMount.MountPath)
+	assert.True(t, volumeMount.ReadOnly)
+	assert.Equal(t, volumeMount.Name, volume.Name)
+	require.NotNil(t, volume.ConfigMap)
+	assert.Equal(t, "opa-bundle", volume.ConfigMap.Name)
+
+	for _, ref := range []*mcpv1alpha1.AuthzConfigRef{
+		nil,
+		configMapRef("cedar-authz"),
+		configMapRef("missing"),
+		{Type: mcpv1alpha1.AuthzConfigTypeInline, Inline: &mcpv1alpha1.InlineAuthzConfig{}},
+	} {
+		volumeMount, volume := GenerateOPABundleVolumeConfig(context.Background(), client, "default", ref)
+		assert.Nil(t, volumeMount)
+		assert.Nil(t, volume)
+	}
+}
+
 // Helper function to create a NamespacedName key
 func getKey(namespace, name string) struct {
 	Namespace string
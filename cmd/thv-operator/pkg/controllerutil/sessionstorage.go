@@ -0,0 +1,88 @@
+package controllerutil
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	mcpv1alpha1 "github.com/stacklok/toolhive/cmd/thv-operator/api/v1alpha1"
+	"github.com/stacklok/toolhive/pkg/runner"
+	"github.com/stacklok/toolhive/pkg/transport/session"
+)
+
+// SessionStorageRedisPasswordEnvVar is the proxy environment variable that carries the Redis
+// session storage password referenced by the MCPServer spec
+const SessionStorageRedisPasswordEnvVar = "TOOLHIVE_SESSION_REDIS_PASSWORD"
+
+// AddSessionStorageConfigOptions adds session storage configuration options to the builder options
+func AddSessionStorageConfigOptions(
+	options *[]runner.RunConfigBuilderOption,
+	storageConfig *mcpv1alpha1.SessionStorageConfig,
+) {
+	if storageConfig == nil {
+		return
+	}
+
+	config := &session.StorageConfig{
+		Type: storageConfig.Type,
+	}
+	if storageConfig.Redis != nil {
+		config.Redis = &session.RedisConfig{
+			Address:   storageConfig.Redis.Address,
+			DB:        int(storageConfig.Redis.DB),
+			KeyPrefix: storageConfig.Redis.KeyPrefix,
+			TLS:       storageConfig.Redis.TLS,
+		}
+		if storageConfig.Redis.PasswordRef != nil {
+			config.Redis.PasswordEnv = SessionStorageRedisPasswordEnvVar
+		}
+	}
+
+	*options = append(*options, runner.WithSessionStorage(config))
+}
+
+// GenerateSessionStoragePasswordEnvVar generates the proxy environment variable for the
+// Redis session storage password. It returns nil if no password secret is referenced.
+func GenerateSessionStoragePasswordEnvVar(
+	ctx context.Context,
+	c client.Client,
+	namespace string,
+	storageConfig *mcpv1alpha1.SessionStorageConfig,
+) (*corev1.EnvVar, error) {
+	if storageConfig == nil || storageConfig.Type != mcpv1alpha1.SessionStorageTypeRedis ||
+		storageConfig.Redis == nil || storageConfig.Redis.PasswordRef == nil {
+		return nil, nil
+	}
+	passwordRef := storageConfig.Redis.PasswordRef
+
+	// Validate that the referenced secret exists
+	var secret corev1.Secret
+	if err := c.Get(ctx, types.NamespacedName{
+		Namespace: namespace,
+		Name:      passwordRef.Name,
+	}, &secret); err != nil {
+		return nil, fmt.Errorf("failed to get session storage password secret %s/%s: %w",
+			namespace, passwordRef.Name, err)
+	}
+
+	// Validate that the key exists in the secret
+	if _, ok := secret.Data[passwordRef.Key]; !ok {
+		return nil, fmt.Errorf("session storage password secret %s/%s is missing key %q",
+			namespace, passwordRef.Name, passwordRef.Key)
+	}
+
+	return &corev1.EnvVar{
+		Name: SessionStorageRedisPasswordEnvVar,
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: passwordRef.Name,
+				},
+				Key: passwordRef.Key,
+			},
+		},
+	}, nil
+}
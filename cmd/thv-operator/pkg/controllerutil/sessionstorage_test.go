@@ -0,0 +1,165 @@
+package controllerutil
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	mcpv1alpha1 "github.com/stacklok/toolhive/cmd/thv-operator/api/v1alpha1"
+	"github.com/stacklok/toolhive/pkg/runner"
+	"github.com/stacklok/toolhive/pkg/transport/session"
+)
+
+func TestAddSessionStorageConfigOptions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		storageConfig *mcpv1alpha1.SessionStorageConfig
+		expected      *session.StorageConfig
+	}{
+		{
+			name:          "nil config adds no option",
+			storageConfig: nil,
+			expected:      nil,
+		},
+		{
+			name:          "local storage",
+			storageConfig: &mcpv1alpha1.SessionStorageConfig{Type: mcpv1alpha1.SessionStorageTypeLocal},
+			expected:      &session.StorageConfig{Type: session.StorageTypeLocal},
+		},
+		{
+			name: "redis storage with password",
+			storageConfig: &mcpv1alpha1.SessionStorageConfig{
+				Type: mcpv1alpha1.SessionStorageTypeRedis,
+				Redis: &mcpv1alpha1.RedisSessionStorageConfig{
+					Address:     "redis:6379",
+					DB:          2,
+					KeyPrefix:   "sessions:",
+					PasswordRef: &mcpv1alpha1.SecretKeyRef{Name: "redis", Key: "password"},
+					TLS:         true,
+				},
+			},
+			expected: &session.StorageConfig{
+				Type: session.StorageTypeRedis,
+				Redis: &session.RedisConfig{
+					Address:     "redis:6379",
+					DB:          2,
+					KeyPrefix:   "sessions:",
+					PasswordEnv: SessionStorageRedisPasswordEnvVar,
+					TLS:         true,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var options []runner.RunConfigBuilderOption
+			AddSessionStorageConfigOptions(&options, tt.storageConfig)
+			if tt.expected == nil {
+				assert.Empty(t, options)
+				return
+			}
+
+			config, err := runner.NewOperatorRunConfigBuilder(context.Background(), nil, nil, nil, options...)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, config.SessionStorage)
+		})
+	}
+}
+
+func TestGenerateSessionStoragePasswordEnvVar(t *testing.T) {
+	t.Parallel()
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "redis-secret",
+			Namespace: "default",
+		},
+		Data: map[string][]byte{
+			"password": []byte("secret-value"),
+		},
+	}
+
+	redisConfig := func(key string) *mcpv1alpha1.SessionStorageConfig {
+		return &mcpv1alpha1.SessionStorageConfig{
+			Type: mcpv1alpha1.SessionStorageTypeRedis,
+			Redis: &mcpv1alpha1.RedisSessionStorageConfig{
+				Address:     "redis:6379",
+				PasswordRef: &mcpv1alpha1.SecretKeyRef{Name: "redis-secret", Key: key},
+			},
+		}
+	}
+
+	tests := []struct {
+		name          string
+		storageConfig *mcpv1alpha1.SessionStorageConfig
+		expectError   bool
+		errContains   string
+		expectEnvVar  bool
+	}{
+		{
+			name:          "nil config returns nil",
+			storageConfig: nil,
+		},
+		{
+			name:          "local storage returns nil",
+			storageConfig: &mcpv1alpha1.SessionStorageConfig{Type: mcpv1alpha1.SessionStorageTypeLocal},
+		},
+		{
+			name: "redis without password returns nil",
+			storageConfig: &mcpv1alpha1.SessionStorageConfig{
+				Type:  mcpv1alpha1.SessionStorageTypeRedis,
+				Redis: &mcpv1alpha1.RedisSessionStorageConfig{Address: "redis:6379"},
+			},
+		},
+		{
+			name:          "valid password ref generates env var",
+			storageConfig: redisConfig("password"),
+			expectEnvVar:  true,
+		},
+		{
+			name:          "missing key in secret returns error",
+			storageConfig: redisConfig("wrong-key"),
+			expectError:   true,
+			errContains:   "is missing key",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			scheme := runtime.NewScheme()
+			require.NoError(t, corev1.AddToScheme(scheme))
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret.DeepCopy()).Build()
+
+			envVar, err := GenerateSessionStoragePasswordEnvVar(context.TODO(), fakeClient, "default", tt.storageConfig)
+			if tt.expectError {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContains)
+				return
+			}
+			require.NoError(t, err)
+			if !tt.expectEnvVar {
+				assert.Nil(t, envVar)
+				return
+			}
+			require.NotNil(t, envVar)
+			assert.Equal(t, SessionStorageRedisPasswordEnvVar, envVar.Name)
+			require.NotNil(t, envVar.ValueFrom)
+			require.NotNil(t, envVar.ValueFrom.SecretKeyRef)
+			assert.Equal(t, "redis-secret", envVar.ValueFrom.SecretKeyRef.Name)
+			assert.Equal(t, "password", envVar.ValueFrom.SecretKeyRef.Key)
+		})
+	}
+}
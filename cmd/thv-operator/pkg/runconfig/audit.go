@@ -18,5 +18,8 @@ func AddAuditConfigOptions(
 	}
 
 	// Add audit config to options with default config (no custom config path for now)
-	*options = append(*options, runner.WithAuditEnabled(auditConfig.Enabled, ""))
+	*options = append(*options,
+		runner.WithAuditEnabled(auditConfig.Enabled, ""),
+		runner.WithAuditSinks(auditConfig.Sinks),
+	)
 }
@@ -8,9 +8,11 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	mcpv1alpha1 "github.com/stacklok/toolhive/cmd/thv-operator/api/v1alpha1"
+	"github.com/stacklok/toolhive/pkg/audit"
 	"github.com/stacklok/toolhive/pkg/runner"
 )
 
@@ -59,6 +61,57 @@ func TestAddAuditConfigOptions(t *testing.T) {
 				assert.NotNil(t, config.AuditConfig)
 			},
 		},
+		{
+			name: "with audit sinks",
+			mcpServer: &mcpv1alpha1.MCPServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "audit-sinks-server",
+					Namespace: "test-ns",
+				},
+				Spec: mcpv1alpha1.MCPServerSpec{
+					Image:     testImage,
+					Transport: stdioTransport,
+					ProxyPort: 8080,
+					Audit: &mcpv1alpha1.AuditConfig{
+						Enabled: true,
+						Sinks: []audit.SinkConfig{{
+							Type:   audit.SinkTypeSyslog,
+							Syslog: &audit.SyslogSinkConfig{Address: "syslog.logging.svc:6514", Network: audit.SyslogNetworkTLS},
+						}},
+					},
+				},
+			},
+			//nolint:thelper // We want to see the error at the specific line
+			expected: func(t *testing.T, config *runner.RunConfig) {
+				require.NotNil(t, config.AuditConfig)
+				require.Len(t, config.AuditConfig.Sinks, 1)
+				assert.Equal(t, "syslog.logging.svc:6514", config.AuditConfig.Sinks[0].Syslog.Address)
+			},
+		},
+		{
+			name: "with audit sinks and disabled audit configuration",
+			mcpServer: &mcpv1alpha1.MCPServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "audit-disabled-server",
+					Namespace: "test-ns",
+				},
+				Spec: mcpv1alpha1.MCPServerSpec{
+					Image:     testImage,
+					Transport: stdioTransport,
+					ProxyPort: 8080,
+					Audit: &mcpv1alpha1.AuditConfig{
+						Sinks: []audit.SinkConfig{{
+							Type:    audit.SinkTypeWebhook,
+							Webhook: &audit.WebhookSinkConfig{URL: "https://collector.example.com/events"},
+						}},
+					},
+				},
+			},
+			//nolint:thelper // We want to see the error at the specific line
+			expected: func(t *testing.T, config *runner.RunConfig) {
+				assert.Nil(t, config.AuditConfig)
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -118,6 +118,18 @@ func (c *Converter) Convert(
 	// Use Operational from spec.config directly
 	config.Operational = vmcp.Spec.Config.Operational
 
+	// Use TokenCache from spec.config directly. Redis passwords are referenced by
+	// environment variable name, which can be populated through podTemplateSpec.
+	config.TokenCache = vmcp.Spec.Config.TokenCache
+
+	// Use SessionStorage from spec.config directly, so that every replica of the
+	// Deployment shares client sessions and session affinity pins.
+	config.SessionStorage = vmcp.Spec.Config.SessionStorage
+
+	// Use WorkflowState from spec.config directly. The vMCP service account is
+	// allowed to manage ConfigMaps, which the configmap provider needs.
+	config.WorkflowState = vmcp.Spec.Config.WorkflowState
+
 	// Normalize telemetry config using the shared spectoconfig normalization logic.
 	// This applies runtime defaults and normalization (endpoint prefix stripping, service name defaults).
 	// Note: Most defaults (e.g., SamplingRate="0.05", TracingEnabled=false, MetricsEnabled=false)
@@ -132,6 +144,8 @@ func (c *Converter) Convert(
 		config.Audit.Component = vmcp.Name
 	}
 
+	config.RateLimit = vmcp.Spec.Config.RateLimit
+
 	// Apply operational defaults (fills missing values)
 	config.EnsureOperationalDefaults()
 
@@ -356,6 +370,11 @@ func (c *Converter) convertAggregation(
 		ExcludeAllTools:    srcAgg.ExcludeAllTools,
 	}
 
+	// Copy replica groups
+	for _, group := range srcAgg.ReplicaGroups {
+		agg.ReplicaGroups = append(agg.ReplicaGroups, group.DeepCopy())
+	}
+
 	// Apply defaults for conflict resolution
 	c.applyConflictResolutionDefaults(srcAgg, agg)
 
@@ -1543,3 +1543,103 @@ func TestConverter_TelemetryEndpointPrefixStripping(t *testing.T) {
 		})
 	}
 }
+
+func TestConverter_TokenCachePreserved(t *testing.T) {
+	t.Parallel()
+
+	tokenCache := &vmcpconfig.TokenCacheConfig{
+		Provider:      "redis",
+		RefreshOffset: vmcpconfig.Duration(2 * time.Minute),
+		Redis: &vmcpconfig.RedisTokenCacheConfig{
+			Address:     "valkey:6379",
+			PasswordEnv: "VALKEY_PASSWORD",
+		},
+	}
+	vmcp := &mcpv1alpha1.VirtualMCPServer{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-vmcp",
+			Namespace: "default",
+		},
+		Spec: mcpv1alpha1.VirtualMCPServerSpec{
+			IncomingAuth: &mcpv1alpha1.IncomingAuthConfig{
+				Type: "anonymous",
+			},
+			Config: vmcpconfig.Config{
+				Group:      "test-group",
+				TokenCache: tokenCache,
+			},
+		},
+	}
+
+	converter := newTestConverter(t, newNoOpMockResolver(t))
+	ctx := log.IntoContext(context.Background(), logr.Discard())
+
+	config, err := converter.Convert(ctx, vmcp)
+	require.NoError(t, err)
+	assert.Equal(t, tokenCache, config.TokenCache)
+}
+
+func TestConverter_SessionStoragePreserved(t *testing.T) {
+	t.Parallel()
+
+	sessionStorage := &vmcpconfig.SessionStorageConfig{
+		Provider: "redis",
+		Redis: &vmcpconfig.RedisSessionStorageConfig{
+			Address:     "valkey:6379",
+			PasswordEnv: "VALKEY_PASSWORD",
+		},
+	}
+	vmcp := &mcpv1alpha1.VirtualMCPServer{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-vmcp",
+			Namespace: "default",
+		},
+		Spec: mcpv1alpha1.VirtualMCPServerSpec{
+			IncomingAuth: &mcpv1alpha1.IncomingAuthConfig{
+				Type: "anonymous",
+			},
+			Config: vmcpconfig.Config{
+				Group:          "test-group",
+				SessionStorage: sessionStorage,
+			},
+		},
+	}
+
+	converter := newTestConverter(t, newNoOpMockResolver(t))
+	ctx := log.IntoContext(context.Background(), logr.Discard())
+
+	config, err := converter.Convert(ctx, vmcp)
+	require.NoError(t, err)
+	assert.Equal(t, sessionStorage, config.SessionStorage)
+}
+
+func TestConverter_WorkflowStatePreserved(t *testing.T) {
+	t.Parallel()
+
+	workflowState := &vmcpconfig.WorkflowStateConfig{
+		Provider:        "configmap",
+		RetentionPeriod: vmcpconfig.Duration(2 * time.Hour),
+	}
+	vmcp := &mcpv1alpha1.VirtualMCPServer{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-vmcp",
+			Namespace: "default",
+		},
+		Spec: mcpv1alpha1.VirtualMCPServerSpec{
+			IncomingAuth: &mcpv1alpha1.IncomingAuthConfig{
+				Type: "anonymous",
+			},
+			Config: vmcpconfig.Config{
+				Group:         "test-group",
+				WorkflowState: workflowState,
+			},
+		},
+	}
+
+	converter := newTestConverter(t, newNoOpMockResolver(t))
+	ctx := log.IntoContext(context.Background(), logr.Discard())
+
+	config, err := converter.Convert(ctx, vmcp)
+	require.NoError(t, err)
+	assert.Equal(t, workflowState, config.WorkflowState)
+}
@@ -0,0 +1,342 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"github.com/stacklok/toolhive/pkg/client"
+	"github.com/stacklok/toolhive/pkg/container/runtime"
+	"github.com/stacklok/toolhive/pkg/groups"
+	"github.com/stacklok/toolhive/pkg/labels"
+	"github.com/stacklok/toolhive/pkg/manifest"
+	"github.com/stacklok/toolhive/pkg/networking"
+	"github.com/stacklok/toolhive/pkg/workloads"
+)
+
+const manifestLong = `
+A manifest is a YAML or JSON file that declares a group, its MCP servers, the defaults
+shared by these servers and the clients registered with the group:
+
+	group: team-tools
+	defaults:
+	  env_vars:
+	    LOG_LEVEL: info
+	clients:
+	  - vscode
+	  - cursor
+	servers:
+	  - name: fetch
+	    server: fetch
+	  - name: github
+	    server: ghcr.io/github/github-mcp-server:latest
+	    secrets:
+	      - github-token,target=GITHUB_PERSONAL_ACCESS_TOKEN
+	    permission_profile: network
+	  - name: git
+	    server: uvx://mcp-server-git
+	    args: ["--repository", "/projects/repo"]
+	    volumes:
+	      - ./repo:/projects/repo:ro
+
+Servers accept the registry server names, container images, protocol schemes and remote
+URLs accepted by 'thv run', as well as its transport, proxy_mode, proxy_port, target_port,
+env, secrets, permission_profile, volumes, tools, isolate_network and labels settings.
+
+Servers that are not in the group, or whose manifest entry or group defaults changed since
+they were applied, are recreated. With --prune, the workloads and clients of the group that
+are not in the manifest, and the defaults of the group if the manifest sets none, are removed.`
+
+var (
+	manifestFile  string
+	manifestPrune bool
+)
+
+var applyCmd = &cobra.Command{
+	Use:   "apply -f FILE",
+	Short: "Apply a manifest of a group of MCP servers",
+	Long: `Reconcile a group of MCP servers with a manifest: create the group, set its defaults,
+register its clients and run, update or remove its servers.
+` + manifestLong + `
+
+Examples:
+  # Show the changes, then apply them
+  thv diff -f stack.yaml
+  thv apply -f stack.yaml
+
+  # Also remove the servers of the group that are not in the manifest
+  thv apply -f stack.yaml --prune`,
+	Args: cobra.NoArgs,
+	RunE: applyCmdFunc,
+}
+
+var diffCmd = &cobra.Command{
+	Use:   "diff -f FILE",
+	Short: "Show the changes applying a manifest would make",
+	Long: `Show the changes 'thv apply' would make to reconcile a group of MCP servers with a manifest.
+
+Lines start with + for additions, ~ for updates and - for removals.
+` + manifestLong,
+	Args: cobra.NoArgs,
+	RunE: diffCmdFunc,
+}
+
+func init() {
+	for _, cmd := range []*cobra.Command{applyCmd, diffCmd} {
+		cmd.Flags().StringVarP(&manifestFile, "file", "f", "", "Path to the manifest file")
+		cmd.Flags().BoolVar(&manifestPrune, "prune", false,
+			"Remove the servers and clients of the group that are not in the manifest")
+		if err := cmd.MarkFlagRequired("file"); err != nil {
+			panic(err)
+		}
+	}
+}
+
+func diffCmdFunc(cmd *cobra.Command, _ []string) error {
+	ctx := cmd.Context()
+
+	m, err := manifest.Load(manifestFile)
+	if err != nil {
+		return err
+	}
+	groupManager, workloadManager, err := newManifestManagers(ctx)
+	if err != nil {
+		return err
+	}
+	plan, err := planManifest(ctx, m, groupManager, workloadManager)
+	if err != nil {
+		return err
+	}
+	return plan.Write(os.Stdout)
+}
+
+func applyCmdFunc(cmd *cobra.Command, _ []string) error {
+	ctx := cmd.Context()
+
+	m, err := manifest.Load(manifestFile)
+	if err != nil {
+		return err
+	}
+	groupManager, workloadManager, err := newManifestManagers(ctx)
+	if err != nil {
+		return err
+	}
+	plan, err := planManifest(ctx, m, groupManager, workloadManager)
+	if err != nil {
+		return err
+	}
+	if err := plan.Write(os.Stdout); err != nil {
+		return err
+	}
+	if !plan.HasChanges() {
+		return nil
+	}
+
+	if err := applyGroupChanges(ctx, plan, groupManager, workloadManager); err != nil {
+		return err
+	}
+
+	// Remove the pruned workloads first, so that their ports can be reused
+	var deleted []string
+	for _, change := range plan.Servers {
+		if change.Action == manifest.ActionDelete {
+			deleted = append(deleted, change.Name)
+		}
+	}
+	if len(deleted) > 0 {
+		complete, err := workloadManager.DeleteWorkloads(ctx, deleted)
+		if err != nil {
+			return fmt.Errorf("failed to delete workloads: %w", err)
+		}
+		if err := complete(); err != nil {
+			return fmt.Errorf("failed to delete workloads: %w", err)
+		}
+		fmt.Printf("Servers %v removed from group '%s'.\n", deleted, plan.Group)
+	}
+
+	debugMode, _ := cmd.Flags().GetBool("debug")
+	for _, change := range plan.Servers {
+		if change.Action != manifest.ActionCreate && change.Action != manifest.ActionUpdate {
+			continue
+		}
+		if err := applyServer(ctx, m, change, debugMode, workloadManager); err != nil {
+			return fmt.Errorf("failed to apply server '%s': %w", change.Name, err)
+		}
+	}
+
+	fmt.Printf("Manifest applied to group '%s'.\n", plan.Group)
+	return nil
+}
+
+// newManifestManagers creates the group and workload managers that reconcile manifests
+func newManifestManagers(ctx context.Context) (groups.Manager, workloads.Manager, error) {
+	if runtime.IsKubernetesRuntime() {
+		return nil, nil, fmt.Errorf("manifests are not supported in Kubernetes")
+	}
+	groupManager, err := groups.NewManager()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create group manager: %w", err)
+	}
+	workloadManager, err := workloads.NewManager(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create workload manager: %w", err)
+	}
+	return groupManager, workloadManager, nil
+}
+
+// planManifest computes the changes that reconcile the local state with a manifest
+func planManifest(
+	ctx context.Context,
+	m *manifest.Manifest,
+	groupManager groups.Manager,
+	workloadManager workloads.Manager,
+) (*manifest.Plan, error) {
+	exists, err := groupManager.Exists(ctx, m.Group)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check if group exists: %w", err)
+	}
+	var group *groups.Group
+	if exists {
+		group, err = groupManager.Get(ctx, m.Group)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get group: %w", err)
+		}
+	}
+
+	allWorkloads, err := workloadManager.ListWorkloads(ctx, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workloads: %w", err)
+	}
+
+	return manifest.NewPlan(m, group, allWorkloads, manifestPrune), nil
+}
+
+// applyGroupChanges creates the group, sets its defaults and registers its clients.
+// Clients are registered before the servers run, so that the servers are added to them.
+func applyGroupChanges(
+	ctx context.Context,
+	plan *manifest.Plan,
+	groupManager groups.Manager,
+	workloadManager workloads.Manager,
+) error {
+	if plan.CreateGroup {
+		if err := groupManager.Create(ctx, plan.Group); err != nil {
+			return fmt.Errorf("failed to create group: %w", err)
+		}
+	}
+	if plan.UpdateDefaults {
+		if err := groupManager.SetDefaults(ctx, plan.Group, plan.Defaults); err != nil {
+			return fmt.Errorf("failed to set group defaults: %w", err)
+		}
+	}
+	if len(plan.RegisterClients) == 0 && len(plan.UnregisterClients) == 0 {
+		return nil
+	}
+
+	clientManager, err := client.NewManager(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create client manager: %w", err)
+	}
+	runningWorkloads, err := workloadManager.ListWorkloads(ctx, false)
+	if err != nil {
+		return fmt.Errorf("failed to list running workloads: %w", err)
+	}
+	groupNames := []string{plan.Group}
+
+	for _, clientName := range plan.UnregisterClients {
+		clientToRemove := client.Client{Name: client.MCPClient(clientName)}
+		if err := removeClientFromGroups(ctx, clientToRemove, groupNames, runningWorkloads, groupManager, clientManager); err != nil {
+			return err
+		}
+	}
+	if len(plan.RegisterClients) > 0 {
+		clients := make([]client.Client, len(plan.RegisterClients))
+		for i, clientName := range plan.RegisterClients {
+			clients[i] = client.Client{Name: client.MCPClient(clientName)}
+		}
+		if err := registerClientsWithGroups(ctx, clients, groupNames, clientManager, runningWorkloads); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyServer runs a server of a manifest, recreating the existing workload when it is updated.
+// The run configuration is built the same way as for `thv run`.
+func applyServer(
+	ctx context.Context,
+	m *manifest.Manifest,
+	change manifest.ServerChange,
+	debugMode bool,
+	workloadManager workloads.Manager,
+) error {
+	// Register the run flags on a command of their own to start from their defaults
+	cmd := &cobra.Command{}
+	serverFlags := &RunFlags{}
+	AddRunFlags(cmd, serverFlags)
+	setManifestRunFlags(serverFlags, m, change.Server)
+
+	runnerConfig, err := BuildRunnerConfig(ctx, serverFlags, change.Server.Server, change.Server.Args, debugMode, cmd, "")
+	if err != nil {
+		return err
+	}
+
+	if change.Action == manifest.ActionUpdate {
+		complete, err := workloadManager.UpdateWorkload(ctx, change.Name, runnerConfig)
+		if err != nil {
+			return err
+		}
+		if err := complete(); err != nil {
+			return err
+		}
+		fmt.Printf("Server '%s' updated.\n", change.Name)
+		return nil
+	}
+
+	// NOTE: Save before secrets processing to avoid storing secrets in the state store
+	if err := runnerConfig.SaveState(ctx); err != nil {
+		return fmt.Errorf("failed to save run configuration: %w", err)
+	}
+	if err := workloadManager.RunWorkloadDetached(ctx, runnerConfig); err != nil {
+		return err
+	}
+	fmt.Printf("Server '%s' created.\n", change.Name)
+	return nil
+}
+
+// setManifestRunFlags sets the run flags of a server of a manifest
+func setManifestRunFlags(runFlags *RunFlags, m *manifest.Manifest, server *manifest.Server) {
+	runFlags.Name = server.Name
+	runFlags.Group = m.Group
+	runFlags.Transport = server.Transport
+	if server.ProxyMode != "" {
+		runFlags.ProxyMode = server.ProxyMode
+	}
+	runFlags.ProxyPort = server.ProxyPort
+	runFlags.TargetPort = server.TargetPort
+	runFlags.PermissionProfile = server.PermissionProfile
+	runFlags.Volumes = server.Volumes
+	runFlags.Secrets = server.Secrets
+	runFlags.ToolsFilter = server.Tools
+	runFlags.IsolateNetwork = server.IsolateNetwork
+	if networking.IsURL(server.Server) {
+		runFlags.RemoteURL = server.Server
+	}
+
+	runFlags.Env = sortedKeyValues(server.Env)
+	runFlags.Labels = append(sortedKeyValues(server.Labels),
+		labels.LabelManifestHash+"="+server.Hash(m.Defaults))
+}
+
+// sortedKeyValues returns the key=value pairs of a map, sorted by key
+func sortedKeyValues(values map[string]string) []string {
+	pairs := make([]string, 0, len(values))
+	for key, value := range values {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return pairs
+}
@@ -0,0 +1,397 @@
+package app
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/spf13/cobra"
+
+	"github.com/stacklok/toolhive/pkg/audit"
+	"github.com/stacklok/toolhive/pkg/audit/query"
+	"github.com/stacklok/toolhive/pkg/workloads"
+)
+
+var (
+	auditVerifyPublicKey        string
+	auditVerifySigningKeySecret string
+	auditVerifyFormat           string
+
+	auditQueryFiles    []string
+	auditQuerySubject  string
+	auditQueryTypes    []string
+	auditQueryOutcomes []string
+	auditQueryTool     string
+	auditQuerySince    string
+	auditQueryUntil    string
+	auditQueryLimit    int
+	auditQueryFormat   string
+
+	auditReplayTarget    string
+	auditReplayWorkloads []string
+	auditReplayFiles     []string
+	auditReplayFormat    string
+)
+
+func newAuditCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Inspect and verify audit logs",
+		Long: `Inspect and verify the audit logs written by MCP servers and the Virtual MCP server.
+
+Audit logging is enabled with 'thv run --enable-audit' or an audit configuration file
+passed with --audit-config.`,
+	}
+
+	cmd.AddCommand(
+		newAuditQueryCommand(),
+		newAuditReplayCommand(),
+		newAuditVerifyCommand(),
+	)
+
+	return cmd
+}
+
+func newAuditQueryCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "query [workload-name...]",
+		Short: "Search the audit events of MCP servers",
+		Long: `Search the audit events recorded for MCP servers, and export them as CSV or JSON.
+
+The events of a workload are read from the log file of its audit configuration or, when
+none is set, from the log of its proxy, and from its egress log. Without workload names,
+the events of all the workloads are searched. Use --file to search audit log files
+instead, e.g. the audit log of a Virtual MCP server.
+
+Times are RFC 3339 times, dates or durations relative to now.
+
+Examples:
+  # Show the tools called by alice on the fetch server in the last week
+  thv audit query fetch --subject alice --type mcp_tool_call --since 168h
+
+  # Export the denied requests of all the workloads as CSV
+  thv audit query --outcome denied --format csv > denied.csv
+
+  # Search the workflow events of a Virtual MCP server audit log
+  thv audit query --file /var/log/vmcp/audit.log --type vmcp_workflow_started,vmcp_workflow_failed`,
+		RunE: auditQueryCmdFunc,
+	}
+
+	cmd.Flags().StringSliceVar(&auditQueryFiles, "file", nil, "Search these audit log files instead of workloads")
+	cmd.Flags().StringVar(&auditQuerySubject, "subject", "", "Only show events of this subject, e.g. a user or client name")
+	cmd.Flags().StringSliceVar(&auditQueryTypes, "type", nil, "Only show events of these types (e.g. mcp_tool_call)")
+	cmd.Flags().StringSliceVar(&auditQueryOutcomes, "outcome", nil, "Only show events with these outcomes (e.g. denied)")
+	cmd.Flags().StringVar(&auditQueryTool, "tool", "", "Only show the calls of this tool")
+	cmd.Flags().StringVar(&auditQuerySince, "since", "", "Only show events after this time (e.g. 24h or 2025-06-01)")
+	cmd.Flags().StringVar(&auditQueryUntil, "until", "", "Only show events before this time")
+	cmd.Flags().IntVar(&auditQueryLimit, "limit", 0, "Only show the most recent events (0 for no limit)")
+	AddFormatFlag(cmd, &auditQueryFormat, FormatJSON, FormatText, FormatCSV)
+	cmd.PreRunE = ValidateFormat(&auditQueryFormat, FormatJSON, FormatText, FormatCSV)
+
+	return cmd
+}
+
+func newAuditReplayCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "replay <audit-id>",
+		Short: "Replay a recorded tool call against a sandbox workload",
+		Long: `Replay a tool call recorded in an audit event against a sandbox workload, e.g. to
+reproduce an incident. The tool is called with the recorded arguments, and its result is printed.
+
+The request is only recorded when the audit configuration sets includeRequestData. The call
+cannot be replayed against the workload it was recorded for, as it would repeat its side effects.
+The target must run in a sandbox: with the process runtime, with the network mode none, or with
+the toolhive-sandbox=true label.
+
+Examples:
+  # Replay a call recorded for the fetch server against the fetch-sandbox workload
+  thv audit replay 4b3c6a5e-5c1e-4d2a-9f0e-2b8d1c7a9e10 --workload fetch --target fetch-sandbox`,
+		Args: cobra.ExactArgs(1),
+		RunE: auditReplayCmdFunc,
+	}
+
+	cmd.Flags().StringVar(&auditReplayTarget, "target", "", "Name of the sandbox workload to replay the call against")
+	cmd.Flags().StringSliceVar(&auditReplayWorkloads, "workload", nil,
+		"Search the event in the audit events of these workloads (default: all workloads)")
+	cmd.Flags().StringSliceVar(&auditReplayFiles, "file", nil, "Search the event in these audit log files")
+	cmd.MarkFlagsMutuallyExclusive("workload", "file")
+	_ = cmd.MarkFlagRequired("target")
+	AddFormatFlag(cmd, &auditReplayFormat, FormatJSON, FormatText)
+	cmd.PreRunE = ValidateFormat(&auditReplayFormat, FormatJSON, FormatText)
+
+	return cmd
+}
+
+func newAuditVerifyCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "verify <file>",
+		Short: "Verify the integrity of a hash-chained audit log",
+		Long: `Verify the integrity of an audit log written with the integrity mode enabled.
+
+In this mode, each event carries its sequence number and the hash of the previous event,
+and checkpoints signed with an Ed25519 key are written periodically. This command detects
+missing, reordered, inserted and modified events. When the public key is given, with
+--public-key or --signing-key-secret, the signatures of the checkpoints are verified too,
+which detects a log rewritten from scratch.
+
+The command fails if an integrity violation is found. Events after the last checkpoint
+are reported as not covered by a signature: they are expected if the writer did not
+shut down cleanly, but the end of the log may have been truncated.
+
+Examples:
+  # Verify the hash chain and the checkpoints with a public key
+  thv audit verify /var/log/toolhive/audit.log --public-key audit-signing.pub
+
+  # Verify with the public key of the signing key stored in the secrets provider
+  thv audit verify /var/log/toolhive/audit.log --signing-key-secret audit-signing-key`,
+		Args: cobra.ExactArgs(1),
+		RunE: auditVerifyCmdFunc,
+	}
+
+	cmd.Flags().StringVar(&auditVerifyPublicKey, "public-key", "",
+		"Path to the Ed25519 public key verifying the checkpoints (PEM or base64)")
+	cmd.Flags().StringVar(&auditVerifySigningKeySecret, "signing-key-secret", "",
+		"Name of the secret holding the signing key, whose public key verifies the checkpoints")
+	cmd.MarkFlagsMutuallyExclusive("public-key", "signing-key-secret")
+	AddFormatFlag(cmd, &auditVerifyFormat, FormatJSON, FormatText)
+	cmd.PreRunE = ValidateFormat(&auditVerifyFormat, FormatJSON, FormatText)
+
+	return cmd
+}
+
+func auditQueryCmdFunc(cmd *cobra.Command, args []string) error {
+	if len(auditQueryFiles) > 0 && len(args) > 0 {
+		return errors.New("workload names cannot be used with --file")
+	}
+
+	filter := query.Filter{
+		Subject:    auditQuerySubject,
+		EventTypes: auditQueryTypes,
+		Outcomes:   auditQueryOutcomes,
+		Tool:       auditQueryTool,
+		Limit:      auditQueryLimit,
+	}
+	now := time.Now()
+	var err error
+	if auditQuerySince != "" {
+		if filter.Since, err = query.ParseTime(auditQuerySince, now); err != nil {
+			return err
+		}
+	}
+	if auditQueryUntil != "" {
+		if filter.Until, err = query.ParseTime(auditQueryUntil, now); err != nil {
+			return err
+		}
+	}
+
+	records, err := readAuditRecords(cmd, args, auditQueryFiles, filter)
+	if err != nil {
+		return err
+	}
+
+	switch auditQueryFormat {
+	case FormatJSON:
+		if records == nil {
+			records = []*query.Record{}
+		}
+		return printAuditJSON(records)
+	case FormatCSV:
+		return query.WriteCSV(os.Stdout, records)
+	default:
+		if len(records) == 0 {
+			fmt.Println("No audit events found")
+			return nil
+		}
+		printAuditRecordsText(records)
+		return nil
+	}
+}
+
+// readAuditRecords reads the audit events of audit log files if any are given, or of workloads
+func readAuditRecords(cmd *cobra.Command, workloadNames, files []string, filter query.Filter) ([]*query.Record, error) {
+	if len(files) == 0 {
+		records, err := query.ReadWorkloads(cmd.Context(), workloadNames, filter)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read audit events: %w", err)
+		}
+		return records, nil
+	}
+
+	for _, file := range files {
+		if _, err := os.Stat(file); err != nil {
+			return nil, fmt.Errorf("failed to read audit log: %w", err)
+		}
+	}
+	return query.ReadFiles(files, filter)
+}
+
+func auditReplayCmdFunc(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	records, err := readAuditRecords(cmd, auditReplayWorkloads, auditReplayFiles, query.Filter{AuditID: args[0], Limit: 1})
+	if err != nil {
+		return err
+	}
+	record, err := query.Find(records, args[0])
+	if err != nil {
+		return err
+	}
+
+	manager, err := workloads.NewManager(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create workload manager: %w", err)
+	}
+	result, err := query.ReplayOnWorkload(ctx, manager, record, auditReplayTarget)
+	if err != nil {
+		return err
+	}
+
+	if auditReplayFormat == FormatJSON {
+		return printAuditJSON(result)
+	}
+	printToolResultText(result)
+	return nil
+}
+
+func printAuditJSON(v any) error {
+	jsonData, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	fmt.Println(string(jsonData))
+	return nil
+}
+
+func printAuditRecordsText(records []*query.Record) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	if _, err := fmt.Fprintln(w, "TIME\tWORKLOAD\tTYPE\tOUTCOME\tSUBJECT\tTOOL\tAUDIT ID"); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Failed to write output: %v\n", err)
+		return
+	}
+	for _, record := range records {
+		if _, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			record.Time.Local().Format(time.DateTime),
+			valueOrDash(record.Workload),
+			record.Type,
+			record.Outcome,
+			valueOrDash(auditSubject(record)),
+			valueOrDash(record.Tool()),
+			record.AuditID,
+		); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Failed to write audit event: %v\n", err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Failed to flush output: %v\n", err)
+	}
+}
+
+// auditSubject returns the most specific subject of an audit event
+func auditSubject(record *query.Record) string {
+	for _, key := range []string{audit.SubjectKeyUser, audit.SubjectKeyUserID, audit.SubjectKeyClientName} {
+		if subject := record.Subjects[key]; subject != "" {
+			return subject
+		}
+	}
+	return ""
+}
+
+func valueOrDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+func printToolResultText(result *mcp.CallToolResult) {
+	var texts []string
+	for _, content := range result.Content {
+		if text, ok := content.(mcp.TextContent); ok {
+			texts = append(texts, text.Text)
+		} else {
+			texts = append(texts, fmt.Sprintf("[%T content]", content))
+		}
+	}
+	if result.IsError {
+		fmt.Println("The tool returned an error:")
+	}
+	fmt.Println(strings.Join(texts, "\n"))
+}
+
+func auditVerifyCmdFunc(cmd *cobra.Command, args []string) error {
+	var publicKey ed25519.PublicKey
+	switch {
+	case auditVerifyPublicKey != "":
+		data, err := os.ReadFile(filepath.Clean(auditVerifyPublicKey))
+		if err != nil {
+			return fmt.Errorf("failed to read public key: %w", err)
+		}
+		if publicKey, err = audit.ParsePublicKey(data); err != nil {
+			return fmt.Errorf("invalid public key %s: %w", auditVerifyPublicKey, err)
+		}
+	case auditVerifySigningKeySecret != "":
+		signingKey, err := audit.LoadSigningKey(cmd.Context(), auditVerifySigningKeySecret)
+		if err != nil {
+			return err
+		}
+		publicKey = signingKey.Public().(ed25519.PublicKey)
+	}
+
+	file, err := os.Open(filepath.Clean(args[0]))
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	result, err := audit.VerifyLog(file, publicKey)
+	if err != nil {
+		return err
+	}
+
+	if auditVerifyFormat == FormatJSON {
+		if err := printAuditJSON(result); err != nil {
+			return err
+		}
+	} else {
+		printAuditVerifyText(args[0], result)
+	}
+
+	if !result.Valid() {
+		return errors.New("audit log integrity check failed")
+	}
+	return nil
+}
+
+func printAuditVerifyText(path string, result *audit.VerifyResult) {
+	for _, problem := range result.Problems {
+		if problem.Line > 0 {
+			fmt.Printf("%s:%d: %s\n", path, problem.Line, problem.Message)
+		} else {
+			fmt.Printf("%s: %s\n", path, problem.Message)
+		}
+	}
+
+	fmt.Printf("Checked %d events and %d checkpoints in %d hash chain(s)\n",
+		result.Events, result.Checkpoints, result.Chains)
+	if result.UnchainedLines > 0 {
+		fmt.Printf("Warning: %d lines before the first chained event are not protected\n", result.UnchainedLines)
+	}
+	if result.UnsignedEvents > 0 {
+		fmt.Printf("Warning: %d events after the last checkpoint are not covered by a signature\n", result.UnsignedEvents)
+	}
+	if !result.SignaturesVerified {
+		fmt.Println("Warning: checkpoint signatures were not verified, use --public-key or --signing-key-secret")
+	}
+	if result.Valid() {
+		fmt.Println("No integrity violation found")
+	} else {
+		fmt.Printf("Found %d integrity violation(s)\n", len(result.Problems))
+	}
+}
@@ -55,6 +55,7 @@ Valid clients:
   - antigravity: Google Antigravity IDE
   - claude-code: Claude Code CLI
   - cline: Cline extension for VS Code
+  - codex: OpenAI Codex CLI
   - continue: Continue.dev extensions for VS Code and JetBrains
   - cursor: Cursor editor
   - goose: Goose AI agent
@@ -86,6 +87,7 @@ Valid clients:
   - antigravity: Google Antigravity IDE
   - claude-code: Claude Code CLI
   - cline: Cline extension for VS Code
+  - codex: OpenAI Codex CLI
   - continue: Continue.dev extensions for VS Code and JetBrains
   - cursor: Cursor editor
   - goose: Goose AI agent
@@ -206,13 +208,13 @@ func clientRegisterCmdFunc(cmd *cobra.Command, args []string) error {
 	switch clientType {
 	case "roo-code", "cline", "cursor", "claude-code", "vscode-insider", "vscode", "windsurf", "windsurf-jetbrains",
 		"amp-cli", "amp-vscode", "amp-vscode-insider", "amp-cursor", "amp-windsurf", "lm-studio", "goose", "trae",
-		"continue", "opencode", "kiro", "antigravity", "zed":
+		"continue", "opencode", "kiro", "antigravity", "zed", "codex":
 		// Valid client type
 	default:
 		return fmt.Errorf(
 			"invalid client type: %s (valid types: roo-code, cline, cursor, claude-code, vscode, vscode-insider, "+
 				"windsurf, windsurf-jetbrains, amp-cli, amp-vscode, amp-vscode-insider, amp-cursor, amp-windsurf, lm-studio, "+
-				"goose, trae, continue, opencode, kiro, antigravity, zed)",
+				"goose, trae, continue, opencode, kiro, antigravity, zed, codex)",
 			clientType)
 	}
 
@@ -226,13 +228,13 @@ func clientRemoveCmdFunc(cmd *cobra.Command, args []string) error {
 	switch clientType {
 	case "roo-code", "cline", "cursor", "claude-code", "vscode-insider", "vscode", "windsurf", "windsurf-jetbrains",
 		"amp-cli", "amp-vscode", "amp-vscode-insider", "amp-cursor", "amp-windsurf", "lm-studio", "goose", "trae",
-		"continue", "opencode", "kiro", "antigravity", "zed":
+		"continue", "opencode", "kiro", "antigravity", "zed", "codex":
 		// Valid client type
 	default:
 		return fmt.Errorf(
 			"invalid client type: %s (valid types: roo-code, cline, cursor, claude-code, vscode, vscode-insider, "+
 				"windsurf, windsurf-jetbrains, amp-cli, amp-vscode, amp-vscode-insider, amp-cursor, amp-windsurf, lm-studio, "+
-				"goose, trae, continue, opencode, kiro, antigravity, zed)",
+				"goose, trae, continue, opencode, kiro, antigravity, zed, codex)",
 			clientType)
 	}
 
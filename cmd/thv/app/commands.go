@@ -47,14 +47,20 @@ func NewRootCmd(enableUpdates bool) *cobra.Command {
 	rootCmd.AddCommand(rmCmd)
 	rootCmd.AddCommand(proxyCmd)
 	rootCmd.AddCommand(restartCmd)
+	rootCmd.AddCommand(upgradeCmd)
 	rootCmd.AddCommand(serveCmd)
 	rootCmd.AddCommand(newExportCmd())
+	rootCmd.AddCommand(importCmd)
 	rootCmd.AddCommand(newVersionCmd())
 	rootCmd.AddCommand(logsCommand())
 	rootCmd.AddCommand(newSecretCommand())
 	rootCmd.AddCommand(inspectorCommand())
 	rootCmd.AddCommand(newMCPCommand())
 	rootCmd.AddCommand(groupCmd)
+	rootCmd.AddCommand(newEgressCommand())
+	rootCmd.AddCommand(newAuditCommand())
+	rootCmd.AddCommand(applyCmd)
+	rootCmd.AddCommand(diffCmd)
 
 	// Silence printing the usage on error
 	rootCmd.SilenceUsage = true
@@ -89,6 +95,7 @@ func IsInformationalCommand(args []string) bool {
 		"completion": true,
 		"registry":   true,
 		"mcp":        true,
+		"egress":     true,
 	}
 
 	return informationalCommands[command]
@@ -6,4 +6,6 @@ const (
 	FormatJSON = "json"
 	// FormatText is the text output format
 	FormatText = "text"
+	// FormatCSV is the CSV output format
+	FormatCSV = "csv"
 )
@@ -0,0 +1,235 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/stacklok/toolhive/pkg/egress"
+	"github.com/stacklok/toolhive/pkg/permissions"
+	"github.com/stacklok/toolhive/pkg/runner"
+)
+
+var (
+	egressLogSince      time.Duration
+	egressLogHost       string
+	egressLogDenied     bool
+	egressLogSummary    bool
+	egressLogFormat     string
+	egressSuggestDenied bool
+)
+
+func newEgressCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "egress",
+		Short: "Inspect the outbound traffic of MCP servers",
+		Long: `Inspect the outbound traffic of MCP servers running with network isolation.
+
+When an MCP server runs with --isolate-network, every outbound request that goes through
+its egress proxy is recorded as an audit event in the egress log of the workload.`,
+	}
+
+	cmd.AddCommand(
+		newEgressLogCommand(),
+		newEgressSuggestCommand(),
+	)
+
+	return cmd
+}
+
+func newEgressLogCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "log [workload-name]",
+		Short: "Show the outbound requests of an MCP server",
+		Long: `Show the outbound requests of an MCP server recorded by its egress proxy.
+
+Examples:
+  # Show all outbound requests of an MCP server
+  thv egress log fetch
+
+  # Show the requests denied in the last hour
+  thv egress log fetch --denied --since 1h
+
+  # Show the requests to each destination host
+  thv egress log fetch --summary`,
+		Args: cobra.ExactArgs(1),
+		RunE: egressLogCmdFunc,
+	}
+
+	cmd.Flags().DurationVar(&egressLogSince, "since", 0, "Only show requests newer than a relative duration (e.g. 1h)")
+	cmd.Flags().StringVar(&egressLogHost, "host", "", "Only show requests to this destination host")
+	cmd.Flags().BoolVar(&egressLogDenied, "denied", false, "Only show requests denied by the egress proxy")
+	cmd.Flags().BoolVar(&egressLogSummary, "summary", false, "Aggregate requests per destination host")
+	AddFormatFlag(cmd, &egressLogFormat, FormatJSON, FormatText)
+	cmd.PreRunE = ValidateFormat(&egressLogFormat, FormatJSON, FormatText)
+
+	return cmd
+}
+
+func newEgressSuggestCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "suggest [workload-name]",
+		Short: "Suggest a least-privilege permission profile from recorded traffic",
+		Long: `Suggest a permission profile for an MCP server that only allows the outbound
+hosts and ports recorded by its egress proxy. The rest of the profile of the workload
+is kept as is. The profile is printed as JSON and can be used with 'thv run --permission-profile'.
+
+Examples:
+  # Suggest a profile from the allowed requests
+  thv egress suggest fetch > fetch-profile.json
+
+  # Also allow the hosts that the current profile denied
+  thv egress suggest fetch --include-denied`,
+		Args: cobra.ExactArgs(1),
+		RunE: egressSuggestCmdFunc,
+	}
+
+	cmd.Flags().BoolVar(&egressSuggestDenied, "include-denied", false,
+		"Also allow the hosts and ports of requests denied by the egress proxy")
+
+	return cmd
+}
+
+func egressLogCmdFunc(_ *cobra.Command, args []string) error {
+	filter := egress.Filter{
+		Host:       egressLogHost,
+		DeniedOnly: egressLogDenied,
+	}
+	if egressLogSince > 0 {
+		filter.Since = time.Now().Add(-egressLogSince)
+	}
+
+	records, err := egress.ReadWorkload(args[0], filter)
+	if err != nil {
+		return fmt.Errorf("failed to read egress log: %w", err)
+	}
+
+	if egressLogSummary {
+		summaries := egress.Summarize(records)
+		if egressLogFormat == FormatJSON {
+			return printEgressJSON(summaries)
+		}
+		printEgressSummaryText(summaries)
+		return nil
+	}
+
+	if egressLogFormat == FormatJSON {
+		if records == nil {
+			records = []*egress.Record{}
+		}
+		return printEgressJSON(records)
+	}
+	if len(records) == 0 {
+		fmt.Printf("No outbound requests recorded for workload %s\n", args[0])
+		return nil
+	}
+	printEgressRecordsText(records)
+	return nil
+}
+
+func egressSuggestCmdFunc(cmd *cobra.Command, args []string) error {
+	workloadName := args[0]
+
+	runConfig, err := runner.LoadState(cmd.Context(), workloadName)
+	if err != nil {
+		return fmt.Errorf("failed to load run configuration for workload '%s': %w", workloadName, err)
+	}
+
+	records, err := egress.ReadWorkload(workloadName, egress.Filter{})
+	if err != nil {
+		return fmt.Errorf("failed to read egress log: %w", err)
+	}
+	if len(records) == 0 {
+		return fmt.Errorf("no outbound requests recorded for workload %s, "+
+			"run it with --isolate-network to record its egress traffic", workloadName)
+	}
+
+	profile := runConfig.PermissionProfile
+	if profile == nil {
+		profile = permissions.BuiltinNetworkProfile()
+	}
+	if profile.Network == nil {
+		profile.Network = &permissions.NetworkPermissions{}
+	}
+	profile.Network.Outbound = egress.SuggestOutboundPermissions(records, egressSuggestDenied)
+
+	return printEgressJSON(profile)
+}
+
+func printEgressJSON(v any) error {
+	jsonData, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	fmt.Println(string(jsonData))
+	return nil
+}
+
+func printEgressRecordsText(records []*egress.Record) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	if _, err := fmt.Fprintln(w, "TIME\tHOST\tPORT\tMETHOD\tVERDICT\tSENT\tRECEIVED"); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Failed to write output: %v\n", err)
+		return
+	}
+	for _, record := range records {
+		if _, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%d\t%d\n",
+			record.Time.Local().Format(time.DateTime),
+			record.Host,
+			formatEgressPort(record.Port),
+			record.Method,
+			record.Verdict(),
+			record.BytesSent,
+			record.BytesReceived,
+		); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Failed to write egress record: %v\n", err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Failed to flush output: %v\n", err)
+	}
+}
+
+func printEgressSummaryText(summaries []egress.HostSummary) {
+	if len(summaries) == 0 {
+		fmt.Println("No outbound requests recorded")
+		return
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	if _, err := fmt.Fprintln(w, "HOST\tPORTS\tREQUESTS\tDENIED\tSENT\tRECEIVED\tLAST SEEN"); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Failed to write output: %v\n", err)
+		return
+	}
+	for _, summary := range summaries {
+		ports := make([]string, 0, len(summary.Ports))
+		for _, port := range summary.Ports {
+			ports = append(ports, strconv.Itoa(port))
+		}
+		if _, err := fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\t%d\t%s\n",
+			summary.Host,
+			strings.Join(ports, ","),
+			summary.Requests,
+			summary.Denied,
+			summary.BytesSent,
+			summary.BytesReceived,
+			summary.LastSeen.Local().Format(time.DateTime),
+		); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Failed to write egress summary: %v\n", err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Failed to flush output: %v\n", err)
+	}
+}
+
+func formatEgressPort(port int) string {
+	if port == 0 {
+		return "-"
+	}
+	return strconv.Itoa(port)
+}
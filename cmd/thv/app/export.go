@@ -1,31 +1,43 @@
 package app
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 
 	"github.com/spf13/cobra"
 
 	"github.com/stacklok/toolhive/pkg/export"
+	"github.com/stacklok/toolhive/pkg/groups"
 	"github.com/stacklok/toolhive/pkg/runner"
+	"github.com/stacklok/toolhive/pkg/workloads"
 )
 
-var exportFormat string
+var (
+	exportFormat string
+	exportGroup  string
+)
 
 func newExportCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "export <workload name> <path>",
+		Use:   "export [workload name] <path>",
 		Short: "Export a workload's run configuration to a file",
 		Long: `Export a workload's run configuration to a file for sharing or backup.
 
 The exported configuration can be used with 'thv run --from-config <path>' to recreate
-the same workload with identical settings.
+the same workload with identical settings. It includes the defaults of the group of the
+workload that were merged into its configuration when it was run.
 
 You can export in different formats:
 - json: Export as RunConfig JSON (default, can be used with 'thv run --from-config')
 - k8s: Export as Kubernetes MCPServer resource YAML
 
+With --group, the run configurations of all the workloads of a group are exported with
+the defaults of the group to a single bundle file, which can be imported on another
+machine with 'thv import <path>'.
+
 Examples:
 
 	# Export a workload configuration to a JSON file
@@ -35,43 +47,53 @@ Examples:
 	thv export my-server ./my-server.yaml --format k8s
 
 	# Export to a specific directory
-	thv export github-mcp /tmp/configs/github-config.json`,
-		Args: cobra.ExactArgs(2),
-		RunE: exportCmdFunc,
+	thv export github-mcp /tmp/configs/github-config.json
+
+	# Export all the workloads of a group
+	thv export --group team-tools ./team-tools.json`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if exportGroup != "" {
+				return cobra.ExactArgs(1)(cmd, args)
+			}
+			return cobra.ExactArgs(2)(cmd, args)
+		},
+		PreRunE: validateGroupFlag(),
+		RunE:    exportCmdFunc,
 	}
 
 	cmd.Flags().StringVar(&exportFormat, "format", "json", "Export format: json or k8s")
+	cmd.Flags().StringVar(&exportGroup, "group", "", "Export all the workloads of a group to a bundle (json format only)")
 
 	return cmd
 }
 
 func exportCmdFunc(cmd *cobra.Command, args []string) error {
 	ctx := cmd.Context()
-	workloadName := args[0]
-	outputPath := args[1]
 
 	// Validate format
 	if exportFormat != "json" && exportFormat != "k8s" {
 		return fmt.Errorf("invalid format '%s': must be 'json' or 'k8s'", exportFormat)
 	}
 
+	if exportGroup != "" {
+		if exportFormat != "json" {
+			return fmt.Errorf("groups can only be exported in the json format")
+		}
+		return exportGroupBundle(ctx, exportGroup, args[0])
+	}
+
+	workloadName := args[0]
+	outputPath := args[1]
+
 	// Load the saved run configuration
 	runConfig, err := runner.LoadState(ctx, workloadName)
 	if err != nil {
 		return fmt.Errorf("failed to load run configuration for workload '%s': %w", workloadName, err)
 	}
 
-	// Ensure the output directory exists
-	outputDir := filepath.Dir(outputPath)
-	if err := os.MkdirAll(outputDir, 0750); err != nil {
-		return fmt.Errorf("failed to create output directory: %w", err)
-	}
-
-	// Create the output file
-	// #nosec G304 - outputPath is provided by the user as a command line argument for export functionality
-	outputFile, err := os.OpenFile(outputPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	outputFile, err := createExportFile(outputPath)
 	if err != nil {
-		return fmt.Errorf("failed to create output file: %w", err)
+		return err
 	}
 	defer func() {
 		// Non-fatal: file cleanup failure after successful write
@@ -101,3 +123,70 @@ func exportCmdFunc(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// exportGroupBundle exports the defaults and the run configurations of the workloads of a group
+func exportGroupBundle(ctx context.Context, groupName, outputPath string) error {
+	groupManager, err := groups.NewManager()
+	if err != nil {
+		return fmt.Errorf("failed to create group manager: %w", err)
+	}
+	group, err := groupManager.Get(ctx, groupName)
+	if err != nil {
+		return fmt.Errorf("failed to get group '%s': %w", groupName, err)
+	}
+
+	workloadManager, err := workloads.NewManager(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create workload manager: %w", err)
+	}
+	workloadNames, err := workloadManager.ListWorkloadsInGroup(ctx, groupName)
+	if err != nil {
+		return fmt.Errorf("failed to list workloads in group '%s': %w", groupName, err)
+	}
+	sort.Strings(workloadNames)
+
+	bundle := &export.Bundle{
+		Group:     groupName,
+		Defaults:  group.Defaults,
+		Workloads: make([]*runner.RunConfig, 0, len(workloadNames)),
+	}
+	for _, workloadName := range workloadNames {
+		runConfig, err := runner.LoadState(ctx, workloadName)
+		if err != nil {
+			return fmt.Errorf("failed to load run configuration for workload '%s': %w", workloadName, err)
+		}
+		bundle.Workloads = append(bundle.Workloads, runConfig)
+	}
+
+	outputFile, err := createExportFile(outputPath)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// Non-fatal: file cleanup failure after successful write
+		_ = outputFile.Close()
+	}()
+
+	if err := export.WriteBundle(bundle, outputFile); err != nil {
+		return fmt.Errorf("failed to write bundle to file: %w", err)
+	}
+	fmt.Printf("Successfully exported %d workloads of group '%s' to '%s'\n", len(bundle.Workloads), groupName, outputPath)
+	return nil
+}
+
+// createExportFile creates the output file of an export and its directory
+func createExportFile(outputPath string) (*os.File, error) {
+	// Ensure the output directory exists
+	outputDir := filepath.Dir(outputPath)
+	if err := os.MkdirAll(outputDir, 0750); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	// Create the output file
+	// #nosec G304 - outputPath is provided by the user as a command line argument for export functionality
+	outputFile, err := os.OpenFile(outputPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create output file: %w", err)
+	}
+	return outputFile, nil
+}
@@ -0,0 +1,331 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/spf13/cobra"
+
+	"github.com/stacklok/toolhive/pkg/audit"
+	"github.com/stacklok/toolhive/pkg/auth"
+	"github.com/stacklok/toolhive/pkg/authz"
+	"github.com/stacklok/toolhive/pkg/environment"
+	"github.com/stacklok/toolhive/pkg/groups"
+	"github.com/stacklok/toolhive/pkg/permissions"
+	"github.com/stacklok/toolhive/pkg/secrets"
+)
+
+var groupDefaultsCmd = &cobra.Command{
+	Use:   "defaults",
+	Short: "Manage the defaults shared by the MCP servers of a group",
+	Long: `Manage the defaults shared by the MCP servers of a group.
+
+Group defaults are settings merged into the configuration of each MCP server run
+in the group, such as environment variables, secrets, a permission profile and the
+OIDC, authorization and audit configurations. The settings of an MCP server take
+precedence over the group defaults:
+
+- Environment variables and secrets of the server override the group ones with the
+  same name or target.
+- The permission profile and the OIDC, authorization and audit configurations of the
+  group only apply to servers that do not set their own.
+
+Group defaults are merged when an MCP server is run in the group, so they apply to
+MCP servers run after they are set. 'thv export' shows the merged configuration.`,
+}
+
+var groupDefaultsShowCmd = &cobra.Command{
+	Use:     "show [group-name]",
+	Short:   "Show the defaults of a group",
+	Long:    `Show the defaults of a group as JSON.`,
+	Args:    cobra.ExactArgs(1),
+	PreRunE: validateGroupArg(),
+	RunE:    groupDefaultsShowCmdFunc,
+}
+
+var groupDefaultsSetCmd = &cobra.Command{
+	Use:   "set [group-name]",
+	Short: "Set defaults of a group",
+	Long: `Set defaults of a group. Only the defaults given as flags are changed.
+
+Examples:
+  # Share an environment variable and a secret between the servers of a group
+  thv group defaults set security-tools --env LOG_LEVEL=info --secret github-token,target=GITHUB_TOKEN
+
+  # Restrict the outbound network of the servers of a group
+  thv group defaults set security-tools --permission-profile ./egress-profile.json
+
+  # Require OIDC authentication and audit the servers of a group
+  thv group defaults set security-tools --oidc-issuer https://auth.example.com --oidc-audience tools --enable-audit`,
+	Args:    cobra.ExactArgs(1),
+	PreRunE: validateGroupArg(),
+	RunE:    groupDefaultsSetCmdFunc,
+}
+
+var groupDefaultsUnsetCmd = &cobra.Command{
+	Use:   "unset [group-name]",
+	Short: "Remove defaults of a group",
+	Long: `Remove defaults of a group. Only the defaults given as flags are removed, use --all to remove all of them.
+
+Examples:
+  # Remove an environment variable and a secret
+  thv group defaults unset security-tools --env LOG_LEVEL --secret github-token
+
+  # Remove all the defaults of a group
+  thv group defaults unset security-tools --all`,
+	Args:    cobra.ExactArgs(1),
+	PreRunE: validateGroupArg(),
+	RunE:    groupDefaultsUnsetCmdFunc,
+}
+
+var (
+	groupDefaultsEnv               []string
+	groupDefaultsSecrets           []string
+	groupDefaultsPermissionProfile string
+	groupDefaultsOIDCIssuer        string
+	groupDefaultsOIDCAudience      string
+	groupDefaultsOIDCJwksURL       string
+	groupDefaultsOIDCClientID      string
+	groupDefaultsAuthzConfig       string
+	groupDefaultsAuditConfig       string
+	groupDefaultsEnableAudit       bool
+
+	groupDefaultsUnsetPermissionProfile bool
+	groupDefaultsUnsetOIDC              bool
+	groupDefaultsUnsetAuthz             bool
+	groupDefaultsUnsetAudit             bool
+	groupDefaultsUnsetAll               bool
+)
+
+func groupDefaultsShowCmdFunc(cmd *cobra.Command, args []string) error {
+	group, err := getGroup(cmd, args[0])
+	if err != nil {
+		return err
+	}
+
+	defaults := group.Defaults
+	if defaults == nil {
+		defaults = &groups.Defaults{}
+	}
+
+	jsonData, err := json.MarshalIndent(defaults, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal group defaults: %w", err)
+	}
+	fmt.Println(string(jsonData))
+	return nil
+}
+
+func groupDefaultsSetCmdFunc(cmd *cobra.Command, args []string) error {
+	groupName := args[0]
+
+	group, err := getGroup(cmd, groupName)
+	if err != nil {
+		return err
+	}
+	defaults := group.Defaults
+	if defaults == nil {
+		defaults = &groups.Defaults{}
+	}
+
+	if err := setGroupDefaultsFromFlags(defaults); err != nil {
+		return err
+	}
+
+	return saveGroupDefaults(cmd, groupName, defaults)
+}
+
+// setGroupDefaultsFromFlags sets the defaults given as flags of the set command
+func setGroupDefaultsFromFlags(defaults *groups.Defaults) error {
+	envVars, err := environment.ParseEnvironmentVariables(groupDefaultsEnv)
+	if err != nil {
+		return fmt.Errorf("failed to parse environment variables: %w", err)
+	}
+	if len(envVars) > 0 && defaults.EnvVars == nil {
+		defaults.EnvVars = make(map[string]string, len(envVars))
+	}
+	for name, value := range envVars {
+		defaults.EnvVars[name] = value
+	}
+
+	// A secret replaces the secret with the same target
+	for _, secret := range groupDefaultsSecrets {
+		parameter, err := secrets.ParseSecretParameter(secret)
+		if err != nil {
+			return fmt.Errorf("invalid secret: %w", err)
+		}
+		defaults.Secrets = slices.DeleteFunc(defaults.Secrets, func(existing string) bool {
+			existingParameter, err := secrets.ParseSecretParameter(existing)
+			return err == nil && existingParameter.Target == parameter.Target
+		})
+		defaults.Secrets = append(defaults.Secrets, secret)
+	}
+
+	if groupDefaultsPermissionProfile != "" {
+		profile, err := loadGroupDefaultsPermissionProfile(groupDefaultsPermissionProfile)
+		if err != nil {
+			return err
+		}
+		defaults.PermissionProfile = profile
+	}
+
+	if groupDefaultsOIDCIssuer != "" || groupDefaultsOIDCAudience != "" ||
+		groupDefaultsOIDCJwksURL != "" || groupDefaultsOIDCClientID != "" {
+		defaults.OIDCConfig = &auth.TokenValidatorConfig{
+			Issuer:   groupDefaultsOIDCIssuer,
+			Audience: groupDefaultsOIDCAudience,
+			JWKSURL:  groupDefaultsOIDCJwksURL,
+			ClientID: groupDefaultsOIDCClientID,
+		}
+	}
+
+	if groupDefaultsAuthzConfig != "" {
+		authzConfig, err := authz.LoadConfig(groupDefaultsAuthzConfig)
+		if err != nil {
+			return fmt.Errorf("failed to load authorization configuration: %w", err)
+		}
+		defaults.AuthzConfig = authzConfig
+	}
+
+	if groupDefaultsAuditConfig != "" {
+		auditConfig, err := audit.LoadFromFile(groupDefaultsAuditConfig)
+		if err != nil {
+			return fmt.Errorf("failed to load audit configuration: %w", err)
+		}
+		defaults.AuditConfig = auditConfig
+	} else if groupDefaultsEnableAudit {
+		defaults.AuditConfig = audit.DefaultConfig()
+	}
+
+	return nil
+}
+
+// loadGroupDefaultsPermissionProfile loads a built-in permission profile by name or a profile from a file
+func loadGroupDefaultsPermissionProfile(nameOrPath string) (*permissions.Profile, error) {
+	switch nameOrPath {
+	case permissions.ProfileNone:
+		return permissions.BuiltinNoneProfile(), nil
+	case permissions.ProfileNetwork:
+		return permissions.BuiltinNetworkProfile(), nil
+	default:
+		profile, err := permissions.FromFile(nameOrPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load permission profile: %w", err)
+		}
+		return profile, nil
+	}
+}
+
+func groupDefaultsUnsetCmdFunc(cmd *cobra.Command, args []string) error {
+	groupName := args[0]
+
+	if groupDefaultsUnsetAll {
+		return saveGroupDefaults(cmd, groupName, nil)
+	}
+
+	group, err := getGroup(cmd, groupName)
+	if err != nil {
+		return err
+	}
+	defaults := group.Defaults
+	if defaults == nil {
+		fmt.Printf("Group '%s' has no defaults.\n", groupName)
+		return nil
+	}
+
+	for _, name := range groupDefaultsEnv {
+		delete(defaults.EnvVars, name)
+	}
+	for _, name := range groupDefaultsSecrets {
+		defaults.Secrets = slices.DeleteFunc(defaults.Secrets, func(existing string) bool {
+			parameter, err := secrets.ParseSecretParameter(existing)
+			return err == nil && parameter.Name == name
+		})
+	}
+	if groupDefaultsUnsetPermissionProfile {
+		defaults.PermissionProfile = nil
+	}
+	if groupDefaultsUnsetOIDC {
+		defaults.OIDCConfig = nil
+	}
+	if groupDefaultsUnsetAuthz {
+		defaults.AuthzConfig = nil
+	}
+	if groupDefaultsUnsetAudit {
+		defaults.AuditConfig = nil
+	}
+
+	return saveGroupDefaults(cmd, groupName, defaults)
+}
+
+// getGroup returns the group with the given name
+func getGroup(cmd *cobra.Command, groupName string) (*groups.Group, error) {
+	ctx := cmd.Context()
+
+	manager, err := groups.NewManager()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create group manager: %w", err)
+	}
+
+	exists, err := manager.Exists(ctx, groupName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check if group exists: %w", err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("group '%s' does not exist. Hint: use 'thv group list' to see available groups", groupName)
+	}
+
+	return manager.Get(ctx, groupName)
+}
+
+// saveGroupDefaults replaces the defaults of a group
+func saveGroupDefaults(cmd *cobra.Command, groupName string, defaults *groups.Defaults) error {
+	manager, err := groups.NewManager()
+	if err != nil {
+		return fmt.Errorf("failed to create group manager: %w", err)
+	}
+
+	if err := manager.SetDefaults(cmd.Context(), groupName, defaults); err != nil {
+		return fmt.Errorf("failed to set defaults of group '%s': %w", groupName, err)
+	}
+
+	fmt.Printf("Defaults of group '%s' updated successfully.\n", groupName)
+	return nil
+}
+
+func init() {
+	groupCmd.AddCommand(groupDefaultsCmd)
+	groupDefaultsCmd.AddCommand(groupDefaultsShowCmd)
+	groupDefaultsCmd.AddCommand(groupDefaultsSetCmd)
+	groupDefaultsCmd.AddCommand(groupDefaultsUnsetCmd)
+
+	groupDefaultsSetCmd.Flags().StringArrayVar(&groupDefaultsEnv, "env", []string{},
+		"Environment variables to set in the MCP servers of the group (format: KEY=VALUE)")
+	groupDefaultsSetCmd.Flags().StringArrayVar(&groupDefaultsSecrets, "secret", []string{},
+		"Secrets to set as environment variables in the MCP servers of the group (format: NAME,target=TARGET)")
+	groupDefaultsSetCmd.Flags().StringVar(&groupDefaultsPermissionProfile, "permission-profile", "",
+		"Permission profile of the MCP servers of the group (none, network, or path to JSON file)")
+	groupDefaultsSetCmd.Flags().StringVar(&groupDefaultsOIDCIssuer, "oidc-issuer", "",
+		"OIDC issuer URL (e.g., https://accounts.google.com)")
+	groupDefaultsSetCmd.Flags().StringVar(&groupDefaultsOIDCAudience, "oidc-audience", "", "Expected audience for the token")
+	groupDefaultsSetCmd.Flags().StringVar(&groupDefaultsOIDCJwksURL, "oidc-jwks-url", "", "URL to fetch the JWKS from")
+	groupDefaultsSetCmd.Flags().StringVar(&groupDefaultsOIDCClientID, "oidc-client-id", "", "OIDC client ID")
+	groupDefaultsSetCmd.Flags().StringVar(&groupDefaultsAuthzConfig, "authz-config", "",
+		"Path to the authorization configuration file of the MCP servers of the group")
+	groupDefaultsSetCmd.Flags().StringVar(&groupDefaultsAuditConfig, "audit-config", "",
+		"Path to the audit configuration file of the MCP servers of the group")
+	groupDefaultsSetCmd.Flags().BoolVar(&groupDefaultsEnableAudit, "enable-audit", false,
+		"Enable audit logging with the default configuration in the MCP servers of the group")
+
+	groupDefaultsUnsetCmd.Flags().StringArrayVar(&groupDefaultsEnv, "env", []string{},
+		"Names of the environment variables to remove")
+	groupDefaultsUnsetCmd.Flags().StringArrayVar(&groupDefaultsSecrets, "secret", []string{},
+		"Names of the secrets to remove")
+	groupDefaultsUnsetCmd.Flags().BoolVar(&groupDefaultsUnsetPermissionProfile, "permission-profile", false,
+		"Remove the permission profile")
+	groupDefaultsUnsetCmd.Flags().BoolVar(&groupDefaultsUnsetOIDC, "oidc", false, "Remove the OIDC configuration")
+	groupDefaultsUnsetCmd.Flags().BoolVar(&groupDefaultsUnsetAuthz, "authz", false, "Remove the authorization configuration")
+	groupDefaultsUnsetCmd.Flags().BoolVar(&groupDefaultsUnsetAudit, "audit", false, "Remove the audit configuration")
+	groupDefaultsUnsetCmd.Flags().BoolVar(&groupDefaultsUnsetAll, "all", false, "Remove all the defaults of the group")
+}
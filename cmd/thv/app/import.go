@@ -0,0 +1,312 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/stacklok/toolhive/pkg/container/runtime"
+	"github.com/stacklok/toolhive/pkg/export"
+	"github.com/stacklok/toolhive/pkg/groups"
+	"github.com/stacklok/toolhive/pkg/networking"
+	"github.com/stacklok/toolhive/pkg/runner"
+	"github.com/stacklok/toolhive/pkg/secrets"
+	"github.com/stacklok/toolhive/pkg/workloads"
+)
+
+var (
+	importName  string
+	importGroup string
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import <path>",
+	Short: "Import a workload or a group from an exported file",
+	Long: `Recreate workloads from a file written by 'thv export'.
+
+The format of the file is detected from its content:
+- A RunConfig JSON, written by 'thv export <workload> <path>'
+- A Kubernetes MCPServer resource YAML, written by 'thv export --format k8s'
+- A group bundle JSON, written by 'thv export --group <group>'
+
+The schema version of the file must not be newer than the version supported by this
+ToolHive. The secrets referenced by the workloads must exist in the local secrets
+provider; when a secret is missing and the command runs in a terminal, its value is
+prompted for and stored in the provider.
+
+Importing a group bundle creates the group with the defaults of the bundle if it does
+not exist, then runs all the workloads of the bundle. A workload with the same name
+as an imported one must not already exist.
+
+Examples:
+
+	# Import a workload under another name
+	thv import ./github.json --name github-copy
+
+	# Import a Kubernetes MCPServer resource into a group
+	thv import ./fetch.yaml --group team-tools
+
+	# Import a group exported on another machine
+	thv import ./team-tools.json`,
+	Args:    cobra.ExactArgs(1),
+	PreRunE: validateGroupFlag(),
+	RunE:    importCmdFunc,
+}
+
+func init() {
+	importCmd.Flags().StringVar(&importName, "name", "",
+		"Name of the imported workload (defaults to the name in the file, not supported for bundles)")
+	importCmd.Flags().StringVar(&importGroup, "group", "",
+		"Group of the imported workloads (defaults to the group in the file)")
+}
+
+func importCmdFunc(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	path := args[0]
+
+	if runtime.IsKubernetesRuntime() {
+		return fmt.Errorf("import is not supported in Kubernetes")
+	}
+
+	// #nosec G304 - path is provided by the user as a command line argument for import functionality
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read file '%s': %w", path, err)
+	}
+
+	format, err := export.DetectFormat(data)
+	if err != nil {
+		return err
+	}
+	if format == export.FormatBundle && importName != "" {
+		return fmt.Errorf("--name cannot be used when importing a group bundle")
+	}
+
+	groupManager, err := groups.NewManager()
+	if err != nil {
+		return fmt.Errorf("failed to create group manager: %w", err)
+	}
+	workloadManager, err := workloads.NewManager(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create workload manager: %w", err)
+	}
+
+	configs, bundle, err := readImportFile(ctx, format, data)
+	if err != nil {
+		return err
+	}
+
+	// Check all the workloads before changing anything, so that a failed import leaves no trace
+	for _, config := range configs {
+		if err := prepareImportedConfig(config); err != nil {
+			return err
+		}
+		exists, err := workloadManager.DoesWorkloadExist(ctx, config.Name)
+		if err != nil {
+			return fmt.Errorf("failed to check if workload '%s' exists: %w", config.Name, err)
+		}
+		if exists {
+			return fmt.Errorf("workload '%s' already exists, use --name to import it under another name", config.Name)
+		}
+	}
+
+	if err := resolveImportedSecrets(ctx, configs); err != nil {
+		return err
+	}
+
+	if bundle != nil {
+		if err := ensureImportedGroup(ctx, groupManager, configs[0].Group, bundle.Defaults); err != nil {
+			return err
+		}
+	} else if configs[0].Group != "" {
+		if err := ensureImportedGroup(ctx, groupManager, configs[0].Group, nil); err != nil {
+			return err
+		}
+	}
+
+	for _, config := range configs {
+		// Save the run config before running, so that imported configs are persisted like normal runs
+		if err := config.SaveState(ctx); err != nil {
+			return fmt.Errorf("failed to save run configuration of '%s': %w", config.Name, err)
+		}
+		if err := workloadManager.RunWorkloadDetached(ctx, config); err != nil {
+			return fmt.Errorf("failed to run workload '%s': %w", config.Name, err)
+		}
+		fmt.Printf("Imported workload '%s' in group '%s'\n", config.Name, config.Group)
+	}
+	return nil
+}
+
+// readImportFile reads the run configurations of an exported file, and the bundle if the file is one
+func readImportFile(ctx context.Context, format export.Format, data []byte) ([]*runner.RunConfig, *export.Bundle, error) {
+	switch format {
+	case export.FormatJSON:
+		config, err := export.ReadRunConfig(bytes.NewReader(data))
+		if err != nil {
+			return nil, nil, err
+		}
+		return []*runner.RunConfig{config}, nil, nil
+	case export.FormatK8s:
+		mcpServer, err := export.ReadK8sManifest(bytes.NewReader(data))
+		if err != nil {
+			return nil, nil, err
+		}
+		// The resource is converted like 'thv run' builds a configuration, with the group defaults
+		group := importGroup
+		if group == "" {
+			group = mcpServer.Spec.GroupRef
+		}
+		if group == "" {
+			group = groups.DefaultGroup
+		}
+		defaults, err := getGroupDefaults(ctx, group)
+		if err != nil {
+			return nil, nil, err
+		}
+		config, err := export.K8sManifestToRunConfig(ctx, mcpServer, runner.WithGroup(group), runner.WithGroupDefaults(defaults))
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to convert MCPServer resource: %w", err)
+		}
+		return []*runner.RunConfig{config}, nil, nil
+	case export.FormatBundle:
+		bundle, err := export.ReadBundle(bytes.NewReader(data))
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(bundle.Workloads) == 0 {
+			return nil, nil, fmt.Errorf("bundle of group '%s' has no workloads", bundle.Group)
+		}
+		group := importGroup
+		if group == "" {
+			group = bundle.Group
+		}
+		for _, config := range bundle.Workloads {
+			config.Group = group
+		}
+		return bundle.Workloads, bundle, nil
+	}
+	return nil, nil, fmt.Errorf("unsupported format %q", format)
+}
+
+// prepareImportedConfig applies the command line overrides to an imported configuration and
+// selects its proxy port on this machine
+func prepareImportedConfig(config *runner.RunConfig) error {
+	if importName != "" && importName != config.Name {
+		config.Name = importName
+		config.ContainerName = ""
+		config.BaseName = ""
+		config.WithContainerName()
+	}
+	if importGroup != "" {
+		config.Group = importGroup
+	}
+	if config.Name == "" {
+		return fmt.Errorf("imported workload has no name, use --name to set one")
+	}
+
+	// The exported port may be in use on this machine
+	port, err := networking.FindOrUsePort(config.Port)
+	if err != nil {
+		return fmt.Errorf("failed to find a port for workload '%s': %w", config.Name, err)
+	}
+	if config.Port != 0 && port != config.Port {
+		fmt.Printf("Port %d of workload '%s' is not available, using port %d\n", config.Port, config.Name, port)
+	}
+	config.Port = port
+
+	// Refresh the labels, which hold the name, group and port of the workload
+	config.WithStandardLabels()
+	return nil
+}
+
+// ensureImportedGroup creates the group of imported workloads if it does not exist.
+// The defaults are only set on a new group, the defaults of an existing group are kept.
+func ensureImportedGroup(ctx context.Context, groupManager groups.Manager, name string, defaults *groups.Defaults) error {
+	exists, err := groupManager.Exists(ctx, name)
+	if err != nil {
+		return fmt.Errorf("failed to check if group exists: %w", err)
+	}
+	if exists {
+		if !defaults.IsEmpty() {
+			fmt.Printf("Group '%s' already exists, keeping its defaults\n", name)
+		}
+		return nil
+	}
+
+	if err := groupManager.Create(ctx, name); err != nil {
+		return fmt.Errorf("failed to create group '%s': %w", name, err)
+	}
+	if !defaults.IsEmpty() {
+		if err := groupManager.SetDefaults(ctx, name, defaults); err != nil {
+			return fmt.Errorf("failed to set defaults of group '%s': %w", name, err)
+		}
+	}
+	fmt.Printf("Created group '%s'\n", name)
+	return nil
+}
+
+// resolveImportedSecrets checks that the secrets referenced by the imported workloads exist in
+// the local secrets provider, and prompts for the missing ones when running in a terminal
+func resolveImportedSecrets(ctx context.Context, configs []*runner.RunConfig) error {
+	var names []string
+	seen := make(map[string]bool)
+	for _, config := range configs {
+		for _, parameter := range config.Secrets {
+			secret, err := secrets.ParseSecretParameter(parameter)
+			if err != nil {
+				return fmt.Errorf("invalid secret of workload '%s': %w", config.Name, err)
+			}
+			if !seen[secret.Name] {
+				seen[secret.Name] = true
+				names = append(names, secret.Name)
+			}
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+
+	manager, err := getSecretsManager()
+	if err != nil {
+		if errors.Is(err, secrets.ErrSecretsNotSetup) {
+			return fmt.Errorf("the imported workloads use secrets, run 'thv secret setup' first")
+		}
+		return err
+	}
+
+	stat, _ := os.Stdin.Stat()
+	interactive := stat != nil && (stat.Mode()&os.ModeCharDevice) != 0 && manager.Capabilities().CanWrite
+
+	for _, name := range names {
+		_, err := manager.GetSecret(ctx, name)
+		if err == nil {
+			continue
+		}
+		if !secrets.IsNotFoundError(err) {
+			return fmt.Errorf("failed to get secret '%s': %w", name, err)
+		}
+		if !interactive {
+			return fmt.Errorf("secret '%s' does not exist, create it with 'thv secret set %s'", name, name)
+		}
+
+		fmt.Printf("Enter the value of secret '%s' (input will be hidden): ", name)
+		value, err := term.ReadPassword(int(syscall.Stdin))
+		fmt.Println("") // Add a newline after the hidden input
+		if err != nil {
+			return fmt.Errorf("error reading secret from terminal: %w", err)
+		}
+		if len(value) == 0 {
+			return fmt.Errorf("secret '%s' cannot be empty", name)
+		}
+		if err := manager.SetSecret(ctx, name, string(value)); err != nil {
+			return fmt.Errorf("failed to set secret '%s': %w", name, err)
+		}
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package app
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -12,6 +13,7 @@ import (
 	"github.com/stacklok/toolhive/pkg/core"
 	"github.com/stacklok/toolhive/pkg/logger"
 	"github.com/stacklok/toolhive/pkg/workloads"
+	"github.com/stacklok/toolhive/pkg/workloads/imageupdates"
 )
 
 var listCmd = &cobra.Command{
@@ -34,7 +36,10 @@ Examples:
   thv list --group production
 
   # List servers with specific labels
-  thv list --label env=dev --label team=backend`,
+  thv list --label env=dev --label team=backend
+
+  # Check the registries for newer images before listing
+  thv list --check-updates`,
 	RunE: listCmdFunc,
 }
 
@@ -43,6 +48,7 @@ var (
 	listFormat      string
 	listLabelFilter []string
 	listGroupFilter string
+	listCheckUpdate bool
 )
 
 func init() {
@@ -50,6 +56,8 @@ func init() {
 	AddFormatFlag(listCmd, &listFormat, FormatJSON, FormatText, "mcpservers")
 	listCmd.Flags().StringArrayVarP(&listLabelFilter, "label", "l", []string{}, "Filter workloads by labels (format: key=value)")
 	AddGroupFlag(listCmd, &listGroupFilter, false)
+	listCmd.Flags().BoolVar(&listCheckUpdate, "check-updates", false,
+		"Check the registries for newer images of the running workloads before listing")
 
 	listCmd.PreRunE = chainPreRunE(
 		validateGroupFlag(),
@@ -66,6 +74,10 @@ func listCmdFunc(cmd *cobra.Command, _ []string) error {
 		return fmt.Errorf("failed to create status manager: %w", err)
 	}
 
+	if listCheckUpdate {
+		checkImageUpdates(ctx)
+	}
+
 	workloadList, err := manager.ListWorkloads(ctx, listAll, listLabelFilter...)
 	if err != nil {
 		return fmt.Errorf("failed to list workloads: %w", err)
@@ -100,6 +112,19 @@ func listCmdFunc(cmd *cobra.Command, _ []string) error {
 	}
 }
 
+// checkImageUpdates checks the images of the running workloads, so that the listed workloads
+// have up to date image update information. Failed checks do not prevent listing.
+func checkImageUpdates(ctx context.Context) {
+	checker, err := imageupdates.NewChecker(ctx)
+	if err != nil {
+		logger.Warnf("Failed to check for image updates: %v", err)
+		return
+	}
+	if _, err := checker.CheckWorkloads(ctx); err != nil {
+		logger.Warnf("Failed to check some workloads for image updates: %v", err)
+	}
+}
+
 // printJSONOutput prints workload information in JSON format
 func printJSONOutput(workloadList []core.Workload) error {
 	// Ensure we have a non-nil slice to avoid null in JSON output
@@ -155,7 +180,7 @@ func printTextOutput(workloadList []core.Workload) {
 
 	// Create a tabwriter for pretty output
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
-	if _, err := fmt.Fprintln(w, "NAME\tPACKAGE\tSTATUS\tURL\tPORT\tGROUP\tCREATED"); err != nil {
+	if _, err := fmt.Fprintln(w, "NAME\tPACKAGE\tSTATUS\tRESTARTS\tURL\tPORT\tGROUP\tCREATED"); err != nil {
 		logger.Warnf("Failed to write output header: %v", err)
 		return
 	}
@@ -168,11 +193,18 @@ func printTextOutput(workloadList []core.Workload) {
 			status = "⚠️  " + status
 		}
 
+		// Mark workloads with a newer image in the registry
+		pkg := c.Package
+		if c.ImageUpdate != nil && c.ImageUpdate.Available {
+			pkg += " (update available)"
+		}
+
 		// Print workload information
-		if _, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%s\t%s\n",
+		if _, err := fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\t%d\t%s\t%s\n",
 			c.Name,
-			c.Package,
+			pkg,
 			status,
+			c.Restarts,
 			c.URL,
 			c.Port,
 			c.Group,
@@ -93,6 +93,16 @@ You can specify the network mode for the container using the --network flag:
 
 The --network flag accepts any Docker-compatible network mode.
 
+#### Learning a permission profile
+
+With --permission-profile learn, the server runs with unrestricted outbound network
+access and writable mounts, while ToolHive records the hosts, ports and mounted paths
+it uses. When the server stops, a least-privilege profile is written to the ToolHive
+data directory and its differences from the registry profile are logged:
+
+- Learn: $ thv run --permission-profile learn <server>
+- Apply: $ thv run --permission-profile <learned-profile.json> <server>
+
 Examples:
   # Run a server from the registry
   thv run filesystem
@@ -10,16 +10,21 @@ import (
 	"github.com/stacklok/toolhive/pkg/auth"
 	"github.com/stacklok/toolhive/pkg/auth/remote"
 	authsecrets "github.com/stacklok/toolhive/pkg/auth/secrets"
+	"github.com/stacklok/toolhive/pkg/authserver"
 	"github.com/stacklok/toolhive/pkg/authz"
 	"github.com/stacklok/toolhive/pkg/cli"
 	cfg "github.com/stacklok/toolhive/pkg/config"
 	"github.com/stacklok/toolhive/pkg/container"
 	"github.com/stacklok/toolhive/pkg/container/runtime"
 	"github.com/stacklok/toolhive/pkg/environment"
+	"github.com/stacklok/toolhive/pkg/groups"
 	"github.com/stacklok/toolhive/pkg/ignore"
 	"github.com/stacklok/toolhive/pkg/logger"
+	"github.com/stacklok/toolhive/pkg/mcp"
 	"github.com/stacklok/toolhive/pkg/networking"
+	"github.com/stacklok/toolhive/pkg/permissions"
 	"github.com/stacklok/toolhive/pkg/process"
+	"github.com/stacklok/toolhive/pkg/ratelimit"
 	regtypes "github.com/stacklok/toolhive/pkg/registry/registry"
 	"github.com/stacklok/toolhive/pkg/runner"
 	"github.com/stacklok/toolhive/pkg/runner/retriever"
@@ -72,6 +77,9 @@ type RunFlags struct {
 	// OAuth discovery configuration
 	ResourceURL string
 
+	// Embedded authorization server configuration
+	AuthServerConfig string
+
 	// Telemetry configuration
 	OtelEndpoint                    string
 	OtelServiceName                 string
@@ -96,6 +104,19 @@ type RunFlags struct {
 	// Network mode
 	Network string
 
+	// Resource limits
+	CPUShares int64
+	CPUs      string
+	Memory    string
+	PidsLimit int64
+	TmpfsSize string
+
+	// Restart policy
+	RestartPolicy     string
+	RestartMaxRetries int
+	RestartBackoff    string
+	RestartMaxBackoff string
+
 	// Labels
 	Labels []string
 
@@ -107,6 +128,14 @@ type RunFlags struct {
 	// Tools override file
 	ToolsOverride string
 
+	// Response caching
+	EnableResponseCache bool
+	ResponseCacheTTL    string
+	ResponseCacheTools  []string
+
+	// Rate limiting configuration file
+	RateLimitConfig string
+
 	// Configuration import
 	FromConfig string
 
@@ -146,7 +175,7 @@ func AddRunFlags(cmd *cobra.Command, config *RunFlags) {
 		&config.PermissionProfile,
 		"permission-profile",
 		"",
-		"Permission profile to use (none, network, or path to JSON file)",
+		"Permission profile to use (none, network, learn, or path to JSON file)",
 	)
 	cmd.Flags().StringArrayVarP(
 		&config.Env,
@@ -192,6 +221,9 @@ func AddRunFlags(cmd *cobra.Command, config *RunFlags) {
 	// OAuth discovery configuration
 	cmd.Flags().StringVar(&config.ResourceURL, "resource-url", "",
 		"Explicit resource URL for OAuth discovery endpoint (RFC 9728)")
+	cmd.Flags().StringVar(&config.AuthServerConfig, "authserver-config", "",
+		"Path to the configuration file of an OAuth authorization server embedded in the proxy "+
+			"(only applicable to SSE and streamable HTTP transports)")
 
 	// OpenTelemetry flags updated per origin/main
 	cmd.Flags().StringVar(&config.OtelEndpoint, "otel-endpoint", "",
@@ -222,6 +254,21 @@ func AddRunFlags(cmd *cobra.Command, config *RunFlags) {
 		"Path prefix to prepend to SSE endpoint URLs (e.g., /playwright)")
 	cmd.Flags().StringVar(&config.Network, "network", "",
 		"Connect the container to a network (e.g., 'host' for host networking)")
+	cmd.Flags().Int64Var(&config.CPUShares, "cpu-shares", 0,
+		"Relative CPU weight of the container (default 1024 in Docker; a CPU request in Kubernetes)")
+	cmd.Flags().StringVar(&config.CPUs, "cpus", "", "Maximum number of CPUs the container can use (e.g. 0.5, 2, 500m)")
+	cmd.Flags().StringVar(&config.Memory, "memory", "", "Maximum amount of memory the container can use (e.g. 512Mi, 2Gi)")
+	cmd.Flags().Int64Var(&config.PidsLimit, "pids-limit", 0, "Maximum number of processes the container can run")
+	cmd.Flags().StringVar(&config.TmpfsSize, "tmpfs-size", "",
+		"Mount a memory-backed /tmp of the given size in the container (e.g. 64Mi)")
+	cmd.Flags().StringVar(&config.RestartPolicy, "restart", string(runner.RestartPolicyAlways),
+		"Restart policy when the MCP server exits (never, on-failure, always)")
+	cmd.Flags().IntVar(&config.RestartMaxRetries, "restart-max-retries", runner.DefaultRestartMaxRetries,
+		"Maximum number of consecutive restarts (0 for unlimited)")
+	cmd.Flags().StringVar(&config.RestartBackoff, "restart-backoff", runner.DefaultRestartBackoff.String(),
+		"Delay before the first restart; doubles on each consecutive restart")
+	cmd.Flags().StringVar(&config.RestartMaxBackoff, "restart-max-backoff", runner.DefaultRestartMaxBackoff.String(),
+		"Maximum delay between restarts")
 	cmd.Flags().StringArrayVarP(&config.Labels, "label", "l", []string{}, "Set labels on the container (format: key=value)")
 	cmd.Flags().BoolVarP(&config.Foreground, "foreground", "f", false, "Run in foreground mode (block until container exits)")
 	cmd.Flags().StringArrayVar(
@@ -236,6 +283,14 @@ func AddRunFlags(cmd *cobra.Command, config *RunFlags) {
 		"",
 		"Path to a JSON file containing overrides for MCP server tools names and descriptions",
 	)
+	cmd.Flags().BoolVar(&config.EnableResponseCache, "response-cache", false,
+		"Cache responses to idempotent MCP requests (tools/list, prompts/get, resources/read, ...)")
+	cmd.Flags().StringVar(&config.ResponseCacheTTL, "response-cache-ttl", "",
+		"How long responses are served from the cache (e.g. 30s, 5m; default 5m)")
+	cmd.Flags().StringArrayVar(&config.ResponseCacheTools, "response-cache-tools", nil,
+		"Tools whose tools/call responses may be cached; only tools annotated readOnlyHint are cached")
+	cmd.Flags().StringVar(&config.RateLimitConfig, "rate-limit-config", "",
+		"Path to a YAML or JSON file with token bucket limits for MCP requests")
 	cmd.Flags().StringVar(&config.FromConfig, "from-config", "", "Load configuration from exported file")
 
 	// Environment file processing flags
@@ -328,6 +383,20 @@ func setupOIDCConfiguration(cmd *cobra.Command, runFlags *RunFlags) (*auth.Token
 		}
 	}
 
+	// Validate tokens issued by the embedded authorization server unless OIDC is configured explicitly
+	if runFlags.AuthServerConfig != "" && oidcIssuer == "" {
+		authServerConfig, err := authserver.LoadRunConfig(runFlags.AuthServerConfig)
+		if err != nil {
+			return nil, err
+		}
+		oidcConfig := authServerConfig.TokenValidatorConfig()
+		if runFlags.ResourceURL != "" {
+			oidcConfig.ResourceURL = runFlags.ResourceURL
+		}
+		oidcConfig.InsecureAllowHTTP = oidcConfig.InsecureAllowHTTP || runFlags.InsecureAllowHTTP
+		return oidcConfig, nil
+	}
+
 	return createOIDCConfig(oidcIssuer, oidcAudience, oidcJwksURL, oidcIntrospectionURL,
 		oidcClientID, oidcClientSecret, runFlags.ResourceURL, runFlags.JWKSAllowPrivateIP, oidcScopes), nil
 }
@@ -464,6 +533,8 @@ func buildRunnerConfig(
 		runner.WithTrustProxyHeaders(runFlags.TrustProxyHeaders),
 		runner.WithEndpointPrefix(runFlags.EndpointPrefix),
 		runner.WithNetworkMode(runFlags.Network),
+		runner.WithResourceLimits(getResourceLimitsFromRunFlags(runFlags)),
+		runner.WithRestartPolicy(getRestartPolicyFromRunFlags(runFlags)),
 		runner.WithK8sPodPatch(runFlags.K8sPodPatch),
 		runner.WithProxyMode(types.ProxyMode(runFlags.ProxyMode)),
 		runner.WithTransportAndPorts(transportType, runFlags.ProxyPort, runFlags.TargetPort),
@@ -493,9 +564,43 @@ func buildRunnerConfig(
 	}
 	opts = append(opts, additionalOpts...)
 
+	// Merge the defaults of the group of the workload
+	groupDefaults, err := getGroupDefaults(ctx, runFlags.Group)
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts, runner.WithGroupDefaults(groupDefaults))
+
 	return runner.NewRunConfigBuilder(ctx, imageMetadata, envVars, envVarValidator, opts...)
 }
 
+// getGroupDefaults returns the defaults of a group, or nil if the group has none
+func getGroupDefaults(ctx context.Context, groupName string) (*groups.Defaults, error) {
+	// Groups do not hold defaults in Kubernetes
+	if groupName == "" || runtime.IsKubernetesRuntime() {
+		return nil, nil
+	}
+
+	groupManager, err := groups.NewManager()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create group manager: %w", err)
+	}
+
+	exists, err := groupManager.Exists(ctx, groupName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check if group exists: %w", err)
+	}
+	if !exists {
+		return nil, nil
+	}
+
+	group, err := groupManager.Get(ctx, groupName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get group '%s': %w", groupName, err)
+	}
+	return group.Defaults, nil
+}
+
 // configureMiddlewareAndOptions configures middleware and additional runner options
 func configureMiddlewareAndOptions(
 	runFlags *RunFlags,
@@ -518,6 +623,14 @@ func configureMiddlewareAndOptions(
 		return nil, fmt.Errorf("invalid token exchange configuration: %w", err)
 	}
 
+	var rateLimitConfig *ratelimit.Config
+	if runFlags.RateLimitConfig != "" {
+		rateLimitConfig, err = ratelimit.LoadConfig(runFlags.RateLimitConfig)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Use computed serverName and transportType for correct telemetry labels
 	opts = append(opts, runner.WithToolsOverride(toolsOverride))
 	opts = append(
@@ -534,6 +647,8 @@ func configureMiddlewareAndOptions(
 			serverName,
 			transportType,
 			appConfig.DisableUsageMetrics,
+			getResponseCacheFromRunFlags(runFlags),
+			rateLimitConfig,
 		),
 	)
 
@@ -552,9 +667,19 @@ func configureMiddlewareAndOptions(
 		// Note: Path is already set via WithAuthzConfigPath above
 	}
 
+	// Load embedded authorization server config if path is provided
+	if runFlags.AuthServerConfig != "" {
+		authServerConfig, err := authserver.LoadRunConfig(runFlags.AuthServerConfig)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, runner.WithEmbeddedAuthServer(authServerConfig))
+	}
+
 	// Get OIDC and telemetry values for legacy configuration
 	oidcIssuer, oidcAudience, oidcJwksURL, oidcIntrospectionURL, oidcClientID, oidcClientSecret,
 		oidcScopes := extractOIDCValues(oidcConfig)
+	allowPrivateIP, insecureAllowHTTP := oidcNetworkOptions(runFlags, oidcConfig)
 	finalOtelEndpoint, finalOtelSamplingRate, finalOtelEnvironmentVariables := extractTelemetryValues(telemetryConfig)
 
 	// Set additional configurations that are still needed in old format for other parts of the system
@@ -562,7 +687,7 @@ func configureMiddlewareAndOptions(
 		runner.WithOIDCConfig(
 			oidcIssuer, oidcAudience, oidcJwksURL, oidcIntrospectionURL, oidcClientID, oidcClientSecret,
 			runFlags.ThvCABundle, runFlags.JWKSAuthTokenFile, runFlags.ResourceURL,
-			runFlags.JWKSAllowPrivateIP, runFlags.InsecureAllowHTTP, oidcScopes,
+			allowPrivateIP, insecureAllowHTTP, oidcScopes,
 		),
 		runner.WithTelemetryConfigFromFlags(finalOtelEndpoint, runFlags.OtelEnablePrometheusMetricsPath,
 			runFlags.OtelTracingEnabled, runFlags.OtelMetricsEnabled, runFlags.OtelServiceName,
@@ -581,6 +706,43 @@ func configureMiddlewareAndOptions(
 	return opts, nil
 }
 
+// getResponseCacheFromRunFlags returns the response cache configuration, or nil if caching is disabled.
+func getResponseCacheFromRunFlags(runFlags *RunFlags) *mcp.ResponseCacheConfig {
+	if !runFlags.EnableResponseCache {
+		return nil
+	}
+	return &mcp.ResponseCacheConfig{
+		TTL:   runFlags.ResponseCacheTTL,
+		Tools: runFlags.ResponseCacheTools,
+	}
+}
+
+// getResourceLimitsFromRunFlags returns the resource limits set on the command line.
+// They override the limits of the permission profile.
+func getResourceLimitsFromRunFlags(runFlags *RunFlags) *permissions.ResourceLimits {
+	return &permissions.ResourceLimits{
+		CPUShares: runFlags.CPUShares,
+		CPUs:      runFlags.CPUs,
+		Memory:    runFlags.Memory,
+		PidsLimit: runFlags.PidsLimit,
+		TmpfsSize: runFlags.TmpfsSize,
+	}
+}
+
+// getRestartPolicyFromRunFlags returns the restart policy set on the command line,
+// or nil if none is set so that the default restart policy applies.
+func getRestartPolicyFromRunFlags(runFlags *RunFlags) *runner.RestartPolicy {
+	if runFlags.RestartPolicy == "" {
+		return nil
+	}
+	return &runner.RestartPolicy{
+		Policy:         runner.RestartPolicyName(runFlags.RestartPolicy),
+		MaxRetries:     runFlags.RestartMaxRetries,
+		InitialBackoff: runFlags.RestartBackoff,
+		MaxBackoff:     runFlags.RestartMaxBackoff,
+	}
+}
+
 // configureRemoteAuth configures remote authentication options if applicable
 func configureRemoteAuth(runFlags *RunFlags, serverMetadata regtypes.ServerMetadata) ([]runner.RunConfigBuilderOption, error) {
 	var opts []runner.RunConfigBuilderOption
@@ -627,6 +789,18 @@ func extractOIDCValues(
 		config.ClientID, config.ClientSecret, config.Scopes
 }
 
+// oidcNetworkOptions returns whether OIDC endpoints may be reached on private IP addresses and over
+// plain HTTP. The flags are combined with what the OIDC configuration requires, such as the embedded
+// authorization server, whose signing keys the proxy fetches from itself.
+func oidcNetworkOptions(runFlags *RunFlags, oidcConfig *auth.TokenValidatorConfig) (allowPrivateIP, insecureAllowHTTP bool) {
+	allowPrivateIP, insecureAllowHTTP = runFlags.JWKSAllowPrivateIP, runFlags.InsecureAllowHTTP
+	if oidcConfig != nil {
+		allowPrivateIP = allowPrivateIP || oidcConfig.AllowPrivateIP
+		insecureAllowHTTP = insecureAllowHTTP || oidcConfig.InsecureAllowHTTP
+	}
+	return allowPrivateIP, insecureAllowHTTP
+}
+
 // extractTelemetryValues extracts telemetry values from the telemetry config for legacy configuration
 func extractTelemetryValues(config *telemetry.Config) (string, float64, []string) {
 	if config == nil {
@@ -354,3 +354,38 @@ func TestBuildRunnerConfig_TelemetryProcessing_Integration(t *testing.T) {
 	assert.Equal(t, false, finalInsecure, "Insecure setting should use runFlags value when not set via CLI")
 	assert.Equal(t, false, finalEnablePrometheusMetricsPath, "Enable Prometheus metrics path should use runFlags value when not set via CLI")
 }
+
+func TestSetupOIDCConfiguration_EmbeddedAuthServer(t *testing.T) {
+	t.Parallel()
+
+	configPath := filepath.Join(t.TempDir(), "authserver.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte(`issuer: http://localhost:8080
+allowed_audiences: ["http://localhost:8080/mcp"]
+upstream:
+  issuer: https://idp.example.com
+  client_id: toolhive
+`), 0600))
+
+	cmd := &cobra.Command{}
+	runFlags := &RunFlags{}
+	AddRunFlags(cmd, runFlags)
+	runFlags.AuthServerConfig = configPath
+
+	oidcConfig, err := setupOIDCConfiguration(cmd, runFlags)
+	require.NoError(t, err)
+	assert.Equal(t, "http://localhost:8080", oidcConfig.Issuer)
+	assert.True(t, oidcConfig.AllowPrivateIP)
+	assert.True(t, oidcConfig.InsecureAllowHTTP)
+
+	// The flags are left untouched, the embedded server's needs are derived from its configuration
+	assert.False(t, runFlags.JWKSAllowPrivateIP)
+	assert.False(t, runFlags.InsecureAllowHTTP)
+
+	allowPrivateIP, insecureAllowHTTP := oidcNetworkOptions(runFlags, oidcConfig)
+	assert.True(t, allowPrivateIP)
+	assert.True(t, insecureAllowHTTP)
+
+	allowPrivateIP, insecureAllowHTTP = oidcNetworkOptions(runFlags, nil)
+	assert.False(t, allowPrivateIP)
+	assert.False(t, insecureAllowHTTP)
+}
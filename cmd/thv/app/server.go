@@ -13,6 +13,7 @@ import (
 	"github.com/stacklok/toolhive/pkg/auth"
 	"github.com/stacklok/toolhive/pkg/logger"
 	mcpserver "github.com/stacklok/toolhive/pkg/mcp/server"
+	"github.com/stacklok/toolhive/pkg/workloads/imageupdates"
 )
 
 var (
@@ -23,6 +24,8 @@ var (
 	enableMCPServer bool
 	mcpServerPort   string
 	mcpServerHost   string
+
+	imageUpdateInterval time.Duration
 )
 
 var serveCmd = &cobra.Command{
@@ -102,6 +105,16 @@ var serveCmd = &cobra.Command{
 			}()
 		}
 
+		// Periodically check the registries for newer images of the running workloads
+		if imageUpdateInterval > 0 {
+			checker, err := imageupdates.NewChecker(ctx)
+			if err != nil {
+				logger.Debugf("Image update checks are disabled: %v", err)
+			} else {
+				go checker.Schedule(ctx, imageUpdateInterval)
+			}
+		}
+
 		return s.Serve(ctx, address, isUnixSocket, debugMode, enableDocs, oidcConfig)
 	},
 }
@@ -122,6 +135,9 @@ func init() {
 	serveCmd.Flags().StringVar(&mcpServerHost, "experimental-mcp-host", "localhost",
 		"EXPERIMENTAL: Host for the embedded MCP server")
 
+	serveCmd.Flags().DurationVar(&imageUpdateInterval, "image-update-interval", imageupdates.DefaultCheckInterval,
+		"Interval of the checks for newer images of the running workloads (0 disables the checks)")
+
 	// Add OIDC validation flags
 	AddOIDCFlags(serveCmd)
 }
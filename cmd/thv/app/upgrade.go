@@ -0,0 +1,89 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/stacklok/toolhive/pkg/container/runtime"
+	"github.com/stacklok/toolhive/pkg/runner/retriever"
+	"github.com/stacklok/toolhive/pkg/workloads"
+	"github.com/stacklok/toolhive/pkg/workloads/imageupdates"
+)
+
+var (
+	upgradeImageVerification string
+	upgradeCheckOnly         bool
+)
+
+var upgradeCmd = &cobra.Command{
+	Use:   "upgrade <workload-name>",
+	Short: "Upgrade a workload to the latest image of its image reference",
+	Long: `Upgrade a workload to the image its image reference points to in the registry.
+
+Workloads keep running the image that was pulled when they were created, even when a
+newer image is pushed with the same tag. 'thv list --check-updates' and the scheduled
+checks of 'thv serve' report the workloads with a newer image.
+
+The provenance of the newer image is verified like 'thv run' does, then the workload
+is recreated with the same run configuration.
+
+Examples:
+
+	# Check if a newer image is available without upgrading
+	thv upgrade fetch --check
+
+	# Upgrade a workload, refusing images without verified provenance
+	thv upgrade fetch --image-verification enabled`,
+	Args:              cobra.ExactArgs(1),
+	RunE:              upgradeCmdFunc,
+	ValidArgsFunction: completeMCPServerNames,
+}
+
+func init() {
+	upgradeCmd.Flags().StringVar(&upgradeImageVerification, "image-verification", retriever.VerifyImageWarn,
+		fmt.Sprintf("Set image verification mode (%s, %s, %s)",
+			retriever.VerifyImageWarn, retriever.VerifyImageEnabled, retriever.VerifyImageDisabled))
+	upgradeCmd.Flags().BoolVar(&upgradeCheckOnly, "check", false, "Only check if a newer image is available")
+}
+
+func upgradeCmdFunc(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	workloadName := args[0]
+
+	if runtime.IsKubernetesRuntime() {
+		return fmt.Errorf("upgrade is not supported in Kubernetes")
+	}
+
+	checker, err := imageupdates.NewChecker(ctx)
+	if err != nil {
+		return err
+	}
+	update, err := checker.CheckWorkload(ctx, workloadName)
+	if errors.Is(err, imageupdates.ErrNoRegistryImage) {
+		return fmt.Errorf("workload '%s' cannot be upgraded: %w", workloadName, err)
+	}
+	if err != nil {
+		return err
+	}
+	if !update.Available {
+		fmt.Printf("Workload '%s' runs the latest image of %s\n", workloadName, update.Image)
+		return nil
+	}
+	if upgradeCheckOnly {
+		fmt.Printf("A newer image of %s is available for workload '%s' (%s)\n",
+			update.Image, workloadName, update.LatestDigest)
+		return nil
+	}
+
+	workloadManager, err := workloads.NewManager(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create workload manager: %w", err)
+	}
+	if err := checker.Upgrade(ctx, workloadManager, workloadName, upgradeImageVerification); err != nil {
+		return err
+	}
+	fmt.Printf("Workload '%s' upgraded to the latest image of %s\n", workloadName, update.Image)
+	return nil
+}
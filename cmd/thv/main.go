@@ -13,12 +13,16 @@ import (
 	"github.com/stacklok/toolhive/cmd/thv/app"
 	"github.com/stacklok/toolhive/pkg/client"
 	"github.com/stacklok/toolhive/pkg/container"
+	"github.com/stacklok/toolhive/pkg/container/process"
 	"github.com/stacklok/toolhive/pkg/lockfile"
 	"github.com/stacklok/toolhive/pkg/logger"
 	"github.com/stacklok/toolhive/pkg/migration"
 )
 
 func main() {
+	// Run the sandbox helper of the process runtime, which never returns
+	process.Init()
+
 	// Initialize the logger
 	logger.Initialize()
 
@@ -5,21 +5,29 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
 	"time"
 
+	"github.com/adrg/xdg"
+
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/stacklok/toolhive/pkg/audit"
 	"github.com/stacklok/toolhive/pkg/env"
 	"github.com/stacklok/toolhive/pkg/groups"
 	"github.com/stacklok/toolhive/pkg/logger"
 	"github.com/stacklok/toolhive/pkg/telemetry"
+	transportsession "github.com/stacklok/toolhive/pkg/transport/session"
 	"github.com/stacklok/toolhive/pkg/vmcp"
 	"github.com/stacklok/toolhive/pkg/vmcp/aggregator"
 	"github.com/stacklok/toolhive/pkg/vmcp/auth/factory"
+	"github.com/stacklok/toolhive/pkg/vmcp/auth/strategies"
+	"github.com/stacklok/toolhive/pkg/vmcp/cache"
 	vmcpclient "github.com/stacklok/toolhive/pkg/vmcp/client"
+	"github.com/stacklok/toolhive/pkg/vmcp/composer"
 	"github.com/stacklok/toolhive/pkg/vmcp/config"
 	"github.com/stacklok/toolhive/pkg/vmcp/discovery"
 	"github.com/stacklok/toolhive/pkg/vmcp/health"
@@ -211,13 +219,121 @@ func loadAndValidateConfig(configPath string) (*config.Config, error) {
 	return cfg, nil
 }
 
+// newTokenCache creates the token cache for outgoing token exchange.
+// Cache metrics are recorded when a telemetry provider is configured.
+func newTokenCache(
+	ctx context.Context, cfg *config.TokenCacheConfig, telemetryProvider *telemetry.Provider,
+) (cache.TokenCache, error) {
+	provider := cfg.Provider
+	if provider == "" {
+		provider = cache.ProviderMemory
+	}
+	logger.Infof("Initializing token cache (provider: %s)", provider)
+
+	tokenCache, err := cache.NewFromConfig(ctx, cfg, &env.OSReader{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create token cache: %w", err)
+	}
+
+	if telemetryProvider != nil {
+		instrumented, err := cache.NewInstrumentedTokenCache(tokenCache, telemetryProvider.MeterProvider(), provider)
+		if err != nil {
+			_ = tokenCache.Close()
+			return nil, fmt.Errorf("failed to instrument token cache: %w", err)
+		}
+		tokenCache = instrumented
+	}
+
+	return tokenCache, nil
+}
+
+// defaultSessionKeyPrefix is the default prefix for vMCP session keys in Redis.
+const defaultSessionKeyPrefix = "vmcp:sessions:"
+
+// newSessionStorage creates the storage shared by vMCP replicas for client sessions
+// and session affinity pins.
+// Returns nil when sessions are kept in memory.
+func newSessionStorage(
+	ctx context.Context, cfg *config.SessionStorageConfig, ttl time.Duration,
+) (transportsession.Storage, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+
+	switch cfg.Provider {
+	case "", "memory":
+		return nil, nil
+	case "redis":
+		keyPrefix := cfg.Redis.KeyPrefix
+		if keyPrefix == "" {
+			keyPrefix = defaultSessionKeyPrefix
+		}
+		logger.Infof("Initializing session storage (provider: redis, address: %s)", cfg.Redis.Address)
+		return transportsession.NewStorage(ctx, &transportsession.StorageConfig{
+			Type: transportsession.StorageTypeRedis,
+			Redis: &transportsession.RedisConfig{
+				Address:     cfg.Redis.Address,
+				DB:          cfg.Redis.DB,
+				KeyPrefix:   keyPrefix,
+				PasswordEnv: cfg.Redis.PasswordEnv,
+				TLS:         cfg.Redis.TLS,
+			},
+		}, ttl)
+	default:
+		return nil, fmt.Errorf("unsupported session storage provider: %s", cfg.Provider)
+	}
+}
+
+// newWorkflowStateStore creates the persistent store for composite tool workflow state.
+// Returns nil when workflow state is kept in memory.
+func newWorkflowStateStore(cfg *config.Config) (*composer.PersistentStateStore, error) {
+	if cfg.WorkflowState == nil {
+		return nil, nil
+	}
+
+	maxAge := time.Duration(cfg.WorkflowState.RetentionPeriod)
+
+	switch cfg.WorkflowState.Provider {
+	case "", "memory":
+		return nil, nil
+	case "file":
+		dir := cfg.WorkflowState.Path
+		if dir == "" {
+			dir = filepath.Join(xdg.StateHome, "toolhive", "vmcp", cfg.Name, "workflows")
+		}
+		logger.Infof("Initializing workflow state store (provider: file, path: %s)", dir)
+		return composer.NewFileStateStore(dir, composer.DefaultStateCleanupInterval, maxAge)
+	case "configmap":
+		restConfig, err := rest.InClusterConfig()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get in-cluster config: %w", err)
+		}
+		k8sClient, err := client.New(restConfig, client.Options{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create kubernetes client: %w", err)
+		}
+
+		// The operator sets VMCP_NAMESPACE to the VirtualMCPServer's namespace
+		namespace := os.Getenv("VMCP_NAMESPACE")
+		if namespace == "" {
+			return nil, fmt.Errorf("VMCP_NAMESPACE environment variable not set")
+		}
+		logger.Infof("Initializing workflow state store (provider: configmap, namespace: %s)", namespace)
+		return composer.NewConfigMapStateStore(k8sClient, namespace, cfg.Name, composer.DefaultStateCleanupInterval, maxAge)
+	default:
+		return nil, fmt.Errorf("unsupported workflow state provider: %s", cfg.WorkflowState.Provider)
+	}
+}
+
 // discoverBackends initializes managers, discovers backends, and creates backend client
 // Returns empty backends list with no error if running in Kubernetes where CLI discovery doesn't work
-func discoverBackends(ctx context.Context, cfg *config.Config) ([]vmcp.Backend, vmcp.BackendClient, error) {
+func discoverBackends(
+	ctx context.Context, cfg *config.Config, tokenExchangeOpts ...strategies.TokenExchangeOption,
+) ([]vmcp.Backend, vmcp.BackendClient, error) {
 	// Create outgoing authentication registry
 	logger.Info("Initializing outgoing authentication")
 	envReader := &env.OSReader{}
-	outgoingRegistry, err := factory.NewOutgoingAuthRegistry(ctx, envReader)
+	outgoingRegistry, err := factory.NewOutgoingAuthRegistry(ctx, envReader, tokenExchangeOpts...)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to create outgoing authentication registry: %w", err)
 	}
@@ -256,6 +372,64 @@ func discoverBackends(ctx context.Context, cfg *config.Config) ([]vmcp.Backend,
 	return backends, backendClient, nil
 }
 
+// newServerConfig creates the Virtual MCP Server configuration from the vMCP configuration.
+// Listener, authentication, telemetry and watcher settings are set by the caller.
+func newServerConfig(cfg *config.Config, sessionStorage transportsession.Storage) (*vmcpserver.Config, error) {
+	// Configure health monitoring if enabled
+	var healthMonitorConfig *health.MonitorConfig
+	if cfg.Operational != nil && cfg.Operational.FailureHandling != nil && cfg.Operational.FailureHandling.HealthCheckInterval > 0 {
+		// Note: HealthCheckInterval is config.Duration (alias for time.Duration), already in nanoseconds
+		// from YAML/JSON parsing via time.ParseDuration. This is a simple type cast, not unit conversion.
+		checkInterval := time.Duration(cfg.Operational.FailureHandling.HealthCheckInterval)
+		if cfg.Operational.FailureHandling.UnhealthyThreshold < 1 {
+			return nil, fmt.Errorf("invalid health check configuration: unhealthy threshold must be >= 1, got %d",
+				cfg.Operational.FailureHandling.UnhealthyThreshold)
+		}
+
+		defaults := health.DefaultConfig()
+		healthMonitorConfig = &health.MonitorConfig{
+			CheckInterval:      checkInterval,
+			UnhealthyThreshold: cfg.Operational.FailureHandling.UnhealthyThreshold,
+			Timeout:            defaults.Timeout,
+			DegradedThreshold:  defaults.DegradedThreshold,
+		}
+		logger.Info("Health monitoring configured from operational settings")
+	}
+
+	// Configure circuit breaker if enabled
+	var circuitBreakerConfig *health.CircuitBreakerConfig
+	if cfg.Operational != nil && cfg.Operational.FailureHandling != nil &&
+		cfg.Operational.FailureHandling.CircuitBreaker != nil && cfg.Operational.FailureHandling.CircuitBreaker.Enabled {
+		cb := cfg.Operational.FailureHandling.CircuitBreaker
+		circuitBreakerConfig = &health.CircuitBreakerConfig{
+			FailureThreshold: cb.FailureThreshold,
+			Timeout:          time.Duration(cb.Timeout),
+		}
+		logger.Info("Circuit breaker configured from operational settings")
+	}
+
+	serverCfg := &vmcpserver.Config{
+		Name:                 cfg.Name,
+		Version:              getVersion(),
+		GroupRef:             cfg.Group,
+		SessionTTL:           vmcpserver.DefaultSessionTTL,
+		AuditConfig:          cfg.Audit,
+		RateLimitConfig:      cfg.RateLimit,
+		HealthMonitorConfig:  healthMonitorConfig,
+		CircuitBreakerConfig: circuitBreakerConfig,
+		ReplicaGroups:        cfg.Aggregation.ReplicaGroups,
+	}
+
+	// Share client sessions and session affinity pins through the session storage, so that
+	// any replica can serve a client session and routes it to the same backend replica
+	if sessionStorage != nil {
+		serverCfg.SessionStorage = sessionStorage
+		serverCfg.SessionAffinityProvider = vmcprouter.NewStorageSessionAffinity(sessionStorage)
+	}
+
+	return serverCfg, nil
+}
+
 // runServe implements the serve command logic
 //
 //nolint:gocyclo // Complexity from server initialization and configuration is acceptable
@@ -282,8 +456,40 @@ func runServe(cmd *cobra.Command, _ []string) error {
 		logger.Info("Audit logging enabled with default configuration")
 	}
 
+	// If telemetry is configured, create the provider.
+	var telemetryProvider *telemetry.Provider
+	if cfg.Telemetry != nil {
+		var err error
+		telemetryProvider, err = telemetry.NewProvider(ctx, *cfg.Telemetry)
+		if err != nil {
+			return fmt.Errorf("failed to create telemetry provider: %w", err)
+		}
+		defer func() {
+			err := telemetryProvider.Shutdown(ctx)
+			if err != nil {
+				logger.Errorf("failed to shutdown telemetry provider: %v", err)
+			}
+		}()
+	}
+
+	// Create the token cache for outgoing token exchange, if configured
+	var tokenExchangeOpts []strategies.TokenExchangeOption
+	if cfg.TokenCache != nil {
+		tokenCache, err := newTokenCache(ctx, cfg.TokenCache, telemetryProvider)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if err := tokenCache.Close(); err != nil {
+				logger.Errorf("failed to close token cache: %v", err)
+			}
+		}()
+		tokenExchangeOpts = append(tokenExchangeOpts,
+			strategies.WithTokenCache(tokenCache, time.Duration(cfg.TokenCache.RefreshOffset)))
+	}
+
 	// Discover backends and create client
-	backends, backendClient, err := discoverBackends(ctx, cfg)
+	backends, backendClient, err := discoverBackends(ctx, cfg, tokenExchangeOpts...)
 	if err != nil {
 		return err
 	}
@@ -295,7 +501,8 @@ func runServe(cmd *cobra.Command, _ []string) error {
 	}
 
 	// Create aggregator
-	agg := aggregator.NewDefaultAggregator(backendClient, conflictResolver, cfg.Aggregation.Tools)
+	agg := aggregator.NewDefaultAggregator(backendClient, conflictResolver, cfg.Aggregation.Tools,
+		aggregator.WithReplicaGroups(cfg.Aggregation.ReplicaGroups))
 
 	// Use DynamicRegistry for version-based cache invalidation
 	// Works in both standalone (CLI with YAML config) and Kubernetes (operator-deployed) modes
@@ -365,55 +572,38 @@ func runServe(cmd *cobra.Command, _ []string) error {
 	host, _ := cmd.Flags().GetString("host")
 	port, _ := cmd.Flags().GetInt("port")
 
-	// If telemetry is configured, create the provider.
-	var telemetryProvider *telemetry.Provider
-	if cfg.Telemetry != nil {
-		var err error
-		telemetryProvider, err = telemetry.NewProvider(ctx, *cfg.Telemetry)
-		if err != nil {
-			return fmt.Errorf("failed to create telemetry provider: %w", err)
-		}
+	// Create the storage shared by vMCP replicas for client sessions and affinity pins, if configured
+	sessionStorage, err := newSessionStorage(ctx, cfg.SessionStorage, vmcpserver.DefaultSessionTTL)
+	if err != nil {
+		return fmt.Errorf("failed to create session storage: %w", err)
+	}
+	if sessionStorage != nil {
 		defer func() {
-			err := telemetryProvider.Shutdown(ctx)
-			if err != nil {
-				logger.Errorf("failed to shutdown telemetry provider: %v", err)
+			if err := sessionStorage.Close(); err != nil {
+				logger.Errorf("failed to close session storage: %v", err)
 			}
 		}()
 	}
 
-	// Configure health monitoring if enabled
-	var healthMonitorConfig *health.MonitorConfig
-	if cfg.Operational != nil && cfg.Operational.FailureHandling != nil && cfg.Operational.FailureHandling.HealthCheckInterval > 0 {
-		// Note: HealthCheckInterval is config.Duration (alias for time.Duration), already in nanoseconds
-		// from YAML/JSON parsing via time.ParseDuration. This is a simple type cast, not unit conversion.
-		checkInterval := time.Duration(cfg.Operational.FailureHandling.HealthCheckInterval)
-		if cfg.Operational.FailureHandling.UnhealthyThreshold < 1 {
-			return fmt.Errorf("invalid health check configuration: unhealthy threshold must be >= 1, got %d",
-				cfg.Operational.FailureHandling.UnhealthyThreshold)
-		}
-
-		defaults := health.DefaultConfig()
-		healthMonitorConfig = &health.MonitorConfig{
-			CheckInterval:      checkInterval,
-			UnhealthyThreshold: cfg.Operational.FailureHandling.UnhealthyThreshold,
-			Timeout:            defaults.Timeout,
-			DegradedThreshold:  defaults.DegradedThreshold,
-		}
-		logger.Info("Health monitoring configured from operational settings")
+	serverCfg, err := newServerConfig(cfg, sessionStorage)
+	if err != nil {
+		return err
 	}
-
-	serverCfg := &vmcpserver.Config{
-		Name:                cfg.Name,
-		Version:             getVersion(),
-		GroupRef:            cfg.Group,
-		Host:                host,
-		Port:                port,
-		AuthMiddleware:      authMiddleware,
-		AuthInfoHandler:     authInfoHandler,
-		TelemetryProvider:   telemetryProvider,
-		AuditConfig:         cfg.Audit,
-		HealthMonitorConfig: healthMonitorConfig,
-		Watcher:             backendWatcher,
+	serverCfg.Host = host
+	serverCfg.Port = port
+	serverCfg.AuthMiddleware = authMiddleware
+	serverCfg.AuthInfoHandler = authInfoHandler
+	serverCfg.TelemetryProvider = telemetryProvider
+	serverCfg.Watcher = backendWatcher
+
+	// Create the persistent workflow state store, if configured
+	workflowStateStore, err := newWorkflowStateStore(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create workflow state store: %w", err)
+	}
+	if workflowStateStore != nil {
+		defer workflowStateStore.Stop()
+		serverCfg.WorkflowStateStore = workflowStateStore
 	}
 
 	// Convert composite tool configurations to workflow definitions
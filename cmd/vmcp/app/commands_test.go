@@ -0,0 +1,115 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/stacklok/toolhive/pkg/vmcp"
+	discoverymocks "github.com/stacklok/toolhive/pkg/vmcp/discovery/mocks"
+	"github.com/stacklok/toolhive/pkg/vmcp/mocks"
+	vmcprouter "github.com/stacklok/toolhive/pkg/vmcp/router"
+	vmcpserver "github.com/stacklok/toolhive/pkg/vmcp/server"
+)
+
+// writeTestConfig writes a vMCP configuration with a session affinity replica group
+// and the given session storage section.
+func writeTestConfig(t *testing.T, sessionStorage string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "vmcp.yaml")
+	content := `
+name: test-vmcp
+groupRef: test-group
+
+incomingAuth:
+  type: anonymous
+
+outgoingAuth:
+  source: inline
+  default:
+    type: unauthenticated
+
+aggregation:
+  conflictResolution: prefix
+  conflictResolutionConfig:
+    prefixFormat: "{workload}_"
+  replicaGroups:
+    - name: browser
+      workloads: ["playwright-a", "playwright-b"]
+      sessionAffinity: true
+` + sessionStorage
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func TestNewServerConfig_SessionAffinity(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	redis := miniredis.RunT(t)
+
+	cfg, err := loadAndValidateConfig(writeTestConfig(t, fmt.Sprintf(`
+sessionStorage:
+  provider: redis
+  redis:
+    address: %s
+`, redis.Addr())))
+	require.NoError(t, err)
+
+	sessionStorage, err := newSessionStorage(ctx, cfg.SessionStorage, vmcpserver.DefaultSessionTTL)
+	require.NoError(t, err)
+	require.NotNil(t, sessionStorage)
+	defer func() { _ = sessionStorage.Close() }()
+
+	serverCfg, err := newServerConfig(cfg, sessionStorage)
+	require.NoError(t, err)
+	require.NotNil(t, serverCfg.SessionAffinityProvider)
+	assert.Equal(t, sessionStorage, serverCfg.SessionStorage)
+
+	ctrl := gomock.NewController(t)
+	srv, err := vmcpserver.New(ctx, serverCfg, vmcprouter.NewDefaultRouter(), mocks.NewMockBackendClient(ctrl),
+		discoverymocks.NewMockManager(ctrl), vmcp.NewImmutableRegistry(nil), nil)
+	require.NoError(t, err)
+	require.NotNil(t, srv)
+
+	// Session pins are shared through the session storage
+	target := &vmcp.BackendTarget{WorkloadID: "playwright-b", ReplicaGroup: "browser"}
+	key := vmcprouter.SessionAffinityKey("session-1", "browser")
+	require.NoError(t, serverCfg.SessionAffinityProvider.SetBackendForSession(ctx, key, target))
+	assert.True(t, redis.Exists(defaultSessionKeyPrefix+"vmcp-affinity:"+key))
+
+	otherReplica := vmcprouter.NewStorageSessionAffinity(sessionStorage)
+	pinned, err := otherReplica.GetBackendForSession(ctx, key)
+	require.NoError(t, err)
+	require.NotNil(t, pinned)
+	assert.Equal(t, "playwright-b", pinned.WorkloadID)
+}
+
+func TestNewServerConfig_MemorySessionStorage(t *testing.T) {
+	t.Parallel()
+
+	cfg, err := loadAndValidateConfig(writeTestConfig(t, `
+sessionStorage:
+  provider: memory
+`))
+	require.NoError(t, err)
+
+	sessionStorage, err := newSessionStorage(context.Background(), cfg.SessionStorage, vmcpserver.DefaultSessionTTL)
+	require.NoError(t, err)
+	assert.Nil(t, sessionStorage)
+
+	// The server falls back to in-memory session affinity
+	serverCfg, err := newServerConfig(cfg, sessionStorage)
+	require.NoError(t, err)
+	assert.Nil(t, serverCfg.SessionAffinityProvider)
+	assert.Nil(t, serverCfg.SessionStorage)
+	assert.Equal(t, vmcpserver.DefaultSessionTTL, serverCfg.SessionTTL)
+}
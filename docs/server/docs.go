@@ -8,6 +8,36 @@ const docTemplate = `{
     "schemes": {{ marshal .Schemes }},
     "components": {
         "schemas": {
+            "audit.BatchConfig": {
+                "description": "Batch configures the batching, retries and disk buffering of the events.\n+optional",
+                "properties": {
+                    "bufferDir": {
+                        "description": "BufferDir is a directory where the events that could not be delivered are kept\nuntil the collector is available again. If empty, these events are dropped.\n+optional",
+                        "type": "string"
+                    },
+                    "flushInterval": {
+                        "description": "FlushInterval is the maximum time an event waits before being sent, e.g. \"5s\".\n+kubebuilder:default=\"5s\"\n+optional",
+                        "type": "string"
+                    },
+                    "maxBatchSize": {
+                        "description": "MaxBatchSize is the maximum number of events sent in one request.\n+kubebuilder:default=100\n+kubebuilder:validation:Minimum=1\n+optional",
+                        "type": "integer"
+                    },
+                    "maxBufferBytes": {
+                        "description": "MaxBufferBytes limits the size of the disk buffer. Events are dropped when it is full.\n+kubebuilder:default=104857600\n+optional",
+                        "type": "integer"
+                    },
+                    "maxRetries": {
+                        "description": "MaxRetries is the number of times a failed request is retried, with exponential backoff.\n0 disables retries. Defaults to 3 when unset.\n+kubebuilder:default=3\n+kubebuilder:validation:Minimum=0\n+optional",
+                        "type": "integer"
+                    },
+                    "timeout": {
+                        "description": "Timeout is the timeout of a request, e.g. \"10s\".\n+kubebuilder:default=\"10s\"\n+optional",
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
             "audit.Config": {
                 "description": "DEPRECATED: Middleware configuration.\nAuditConfig contains the audit logging configuration",
                 "properties": {
@@ -43,6 +73,9 @@ const docTemplate = `{
                         "description": "IncludeResponseData determines whether to include response data in audit logs.\n+kubebuilder:default=false\n+optional",
                         "type": "boolean"
                     },
+                    "integrity": {
+                        "$ref": "#/components/schemas/audit.IntegrityConfig"
+                    },
                     "logFile": {
                         "description": "LogFile specifies the file path for audit logs. If empty, logs to stdout.\n+optional",
                         "type": "string"
@@ -50,6 +83,151 @@ const docTemplate = `{
                     "maxDataSize": {
                         "description": "MaxDataSize limits the size of request/response data included in audit logs (in bytes).\n+kubebuilder:default=1024\n+optional",
                         "type": "integer"
+                    },
+                    "sinks": {
+                        "description": "Sinks are additional destinations of audit events, such as syslog servers, HTTP collectors\nand OTLP collectors. Events are written to all the sinks, in addition to LogFile or stdout.\n+optional",
+                        "items": {
+                            "$ref": "#/components/schemas/audit.SinkConfig"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    }
+                },
+                "type": "object"
+            },
+            "audit.EventSource": {
+                "description": "Source is the source of the request",
+                "properties": {
+                    "extra": {
+                        "additionalProperties": {},
+                        "description": "Extra allows for including additional information about the event\nsource that aids in tracking, parsing or auditing",
+                        "type": "object"
+                    },
+                    "type": {
+                        "description": "Type indicates the source type. e.g. Network, File, local, etc.\nThe intent is to determine where a request came from.",
+                        "type": "string"
+                    },
+                    "value": {
+                        "description": "Value aims to indicate the source of the event. e.g. IP address,\nhostname, etc.",
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "audit.IntegrityConfig": {
+                "description": "Integrity enables the tamper-evident mode, in which events are hash-chained\nand signed checkpoints are written periodically.\n+optional",
+                "properties": {
+                    "chainStateFile": {
+                        "description": "ChainStateFile is a file keeping the position of the hash chain when events are written to\nstdout, where the chain cannot be resumed. Each process starts its chain with a signed record\nreferencing the last event of the previous process, so that removed events are detected.\nWithout it, chains are not linked. It is not used when events are written to a log file.\n+optional",
+                        "type": "string"
+                    },
+                    "checkpointEvents": {
+                        "description": "CheckpointEvents is the maximum number of events between two checkpoints.\n+kubebuilder:default=100\n+kubebuilder:validation:Minimum=1\n+optional",
+                        "type": "integer"
+                    },
+                    "checkpointInterval": {
+                        "description": "CheckpointInterval is the maximum time between an event and the next checkpoint, e.g. \"1m\".\n+kubebuilder:default=\"1m\"\n+optional",
+                        "type": "string"
+                    },
+                    "signingKeySecret": {
+                        "description": "SigningKeySecret is the name of the secret holding the Ed25519 private key signing the\ncheckpoints, either PEM encoded (PKCS #8) or as the base64 encoded seed.\nIn Kubernetes, it is the name of a Secret in the same namespace, holding the key in its\nsigningKey entry.\n+kubebuilder:validation:Required",
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "audit.OTLPSinkConfig": {
+                "description": "OTLP configures an OTLP logs sink.\n+optional",
+                "properties": {
+                    "batch": {
+                        "$ref": "#/components/schemas/audit.BatchConfig"
+                    },
+                    "bearerTokenEnv": {
+                        "description": "BearerTokenEnv is the name of an environment variable holding a bearer token\nsent in the Authorization header.\n+optional",
+                        "type": "string"
+                    },
+                    "endpoint": {
+                        "description": "Endpoint is the URL of the OTLP/HTTP collector, e.g. \"http://otel-collector:4318\".\nThe /v1/logs path is used unless the URL has a path.\n+kubebuilder:validation:Required",
+                        "type": "string"
+                    },
+                    "headers": {
+                        "additionalProperties": {
+                            "type": "string"
+                        },
+                        "description": "Headers are additional HTTP headers of the export requests.\n+optional",
+                        "type": "object"
+                    },
+                    "serviceName": {
+                        "description": "ServiceName is the service.name resource attribute of the log records.\n+kubebuilder:default=\"toolhive\"\n+optional",
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "audit.SinkConfig": {
+                "properties": {
+                    "otlp": {
+                        "$ref": "#/components/schemas/audit.OTLPSinkConfig"
+                    },
+                    "syslog": {
+                        "$ref": "#/components/schemas/audit.SyslogSinkConfig"
+                    },
+                    "type": {
+                        "description": "Type is the type of the sink.\n+kubebuilder:validation:Enum=syslog;webhook;otlp",
+                        "type": "string"
+                    },
+                    "webhook": {
+                        "$ref": "#/components/schemas/audit.WebhookSinkConfig"
+                    }
+                },
+                "type": "object"
+            },
+            "audit.SyslogSinkConfig": {
+                "description": "Syslog configures a syslog sink.\n+optional",
+                "properties": {
+                    "address": {
+                        "description": "Address is the host:port of the syslog server.\n+kubebuilder:validation:Required",
+                        "type": "string"
+                    },
+                    "appName": {
+                        "description": "AppName is the APP-NAME of the messages.\n+kubebuilder:default=toolhive\n+optional",
+                        "type": "string"
+                    },
+                    "caCertPath": {
+                        "description": "CACertPath is the path to a CA certificate bundle verifying the certificate of the\nsyslog server with the tls network. If empty, the system roots are used.\n+optional",
+                        "type": "string"
+                    },
+                    "facility": {
+                        "description": "Facility is the syslog facility of the events, e.g. \"auth\", \"audit\" or \"local0\".\n+kubebuilder:default=auth\n+optional",
+                        "type": "string"
+                    },
+                    "network": {
+                        "description": "Network is the transport to the syslog server.\n+kubebuilder:validation:Enum=tcp;tls;udp\n+kubebuilder:default=tcp\n+optional",
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "audit.WebhookSinkConfig": {
+                "description": "Webhook configures an HTTP webhook sink.\n+optional",
+                "properties": {
+                    "batch": {
+                        "$ref": "#/components/schemas/audit.BatchConfig"
+                    },
+                    "bearerTokenEnv": {
+                        "description": "BearerTokenEnv is the name of an environment variable holding a bearer token\nsent in the Authorization header.\n+optional",
+                        "type": "string"
+                    },
+                    "headers": {
+                        "additionalProperties": {
+                            "type": "string"
+                        },
+                        "description": "Headers are additional HTTP headers of the requests.\n+optional",
+                        "type": "object"
+                    },
+                    "url": {
+                        "description": "URL is the URL the batches of events are posted to.\n+kubebuilder:validation:Required",
+                        "type": "string"
                     }
                 },
                 "type": "object"
@@ -111,6 +289,100 @@ const docTemplate = `{
                 },
                 "type": "object"
             },
+            "authserver.RunConfig": {
+                "description": "EmbeddedAuthServerConfig configures an OAuth authorization server served by the proxy.\nWhen set, MCP clients obtain access tokens from the proxy, which federates user\nauthentication to an upstream identity provider.\nOnly applies to SSE and streamable HTTP transports.",
+                "properties": {
+                    "access_token_lifespan": {
+                        "description": "AccessTokenLifespan is the lifespan of access tokens (e.g., \"1h\").",
+                        "type": "string"
+                    },
+                    "allowed_audiences": {
+                        "description": "AllowedAudiences are the resource servers tokens can be issued for,\ntypically the MCP server URL. The first audience is validated by the proxy.",
+                        "items": {
+                            "type": "string"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    },
+                    "auth_code_lifespan": {
+                        "description": "AuthCodeLifespan is the lifespan of authorization codes (e.g., \"10m\").",
+                        "type": "string"
+                    },
+                    "hmac_secret_files": {
+                        "description": "HMACSecretFiles are paths to files containing the secrets used to sign authorization\ncodes and refresh tokens. The first is the current secret, the rest are rotated secrets\nstill accepted for verification. If empty, a random secret is generated at startup.",
+                        "items": {
+                            "type": "string"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    },
+                    "issuer": {
+                        "description": "Issuer is the issuer identifier and public base URL of the authorization server,\ntypically the proxy URL (e.g., https://mcp.example.com).",
+                        "type": "string"
+                    },
+                    "refresh_token_lifespan": {
+                        "description": "RefreshTokenLifespan is the lifespan of refresh tokens (e.g., \"720h\").",
+                        "type": "string"
+                    },
+                    "scopes_supported": {
+                        "description": "ScopesSupported are the scopes clients may request. Defaults to openid, profile and email.",
+                        "items": {
+                            "type": "string"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    },
+                    "signing_algorithm": {
+                        "description": "SigningAlgorithm overrides the signing algorithm. Defaults to the algorithm of the key type.",
+                        "type": "string"
+                    },
+                    "signing_key_file": {
+                        "description": "SigningKeyFile is the path to a PEM encoded RSA or EC private key used to sign\naccess tokens. If empty, an ephemeral RSA key is generated at startup and tokens\ndo not survive restarts.",
+                        "type": "string"
+                    },
+                    "signing_key_id": {
+                        "description": "SigningKeyID overrides the key ID published in the JWKS.\nDefaults to the RFC 7638 thumbprint of the key.",
+                        "type": "string"
+                    },
+                    "storage": {
+                        "$ref": "#/components/schemas/storage.RunConfig"
+                    },
+                    "upstream": {
+                        "$ref": "#/components/schemas/authserver.UpstreamRunConfig"
+                    }
+                },
+                "type": "object"
+            },
+            "authserver.UpstreamRunConfig": {
+                "description": "Upstream configures the identity provider users authenticate with.",
+                "properties": {
+                    "client_id": {
+                        "description": "ClientID is the client ID registered with the upstream provider.",
+                        "type": "string"
+                    },
+                    "client_secret_file": {
+                        "description": "ClientSecretFile is the path to a file containing the upstream client secret.\nIf empty, the secret is read from the TOOLHIVE_AUTHSERVER_UPSTREAM_CLIENT_SECRET\nenvironment variable.",
+                        "type": "string"
+                    },
+                    "issuer": {
+                        "description": "Issuer is the issuer URL of the upstream OIDC provider.",
+                        "type": "string"
+                    },
+                    "redirect_uri": {
+                        "description": "RedirectURI is the callback URL registered with the upstream provider.\nDefaults to the issuer followed by /oauth/callback.",
+                        "type": "string"
+                    },
+                    "scopes": {
+                        "description": "Scopes are the scopes requested from the upstream provider.\nDefaults to openid, profile and email.",
+                        "items": {
+                            "type": "string"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    }
+                },
+                "type": "object"
+            },
             "authz.Config": {
                 "description": "DEPRECATED: Middleware configuration.\nAuthzConfig contains the authorization configuration",
                 "properties": {
@@ -148,7 +420,8 @@ const docTemplate = `{
                     "opencode",
                     "kiro",
                     "antigravity",
-                    "zed"
+                    "zed",
+                    "codex"
                 ],
                 "type": "string",
                 "x-enum-varnames": [
@@ -172,7 +445,8 @@ const docTemplate = `{
                     "OpenCode",
                     "Kiro",
                     "Antigravity",
-                    "Zed"
+                    "Zed",
+                    "Codex"
                 ]
             },
             "client.MCPClientStatus": {
@@ -206,6 +480,28 @@ const docTemplate = `{
                 },
                 "type": "object"
             },
+            "core.ImageUpdate": {
+                "description": "ImageUpdate is the result of the last check for a newer image of the workload, if any.",
+                "properties": {
+                    "available": {
+                        "description": "Available indicates whether the registry has a different image for the reference.",
+                        "type": "boolean"
+                    },
+                    "checked_at": {
+                        "description": "CheckedAt is the timestamp of the check.",
+                        "type": "string"
+                    },
+                    "image": {
+                        "description": "Image is the image reference that was checked.",
+                        "type": "string"
+                    },
+                    "latest_digest": {
+                        "description": "LatestDigest is the digest of the image the reference points to in the registry.",
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
             "core.Workload": {
                 "properties": {
                     "created_at": {
@@ -216,6 +512,9 @@ const docTemplate = `{
                         "description": "Group is the name of the group this workload belongs to, if any.",
                         "type": "string"
                     },
+                    "image_update": {
+                        "$ref": "#/components/schemas/core.ImageUpdate"
+                    },
                     "labels": {
                         "additionalProperties": {
                             "type": "string"
@@ -223,6 +522,10 @@ const docTemplate = `{
                         "description": "Labels are the container labels (excluding standard ToolHive labels)",
                         "type": "object"
                     },
+                    "last_restart_at": {
+                        "description": "LastRestartAt is the timestamp of the last restart of the workload, if any.",
+                        "type": "string"
+                    },
                     "name": {
                         "description": "Name is the name of the workload.\nIt is used as a unique identifier.",
                         "type": "string"
@@ -243,6 +546,10 @@ const docTemplate = `{
                         "description": "Remote indicates whether this is a remote workload (true) or a container workload (false).",
                         "type": "boolean"
                     },
+                    "restarts": {
+                        "description": "Restarts is the number of times the workload was restarted after exiting unexpectedly.",
+                        "type": "integer"
+                    },
                     "status": {
                         "$ref": "#/components/schemas/runtime.WorkloadStatus"
                     },
@@ -268,8 +575,43 @@ const docTemplate = `{
                 },
                 "type": "object"
             },
+            "groups.Defaults": {
+                "properties": {
+                    "audit_config": {
+                        "$ref": "#/components/schemas/audit.Config"
+                    },
+                    "authz_config": {
+                        "$ref": "#/components/schemas/authz.Config"
+                    },
+                    "env_vars": {
+                        "additionalProperties": {
+                            "type": "string"
+                        },
+                        "description": "EnvVars are environment variables set in the workloads of the group.\nVariables set on a workload override the variables with the same name.",
+                        "type": "object"
+                    },
+                    "oidc_config": {
+                        "$ref": "#/components/schemas/auth.TokenValidatorConfig"
+                    },
+                    "permission_profile": {
+                        "$ref": "#/components/schemas/permissions.Profile"
+                    },
+                    "secrets": {
+                        "description": "Secrets are secrets set as environment variables in the workloads of the group\n(format: NAME,target=TARGET). Secrets of a workload override the secrets with the same target.",
+                        "items": {
+                            "type": "string"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    }
+                },
+                "type": "object"
+            },
             "groups.Group": {
                 "properties": {
+                    "defaults": {
+                        "$ref": "#/components/schemas/groups.Defaults"
+                    },
                     "name": {
                         "type": "string"
                     },
@@ -297,6 +639,28 @@ const docTemplate = `{
                 },
                 "type": "object"
             },
+            "mcp.ResponseCacheConfig": {
+                "description": "ResponseCacheConfig contains the configuration for caching responses to idempotent MCP requests",
+                "properties": {
+                    "max_entries": {
+                        "description": "MaxEntries is the maximum number of cached responses. The least recently used\nresponse is evicted when the cache is full. Defaults to 1000.",
+                        "type": "integer"
+                    },
+                    "tools": {
+                        "description": "Tools lists the tools whose tools/call responses may be cached. A tool is only\ncached if the server also annotates it with readOnlyHint in its tools/list response.",
+                        "items": {
+                            "type": "string"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    },
+                    "ttl": {
+                        "description": "TTL is how long a response is served from the cache, e.g. \"5m\". Defaults to 5 minutes.",
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
             "permissions.InboundNetworkPermissions": {
                 "description": "Inbound defines inbound network permissions",
                 "properties": {
@@ -354,7 +718,7 @@ const docTemplate = `{
                 "type": "object"
             },
             "permissions.Profile": {
-                "description": "PermissionProfile is the permission profile to use",
+                "description": "LearnBaseProfile is the profile the learned permission profile is compared to when the\nworkload runs with the learn permission profile, usually the profile from the registry",
                 "properties": {
                     "name": {
                         "description": "Name is the name of the profile",
@@ -375,6 +739,9 @@ const docTemplate = `{
                         "type": "array",
                         "uniqueItems": false
                     },
+                    "resources": {
+                        "$ref": "#/components/schemas/permissions.ResourceLimits"
+                    },
                     "write": {
                         "description": "Write is a list of mount declarations that the container can write to\nThese follow the same format as Read mounts but with write permissions",
                         "items": {
@@ -386,42 +753,190 @@ const docTemplate = `{
                 },
                 "type": "object"
             },
-            "registry.EnvVar": {
+            "permissions.ResourceLimits": {
+                "description": "Resources defines CPU, memory and process limits for the container\nWhen nil, the defaults of the container runtime apply",
                 "properties": {
-                    "default": {
-                        "description": "Default is the value to use if the environment variable is not explicitly provided\nOnly used for non-required variables",
-                        "type": "string"
+                    "cpu_shares": {
+                        "description": "CPUShares is the relative CPU weight of the container (Docker default 1024)",
+                        "type": "integer"
                     },
-                    "description": {
-                        "description": "Description is a human-readable explanation of the variable's purpose",
+                    "cpus": {
+                        "description": "CPUs is the maximum number of CPUs the container can use (e.g. \"1.5\" or \"500m\")",
                         "type": "string"
                     },
-                    "name": {
-                        "description": "Name is the environment variable name (e.g., API_KEY)",
+                    "memory": {
+                        "description": "Memory is the maximum amount of memory the container can use (e.g. \"512Mi\", \"2Gi\")",
                         "type": "string"
                     },
-                    "required": {
-                        "description": "Required indicates whether this environment variable must be provided\nIf true and not provided via command line or secrets, the user will be prompted for a value",
-                        "type": "boolean"
+                    "pids_limit": {
+                        "description": "PidsLimit is the maximum number of processes the container can run",
+                        "type": "integer"
                     },
-                    "secret": {
-                        "description": "Secret indicates whether this environment variable contains sensitive information\nIf true, the value will be stored as a secret rather than as a plain environment variable",
-                        "type": "boolean"
+                    "tmpfs_size": {
+                        "description": "TmpfsSize is the size of the in-memory filesystem mounted at /tmp (e.g. \"64Mi\").\nWhen empty, /tmp is part of the container filesystem.",
+                        "type": "string"
                     }
                 },
                 "type": "object"
             },
-            "registry.Group": {
+            "query.Record": {
+                "description": "Event is the replayed audit event",
                 "properties": {
-                    "description": {
-                        "description": "Description is a human-readable description of the group's purpose and functionality",
+                    "audit_id": {
+                        "description": "AuditID is the unique identifier of the event",
                         "type": "string"
                     },
-                    "name": {
-                        "description": "Name is the identifier for the group, used when referencing the group in commands",
+                    "component": {
+                        "description": "Component is the component that recorded the event",
                         "type": "string"
                     },
-                    "remote_servers": {
+                    "data": {
+                        "description": "Data is the request and response data of the event, if recorded",
+                        "type": "object"
+                    },
+                    "extra": {
+                        "additionalProperties": {},
+                        "description": "Extra is the additional metadata of the event, e.g. its duration",
+                        "type": "object"
+                    },
+                    "logged_at": {
+                        "description": "Time is the time the event was logged",
+                        "type": "string"
+                    },
+                    "outcome": {
+                        "description": "Outcome is the outcome of the event, e.g. success or denied",
+                        "type": "string"
+                    },
+                    "source": {
+                        "$ref": "#/components/schemas/audit.EventSource"
+                    },
+                    "subjects": {
+                        "additionalProperties": {
+                            "type": "string"
+                        },
+                        "description": "Subjects identify who made the request, e.g. the user and the client",
+                        "type": "object"
+                    },
+                    "target": {
+                        "additionalProperties": {
+                            "type": "string"
+                        },
+                        "description": "Target is what the request acted on, e.g. the tool called",
+                        "type": "object"
+                    },
+                    "type": {
+                        "description": "Type is the type of the event, e.g. mcp_tool_call",
+                        "type": "string"
+                    },
+                    "workload": {
+                        "description": "Workload is the name of the workload the event was recorded for, if known",
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "ratelimit.Config": {
+                "description": "RateLimitConfig contains the token bucket limits applied to MCP requests",
+                "properties": {
+                    "limits": {
+                        "description": "Limits are the token bucket limits to enforce. A request must be allowed\nby every limit that applies to it.\n+kubebuilder:validation:MinItems=1",
+                        "items": {
+                            "$ref": "#/components/schemas/ratelimit.Limit"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    }
+                },
+                "type": "object"
+            },
+            "ratelimit.KeyType": {
+                "description": "Key determines how requests are grouped; each group has its own bucket.\n+kubebuilder:validation:Enum=identity;clientIP;tool;method",
+                "enum": [
+                    "identity",
+                    "clientIP",
+                    "tool",
+                    "method"
+                ],
+                "type": "string",
+                "x-enum-varnames": [
+                    "KeyIdentity",
+                    "KeyClientIP",
+                    "KeyTool",
+                    "KeyMethod"
+                ]
+            },
+            "ratelimit.Limit": {
+                "properties": {
+                    "burst": {
+                        "description": "Burst is the size of a bucket, i.e. the number of requests allowed at once.\nDefaults to RequestsPerMinute.\n+kubebuilder:validation:Minimum=0\n+optional",
+                        "type": "integer"
+                    },
+                    "key": {
+                        "$ref": "#/components/schemas/ratelimit.KeyType"
+                    },
+                    "methods": {
+                        "description": "Methods restricts the limit to the given MCP methods, e.g. \"tools/call\".\nWhen empty, the limit applies to all methods.\n+optional",
+                        "items": {
+                            "type": "string"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    },
+                    "name": {
+                        "description": "Name identifies the limit in metrics, audit logs and error responses.\n+kubebuilder:validation:Required",
+                        "type": "string"
+                    },
+                    "requestsPerMinute": {
+                        "description": "RequestsPerMinute is the rate at which tokens are added to a bucket.\n+kubebuilder:validation:Minimum=1",
+                        "type": "integer"
+                    },
+                    "tools": {
+                        "description": "Tools restricts the limit to tools/call requests for the given tools.\nWhen empty, the limit is not restricted by tool.\n+optional",
+                        "items": {
+                            "type": "string"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    }
+                },
+                "type": "object"
+            },
+            "registry.EnvVar": {
+                "properties": {
+                    "default": {
+                        "description": "Default is the value to use if the environment variable is not explicitly provided\nOnly used for non-required variables",
+                        "type": "string"
+                    },
+                    "description": {
+                        "description": "Description is a human-readable explanation of the variable's purpose",
+                        "type": "string"
+                    },
+                    "name": {
+                        "description": "Name is the environment variable name (e.g., API_KEY)",
+                        "type": "string"
+                    },
+                    "required": {
+                        "description": "Required indicates whether this environment variable must be provided\nIf true and not provided via command line or secrets, the user will be prompted for a value",
+                        "type": "boolean"
+                    },
+                    "secret": {
+                        "description": "Secret indicates whether this environment variable contains sensitive information\nIf true, the value will be stored as a secret rather than as a plain environment variable",
+                        "type": "boolean"
+                    }
+                },
+                "type": "object"
+            },
+            "registry.Group": {
+                "properties": {
+                    "description": {
+                        "description": "Description is a human-readable description of the group's purpose and functionality",
+                        "type": "string"
+                    },
+                    "name": {
+                        "description": "Name is the identifier for the group, used when referencing the group in commands",
+                        "type": "string"
+                    },
+                    "remote_servers": {
                         "additionalProperties": {
                             "$ref": "#/components/schemas/registry.RemoteServerMetadata"
                         },
@@ -744,6 +1259,41 @@ const docTemplate = `{
                 },
                 "type": "object"
             },
+            "runner.RestartPolicy": {
+                "description": "RestartPolicy defines how the proxy process restarts the workload when it exits.\nWhen nil, the default restart policy applies.",
+                "properties": {
+                    "initial_backoff": {
+                        "description": "InitialBackoff is the delay before the first restart (e.g. \"5s\")",
+                        "type": "string"
+                    },
+                    "max_backoff": {
+                        "description": "MaxBackoff is the upper bound of the delay between restarts (e.g. \"1m\")",
+                        "type": "string"
+                    },
+                    "max_retries": {
+                        "description": "MaxRetries is the maximum number of consecutive restarts. 0 means unlimited.",
+                        "type": "integer"
+                    },
+                    "policy": {
+                        "$ref": "#/components/schemas/runner.RestartPolicyName"
+                    }
+                },
+                "type": "object"
+            },
+            "runner.RestartPolicyName": {
+                "description": "Policy is the restart policy (never, on-failure or always)",
+                "enum": [
+                    "never",
+                    "on-failure",
+                    "always"
+                ],
+                "type": "string",
+                "x-enum-varnames": [
+                    "RestartPolicyNever",
+                    "RestartPolicyOnFailure",
+                    "RestartPolicyAlways"
+                ]
+            },
             "runner.RunConfig": {
                 "properties": {
                     "audit_config": {
@@ -787,6 +1337,9 @@ const docTemplate = `{
                         "description": "Debug indicates whether debug mode is enabled",
                         "type": "boolean"
                     },
+                    "embedded_auth_server_config": {
+                        "$ref": "#/components/schemas/authserver.RunConfig"
+                    },
                     "endpoint_prefix": {
                         "description": "EndpointPrefix is an explicit prefix to prepend to SSE endpoint URLs.\nThis is used to handle path-based ingress routing scenarios.",
                         "type": "string"
@@ -829,6 +1382,9 @@ const docTemplate = `{
                         "description": "K8sPodTemplatePatch is a JSON string to patch the Kubernetes pod template\nOnly applicable when using Kubernetes runtime",
                         "type": "string"
                     },
+                    "learn_base_profile": {
+                        "$ref": "#/components/schemas/permissions.Profile"
+                    },
                     "middleware_configs": {
                         "description": "MiddlewareConfigs contains the list of middleware to apply to the transport\nand the configuration for each middleware.",
                         "items": {
@@ -858,6 +1414,9 @@ const docTemplate = `{
                     "proxy_mode": {
                         "$ref": "#/components/schemas/types.ProxyMode"
                     },
+                    "rate_limit_config": {
+                        "$ref": "#/components/schemas/ratelimit.Config"
+                    },
                     "remote_auth_config": {
                         "$ref": "#/components/schemas/remote.Config"
                     },
@@ -865,6 +1424,12 @@ const docTemplate = `{
                         "description": "RemoteURL is the URL of the remote MCP server (if running remotely)",
                         "type": "string"
                     },
+                    "response_cache_config": {
+                        "$ref": "#/components/schemas/mcp.ResponseCacheConfig"
+                    },
+                    "restart_policy": {
+                        "$ref": "#/components/schemas/runner.RestartPolicy"
+                    },
                     "schema_version": {
                         "description": "SchemaVersion is the version of the RunConfig schema",
                         "type": "string"
@@ -877,6 +1442,9 @@ const docTemplate = `{
                         "type": "array",
                         "uniqueItems": false
                     },
+                    "session_storage": {
+                        "$ref": "#/components/schemas/session.StorageConfig"
+                    },
                     "target_host": {
                         "description": "TargetHost is the host to forward traffic to (only applicable to SSE transport)",
                         "type": "string"
@@ -979,6 +1547,84 @@ const docTemplate = `{
                 },
                 "type": "object"
             },
+            "session.RedisConfig": {
+                "description": "Redis configures the Redis/Valkey backend when Type is \"redis\".",
+                "properties": {
+                    "address": {
+                        "description": "Address is the Redis server address (host:port).",
+                        "type": "string"
+                    },
+                    "db": {
+                        "description": "DB is the Redis database number.",
+                        "type": "integer"
+                    },
+                    "key_prefix": {
+                        "description": "KeyPrefix is prepended to every session key. Defaults to DefaultRedisKeyPrefix.",
+                        "type": "string"
+                    },
+                    "password_env": {
+                        "description": "PasswordEnv is the name of the environment variable containing the Redis password.",
+                        "type": "string"
+                    },
+                    "tls": {
+                        "description": "TLS enables TLS for the Redis connection.",
+                        "type": "boolean"
+                    }
+                },
+                "type": "object"
+            },
+            "session.StorageConfig": {
+                "description": "SessionStorage configures where the proxy stores MCP sessions.\nUse a networked backend such as Redis to share sessions between proxy replicas.\nOnly applies to SSE and streamable HTTP transports.",
+                "properties": {
+                    "redis": {
+                        "$ref": "#/components/schemas/session.RedisConfig"
+                    },
+                    "type": {
+                        "description": "Type is the storage backend: \"local\" (default) or \"redis\".",
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "storage.RunConfig": {
+                "description": "Storage configures where the authorization server keeps its state. Defaults to memory.",
+                "properties": {
+                    "sql": {
+                        "$ref": "#/components/schemas/storage.SQLRunConfig"
+                    },
+                    "type": {
+                        "description": "Type specifies the storage backend type: \"memory\" (default) or \"sql\".",
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "storage.SQLRunConfig": {
+                "description": "SQL configures the database when Type is \"sql\".",
+                "properties": {
+                    "dialect": {
+                        "description": "Dialect is the SQL dialect of the database: \"sqlite\" or \"postgres\".",
+                        "type": "string"
+                    },
+                    "driver": {
+                        "description": "Driver overrides the database/sql driver name.",
+                        "type": "string"
+                    },
+                    "dsn": {
+                        "description": "DSN is the data source name, e.g. a SQLite file path. Use DSNEnv for DSNs containing credentials.",
+                        "type": "string"
+                    },
+                    "dsn_env": {
+                        "description": "DSNEnv is the name of the environment variable containing the DSN. Takes precedence over DSN.",
+                        "type": "string"
+                    },
+                    "encryption_key_file": {
+                        "description": "EncryptionKeyFile is the path to a file containing the key material used to\nencrypt upstream tokens at rest. It must contain at least 32 bytes.",
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
             "telemetry.Config": {
                 "description": "DEPRECATED: Middleware configuration.\nTelemetryConfig contains the OpenTelemetry configuration",
                 "properties": {
@@ -1174,6 +1820,20 @@ const docTemplate = `{
                 },
                 "type": "object"
             },
+            "v1.auditEventListResponse": {
+                "description": "Response containing a list of audit events",
+                "properties": {
+                    "events": {
+                        "description": "Events are the audit events, sorted by time",
+                        "items": {
+                            "$ref": "#/components/schemas/query.Record"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    }
+                },
+                "type": "object"
+            },
             "v1.bulkClientRequest": {
                 "properties": {
                     "groups": {
@@ -1712,6 +2372,37 @@ const docTemplate = `{
                 },
                 "type": "object"
             },
+            "v1.replayAuditEventRequest": {
+                "description": "Request to replay a recorded tool call against a sandbox workload",
+                "properties": {
+                    "target": {
+                        "description": "Target is the name of the sandbox workload to replay the call against",
+                        "type": "string"
+                    },
+                    "workloads": {
+                        "description": "Workloads are the workloads whose audit events are searched for the event (default: all workloads)",
+                        "items": {
+                            "type": "string"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    }
+                },
+                "type": "object"
+            },
+            "v1.replayAuditEventResponse": {
+                "description": "Result of a replayed tool call",
+                "properties": {
+                    "event": {
+                        "$ref": "#/components/schemas/query.Record"
+                    },
+                    "result": {
+                        "description": "Result is the result of the tool call on the target workload",
+                        "type": "object"
+                    }
+                },
+                "type": "object"
+            },
             "v1.secretKeyResponse": {
                 "description": "Secret key information",
                 "properties": {
@@ -1929,8 +2620,20 @@ const docTemplate = `{
             "v1.workloadStatusResponse": {
                 "description": "Response containing workload status information",
                 "properties": {
+                    "last_restart_at": {
+                        "description": "Time of the last restart of the workload, if any",
+                        "type": "string"
+                    },
+                    "restarts": {
+                        "description": "Number of times the workload was restarted after exiting unexpectedly",
+                        "type": "integer"
+                    },
                     "status": {
                         "$ref": "#/components/schemas/runtime.WorkloadStatus"
+                    },
+                    "status_context": {
+                        "description": "Additional context about the status, such as the exit code of a restarted workload",
+                        "type": "string"
                     }
                 },
                 "type": "object"
@@ -1968,49 +2671,246 @@ const docTemplate = `{
                 ]
             }
         },
-        "/api/v1beta/clients": {
+        "/api/v1beta/audit/events": {
             "get": {
-                "description": "List all registered clients in ToolHive",
-                "responses": {
-                    "200": {
-                        "content": {
-                            "application/json": {
-                                "schema": {
-                                    "items": {
-                                        "$ref": "#/components/schemas/client.RegisteredClient"
-                                    },
-                                    "type": "array"
-                                }
-                            }
-                        },
-                        "description": "OK"
-                    }
-                },
-                "summary": "List all clients",
-                "tags": [
-                    "clients"
-                ]
-            },
-            "post": {
-                "description": "Register a new client with ToolHive",
-                "requestBody": {
-                    "content": {
-                        "application/json": {
-                            "schema": {
-                                "oneOf": [
-                                    {
-                                        "type": "object"
-                                    },
-                                    {
-                                        "$ref": "#/components/schemas/v1.createClientRequest",
-                                        "summary": "client",
-                                        "description": "Client to register"
-                                    }
-                                ]
-                            }
+                "description": "Search the audit events recorded for workloads, and export them as JSON or CSV.\nMultiple values are given by repeating a parameter or separating the values with commas.",
+                "parameters": [
+                    {
+                        "description": "Only include the events of these workloads (default: all workloads)",
+                        "in": "query",
+                        "name": "workload",
+                        "schema": {
+                            "type": "string"
                         }
                     },
-                    "description": "Client to register",
+                    {
+                        "description": "Only include the events of this subject, e.g. a user or client name",
+                        "in": "query",
+                        "name": "subject",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Only include the events of these types, e.g. mcp_tool_call",
+                        "in": "query",
+                        "name": "type",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Only include the events with these outcomes, e.g. denied",
+                        "in": "query",
+                        "name": "outcome",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Only include the calls of this tool",
+                        "in": "query",
+                        "name": "tool",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Only include the events after this time (RFC 3339 time, date or duration)",
+                        "in": "query",
+                        "name": "since",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Only include the events before this time (RFC 3339 time, date or duration)",
+                        "in": "query",
+                        "name": "until",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Only include the most recent events (default: 100, maximum: 1000)",
+                        "in": "query",
+                        "name": "limit",
+                        "schema": {
+                            "type": "integer"
+                        }
+                    },
+                    {
+                        "description": "Response format (json or csv)",
+                        "in": "query",
+                        "name": "format",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "content": {
+                            "text/csv": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/v1.auditEventListResponse"
+                                }
+                            }
+                        },
+                        "description": "OK"
+                    },
+                    "400": {
+                        "content": {
+                            "text/csv": {
+                                "schema": {
+                                    "type": "string"
+                                }
+                            }
+                        },
+                        "description": "Bad Request"
+                    },
+                    "404": {
+                        "content": {
+                            "text/csv": {
+                                "schema": {
+                                    "type": "string"
+                                }
+                            }
+                        },
+                        "description": "Not Found"
+                    }
+                },
+                "summary": "List audit events",
+                "tags": [
+                    "audit"
+                ]
+            }
+        },
+        "/api/v1beta/audit/events/{id}/replay": {
+            "post": {
+                "description": "Replay a tool call recorded in an audit event against a sandbox workload, e.g. to reproduce an incident.\nThe request is only recorded when the audit configuration sets includeRequestData.",
+                "parameters": [
+                    {
+                        "description": "Audit event ID",
+                        "in": "path",
+                        "name": "id",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "requestBody": {
+                    "content": {
+                        "application/json": {
+                            "schema": {
+                                "oneOf": [
+                                    {
+                                        "type": "object"
+                                    },
+                                    {
+                                        "$ref": "#/components/schemas/v1.replayAuditEventRequest",
+                                        "summary": "request",
+                                        "description": "Replay request"
+                                    }
+                                ]
+                            }
+                        }
+                    },
+                    "description": "Replay request",
+                    "required": true
+                },
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/v1.replayAuditEventResponse"
+                                }
+                            }
+                        },
+                        "description": "OK"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "type": "string"
+                                }
+                            }
+                        },
+                        "description": "Bad Request"
+                    },
+                    "404": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "type": "string"
+                                }
+                            }
+                        },
+                        "description": "Not Found"
+                    },
+                    "409": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "type": "string"
+                                }
+                            }
+                        },
+                        "description": "Target workload not running"
+                    }
+                },
+                "summary": "Replay a recorded tool call",
+                "tags": [
+                    "audit"
+                ]
+            }
+        },
+        "/api/v1beta/clients": {
+            "get": {
+                "description": "List all registered clients in ToolHive",
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "items": {
+                                        "$ref": "#/components/schemas/client.RegisteredClient"
+                                    },
+                                    "type": "array"
+                                }
+                            }
+                        },
+                        "description": "OK"
+                    }
+                },
+                "summary": "List all clients",
+                "tags": [
+                    "clients"
+                ]
+            },
+            "post": {
+                "description": "Register a new client with ToolHive",
+                "requestBody": {
+                    "content": {
+                        "application/json": {
+                            "schema": {
+                                "oneOf": [
+                                    {
+                                        "type": "object"
+                                    },
+                                    {
+                                        "$ref": "#/components/schemas/v1.createClientRequest",
+                                        "summary": "client",
+                                        "description": "Client to register"
+                                    }
+                                ]
+                            }
+                        }
+                    },
+                    "description": "Client to register",
                     "required": true
                 },
                 "responses": {
@@ -2257,12 +3157,191 @@ const docTemplate = `{
                         "content": {
                             "application/json": {
                                 "schema": {
-                                    "$ref": "#/components/schemas/v1.groupListResponse"
+                                    "$ref": "#/components/schemas/v1.groupListResponse"
+                                }
+                            }
+                        },
+                        "description": "OK"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "type": "string"
+                                }
+                            }
+                        },
+                        "description": "Internal Server Error"
+                    }
+                },
+                "summary": "List all groups",
+                "tags": [
+                    "groups"
+                ]
+            },
+            "post": {
+                "description": "Create a new group with the specified name",
+                "requestBody": {
+                    "content": {
+                        "application/json": {
+                            "schema": {
+                                "oneOf": [
+                                    {
+                                        "type": "object"
+                                    },
+                                    {
+                                        "$ref": "#/components/schemas/v1.createGroupRequest",
+                                        "summary": "group",
+                                        "description": "Group creation request"
+                                    }
+                                ]
+                            }
+                        }
+                    },
+                    "description": "Group creation request",
+                    "required": true
+                },
+                "responses": {
+                    "201": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/v1.createGroupResponse"
+                                }
+                            }
+                        },
+                        "description": "Created"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "type": "string"
+                                }
+                            }
+                        },
+                        "description": "Bad Request"
+                    },
+                    "409": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "type": "string"
+                                }
+                            }
+                        },
+                        "description": "Conflict"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "type": "string"
+                                }
+                            }
+                        },
+                        "description": "Internal Server Error"
+                    }
+                },
+                "summary": "Create a new group",
+                "tags": [
+                    "groups"
+                ]
+            }
+        },
+        "/api/v1beta/groups/{name}": {
+            "delete": {
+                "description": "Delete a group by name.",
+                "parameters": [
+                    {
+                        "description": "Group name",
+                        "in": "path",
+                        "name": "name",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Delete all workloads in the group (default: false, moves workloads to default group)",
+                        "in": "query",
+                        "name": "with-workloads",
+                        "schema": {
+                            "type": "boolean"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "type": "string"
+                                }
+                            }
+                        },
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "type": "string"
+                                }
+                            }
+                        },
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "type": "string"
+                                }
+                            }
+                        },
+                        "description": "Internal Server Error"
+                    }
+                },
+                "summary": "Delete a group",
+                "tags": [
+                    "groups"
+                ]
+            },
+            "get": {
+                "description": "Get details of a specific group",
+                "parameters": [
+                    {
+                        "description": "Group name",
+                        "in": "path",
+                        "name": "name",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/groups.Group"
                                 }
                             }
                         },
                         "description": "OK"
                     },
+                    "404": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "type": "string"
+                                }
+                            }
+                        },
+                        "description": "Not Found"
+                    },
                     "500": {
                         "content": {
                             "application/json": {
@@ -2274,45 +3353,38 @@ const docTemplate = `{
                         "description": "Internal Server Error"
                     }
                 },
-                "summary": "List all groups",
+                "summary": "Get group details",
                 "tags": [
                     "groups"
                 ]
-            },
-            "post": {
-                "description": "Create a new group with the specified name",
-                "requestBody": {
-                    "content": {
-                        "application/json": {
-                            "schema": {
-                                "oneOf": [
-                                    {
-                                        "type": "object"
-                                    },
-                                    {
-                                        "$ref": "#/components/schemas/v1.createGroupRequest",
-                                        "summary": "group",
-                                        "description": "Group creation request"
-                                    }
-                                ]
-                            }
+            }
+        },
+        "/api/v1beta/groups/{name}/defaults": {
+            "delete": {
+                "description": "Remove the defaults merged into the workloads of a group",
+                "parameters": [
+                    {
+                        "description": "Group name",
+                        "in": "path",
+                        "name": "name",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
                         }
-                    },
-                    "description": "Group creation request",
-                    "required": true
-                },
+                    }
+                ],
                 "responses": {
-                    "201": {
+                    "204": {
                         "content": {
                             "application/json": {
                                 "schema": {
-                                    "$ref": "#/components/schemas/v1.createGroupResponse"
+                                    "type": "string"
                                 }
                             }
                         },
-                        "description": "Created"
+                        "description": "No Content"
                     },
-                    "400": {
+                    "404": {
                         "content": {
                             "application/json": {
                                 "schema": {
@@ -2320,9 +3392,9 @@ const docTemplate = `{
                                 }
                             }
                         },
-                        "description": "Bad Request"
+                        "description": "Not Found"
                     },
-                    "409": {
+                    "500": {
                         "content": {
                             "application/json": {
                                 "schema": {
@@ -2330,9 +3402,9 @@ const docTemplate = `{
                                 }
                             }
                         },
-                        "description": "Conflict"
+                        "description": "Internal Server Error"
                     },
-                    "500": {
+                    "501": {
                         "content": {
                             "application/json": {
                                 "schema": {
@@ -2340,18 +3412,16 @@ const docTemplate = `{
                                 }
                             }
                         },
-                        "description": "Internal Server Error"
+                        "description": "Not Implemented"
                     }
                 },
-                "summary": "Create a new group",
+                "summary": "Delete group defaults",
                 "tags": [
                     "groups"
                 ]
-            }
-        },
-        "/api/v1beta/groups/{name}": {
-            "delete": {
-                "description": "Delete a group by name.",
+            },
+            "get": {
+                "description": "Get the defaults merged into the workloads of a group",
                 "parameters": [
                     {
                         "description": "Group name",
@@ -2361,26 +3431,18 @@ const docTemplate = `{
                         "schema": {
                             "type": "string"
                         }
-                    },
-                    {
-                        "description": "Delete all workloads in the group (default: false, moves workloads to default group)",
-                        "in": "query",
-                        "name": "with-workloads",
-                        "schema": {
-                            "type": "boolean"
-                        }
                     }
                 ],
                 "responses": {
-                    "204": {
+                    "200": {
                         "content": {
                             "application/json": {
                                 "schema": {
-                                    "type": "string"
+                                    "$ref": "#/components/schemas/groups.Defaults"
                                 }
                             }
                         },
-                        "description": "No Content"
+                        "description": "OK"
                     },
                     "404": {
                         "content": {
@@ -2403,13 +3465,13 @@ const docTemplate = `{
                         "description": "Internal Server Error"
                     }
                 },
-                "summary": "Delete a group",
+                "summary": "Get group defaults",
                 "tags": [
                     "groups"
                 ]
             },
-            "get": {
-                "description": "Get details of a specific group",
+            "put": {
+                "description": "Replace the defaults merged into the workloads of a group.",
                 "parameters": [
                     {
                         "description": "Group name",
@@ -2421,17 +3483,47 @@ const docTemplate = `{
                         }
                     }
                 ],
+                "requestBody": {
+                    "content": {
+                        "application/json": {
+                            "schema": {
+                                "oneOf": [
+                                    {
+                                        "type": "object"
+                                    },
+                                    {
+                                        "$ref": "#/components/schemas/groups.Defaults",
+                                        "summary": "defaults",
+                                        "description": "Group defaults"
+                                    }
+                                ]
+                            }
+                        }
+                    },
+                    "description": "Group defaults",
+                    "required": true
+                },
                 "responses": {
                     "200": {
                         "content": {
                             "application/json": {
                                 "schema": {
-                                    "$ref": "#/components/schemas/groups.Group"
+                                    "$ref": "#/components/schemas/groups.Defaults"
                                 }
                             }
                         },
                         "description": "OK"
                     },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "type": "string"
+                                }
+                            }
+                        },
+                        "description": "Bad Request"
+                    },
                     "404": {
                         "content": {
                             "application/json": {
@@ -2451,9 +3543,19 @@ const docTemplate = `{
                             }
                         },
                         "description": "Internal Server Error"
+                    },
+                    "501": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "type": "string"
+                                }
+                            }
+                        },
+                        "description": "Not Implemented"
                     }
                 },
-                "summary": "Get group details",
+                "summary": "Set group defaults",
                 "tags": [
                     "groups"
                 ]
@@ -3521,6 +4623,68 @@ const docTemplate = `{
                 ]
             }
         },
+        "/api/v1beta/workloads/{name}/check-update": {
+            "post": {
+                "description": "Check if the registry has a newer image for the image reference of a running workload,\nand record the result in the image_update field of the workload",
+                "parameters": [
+                    {
+                        "description": "Workload name",
+                        "in": "path",
+                        "name": "name",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/core.ImageUpdate"
+                                }
+                            }
+                        },
+                        "description": "OK"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "type": "string"
+                                }
+                            }
+                        },
+                        "description": "Bad Request"
+                    },
+                    "404": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "type": "string"
+                                }
+                            }
+                        },
+                        "description": "Not Found"
+                    },
+                    "501": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "type": "string"
+                                }
+                            }
+                        },
+                        "description": "Not Implemented"
+                    }
+                },
+                "summary": "Check for a newer workload image",
+                "tags": [
+                    "workloads"
+                ]
+            }
+        },
         "/api/v1beta/workloads/{name}/edit": {
             "post": {
                 "description": "Update an existing workload configuration",
@@ -3885,6 +5049,76 @@ const docTemplate = `{
                 ]
             }
         },
+        "/api/v1beta/workloads/{name}/upgrade": {
+            "post": {
+                "description": "Verify the provenance of the image the image reference of a workload points to,\npull it and recreate the workload with the same configuration",
+                "parameters": [
+                    {
+                        "description": "Workload name",
+                        "in": "path",
+                        "name": "name",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Image verification mode (warn, enabled, disabled)",
+                        "in": "query",
+                        "name": "image_verification",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "type": "string"
+                                }
+                            }
+                        },
+                        "description": "Accepted"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "type": "string"
+                                }
+                            }
+                        },
+                        "description": "Bad Request"
+                    },
+                    "404": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "type": "string"
+                                }
+                            }
+                        },
+                        "description": "Not Found"
+                    },
+                    "501": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "type": "string"
+                                }
+                            }
+                        },
+                        "description": "Not Implemented"
+                    }
+                },
+                "summary": "Upgrade a workload image",
+                "tags": [
+                    "workloads"
+                ]
+            }
+        },
         "/health": {
             "get": {
                 "description": "Check if the API is healthy",
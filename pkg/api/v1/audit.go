@@ -0,0 +1,219 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/mark3labs/mcp-go/mcp"
+
+	apierrors "github.com/stacklok/toolhive/pkg/api/errors"
+	"github.com/stacklok/toolhive/pkg/audit/query"
+	thverrors "github.com/stacklok/toolhive/pkg/errors"
+	"github.com/stacklok/toolhive/pkg/workloads"
+)
+
+const (
+	// defaultAuditEventLimit is the number of audit events listed when no limit is given
+	defaultAuditEventLimit = 100
+	// maxAuditEventLimit is the maximum number of audit events listed in a response
+	maxAuditEventLimit = 1000
+)
+
+// AuditRoutes defines the routes for the audit API.
+type AuditRoutes struct {
+	workloadManager workloads.Manager
+}
+
+// AuditRouter creates a new router for the audit API.
+func AuditRouter(workloadManager workloads.Manager) http.Handler {
+	routes := AuditRoutes{
+		workloadManager: workloadManager,
+	}
+
+	r := chi.NewRouter()
+	r.Get("/events", apierrors.ErrorHandler(routes.listAuditEvents))
+	r.Post("/events/{id}/replay", apierrors.ErrorHandler(routes.replayAuditEvent))
+	return r
+}
+
+// listAuditEvents
+//
+//	@Summary		List audit events
+//	@Description	Search the audit events recorded for workloads, and export them as JSON or CSV.
+//	@Description	Multiple values are given by repeating a parameter or separating the values with commas.
+//	@Tags			audit
+//	@Produce		json
+//	@Produce		text/csv
+//	@Param			workload	query		string	false	"Only include the events of these workloads (default: all workloads)"
+//	@Param			subject		query		string	false	"Only include the events of this subject, e.g. a user or client name"
+//	@Param			type		query		string	false	"Only include the events of these types, e.g. mcp_tool_call"
+//	@Param			outcome		query		string	false	"Only include the events with these outcomes, e.g. denied"
+//	@Param			tool		query		string	false	"Only include the calls of this tool"
+//	@Param			since		query		string	false	"Only include the events after this time (RFC 3339 time, date or duration)"
+//	@Param			until		query		string	false	"Only include the events before this time (RFC 3339 time, date or duration)"
+//	@Param			limit		query		int		false	"Only include the most recent events (default: 100, maximum: 1000)"
+//	@Param			format		query		string	false	"Response format (json or csv)"
+//	@Success		200			{object}	auditEventListResponse
+//	@Failure		400			{string}	string	"Bad Request"
+//	@Failure		404			{string}	string	"Not Found"
+//	@Router			/api/v1beta/audit/events [get]
+func (s *AuditRoutes) listAuditEvents(w http.ResponseWriter, r *http.Request) error {
+	params := r.URL.Query()
+	format := params.Get("format")
+	if format != "" && format != "json" && format != "csv" {
+		return thverrors.WithCode(fmt.Errorf("invalid format %q: must be json or csv", format), http.StatusBadRequest)
+	}
+
+	filter, err := auditFilterFromQuery(params)
+	if err != nil {
+		return thverrors.WithCode(err, http.StatusBadRequest)
+	}
+
+	records, err := query.ReadWorkloads(r.Context(), splitQueryValues(params["workload"]), filter)
+	if err != nil {
+		return err // ErrInvalidWorkloadName (400) and ErrRunConfigNotFound (404) already have status codes
+	}
+
+	if format == "csv" {
+		w.Header().Set("Content-Type", "text/csv")
+		return query.WriteCSV(w, records)
+	}
+	if records == nil {
+		records = []*query.Record{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(auditEventListResponse{Events: records}); err != nil {
+		return fmt.Errorf("failed to marshal audit events: %w", err)
+	}
+	return nil
+}
+
+// replayAuditEvent
+//
+//	@Summary		Replay a recorded tool call
+//	@Description	Replay a tool call recorded in an audit event against a sandbox workload, e.g. to reproduce an incident.
+//	@Description	The request is only recorded when the audit configuration sets includeRequestData.
+//	@Tags			audit
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string						true	"Audit event ID"
+//	@Param			request	body		replayAuditEventRequest		true	"Replay request"
+//	@Success		200		{object}	replayAuditEventResponse
+//	@Failure		400		{string}	string	"Bad Request"
+//	@Failure		404		{string}	string	"Not Found"
+//	@Failure		409		{string}	string	"Target workload not running"
+//	@Router			/api/v1beta/audit/events/{id}/replay [post]
+func (s *AuditRoutes) replayAuditEvent(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	auditID := chi.URLParam(r, "id")
+
+	var req replayAuditEventRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return thverrors.WithCode(fmt.Errorf("invalid request body: %w", err), http.StatusBadRequest)
+	}
+	if req.Target == "" {
+		return thverrors.WithCode(fmt.Errorf("target workload is required"), http.StatusBadRequest)
+	}
+
+	records, err := query.ReadWorkloads(ctx, req.Workloads, query.Filter{AuditID: auditID, Limit: 1})
+	if err != nil {
+		return err
+	}
+	record, err := query.Find(records, auditID)
+	if err != nil {
+		return err // ErrEventNotFound already has 404 status code
+	}
+
+	result, err := query.ReplayOnWorkload(ctx, s.workloadManager, record, req.Target)
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(replayAuditEventResponse{Event: record, Result: result}); err != nil {
+		return fmt.Errorf("failed to marshal replay result: %w", err)
+	}
+	return nil
+}
+
+// auditFilterFromQuery builds the filter of audit events from the query parameters
+func auditFilterFromQuery(params map[string][]string) (query.Filter, error) {
+	get := func(key string) string {
+		if values := params[key]; len(values) > 0 {
+			return values[0]
+		}
+		return ""
+	}
+
+	filter := query.Filter{
+		Subject:    get("subject"),
+		EventTypes: splitQueryValues(params["type"]),
+		Outcomes:   splitQueryValues(params["outcome"]),
+		Tool:       get("tool"),
+	}
+	now := time.Now()
+	var err error
+	if since := get("since"); since != "" {
+		if filter.Since, err = query.ParseTime(since, now); err != nil {
+			return filter, err
+		}
+	}
+	if until := get("until"); until != "" {
+		if filter.Until, err = query.ParseTime(until, now); err != nil {
+			return filter, err
+		}
+	}
+	filter.Limit = defaultAuditEventLimit
+	if limit := get("limit"); limit != "" {
+		if filter.Limit, err = strconv.Atoi(limit); err != nil || filter.Limit < 1 || filter.Limit > maxAuditEventLimit {
+			return filter, fmt.Errorf("invalid limit %q: must be between 1 and %d", limit, maxAuditEventLimit)
+		}
+	}
+	return filter, nil
+}
+
+// splitQueryValues returns the values of a repeated query parameter, also split on commas
+func splitQueryValues(values []string) []string {
+	var result []string
+	for _, value := range values {
+		for _, part := range strings.Split(value, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				result = append(result, part)
+			}
+		}
+	}
+	return result
+}
+
+// auditEventListResponse represents the response for listing audit events
+//
+//	@Description	Response containing a list of audit events
+type auditEventListResponse struct {
+	// Events are the audit events, sorted by time
+	Events []*query.Record `json:"events"`
+}
+
+// replayAuditEventRequest represents the request to replay a recorded tool call
+//
+//	@Description	Request to replay a recorded tool call against a sandbox workload
+type replayAuditEventRequest struct {
+	// Target is the name of the sandbox workload to replay the call against
+	Target string `json:"target"`
+	// Workloads are the workloads whose audit events are searched for the event (default: all workloads)
+	Workloads []string `json:"workloads,omitempty"`
+}
+
+// replayAuditEventResponse represents the result of a replayed tool call
+//
+//	@Description	Result of a replayed tool call
+type replayAuditEventResponse struct {
+	// Event is the replayed audit event
+	Event *query.Record `json:"event"`
+	// Result is the result of the tool call on the target workload
+	Result *mcp.CallToolResult `json:"result" swaggertype:"object"`
+}
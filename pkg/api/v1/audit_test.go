@@ -0,0 +1,72 @@
+package v1
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditFilterFromQuery(t *testing.T) {
+	t.Parallel()
+
+	params, err := url.ParseQuery("subject=alice&type=mcp_tool_call,mcp_tools_list&outcome=denied&outcome=error" +
+		"&tool=fetch&since=2025-06-01T00:00:00Z&limit=10")
+	require.NoError(t, err)
+
+	filter, err := auditFilterFromQuery(params)
+	require.NoError(t, err)
+	assert.Equal(t, "alice", filter.Subject)
+	assert.Equal(t, []string{"mcp_tool_call", "mcp_tools_list"}, filter.EventTypes)
+	assert.Equal(t, []string{"denied", "error"}, filter.Outcomes)
+	assert.Equal(t, "fetch", filter.Tool)
+	assert.True(t, filter.Since.Equal(time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)))
+	assert.True(t, filter.Until.IsZero())
+	assert.Equal(t, 10, filter.Limit)
+
+	// The number of events is bounded by default
+	filter, err = auditFilterFromQuery(url.Values{})
+	require.NoError(t, err)
+	assert.Equal(t, defaultAuditEventLimit, filter.Limit)
+
+	for _, invalid := range []string{"since=yesterday", "until=-1h", "limit=-1", "limit=0", "limit=ten", "limit=1001"} {
+		params, err := url.ParseQuery(invalid)
+		require.NoError(t, err)
+		_, err = auditFilterFromQuery(params)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestAuditRouter_InvalidRequests(t *testing.T) {
+	t.Parallel()
+
+	router := AuditRouter(nil)
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+	}{
+		{name: "invalid format", method: http.MethodGet, path: "/events?format=xml"},
+		{name: "invalid limit", method: http.MethodGet, path: "/events?limit=ten"},
+		{name: "limit above maximum", method: http.MethodGet, path: "/events?limit=5000"},
+		{name: "invalid workload name", method: http.MethodGet, path: "/events?workload=../etc"},
+		{name: "invalid body", method: http.MethodPost, path: "/events/a1/replay", body: "{"},
+		{name: "missing target", method: http.MethodPost, path: "/events/a1/replay", body: "{}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+			assert.Equal(t, http.StatusBadRequest, resp.Code, resp.Body.String())
+		})
+	}
+}
@@ -39,6 +39,9 @@ func GroupsRouter(groupManager groups.Manager, workloadManager workloads.Manager
 	r.Post("/", apierrors.ErrorHandler(routes.createGroup))
 	r.Get("/{name}", apierrors.ErrorHandler(routes.getGroup))
 	r.Delete("/{name}", apierrors.ErrorHandler(routes.deleteGroup))
+	r.Get("/{name}/defaults", apierrors.ErrorHandler(routes.getGroupDefaults))
+	r.Put("/{name}/defaults", apierrors.ErrorHandler(routes.setGroupDefaults))
+	r.Delete("/{name}/defaults", apierrors.ErrorHandler(routes.deleteGroupDefaults))
 
 	return r
 }
@@ -226,6 +229,125 @@ func (s *GroupsRoutes) deleteGroup(w http.ResponseWriter, r *http.Request) error
 	return nil
 }
 
+// getGroupDefaults
+//
+//	@Summary		Get group defaults
+//	@Description	Get the defaults merged into the workloads of a group
+//	@Tags			groups
+//	@Produce		json
+//	@Param			name	path		string	true	"Group name"
+//	@Success		200		{object}	groups.Defaults
+//	@Failure		404		{string}	string	"Not Found"
+//	@Failure		500		{string}	string	"Internal Server Error"
+//	@Router			/api/v1beta/groups/{name}/defaults [get]
+func (s *GroupsRoutes) getGroupDefaults(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	name := chi.URLParam(r, "name")
+
+	// Validate group name
+	if err := validation.ValidateGroupName(name); err != nil {
+		return thverrors.WithCode(
+			fmt.Errorf("invalid group name: %w", err),
+			http.StatusBadRequest,
+		)
+	}
+
+	group, err := s.groupManager.Get(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	defaults := group.Defaults
+	if defaults == nil {
+		defaults = &groups.Defaults{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(defaults); err != nil {
+		return fmt.Errorf("failed to marshal group defaults: %w", err)
+	}
+	return nil
+}
+
+// setGroupDefaults
+//
+//	@Summary		Set group defaults
+//	@Description	Replace the defaults merged into the workloads of a group.
+//	The defaults apply to workloads created or updated after they are set,
+//	and the settings of a workload take precedence over them.
+//	@Tags			groups
+//	@Accept			json
+//	@Produce		json
+//	@Param			name		path		string			true	"Group name"
+//	@Param			defaults	body		groups.Defaults	true	"Group defaults"
+//	@Success		200			{object}	groups.Defaults
+//	@Failure		400			{string}	string	"Bad Request"
+//	@Failure		404			{string}	string	"Not Found"
+//	@Failure		500			{string}	string	"Internal Server Error"
+//	@Failure		501			{string}	string	"Not Implemented"
+//	@Router			/api/v1beta/groups/{name}/defaults [put]
+func (s *GroupsRoutes) setGroupDefaults(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	name := chi.URLParam(r, "name")
+
+	// Validate group name
+	if err := validation.ValidateGroupName(name); err != nil {
+		return thverrors.WithCode(
+			fmt.Errorf("invalid group name: %w", err),
+			http.StatusBadRequest,
+		)
+	}
+
+	var defaults groups.Defaults
+	if err := json.NewDecoder(r.Body).Decode(&defaults); err != nil {
+		return thverrors.WithCode(
+			fmt.Errorf("invalid request body: %w", err),
+			http.StatusBadRequest,
+		)
+	}
+
+	if err := s.groupManager.SetDefaults(ctx, name, &defaults); err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(&defaults); err != nil {
+		return fmt.Errorf("failed to marshal group defaults: %w", err)
+	}
+	return nil
+}
+
+// deleteGroupDefaults
+//
+//	@Summary		Delete group defaults
+//	@Description	Remove the defaults merged into the workloads of a group
+//	@Tags			groups
+//	@Param			name	path		string	true	"Group name"
+//	@Success		204		{string}	string	"No Content"
+//	@Failure		404		{string}	string	"Not Found"
+//	@Failure		500		{string}	string	"Internal Server Error"
+//	@Failure		501		{string}	string	"Not Implemented"
+//	@Router			/api/v1beta/groups/{name}/defaults [delete]
+func (s *GroupsRoutes) deleteGroupDefaults(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	name := chi.URLParam(r, "name")
+
+	// Validate group name
+	if err := validation.ValidateGroupName(name); err != nil {
+		return thverrors.WithCode(
+			fmt.Errorf("invalid group name: %w", err),
+			http.StatusBadRequest,
+		)
+	}
+
+	if err := s.groupManager.SetDefaults(ctx, name, nil); err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
 // handleWorkloadsForGroupDeletion handles workloads when deleting a group
 func (s *GroupsRoutes) handleWorkloadsForGroupDeletion(
 	ctx context.Context,
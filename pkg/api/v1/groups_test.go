@@ -206,6 +206,65 @@ func TestGroupsRouter(t *testing.T) {
 			expectedStatus: http.StatusNoContent,
 			expectedBody:   "",
 		},
+		{
+			name:   "get group defaults",
+			method: "GET",
+			path:   "/testgroup/defaults",
+			setupMock: func(gm *groupsmocks.MockManager, _ *workloadsmocks.MockManager) {
+				gm.EXPECT().Get(gomock.Any(), "testgroup").Return(&groups.Group{
+					Name:     "testgroup",
+					Defaults: &groups.Defaults{EnvVars: map[string]string{"LOG_LEVEL": "info"}},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"env_vars": {"LOG_LEVEL": "info"}}`,
+		},
+		{
+			name:   "get group without defaults",
+			method: "GET",
+			path:   "/testgroup/defaults",
+			setupMock: func(gm *groupsmocks.MockManager, _ *workloadsmocks.MockManager) {
+				gm.EXPECT().Get(gomock.Any(), "testgroup").Return(&groups.Group{Name: "testgroup"}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{}`,
+		},
+		{
+			name:   "set group defaults",
+			method: "PUT",
+			path:   "/testgroup/defaults",
+			body:   `{"secrets": ["token,target=API_TOKEN"]}`,
+			setupMock: func(gm *groupsmocks.MockManager, _ *workloadsmocks.MockManager) {
+				gm.EXPECT().
+					SetDefaults(gomock.Any(), "testgroup", &groups.Defaults{Secrets: []string{"token,target=API_TOKEN"}}).
+					Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"secrets": ["token,target=API_TOKEN"]}`,
+		},
+		{
+			name:   "set invalid group defaults",
+			method: "PUT",
+			path:   "/testgroup/defaults",
+			body:   `{"secrets": ["token"]}`,
+			setupMock: func(gm *groupsmocks.MockManager, _ *workloadsmocks.MockManager) {
+				gm.EXPECT().
+					SetDefaults(gomock.Any(), "testgroup", gomock.Any()).
+					Return(fmt.Errorf("%w: invalid secret", groups.ErrInvalidGroupDefaults))
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "invalid group defaults",
+		},
+		{
+			name:   "delete group defaults",
+			method: "DELETE",
+			path:   "/testgroup/defaults",
+			setupMock: func(gm *groupsmocks.MockManager, _ *workloadsmocks.MockManager) {
+				gm.EXPECT().SetDefaults(gomock.Any(), "testgroup", nil).Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+			expectedBody:   "",
+		},
 	}
 
 	for _, tt := range tests {
@@ -266,6 +266,13 @@ func (s *WorkloadService) BuildFullRunConfig(
 		options = append(options, runner.WithExistingPort(existingPort))
 	}
 
+	// Merge the defaults of the group into the workload
+	group, err := s.groupManager.Get(ctx, groupName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get group '%s': %w", groupName, err)
+	}
+	options = append(options, runner.WithGroupDefaults(group.Defaults))
+
 	// Determine transport type
 	transportType := "streamable-http"
 	if req.Transport != "" {
@@ -288,6 +295,8 @@ func (s *WorkloadService) BuildFullRunConfig(
 			req.Name,
 			transportType,
 			s.appConfig.DisableUsageMetrics,
+			nil, // responseCacheConfig - not supported via API yet
+			nil, // rateLimitConfig - not supported via API yet
 		),
 	)
 
@@ -2,6 +2,7 @@ package v1
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/stacklok/toolhive/pkg/container/runtime"
 	"github.com/stacklok/toolhive/pkg/core"
@@ -25,6 +26,12 @@ type workloadListResponse struct {
 type workloadStatusResponse struct {
 	// Current status of the workload
 	Status runtime.WorkloadStatus `json:"status"`
+	// Additional context about the status, such as the exit code of a restarted workload
+	StatusContext string `json:"status_context,omitempty"`
+	// Number of times the workload was restarted after exiting unexpectedly
+	Restarts int `json:"restarts"`
+	// Time of the last restart of the workload, if any
+	LastRestartAt *time.Time `json:"last_restart_at,omitempty"`
 }
 
 // updateRequest represents the request to update an existing workload
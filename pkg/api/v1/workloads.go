@@ -12,9 +12,12 @@ import (
 	"github.com/stacklok/toolhive/pkg/container/runtime"
 	thverrors "github.com/stacklok/toolhive/pkg/errors"
 	"github.com/stacklok/toolhive/pkg/groups"
+	"github.com/stacklok/toolhive/pkg/logger"
 	"github.com/stacklok/toolhive/pkg/runner"
+	"github.com/stacklok/toolhive/pkg/runner/retriever"
 	"github.com/stacklok/toolhive/pkg/validation"
 	"github.com/stacklok/toolhive/pkg/workloads"
+	"github.com/stacklok/toolhive/pkg/workloads/imageupdates"
 	wt "github.com/stacklok/toolhive/pkg/workloads/types"
 )
 
@@ -69,6 +72,8 @@ func WorkloadRouter(
 	r.Get("/{name}/logs", apierrors.ErrorHandler(routes.getLogsForWorkload))
 	r.Get("/{name}/proxy-logs", apierrors.ErrorHandler(routes.getProxyLogsForWorkload))
 	r.Get("/{name}/export", apierrors.ErrorHandler(routes.exportWorkload))
+	r.Post("/{name}/check-update", apierrors.ErrorHandler(routes.checkWorkloadUpdate))
+	r.Post("/{name}/upgrade", apierrors.ErrorHandler(routes.upgradeWorkload))
 	r.Delete("/{name}", apierrors.ErrorHandler(routes.deleteWorkload))
 
 	return r
@@ -539,7 +544,10 @@ func (s *WorkloadRoutes) getWorkloadStatus(w http.ResponseWriter, r *http.Reques
 	}
 
 	response := workloadStatusResponse{
-		Status: workload.Status,
+		Status:        workload.Status,
+		StatusContext: workload.StatusContext,
+		Restarts:      workload.Restarts,
+		LastRestartAt: workload.LastRestartAt,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -576,3 +584,95 @@ func (*WorkloadRoutes) exportWorkload(w http.ResponseWriter, r *http.Request) er
 	}
 	return nil
 }
+
+// checkWorkloadUpdate
+//
+//	@Summary		Check for a newer workload image
+//	@Description	Check if the registry has a newer image for the image reference of a running workload,
+//	@Description	and record the result in the image_update field of the workload
+//	@Tags			workloads
+//	@Produce		json
+//	@Param			name	path		string	true	"Workload name"
+//	@Success		200		{object}	core.ImageUpdate
+//	@Failure		400		{string}	string	"Bad Request"
+//	@Failure		404		{string}	string	"Not Found"
+//	@Failure		501		{string}	string	"Not Implemented"
+//	@Router			/api/v1beta/workloads/{name}/check-update [post]
+func (s *WorkloadRoutes) checkWorkloadUpdate(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	name := chi.URLParam(r, "name")
+
+	if _, err := s.workloadManager.GetWorkload(ctx, name); err != nil {
+		return err // ErrWorkloadNotFound (404) or ErrInvalidWorkloadName (400) already have status codes
+	}
+
+	checker, err := imageupdates.NewCheckerFromRuntime(ctx, s.containerRuntime)
+	if err != nil {
+		return thverrors.WithCode(err, http.StatusNotImplemented)
+	}
+	update, err := checker.CheckWorkload(ctx, name)
+	if err != nil {
+		return err // ErrNoRegistryImage already has 400 status code
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(update); err != nil {
+		return fmt.Errorf("failed to marshal image update: %w", err)
+	}
+	return nil
+}
+
+// upgradeWorkload
+//
+//	@Summary		Upgrade a workload image
+//	@Description	Verify the provenance of the image the image reference of a workload points to,
+//	@Description	pull it and recreate the workload with the same configuration
+//	@Tags			workloads
+//	@Param			name				path		string	true	"Workload name"
+//	@Param			image_verification	query		string	false	"Image verification mode (warn, enabled, disabled)"
+//	@Success		202					{string}	string	"Accepted"
+//	@Failure		400					{string}	string	"Bad Request"
+//	@Failure		404					{string}	string	"Not Found"
+//	@Failure		501					{string}	string	"Not Implemented"
+//	@Router			/api/v1beta/workloads/{name}/upgrade [post]
+func (s *WorkloadRoutes) upgradeWorkload(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	name := chi.URLParam(r, "name")
+
+	verification := r.URL.Query().Get("image_verification")
+	switch verification {
+	case "":
+		verification = retriever.VerifyImageWarn
+	case retriever.VerifyImageWarn, retriever.VerifyImageEnabled, retriever.VerifyImageDisabled:
+	default:
+		return thverrors.WithCode(
+			fmt.Errorf("invalid image verification mode: %s", verification),
+			http.StatusBadRequest,
+		)
+	}
+
+	if _, err := s.workloadManager.GetWorkload(ctx, name); err != nil {
+		return err // ErrWorkloadNotFound (404) or ErrInvalidWorkloadName (400) already have status codes
+	}
+	runConfig, err := runner.LoadState(ctx, name)
+	if err != nil {
+		return err // ErrRunConfigNotFound (404) already has status code
+	}
+	if err := imageupdates.ValidateUpgrade(runConfig); err != nil {
+		return err // ErrNoRegistryImage already has 400 status code
+	}
+
+	checker, err := imageupdates.NewCheckerFromRuntime(ctx, s.containerRuntime)
+	if err != nil {
+		return thverrors.WithCode(err, http.StatusNotImplemented)
+	}
+
+	// Pulling the image can take a while, so the upgrade is a background operation
+	go func() {
+		if err := checker.Upgrade(context.Background(), s.workloadManager, name, verification); err != nil {
+			logger.Errorf("Failed to upgrade workload %s: %v", name, err)
+		}
+	}()
+	w.WriteHeader(http.StatusAccepted)
+	return nil
+}
@@ -7,6 +7,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/stretchr/testify/assert"
@@ -18,6 +19,7 @@ import (
 	"github.com/stacklok/toolhive/pkg/container/runtime"
 	runtimemocks "github.com/stacklok/toolhive/pkg/container/runtime/mocks"
 	"github.com/stacklok/toolhive/pkg/core"
+	"github.com/stacklok/toolhive/pkg/groups"
 	groupsmocks "github.com/stacklok/toolhive/pkg/groups/mocks"
 	"github.com/stacklok/toolhive/pkg/logger"
 	regtypes "github.com/stacklok/toolhive/pkg/registry/registry"
@@ -94,6 +96,98 @@ func TestGetWorkload(t *testing.T) {
 	}
 }
 
+func TestGetWorkloadStatus(t *testing.T) {
+	t.Parallel()
+
+	logger.Initialize()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	lastRestart := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	mockWorkloadManager := workloadsmocks.NewMockManager(ctrl)
+	mockWorkloadManager.EXPECT().GetWorkload(gomock.Any(), "flaky").Return(core.Workload{
+		Name:          "flaky",
+		Status:        runtime.WorkloadStatusStarting,
+		StatusContext: "Container exited with code 1, restarting in 5s",
+		Restarts:      3,
+		LastRestartAt: &lastRestart,
+	}, nil)
+
+	routes := &WorkloadRoutes{workloadManager: mockWorkloadManager}
+
+	req := httptest.NewRequest("GET", "/flaky/status", nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("name", "flaky")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+	w := httptest.NewRecorder()
+	apierrors.ErrorHandler(routes.getWorkloadStatus).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{
+		"status": "starting",
+		"status_context": "Container exited with code 1, restarting in 5s",
+		"restarts": 3,
+		"last_restart_at": "2025-01-02T03:04:05Z"
+	}`, w.Body.String())
+}
+
+func TestUpgradeWorkload(t *testing.T) {
+	t.Parallel()
+
+	logger.Initialize()
+
+	tests := []struct {
+		name           string
+		query          string
+		setupMock      func(*workloadsmocks.MockManager)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "invalid image verification mode",
+			query:          "?image_verification=sometimes",
+			setupMock:      func(*workloadsmocks.MockManager) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "invalid image verification mode",
+		},
+		{
+			name:  "workload not found",
+			query: "?image_verification=" + retriever.VerifyImageEnabled,
+			setupMock: func(wm *workloadsmocks.MockManager) {
+				wm.EXPECT().GetWorkload(gomock.Any(), "fetch").
+					Return(core.Workload{}, runtime.ErrWorkloadNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "workload not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			mockWorkloadManager := workloadsmocks.NewMockManager(ctrl)
+			tt.setupMock(mockWorkloadManager)
+
+			routes := &WorkloadRoutes{workloadManager: mockWorkloadManager}
+
+			req := httptest.NewRequest("POST", "/fetch/upgrade"+tt.query, nil)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("name", "fetch")
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+			w := httptest.NewRecorder()
+			apierrors.ErrorHandler(routes.upgradeWorkload).ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tt.expectedBody)
+		})
+	}
+}
+
 func TestCreateWorkload(t *testing.T) {
 	t.Parallel()
 
@@ -129,6 +223,7 @@ func TestCreateWorkload(t *testing.T) {
 			setupMock: func(_ *testing.T, wm *workloadsmocks.MockManager, _ *runtimemocks.MockRuntime, gm *groupsmocks.MockManager) {
 				wm.EXPECT().DoesWorkloadExist(gomock.Any(), "test-workload").Return(false, nil)
 				gm.EXPECT().Exists(gomock.Any(), "default").Return(true, nil).AnyTimes()
+				gm.EXPECT().Get(gomock.Any(), "default").Return(&groups.Group{Name: "default"}, nil).AnyTimes()
 			},
 			expectedStatus: http.StatusBadRequest,
 			expectedBody:   "Invalid proxy_mode",
@@ -141,6 +236,7 @@ func TestCreateWorkload(t *testing.T) {
 
 				wm.EXPECT().DoesWorkloadExist(gomock.Any(), "test-workload").Return(false, nil)
 				gm.EXPECT().Exists(gomock.Any(), "default").Return(true, nil)
+				gm.EXPECT().Get(gomock.Any(), "default").Return(&groups.Group{Name: "default"}, nil).AnyTimes()
 				wm.EXPECT().RunWorkloadDetached(gomock.Any(), gomock.Any()).
 					DoAndReturn(func(_ context.Context, runConfig *runner.RunConfig) error {
 						assert.Equal(t, toolsFilter, runConfig.ToolsFilter, "Tools filter should be equal")
@@ -150,6 +246,30 @@ func TestCreateWorkload(t *testing.T) {
 			expectedStatus: http.StatusCreated,
 			expectedBody:   "test-workload",
 		},
+		{
+			name:        "with group defaults",
+			requestBody: `{"name": "test-workload", "image": "test-image", "env_vars": {"REGION": "us"}}`,
+			setupMock: func(_ *testing.T, wm *workloadsmocks.MockManager, _ *runtimemocks.MockRuntime, gm *groupsmocks.MockManager) {
+				wm.EXPECT().DoesWorkloadExist(gomock.Any(), "test-workload").Return(false, nil)
+				gm.EXPECT().Exists(gomock.Any(), "default").Return(true, nil)
+				gm.EXPECT().Get(gomock.Any(), "default").Return(&groups.Group{
+					Name: "default",
+					Defaults: &groups.Defaults{
+						EnvVars: map[string]string{"LOG_LEVEL": "info", "REGION": "eu"},
+						Secrets: []string{"shared-token,target=API_TOKEN"},
+					},
+				}, nil)
+				wm.EXPECT().RunWorkloadDetached(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, runConfig *runner.RunConfig) error {
+						assert.Equal(t, "info", runConfig.EnvVars["LOG_LEVEL"], "Group environment variables should be merged")
+						assert.Equal(t, "us", runConfig.EnvVars["REGION"], "Workload environment variables should take precedence")
+						assert.Equal(t, []string{"shared-token,target=API_TOKEN"}, runConfig.Secrets, "Group secrets should be merged")
+						return nil
+					})
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   "test-workload",
+		},
 		{
 			name:        "with tool override",
 			requestBody: `{"name": "test-workload", "image": "test-image", "tools_override": {"actual-tool": {"name": "override-tool", "description": "Overridden tool"}}}`,
@@ -158,6 +278,7 @@ func TestCreateWorkload(t *testing.T) {
 
 				wm.EXPECT().DoesWorkloadExist(gomock.Any(), "test-workload").Return(false, nil)
 				gm.EXPECT().Exists(gomock.Any(), "default").Return(true, nil)
+				gm.EXPECT().Get(gomock.Any(), "default").Return(&groups.Group{Name: "default"}, nil).AnyTimes()
 				wm.EXPECT().RunWorkloadDetached(gomock.Any(), gomock.Any()).
 					DoAndReturn(func(_ context.Context, runConfig *runner.RunConfig) error {
 						assert.Equal(t, toolsFilter, runConfig.ToolsFilter, "Tools filter should be equal")
@@ -175,6 +296,7 @@ func TestCreateWorkload(t *testing.T) {
 
 				wm.EXPECT().DoesWorkloadExist(gomock.Any(), "test-workload").Return(false, nil)
 				gm.EXPECT().Exists(gomock.Any(), "default").Return(true, nil)
+				gm.EXPECT().Get(gomock.Any(), "default").Return(&groups.Group{Name: "default"}, nil).AnyTimes()
 				wm.EXPECT().RunWorkloadDetached(gomock.Any(), gomock.Any()).
 					DoAndReturn(func(_ context.Context, runConfig *runner.RunConfig) error {
 						assert.Equal(t, toolsFilter, runConfig.ToolsFilter, "Tools filter should be equal")
@@ -190,6 +312,7 @@ func TestCreateWorkload(t *testing.T) {
 			setupMock: func(_ *testing.T, wm *workloadsmocks.MockManager, _ *runtimemocks.MockRuntime, gm *groupsmocks.MockManager) {
 				wm.EXPECT().DoesWorkloadExist(gomock.Any(), "test-workload").Return(false, nil)
 				gm.EXPECT().Exists(gomock.Any(), "default").Return(true, nil)
+				gm.EXPECT().Get(gomock.Any(), "default").Return(&groups.Group{Name: "default"}, nil).AnyTimes()
 			},
 			expectedStatus: http.StatusBadRequest,
 			expectedBody:   "tool override for actual-tool must have either Name or Description set",
@@ -282,6 +405,7 @@ func TestUpdateWorkload(t *testing.T) {
 				wm.EXPECT().GetWorkload(gomock.Any(), "test-workload").
 					Return(core.Workload{Name: "test-workload"}, nil)
 				gm.EXPECT().Exists(gomock.Any(), "default").Return(true, nil)
+				gm.EXPECT().Get(gomock.Any(), "default").Return(&groups.Group{Name: "default"}, nil).AnyTimes()
 				wm.EXPECT().UpdateWorkload(gomock.Any(), "test-workload", gomock.Any()).
 					Return(nil, fmt.Errorf("stop failed"))
 			},
@@ -296,6 +420,7 @@ func TestUpdateWorkload(t *testing.T) {
 				wm.EXPECT().GetWorkload(gomock.Any(), "test-workload").
 					Return(core.Workload{Name: "test-workload"}, nil)
 				gm.EXPECT().Exists(gomock.Any(), "default").Return(true, nil)
+				gm.EXPECT().Get(gomock.Any(), "default").Return(&groups.Group{Name: "default"}, nil).AnyTimes()
 				wm.EXPECT().UpdateWorkload(gomock.Any(), "test-workload", gomock.Any()).
 					Return(nil, fmt.Errorf("delete failed"))
 			},
@@ -313,6 +438,7 @@ func TestUpdateWorkload(t *testing.T) {
 				wm.EXPECT().GetWorkload(gomock.Any(), "test-workload").
 					Return(core.Workload{Name: "test-workload"}, nil)
 				gm.EXPECT().Exists(gomock.Any(), "default").Return(true, nil)
+				gm.EXPECT().Get(gomock.Any(), "default").Return(&groups.Group{Name: "default"}, nil).AnyTimes()
 				wm.EXPECT().UpdateWorkload(gomock.Any(), "test-workload", gomock.Any()).
 					DoAndReturn(func(_ context.Context, _ string, runConfig *runner.RunConfig) (*errgroup.Group, error) {
 						assert.Equal(t, toolsFilter, runConfig.ToolsFilter, "Tools filter should be equal")
@@ -339,6 +465,7 @@ func TestUpdateWorkload(t *testing.T) {
 				wm.EXPECT().GetWorkload(gomock.Any(), "test-workload").
 					Return(core.Workload{Name: "test-workload"}, nil)
 				gm.EXPECT().Exists(gomock.Any(), "default").Return(true, nil)
+				gm.EXPECT().Get(gomock.Any(), "default").Return(&groups.Group{Name: "default"}, nil).AnyTimes()
 				wm.EXPECT().UpdateWorkload(gomock.Any(), "test-workload", gomock.Any()).
 					DoAndReturn(func(_ context.Context, _ string, runConfig *runner.RunConfig) (*errgroup.Group, error) {
 						assert.Equal(t, toolsFilter, runConfig.ToolsFilter, "Tools filter should be equal")
@@ -365,6 +492,7 @@ func TestUpdateWorkload(t *testing.T) {
 				wm.EXPECT().GetWorkload(gomock.Any(), "test-workload").
 					Return(core.Workload{Name: "test-workload"}, nil)
 				gm.EXPECT().Exists(gomock.Any(), "default").Return(true, nil)
+				gm.EXPECT().Get(gomock.Any(), "default").Return(&groups.Group{Name: "default"}, nil).AnyTimes()
 				wm.EXPECT().UpdateWorkload(gomock.Any(), "test-workload", gomock.Any()).
 					DoAndReturn(func(_ context.Context, _ string, runConfig *runner.RunConfig) (*errgroup.Group, error) {
 						assert.Equal(t, toolsFilter, runConfig.ToolsFilter, "Tools filter should be equal")
@@ -383,6 +511,7 @@ func TestUpdateWorkload(t *testing.T) {
 				wm.EXPECT().GetWorkload(gomock.Any(), "test-workload").
 					Return(core.Workload{Name: "test-workload"}, nil)
 				gm.EXPECT().Exists(gomock.Any(), "default").Return(true, nil)
+				gm.EXPECT().Get(gomock.Any(), "default").Return(&groups.Group{Name: "default"}, nil).AnyTimes()
 				// The validation error should occur before UpdateWorkload is called
 			},
 			expectedStatus: http.StatusBadRequest,
@@ -463,6 +592,7 @@ func TestUpdateWorkload_PortReuse(t *testing.T) {
 				wm.EXPECT().GetWorkload(gomock.Any(), "test-workload").
 					Return(core.Workload{Name: "test-workload", Port: 8080}, nil)
 				gm.EXPECT().Exists(gomock.Any(), "default").Return(true, nil)
+				gm.EXPECT().Get(gomock.Any(), "default").Return(&groups.Group{Name: "default"}, nil).AnyTimes()
 				wm.EXPECT().UpdateWorkload(gomock.Any(), "test-workload", gomock.Any()).
 					DoAndReturn(func(_ context.Context, _ string, runConfig *runner.RunConfig) (*errgroup.Group, error) {
 						assert.Equal(t, 8080, runConfig.Port, "Port should be reused from existing workload")
@@ -483,6 +613,7 @@ func TestUpdateWorkload_PortReuse(t *testing.T) {
 				wm.EXPECT().GetWorkload(gomock.Any(), "test-workload").
 					Return(core.Workload{Name: "test-workload", Port: 8080}, nil)
 				gm.EXPECT().Exists(gomock.Any(), "default").Return(true, nil)
+				gm.EXPECT().Get(gomock.Any(), "default").Return(&groups.Group{Name: "default"}, nil).AnyTimes()
 				wm.EXPECT().UpdateWorkload(gomock.Any(), "test-workload", gomock.Any()).
 					DoAndReturn(func(_ context.Context, _ string, runConfig *runner.RunConfig) (*errgroup.Group, error) {
 						assert.Equal(t, 9090, runConfig.Port, "Port should be set to explicitly requested port")
@@ -503,6 +634,7 @@ func TestUpdateWorkload_PortReuse(t *testing.T) {
 				wm.EXPECT().GetWorkload(gomock.Any(), "test-workload").
 					Return(core.Workload{Name: "test-workload", Port: 8080}, nil)
 				gm.EXPECT().Exists(gomock.Any(), "default").Return(true, nil)
+				gm.EXPECT().Get(gomock.Any(), "default").Return(&groups.Group{Name: "default"}, nil).AnyTimes()
 				wm.EXPECT().UpdateWorkload(gomock.Any(), "test-workload", gomock.Any()).
 					DoAndReturn(func(_ context.Context, _ string, runConfig *runner.RunConfig) (*errgroup.Group, error) {
 						assert.Equal(t, 8080, runConfig.Port, "Port should remain the same")
@@ -523,6 +655,7 @@ func TestUpdateWorkload_PortReuse(t *testing.T) {
 				wm.EXPECT().GetWorkload(gomock.Any(), "test-workload").
 					Return(core.Workload{Name: "test-workload", Port: 8080}, nil)
 				gm.EXPECT().Exists(gomock.Any(), "default").Return(true, nil)
+				gm.EXPECT().Get(gomock.Any(), "default").Return(&groups.Group{Name: "default"}, nil).AnyTimes()
 				wm.EXPECT().UpdateWorkload(gomock.Any(), "test-workload", gomock.Any()).
 					DoAndReturn(func(_ context.Context, _ string, runConfig *runner.RunConfig) (*errgroup.Group, error) {
 						assert.Equal(t, 8080, runConfig.Port, "Port should default to existing port")
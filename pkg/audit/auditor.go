@@ -92,6 +92,9 @@ func NewAuditorWithTransport(config *Config, transportType string) (*Auditor, er
 // Close closes the underlying log writer if it implements io.Closer.
 // This should be called when the auditor is no longer needed to properly release resources.
 func (a *Auditor) Close() error {
+	if a.logWriter == os.Stdout {
+		return nil
+	}
 	if closer, ok := a.logWriter.(io.Closer); ok {
 		return closer.Close()
 	}
@@ -309,6 +312,8 @@ func (*Auditor) determineOutcome(statusCode int) string {
 		return OutcomeSuccess
 	case statusCode == 401 || statusCode == 403:
 		return OutcomeDenied
+	case statusCode == http.StatusTooManyRequests:
+		return OutcomeRateLimited
 	case statusCode >= 400 && statusCode < 500:
 		return OutcomeFailure
 	case statusCode >= 500:
@@ -319,9 +324,20 @@ func (*Auditor) determineOutcome(statusCode int) string {
 }
 
 // extractSource extracts source information from the HTTP request.
-func (a *Auditor) extractSource(r *http.Request) EventSource {
+func (*Auditor) extractSource(r *http.Request) EventSource {
+	return extractRequestSource(r)
+}
+
+// getClientIP extracts the client IP address from the request.
+func (*Auditor) getClientIP(r *http.Request) string {
+	return clientIPFromRequest(r)
+}
+
+// extractRequestSource extracts source information from the HTTP request.
+// This helper is shared by the auditors that log events of HTTP requests.
+func extractRequestSource(r *http.Request) EventSource {
 	// Get the client IP address
-	clientIP := a.getClientIP(r)
+	clientIP := clientIPFromRequest(r)
 
 	source := EventSource{
 		Type:  SourceTypeNetwork,
@@ -342,8 +358,8 @@ func (a *Auditor) extractSource(r *http.Request) EventSource {
 	return source
 }
 
-// getClientIP extracts the client IP address from the request.
-func (*Auditor) getClientIP(r *http.Request) string {
+// clientIPFromRequest extracts the client IP address from the request.
+func clientIPFromRequest(r *http.Request) string {
 	// Check X-Forwarded-For header first
 	if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
 		// Take the first IP in the list
@@ -403,6 +403,7 @@ func TestDetermineOutcome(t *testing.T) {
 		{403, OutcomeDenied},
 		{400, OutcomeFailure},
 		{404, OutcomeFailure},
+		{429, OutcomeRateLimited},
 		{499, OutcomeFailure},
 		{500, OutcomeError},
 		{503, OutcomeError},
@@ -2,6 +2,7 @@
 package audit
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -47,21 +48,62 @@ type Config struct {
 	// LogFile specifies the file path for audit logs. If empty, logs to stdout.
 	// +optional
 	LogFile string `json:"logFile,omitempty" yaml:"logFile,omitempty"`
+	// Sinks are additional destinations of audit events, such as syslog servers, HTTP collectors
+	// and OTLP collectors. Events are written to all the sinks, in addition to LogFile or stdout.
+	// +optional
+	Sinks []SinkConfig `json:"sinks,omitempty" yaml:"sinks,omitempty"`
+	// Integrity enables the tamper-evident mode, in which events are hash-chained
+	// and signed checkpoints are written periodically.
+	// +optional
+	Integrity *IntegrityConfig `json:"integrity,omitempty" yaml:"integrity,omitempty"`
 }
 
 // GetLogWriter creates and returns the appropriate io.Writer based on the configuration.
+// When sinks or the integrity mode are configured, the writer implements io.Closer to flush them.
 func (c *Config) GetLogWriter() (io.Writer, error) {
-	if c == nil || c.LogFile == "" {
+	if c == nil || (c.LogFile == "" && len(c.Sinks) == 0 && c.Integrity == nil) {
 		return os.Stdout, nil
 	}
 
-	// Clean the path to prevent directory traversal
-	file, err := os.OpenFile(filepath.Clean(c.LogFile), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
-	if err != nil {
-		return nil, fmt.Errorf("failed to open audit log file %s: %w", c.LogFile, err)
+	var logWriter io.Writer = os.Stdout
+	if c.LogFile != "" {
+		// Clean the path to prevent directory traversal
+		file, err := os.OpenFile(filepath.Clean(c.LogFile), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open audit log file %s: %w", c.LogFile, err)
+		}
+		logWriter = file
+	}
+	closeOnError := func(w io.Writer) {
+		if closer, ok := w.(io.Closer); ok && w != os.Stdout {
+			_ = closer.Close()
+		}
+	}
+
+	if len(c.Sinks) > 0 {
+		writer, err := newFanOutWriter(logWriter, c.Sinks)
+		if err != nil {
+			closeOnError(logWriter)
+			return nil, err
+		}
+		logWriter = writer
 	}
 
-	return file, nil
+	if c.Integrity != nil {
+		// The events sent to the sinks are chained too, so that they can be verified
+		signer, err := LoadSigningKey(context.Background(), c.Integrity.SigningKeySecret)
+		if err != nil {
+			closeOnError(logWriter)
+			return nil, err
+		}
+		writer, err := newChainWriter(logWriter, c.LogFile, c.Integrity, signer)
+		if err != nil {
+			closeOnError(logWriter)
+			return nil, err
+		}
+		logWriter = writer
+	}
+	return logWriter, nil
 }
 
 // DefaultConfig returns a default audit configuration.
@@ -201,5 +243,17 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	for i := range c.Sinks {
+		if err := c.Sinks[i].Validate(); err != nil {
+			return fmt.Errorf("invalid sink %d: %w", i, err)
+		}
+	}
+
+	if c.Integrity != nil {
+		if err := c.Integrity.Validate(); err != nil {
+			return fmt.Errorf("invalid integrity configuration: %w", err)
+		}
+	}
+
 	return nil
 }
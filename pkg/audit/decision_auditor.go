@@ -0,0 +1,205 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+
+	"github.com/stacklok/toolhive/pkg/auth"
+)
+
+// sourceKey is the context key for storing the source of the request being authorized
+type sourceKey struct{}
+
+// AuthzDecision describes an authorization decision logged by the DecisionAuditor.
+type AuthzDecision struct {
+	// Authorizer is the type of the authorizer that made the decision, e.g. "cedarv1".
+	Authorizer string
+	// Feature is the MCP feature of the request: tool, prompt or resource.
+	// It is empty for features/list requests.
+	Feature string
+	// Operation is the operation of the request: call, get, read or list.
+	Operation string
+	// ResourceID is the name of the tool or prompt, or the URI of the resource.
+	ResourceID string
+	// Arguments are the arguments of the request.
+	Arguments map[string]any
+	// Allowed reports whether the request was allowed.
+	Allowed bool
+	// Policies are the IDs of the policies that determined the decision.
+	Policies []string
+	// Err is the error of the evaluation, in which case the request is denied.
+	Err error
+	// Shadow is the decision of the shadow policies, if configured. Shadow decisions are not enforced.
+	Shadow *AuthzDecision
+}
+
+// DecisionAuditor provides audit logging for authorization decisions.
+// Unlike the HTTP middleware-based Auditor, it logs the decisions of requests
+// denied by the authorization middleware, along with the policies that matched.
+type DecisionAuditor struct {
+	auditLogger *slog.Logger
+	config      *Config
+	component   string
+	logWriter   io.Writer
+}
+
+// NewDecisionAuditor creates a new authorization decision auditor.
+// If config is nil, creates a default configuration with stdout logging.
+func NewDecisionAuditor(config *Config) (*DecisionAuditor, error) {
+	if config == nil {
+		config = DefaultConfig()
+	}
+
+	logWriter, err := config.GetLogWriter()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create log writer: %w", err)
+	}
+
+	// Use configured component or default to authz
+	component := config.Component
+	if component == "" {
+		component = "authz"
+	}
+
+	return &DecisionAuditor{
+		auditLogger: NewAuditLogger(logWriter),
+		config:      config,
+		component:   component,
+		logWriter:   logWriter,
+	}, nil
+}
+
+// Close closes the audit log file and flushes the audit sinks.
+func (d *DecisionAuditor) Close() error {
+	if d.logWriter == os.Stdout {
+		return nil
+	}
+	if closer, ok := d.logWriter.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// Middleware records the source of the requests, so that it is included in the decisions
+// logged while handling them.
+func (*DecisionAuditor) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), sourceKey{}, extractRequestSource(r))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// LogDecision logs an authorization decision.
+func (d *DecisionAuditor) LogDecision(ctx context.Context, decision *AuthzDecision) {
+	if !d.config.ShouldAuditEvent(EventTypeAuthzDecision) {
+		return
+	}
+
+	outcome := OutcomeDenied
+	if decision.Err != nil {
+		outcome = OutcomeError
+	} else if decision.Allowed {
+		outcome = OutcomeSuccess
+	}
+
+	event := NewAuditEvent(
+		EventTypeAuthzDecision,
+		d.extractSource(ctx),
+		outcome,
+		d.extractSubjects(ctx),
+		d.component,
+	)
+	event.WithTarget(decisionTarget(decision))
+
+	event.Metadata.Extra = decisionMetadata(decision)
+	if decision.Shadow != nil {
+		event.Metadata.Extra[MetadataExtraKeyShadow] = decisionMetadata(decision.Shadow)
+	}
+
+	// Add request arguments as data (if configured)
+	// Using same structure as HTTP auditor for consistency
+	if d.config.IncludeRequestData && decision.Arguments != nil {
+		data := map[string]any{
+			"request": decision.Arguments,
+		}
+		if dataBytes, err := json.Marshal(data); err == nil && len(dataBytes) <= d.config.MaxDataSize {
+			rawMsg := json.RawMessage(dataBytes)
+			event.WithData(&rawMsg)
+		}
+	}
+
+	event.LogTo(ctx, d.auditLogger, LevelAudit)
+}
+
+// decisionTarget returns the target of the request of an authorization decision.
+func decisionTarget(decision *AuthzDecision) map[string]string {
+	target := map[string]string{
+		TargetKeyOperation: decision.Operation,
+	}
+	if decision.Feature != "" {
+		target[TargetKeyType] = decision.Feature
+	} else {
+		target[TargetKeyType] = TargetTypeServer
+	}
+
+	switch {
+	case decision.ResourceID == "":
+	case decision.Feature == TargetTypeResource:
+		target[TargetKeyURI] = decision.ResourceID
+	default:
+		target[TargetKeyName] = decision.ResourceID
+	}
+	return target
+}
+
+// decisionMetadata returns the metadata of an authorization decision.
+func decisionMetadata(decision *AuthzDecision) map[string]any {
+	policies := decision.Policies
+	if policies == nil {
+		policies = []string{}
+	}
+	metadata := map[string]any{
+		MetadataExtraKeyAuthorizer: decision.Authorizer,
+		MetadataExtraKeyPolicies:   policies,
+		MetadataExtraKeyAllowed:    decision.Allowed && decision.Err == nil,
+	}
+	if decision.Err != nil {
+		metadata[MetadataExtraKeyError] = decision.Err.Error()
+	}
+	return metadata
+}
+
+// extractSource extracts source information from context.
+// The source is recorded by Middleware; decisions made outside of HTTP requests are local.
+func (*DecisionAuditor) extractSource(ctx context.Context) EventSource {
+	if source, ok := ctx.Value(sourceKey{}).(EventSource); ok {
+		return source
+	}
+	return EventSource{
+		Type:  SourceTypeLocal,
+		Value: "authz",
+		Extra: map[string]any{},
+	}
+}
+
+// extractSubjects extracts subject information from context.
+func (*DecisionAuditor) extractSubjects(ctx context.Context) map[string]string {
+	subjects := make(map[string]string)
+
+	// Extract user information from Identity
+	if identity, ok := auth.IdentityFromContext(ctx); ok {
+		subjects = extractSubjectsFromIdentity(identity)
+	}
+
+	// If no user found, set anonymous
+	if subjects[SubjectKeyUser] == "" {
+		subjects[SubjectKeyUser] = "anonymous"
+	}
+
+	return subjects
+}
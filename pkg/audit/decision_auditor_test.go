@@ -0,0 +1,192 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stacklok/toolhive/pkg/auth"
+)
+
+// createTestDecisionAuditor creates a DecisionAuditor for testing with captured output.
+func createTestDecisionAuditor(t *testing.T, config *Config) (*DecisionAuditor, *testLogWriter) {
+	t.Helper()
+
+	writer := &testLogWriter{}
+	auditor := &DecisionAuditor{
+		auditLogger: NewAuditLogger(writer),
+		config:      config,
+		component:   "authz",
+	}
+
+	return auditor, writer
+}
+
+func TestNewDecisionAuditor(t *testing.T) {
+	t.Parallel()
+
+	auditor, err := NewDecisionAuditor(nil)
+	require.NoError(t, err)
+	assert.Equal(t, "authz", auditor.component)
+	assert.NoError(t, auditor.Close())
+
+	auditor, err = NewDecisionAuditor(&Config{Component: "weather-authz"})
+	require.NoError(t, err)
+	assert.Equal(t, "weather-authz", auditor.component)
+}
+
+func TestDecisionAuditor_LogDecision(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		decision     *AuthzDecision
+		wantOutcome  string
+		wantTarget   map[string]any
+		wantMetadata map[string]any
+	}{
+		{
+			name: "allowed_tool_call",
+			decision: &AuthzDecision{
+				Authorizer: "cedarv1", Feature: "tool", Operation: "call", ResourceID: "weather",
+				Allowed: true, Policies: []string{"allow-weather"},
+			},
+			wantOutcome: OutcomeSuccess,
+			wantTarget:  map[string]any{TargetKeyType: TargetTypeTool, TargetKeyName: "weather", TargetKeyOperation: "call"},
+			wantMetadata: map[string]any{
+				MetadataExtraKeyAuthorizer: "cedarv1",
+				MetadataExtraKeyAllowed:    true,
+				MetadataExtraKeyPolicies:   []any{"allow-weather"},
+			},
+		},
+		{
+			name: "denied_resource_read",
+			decision: &AuthzDecision{
+				Authorizer: "cedarv1", Feature: "resource", Operation: "read", ResourceID: "file:///etc/passwd",
+			},
+			wantOutcome: OutcomeDenied,
+			wantTarget: map[string]any{
+				TargetKeyType: TargetTypeResource, TargetKeyURI: "file:///etc/passwd", TargetKeyOperation: "read",
+			},
+			wantMetadata: map[string]any{
+				MetadataExtraKeyAuthorizer: "cedarv1",
+				MetadataExtraKeyAllowed:    false,
+				MetadataExtraKeyPolicies:   []any{},
+			},
+		},
+		{
+			name: "error_with_shadow_decision",
+			decision: &AuthzDecision{
+				Authorizer: "opa", Feature: "prompt", Operation: "get", ResourceID: "greeting",
+				Err:    errors.New("policy aborted"),
+				Shadow: &AuthzDecision{Authorizer: "cedarv1", Allowed: true, Policies: []string{"policy0"}},
+			},
+			wantOutcome: OutcomeError,
+			wantTarget:  map[string]any{TargetKeyType: TargetTypePrompt, TargetKeyName: "greeting", TargetKeyOperation: "get"},
+			wantMetadata: map[string]any{
+				MetadataExtraKeyAuthorizer: "opa",
+				MetadataExtraKeyAllowed:    false,
+				MetadataExtraKeyPolicies:   []any{},
+				MetadataExtraKeyError:      "policy aborted",
+				MetadataExtraKeyShadow: map[string]any{
+					MetadataExtraKeyAuthorizer: "cedarv1",
+					MetadataExtraKeyAllowed:    true,
+					MetadataExtraKeyPolicies:   []any{"policy0"},
+				},
+			},
+		},
+		{
+			name:        "features_list",
+			decision:    &AuthzDecision{Authorizer: "cedarv1", Operation: "list", Allowed: true},
+			wantOutcome: OutcomeSuccess,
+			wantTarget:  map[string]any{TargetKeyType: TargetTypeServer, TargetKeyOperation: "list"},
+			wantMetadata: map[string]any{
+				MetadataExtraKeyAuthorizer: "cedarv1",
+				MetadataExtraKeyAllowed:    true,
+				MetadataExtraKeyPolicies:   []any{},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			auditor, writer := createTestDecisionAuditor(t, DefaultConfig())
+			ctx := auth.WithIdentity(context.Background(), &auth.Identity{Subject: "user-123", Email: "user@example.com"})
+
+			auditor.LogDecision(ctx, tt.decision)
+
+			require.Len(t, writer.logs, 1)
+			entry := parseLogEntry(t, writer.getLastLog())
+			assert.Equal(t, EventTypeAuthzDecision, entry["type"])
+			assert.Equal(t, "authz", entry["component"])
+			assert.Equal(t, tt.wantOutcome, entry["outcome"])
+			assert.Equal(t, tt.wantTarget, entry["target"])
+			assert.Equal(t, map[string]any{"extra": tt.wantMetadata}, entry["metadata"])
+
+			subjects, ok := entry["subjects"].(map[string]any)
+			require.True(t, ok, "subjects should be a map")
+			assert.Equal(t, "user-123", subjects[SubjectKeyUserID])
+			assert.Equal(t, "user@example.com", subjects[SubjectKeyUser])
+
+			source, ok := entry["source"].(map[string]any)
+			require.True(t, ok, "source should be a map")
+			assert.Equal(t, SourceTypeLocal, source["type"])
+		})
+	}
+}
+
+func TestDecisionAuditor_LogDecisionRequestData(t *testing.T) {
+	t.Parallel()
+
+	decision := &AuthzDecision{
+		Authorizer: "cedarv1", Feature: "tool", Operation: "call", ResourceID: "weather",
+		Arguments: map[string]any{"location": "Paris"}, Allowed: true,
+	}
+
+	auditor, writer := createTestDecisionAuditor(t, &Config{IncludeRequestData: true, MaxDataSize: 1024})
+	auditor.LogDecision(context.Background(), decision)
+	entry := parseLogEntry(t, writer.getLastLog())
+	assert.Equal(t, map[string]any{"request": map[string]any{"location": "Paris"}}, entry["data"])
+	subjects, ok := entry["subjects"].(map[string]any)
+	require.True(t, ok, "subjects should be a map")
+	assert.Equal(t, "anonymous", subjects[SubjectKeyUser])
+
+	// Arguments larger than the maximum data size are omitted
+	auditor, writer = createTestDecisionAuditor(t, &Config{IncludeRequestData: true, MaxDataSize: 8})
+	auditor.LogDecision(context.Background(), decision)
+	assert.NotContains(t, parseLogEntry(t, writer.getLastLog()), "data")
+
+	// Decisions are filtered by event type
+	auditor, writer = createTestDecisionAuditor(t, &Config{ExcludeEventTypes: []string{EventTypeAuthzDecision}})
+	auditor.LogDecision(context.Background(), decision)
+	assert.Empty(t, writer.logs)
+}
+
+func TestDecisionAuditor_Middleware(t *testing.T) {
+	t.Parallel()
+
+	auditor, writer := createTestDecisionAuditor(t, DefaultConfig())
+	handler := auditor.Middleware(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		auditor.LogDecision(r.Context(), &AuthzDecision{Authorizer: "cedarv1", Feature: "tool", Operation: "call"})
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+	req.RemoteAddr = "192.168.1.100:12345"
+	req.Header.Set("User-Agent", "test-client/1.0")
+	req.Header.Set("X-Request-ID", "req-123")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	entry := parseLogEntry(t, writer.getLastLog())
+	assert.Equal(t, map[string]any{
+		"type":  SourceTypeNetwork,
+		"value": "192.168.1.100",
+		"extra": map[string]any{SourceExtraKeyUserAgent: "test-client/1.0", SourceExtraKeyRequestID: "req-123"},
+	}, entry["source"])
+}
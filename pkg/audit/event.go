@@ -210,6 +210,8 @@ const (
 	OutcomeError = "error"
 	// OutcomeDenied indicates the event was denied (e.g., by authorization)
 	OutcomeDenied = "denied"
+	// OutcomeRateLimited indicates the event was rejected by rate limiting
+	OutcomeRateLimited = "rate_limited"
 )
 
 // Common source types
@@ -0,0 +1,565 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	authsecrets "github.com/stacklok/toolhive/pkg/auth/secrets"
+	"github.com/stacklok/toolhive/pkg/logger"
+)
+
+// EventTypeAuditCheckpoint is the type of the signed checkpoints of a hash-chained audit log
+const EventTypeAuditCheckpoint = "audit_checkpoint"
+
+// EventTypeAuditChainStart is the type of the signed records starting the hash chains written to stdout.
+// They reference the last event of the chain of the previous process, if known.
+const EventTypeAuditChainStart = "audit_chain_start"
+
+const (
+	// SigningKeyEnvVar is the environment variable holding the key signing the checkpoints. When set,
+	// it takes precedence over the secrets provider, which is not available in Kubernetes. The operator
+	// sets it from the Kubernetes Secret named by signingKeySecret.
+	SigningKeyEnvVar = "TOOLHIVE_AUDIT_SIGNING_KEY"
+
+	// SigningKeySecretKey is the key of the Kubernetes Secret holding the key signing the checkpoints
+	SigningKeySecretKey = "signingKey"
+)
+
+const (
+	defaultCheckpointEvents   = 100
+	defaultCheckpointInterval = time.Minute
+	// tailChunkSize is the size of the chunks read backwards to find the last line of a log file
+	tailChunkSize = 64 * 1024
+)
+
+// IntegrityConfig configures the tamper-evident mode of the audit log. Each event carries its
+// sequence number and the hash of the previous event, and checkpoints signing the hash of the
+// chain are written periodically, so that deleted, reordered and modified events are detected.
+// +kubebuilder:object:generate=true
+// +gendoc
+type IntegrityConfig struct {
+	// SigningKeySecret is the name of the secret holding the Ed25519 private key signing the
+	// checkpoints, either PEM encoded (PKCS #8) or as the base64 encoded seed.
+	// In Kubernetes, it is the name of a Secret in the same namespace, holding the key in its
+	// signingKey entry.
+	// +kubebuilder:validation:Required
+	SigningKeySecret string `json:"signingKeySecret" yaml:"signingKeySecret"`
+	// CheckpointEvents is the maximum number of events between two checkpoints.
+	// +kubebuilder:default=100
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	CheckpointEvents int `json:"checkpointEvents,omitempty" yaml:"checkpointEvents,omitempty"`
+	// CheckpointInterval is the maximum time between an event and the next checkpoint, e.g. "1m".
+	// +kubebuilder:default="1m"
+	// +optional
+	CheckpointInterval string `json:"checkpointInterval,omitempty" yaml:"checkpointInterval,omitempty"`
+	// ChainStateFile is a file keeping the position of the hash chain when events are written to
+	// stdout, where the chain cannot be resumed. Each process starts its chain with a signed record
+	// referencing the last event of the previous process, so that removed events are detected.
+	// Without it, chains are not linked. It is not used when events are written to a log file.
+	// +optional
+	ChainStateFile string `json:"chainStateFile,omitempty" yaml:"chainStateFile,omitempty"`
+}
+
+// Validate validates the integrity configuration.
+func (c *IntegrityConfig) Validate() error {
+	if c.SigningKeySecret == "" {
+		return errors.New("signingKeySecret is required")
+	}
+	if c.CheckpointEvents < 0 {
+		return errors.New("checkpointEvents cannot be negative")
+	}
+	if interval, err := parseOptionalDuration(c.CheckpointInterval, defaultCheckpointInterval); err != nil || interval <= 0 {
+		return fmt.Errorf("invalid checkpointInterval %q: must be a positive duration", c.CheckpointInterval)
+	}
+	return nil
+}
+
+// LoadSigningKey reads the Ed25519 private key signing the checkpoints from the SigningKeyEnvVar
+// environment variable if it is set, and from the secrets provider otherwise.
+func LoadSigningKey(ctx context.Context, secretName string) (ed25519.PrivateKey, error) {
+	value := os.Getenv(SigningKeyEnvVar)
+	if value == "" {
+		manager, err := authsecrets.GetSecretsManager()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get secrets provider: %w", err)
+		}
+		value, err = manager.GetSecret(ctx, secretName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get audit signing key %s: %w", secretName, err)
+		}
+	}
+	key, err := ParseSigningKey(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid audit signing key %s: %w", secretName, err)
+	}
+	return key, nil
+}
+
+// ParseSigningKey parses an Ed25519 private key, either PEM encoded (PKCS #8) or as the base64 encoded seed
+// or private key.
+func ParseSigningKey(value string) (ed25519.PrivateKey, error) {
+	value = strings.TrimSpace(value)
+	if block, _ := pem.Decode([]byte(value)); block != nil {
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		privateKey, ok := key.(ed25519.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("expected an Ed25519 key, found %T", key)
+		}
+		return privateKey, nil
+	}
+
+	data, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return nil, errors.New("key is neither PEM nor base64 encoded")
+	}
+	switch len(data) {
+	case ed25519.SeedSize:
+		return ed25519.NewKeyFromSeed(data), nil
+	case ed25519.PrivateKeySize:
+		return ed25519.PrivateKey(data), nil
+	default:
+		return nil, fmt.Errorf("expected a %d bytes seed or a %d bytes private key, found %d bytes",
+			ed25519.SeedSize, ed25519.PrivateKeySize, len(data))
+	}
+}
+
+// ParsePublicKey parses an Ed25519 public key, either PEM encoded (PKIX) or base64 encoded.
+func ParsePublicKey(data []byte) (ed25519.PublicKey, error) {
+	if block, _ := pem.Decode(data); block != nil {
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		publicKey, ok := key.(ed25519.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("expected an Ed25519 key, found %T", key)
+		}
+		return publicKey, nil
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil {
+		return nil, errors.New("key is neither PEM nor base64 encoded")
+	}
+	if len(decoded) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("expected a %d bytes public key, found %d bytes", ed25519.PublicKeySize, len(decoded))
+	}
+	return ed25519.PublicKey(decoded), nil
+}
+
+// KeyID returns the identifier of a public key recorded in the checkpoints it signs.
+func KeyID(publicKey ed25519.PublicKey) string {
+	sum := sha256.Sum256(publicKey)
+	return hex.EncodeToString(sum[:8])
+}
+
+// lineHash returns the hash of a line of the audit log, without its line break
+func lineHash(line []byte) string {
+	sum := sha256.Sum256(line)
+	return hex.EncodeToString(sum[:])
+}
+
+// chainStartPayload returns the message signed by a chain start record: the sequence number and the
+// hash of the last event of the previous chain, or 0 and an empty hash if it is unknown.
+func chainStartPayload(prevSeq uint64, prevHash string) []byte {
+	return []byte("toolhive-audit-chain-start:" + strconv.FormatUint(prevSeq, 10) + ":" + prevHash)
+}
+
+// checkpointPayload returns the message signed by a checkpoint: the sequence number and the hash
+// of the last event it covers. As each event includes the hash of the previous one, the signature
+// covers all the events of the chain up to that event.
+func checkpointPayload(seq uint64, hash string) []byte {
+	return []byte("toolhive-audit-checkpoint:" + strconv.FormatUint(seq, 10) + ":" + hash)
+}
+
+// chainLink holds the hash chain fields of a line of the audit log
+type chainLink struct {
+	Seq           *uint64 `json:"seq"`
+	PrevHash      *string `json:"prev_hash"`
+	Type          string  `json:"type"`
+	KeyID         string  `json:"key_id"`
+	Signature     string  `json:"signature"`
+	PrevChainSeq  uint64  `json:"prev_chain_seq"`
+	PrevChainHash string  `json:"prev_chain_hash"`
+}
+
+// checkpointRecord is a signed checkpoint. Its fields follow those of the events written by slog.
+type checkpointRecord struct {
+	Time      time.Time `json:"time"`
+	Level     string    `json:"level"`
+	Msg       string    `json:"msg"`
+	Type      string    `json:"type"`
+	LoggedAt  time.Time `json:"logged_at"`
+	KeyID     string    `json:"key_id"`
+	Signature string    `json:"signature"`
+}
+
+// chainStartRecord is a signed record starting a hash chain written to stdout
+type chainStartRecord struct {
+	Time          time.Time `json:"time"`
+	Level         string    `json:"level"`
+	Msg           string    `json:"msg"`
+	Type          string    `json:"type"`
+	LoggedAt      time.Time `json:"logged_at"`
+	PrevChainSeq  uint64    `json:"prev_chain_seq"`
+	PrevChainHash string    `json:"prev_chain_hash"`
+	KeyID         string    `json:"key_id"`
+	Signature     string    `json:"signature"`
+}
+
+// chainPosition is the position of a hash chain kept in the chain state file
+type chainPosition struct {
+	Seq      uint64 `json:"seq"`
+	LastHash string `json:"last_hash"`
+}
+
+// chainState is the position of the hash chain of a log destination.
+// Writers of the same destination share it, so that their events form a single chain.
+type chainState struct {
+	mu              sync.Mutex
+	seq             uint64
+	lastHash        string
+	sinceCheckpoint int
+	refs            int
+
+	// Chains written to stdout start with a chain start record referencing the previous chain
+	startPending bool
+	prevChain    chainPosition
+	stateFile    string
+}
+
+var (
+	chainStates   = make(map[string]*chainState)
+	chainStatesMu sync.Mutex
+)
+
+// acquireChainState returns the chain state of a log destination, resuming the chain from the
+// last line of the log file when no writer of this destination is open. The chains written to
+// stdout are linked to the previous chain recorded in the state file, if any.
+func acquireChainState(key, logFile, stateFile string) (*chainState, error) {
+	chainStatesMu.Lock()
+	defer chainStatesMu.Unlock()
+
+	if state, ok := chainStates[key]; ok && state.refs > 0 {
+		state.refs++
+		return state, nil
+	}
+
+	state := &chainState{refs: 1}
+	if logFile != "" {
+		last, err := readLastLine(logFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resume the audit hash chain: %w", err)
+		}
+		var link chainLink
+		if len(last) > 0 && json.Unmarshal(last, &link) == nil && link.Seq != nil {
+			state.seq = *link.Seq
+			state.lastHash = lineHash(last)
+			// Events written after the last checkpoint before a crash are covered by the next one
+			if link.Type != EventTypeAuditCheckpoint {
+				state.sinceCheckpoint = 1
+			}
+		}
+	} else {
+		state.startPending = true
+		if stateFile != "" {
+			state.stateFile = filepath.Clean(stateFile)
+			if err := state.loadPreviousChain(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	chainStates[key] = state
+	return state, nil
+}
+
+// loadPreviousChain reads the position of the previous chain from the state file,
+// and checks that the file can be written.
+func (s *chainState) loadPreviousChain() error {
+	data, err := os.ReadFile(s.stateFile)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return fmt.Errorf("failed to read audit chain state: %w", err)
+	default:
+		if err := json.Unmarshal(data, &s.prevChain); err != nil {
+			return fmt.Errorf("invalid audit chain state %s: %w", s.stateFile, err)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.stateFile), 0700); err != nil {
+		return fmt.Errorf("failed to create audit chain state directory: %w", err)
+	}
+	return s.savePosition(s.prevChain)
+}
+
+// savePosition writes the position of the chain to the state file
+func (s *chainState) savePosition(position chainPosition) error {
+	data, err := json.Marshal(position)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(s.stateFile, data, 0600); err != nil {
+		return fmt.Errorf("failed to write audit chain state: %w", err)
+	}
+	return nil
+}
+
+func releaseChainState(state *chainState) {
+	chainStatesMu.Lock()
+	defer chainStatesMu.Unlock()
+	state.refs--
+}
+
+// readLastLine returns the last non-empty line of a file, or nil if the file does not exist or is empty
+func readLastLine(path string) ([]byte, error) {
+	file, err := os.Open(filepath.Clean(path))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	var tail []byte
+	for offset := info.Size(); offset > 0; {
+		size := min(int64(tailChunkSize), offset)
+		offset -= size
+		chunk := make([]byte, size)
+		if _, err := file.ReadAt(chunk, offset); err != nil && !errors.Is(err, io.EOF) {
+			return nil, err
+		}
+		tail = append(chunk, tail...)
+
+		trimmed := bytes.TrimRight(tail, "\r\n")
+		if i := bytes.LastIndexByte(trimmed, '\n'); i >= 0 {
+			return trimmed[i+1:], nil
+		}
+		if offset == 0 && len(trimmed) > 0 {
+			return trimmed, nil
+		}
+	}
+	return nil, nil
+}
+
+// chainWriter links each audit event to the previous one by adding its sequence number and the
+// hash of the previous line, and writes signed checkpoints every few events, periodically and when closed.
+type chainWriter struct {
+	next             io.Writer
+	state            *chainState
+	signer           ed25519.PrivateKey
+	keyID            string
+	checkpointEvents int
+
+	done      chan struct{}
+	stopped   chan struct{}
+	closeOnce sync.Once
+}
+
+// newChainWriter creates a writer adding the hash chain to the events written to next.
+// The logFile, if any, is the file next appends to, from which the chain is resumed.
+func newChainWriter(
+	next io.Writer, logFile string, config *IntegrityConfig, signer ed25519.PrivateKey,
+) (*chainWriter, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Writers of stdout share a chain, as do the writers of a file
+	key := "-"
+	if logFile != "" {
+		key = filepath.Clean(logFile)
+	}
+	state, err := acquireChainState(key, logFile, config.ChainStateFile)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &chainWriter{
+		next:             next,
+		state:            state,
+		signer:           signer,
+		keyID:            KeyID(signer.Public().(ed25519.PublicKey)),
+		checkpointEvents: config.CheckpointEvents,
+		done:             make(chan struct{}),
+		stopped:          make(chan struct{}),
+	}
+	if w.checkpointEvents == 0 {
+		w.checkpointEvents = defaultCheckpointEvents
+	}
+	// Validate has checked the interval
+	interval, _ := parseOptionalDuration(config.CheckpointInterval, defaultCheckpointInterval)
+
+	go w.run(interval)
+	return w, nil
+}
+
+// Write links an audit event to the chain and writes it.
+func (w *chainWriter) Write(p []byte) (int, error) {
+	event := bytes.TrimSpace(p)
+	if len(event) == 0 {
+		return len(p), nil
+	}
+
+	w.state.mu.Lock()
+	defer w.state.mu.Unlock()
+
+	if err := w.startChain(); err != nil {
+		return 0, err
+	}
+	if err := w.writeLinked(event); err != nil {
+		return 0, err
+	}
+	w.state.sinceCheckpoint++
+	if w.state.sinceCheckpoint >= w.checkpointEvents {
+		if err := w.checkpoint(); err != nil {
+			logger.Errorf("Failed to write audit checkpoint: %v", err)
+		}
+	}
+	return len(p), nil
+}
+
+// Close writes a checkpoint covering the last events, and closes the underlying writer unless it is stdout.
+func (w *chainWriter) Close() error {
+	w.closeOnce.Do(func() {
+		close(w.done)
+	})
+	<-w.stopped
+
+	w.state.mu.Lock()
+	var errs []error
+	if w.state.sinceCheckpoint > 0 {
+		if err := w.checkpoint(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to write audit checkpoint: %w", err))
+		}
+	}
+	w.state.mu.Unlock()
+	releaseChainState(w.state)
+
+	if closer, ok := w.next.(io.Closer); ok && w.next != os.Stdout {
+		if err := closer.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (w *chainWriter) run(interval time.Duration) {
+	defer close(w.stopped)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			w.state.mu.Lock()
+			if w.state.sinceCheckpoint > 0 {
+				if err := w.checkpoint(); err != nil {
+					logger.Errorf("Failed to write audit checkpoint: %v", err)
+				}
+			}
+			w.state.mu.Unlock()
+		case <-w.done:
+			return
+		}
+	}
+}
+
+// checkpoint writes a checkpoint signing the hash of the last event. The caller must hold the state lock.
+func (w *chainWriter) checkpoint() error {
+	now := time.Now().UTC()
+	record, err := json.Marshal(checkpointRecord{
+		Time:      now,
+		Level:     LevelAudit.String(),
+		Msg:       EventTypeAuditCheckpoint,
+		Type:      EventTypeAuditCheckpoint,
+		LoggedAt:  now,
+		KeyID:     w.keyID,
+		Signature: base64.StdEncoding.EncodeToString(ed25519.Sign(w.signer, checkpointPayload(w.state.seq, w.state.lastHash))),
+	})
+	if err != nil {
+		return err
+	}
+	w.state.sinceCheckpoint = 0
+	return w.writeLinked(record)
+}
+
+// startChain writes the chain start record of a chain written to stdout before its first event.
+// The caller must hold the state lock.
+func (w *chainWriter) startChain() error {
+	if !w.state.startPending {
+		return nil
+	}
+	w.state.startPending = false
+
+	now := time.Now().UTC()
+	prev := w.state.prevChain
+	record, err := json.Marshal(chainStartRecord{
+		Time:          now,
+		Level:         LevelAudit.String(),
+		Msg:           EventTypeAuditChainStart,
+		Type:          EventTypeAuditChainStart,
+		LoggedAt:      now,
+		PrevChainSeq:  prev.Seq,
+		PrevChainHash: prev.LastHash,
+		KeyID:         w.keyID,
+		Signature:     base64.StdEncoding.EncodeToString(ed25519.Sign(w.signer, chainStartPayload(prev.Seq, prev.LastHash))),
+	})
+	if err != nil {
+		return err
+	}
+	return w.writeLinked(record)
+}
+
+// writeLinked adds the chain fields to a JSON object and writes it as a line. The caller must hold the state lock.
+// The chain advances even if the write fails, so that lost events show up as gaps.
+func (w *chainWriter) writeLinked(event []byte) error {
+	if len(event) < 2 || event[0] != '{' || event[len(event)-1] != '}' {
+		return errors.New("audit event is not a JSON object")
+	}
+
+	seq := w.state.seq + 1
+	line := make([]byte, 0, len(event)+128)
+	line = append(line, event[:len(event)-1]...)
+	if len(bytes.TrimSpace(event[1:len(event)-1])) > 0 {
+		line = append(line, ',')
+	}
+	line = fmt.Appendf(line, `"seq":%d,"prev_hash":%q}`, seq, w.state.lastHash)
+
+	w.state.seq = seq
+	w.state.lastHash = lineHash(line)
+	_, err := w.next.Write(append(line, '\n'))
+	if w.state.stateFile != "" {
+		if err := w.state.savePosition(chainPosition{Seq: seq, LastHash: w.state.lastHash}); err != nil {
+			logger.Warnf("Failed to save audit chain position: %v", err)
+		}
+	}
+	return err
+}
@@ -0,0 +1,258 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSigningKey(t *testing.T) ed25519.PrivateKey {
+	t.Helper()
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	return key
+}
+
+// writeChainedEvents logs audit events through a chain writer appending to the log file
+func writeChainedEvents(t *testing.T, logFile string, config *IntegrityConfig, key ed25519.PrivateKey, count int) {
+	t.Helper()
+	file, err := os.OpenFile(logFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	require.NoError(t, err)
+	writer, err := newChainWriter(file, logFile, config, key)
+	require.NoError(t, err)
+
+	auditLogger := NewAuditLogger(writer)
+	for range count {
+		event := NewAuditEvent(EventTypeMCPToolCall, EventSource{Type: SourceTypeNetwork, Value: "127.0.0.1"},
+			OutcomeSuccess, map[string]string{SubjectKeyUser: "alice"}, "fetch")
+		event.LogTo(context.Background(), auditLogger, LevelAudit)
+	}
+	require.NoError(t, writer.Close())
+}
+
+func readLines(t *testing.T, path string) []string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+}
+
+func TestChainWriter(t *testing.T) {
+	t.Parallel()
+
+	key := newTestSigningKey(t)
+	logFile := filepath.Join(t.TempDir(), "audit.log")
+	config := &IntegrityConfig{SigningKeySecret: "audit-key", CheckpointEvents: 2}
+
+	writeChainedEvents(t, logFile, config, key, 3)
+
+	// Three events, a checkpoint after the second one and a final checkpoint when closed
+	lines := readLines(t, logFile)
+	require.Len(t, lines, 5)
+	var prevHash string
+	for i, line := range lines {
+		var link chainLink
+		require.NoError(t, json.Unmarshal([]byte(line), &link))
+		assert.Equal(t, uint64(i+1), *link.Seq)
+		assert.Equal(t, prevHash, *link.PrevHash)
+		prevHash = lineHash([]byte(line))
+	}
+	assert.Contains(t, lines[2], `"type":"audit_checkpoint"`)
+	assert.Contains(t, lines[4], `"type":"audit_checkpoint"`)
+	assert.Contains(t, lines[0], `"type":"mcp_tool_call"`)
+
+	// The chain is resumed from the log file
+	writeChainedEvents(t, logFile, config, key, 1)
+	lines = readLines(t, logFile)
+	require.Len(t, lines, 7)
+	assert.Contains(t, lines[5], `"seq":6,"prev_hash":"`+prevHash+`"}`)
+
+	result, err := VerifyLog(strings.NewReader(strings.Join(lines, "\n")+"\n"), key.Public().(ed25519.PublicKey))
+	require.NoError(t, err)
+	assert.Empty(t, result.Problems)
+	assert.Equal(t, 4, result.Events)
+	assert.Equal(t, 3, result.Checkpoints)
+	assert.Equal(t, 1, result.Chains)
+	assert.Zero(t, result.UnsignedEvents)
+}
+
+func TestChainWriter_SharedChain(t *testing.T) {
+	t.Parallel()
+
+	key := newTestSigningKey(t)
+	logFile := filepath.Join(t.TempDir(), "audit.log")
+	config := &IntegrityConfig{SigningKeySecret: "audit-key"}
+
+	// Two writers of the same file, e.g. the request and workflow auditors, write a single chain
+	var writers []*chainWriter
+	for range 2 {
+		file, err := os.OpenFile(logFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+		require.NoError(t, err)
+		writer, err := newChainWriter(file, logFile, config, key)
+		require.NoError(t, err)
+		writers = append(writers, writer)
+	}
+	for i := range 4 {
+		_, err := writers[i%2].Write([]byte(`{"type":"mcp_tool_call"}` + "\n"))
+		require.NoError(t, err)
+	}
+	for _, writer := range writers {
+		require.NoError(t, writer.Close())
+	}
+
+	data, err := os.ReadFile(logFile)
+	require.NoError(t, err)
+	result, err := VerifyLog(bytes.NewReader(data), key.Public().(ed25519.PublicKey))
+	require.NoError(t, err)
+	assert.Empty(t, result.Problems)
+	assert.Equal(t, 4, result.Events)
+	assert.Equal(t, 1, result.Checkpoints)
+}
+
+func TestChainWriter_StdoutChains(t *testing.T) { //nolint:paralleltest // writers of stdout share a chain
+	key := newTestSigningKey(t)
+	publicKey := key.Public().(ed25519.PublicKey)
+	config := &IntegrityConfig{SigningKeySecret: "audit-key", ChainStateFile: filepath.Join(t.TempDir(), "chain.json")}
+
+	// Each process writes a new chain to stdout
+	writeProcess := func(config *IntegrityConfig, count int) []string {
+		var buf bytes.Buffer
+		writer, err := newChainWriter(&buf, "", config, key)
+		require.NoError(t, err)
+		for range count {
+			_, err := writer.Write([]byte(`{"type":"mcp_tool_call"}` + "\n"))
+			require.NoError(t, err)
+		}
+		require.NoError(t, writer.Close())
+		return strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	}
+	verify := func(lines []string) *VerifyResult {
+		result, err := VerifyLog(strings.NewReader(strings.Join(lines, "\n")+"\n"), publicKey)
+		require.NoError(t, err)
+		return result
+	}
+
+	first := writeProcess(config, 2)
+	require.Len(t, first, 4)
+	assert.Contains(t, first[0], `"type":"audit_chain_start","logged_at"`)
+	assert.Contains(t, first[0], `"prev_chain_seq":0,"prev_chain_hash":""`)
+
+	// The chain of the next process references the last line of the previous chain
+	second := writeProcess(config, 1)
+	require.Len(t, second, 3)
+	assert.Contains(t, second[0], `"prev_chain_seq":4,"prev_chain_hash":"`+lineHash([]byte(first[3]))+`"`)
+
+	result := verify(append(append([]string{}, first...), second...))
+	assert.Empty(t, result.Problems)
+	assert.Equal(t, 2, result.Chains)
+	assert.Equal(t, 3, result.Events)
+	assert.Equal(t, 2, result.Checkpoints)
+
+	// Removing the end of the previous chain is detected
+	result = verify(append(append([]string{}, first[:2]...), second...))
+	require.Len(t, result.Problems, 1)
+	assert.Equal(t, 3, result.Problems[0].Line)
+	assert.Contains(t, result.Problems[0].Message, "hash chain restarts without referencing the previous chain")
+
+	// A forged chain start record is detected
+	forged := append([]string{}, second...)
+	forged[0] = strings.Replace(forged[0], `"prev_chain_seq":4,"prev_chain_hash":"`+lineHash([]byte(first[3])),
+		`"prev_chain_seq":2,"prev_chain_hash":"`+lineHash([]byte(first[1])), 1)
+	result = verify(append(append([]string{}, first[:2]...), forged...))
+	require.Len(t, result.Problems, 2)
+	assert.Equal(t, "invalid chain start signature", result.Problems[0].Message)
+	assert.Contains(t, result.Problems[1].Message, "hash of the previous event does not match")
+
+	// Without a chain state file, chains are not linked
+	third := writeProcess(&IntegrityConfig{SigningKeySecret: "audit-key"}, 1)
+	result = verify(append(append([]string{}, first...), third...))
+	require.Len(t, result.Problems, 1)
+	assert.Equal(t, 5, result.Problems[0].Line)
+}
+
+func TestIntegrityConfig_Validate(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, (&IntegrityConfig{SigningKeySecret: "audit-key", CheckpointInterval: "30s"}).Validate())
+	assert.ErrorContains(t, (&IntegrityConfig{}).Validate(), "signingKeySecret is required")
+	assert.ErrorContains(t, (&IntegrityConfig{SigningKeySecret: "audit-key", CheckpointInterval: "0s"}).Validate(),
+		"invalid checkpointInterval")
+
+	config := &Config{Integrity: &IntegrityConfig{SigningKeySecret: "audit-key", CheckpointEvents: -1}}
+	assert.ErrorContains(t, config.Validate(), "invalid integrity configuration")
+}
+
+func TestParseSigningKey(t *testing.T) {
+	t.Parallel()
+
+	key := newTestSigningKey(t)
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		value   string
+		wantErr string
+	}{
+		{name: "pem", value: string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8}))},
+		{name: "base64 seed", value: base64.StdEncoding.EncodeToString(key.Seed()) + "\n"},
+		{name: "base64 private key", value: base64.StdEncoding.EncodeToString(key)},
+		{name: "wrong size", value: base64.StdEncoding.EncodeToString([]byte("short")), wantErr: "found 5 bytes"},
+		{name: "not encoded", value: "not a key!", wantErr: "neither PEM nor base64"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			parsed, err := ParseSigningKey(tt.value)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, key.Equal(parsed))
+		})
+	}
+}
+
+func TestLoadSigningKey_Environment(t *testing.T) { //nolint:paralleltest // uses t.Setenv
+	key := newTestSigningKey(t)
+	t.Setenv(SigningKeyEnvVar, base64.StdEncoding.EncodeToString(key.Seed()))
+
+	// The environment variable is used without a secrets provider, as in Kubernetes
+	loaded, err := LoadSigningKey(context.Background(), "audit-signing-key")
+	require.NoError(t, err)
+	assert.True(t, key.Equal(loaded))
+
+	t.Setenv(SigningKeyEnvVar, "not a key!")
+	_, err = LoadSigningKey(context.Background(), "audit-signing-key")
+	assert.ErrorContains(t, err, "invalid audit signing key audit-signing-key")
+}
+
+func TestParsePublicKey(t *testing.T) {
+	t.Parallel()
+
+	publicKey := newTestSigningKey(t).Public().(ed25519.PublicKey)
+	pkix, err := x509.MarshalPKIXPublicKey(publicKey)
+	require.NoError(t, err)
+
+	parsed, err := ParsePublicKey(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pkix}))
+	require.NoError(t, err)
+	assert.True(t, publicKey.Equal(parsed))
+
+	parsed, err = ParsePublicKey([]byte(base64.StdEncoding.EncodeToString(publicKey)))
+	require.NoError(t, err)
+	assert.True(t, publicKey.Equal(parsed))
+}
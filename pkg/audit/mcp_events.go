@@ -48,6 +48,10 @@ const (
 	// EventTypeWorkflowStepSkipped represents conditional step skip
 	EventTypeWorkflowStepSkipped = "vmcp_workflow_step_skipped"
 
+	// Authorization event types
+	// EventTypeAuthzDecision represents an authorization decision
+	EventTypeAuthzDecision = "authz_decision"
+
 	// Fallback event types for unrecognized or generic requests
 	// EventTypeMCPRequest represents a generic MCP request when specific type cannot be determined
 	EventTypeMCPRequest = "mcp_request"
@@ -93,6 +97,8 @@ const (
 	TargetKeyStepType = "step_type"
 	// TargetKeyToolName is the key for the tool being called (for tool steps)
 	TargetKeyToolName = "tool_name"
+	// TargetKeyOperation is the key for the operation on the target (call, get, read, list)
+	TargetKeyOperation = "operation"
 )
 
 // MCP-specific subject field keys
@@ -133,4 +139,14 @@ const (
 	MetadataExtraKeyStepCount = "step_count"
 	// MetadataExtraKeyTimeout is the key for the workflow timeout in milliseconds
 	MetadataExtraKeyTimeout = "timeout_ms"
+	// MetadataExtraKeyAuthorizer is the key for the type of the authorizer that made a decision
+	MetadataExtraKeyAuthorizer = "authorizer"
+	// MetadataExtraKeyAllowed is the key for whether an authorization decision allowed the request
+	MetadataExtraKeyAllowed = "allowed"
+	// MetadataExtraKeyPolicies is the key for the IDs of the policies that determined a decision
+	MetadataExtraKeyPolicies = "policies"
+	// MetadataExtraKeyError is the key for the error of a failed operation
+	MetadataExtraKeyError = "error"
+	// MetadataExtraKeyShadow is the key for the decision of the shadow authorization policies
+	MetadataExtraKeyShadow = "shadow"
 )
@@ -24,6 +24,7 @@ type MiddlewareParams struct {
 // Middleware wraps audit middleware functionality
 type Middleware struct {
 	middleware types.MiddlewareFunction
+	auditor    *Auditor
 }
 
 // Handler returns the middleware function used by the proxy.
@@ -31,10 +32,12 @@ func (m *Middleware) Handler() types.MiddlewareFunction {
 	return m.middleware
 }
 
-// Close cleans up any resources used by the middleware.
-func (*Middleware) Close() error {
-	// Audit middleware doesn't need cleanup
-	return nil
+// Close closes the audit log file and flushes the audit sinks.
+func (m *Middleware) Close() error {
+	if m.auditor == nil {
+		return nil
+	}
+	return m.auditor.Close()
 }
 
 // CreateMiddleware factory function for audit middleware
@@ -73,12 +76,12 @@ func CreateMiddleware(config *types.MiddlewareConfig, runner types.MiddlewareRun
 	}
 
 	// Always use the transport-aware constructor
-	middleware, err := auditConfig.CreateMiddlewareWithTransport(params.TransportType)
+	auditor, err := NewAuditorWithTransport(auditConfig, params.TransportType)
 	if err != nil {
 		return fmt.Errorf("failed to create audit middleware: %w", err)
 	}
 
-	auditMw := &Middleware{middleware: middleware}
+	auditMw := &Middleware{middleware: auditor.Middleware, auditor: auditor}
 	runner.AddMiddleware(config.Type, auditMw)
 	return nil
 }
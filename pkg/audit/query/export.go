@@ -0,0 +1,62 @@
+package query
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/stacklok/toolhive/pkg/audit"
+)
+
+// csvHeader is the header of the CSV export of audit events
+var csvHeader = []string{
+	"logged_at", "workload", "audit_id", "type", "outcome", "component",
+	"user", "user_id", "client_name", "source", "tool", "target", "duration_ms",
+}
+
+// WriteCSV writes audit events as CSV, one row per event after a header row.
+func WriteCSV(w io.Writer, records []*Record) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+	for _, record := range records {
+		var duration string
+		if value, ok := record.Extra[audit.MetadataExtraKeyDuration]; ok {
+			duration = fmt.Sprint(value)
+		}
+		row := []string{
+			record.Time.UTC().Format(time.RFC3339Nano),
+			record.Workload,
+			record.AuditID,
+			record.Type,
+			record.Outcome,
+			record.Component,
+			record.Subjects[audit.SubjectKeyUser],
+			record.Subjects[audit.SubjectKeyUserID],
+			record.Subjects[audit.SubjectKeyClientName],
+			record.Source.Value,
+			record.Tool(),
+			formatPairs(record.Target),
+			duration,
+		}
+		if err := writer.Write(row); err != nil {
+			return fmt.Errorf("failed to write CSV row: %w", err)
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// formatPairs formats a map as key=value pairs sorted by key and separated by spaces
+func formatPairs(values map[string]string) string {
+	pairs := make([]string, 0, len(values))
+	for _, key := range slices.Sorted(maps.Keys(values)) {
+		pairs = append(pairs, key+"="+values[key])
+	}
+	return strings.Join(pairs, " ")
+}
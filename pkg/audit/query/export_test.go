@@ -0,0 +1,40 @@
+package query
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stacklok/toolhive/pkg/audit"
+)
+
+func TestWriteCSV(t *testing.T) {
+	t.Parallel()
+
+	records := []*Record{{
+		Workload: "fetch",
+		AuditID:  "a1",
+		Type:     audit.EventTypeMCPToolCall,
+		Time:     testTime,
+		Outcome:  audit.OutcomeSuccess,
+		Source:   audit.EventSource{Type: audit.SourceTypeNetwork, Value: "127.0.0.1"},
+		Subjects: map[string]string{audit.SubjectKeyUser: "alice", audit.SubjectKeyClientName: "claude, desktop"},
+		Target:   map[string]string{audit.TargetKeyType: audit.TargetTypeTool, audit.TargetKeyName: "fetch"},
+		Extra:    map[string]any{audit.MetadataExtraKeyDuration: float64(42)},
+	}}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteCSV(&buf, records))
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, csvHeader, rows[0])
+	assert.Equal(t, []string{
+		"2025-06-02T10:00:00Z", "fetch", "a1", "mcp_tool_call", "success", "",
+		"alice", "", "claude, desktop", "127.0.0.1", "fetch", "name=fetch type=tool", "42",
+	}, rows[1])
+}
@@ -0,0 +1,388 @@
+// Package query reads the audit events recorded for ToolHive workloads, to search
+// them, export them and replay the tool calls they record.
+package query
+
+import (
+	"bufio"
+	"container/heap"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/adrg/xdg"
+
+	"github.com/stacklok/toolhive/pkg/audit"
+	"github.com/stacklok/toolhive/pkg/egress"
+	thverrors "github.com/stacklok/toolhive/pkg/errors"
+	"github.com/stacklok/toolhive/pkg/logger"
+	"github.com/stacklok/toolhive/pkg/runner"
+	"github.com/stacklok/toolhive/pkg/state"
+	wt "github.com/stacklok/toolhive/pkg/workloads/types"
+)
+
+// proxyLogDir is the directory of the logs of detached proxies, relative to the XDG data directory
+const proxyLogDir = "toolhive/logs"
+
+// ErrEventNotFound is returned when no audit event has the requested ID.
+var ErrEventNotFound = thverrors.New("audit event not found", http.StatusNotFound)
+
+// Record is an audit event read from an audit log.
+type Record struct {
+	// Workload is the name of the workload the event was recorded for, if known
+	Workload string `json:"workload,omitempty"`
+	// AuditID is the unique identifier of the event
+	AuditID string `json:"audit_id"`
+	// Type is the type of the event, e.g. mcp_tool_call
+	Type string `json:"type"`
+	// Time is the time the event was logged
+	Time time.Time `json:"logged_at"`
+	// Outcome is the outcome of the event, e.g. success or denied
+	Outcome string `json:"outcome"`
+	// Component is the component that recorded the event
+	Component string `json:"component,omitempty"`
+	// Source is the source of the request
+	Source audit.EventSource `json:"source"`
+	// Subjects identify who made the request, e.g. the user and the client
+	Subjects map[string]string `json:"subjects,omitempty"`
+	// Target is what the request acted on, e.g. the tool called
+	Target map[string]string `json:"target,omitempty"`
+	// Extra is the additional metadata of the event, e.g. its duration
+	Extra map[string]any `json:"extra,omitempty"`
+	// Data is the request and response data of the event, if recorded
+	Data json.RawMessage `json:"data,omitempty" swaggertype:"object"`
+}
+
+// Tool returns the name of the tool called in the event, or an empty string.
+func (r *Record) Tool() string {
+	if name := r.Target[audit.TargetKeyToolName]; name != "" {
+		return name
+	}
+	if r.Target[audit.TargetKeyType] == audit.TargetTypeTool {
+		return r.Target[audit.TargetKeyName]
+	}
+	return ""
+}
+
+// logLine holds the fields of an audit event, either written by the audit logger or
+// serialized as an audit.AuditEvent, as in the egress log
+type logLine struct {
+	AuditID   string            `json:"audit_id"`
+	Type      string            `json:"type"`
+	LoggedAt  time.Time         `json:"logged_at"`
+	Outcome   string            `json:"outcome"`
+	Component string            `json:"component"`
+	Source    audit.EventSource `json:"source"`
+	Subjects  map[string]string `json:"subjects"`
+	Target    map[string]string `json:"target"`
+	Metadata  struct {
+		AuditID string         `json:"auditId"`
+		Extra   map[string]any `json:"extra"`
+	} `json:"metadata"`
+	Data json.RawMessage `json:"data"`
+	// EventLoggedAt is the time of the events serialized as audit.AuditEvent
+	EventLoggedAt time.Time `json:"loggedAt"`
+}
+
+// parseLine parses a line of an audit log. Lines that are not audit events, such as the
+// other output of the proxy or the checkpoints of a hash-chained log, are rejected.
+func parseLine(line []byte) (*Record, error) {
+	var l logLine
+	if err := json.Unmarshal(line, &l); err != nil {
+		return nil, err
+	}
+	record := &Record{
+		AuditID:   l.AuditID,
+		Type:      l.Type,
+		Time:      l.LoggedAt,
+		Outcome:   l.Outcome,
+		Component: l.Component,
+		Source:    l.Source,
+		Subjects:  l.Subjects,
+		Target:    l.Target,
+		Extra:     l.Metadata.Extra,
+		Data:      l.Data,
+	}
+	if record.AuditID == "" {
+		record.AuditID = l.Metadata.AuditID
+	}
+	if record.Time.IsZero() {
+		record.Time = l.EventLoggedAt
+	}
+	if record.Type == "" || record.Outcome == "" || record.Time.IsZero() {
+		return nil, errors.New("not an audit event")
+	}
+	return record, nil
+}
+
+// Filter selects audit events.
+type Filter struct {
+	// Subject only includes events with a subject, e.g. the user or the client name, equal to this value
+	Subject string
+	// EventTypes only includes events of these types
+	EventTypes []string
+	// Outcomes only includes events with these outcomes
+	Outcomes []string
+	// Tool only includes the calls of this tool
+	Tool string
+	// Since excludes events before this time
+	Since time.Time
+	// Until excludes events after this time
+	Until time.Time
+	// AuditID only includes the event with this ID
+	AuditID string
+	// Limit only keeps the most recent events, if positive
+	Limit int
+}
+
+// Matches returns true if the record is selected by the filter. The limit is not taken into account.
+func (f *Filter) Matches(record *Record) bool {
+	if f.AuditID != "" && record.AuditID != f.AuditID {
+		return false
+	}
+	if !f.Since.IsZero() && record.Time.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && record.Time.After(f.Until) {
+		return false
+	}
+	if len(f.EventTypes) > 0 && !slices.Contains(f.EventTypes, record.Type) {
+		return false
+	}
+	if len(f.Outcomes) > 0 && !slices.Contains(f.Outcomes, record.Outcome) {
+		return false
+	}
+	if f.Tool != "" && record.Tool() != f.Tool {
+		return false
+	}
+	if f.Subject != "" {
+		for _, subject := range record.Subjects {
+			if strings.EqualFold(subject, f.Subject) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// recordSet collects the records selected by a filter. With a limit, only the most recent records
+// are kept while the logs are read, so that the memory used does not grow with the size of the logs.
+type recordSet struct {
+	limit   int
+	records recordHeap
+}
+
+func newRecordSet(filter Filter) *recordSet {
+	return &recordSet{limit: filter.Limit}
+}
+
+// add adds a record, replacing the oldest one if the limit is reached
+func (s *recordSet) add(record *Record) {
+	switch {
+	case s.limit <= 0:
+		s.records = append(s.records, record)
+	case len(s.records) < s.limit:
+		heap.Push(&s.records, record)
+	case s.records[0].Time.Before(record.Time):
+		s.records[0] = record
+		heap.Fix(&s.records, 0)
+	}
+}
+
+// sorted returns the records sorted by time
+func (s *recordSet) sorted() []*Record {
+	records := []*Record(s.records)
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].Time.Before(records[j].Time)
+	})
+	return records
+}
+
+// recordHeap is a min-heap of records ordered by time, whose root is the oldest record
+type recordHeap []*Record
+
+func (h recordHeap) Len() int           { return len(h) }
+func (h recordHeap) Less(i, j int) bool { return h[i].Time.Before(h[j].Time) }
+func (h recordHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *recordHeap) Push(x any)        { *h = append(*h, x.(*Record)) }
+func (h *recordHeap) Pop() any {
+	old := *h
+	record := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return record
+}
+
+// Read reads the audit events of an audit log that match the filter.
+// Lines that are not audit events are skipped.
+func Read(r io.Reader, filter Filter) ([]*Record, error) {
+	records := newRecordSet(filter)
+	if err := read(r, filter, "", records); err != nil {
+		return nil, err
+	}
+	return records.sorted(), nil
+}
+
+// read adds the audit events of an audit log that match the filter to records,
+// setting the workload they were recorded for
+func read(r io.Reader, filter Filter, workload string, records *recordSet) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 || line[0] != '{' {
+			continue
+		}
+		record, err := parseLine(line)
+		if err != nil {
+			logger.Debugf("Skipping audit log line: %v", err)
+			continue
+		}
+		if filter.Matches(record) {
+			record.Workload = workload
+			records.add(record)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read audit log: %w", err)
+	}
+	return nil
+}
+
+// ReadFiles reads the audit events of audit log files that match the filter, sorted by time.
+// Files that do not exist are skipped.
+func ReadFiles(paths []string, filter Filter) ([]*Record, error) {
+	records := newRecordSet(filter)
+	for _, path := range paths {
+		if err := readFile(path, filter, "", records); err != nil {
+			return nil, err
+		}
+	}
+	return records.sorted(), nil
+}
+
+func readFile(path string, filter Filter, workload string, records *recordSet) error {
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer func() {
+		if err := f.Close(); err != nil {
+			logger.Debugf("Failed to close audit log: %v", err)
+		}
+	}()
+	if err := read(f, filter, workload, records); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// Sources returns the audit logs of a workload: the log file of its audit configuration, or
+// the log of its proxy, to which audit events are written by default, and its egress log.
+func Sources(runConfig *runner.RunConfig) ([]string, error) {
+	var sources []string
+	if runConfig.AuditConfig != nil {
+		if runConfig.AuditConfig.LogFile != "" {
+			sources = append(sources, runConfig.AuditConfig.LogFile)
+		} else {
+			path, err := xdg.DataFile(filepath.Join(proxyLogDir, runConfig.BaseName+".log"))
+			if err != nil {
+				return nil, fmt.Errorf("failed to get proxy log path for workload %s: %w", runConfig.Name, err)
+			}
+			sources = append(sources, path)
+		}
+	}
+
+	egressLog, err := egress.LogPath(runConfig.Name)
+	if err != nil {
+		return nil, err
+	}
+	return append(sources, egressLog), nil
+}
+
+// ReadWorkloads reads the audit events of workloads that match the filter, sorted by time.
+// If no workload is given, the events of all the workloads are read.
+func ReadWorkloads(ctx context.Context, workloadNames []string, filter Filter) ([]*Record, error) {
+	all := len(workloadNames) == 0
+	if all {
+		var err error
+		if workloadNames, err = listWorkloads(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	records := newRecordSet(filter)
+	for _, name := range workloadNames {
+		// Workload names are used in file paths
+		if err := wt.ValidateWorkloadName(name); err != nil {
+			return nil, err
+		}
+		runConfig, err := runner.LoadState(ctx, name)
+		if err != nil {
+			if all {
+				logger.Warnf("Skipping audit events of workload %s: %v", name, err)
+				continue
+			}
+			return nil, fmt.Errorf("failed to load run configuration for workload %s: %w", name, err)
+		}
+		sources, err := Sources(runConfig)
+		if err != nil {
+			return nil, err
+		}
+		for _, source := range sources {
+			if err := readFile(source, filter, name, records); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return records.sorted(), nil
+}
+
+// listWorkloads returns the names of the workloads with a saved run configuration
+func listWorkloads(ctx context.Context) ([]string, error) {
+	store, err := state.NewRunConfigStore(state.DefaultAppName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create state store: %w", err)
+	}
+	names, err := store.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workloads: %w", err)
+	}
+	return names, nil
+}
+
+// Find returns the record with the given audit ID, or ErrEventNotFound.
+func Find(records []*Record, auditID string) (*Record, error) {
+	for _, record := range records {
+		if record.AuditID == auditID {
+			return record, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrEventNotFound, auditID)
+}
+
+// ParseTime parses the bound of a time range, either an RFC 3339 time, a date (2006-01-02)
+// or a duration relative to now, e.g. 24h for the last 24 hours.
+func ParseTime(value string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation(time.DateOnly, value, time.Local); err == nil {
+		return t, nil
+	}
+	if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q: expected an RFC 3339 time, a date or a duration", value)
+}
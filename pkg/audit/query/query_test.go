@@ -0,0 +1,187 @@
+package query
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stacklok/toolhive/pkg/audit"
+	"github.com/stacklok/toolhive/pkg/egress"
+	"github.com/stacklok/toolhive/pkg/runner"
+)
+
+var testTime = time.Date(2025, 6, 2, 10, 0, 0, 0, time.UTC)
+
+// logEvent writes an audit event as the audit logger does, at the given offset from testTime
+func logEvent(t *testing.T, buf *bytes.Buffer, eventType, outcome, user, tool string, offset time.Duration) string {
+	t.Helper()
+	event := audit.NewAuditEvent(eventType, audit.EventSource{Type: audit.SourceTypeNetwork, Value: "127.0.0.1"},
+		outcome, map[string]string{audit.SubjectKeyUser: user}, "fetch")
+	event.LoggedAt = testTime.Add(offset)
+	if tool != "" {
+		event.WithTarget(map[string]string{audit.TargetKeyType: audit.TargetTypeTool, audit.TargetKeyName: tool})
+	}
+	event.LogTo(context.Background(), audit.NewAuditLogger(buf), audit.LevelAudit)
+	return event.Metadata.AuditID
+}
+
+func TestRead(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	buf.WriteString("12:00PM INF Starting proxy\n")
+	logEvent(t, &buf, audit.EventTypeMCPToolCall, audit.OutcomeSuccess, "alice", "fetch", 0)
+	deniedID := logEvent(t, &buf, audit.EventTypeMCPToolCall, audit.OutcomeDenied, "bob", "fetch", time.Hour)
+	logEvent(t, &buf, audit.EventTypeMCPToolCall, audit.OutcomeSuccess, "Alice", "search", 2*time.Hour)
+	logEvent(t, &buf, audit.EventTypeMCPToolsList, audit.OutcomeSuccess, "alice", "", 3*time.Hour)
+	buf.WriteString(`{"time":"2025-06-02T10:00:00Z","msg":"audit_checkpoint","type":"audit_checkpoint","seq":5}` + "\n")
+
+	// Egress events are serialized as audit.AuditEvent
+	egressEvent, err := (&egress.Record{Time: testTime.Add(4 * time.Hour), Host: "example.com", Allowed: true}).AuditEvent()
+	require.NoError(t, err)
+	require.NoError(t, json.NewEncoder(&buf).Encode(egressEvent))
+	data := buf.String()
+
+	tests := []struct {
+		name      string
+		filter    Filter
+		wantHours []int
+	}{
+		{name: "all events", wantHours: []int{0, 1, 2, 3, 4}},
+		{name: "subject", filter: Filter{Subject: "alice"}, wantHours: []int{0, 2, 3}},
+		{name: "event type", filter: Filter{EventTypes: []string{audit.EventTypeMCPToolCall}}, wantHours: []int{0, 1, 2}},
+		{name: "outcome", filter: Filter{Outcomes: []string{audit.OutcomeDenied}}, wantHours: []int{1}},
+		{name: "tool", filter: Filter{Tool: "fetch"}, wantHours: []int{0, 1}},
+		{
+			name:      "time range",
+			filter:    Filter{Since: testTime.Add(time.Hour), Until: testTime.Add(3 * time.Hour)},
+			wantHours: []int{1, 2, 3},
+		},
+		{name: "limit", filter: Filter{Subject: "alice", Limit: 2}, wantHours: []int{2, 3}},
+		{name: "limit above the number of events", filter: Filter{Limit: 10}, wantHours: []int{0, 1, 2, 3, 4}},
+		{name: "audit ID", filter: Filter{AuditID: deniedID, Limit: 1}, wantHours: []int{1}},
+		{name: "egress", filter: Filter{EventTypes: []string{egress.EventTypeEgressRequest}}, wantHours: []int{4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			records, err := Read(strings.NewReader(data), tt.filter)
+			require.NoError(t, err)
+			hours := make([]int, 0, len(records))
+			for _, record := range records {
+				assert.NotEmpty(t, record.AuditID)
+				hours = append(hours, int(record.Time.Sub(testTime).Hours()))
+			}
+			assert.Equal(t, tt.wantHours, hours)
+		})
+	}
+}
+
+func TestRecord_Tool(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "fetch", (&Record{Target: map[string]string{
+		audit.TargetKeyType: audit.TargetTypeTool, audit.TargetKeyName: "fetch",
+	}}).Tool())
+	assert.Equal(t, "search", (&Record{Target: map[string]string{
+		audit.TargetKeyType: audit.TargetTypeWorkflow, audit.TargetKeyToolName: "search",
+	}}).Tool())
+	assert.Empty(t, (&Record{Target: map[string]string{
+		audit.TargetKeyType: audit.TargetTypeResource, audit.TargetKeyName: "file:///etc/hosts",
+	}}).Tool())
+}
+
+func TestReadFiles(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	records, err := ReadFiles([]string{filepath.Join(dir, "missing.log")}, Filter{})
+	require.NoError(t, err)
+	assert.Empty(t, records)
+
+	var buf bytes.Buffer
+	auditID := logEvent(t, &buf, audit.EventTypeMCPToolCall, audit.OutcomeSuccess, "alice", "fetch", 0)
+	path := filepath.Join(dir, "audit.log")
+	require.NoError(t, os.WriteFile(path, buf.Bytes(), 0600))
+
+	records, err = ReadFiles([]string{path}, Filter{})
+	require.NoError(t, err)
+	record, err := Find(records, auditID)
+	require.NoError(t, err)
+	assert.Equal(t, "alice", record.Subjects[audit.SubjectKeyUser])
+
+	_, err = Find(records, "unknown")
+	assert.ErrorIs(t, err, ErrEventNotFound)
+}
+
+func TestSources(t *testing.T) {
+	t.Parallel()
+
+	runConfig := &runner.RunConfig{
+		Name:        "fetch",
+		BaseName:    "fetch",
+		AuditConfig: &audit.Config{LogFile: "/var/log/toolhive/fetch.log"},
+	}
+	sources, err := Sources(runConfig)
+	require.NoError(t, err)
+	require.Len(t, sources, 2)
+	assert.Equal(t, "/var/log/toolhive/fetch.log", sources[0])
+	assert.Equal(t, "fetch.jsonl", filepath.Base(sources[1]))
+
+	// Without a log file, audit events are written to the output of the proxy
+	runConfig.AuditConfig.LogFile = ""
+	sources, err = Sources(runConfig)
+	require.NoError(t, err)
+	require.Len(t, sources, 2)
+	assert.Equal(t, "fetch.log", filepath.Base(sources[0]))
+	assert.Equal(t, "logs", filepath.Base(filepath.Dir(sources[0])))
+
+	// Without audit, only the egress log may hold audit events
+	runConfig.AuditConfig = nil
+	sources, err = Sources(runConfig)
+	require.NoError(t, err)
+	assert.Len(t, sources, 1)
+}
+
+func TestParseTime(t *testing.T) {
+	t.Parallel()
+
+	parsed, err := ParseTime("2025-06-02T10:00:00Z", testTime)
+	require.NoError(t, err)
+	assert.True(t, parsed.Equal(testTime))
+
+	parsed, err = ParseTime("24h", testTime)
+	require.NoError(t, err)
+	assert.True(t, parsed.Equal(testTime.Add(-24*time.Hour)))
+
+	parsed, err = ParseTime("2025-06-01", testTime)
+	require.NoError(t, err)
+	assert.Equal(t, 1, parsed.Day())
+
+	_, err = ParseTime("last week", testTime)
+	assert.ErrorContains(t, err, "invalid time")
+}
+
+func TestRecordSet(t *testing.T) {
+	t.Parallel()
+
+	// The most recent records are kept whatever the order they are read in
+	records := newRecordSet(Filter{Limit: 3})
+	for _, hour := range []int{5, 1, 7, 3, 6, 0, 2} {
+		records.add(&Record{Time: testTime.Add(time.Duration(hour) * time.Hour)})
+	}
+	var hours []int
+	for _, record := range records.sorted() {
+		hours = append(hours, int(record.Time.Sub(testTime).Hours()))
+	}
+	assert.Equal(t, []int{5, 6, 7}, hours)
+}
@@ -0,0 +1,181 @@
+package query
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/stacklok/toolhive/pkg/audit"
+	"github.com/stacklok/toolhive/pkg/container/process"
+	"github.com/stacklok/toolhive/pkg/container/runtime"
+	"github.com/stacklok/toolhive/pkg/core"
+	thverrors "github.com/stacklok/toolhive/pkg/errors"
+	"github.com/stacklok/toolhive/pkg/labels"
+	"github.com/stacklok/toolhive/pkg/logger"
+	"github.com/stacklok/toolhive/pkg/runner"
+	"github.com/stacklok/toolhive/pkg/transport/ssecommon"
+	"github.com/stacklok/toolhive/pkg/versions"
+	"github.com/stacklok/toolhive/pkg/workloads"
+)
+
+// replayClientName is the name of the MCP client replaying tool calls
+const replayClientName = "toolhive-audit-replay"
+
+// ErrNotReplayable is returned when an audit event does not record a tool call that can be replayed.
+var ErrNotReplayable = thverrors.New("audit event cannot be replayed", http.StatusBadRequest)
+
+// ToolCall is a tools/call request recorded in an audit event.
+type ToolCall struct {
+	// Name is the name of the tool
+	Name string `json:"name"`
+	// Arguments are the arguments of the call
+	Arguments map[string]any `json:"arguments,omitempty"`
+}
+
+// ToolCall returns the tools/call request recorded in the event. The request is only
+// recorded if the audit configuration includes the request data.
+func (r *Record) ToolCall() (*ToolCall, error) {
+	if r.Type != audit.EventTypeMCPToolCall {
+		return nil, fmt.Errorf("%w: %s is a %s event, not a tool call", ErrNotReplayable, r.AuditID, r.Type)
+	}
+
+	var data struct {
+		Request json.RawMessage `json:"request"`
+	}
+	if len(r.Data) > 0 {
+		if err := json.Unmarshal(r.Data, &data); err != nil {
+			return nil, fmt.Errorf("%w: invalid data in event %s: %v", ErrNotReplayable, r.AuditID, err)
+		}
+	}
+	if len(data.Request) == 0 {
+		return nil, fmt.Errorf("%w: event %s has no request data, "+
+			"set includeRequestData in the audit configuration to record it", ErrNotReplayable, r.AuditID)
+	}
+
+	var request struct {
+		Method string    `json:"method"`
+		Params *ToolCall `json:"params"`
+	}
+	if err := json.Unmarshal(data.Request, &request); err != nil ||
+		request.Method != string(mcp.MethodToolsCall) || request.Params == nil || request.Params.Name == "" {
+		return nil, fmt.Errorf("%w: the request of event %s is not a tools/call request", ErrNotReplayable, r.AuditID)
+	}
+	return request.Params, nil
+}
+
+// Replay calls a tool of the MCP server at serverURL with the arguments of a recorded call,
+// e.g. to reproduce an incident against a sandbox workload.
+func Replay(ctx context.Context, call *ToolCall, serverURL string) (*mcp.CallToolResult, error) {
+	mcpClient, err := newClient(serverURL)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = mcpClient.Close() }()
+
+	if err := mcpClient.Start(ctx); err != nil {
+		return nil, fmt.Errorf("failed to start MCP transport: %w", err)
+	}
+	initRequest := mcp.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	initRequest.Params.ClientInfo = mcp.Implementation{
+		Name:    replayClientName,
+		Version: versions.GetVersionInfo().Version,
+	}
+	if _, err := mcpClient.Initialize(ctx, initRequest); err != nil {
+		return nil, fmt.Errorf("failed to initialize MCP client: %w", err)
+	}
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = call.Name
+	request.Params.Arguments = call.Arguments
+	result, err := mcpClient.CallTool(ctx, request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call tool %s: %w", call.Name, err)
+	}
+	return result, nil
+}
+
+// ReplayOnWorkload replays a recorded tool call against a running sandbox workload. The workload must not
+// be the one the call was recorded for, and must run in a sandbox (see IsSandbox), as the call would
+// repeat its side effects.
+func ReplayOnWorkload(
+	ctx context.Context, manager workloads.Manager, record *Record, target string,
+) (*mcp.CallToolResult, error) {
+	if target == record.Workload {
+		return nil, thverrors.WithCode(
+			fmt.Errorf("event %s was recorded for workload %s, replay it against a sandbox workload", record.AuditID, target),
+			http.StatusBadRequest,
+		)
+	}
+	call, err := record.ToolCall()
+	if err != nil {
+		return nil, err
+	}
+
+	workload, err := manager.GetWorkload(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	if workload.Status != runtime.WorkloadStatusRunning {
+		return nil, thverrors.WithCode(
+			fmt.Errorf("workload %s is not running (status: %s)", target, workload.Status),
+			http.StatusConflict,
+		)
+	}
+
+	var runConfig *runner.RunConfig
+	if !labels.IsSandboxWorkload(workload.Labels) {
+		if runConfig, err = runner.LoadState(ctx, target); err != nil {
+			logger.Debugf("Failed to load run configuration of workload %s: %v", target, err)
+		}
+	}
+	if !IsSandbox(workload, runConfig) {
+		return nil, thverrors.WithCode(
+			fmt.Errorf("workload %s is not a sandbox: run it with the %s runtime, the network mode none "+
+				"or the %s=true label", target, process.RuntimeName, labels.LabelSandbox),
+			http.StatusBadRequest,
+		)
+	}
+	return Replay(ctx, call, workload.URL)
+}
+
+// IsSandbox returns true if a workload runs in a sandbox: it is labelled as such, which the process
+// runtime does for the workloads it confines, or its network mode is none. runConfig may be nil.
+func IsSandbox(workload core.Workload, runConfig *runner.RunConfig) bool {
+	if labels.IsSandboxWorkload(workload.Labels) {
+		return true
+	}
+	return runConfig != nil && runConfig.PermissionProfile != nil && runConfig.PermissionProfile.Network != nil &&
+		runConfig.PermissionProfile.Network.Mode == "none"
+}
+
+// newClient creates an MCP client for the URL of a workload, which ends with /sse for SSE servers
+func newClient(serverURL string) (*client.Client, error) {
+	parsed, err := url.Parse(serverURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid server URL %s: %w", serverURL, err)
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return nil, errors.New("replay requires an MCP server reachable over HTTP")
+	}
+
+	if strings.HasSuffix(parsed.Path, ssecommon.HTTPSSEEndpoint) {
+		mcpClient, err := client.NewSSEMCPClient(serverURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create SSE MCP client: %w", err)
+		}
+		return mcpClient, nil
+	}
+	mcpClient, err := client.NewStreamableHttpClient(serverURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Streamable HTTP MCP client: %w", err)
+	}
+	return mcpClient, nil
+}
@@ -0,0 +1,135 @@
+package query
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/stacklok/toolhive/pkg/audit"
+	"github.com/stacklok/toolhive/pkg/container/runtime"
+	"github.com/stacklok/toolhive/pkg/core"
+	thverrors "github.com/stacklok/toolhive/pkg/errors"
+	"github.com/stacklok/toolhive/pkg/labels"
+	"github.com/stacklok/toolhive/pkg/permissions"
+	"github.com/stacklok/toolhive/pkg/runner"
+	"github.com/stacklok/toolhive/pkg/workloads/mocks"
+)
+
+func toolCallRecord(data string) *Record {
+	return &Record{
+		Workload: "fetch",
+		AuditID:  "a1",
+		Type:     audit.EventTypeMCPToolCall,
+		Outcome:  audit.OutcomeSuccess,
+		Data:     json.RawMessage(data),
+	}
+}
+
+func TestRecord_ToolCall(t *testing.T) {
+	t.Parallel()
+
+	call, err := toolCallRecord(`{"request":{"jsonrpc":"2.0","id":1,"method":"tools/call",` +
+		`"params":{"name":"fetch","arguments":{"url":"https://example.com"}}}}`).ToolCall()
+	require.NoError(t, err)
+	assert.Equal(t, &ToolCall{Name: "fetch", Arguments: map[string]any{"url": "https://example.com"}}, call)
+
+	tests := []struct {
+		name    string
+		record  *Record
+		wantErr string
+	}{
+		{name: "no data", record: toolCallRecord(""), wantErr: "set includeRequestData"},
+		{name: "no request", record: toolCallRecord(`{"response":{}}`), wantErr: "set includeRequestData"},
+		{name: "request not JSON", record: toolCallRecord(`{"request":"not json"}`), wantErr: "not a tools/call request"},
+		{
+			name:    "other method",
+			record:  toolCallRecord(`{"request":{"method":"tools/list"}}`),
+			wantErr: "not a tools/call request",
+		},
+		{name: "not a tool call", record: &Record{AuditID: "a2", Type: audit.EventTypeMCPToolsList}, wantErr: "not a tool call"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := tt.record.ToolCall()
+			assert.ErrorIs(t, err, ErrNotReplayable)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestReplayOnWorkload(t *testing.T) {
+	t.Parallel()
+
+	mcpServer := server.NewMCPServer("sandbox", "1.0.0")
+	mcpServer.AddTool(mcp.NewTool("fetch", mcp.WithString("url")),
+		func(_ context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultText("fetched " + request.GetString("url", "")), nil
+		})
+	testServer := server.NewTestStreamableHTTPServer(mcpServer)
+	t.Cleanup(testServer.Close)
+
+	ctrl := gomock.NewController(t)
+	manager := mocks.NewMockManager(ctrl)
+	manager.EXPECT().GetWorkload(gomock.Any(), "sandbox").Return(core.Workload{
+		Name:   "sandbox",
+		URL:    testServer.URL + "/mcp",
+		Status: runtime.WorkloadStatusRunning,
+		Labels: map[string]string{labels.LabelSandbox: "true"},
+	}, nil)
+	manager.EXPECT().GetWorkload(gomock.Any(), "replay-test-unsandboxed").Return(core.Workload{
+		Name:   "replay-test-unsandboxed",
+		URL:    testServer.URL + "/mcp",
+		Status: runtime.WorkloadStatusRunning,
+	}, nil)
+
+	record := toolCallRecord(`{"request":{"jsonrpc":"2.0","id":1,"method":"tools/call",` +
+		`"params":{"name":"fetch","arguments":{"url":"https://example.com"}}}}`)
+	result, err := ReplayOnWorkload(context.Background(), manager, record, "sandbox")
+	require.NoError(t, err)
+	require.Len(t, result.Content, 1)
+	assert.Equal(t, "fetched https://example.com", result.Content[0].(mcp.TextContent).Text)
+
+	// Events are not replayed against the workload they were recorded for
+	_, err = ReplayOnWorkload(context.Background(), manager, record, "fetch")
+	assert.ErrorContains(t, err, "replay it against a sandbox workload")
+
+	// Nor against workloads that do not run in a sandbox
+	_, err = ReplayOnWorkload(context.Background(), manager, record, "replay-test-unsandboxed")
+	assert.ErrorContains(t, err, "workload replay-test-unsandboxed is not a sandbox")
+	assert.Equal(t, http.StatusBadRequest, thverrors.Code(err))
+}
+
+func TestIsSandbox(t *testing.T) {
+	t.Parallel()
+
+	withNetworkMode := func(mode string) *runner.RunConfig {
+		return &runner.RunConfig{PermissionProfile: &permissions.Profile{Network: &permissions.NetworkPermissions{Mode: mode}}}
+	}
+	tests := []struct {
+		name      string
+		labels    map[string]string
+		runConfig *runner.RunConfig
+		want      bool
+	}{
+		{name: "sandbox label", labels: map[string]string{labels.LabelSandbox: "true"}, want: true},
+		{name: "sandbox label disabled", labels: map[string]string{labels.LabelSandbox: "false"}},
+		{name: "network mode none", runConfig: withNetworkMode("none"), want: true},
+		{name: "bridge network", runConfig: withNetworkMode("bridge")},
+		{name: "no permission profile", runConfig: &runner.RunConfig{}},
+		{name: "no run configuration"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, IsSandbox(core.Workload{Labels: tt.labels}, tt.runConfig))
+		})
+	}
+}
@@ -0,0 +1,256 @@
+package audit
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/stacklok/toolhive/pkg/logger"
+)
+
+// Sink types
+const (
+	// SinkTypeSyslog sends audit events to a syslog server (RFC 5424)
+	SinkTypeSyslog = "syslog"
+	// SinkTypeWebhook sends batches of audit events to an HTTP collector
+	SinkTypeWebhook = "webhook"
+	// SinkTypeOTLP sends audit events as OpenTelemetry logs to an OTLP/HTTP collector
+	SinkTypeOTLP = "otlp"
+)
+
+// Sink is a destination of audit events.
+// Write receives exactly one JSON-encoded audit event per call, and must not retain the slice
+// after returning. Close flushes the events that have not been delivered yet.
+type Sink interface {
+	io.WriteCloser
+}
+
+// SinkConfig configures a destination of audit events in addition to the log file.
+// Exactly one of Syslog, Webhook and OTLP must be set, matching Type.
+// +kubebuilder:object:generate=true
+// +gendoc
+type SinkConfig struct {
+	// Type is the type of the sink.
+	// +kubebuilder:validation:Enum=syslog;webhook;otlp
+	Type string `json:"type" yaml:"type"`
+	// Syslog configures a syslog sink.
+	// +optional
+	Syslog *SyslogSinkConfig `json:"syslog,omitempty" yaml:"syslog,omitempty"`
+	// Webhook configures an HTTP webhook sink.
+	// +optional
+	Webhook *WebhookSinkConfig `json:"webhook,omitempty" yaml:"webhook,omitempty"`
+	// OTLP configures an OTLP logs sink.
+	// +optional
+	OTLP *OTLPSinkConfig `json:"otlp,omitempty" yaml:"otlp,omitempty"`
+}
+
+// BatchConfig configures the batching, retries and disk buffering of the sinks that deliver
+// events in batches.
+// +kubebuilder:object:generate=true
+// +gendoc
+type BatchConfig struct {
+	// MaxBatchSize is the maximum number of events sent in one request.
+	// +kubebuilder:default=100
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxBatchSize int `json:"maxBatchSize,omitempty" yaml:"maxBatchSize,omitempty"`
+	// FlushInterval is the maximum time an event waits before being sent, e.g. "5s".
+	// +kubebuilder:default="5s"
+	// +optional
+	FlushInterval string `json:"flushInterval,omitempty" yaml:"flushInterval,omitempty"`
+	// MaxRetries is the number of times a failed request is retried, with exponential backoff.
+	// 0 disables retries. Defaults to 3 when unset.
+	// +kubebuilder:default=3
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxRetries *int `json:"maxRetries,omitempty" yaml:"maxRetries,omitempty"`
+	// Timeout is the timeout of a request, e.g. "10s".
+	// +kubebuilder:default="10s"
+	// +optional
+	Timeout string `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	// BufferDir is a directory where the events that could not be delivered are kept
+	// until the collector is available again. If empty, these events are dropped.
+	// +optional
+	BufferDir string `json:"bufferDir,omitempty" yaml:"bufferDir,omitempty"`
+	// MaxBufferBytes limits the size of the disk buffer. Events are dropped when it is full.
+	// +kubebuilder:default=104857600
+	// +optional
+	MaxBufferBytes int64 `json:"maxBufferBytes,omitempty" yaml:"maxBufferBytes,omitempty"`
+}
+
+// Defaults of the batch configuration
+const (
+	defaultMaxBatchSize   = 100
+	defaultFlushInterval  = 5 * time.Second
+	defaultMaxRetries     = 3
+	defaultSinkTimeout    = 10 * time.Second
+	defaultMaxBufferBytes = 100 * 1024 * 1024
+)
+
+// Validate validates the sink configuration.
+func (c *SinkConfig) Validate() error {
+	set := 0
+	for _, configured := range []bool{c.Syslog != nil, c.Webhook != nil, c.OTLP != nil} {
+		if configured {
+			set++
+		}
+	}
+	if set > 1 {
+		return fmt.Errorf("sink of type %q must only set the %s configuration", c.Type, c.Type)
+	}
+
+	switch c.Type {
+	case SinkTypeSyslog:
+		if c.Syslog == nil {
+			return fmt.Errorf("syslog sink requires the syslog configuration")
+		}
+		return c.Syslog.Validate()
+	case SinkTypeWebhook:
+		if c.Webhook == nil {
+			return fmt.Errorf("webhook sink requires the webhook configuration")
+		}
+		return c.Webhook.Validate()
+	case SinkTypeOTLP:
+		if c.OTLP == nil {
+			return fmt.Errorf("otlp sink requires the otlp configuration")
+		}
+		return c.OTLP.Validate()
+	default:
+		return fmt.Errorf("unknown sink type %q: must be one of %s, %s or %s",
+			c.Type, SinkTypeSyslog, SinkTypeWebhook, SinkTypeOTLP)
+	}
+}
+
+// Validate validates the batch configuration.
+func (c *BatchConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	if c.MaxBatchSize < 0 {
+		return fmt.Errorf("maxBatchSize cannot be negative")
+	}
+	if c.MaxRetries != nil && *c.MaxRetries < 0 {
+		return fmt.Errorf("maxRetries cannot be negative")
+	}
+	if c.MaxBufferBytes < 0 {
+		return fmt.Errorf("maxBufferBytes cannot be negative")
+	}
+	if _, err := parseOptionalDuration(c.FlushInterval, defaultFlushInterval); err != nil {
+		return fmt.Errorf("invalid flushInterval: %w", err)
+	}
+	if _, err := parseOptionalDuration(c.Timeout, defaultSinkTimeout); err != nil {
+		return fmt.Errorf("invalid timeout: %w", err)
+	}
+	return nil
+}
+
+// NewSink creates the sink of a sink configuration.
+func NewSink(config *SinkConfig) (Sink, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	switch config.Type {
+	case SinkTypeSyslog:
+		return NewSyslogSink(config.Syslog)
+	case SinkTypeWebhook:
+		return NewWebhookSink(config.Webhook)
+	case SinkTypeOTLP:
+		return NewOTLPSink(config.OTLP)
+	}
+	return nil, fmt.Errorf("unknown sink type %q", config.Type)
+}
+
+// fanOutWriter writes each audit event to the log writer and to all the sinks.
+// A failing sink does not prevent the event from being written to the others.
+type fanOutWriter struct {
+	primary io.Writer
+	sinks   []Sink
+}
+
+// newFanOutWriter creates the sinks of the configurations, and a writer writing to them and to the
+// primary writer. The sinks created before a failure are closed.
+func newFanOutWriter(primary io.Writer, configs []SinkConfig) (*fanOutWriter, error) {
+	sinks := make([]Sink, 0, len(configs))
+	for i := range configs {
+		sink, err := NewSink(&configs[i])
+		if err != nil {
+			for _, created := range sinks {
+				_ = created.Close()
+			}
+			return nil, fmt.Errorf("failed to create audit sink %d (%s): %w", i, configs[i].Type, err)
+		}
+		sinks = append(sinks, sink)
+	}
+	return &fanOutWriter{primary: primary, sinks: sinks}, nil
+}
+
+// Write writes an audit event to the log writer and to all the sinks.
+func (f *fanOutWriter) Write(p []byte) (int, error) {
+	var errs []error
+	if _, err := f.primary.Write(p); err != nil {
+		errs = append(errs, err)
+	}
+	for _, sink := range f.sinks {
+		if _, err := sink.Write(p); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return 0, errors.Join(errs...)
+	}
+	return len(p), nil
+}
+
+// Close closes all the sinks, and the log writer unless it is stdout.
+func (f *fanOutWriter) Close() error {
+	var errs []error
+	for _, sink := range f.sinks {
+		if err := sink.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if closer, ok := f.primary.(io.Closer); ok && f.primary != os.Stdout {
+		if err := closer.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// eventHeader holds the fields of a serialized audit event that sinks map to their own metadata
+type eventHeader struct {
+	AuditID   string    `json:"audit_id"`
+	Type      string    `json:"type"`
+	LoggedAt  time.Time `json:"logged_at"`
+	Outcome   string    `json:"outcome"`
+	Component string    `json:"component"`
+}
+
+// parseEventHeader extracts the header of a serialized audit event.
+// Events that cannot be parsed get an empty header, so that they are still delivered.
+func parseEventHeader(event []byte) eventHeader {
+	var header eventHeader
+	if err := json.Unmarshal(event, &header); err != nil {
+		logger.Debugf("Failed to parse audit event header: %v", err)
+	}
+	return header
+}
+
+// parseOptionalDuration parses a duration, returning the default if it is empty.
+func parseOptionalDuration(value string, defaultValue time.Duration) (time.Duration, error) {
+	if value == "" {
+		return defaultValue, nil
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, err
+	}
+	if duration <= 0 {
+		return 0, fmt.Errorf("duration must be positive")
+	}
+	return duration, nil
+}
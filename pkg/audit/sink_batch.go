@@ -0,0 +1,359 @@
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/stacklok/toolhive/pkg/logger"
+)
+
+// initialRetryBackoff is the delay before the first retry of a failed request
+const initialRetryBackoff = 500 * time.Millisecond
+
+// batchSender delivers a batch of serialized audit events to a collector
+type batchSender func(ctx context.Context, events [][]byte) error
+
+// permanentError marks delivery errors that are not retried, such as payloads rejected by the collector
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// batchingSink queues audit events and delivers them in batches from a background goroutine,
+// so that slow collectors do not delay requests. Batches that cannot be delivered after the
+// retries are kept in a disk buffer, if configured, and delivered before newer events.
+type batchingSink struct {
+	name          string
+	send          batchSender
+	maxBatchSize  int
+	flushInterval time.Duration
+	maxRetries    int
+	timeout       time.Duration
+	buffer        *diskBuffer
+
+	mu      sync.Mutex
+	pending [][]byte
+
+	flush     chan struct{}
+	done      chan struct{}
+	stopped   chan struct{}
+	closeOnce sync.Once
+}
+
+// newBatchingSink creates a batching sink and starts its delivery goroutine
+func newBatchingSink(name string, config *BatchConfig, send batchSender) (*batchingSink, error) {
+	if config == nil {
+		config = &BatchConfig{}
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	s := &batchingSink{
+		name:         name,
+		send:         send,
+		maxBatchSize: config.MaxBatchSize,
+		maxRetries:   defaultMaxRetries,
+		flush:        make(chan struct{}, 1),
+		done:         make(chan struct{}),
+		stopped:      make(chan struct{}),
+	}
+	if s.maxBatchSize == 0 {
+		s.maxBatchSize = defaultMaxBatchSize
+	}
+	if config.MaxRetries != nil {
+		s.maxRetries = *config.MaxRetries
+	}
+	// Validate has checked the durations
+	s.flushInterval, _ = parseOptionalDuration(config.FlushInterval, defaultFlushInterval)
+	s.timeout, _ = parseOptionalDuration(config.Timeout, defaultSinkTimeout)
+
+	if config.BufferDir != "" {
+		buffer, err := newDiskBuffer(config.BufferDir, name, config.MaxBufferBytes)
+		if err != nil {
+			return nil, err
+		}
+		s.buffer = buffer
+	}
+
+	go s.run()
+	return s, nil
+}
+
+// Write queues an audit event for delivery.
+func (s *batchingSink) Write(p []byte) (int, error) {
+	event := bytes.TrimSpace(p)
+	if len(event) == 0 {
+		return len(p), nil
+	}
+
+	s.mu.Lock()
+	s.pending = append(s.pending, bytes.Clone(event))
+	full := len(s.pending) >= s.maxBatchSize
+	s.mu.Unlock()
+
+	if full {
+		select {
+		case s.flush <- struct{}{}:
+		default:
+		}
+	}
+	return len(p), nil
+}
+
+// Close delivers the queued events and stops the delivery goroutine.
+// Events that cannot be delivered without retrying are kept in the disk buffer, if configured.
+func (s *batchingSink) Close() error {
+	s.closeOnce.Do(func() {
+		close(s.done)
+	})
+	<-s.stopped
+	return nil
+}
+
+func (s *batchingSink) run() {
+	defer close(s.stopped)
+
+	ticker := time.NewTicker(s.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.deliver()
+		case <-s.flush:
+			s.deliver()
+		case <-s.done:
+			s.deliver()
+			return
+		}
+	}
+}
+
+// deliver sends the buffered events, then the queued events, in batches
+func (s *batchingSink) deliver() {
+	s.mu.Lock()
+	events := s.pending
+	s.pending = nil
+	s.mu.Unlock()
+
+	// Older events are delivered first, and newer events wait while the collector is unavailable
+	if s.buffer != nil && !s.deliverBuffered() {
+		s.keep(events)
+		return
+	}
+
+	for len(events) > 0 {
+		n := min(len(events), s.maxBatchSize)
+		if err := s.sendWithRetries(events[:n]); err != nil {
+			var permanent *permanentError
+			if errors.As(err, &permanent) {
+				logger.Errorf("Audit sink %s rejected %d events: %v", s.name, n, err)
+				events = events[n:]
+				continue
+			}
+			logger.Warnf("Failed to deliver audit events to sink %s: %v", s.name, err)
+			s.keep(events)
+			return
+		}
+		events = events[n:]
+	}
+}
+
+// deliverBuffered sends the events of the disk buffer, returning false if they could not all be delivered
+func (s *batchingSink) deliverBuffered() bool {
+	delivered := true
+	err := s.buffer.drain(func(events [][]byte) [][]byte {
+		for len(events) > 0 {
+			n := min(len(events), s.maxBatchSize)
+			err := s.sendWithRetries(events[:n])
+			var permanent *permanentError
+			if err != nil && !errors.As(err, &permanent) {
+				delivered = false
+				return events
+			}
+			if err != nil {
+				logger.Errorf("Audit sink %s rejected %d buffered events: %v", s.name, n, err)
+			}
+			events = events[n:]
+		}
+		return nil
+	})
+	if err != nil {
+		logger.Errorf("Failed to update audit buffer of sink %s: %v", s.name, err)
+	}
+	return delivered
+}
+
+// keep stores undelivered events in the disk buffer, or drops them if there is none
+func (s *batchingSink) keep(events [][]byte) {
+	if len(events) == 0 {
+		return
+	}
+	if s.buffer == nil {
+		logger.Errorf("Dropped %d audit events of sink %s", len(events), s.name)
+		return
+	}
+	if err := s.buffer.append(events); err != nil {
+		logger.Errorf("Dropped %d audit events of sink %s: %v", len(events), s.name, err)
+	}
+}
+
+// sendWithRetries sends a batch, retrying with exponential backoff.
+// Retries stop when the sink is closed, so that closing does not wait for an unavailable collector.
+func (s *batchingSink) sendWithRetries(events [][]byte) error {
+	backoff := initialRetryBackoff
+	var err error
+	for attempt := 0; ; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+		err = s.send(ctx, events)
+		cancel()
+
+		var permanent *permanentError
+		if err == nil || errors.As(err, &permanent) || attempt >= s.maxRetries {
+			return err
+		}
+
+		select {
+		case <-s.done:
+			return err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// diskBuffer keeps undelivered audit events in a file, one event per line.
+// Sinks with the same destination share the buffer of that destination.
+type diskBuffer struct {
+	path     string
+	maxBytes int64
+	mu       sync.Mutex
+}
+
+var (
+	diskBuffers   = make(map[string]*diskBuffer)
+	diskBuffersMu sync.Mutex
+)
+
+func newDiskBuffer(dir, name string, maxBytes int64) (*diskBuffer, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create audit buffer directory: %w", err)
+	}
+	if maxBytes == 0 {
+		maxBytes = defaultMaxBufferBytes
+	}
+	path := filepath.Join(filepath.Clean(dir), name+".ndjson")
+
+	diskBuffersMu.Lock()
+	defer diskBuffersMu.Unlock()
+	if buffer, ok := diskBuffers[path]; ok {
+		return buffer, nil
+	}
+	buffer := &diskBuffer{path: path, maxBytes: maxBytes}
+	diskBuffers[path] = buffer
+	return buffer, nil
+}
+
+// drain passes the buffered events to deliver, and keeps the events it returns.
+// The buffer is locked while deliver runs, so that events are not appended meanwhile.
+func (b *diskBuffer) drain(deliver func(events [][]byte) [][]byte) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	events, err := b.load()
+	if err != nil || len(events) == 0 {
+		return err
+	}
+	remaining := deliver(events)
+	if len(remaining) == len(events) {
+		return nil
+	}
+	return b.replace(remaining)
+}
+
+// append adds events to the buffer, or returns an error if the buffer would exceed its size
+func (b *diskBuffer) append(events [][]byte) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	data := joinLines(events)
+	var size int64
+	if info, err := os.Stat(b.path); err == nil {
+		size = info.Size()
+	}
+	if size+int64(len(data)) > b.maxBytes {
+		return fmt.Errorf("audit buffer %s is full", b.path)
+	}
+
+	file, err := os.OpenFile(b.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// load returns the buffered events. The caller must hold the lock.
+func (b *diskBuffer) load() ([][]byte, error) {
+	data, err := os.ReadFile(b.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var events [][]byte
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(data)+1)
+	for scanner.Scan() {
+		if line := scanner.Bytes(); len(line) > 0 {
+			events = append(events, bytes.Clone(line))
+		}
+	}
+	return events, scanner.Err()
+}
+
+// replace replaces the content of the buffer with the given events. The caller must hold the lock.
+func (b *diskBuffer) replace(events [][]byte) error {
+	if len(events) == 0 {
+		if err := os.Remove(b.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+
+	// Write to a temporary file first, so that a crash does not lose the buffered events
+	tmp := b.path + ".tmp"
+	if err := os.WriteFile(tmp, joinLines(events), 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, b.path)
+}
+
+func joinLines(events [][]byte) []byte {
+	var buf bytes.Buffer
+	for _, event := range events {
+		buf.Write(event)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes()
+}
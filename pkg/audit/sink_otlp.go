@@ -0,0 +1,142 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	collectorlogs "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	commonv1 "go.opentelemetry.io/proto/otlp/common/v1"
+	logsv1 "go.opentelemetry.io/proto/otlp/logs/v1"
+	resourcev1 "go.opentelemetry.io/proto/otlp/resource/v1"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/stacklok/toolhive/pkg/versions"
+)
+
+// otlpLogsPath is the default path of the OTLP/HTTP logs endpoint
+const otlpLogsPath = "/v1/logs"
+
+// otlpScopeName is the instrumentation scope of the audit log records
+const otlpScopeName = "github.com/stacklok/toolhive/pkg/audit"
+
+// OTLPSinkConfig configures a sink exporting audit events as OpenTelemetry log records
+// to an OTLP/HTTP collector. The body of each record is the JSON audit event.
+// +kubebuilder:object:generate=true
+// +gendoc
+type OTLPSinkConfig struct {
+	// Endpoint is the URL of the OTLP/HTTP collector, e.g. "http://otel-collector:4318".
+	// The /v1/logs path is used unless the URL has a path.
+	// +kubebuilder:validation:Required
+	Endpoint string `json:"endpoint" yaml:"endpoint"`
+	// Headers are additional HTTP headers of the export requests.
+	// +optional
+	Headers map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+	// BearerTokenEnv is the name of an environment variable holding a bearer token
+	// sent in the Authorization header.
+	// +optional
+	BearerTokenEnv string `json:"bearerTokenEnv,omitempty" yaml:"bearerTokenEnv,omitempty"`
+	// ServiceName is the service.name resource attribute of the log records.
+	// +kubebuilder:default="toolhive"
+	// +optional
+	ServiceName string `json:"serviceName,omitempty" yaml:"serviceName,omitempty"`
+	// Batch configures the batching, retries and disk buffering of the events.
+	// +optional
+	Batch *BatchConfig `json:"batch,omitempty" yaml:"batch,omitempty"`
+}
+
+// Validate validates the OTLP sink configuration.
+func (c *OTLPSinkConfig) Validate() error {
+	if err := validateCollectorURL(c.Endpoint); err != nil {
+		return fmt.Errorf("invalid otlp endpoint: %w", err)
+	}
+	return c.Batch.Validate()
+}
+
+// logsURL returns the URL of the logs endpoint of the collector
+func (c *OTLPSinkConfig) logsURL() string {
+	parsed, err := url.Parse(c.Endpoint)
+	if err != nil || (parsed.Path != "" && parsed.Path != "/") {
+		return c.Endpoint
+	}
+	parsed.Path = otlpLogsPath
+	return parsed.String()
+}
+
+// NewOTLPSink creates a sink exporting audit events to an OTLP/HTTP collector.
+func NewOTLPSink(config *OTLPSinkConfig) (Sink, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	endpoint := config.logsURL()
+	serviceName := config.ServiceName
+	if serviceName == "" {
+		serviceName = "toolhive"
+	}
+	resource := &resourcev1.Resource{
+		Attributes: []*commonv1.KeyValue{
+			stringAttribute("service.name", serviceName),
+			stringAttribute("service.version", versions.Version),
+		},
+	}
+
+	headers := collectorHeaders(config.Headers, config.BearerTokenEnv)
+	headers.Set("Content-Type", "application/x-protobuf")
+	client := &http.Client{}
+
+	send := func(ctx context.Context, events [][]byte) error {
+		body, err := proto.Marshal(newExportLogsRequest(resource, events, time.Now()))
+		if err != nil {
+			return &permanentError{err: fmt.Errorf("failed to encode log records: %w", err)}
+		}
+		return postToCollector(ctx, client, endpoint, headers, body)
+	}
+	return newBatchingSink(sinkName(SinkTypeOTLP, endpoint), config.Batch, send)
+}
+
+// newExportLogsRequest converts serialized audit events to an OTLP export request
+func newExportLogsRequest(
+	resource *resourcev1.Resource, events [][]byte, observedAt time.Time,
+) *collectorlogs.ExportLogsServiceRequest {
+	records := make([]*logsv1.LogRecord, 0, len(events))
+	for _, event := range events {
+		header := parseEventHeader(event)
+		record := &logsv1.LogRecord{
+			ObservedTimeUnixNano: uint64(observedAt.UnixNano()), // #nosec G115 - timestamps are positive
+			SeverityNumber:       logsv1.SeverityNumber_SEVERITY_NUMBER_INFO2,
+			SeverityText:         "AUDIT",
+			EventName:            header.Type,
+			Body:                 &commonv1.AnyValue{Value: &commonv1.AnyValue_StringValue{StringValue: string(event)}},
+			Attributes: []*commonv1.KeyValue{
+				stringAttribute("audit.id", header.AuditID),
+				stringAttribute("audit.type", header.Type),
+				stringAttribute("audit.outcome", header.Outcome),
+				stringAttribute("audit.component", header.Component),
+			},
+		}
+		if !header.LoggedAt.IsZero() {
+			record.TimeUnixNano = uint64(header.LoggedAt.UnixNano()) // #nosec G115 - timestamps are positive
+		}
+		records = append(records, record)
+	}
+
+	return &collectorlogs.ExportLogsServiceRequest{
+		ResourceLogs: []*logsv1.ResourceLogs{{
+			Resource: resource,
+			ScopeLogs: []*logsv1.ScopeLogs{{
+				Scope:      &commonv1.InstrumentationScope{Name: otlpScopeName},
+				LogRecords: records,
+			}},
+		}},
+	}
+}
+
+func stringAttribute(key, value string) *commonv1.KeyValue {
+	return &commonv1.KeyValue{
+		Key:   key,
+		Value: &commonv1.AnyValue{Value: &commonv1.AnyValue_StringValue{StringValue: value}},
+	}
+}
@@ -0,0 +1,92 @@
+package audit
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	collectorlogs "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestOTLPSinkConfig_LogsURL(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		endpoint string
+		want     string
+	}{
+		{endpoint: "http://otel-collector:4318", want: "http://otel-collector:4318/v1/logs"},
+		{endpoint: "http://otel-collector:4318/", want: "http://otel-collector:4318/v1/logs"},
+		{endpoint: "https://otlp.example.com/custom/logs", want: "https://otlp.example.com/custom/logs"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.endpoint, func(t *testing.T) {
+			t.Parallel()
+			config := &OTLPSinkConfig{Endpoint: tt.endpoint}
+			assert.Equal(t, tt.want, config.logsURL())
+		})
+	}
+}
+
+func TestOTLPSink_Export(t *testing.T) {
+	t.Parallel()
+
+	requests := make(chan *collectorlogs.ExportLogsServiceRequest, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, otlpLogsPath, r.URL.Path)
+		assert.Equal(t, "application/x-protobuf", r.Header.Get("Content-Type"))
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		request := &collectorlogs.ExportLogsServiceRequest{}
+		require.NoError(t, proto.Unmarshal(body, request))
+		requests <- request
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	sink, err := NewOTLPSink(&OTLPSinkConfig{
+		Endpoint:    server.URL,
+		ServiceName: "audit-test",
+		Batch:       &BatchConfig{FlushInterval: "1h"},
+	})
+	require.NoError(t, err)
+
+	event := `{"audit_id":"a1","type":"mcp_tool_call","logged_at":"2025-01-02T03:04:05.000000006Z",` +
+		`"outcome":"denied","component":"fetch"}`
+	_, err = sink.Write([]byte(event + "\n"))
+	require.NoError(t, err)
+	require.NoError(t, sink.Close())
+
+	request := <-requests
+	require.Len(t, request.ResourceLogs, 1)
+	resourceLogs := request.ResourceLogs[0]
+	assert.Equal(t, "service.name", resourceLogs.Resource.Attributes[0].Key)
+	assert.Equal(t, "audit-test", resourceLogs.Resource.Attributes[0].Value.GetStringValue())
+
+	require.Len(t, resourceLogs.ScopeLogs, 1)
+	assert.Equal(t, otlpScopeName, resourceLogs.ScopeLogs[0].Scope.Name)
+	require.Len(t, resourceLogs.ScopeLogs[0].LogRecords, 1)
+	record := resourceLogs.ScopeLogs[0].LogRecords[0]
+
+	assert.Equal(t, event, record.Body.GetStringValue())
+	assert.Equal(t, "mcp_tool_call", record.EventName)
+	loggedAt := time.Date(2025, 1, 2, 3, 4, 5, 6, time.UTC)
+	assert.Equal(t, uint64(loggedAt.UnixNano()), record.TimeUnixNano)
+
+	attributes := make(map[string]string)
+	for _, attribute := range record.Attributes {
+		attributes[attribute.Key] = attribute.Value.GetStringValue()
+	}
+	assert.Equal(t, map[string]string{
+		"audit.id":        "a1",
+		"audit.type":      "mcp_tool_call",
+		"audit.outcome":   "denied",
+		"audit.component": "fetch",
+	}, attributes)
+}
@@ -0,0 +1,329 @@
+package audit
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/stacklok/toolhive/pkg/logger"
+)
+
+// Syslog networks
+const (
+	// SyslogNetworkTCP sends syslog messages over TCP with octet counting framing (RFC 6587)
+	SyslogNetworkTCP = "tcp"
+	// SyslogNetworkTLS sends syslog messages over TLS with octet counting framing (RFC 5425)
+	SyslogNetworkTLS = "tls"
+	// SyslogNetworkUDP sends one syslog message per datagram (RFC 5426)
+	SyslogNetworkUDP = "udp"
+)
+
+const (
+	// syslogQueueSize is the number of events waiting to be sent before new events are dropped
+	syslogQueueSize = 1024
+	// syslogDialTimeout is the timeout of connections to the syslog server
+	syslogDialTimeout = 10 * time.Second
+	// syslogWriteTimeout is the timeout of writes to the syslog server
+	syslogWriteTimeout = 10 * time.Second
+	// syslogRetryDelay is the delay before reconnecting to an unavailable syslog server
+	syslogRetryDelay = 5 * time.Second
+)
+
+// syslog severities of audit events (RFC 5424 section 6.2.1)
+const (
+	syslogSeverityWarning = 4
+	syslogSeverityNotice  = 5
+)
+
+// syslogFacilities maps facility names to their codes (RFC 5424 section 6.2.1)
+var syslogFacilities = map[string]int{
+	"kern": 0, "user": 1, "mail": 2, "daemon": 3, "auth": 4, "syslog": 5, "lpr": 6, "news": 7,
+	"uucp": 8, "cron": 9, "authpriv": 10, "ftp": 11, "ntp": 12, "audit": 13, "alert": 14, "clock": 15,
+	"local0": 16, "local1": 17, "local2": 18, "local3": 19, "local4": 20, "local5": 21, "local6": 22, "local7": 23,
+}
+
+// SyslogSinkConfig configures a sink sending audit events to a syslog server in the RFC 5424 format.
+// The message of each event is the JSON audit event, and its MSGID is the event type.
+// +kubebuilder:object:generate=true
+// +gendoc
+type SyslogSinkConfig struct {
+	// Address is the host:port of the syslog server.
+	// +kubebuilder:validation:Required
+	Address string `json:"address" yaml:"address"`
+	// Network is the transport to the syslog server.
+	// +kubebuilder:validation:Enum=tcp;tls;udp
+	// +kubebuilder:default=tcp
+	// +optional
+	Network string `json:"network,omitempty" yaml:"network,omitempty"`
+	// Facility is the syslog facility of the events, e.g. "auth", "audit" or "local0".
+	// +kubebuilder:default=auth
+	// +optional
+	Facility string `json:"facility,omitempty" yaml:"facility,omitempty"`
+	// AppName is the APP-NAME of the messages.
+	// +kubebuilder:default=toolhive
+	// +optional
+	AppName string `json:"appName,omitempty" yaml:"appName,omitempty"`
+	// CACertPath is the path to a CA certificate bundle verifying the certificate of the
+	// syslog server with the tls network. If empty, the system roots are used.
+	// +optional
+	CACertPath string `json:"caCertPath,omitempty" yaml:"caCertPath,omitempty"`
+}
+
+// Validate validates the syslog sink configuration.
+func (c *SyslogSinkConfig) Validate() error {
+	if _, _, err := net.SplitHostPort(c.Address); err != nil {
+		return fmt.Errorf("invalid syslog address %q: %w", c.Address, err)
+	}
+	switch c.Network {
+	case "", SyslogNetworkTCP, SyslogNetworkTLS, SyslogNetworkUDP:
+	default:
+		return fmt.Errorf("unknown syslog network %q: must be one of %s, %s or %s",
+			c.Network, SyslogNetworkTCP, SyslogNetworkTLS, SyslogNetworkUDP)
+	}
+	if c.Facility != "" {
+		if _, ok := syslogFacilities[c.Facility]; !ok {
+			return fmt.Errorf("unknown syslog facility %q", c.Facility)
+		}
+	}
+	if c.CACertPath != "" && c.Network != SyslogNetworkTLS {
+		return fmt.Errorf("caCertPath requires the %s network", SyslogNetworkTLS)
+	}
+	return nil
+}
+
+// syslogSink sends audit events to a syslog server from a background goroutine,
+// reconnecting when the connection fails
+type syslogSink struct {
+	network   string
+	address   string
+	tlsConfig *tls.Config
+	facility  int
+	appName   string
+	hostname  string
+	procID    string
+
+	queue     chan []byte
+	done      chan struct{}
+	stopped   chan struct{}
+	closeOnce sync.Once
+	conn      net.Conn
+}
+
+// NewSyslogSink creates a sink sending audit events to a syslog server.
+func NewSyslogSink(config *SyslogSinkConfig) (Sink, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	s := &syslogSink{
+		network:  config.Network,
+		address:  config.Address,
+		facility: syslogFacilities["auth"],
+		appName:  config.AppName,
+		procID:   strconv.Itoa(os.Getpid()),
+		queue:    make(chan []byte, syslogQueueSize),
+		done:     make(chan struct{}),
+		stopped:  make(chan struct{}),
+	}
+	if s.network == "" {
+		s.network = SyslogNetworkTCP
+	}
+	if config.Facility != "" {
+		s.facility = syslogFacilities[config.Facility]
+	}
+	if s.appName == "" {
+		s.appName = "toolhive"
+	}
+	s.hostname, _ = os.Hostname()
+	if s.hostname == "" {
+		s.hostname = "-"
+	}
+
+	if s.network == SyslogNetworkTLS {
+		tlsConfig, err := syslogTLSConfig(config)
+		if err != nil {
+			return nil, err
+		}
+		s.tlsConfig = tlsConfig
+	}
+
+	go s.run()
+	return s, nil
+}
+
+func syslogTLSConfig(config *SyslogSinkConfig) (*tls.Config, error) {
+	host, _, _ := net.SplitHostPort(config.Address)
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: host,
+	}
+	if config.CACertPath == "" {
+		return tlsConfig, nil
+	}
+
+	caCert, err := os.ReadFile(filepath.Clean(config.CACertPath))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read syslog CA certificate: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caCert) {
+		return nil, fmt.Errorf("no certificates found in %s", config.CACertPath)
+	}
+	tlsConfig.RootCAs = pool
+	return tlsConfig, nil
+}
+
+// Write queues an audit event to be sent, or drops it if the queue is full.
+func (s *syslogSink) Write(p []byte) (int, error) {
+	event := bytes.TrimSpace(p)
+	if len(event) == 0 {
+		return len(p), nil
+	}
+
+	select {
+	case s.queue <- bytes.Clone(event):
+		return len(p), nil
+	default:
+		return 0, fmt.Errorf("syslog queue of %s is full, dropping audit event", s.address)
+	}
+}
+
+// Close sends the queued events and closes the connection.
+func (s *syslogSink) Close() error {
+	s.closeOnce.Do(func() {
+		close(s.done)
+	})
+	<-s.stopped
+	return nil
+}
+
+func (s *syslogSink) run() {
+	defer close(s.stopped)
+	defer s.disconnect()
+
+	for {
+		select {
+		case event := <-s.queue:
+			s.send(event)
+		case <-s.done:
+			// Send the remaining events without waiting for an unavailable server
+			for {
+				select {
+				case event := <-s.queue:
+					if !s.sendOnce(s.format(event, time.Now())) {
+						return
+					}
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// send sends an event, reconnecting and retrying until it is sent or the sink is closed
+func (s *syslogSink) send(event []byte) {
+	message := s.format(event, time.Now())
+	for !s.sendOnce(message) {
+		select {
+		case <-s.done:
+			logger.Errorf("Dropped audit event for syslog server %s", s.address)
+			return
+		case <-time.After(syslogRetryDelay):
+		}
+	}
+}
+
+// sendOnce writes a message on the connection, connecting first if needed
+func (s *syslogSink) sendOnce(message []byte) bool {
+	if s.conn == nil {
+		conn, err := s.dial()
+		if err != nil {
+			logger.Warnf("Failed to connect to syslog server %s: %v", s.address, err)
+			return false
+		}
+		s.conn = conn
+	}
+
+	frame := message
+	if s.network != SyslogNetworkUDP {
+		// Octet counting framing: MSG-LEN SP SYSLOG-MSG
+		frame = append([]byte(strconv.Itoa(len(message))+" "), message...)
+	}
+	if err := s.conn.SetWriteDeadline(time.Now().Add(syslogWriteTimeout)); err != nil {
+		logger.Debugf("Failed to set syslog write deadline: %v", err)
+	}
+	if _, err := s.conn.Write(frame); err != nil {
+		logger.Warnf("Failed to send audit event to syslog server %s: %v", s.address, err)
+		s.disconnect()
+		return false
+	}
+	return true
+}
+
+func (s *syslogSink) dial() (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: syslogDialTimeout}
+	switch s.network {
+	case SyslogNetworkTLS:
+		return tls.DialWithDialer(dialer, "tcp", s.address, s.tlsConfig)
+	default:
+		return dialer.Dial(s.network, s.address)
+	}
+}
+
+func (s *syslogSink) disconnect() {
+	if s.conn != nil {
+		_ = s.conn.Close()
+		s.conn = nil
+	}
+}
+
+// format formats an audit event as an RFC 5424 message:
+// <PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID STRUCTURED-DATA MSG
+func (s *syslogSink) format(event []byte, now time.Time) []byte {
+	header := parseEventHeader(event)
+
+	severity := syslogSeverityNotice
+	switch header.Outcome {
+	case OutcomeFailure, OutcomeError, OutcomeDenied, OutcomeRateLimited:
+		severity = syslogSeverityWarning
+	}
+	timestamp := header.LoggedAt
+	if timestamp.IsZero() {
+		timestamp = now
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "<%d>1 %s %s %s %s %s - ",
+		s.facility*8+severity,
+		timestamp.UTC().Format("2006-01-02T15:04:05.000000Z07:00"),
+		syslogField(s.hostname, 255),
+		syslogField(s.appName, 48),
+		syslogField(s.procID, 128),
+		syslogField(header.Type, 32),
+	)
+	buf.Write(event)
+	return buf.Bytes()
+}
+
+// syslogField returns a header field with only printable US-ASCII characters and a maximum length,
+// or the nil value "-" if it is empty
+func syslogField(value string, maxLength int) string {
+	field := make([]byte, 0, len(value))
+	for i := 0; i < len(value) && len(field) < maxLength; i++ {
+		if value[i] >= 33 && value[i] <= 126 {
+			field = append(field, value[i])
+		}
+	}
+	if len(field) == 0 {
+		return "-"
+	}
+	return string(field)
+}
@@ -0,0 +1,104 @@
+package audit
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyslogSink_Format(t *testing.T) {
+	t.Parallel()
+
+	sink := &syslogSink{facility: syslogFacilities["local0"], appName: "toolhive", hostname: "host", procID: "42"}
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		event string
+		want  string
+	}{
+		{
+			name:  "success",
+			event: `{"type":"mcp_tool_call","logged_at":"2025-06-07T08:09:10.123456Z","outcome":"success"}`,
+			want:  "<133>1 2025-06-07T08:09:10.123456Z host toolhive 42 mcp_tool_call - ",
+		},
+		{
+			name:  "denied",
+			event: `{"type":"mcp_tool_call","outcome":"denied"}`,
+			want:  "<132>1 2025-01-02T03:04:05.000000Z host toolhive 42 mcp_tool_call - ",
+		},
+		{
+			name:  "not an audit event",
+			event: `not json`,
+			want:  "<133>1 2025-01-02T03:04:05.000000Z host toolhive 42 - - ",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want+tt.event, string(sink.format([]byte(tt.event), now)))
+		})
+	}
+}
+
+func TestSyslogSink_TCP(t *testing.T) {
+	t.Parallel()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	messages := make(chan string, 2)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		reader := bufio.NewReader(conn)
+		for {
+			// Octet counting framing: MSG-LEN SP SYSLOG-MSG
+			length, err := reader.ReadString(' ')
+			if err != nil {
+				return
+			}
+			size, err := strconv.Atoi(strings.TrimSpace(length))
+			if err != nil {
+				return
+			}
+			message := make([]byte, size)
+			if _, err := io.ReadFull(reader, message); err != nil {
+				return
+			}
+			messages <- string(message)
+		}
+	}()
+
+	sink, err := NewSyslogSink(&SyslogSinkConfig{Address: listener.Addr().String(), AppName: "thv-test"})
+	require.NoError(t, err)
+
+	events := []string{
+		`{"audit_id":"1","type":"mcp_initialize","outcome":"success"}`,
+		`{"audit_id":"2","type":"mcp_tool_call","outcome":"failure"}`,
+	}
+	for _, event := range events {
+		_, err := sink.Write([]byte(event + "\n"))
+		require.NoError(t, err)
+	}
+	require.NoError(t, sink.Close())
+
+	for i, wantPrefix := range []string{"<37>1 ", "<36>1 "} {
+		message := <-messages
+		assert.True(t, strings.HasPrefix(message, wantPrefix), "unexpected priority in %q", message)
+		assert.Contains(t, message, " thv-test ")
+		assert.True(t, strings.HasSuffix(message, " - "+events[i]), "unexpected message %q", message)
+	}
+}
@@ -0,0 +1,175 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingSink records the events written to it
+type recordingSink struct {
+	mu     sync.Mutex
+	events []string
+	err    error
+	closed bool
+}
+
+func (r *recordingSink) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return 0, r.err
+	}
+	r.events = append(r.events, string(p))
+	return len(p), nil
+}
+
+func (r *recordingSink) Close() error {
+	r.closed = true
+	return nil
+}
+
+func TestSinkConfig_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		config  SinkConfig
+		wantErr string
+	}{
+		{
+			name:   "syslog",
+			config: SinkConfig{Type: SinkTypeSyslog, Syslog: &SyslogSinkConfig{Address: "syslog.example.com:6514", Network: "tls"}},
+		},
+		{
+			name:   "webhook",
+			config: SinkConfig{Type: SinkTypeWebhook, Webhook: &WebhookSinkConfig{URL: "https://collector.example.com/events"}},
+		},
+		{
+			name:   "otlp",
+			config: SinkConfig{Type: SinkTypeOTLP, OTLP: &OTLPSinkConfig{Endpoint: "http://otel-collector:4318"}},
+		},
+		{
+			name:    "unknown type",
+			config:  SinkConfig{Type: "kafka"},
+			wantErr: "unknown sink type",
+		},
+		{
+			name:    "missing configuration",
+			config:  SinkConfig{Type: SinkTypeWebhook},
+			wantErr: "webhook sink requires the webhook configuration",
+		},
+		{
+			name: "configuration of another type",
+			config: SinkConfig{
+				Type:   SinkTypeSyslog,
+				Syslog: &SyslogSinkConfig{Address: "localhost:514"},
+				OTLP:   &OTLPSinkConfig{Endpoint: "http://localhost:4318"},
+			},
+			wantErr: "must only set the syslog configuration",
+		},
+		{
+			name:    "syslog address without port",
+			config:  SinkConfig{Type: SinkTypeSyslog, Syslog: &SyslogSinkConfig{Address: "localhost"}},
+			wantErr: "invalid syslog address",
+		},
+		{
+			name:    "unknown syslog facility",
+			config:  SinkConfig{Type: SinkTypeSyslog, Syslog: &SyslogSinkConfig{Address: "localhost:514", Facility: "printer"}},
+			wantErr: "unknown syslog facility",
+		},
+		{
+			name:    "webhook url without scheme",
+			config:  SinkConfig{Type: SinkTypeWebhook, Webhook: &WebhookSinkConfig{URL: "collector.example.com"}},
+			wantErr: "invalid webhook url",
+		},
+		{
+			name: "invalid flush interval",
+			config: SinkConfig{Type: SinkTypeOTLP, OTLP: &OTLPSinkConfig{
+				Endpoint: "http://localhost:4318",
+				Batch:    &BatchConfig{FlushInterval: "soon"},
+			}},
+			wantErr: "invalid flushInterval",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := tt.config.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfig_ValidateSinks(t *testing.T) {
+	t.Parallel()
+
+	config := &Config{Sinks: []SinkConfig{{Type: SinkTypeWebhook}}}
+	assert.ErrorContains(t, config.Validate(), "invalid sink 0")
+}
+
+func TestFanOutWriter(t *testing.T) {
+	t.Parallel()
+
+	var primary bytes.Buffer
+	failing := &recordingSink{err: errors.New("connection refused")}
+	working := &recordingSink{}
+	writer := &fanOutWriter{primary: &primary, sinks: []Sink{failing, working}}
+
+	logger := NewAuditLogger(writer)
+	event := NewAuditEvent(EventTypeMCPToolCall, EventSource{Type: SourceTypeNetwork, Value: "127.0.0.1"},
+		OutcomeSuccess, map[string]string{SubjectKeyUser: "alice"}, "fetch")
+	event.LogTo(context.Background(), logger, LevelAudit)
+
+	// A failing sink does not prevent the others from receiving the event
+	require.Len(t, working.events, 1)
+	assert.Equal(t, primary.String(), working.events[0])
+	header := parseEventHeader([]byte(working.events[0]))
+	assert.Equal(t, EventTypeMCPToolCall, header.Type)
+	assert.Equal(t, OutcomeSuccess, header.Outcome)
+	assert.Equal(t, "fetch", header.Component)
+	assert.Equal(t, event.Metadata.AuditID, header.AuditID)
+
+	require.NoError(t, writer.Close())
+	assert.True(t, failing.closed)
+	assert.True(t, working.closed)
+}
+
+func TestConfig_GetLogWriterWithSinks(t *testing.T) {
+	t.Parallel()
+
+	logFile := filepath.Join(t.TempDir(), "audit.log")
+	config := &Config{
+		LogFile: logFile,
+		Sinks: []SinkConfig{{
+			Type:    SinkTypeWebhook,
+			Webhook: &WebhookSinkConfig{URL: "http://127.0.0.1:1/events"},
+		}},
+	}
+
+	writer, err := config.GetLogWriter()
+	require.NoError(t, err)
+	fanOut, ok := writer.(*fanOutWriter)
+	require.True(t, ok, "a writer with sinks must fan out")
+	require.Len(t, fanOut.sinks, 1)
+
+	_, err = writer.Write([]byte(`{"type":"mcp_tool_call"}` + "\n"))
+	require.NoError(t, err)
+	require.NoError(t, fanOut.Close())
+
+	data, err := os.ReadFile(logFile)
+	require.NoError(t, err)
+	assert.Equal(t, `{"type":"mcp_tool_call"}`+"\n", string(data))
+}
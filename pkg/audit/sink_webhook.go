@@ -0,0 +1,125 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+)
+
+// WebhookSinkConfig configures a sink posting batches of audit events to an HTTP collector.
+// Each request has a JSON array of events as body.
+// +kubebuilder:object:generate=true
+// +gendoc
+type WebhookSinkConfig struct {
+	// URL is the URL the batches of events are posted to.
+	// +kubebuilder:validation:Required
+	URL string `json:"url" yaml:"url"`
+	// Headers are additional HTTP headers of the requests.
+	// +optional
+	Headers map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+	// BearerTokenEnv is the name of an environment variable holding a bearer token
+	// sent in the Authorization header.
+	// +optional
+	BearerTokenEnv string `json:"bearerTokenEnv,omitempty" yaml:"bearerTokenEnv,omitempty"`
+	// Batch configures the batching, retries and disk buffering of the events.
+	// +optional
+	Batch *BatchConfig `json:"batch,omitempty" yaml:"batch,omitempty"`
+}
+
+// Validate validates the webhook sink configuration.
+func (c *WebhookSinkConfig) Validate() error {
+	if err := validateCollectorURL(c.URL); err != nil {
+		return fmt.Errorf("invalid webhook url: %w", err)
+	}
+	return c.Batch.Validate()
+}
+
+// NewWebhookSink creates a sink posting batches of audit events to an HTTP collector.
+func NewWebhookSink(config *WebhookSinkConfig) (Sink, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	headers := collectorHeaders(config.Headers, config.BearerTokenEnv)
+	headers.Set("Content-Type", "application/json")
+	client := &http.Client{}
+
+	send := func(ctx context.Context, events [][]byte) error {
+		body := append([]byte{'['}, bytes.Join(events, []byte{','})...)
+		body = append(body, ']')
+		return postToCollector(ctx, client, config.URL, headers, body)
+	}
+	return newBatchingSink(sinkName(SinkTypeWebhook, config.URL), config.Batch, send)
+}
+
+// validateCollectorURL checks that a collector URL is an absolute HTTP or HTTPS URL
+func validateCollectorURL(rawURL string) error {
+	if rawURL == "" {
+		return fmt.Errorf("url is required")
+	}
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return fmt.Errorf("scheme must be http or https")
+	}
+	if parsed.Host == "" {
+		return fmt.Errorf("host is required")
+	}
+	return nil
+}
+
+// collectorHeaders builds the headers of the requests to a collector
+func collectorHeaders(configured map[string]string, bearerTokenEnv string) http.Header {
+	headers := make(http.Header, len(configured)+2)
+	for name, value := range configured {
+		headers.Set(name, value)
+	}
+	if bearerTokenEnv != "" {
+		if token := os.Getenv(bearerTokenEnv); token != "" {
+			headers.Set("Authorization", "Bearer "+token)
+		}
+	}
+	return headers
+}
+
+// postToCollector posts a body to a collector. Client errors other than timeouts and
+// rate limiting are permanent, as retrying the same body would fail again.
+func postToCollector(ctx context.Context, client *http.Client, url string, headers http.Header, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return &permanentError{err: err}
+	}
+	req.Header = headers.Clone()
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	// Drain the body so that the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	err = fmt.Errorf("collector responded with status %d", resp.StatusCode)
+	if resp.StatusCode >= 400 && resp.StatusCode < 500 &&
+		resp.StatusCode != http.StatusRequestTimeout && resp.StatusCode != http.StatusTooManyRequests {
+		return &permanentError{err: err}
+	}
+	return err
+}
+
+// sinkName returns a stable name identifying a sink, used in logs and for its disk buffer
+func sinkName(sinkType, destination string) string {
+	sum := sha256.Sum256([]byte(destination))
+	return sinkType + "-" + hex.EncodeToString(sum[:6])
+}
@@ -0,0 +1,214 @@
+package audit
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+)
+
+// webhookCollector records the batches of events posted to it
+type webhookCollector struct {
+	mu      sync.Mutex
+	batches [][]map[string]any
+	headers []http.Header
+}
+
+func (c *webhookCollector) handler(t *testing.T) http.HandlerFunc {
+	t.Helper()
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		var batch []map[string]any
+		require.NoError(t, json.Unmarshal(body, &batch))
+
+		c.mu.Lock()
+		c.batches = append(c.batches, batch)
+		c.headers = append(c.headers, r.Header.Clone())
+		c.mu.Unlock()
+		w.WriteHeader(http.StatusAccepted)
+	}
+}
+
+func (c *webhookCollector) events() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var ids []string
+	for _, batch := range c.batches {
+		for _, event := range batch {
+			ids = append(ids, event["audit_id"].(string))
+		}
+	}
+	return ids
+}
+
+func TestWebhookSink_Batches(t *testing.T) { //nolint:paralleltest // uses t.Setenv
+	collector := &webhookCollector{}
+	server := httptest.NewServer(collector.handler(t))
+	defer server.Close()
+
+	t.Setenv("TEST_WEBHOOK_TOKEN", "secret-token")
+	sink, err := NewWebhookSink(&WebhookSinkConfig{
+		URL:            server.URL,
+		Headers:        map[string]string{"X-Source": "toolhive"},
+		BearerTokenEnv: "TEST_WEBHOOK_TOKEN",
+		Batch:          &BatchConfig{MaxBatchSize: 2, FlushInterval: "1h"},
+	})
+	require.NoError(t, err)
+
+	for _, id := range []string{"1", "2", "3"} {
+		_, err := sink.Write([]byte(`{"audit_id":"` + id + `"}` + "\n"))
+		require.NoError(t, err)
+	}
+	// The third event is sent when the sink is closed
+	require.NoError(t, sink.Close())
+
+	assert.Equal(t, []string{"1", "2", "3"}, collector.events())
+	require.Len(t, collector.batches, 2)
+	assert.Len(t, collector.batches[0], 2)
+	assert.Equal(t, "application/json", collector.headers[0].Get("Content-Type"))
+	assert.Equal(t, "toolhive", collector.headers[0].Get("X-Source"))
+	assert.Equal(t, "Bearer secret-token", collector.headers[0].Get("Authorization"))
+}
+
+func TestWebhookSink_RetriesUnavailableCollector(t *testing.T) {
+	t.Parallel()
+
+	var attempts atomic.Int32
+	collector := &webhookCollector{}
+	handler := collector.handler(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		handler(w, r)
+	}))
+	defer server.Close()
+
+	sink, err := NewWebhookSink(&WebhookSinkConfig{
+		URL:   server.URL,
+		Batch: &BatchConfig{MaxBatchSize: 1, FlushInterval: "1h", MaxRetries: ptr.To(1)},
+	})
+	require.NoError(t, err)
+
+	_, err = sink.Write([]byte(`{"audit_id":"1"}`))
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { return len(collector.events()) == 1 }, defaultSinkTimeout, 10*time.Millisecond)
+	require.NoError(t, sink.Close())
+	assert.Equal(t, int32(2), attempts.Load())
+}
+
+func TestWebhookSink_ZeroRetries(t *testing.T) {
+	t.Parallel()
+
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	sink, err := NewWebhookSink(&WebhookSinkConfig{
+		URL:   server.URL,
+		Batch: &BatchConfig{MaxBatchSize: 1, FlushInterval: "1h", MaxRetries: ptr.To(0)},
+	})
+	require.NoError(t, err)
+
+	_, err = sink.Write([]byte(`{"audit_id":"1"}`))
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { return attempts.Load() == 1 }, defaultSinkTimeout, 10*time.Millisecond)
+
+	// An explicit 0 disables retries instead of selecting the default
+	assert.Never(t, func() bool { return attempts.Load() > 1 }, 2*initialRetryBackoff, 50*time.Millisecond)
+	require.NoError(t, sink.Close())
+}
+
+func TestWebhookSink_DropsRejectedEvents(t *testing.T) {
+	t.Parallel()
+
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	bufferDir := t.TempDir()
+	sink, err := NewWebhookSink(&WebhookSinkConfig{
+		URL:   server.URL,
+		Batch: &BatchConfig{FlushInterval: "1h", BufferDir: bufferDir},
+	})
+	require.NoError(t, err)
+
+	_, err = sink.Write([]byte(`{"audit_id":"1"}`))
+	require.NoError(t, err)
+	require.NoError(t, sink.Close())
+
+	// Rejected events are neither retried nor buffered
+	assert.Equal(t, int32(1), attempts.Load())
+	entries, err := os.ReadDir(bufferDir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestWebhookSink_DiskBuffer(t *testing.T) {
+	t.Parallel()
+
+	bufferDir := t.TempDir()
+	var available atomic.Bool
+	collector := &webhookCollector{}
+	handler := collector.handler(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !available.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		handler(w, r)
+	}))
+	defer server.Close()
+
+	config := &WebhookSinkConfig{
+		URL:   server.URL,
+		Batch: &BatchConfig{FlushInterval: "1h", BufferDir: bufferDir},
+	}
+
+	// The events that cannot be delivered when the sink is closed are kept on disk
+	sink, err := NewWebhookSink(config)
+	require.NoError(t, err)
+	for _, id := range []string{"1", "2"} {
+		_, err := sink.Write([]byte(`{"audit_id":"` + id + `"}`))
+		require.NoError(t, err)
+	}
+	require.NoError(t, sink.Close())
+	assert.Empty(t, collector.events())
+
+	buffered, err := filepath.Glob(filepath.Join(bufferDir, "*.ndjson"))
+	require.NoError(t, err)
+	require.Len(t, buffered, 1)
+	data, err := os.ReadFile(buffered[0])
+	require.NoError(t, err)
+	assert.Equal(t, "{\"audit_id\":\"1\"}\n{\"audit_id\":\"2\"}\n", string(data))
+
+	// The buffered events are delivered before newer events once the collector is available
+	available.Store(true)
+	sink, err = NewWebhookSink(config)
+	require.NoError(t, err)
+	_, err = sink.Write([]byte(`{"audit_id":"3"}`))
+	require.NoError(t, err)
+	require.NoError(t, sink.Close())
+
+	assert.Equal(t, []string{"1", "2", "3"}, collector.events())
+	_, err = os.Stat(buffered[0])
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -0,0 +1,179 @@
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// VerifyProblem is an integrity violation found in a hash-chained audit log.
+type VerifyProblem struct {
+	// Line is the line number of the problem, starting at 1, or 0 for the whole log
+	Line int `json:"line"`
+	// Message describes the problem
+	Message string `json:"message"`
+}
+
+// VerifyResult is the result of the verification of a hash-chained audit log.
+type VerifyResult struct {
+	// Events is the number of chained audit events
+	Events int `json:"events"`
+	// Checkpoints is the number of checkpoints
+	Checkpoints int `json:"checkpoints"`
+	// Chains is the number of hash chains. A new chain starts when the log is written to stdout
+	// by a new process, as the chain cannot be resumed. It must start with a chain start record
+	// referencing the last event of the previous chain.
+	Chains int `json:"chains"`
+	// SignaturesVerified is true if the signatures of the checkpoints were verified with a public key
+	SignaturesVerified bool `json:"signatures_verified"`
+	// UnchainedLines is the number of lines before the first chained event,
+	// written before the integrity mode was enabled
+	UnchainedLines int `json:"unchained_lines"`
+	// UnsignedEvents is the number of events after the last checkpoint of their chain.
+	// They are expected when the writer did not shut down cleanly, and may have been truncated.
+	UnsignedEvents int `json:"unsigned_events"`
+	// Problems are the integrity violations found in the log
+	Problems []VerifyProblem `json:"problems,omitempty"`
+}
+
+// Valid returns true if no integrity violation was found.
+func (r *VerifyResult) Valid() bool {
+	return len(r.Problems) == 0
+}
+
+func (r *VerifyResult) addProblem(line int, format string, args ...any) {
+	r.Problems = append(r.Problems, VerifyProblem{Line: line, Message: fmt.Sprintf(format, args...)})
+}
+
+// VerifyLog verifies the hash chain of an audit log, detecting missing, reordered and modified events.
+// If publicKey is not nil, the signatures of the checkpoints are verified too.
+func VerifyLog(r io.Reader, publicKey ed25519.PublicKey) (*VerifyResult, error) {
+	result := &VerifyResult{SignaturesVerified: publicKey != nil}
+	var expectedKeyID string
+	if publicKey != nil {
+		expectedKeyID = KeyID(publicKey)
+	}
+
+	reader := bufio.NewReader(r)
+	var (
+		lineNumber int
+		started    bool
+		prevSeq    uint64
+		prevHash   string
+		// chainHash is the hash of the last chained line, referenced by the next chain start record
+		chainHash string
+		unsigned  int
+	)
+	for {
+		line, readErr := reader.ReadBytes('\n')
+		if readErr != nil && !errors.Is(readErr, io.EOF) {
+			return nil, fmt.Errorf("failed to read audit log: %w", readErr)
+		}
+		if len(line) > 0 {
+			lineNumber++
+		}
+		line = bytes.TrimRight(line, "\r\n")
+
+		if len(line) > 0 {
+			var link chainLink
+			switch {
+			case json.Unmarshal(line, &link) != nil:
+				result.addProblem(lineNumber, "line is not a JSON object")
+			case link.Seq == nil || link.PrevHash == nil:
+				if !started {
+					result.UnchainedLines++
+					break
+				}
+				result.addProblem(lineNumber, "event is not part of the hash chain")
+			default:
+				seq, linkedHash := *link.Seq, *link.PrevHash
+				switch {
+				case seq == 1 && linkedHash == "":
+					// A new chain starts, linked to the previous one by its chain start record
+					result.Chains++
+					result.UnsignedEvents += unsigned
+					unsigned = 0
+					if started && (link.Type != EventTypeAuditChainStart ||
+						link.PrevChainSeq != prevSeq || link.PrevChainHash != chainHash) {
+						result.addProblem(lineNumber, "hash chain restarts without referencing the previous chain: "+
+							"events may have been removed")
+					}
+				case !started:
+					result.Chains++
+					result.addProblem(lineNumber, "chain starts at sequence %d: earlier events are missing", seq)
+				case seq != prevSeq+1:
+					result.addProblem(lineNumber, "expected sequence %d, found %d: events are missing or reordered",
+						prevSeq+1, seq)
+				case linkedHash != prevHash:
+					result.addProblem(lineNumber, "hash of the previous event does not match: "+
+						"an event was modified, inserted or reordered")
+				}
+				started = true
+
+				switch link.Type {
+				case EventTypeAuditCheckpoint:
+					result.Checkpoints++
+					unsigned = 0
+					if publicKey != nil {
+						verifyCheckpoint(result, lineNumber, &link, publicKey, expectedKeyID)
+					}
+				case EventTypeAuditChainStart:
+					if seq != 1 {
+						result.addProblem(lineNumber, "chain start record in the middle of a hash chain")
+					}
+					if publicKey != nil {
+						verifyChainStart(result, lineNumber, &link, publicKey, expectedKeyID)
+					}
+				default:
+					result.Events++
+					unsigned++
+				}
+				prevSeq = seq
+				chainHash = lineHash(line)
+			}
+			prevHash = lineHash(line)
+		}
+
+		if errors.Is(readErr, io.EOF) {
+			break
+		}
+	}
+	result.UnsignedEvents += unsigned
+
+	if !started {
+		result.addProblem(0, "no hash-chained audit events found")
+	} else if publicKey != nil && result.Checkpoints == 0 {
+		result.addProblem(0, "no signed checkpoint found")
+	}
+	return result, nil
+}
+
+// verifyCheckpoint verifies the signature of a checkpoint over the sequence number and hash of the previous event
+func verifyCheckpoint(result *VerifyResult, lineNumber int, link *chainLink, publicKey ed25519.PublicKey, keyID string) {
+	if link.KeyID != keyID {
+		result.addProblem(lineNumber, "checkpoint is signed with key %s, expected %s", link.KeyID, keyID)
+		return
+	}
+	signature, err := base64.StdEncoding.DecodeString(link.Signature)
+	if err != nil || *link.Seq == 0 ||
+		!ed25519.Verify(publicKey, checkpointPayload(*link.Seq-1, *link.PrevHash), signature) {
+		result.addProblem(lineNumber, "invalid checkpoint signature")
+	}
+}
+
+// verifyChainStart verifies the signature of a chain start record over the position of the previous chain
+func verifyChainStart(result *VerifyResult, lineNumber int, link *chainLink, publicKey ed25519.PublicKey, keyID string) {
+	if link.KeyID != keyID {
+		result.addProblem(lineNumber, "chain start record is signed with key %s, expected %s", link.KeyID, keyID)
+		return
+	}
+	signature, err := base64.StdEncoding.DecodeString(link.Signature)
+	if err != nil || !ed25519.Verify(publicKey, chainStartPayload(link.PrevChainSeq, link.PrevChainHash), signature) {
+		result.addProblem(lineNumber, "invalid chain start signature")
+	}
+}
@@ -0,0 +1,167 @@
+package audit
+
+import (
+	"crypto/ed25519"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyLog(t *testing.T) {
+	t.Parallel()
+
+	key := newTestSigningKey(t)
+	publicKey := key.Public().(ed25519.PublicKey)
+	logFile := filepath.Join(t.TempDir(), "audit.log")
+	// Lines 1-3 are events, 4 is a checkpoint, 5-6 are events and 7 is the final checkpoint
+	writeChainedEvents(t, logFile, &IntegrityConfig{SigningKeySecret: "audit-key", CheckpointEvents: 3}, key, 5)
+	lines := readLines(t, logFile)
+	require.Len(t, lines, 7)
+
+	tests := []struct {
+		name         string
+		tamper       func(lines []string) []string
+		publicKey    ed25519.PublicKey
+		wantProblems []string
+		wantUnsigned int
+		wantUnchain  int
+	}{
+		{
+			name:      "untouched",
+			tamper:    func(lines []string) []string { return lines },
+			publicKey: publicKey,
+		},
+		{
+			name: "modified event",
+			tamper: func(lines []string) []string {
+				lines[1] = strings.Replace(lines[1], `"outcome":"success"`, `"outcome":"denied"`, 1)
+				return lines
+			},
+			publicKey:    publicKey,
+			wantProblems: []string{"line 3: hash of the previous event does not match"},
+		},
+		{
+			name: "deleted event",
+			tamper: func(lines []string) []string {
+				return append(lines[:1:1], lines[2:]...)
+			},
+			publicKey:    publicKey,
+			wantProblems: []string{"line 2: expected sequence 2, found 3"},
+		},
+		{
+			name: "reordered events",
+			tamper: func(lines []string) []string {
+				lines[0], lines[1] = lines[1], lines[0]
+				return lines
+			},
+			publicKey: publicKey,
+			wantProblems: []string{
+				"line 1: chain starts at sequence 2",
+				"line 2: hash chain restarts without referencing the previous chain",
+				"line 3: expected sequence 2, found 3",
+			},
+			wantUnsigned: 1,
+		},
+		{
+			name: "chain restarted after removing events",
+			tamper: func(lines []string) []string {
+				return append(lines[:5:5], lines...)
+			},
+			publicKey:    publicKey,
+			wantProblems: []string{"line 6: hash chain restarts without referencing the previous chain"},
+			wantUnsigned: 1,
+		},
+		{
+			name: "deleted first events",
+			tamper: func(lines []string) []string {
+				return lines[2:]
+			},
+			publicKey:    publicKey,
+			wantProblems: []string{"line 1: chain starts at sequence 3"},
+		},
+		{
+			name: "truncated after a checkpoint",
+			tamper: func(lines []string) []string {
+				return lines[:5]
+			},
+			publicKey:    publicKey,
+			wantUnsigned: 1,
+		},
+		{
+			name: "checkpoint signed with another key",
+			tamper: func(lines []string) []string {
+				return lines
+			},
+			publicKey:    newTestSigningKey(t).Public().(ed25519.PublicKey),
+			wantProblems: []string{"line 4: checkpoint is signed with key", "line 7: checkpoint is signed with key"},
+		},
+		{
+			name: "checkpoints removed",
+			tamper: func(lines []string) []string {
+				return lines[:3]
+			},
+			publicKey:    publicKey,
+			wantProblems: []string{"line 0: no signed checkpoint found"},
+			wantUnsigned: 3,
+		},
+		{
+			name: "signatures not verified",
+			tamper: func(lines []string) []string {
+				return lines[:3]
+			},
+			wantUnsigned: 3,
+		},
+		{
+			name: "lines written before the integrity mode",
+			tamper: func(lines []string) []string {
+				return append([]string{`{"type":"mcp_tool_call"}`}, lines...)
+			},
+			publicKey:   publicKey,
+			wantUnchain: 1,
+		},
+		{
+			name: "unchained line inserted",
+			tamper: func(lines []string) []string {
+				return append(lines[:2:2], append([]string{`{"type":"mcp_tool_call"}`}, lines[2:]...)...)
+			},
+			publicKey: publicKey,
+			wantProblems: []string{
+				"line 3: event is not part of the hash chain",
+				"line 4: hash of the previous event does not match",
+			},
+		},
+		{
+			name:         "not a hash-chained log",
+			tamper:       func([]string) []string { return []string{`{"type":"mcp_tool_call"}`, "not json"} },
+			wantProblems: []string{"line 2: line is not a JSON object", "line 0: no hash-chained audit events found"},
+			wantUnchain:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tampered := tt.tamper(append([]string(nil), lines...))
+			result, err := VerifyLog(strings.NewReader(strings.Join(tampered, "\n")+"\n"), tt.publicKey)
+			require.NoError(t, err)
+
+			var problems []string
+			for _, problem := range result.Problems {
+				problems = append(problems, "line "+strconv.Itoa(problem.Line)+": "+problem.Message)
+			}
+			require.Len(t, problems, len(tt.wantProblems), "problems: %v", problems)
+			for i, want := range tt.wantProblems {
+				assert.True(t, strings.HasPrefix(problems[i], want), "expected %q, found %q", want, problems[i])
+			}
+			assert.Equal(t, len(tt.wantProblems) == 0, result.Valid())
+			assert.Equal(t, tt.wantUnsigned, result.UnsignedEvents)
+			assert.Equal(t, tt.wantUnchain, result.UnchainedLines)
+			assert.Equal(t, tt.publicKey != nil, result.SignaturesVerified)
+		})
+	}
+}
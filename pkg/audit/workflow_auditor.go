@@ -5,7 +5,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log/slog"
+	"os"
 	"time"
 
 	"github.com/stacklok/toolhive/pkg/auth"
@@ -18,6 +20,7 @@ type WorkflowAuditor struct {
 	auditLogger *slog.Logger
 	config      *Config
 	component   string
+	logWriter   io.Writer
 }
 
 // NewWorkflowAuditor creates a new workflow auditor.
@@ -42,9 +45,21 @@ func NewWorkflowAuditor(config *Config) (*WorkflowAuditor, error) {
 		auditLogger: NewAuditLogger(logWriter),
 		config:      config,
 		component:   component,
+		logWriter:   logWriter,
 	}, nil
 }
 
+// Close closes the audit log file and flushes the audit sinks.
+func (w *WorkflowAuditor) Close() error {
+	if w.logWriter == os.Stdout {
+		return nil
+	}
+	if closer, ok := w.logWriter.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
 // LogWorkflowStarted logs the start of workflow execution.
 func (w *WorkflowAuditor) LogWorkflowStarted(
 	ctx context.Context,
@@ -663,7 +663,9 @@ func (v *TokenValidator) getKeyFromJWKS(ctx context.Context, token *jwt.Token) (
 	}
 
 	// Validate the signing method
-	if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
+	switch token.Method.(type) {
+	case *jwt.SigningMethodRSA, *jwt.SigningMethodECDSA:
+	default:
 		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 	}
 
@@ -2,6 +2,8 @@ package auth
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/rsa"
 	"encoding/json"
@@ -174,6 +176,62 @@ func TestTokenValidator(t *testing.T) {
 }
 
 //nolint:gocyclo // This test function is complex but manageable
+func TestTokenValidator_ECDSA(t *testing.T) {
+	t.Parallel()
+	// Generate a new EC key pair for signing tokens
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate EC key pair: %v", err)
+	}
+
+	key, err := jwk.Import(&privateKey.PublicKey)
+	if err != nil {
+		t.Fatalf("Failed to create JWK from public key: %v", err)
+	}
+	if err := key.Set(jwk.KeyIDKey, testKeyID); err != nil {
+		t.Fatalf("Failed to set key ID: %v", err)
+	}
+	if err := key.Set(jwk.AlgorithmKey, "ES256"); err != nil {
+		t.Fatalf("Failed to set algorithm: %v", err)
+	}
+	keySet := jwk.NewSet()
+	if err := keySet.AddKey(key); err != nil {
+		t.Fatalf("Failed to add key to set: %v", err)
+	}
+
+	jwksServer, caCertPath := createTestJWKSServer(t, keySet)
+	t.Cleanup(func() {
+		jwksServer.Close()
+	})
+
+	validator, err := NewTokenValidator(context.Background(), TokenValidatorConfig{
+		Issuer:         "test-issuer",
+		Audience:       "test-audience",
+		JWKSURL:        jwksServer.URL,
+		CACertPath:     caCertPath,
+		AllowPrivateIP: true,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create token validator: %v", err)
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"iss": "test-issuer",
+		"aud": "test-audience",
+		"sub": "user",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	})
+	token.Header["kid"] = testKeyID
+	tokenString, err := token.SignedString(privateKey)
+	if err != nil {
+		t.Fatalf("Failed to sign token: %v", err)
+	}
+
+	if _, err := validator.ValidateToken(context.Background(), tokenString); err != nil {
+		t.Errorf("Expected no error but got %v", err)
+	}
+}
+
 func TestTokenValidatorMiddleware(t *testing.T) {
 	t.Parallel()
 	// Generate a new RSA key pair for signing tokens
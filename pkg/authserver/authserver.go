@@ -0,0 +1,97 @@
+// Copyright 2025 Stacklok, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package authserver provides an OAuth 2.1 authorization server that can be
+// embedded in the ToolHive proxy.
+//
+// MCP clients register dynamically, authorize with PKCE and receive JWT access
+// tokens issued by the embedded server. User authentication is federated to an
+// upstream OIDC provider whose tokens are kept in storage, linked to the issued
+// tokens by the "tsid" claim. The proxy validates the issued tokens with the
+// regular pkg/auth middleware using the keys published at /.well-known/jwks.json.
+package authserver
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/stacklok/toolhive/pkg/authserver/server"
+	"github.com/stacklok/toolhive/pkg/authserver/storage"
+)
+
+// Server is an embedded OAuth authorization server.
+type Server struct {
+	server  *server.Server
+	storage storage.Storage
+}
+
+// New creates an authorization server from its RunConfig. It loads the configured
+// keys, creates the storage backend and discovers the upstream provider.
+func New(ctx context.Context, cfg *RunConfig) (*Server, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("authorization server config is required")
+	}
+
+	serverConfig, err := cfg.toInternalConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	storageConfig := storage.DefaultConfig()
+	if cfg.Storage != nil {
+		if storageConfig, err = cfg.Storage.ToConfig(); err != nil {
+			return nil, err
+		}
+	}
+	store, err := storage.New(ctx, storageConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	srv, err := server.New(*serverConfig, store)
+	if err != nil {
+		_ = store.Close()
+		return nil, err
+	}
+
+	return &Server{server: srv, storage: store}, nil
+}
+
+// Handler returns the HTTP handler serving the authorization server endpoints.
+// The handler must be mounted at the root of the issuer URL; see server.Paths.
+func (s *Server) Handler() http.Handler {
+	return s.server.Handler()
+}
+
+// Paths returns the URL paths served by Handler.
+func (*Server) Paths() []string {
+	return server.Paths
+}
+
+// Issuer returns the issuer identifier of the server.
+func (s *Server) Issuer() string {
+	return s.server.Issuer()
+}
+
+// UpstreamTokenStorage returns the storage holding the upstream IDP tokens of
+// authenticated users, keyed by the "tsid" claim of issued access tokens.
+func (s *Server) UpstreamTokenStorage() storage.UpstreamTokenStorage {
+	return s.storage
+}
+
+// Close releases the resources held by the server.
+func (s *Server) Close() error {
+	return s.storage.Close()
+}
@@ -0,0 +1,303 @@
+// Copyright 2025 Stacklok, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authserver
+
+import (
+	"context"
+	gocrypto "crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/stacklok/toolhive/pkg/auth"
+	"github.com/stacklok/toolhive/pkg/authserver/server"
+	"github.com/stacklok/toolhive/pkg/authserver/server/crypto"
+	"github.com/stacklok/toolhive/pkg/authserver/storage"
+	"github.com/stacklok/toolhive/pkg/authserver/upstream"
+	"github.com/stacklok/toolhive/pkg/logger"
+)
+
+// UpstreamClientSecretEnvVar is the environment variable holding the upstream client secret
+// when no secret file is configured. This allows the secret to be injected from a
+// Kubernetes Secret without writing it to the RunConfig.
+//
+//nolint:gosec // G101: This is an environment variable name, not a credential
+const UpstreamClientSecretEnvVar = "TOOLHIVE_AUTHSERVER_UPSTREAM_CLIENT_SECRET"
+
+// RunConfig is the serializable configuration of the embedded authorization server.
+// It is stored in the workload RunConfig, so it references secrets by file or
+// environment variable rather than containing them.
+type RunConfig struct {
+	// Issuer is the issuer identifier and public base URL of the authorization server,
+	// typically the proxy URL (e.g., https://mcp.example.com).
+	Issuer string `json:"issuer" yaml:"issuer"`
+
+	// SigningKeyFile is the path to a PEM encoded RSA or EC private key used to sign
+	// access tokens. If empty, an ephemeral RSA key is generated at startup and tokens
+	// do not survive restarts.
+	SigningKeyFile string `json:"signing_key_file,omitempty" yaml:"signing_key_file,omitempty"`
+
+	// SigningKeyID overrides the key ID published in the JWKS.
+	// Defaults to the RFC 7638 thumbprint of the key.
+	SigningKeyID string `json:"signing_key_id,omitempty" yaml:"signing_key_id,omitempty"`
+
+	// SigningAlgorithm overrides the signing algorithm. Defaults to the algorithm of the key type.
+	SigningAlgorithm string `json:"signing_algorithm,omitempty" yaml:"signing_algorithm,omitempty"`
+
+	// HMACSecretFiles are paths to files containing the secrets used to sign authorization
+	// codes and refresh tokens. The first is the current secret, the rest are rotated secrets
+	// still accepted for verification. If empty, a random secret is generated at startup.
+	HMACSecretFiles []string `json:"hmac_secret_files,omitempty" yaml:"hmac_secret_files,omitempty"`
+
+	// AccessTokenLifespan is the lifespan of access tokens (e.g., "1h").
+	AccessTokenLifespan string `json:"access_token_lifespan,omitempty" yaml:"access_token_lifespan,omitempty"`
+
+	// RefreshTokenLifespan is the lifespan of refresh tokens (e.g., "720h").
+	RefreshTokenLifespan string `json:"refresh_token_lifespan,omitempty" yaml:"refresh_token_lifespan,omitempty"`
+
+	// AuthCodeLifespan is the lifespan of authorization codes (e.g., "10m").
+	AuthCodeLifespan string `json:"auth_code_lifespan,omitempty" yaml:"auth_code_lifespan,omitempty"`
+
+	// AllowedAudiences are the resource servers tokens can be issued for,
+	// typically the MCP server URL. The first audience is validated by the proxy.
+	AllowedAudiences []string `json:"allowed_audiences" yaml:"allowed_audiences"`
+
+	// ScopesSupported are the scopes clients may request. Defaults to openid, profile and email.
+	ScopesSupported []string `json:"scopes_supported,omitempty" yaml:"scopes_supported,omitempty"`
+
+	// Upstream configures the identity provider users authenticate with.
+	Upstream *UpstreamRunConfig `json:"upstream" yaml:"upstream"`
+
+	// Storage configures where the authorization server keeps its state. Defaults to memory.
+	Storage *storage.RunConfig `json:"storage,omitempty" yaml:"storage,omitempty"`
+}
+
+// UpstreamRunConfig is the serializable configuration of the upstream OIDC provider.
+type UpstreamRunConfig struct {
+	// Issuer is the issuer URL of the upstream OIDC provider.
+	Issuer string `json:"issuer" yaml:"issuer"`
+
+	// ClientID is the client ID registered with the upstream provider.
+	ClientID string `json:"client_id" yaml:"client_id"`
+
+	// ClientSecretFile is the path to a file containing the upstream client secret.
+	// If empty, the secret is read from the TOOLHIVE_AUTHSERVER_UPSTREAM_CLIENT_SECRET
+	// environment variable.
+	ClientSecretFile string `json:"client_secret_file,omitempty" yaml:"client_secret_file,omitempty"`
+
+	// Scopes are the scopes requested from the upstream provider.
+	// Defaults to openid, profile and email.
+	Scopes []string `json:"scopes,omitempty" yaml:"scopes,omitempty"`
+
+	// RedirectURI is the callback URL registered with the upstream provider.
+	// Defaults to the issuer followed by /oauth/callback.
+	RedirectURI string `json:"redirect_uri,omitempty" yaml:"redirect_uri,omitempty"`
+}
+
+// LoadRunConfig reads an authorization server configuration from a JSON or YAML file.
+func LoadRunConfig(path string) (*RunConfig, error) {
+	data, err := os.ReadFile(path) // #nosec G304 - path is provided by the user
+	if err != nil {
+		return nil, fmt.Errorf("failed to read authorization server config: %w", err)
+	}
+
+	var cfg RunConfig
+	if err := yaml.UnmarshalStrict(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse authorization server config: %w", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// Validate checks the configuration without reading files or contacting the upstream provider.
+func (c *RunConfig) Validate() error {
+	if err := server.ValidateIssuer(c.Issuer); err != nil {
+		return err
+	}
+	if len(c.AllowedAudiences) == 0 {
+		return fmt.Errorf("at least one allowed audience is required")
+	}
+	for _, lifespan := range []string{c.AccessTokenLifespan, c.RefreshTokenLifespan, c.AuthCodeLifespan} {
+		if _, err := parseLifespan(lifespan); err != nil {
+			return err
+		}
+	}
+	if c.Upstream == nil {
+		return fmt.Errorf("upstream identity provider configuration is required")
+	}
+	if c.Upstream.Issuer == "" {
+		return fmt.Errorf("upstream issuer is required")
+	}
+	if c.Upstream.ClientID == "" {
+		return fmt.Errorf("upstream client_id is required")
+	}
+	if c.Storage != nil {
+		return c.Storage.Validate()
+	}
+	return nil
+}
+
+// TokenValidatorConfig returns the configuration the proxy uses to validate
+// access tokens issued by this authorization server.
+func (c *RunConfig) TokenValidatorConfig() *auth.TokenValidatorConfig {
+	issuer := strings.TrimSuffix(c.Issuer, "/")
+	cfg := &auth.TokenValidatorConfig{
+		Issuer:  issuer,
+		JWKSURL: issuer + server.JWKSPath,
+		// The proxy fetches the keys from itself
+		AllowPrivateIP: true,
+		Scopes:         c.ScopesSupported,
+	}
+	if len(c.AllowedAudiences) > 0 {
+		cfg.Audience = c.AllowedAudiences[0]
+		cfg.ResourceURL = c.AllowedAudiences[0]
+	}
+	if parsed, err := url.Parse(issuer); err == nil && parsed.Scheme == "http" {
+		cfg.InsecureAllowHTTP = true
+	}
+	return cfg
+}
+
+// toInternalConfig resolves the configuration: it loads keys and secrets, generating
+// ephemeral ones when none are configured, and discovers the upstream provider.
+func (c *RunConfig) toInternalConfig(ctx context.Context) (*server.Config, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	signingKey, err := c.loadSigningKey()
+	if err != nil {
+		return nil, err
+	}
+
+	hmacSecrets, err := crypto.LoadHMACSecrets(c.HMACSecretFiles)
+	if err != nil {
+		return nil, err
+	}
+	if hmacSecrets == nil {
+		logger.Warnf("No HMAC secret configured for the authorization server, generating a random secret. " +
+			"Authorization codes and refresh tokens will not survive restarts.")
+		secret := make([]byte, crypto.MinSecretLength)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, fmt.Errorf("failed to generate HMAC secret: %w", err)
+		}
+		hmacSecrets = &crypto.HMACSecrets{Current: secret}
+	}
+
+	upstreamProvider, err := c.newUpstreamProvider(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Lifespans were validated above
+	accessTokenLifespan, _ := parseLifespan(c.AccessTokenLifespan)
+	refreshTokenLifespan, _ := parseLifespan(c.RefreshTokenLifespan)
+	authCodeLifespan, _ := parseLifespan(c.AuthCodeLifespan)
+
+	return &server.Config{
+		Issuer:               c.Issuer,
+		SigningKey:           signingKey,
+		HMACSecrets:          hmacSecrets,
+		AccessTokenLifespan:  accessTokenLifespan,
+		RefreshTokenLifespan: refreshTokenLifespan,
+		AuthCodeLifespan:     authCodeLifespan,
+		AllowedAudiences:     c.AllowedAudiences,
+		ScopesSupported:      c.ScopesSupported,
+		Upstream:             upstreamProvider,
+	}, nil
+}
+
+func (c *RunConfig) loadSigningKey() (*crypto.SigningKeyParams, error) {
+	var key gocrypto.Signer
+	if c.SigningKeyFile != "" {
+		loaded, err := crypto.LoadSigningKey(c.SigningKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		key = loaded
+	} else {
+		logger.Warnf("No signing key configured for the authorization server, generating an ephemeral RSA key. " +
+			"Issued access tokens will not survive restarts.")
+		generated, err := rsa.GenerateKey(rand.Reader, crypto.MinRSAKeyBits)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate signing key: %w", err)
+		}
+		key = generated
+	}
+
+	return crypto.DeriveSigningKeyParams(key, c.SigningKeyID, c.SigningAlgorithm)
+}
+
+func (c *RunConfig) newUpstreamProvider(ctx context.Context) (*upstream.OIDCProvider, error) {
+	clientSecret, err := c.Upstream.clientSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	redirectURI := c.Upstream.RedirectURI
+	if redirectURI == "" {
+		redirectURI = strings.TrimSuffix(c.Issuer, "/") + server.CallbackEndpointPath
+	}
+
+	provider, err := upstream.NewOIDCProvider(ctx, &upstream.Config{
+		Issuer:       c.Upstream.Issuer,
+		ClientID:     c.Upstream.ClientID,
+		ClientSecret: clientSecret,
+		Scopes:       c.Upstream.Scopes,
+		RedirectURI:  redirectURI,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create upstream provider: %w", err)
+	}
+	return provider, nil
+}
+
+// clientSecret reads the upstream client secret from the configured file or the environment.
+func (u *UpstreamRunConfig) clientSecret() (string, error) {
+	if u.ClientSecretFile != "" {
+		data, err := os.ReadFile(u.ClientSecretFile)
+		if err != nil {
+			return "", fmt.Errorf("failed to read upstream client secret: %w", err)
+		}
+		return strings.TrimSpace(string(data)), nil
+	}
+	if secret := os.Getenv(UpstreamClientSecretEnvVar); secret != "" {
+		return secret, nil
+	}
+	return "", fmt.Errorf("upstream client secret is required: set client_secret_file or %s", UpstreamClientSecretEnvVar)
+}
+
+// parseLifespan parses an optional lifespan. An empty value yields zero, which selects the default.
+func parseLifespan(value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid lifespan %q: %w", value, err)
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("lifespan %q must be positive", value)
+	}
+	return d, nil
+}
@@ -0,0 +1,131 @@
+// Copyright 2025 Stacklok, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authserver
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stacklok/toolhive/pkg/authserver/storage"
+)
+
+func validRunConfig() *RunConfig {
+	return &RunConfig{
+		Issuer:           "https://mcp.example.com",
+		AllowedAudiences: []string{"https://mcp.example.com/mcp"},
+		Upstream: &UpstreamRunConfig{
+			Issuer:   "https://idp.example.com",
+			ClientID: "toolhive",
+		},
+	}
+}
+
+func TestRunConfig_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		modify  func(*RunConfig)
+		wantErr bool
+	}{
+		{name: "valid", modify: func(*RunConfig) {}},
+		{name: "issuer with path", modify: func(c *RunConfig) { c.Issuer = "https://mcp.example.com/auth" }, wantErr: true},
+		{name: "no audiences", modify: func(c *RunConfig) { c.AllowedAudiences = nil }, wantErr: true},
+		{name: "invalid lifespan", modify: func(c *RunConfig) { c.AccessTokenLifespan = "soon" }, wantErr: true},
+		{name: "negative lifespan", modify: func(c *RunConfig) { c.AuthCodeLifespan = "-1m" }, wantErr: true},
+		{name: "no upstream", modify: func(c *RunConfig) { c.Upstream = nil }, wantErr: true},
+		{name: "no upstream client ID", modify: func(c *RunConfig) { c.Upstream.ClientID = "" }, wantErr: true},
+		{
+			name:    "unsupported storage",
+			modify:  func(c *RunConfig) { c.Storage = &storage.RunConfig{Type: "etcd"} },
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			cfg := validRunConfig()
+			tt.modify(cfg)
+			err := cfg.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRunConfig_TokenValidatorConfig(t *testing.T) {
+	t.Parallel()
+
+	cfg := validRunConfig()
+	cfg.Issuer = "http://localhost:8080/"
+
+	validatorConfig := cfg.TokenValidatorConfig()
+	assert.Equal(t, "http://localhost:8080", validatorConfig.Issuer)
+	assert.Equal(t, "http://localhost:8080/.well-known/jwks.json", validatorConfig.JWKSURL)
+	assert.Equal(t, "https://mcp.example.com/mcp", validatorConfig.Audience)
+	assert.Equal(t, "https://mcp.example.com/mcp", validatorConfig.ResourceURL)
+	assert.True(t, validatorConfig.AllowPrivateIP)
+	assert.True(t, validatorConfig.InsecureAllowHTTP)
+}
+
+func TestLoadRunConfig(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "authserver.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+issuer: https://mcp.example.com
+access_token_lifespan: 30m
+allowed_audiences:
+  - https://mcp.example.com/mcp
+upstream:
+  issuer: https://idp.example.com
+  client_id: toolhive
+  client_secret_file: /etc/toolhive/upstream-secret
+`), 0600))
+
+	cfg, err := LoadRunConfig(path)
+	require.NoError(t, err)
+	assert.Equal(t, "https://mcp.example.com", cfg.Issuer)
+	assert.Equal(t, "30m", cfg.AccessTokenLifespan)
+	assert.Equal(t, "/etc/toolhive/upstream-secret", cfg.Upstream.ClientSecretFile)
+
+	// Unknown fields are rejected to catch typos
+	require.NoError(t, os.WriteFile(path, []byte("issuer: https://mcp.example.com\nunknown: true\n"), 0600))
+	_, err = LoadRunConfig(path)
+	assert.Error(t, err)
+}
+
+func TestUpstreamRunConfig_ClientSecret(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "secret")
+	require.NoError(t, os.WriteFile(path, []byte("s3cret\n"), 0600))
+
+	secret, err := (&UpstreamRunConfig{ClientSecretFile: path}).clientSecret()
+	require.NoError(t, err)
+	assert.Equal(t, "s3cret", secret)
+
+	_, err = (&UpstreamRunConfig{ClientSecretFile: filepath.Join(t.TempDir(), "missing")}).clientSecret()
+	assert.Error(t, err)
+}
@@ -0,0 +1,306 @@
+// Copyright 2025 Stacklok, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ory/fosite"
+
+	"github.com/stacklok/toolhive/pkg/authserver/server/crypto"
+	"github.com/stacklok/toolhive/pkg/authserver/server/session"
+	"github.com/stacklok/toolhive/pkg/authserver/storage"
+	"github.com/stacklok/toolhive/pkg/authserver/upstream"
+	"github.com/stacklok/toolhive/pkg/logger"
+)
+
+// resourceParam is the RFC 8707 resource indicator parameter.
+const resourceParam = "resource"
+
+// randomStateBytes is the entropy of the internal state and nonce sent upstream.
+const randomStateBytes = 32
+
+// errInvalidTarget is returned when a requested resource is not served by this server (RFC 8707).
+var errInvalidTarget = &fosite.RFC6749Error{
+	ErrorField:       "invalid_target",
+	DescriptionField: "The requested resource is invalid, unknown, or malformed.",
+	CodeField:        http.StatusBadRequest,
+}
+
+// handleAuthorize validates the client's authorization request, records it and
+// redirects the user to the upstream IDP.
+func (s *Server) handleAuthorize(w http.ResponseWriter, r *http.Request) {
+	if !requireMethod(w, r, http.MethodGet, http.MethodPost) {
+		return
+	}
+	ctx := r.Context()
+
+	ar, err := s.provider.NewAuthorizeRequest(ctx, r)
+	if err != nil {
+		s.provider.WriteAuthorizeError(ctx, w, ar, err)
+		return
+	}
+
+	form := ar.GetRequestForm()
+	if form.Get("code_challenge") == "" || form.Get("code_challenge_method") != crypto.PKCEChallengeMethodS256 {
+		s.provider.WriteAuthorizeError(ctx, w, ar,
+			fosite.ErrInvalidRequest.WithHint("PKCE with the S256 code challenge method is required."))
+		return
+	}
+
+	audiences, err := s.resolveAudiences(form[resourceParam])
+	if err != nil {
+		s.provider.WriteAuthorizeError(ctx, w, ar, err)
+		return
+	}
+
+	internalState, err := randomString()
+	if err != nil {
+		s.provider.WriteAuthorizeError(ctx, w, ar, fosite.ErrServerError.WithWrap(err))
+		return
+	}
+	nonce, err := randomString()
+	if err != nil {
+		s.provider.WriteAuthorizeError(ctx, w, ar, fosite.ErrServerError.WithWrap(err))
+		return
+	}
+	verifier, err := crypto.GeneratePKCEVerifier()
+	if err != nil {
+		s.provider.WriteAuthorizeError(ctx, w, ar, fosite.ErrServerError.WithWrap(err))
+		return
+	}
+
+	pending := &storage.PendingAuthorization{
+		ClientID:             ar.GetClient().GetID(),
+		RedirectURI:          form.Get("redirect_uri"),
+		State:                ar.GetState(),
+		PKCEChallenge:        form.Get("code_challenge"),
+		PKCEMethod:           form.Get("code_challenge_method"),
+		Scopes:               ar.GetRequestedScopes(),
+		Audiences:            audiences,
+		InternalState:        internalState,
+		UpstreamPKCEVerifier: verifier,
+		UpstreamNonce:        nonce,
+		CreatedAt:            time.Now(),
+	}
+	if err := s.storage.StorePendingAuthorization(ctx, internalState, pending); err != nil {
+		s.provider.WriteAuthorizeError(ctx, w, ar, fosite.ErrServerError.WithWrap(err))
+		return
+	}
+
+	upstreamURL, err := s.config.Upstream.AuthorizationURL(internalState, crypto.ComputePKCEChallenge(verifier), nonce)
+	if err != nil {
+		s.provider.WriteAuthorizeError(ctx, w, ar, fosite.ErrServerError.WithWrap(err))
+		return
+	}
+	http.Redirect(w, r, upstreamURL, http.StatusFound)
+}
+
+// handleCallback completes the upstream login and issues an authorization code to the client.
+func (s *Server) handleCallback(w http.ResponseWriter, r *http.Request) {
+	if !requireMethod(w, r, http.MethodGet) {
+		return
+	}
+	ctx := r.Context()
+	query := r.URL.Query()
+
+	internalState := query.Get("state")
+	if internalState == "" {
+		http.Error(w, "missing state parameter", http.StatusBadRequest)
+		return
+	}
+	pending, err := s.storage.LoadPendingAuthorization(ctx, internalState)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) || errors.Is(err, storage.ErrExpired) {
+			http.Error(w, "unknown or expired authorization request", http.StatusBadRequest)
+			return
+		}
+		logger.Errorf("Failed to load pending authorization: %v", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	// The pending authorization is single use
+	if err := s.storage.DeletePendingAuthorization(ctx, internalState); err != nil && !errors.Is(err, storage.ErrNotFound) {
+		logger.Warnf("Failed to delete pending authorization: %v", err)
+	}
+
+	// Rebuild the client's authorization request so that errors and the
+	// final response are delivered to its redirect URI.
+	ar, err := s.restoreAuthorizeRequest(ctx, pending)
+	if err != nil {
+		logger.Errorf("Failed to restore authorization request for client %s: %v", pending.ClientID, err)
+		http.Error(w, "invalid authorization request", http.StatusBadRequest)
+		return
+	}
+
+	if upstreamErr := query.Get("error"); upstreamErr != "" {
+		logger.Infof("Upstream IDP returned error %q: %s", upstreamErr, query.Get("error_description"))
+		s.provider.WriteAuthorizeError(ctx, w, ar,
+			fosite.ErrAccessDenied.WithHint("The upstream identity provider denied the request."))
+		return
+	}
+
+	sess, err := s.completeUpstreamLogin(ctx, query.Get("code"), pending)
+	if err != nil {
+		logger.Warnf("Upstream login failed for client %s: %v", pending.ClientID, err)
+		s.provider.WriteAuthorizeError(ctx, w, ar,
+			fosite.ErrAccessDenied.WithHint("Authentication with the upstream identity provider failed."))
+		return
+	}
+
+	for _, scope := range ar.GetRequestedScopes() {
+		ar.GrantScope(scope)
+	}
+	for _, audience := range pending.Audiences {
+		ar.GrantAudience(audience)
+	}
+
+	resp, err := s.provider.NewAuthorizeResponse(ctx, ar, sess)
+	if err != nil {
+		s.provider.WriteAuthorizeError(ctx, w, ar, err)
+		return
+	}
+	s.provider.WriteAuthorizeResponse(ctx, w, ar, resp)
+}
+
+// completeUpstreamLogin exchanges the upstream authorization code, validates the
+// ID token and stores the upstream tokens. It returns the session for the tokens
+// issued to the client.
+func (s *Server) completeUpstreamLogin(
+	ctx context.Context,
+	code string,
+	pending *storage.PendingAuthorization,
+) (*session.Session, error) {
+	if code == "" {
+		return nil, fmt.Errorf("upstream callback is missing the authorization code")
+	}
+
+	tokens, err := s.config.Upstream.ExchangeCode(ctx, code, pending.UpstreamPKCEVerifier)
+	if err != nil {
+		return nil, err
+	}
+
+	subject, email, name, err := s.upstreamIdentity(ctx, tokens, pending.UpstreamNonce)
+	if err != nil {
+		return nil, err
+	}
+
+	sessionID := uuid.NewString()
+	if err := s.storage.StoreUpstreamTokens(ctx, sessionID, &storage.UpstreamTokens{
+		AccessToken:  tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		IDToken:      tokens.IDToken,
+		ExpiresAt:    tokens.ExpiresAt,
+		Subject:      subject,
+		ClientID:     pending.ClientID,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to store upstream tokens: %w", err)
+	}
+
+	sess := session.New(subject, sessionID, pending.ClientID)
+	if email != "" {
+		sess.JWTClaims.Extra["email"] = email
+	}
+	if name != "" {
+		sess.JWTClaims.Extra["name"] = name
+	}
+	return sess, nil
+}
+
+// upstreamIdentity determines the authenticated user from the upstream tokens.
+// OIDC providers are identified by the validated ID token; other providers by user info.
+func (s *Server) upstreamIdentity(
+	ctx context.Context,
+	tokens *upstream.Tokens,
+	nonce string,
+) (subject, email, name string, err error) {
+	if validator, ok := s.config.Upstream.(upstream.IDTokenNonceValidator); ok {
+		if tokens.IDToken == "" {
+			return "", "", "", upstream.ErrIDTokenMissing
+		}
+		claims, err := validator.ValidateIDTokenWithNonce(tokens.IDToken, nonce)
+		if err != nil {
+			return "", "", "", err
+		}
+		return claims.Subject, claims.Email, claims.Name, nil
+	}
+
+	info, err := s.config.Upstream.UserInfo(ctx, tokens.AccessToken)
+	if err != nil {
+		return "", "", "", err
+	}
+	return info.Subject, info.Email, info.Name, nil
+}
+
+// restoreAuthorizeRequest rebuilds and revalidates the client's authorization request
+// from a pending authorization.
+func (s *Server) restoreAuthorizeRequest(
+	ctx context.Context,
+	pending *storage.PendingAuthorization,
+) (fosite.AuthorizeRequester, error) {
+	params := url.Values{
+		"client_id":             {pending.ClientID},
+		"response_type":         {"code"},
+		"state":                 {pending.State},
+		"code_challenge":        {pending.PKCEChallenge},
+		"code_challenge_method": {pending.PKCEMethod},
+	}
+	if pending.RedirectURI != "" {
+		params.Set("redirect_uri", pending.RedirectURI)
+	}
+	if len(pending.Scopes) > 0 {
+		params.Set("scope", strings.Join(pending.Scopes, " "))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		s.config.endpoint(AuthorizationEndpointPath)+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	return s.provider.NewAuthorizeRequest(ctx, req)
+}
+
+// resolveAudiences validates the requested RFC 8707 resource indicators against the
+// allowed audiences. Without resource indicators, all allowed audiences are granted.
+func (s *Server) resolveAudiences(resources []string) ([]string, error) {
+	if len(resources) == 0 {
+		return s.config.AllowedAudiences, nil
+	}
+	for _, resource := range resources {
+		if !slices.Contains(s.config.AllowedAudiences, resource) {
+			return nil, errInvalidTarget.WithHintf("Resource %q is not served by this authorization server.", resource)
+		}
+	}
+	return resources, nil
+}
+
+// randomString returns a URL-safe random string.
+func randomString() (string, error) {
+	b := make([]byte, randomStateBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate random value: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,173 @@
+// Copyright 2025 Stacklok, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"crypto/ed25519"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/stacklok/toolhive/pkg/authserver/server/crypto"
+	"github.com/stacklok/toolhive/pkg/authserver/server/registration"
+	"github.com/stacklok/toolhive/pkg/authserver/storage"
+	"github.com/stacklok/toolhive/pkg/authserver/upstream"
+)
+
+// Endpoint paths served by the authorization server, relative to the issuer.
+const (
+	// AuthorizationEndpointPath is the OAuth authorization endpoint.
+	AuthorizationEndpointPath = "/oauth/authorize"
+
+	// CallbackEndpointPath receives the upstream IDP redirect after the user authenticates.
+	CallbackEndpointPath = "/oauth/callback"
+
+	// TokenEndpointPath is the OAuth token endpoint.
+	TokenEndpointPath = "/oauth/token"
+
+	// RevocationEndpointPath is the token revocation endpoint (RFC 7009).
+	RevocationEndpointPath = "/oauth/revoke"
+
+	// RegistrationEndpointPath is the dynamic client registration endpoint (RFC 7591).
+	RegistrationEndpointPath = "/oauth/register"
+
+	// JWKSPath serves the public keys used to verify issued access tokens.
+	JWKSPath = "/.well-known/jwks.json"
+
+	// AuthorizationServerMetadataPath serves the authorization server metadata (RFC 8414).
+	AuthorizationServerMetadataPath = "/.well-known/oauth-authorization-server"
+
+	// OpenIDConfigurationPath serves the metadata for clients that use OIDC discovery.
+	OpenIDConfigurationPath = "/.well-known/openid-configuration"
+)
+
+// Paths lists every path served by the authorization server.
+// Proxies that embed the server route these paths to its handler.
+var Paths = []string{
+	AuthorizationEndpointPath,
+	CallbackEndpointPath,
+	TokenEndpointPath,
+	RevocationEndpointPath,
+	RegistrationEndpointPath,
+	JWKSPath,
+	AuthorizationServerMetadataPath,
+	OpenIDConfigurationPath,
+}
+
+// Config is the resolved configuration of the authorization server.
+type Config struct {
+	// Issuer is the issuer identifier and base URL of the authorization server.
+	// It must be an absolute URL without a path, query or fragment.
+	Issuer string
+
+	// SigningKey is the key used to sign access tokens.
+	SigningKey *crypto.SigningKeyParams
+
+	// HMACSecrets sign authorization codes and refresh tokens.
+	HMACSecrets *crypto.HMACSecrets
+
+	// AccessTokenLifespan is how long issued access tokens are valid.
+	// Defaults to storage.DefaultAccessTokenTTL.
+	AccessTokenLifespan time.Duration
+
+	// RefreshTokenLifespan is how long issued refresh tokens are valid.
+	// Defaults to storage.DefaultRefreshTokenTTL.
+	RefreshTokenLifespan time.Duration
+
+	// AuthCodeLifespan is how long authorization codes are valid.
+	// Defaults to storage.DefaultAuthCodeTTL.
+	AuthCodeLifespan time.Duration
+
+	// AllowedAudiences are the resource servers tokens can be issued for.
+	// Clients select one with the RFC 8707 resource parameter; all are granted otherwise.
+	AllowedAudiences []string
+
+	// ScopesSupported are the scopes clients may request.
+	// Defaults to registration.DefaultScopes.
+	ScopesSupported []string
+
+	// Upstream is the identity provider users authenticate with.
+	Upstream upstream.Provider
+}
+
+// applyDefaults fills in unset optional fields.
+func (c *Config) applyDefaults() {
+	if c.AccessTokenLifespan == 0 {
+		c.AccessTokenLifespan = storage.DefaultAccessTokenTTL
+	}
+	if c.RefreshTokenLifespan == 0 {
+		c.RefreshTokenLifespan = storage.DefaultRefreshTokenTTL
+	}
+	if c.AuthCodeLifespan == 0 {
+		c.AuthCodeLifespan = storage.DefaultAuthCodeTTL
+	}
+	if len(c.ScopesSupported) == 0 {
+		c.ScopesSupported = registration.DefaultScopes
+	}
+}
+
+// Validate checks that the configuration is complete.
+func (c *Config) Validate() error {
+	if err := ValidateIssuer(c.Issuer); err != nil {
+		return err
+	}
+	if c.SigningKey == nil || c.SigningKey.Key == nil {
+		return fmt.Errorf("signing key is required")
+	}
+	if _, ok := c.SigningKey.Key.(ed25519.PrivateKey); ok {
+		return fmt.Errorf("Ed25519 signing keys are not supported, use an RSA or EC key")
+	}
+	if c.HMACSecrets == nil || len(c.HMACSecrets.Current) < crypto.MinSecretLength {
+		return fmt.Errorf("HMAC secret of at least %d bytes is required", crypto.MinSecretLength)
+	}
+	if len(c.AllowedAudiences) == 0 {
+		return fmt.Errorf("at least one allowed audience is required")
+	}
+	if c.Upstream == nil {
+		return fmt.Errorf("upstream identity provider is required")
+	}
+	if c.AccessTokenLifespan < 0 || c.RefreshTokenLifespan < 0 || c.AuthCodeLifespan < 0 {
+		return fmt.Errorf("token lifespans must not be negative")
+	}
+	return nil
+}
+
+// ValidateIssuer checks that issuer is usable as the authorization server issuer.
+// Endpoints are served at fixed paths, so the issuer must not contain a path.
+func ValidateIssuer(issuer string) error {
+	if issuer == "" {
+		return fmt.Errorf("issuer is required")
+	}
+	parsed, err := url.Parse(issuer)
+	if err != nil {
+		return fmt.Errorf("invalid issuer: %w", err)
+	}
+	if parsed.Scheme != "https" && parsed.Scheme != "http" {
+		return fmt.Errorf("issuer must use the http or https scheme")
+	}
+	if parsed.Host == "" {
+		return fmt.Errorf("issuer must be an absolute URL")
+	}
+	if strings.TrimSuffix(parsed.Path, "/") != "" || parsed.RawQuery != "" || parsed.Fragment != "" {
+		return fmt.Errorf("issuer must not contain a path, query or fragment")
+	}
+	return nil
+}
+
+// endpoint returns the absolute URL of an authorization server path.
+func (c *Config) endpoint(path string) string {
+	return strings.TrimSuffix(c.Issuer, "/") + path
+}
@@ -0,0 +1,93 @@
+// Copyright 2025 Stacklok, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"net/http"
+
+	"github.com/go-jose/go-jose/v4"
+
+	"github.com/stacklok/toolhive/pkg/authserver/server/crypto"
+	"github.com/stacklok/toolhive/pkg/authserver/server/registration"
+)
+
+// AuthorizationServerMetadata is the authorization server metadata document (RFC 8414).
+type AuthorizationServerMetadata struct {
+	Issuer                            string   `json:"issuer"`
+	AuthorizationEndpoint             string   `json:"authorization_endpoint"`
+	TokenEndpoint                     string   `json:"token_endpoint"`
+	RevocationEndpoint                string   `json:"revocation_endpoint,omitempty"`
+	RegistrationEndpoint              string   `json:"registration_endpoint,omitempty"`
+	JWKSURI                           string   `json:"jwks_uri"`
+	ScopesSupported                   []string `json:"scopes_supported,omitempty"`
+	ResponseTypesSupported            []string `json:"response_types_supported"`
+	GrantTypesSupported               []string `json:"grant_types_supported,omitempty"`
+	TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported,omitempty"`
+	CodeChallengeMethodsSupported     []string `json:"code_challenge_methods_supported,omitempty"`
+
+	// The following fields are required by OIDC Discovery and are only
+	// included in the openid-configuration document.
+	SubjectTypesSupported            []string `json:"subject_types_supported,omitempty"`
+	IDTokenSigningAlgValuesSupported []string `json:"id_token_signing_alg_values_supported,omitempty"`
+}
+
+// metadata builds the authorization server metadata document.
+func (s *Server) metadata() *AuthorizationServerMetadata {
+	return &AuthorizationServerMetadata{
+		Issuer:                            s.config.Issuer,
+		AuthorizationEndpoint:             s.config.endpoint(AuthorizationEndpointPath),
+		TokenEndpoint:                     s.config.endpoint(TokenEndpointPath),
+		RevocationEndpoint:                s.config.endpoint(RevocationEndpointPath),
+		RegistrationEndpoint:              s.config.endpoint(RegistrationEndpointPath),
+		JWKSURI:                           s.config.endpoint(JWKSPath),
+		ScopesSupported:                   s.config.ScopesSupported,
+		ResponseTypesSupported:            registration.DefaultResponseTypes,
+		GrantTypesSupported:               registration.DefaultGrantTypes,
+		TokenEndpointAuthMethodsSupported: []string{"none", "client_secret_basic", "client_secret_post"},
+		CodeChallengeMethodsSupported:     []string{crypto.PKCEChallengeMethodS256},
+	}
+}
+
+func (s *Server) handleAuthorizationServerMetadata(w http.ResponseWriter, r *http.Request) {
+	if !requireMethod(w, r, http.MethodGet) {
+		return
+	}
+	writeJSON(w, http.StatusOK, s.metadata())
+}
+
+func (s *Server) handleOpenIDConfiguration(w http.ResponseWriter, r *http.Request) {
+	if !requireMethod(w, r, http.MethodGet) {
+		return
+	}
+	metadata := s.metadata()
+	metadata.SubjectTypesSupported = []string{"public"}
+	metadata.IDTokenSigningAlgValuesSupported = []string{s.config.SigningKey.Algorithm}
+	writeJSON(w, http.StatusOK, metadata)
+}
+
+func (s *Server) handleJWKS(w http.ResponseWriter, r *http.Request) {
+	if !requireMethod(w, r, http.MethodGet) {
+		return
+	}
+	key := s.config.SigningKey
+	writeJSON(w, http.StatusOK, jose.JSONWebKeySet{
+		Keys: []jose.JSONWebKey{{
+			Key:       key.Key.Public(),
+			KeyID:     key.KeyID,
+			Algorithm: key.Algorithm,
+			Use:       "sig",
+		}},
+	})
+}
@@ -0,0 +1,72 @@
+// Copyright 2025 Stacklok, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+
+	josev3 "github.com/go-jose/go-jose/v3"
+	"github.com/ory/fosite"
+	"github.com/ory/fosite/compose"
+	"github.com/ory/fosite/token/jwt"
+
+	"github.com/stacklok/toolhive/pkg/authserver/storage"
+)
+
+// newOAuth2Provider creates the fosite provider for the authorization code flow with
+// PKCE and refresh tokens. Access tokens are JWTs signed with the configured key so
+// that resource servers can validate them against the JWKS; authorization codes and
+// refresh tokens are opaque HMAC tokens.
+func newOAuth2Provider(cfg *Config, store storage.Storage) fosite.OAuth2Provider {
+	fositeConfig := &fosite.Config{
+		AccessTokenLifespan:   cfg.AccessTokenLifespan,
+		RefreshTokenLifespan:  cfg.RefreshTokenLifespan,
+		AuthorizeCodeLifespan: cfg.AuthCodeLifespan,
+		AccessTokenIssuer:     cfg.Issuer,
+		TokenURL:              cfg.endpoint(TokenEndpointPath),
+		GlobalSecret:          cfg.HMACSecrets.Current,
+		RotatedGlobalSecrets:  cfg.HMACSecrets.Rotated,
+		ScopeStrategy:         fosite.ExactScopeStrategy,
+		JWTScopeClaimKey:      jwt.JWTScopeFieldString,
+		// MCP clients are public clients, so PKCE is always required
+		EnforcePKCE:                    true,
+		EnablePKCEPlainChallengeMethod: false,
+		// Issue refresh tokens regardless of the requested scopes
+		RefreshTokenScopes: []string{},
+	}
+
+	// fosite signs with go-jose v3; the JWK carries the key ID and algorithm into the token header
+	signingKey := &josev3.JSONWebKey{
+		Key:       cfg.SigningKey.Key,
+		KeyID:     cfg.SigningKey.KeyID,
+		Algorithm: cfg.SigningKey.Algorithm,
+		Use:       "sig",
+	}
+	keyGetter := func(context.Context) (interface{}, error) {
+		return signingKey, nil
+	}
+
+	strategy := compose.NewOAuth2JWTStrategy(keyGetter, compose.NewOAuth2HMACStrategy(fositeConfig), fositeConfig)
+
+	return compose.Compose(
+		fositeConfig,
+		store,
+		strategy,
+		compose.OAuth2AuthorizeExplicitFactory,
+		compose.OAuth2RefreshTokenGrantFactory,
+		compose.OAuth2TokenRevocationFactory,
+		compose.OAuth2PKCEFactory,
+	)
+}
@@ -110,6 +110,9 @@ type Config struct {
 	// Scopes overrides the default scopes.
 	// If nil or empty, DefaultScopes is used.
 	Scopes []string
+
+	// Audience is the list of audiences (resource servers) the client may request tokens for.
+	Audience []string
 }
 
 // New creates a fosite.Client from the given configuration.
@@ -141,6 +144,7 @@ func New(cfg Config) (fosite.Client, error) {
 		ResponseTypes: responseTypes,
 		GrantTypes:    grantTypes,
 		Scopes:        scopes,
+		Audience:      cfg.Audience,
 		Public:        cfg.Public,
 	}
 
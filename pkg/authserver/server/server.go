@@ -0,0 +1,110 @@
+// Copyright 2025 Stacklok, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package server implements the HTTP endpoints of the OAuth authorization server.
+//
+// The server issues its own JWT access tokens to MCP clients after federating
+// user authentication to an upstream OIDC identity provider:
+//
+//  1. The client starts the authorization code flow at AuthorizationEndpointPath.
+//  2. The server records the request and redirects the user to the upstream IDP.
+//  3. The upstream IDP redirects back to CallbackEndpointPath. The server exchanges
+//     the upstream code, stores the upstream tokens and redirects the user to the
+//     client with its own authorization code.
+//  4. The client exchanges the code at TokenEndpointPath for an access token that
+//     resource servers validate against the keys published at JWKSPath.
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/ory/fosite"
+
+	"github.com/stacklok/toolhive/pkg/authserver/storage"
+	"github.com/stacklok/toolhive/pkg/logger"
+)
+
+// Server serves the OAuth authorization server endpoints.
+type Server struct {
+	config   *Config
+	storage  storage.Storage
+	provider fosite.OAuth2Provider
+}
+
+// New creates an authorization server from a resolved configuration and a storage backend.
+func New(cfg Config, store storage.Storage) (*Server, error) {
+	if store == nil {
+		return nil, fmt.Errorf("storage is required")
+	}
+	cfg.Issuer = strings.TrimSuffix(cfg.Issuer, "/")
+	cfg.applyDefaults()
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid authorization server configuration: %w", err)
+	}
+
+	return &Server{
+		config:   &cfg,
+		storage:  store,
+		provider: newOAuth2Provider(&cfg, store),
+	}, nil
+}
+
+// Issuer returns the issuer identifier of the server.
+func (s *Server) Issuer() string {
+	return s.config.Issuer
+}
+
+// JWKSURL returns the URL of the JSON Web Key Set used to verify issued tokens.
+func (s *Server) JWKSURL() string {
+	return s.config.endpoint(JWKSPath)
+}
+
+// Handler returns an HTTP handler serving all authorization server endpoints.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(AuthorizationServerMetadataPath, s.handleAuthorizationServerMetadata)
+	mux.HandleFunc(OpenIDConfigurationPath, s.handleOpenIDConfiguration)
+	mux.HandleFunc(JWKSPath, s.handleJWKS)
+	mux.HandleFunc(AuthorizationEndpointPath, s.handleAuthorize)
+	mux.HandleFunc(CallbackEndpointPath, s.handleCallback)
+	mux.HandleFunc(TokenEndpointPath, s.handleToken)
+	mux.HandleFunc(RevocationEndpointPath, s.handleRevoke)
+	mux.HandleFunc(RegistrationEndpointPath, s.handleRegister)
+	return mux
+}
+
+// writeJSON writes v as a JSON response with the given status code.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		logger.Debugf("Failed to write authorization server response: %v", err)
+	}
+}
+
+// requireMethod writes a 405 response and returns false if the request method is not allowed.
+func requireMethod(w http.ResponseWriter, r *http.Request, methods ...string) bool {
+	for _, method := range methods {
+		if r.Method == method {
+			return true
+		}
+	}
+	w.Header().Set("Allow", strings.Join(methods, ", "))
+	http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+	return false
+}
@@ -0,0 +1,359 @@
+// Copyright 2025 Stacklok, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	josejwt "github.com/go-jose/go-jose/v4/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stacklok/toolhive/pkg/authserver/server/crypto"
+	"github.com/stacklok/toolhive/pkg/authserver/server/session"
+	"github.com/stacklok/toolhive/pkg/authserver/storage"
+	"github.com/stacklok/toolhive/pkg/authserver/upstream"
+)
+
+const (
+	testAudience    = "https://mcp.example.com/mcp"
+	testRedirectURI = "http://localhost:8765/callback"
+	testUpstreamURL = "https://idp.example.com/authorize"
+	testSubject     = "user-123"
+)
+
+// fakeUpstream is an upstream provider that authenticates every user as testSubject.
+type fakeUpstream struct{}
+
+func (fakeUpstream) Name() string { return "fake" }
+
+func (fakeUpstream) AuthorizationURL(state, codeChallenge, nonce string) (string, error) {
+	return testUpstreamURL + "?" + url.Values{
+		"state":          {state},
+		"code_challenge": {codeChallenge},
+		"nonce":          {nonce},
+	}.Encode(), nil
+}
+
+func (fakeUpstream) ExchangeCode(_ context.Context, _, _ string) (*upstream.Tokens, error) {
+	return &upstream.Tokens{
+		AccessToken:  "upstream-access-token",
+		RefreshToken: "upstream-refresh-token",
+		ExpiresAt:    time.Now().Add(time.Hour),
+	}, nil
+}
+
+func (fakeUpstream) RefreshTokens(_ context.Context, _ string) (*upstream.Tokens, error) {
+	return &upstream.Tokens{AccessToken: "upstream-access-token-2", ExpiresAt: time.Now().Add(time.Hour)}, nil
+}
+
+func (fakeUpstream) UserInfo(_ context.Context, _ string) (*upstream.UserInfo, error) {
+	return &upstream.UserInfo{Subject: testSubject, Email: "user@example.com"}, nil
+}
+
+type testServer struct {
+	url     string
+	storage *storage.MemoryStorage
+	client  *http.Client
+}
+
+func newTestServer(t *testing.T) *testServer {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	signingKey, err := crypto.DeriveSigningKeyParams(key, "", "")
+	require.NoError(t, err)
+
+	secret := make([]byte, crypto.MinSecretLength)
+	_, err = rand.Read(secret)
+	require.NoError(t, err)
+
+	store := storage.NewMemoryStorage()
+	t.Cleanup(func() { _ = store.Close() })
+
+	ts := httptest.NewUnstartedServer(nil)
+	issuer := "http://" + ts.Listener.Addr().String()
+
+	srv, err := New(Config{
+		Issuer:           issuer,
+		SigningKey:       signingKey,
+		HMACSecrets:      &crypto.HMACSecrets{Current: secret},
+		AllowedAudiences: []string{testAudience},
+		Upstream:         fakeUpstream{},
+	}, store)
+	require.NoError(t, err)
+
+	ts.Config.Handler = srv.Handler()
+	ts.Start()
+	t.Cleanup(ts.Close)
+
+	return &testServer{
+		url:     issuer,
+		storage: store,
+		client: &http.Client{
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+func (s *testServer) register(t *testing.T) string {
+	t.Helper()
+
+	resp, err := s.client.Post(s.url+RegistrationEndpointPath, "application/json",
+		strings.NewReader(`{"redirect_uris":["`+testRedirectURI+`"],"client_name":"test"}`))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	var registered struct {
+		ClientID string `json:"client_id"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&registered))
+	require.NotEmpty(t, registered.ClientID)
+	return registered.ClientID
+}
+
+// authorize starts an authorization request and returns the redirect location.
+func (s *testServer) authorize(t *testing.T, params url.Values) *url.URL {
+	t.Helper()
+
+	resp, err := s.client.Get(s.url + AuthorizationEndpointPath + "?" + params.Encode())
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	return redirectLocation(t, resp)
+}
+
+// redirectLocation returns the target of a redirect response. fosite redirects
+// with 303 See Other, while redirects to the upstream IDP use 302 Found.
+func redirectLocation(t *testing.T, resp *http.Response) *url.URL {
+	t.Helper()
+
+	require.Contains(t, []int{http.StatusFound, http.StatusSeeOther}, resp.StatusCode)
+	location, err := resp.Location()
+	require.NoError(t, err)
+	return location
+}
+
+func (s *testServer) token(t *testing.T, form url.Values) map[string]any {
+	t.Helper()
+
+	resp, err := s.client.PostForm(s.url+TokenEndpointPath, form)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	var body map[string]any
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	require.Equal(t, http.StatusOK, resp.StatusCode, "token response: %v", body)
+	return body
+}
+
+func authorizeParams(clientID, challenge string) url.Values {
+	return url.Values{
+		"client_id":             {clientID},
+		"response_type":         {"code"},
+		"redirect_uri":          {testRedirectURI},
+		"state":                 {"client-state"},
+		"scope":                 {"openid email"},
+		"code_challenge":        {challenge},
+		"code_challenge_method": {crypto.PKCEChallengeMethodS256},
+		"resource":              {testAudience},
+	}
+}
+
+func TestServer_AuthorizationCodeFlow(t *testing.T) {
+	t.Parallel()
+	s := newTestServer(t)
+	clientID := s.register(t)
+
+	verifier, err := crypto.GeneratePKCEVerifier()
+	require.NoError(t, err)
+
+	// The user is sent to the upstream IDP with a state of our own
+	upstreamRedirect := s.authorize(t, authorizeParams(clientID, crypto.ComputePKCEChallenge(verifier)))
+	assert.True(t, strings.HasPrefix(upstreamRedirect.String(), testUpstreamURL))
+	internalState := upstreamRedirect.Query().Get("state")
+	require.NotEmpty(t, internalState)
+	assert.NotEqual(t, "client-state", internalState)
+
+	// The upstream IDP redirects back and the client receives an authorization code
+	resp, err := s.client.Get(s.url + CallbackEndpointPath + "?" + url.Values{
+		"code":  {"upstream-code"},
+		"state": {internalState},
+	}.Encode())
+	require.NoError(t, err)
+	resp.Body.Close()
+	clientRedirect := redirectLocation(t, resp)
+	assert.True(t, strings.HasPrefix(clientRedirect.String(), testRedirectURI))
+	assert.Equal(t, "client-state", clientRedirect.Query().Get("state"))
+	code := clientRedirect.Query().Get("code")
+	require.NotEmpty(t, code)
+
+	// The pending authorization is single use
+	resp, err = s.client.Get(s.url + CallbackEndpointPath + "?" + url.Values{
+		"code":  {"upstream-code"},
+		"state": {internalState},
+	}.Encode())
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	tokens := s.token(t, url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {testRedirectURI},
+		"client_id":     {clientID},
+		"code_verifier": {verifier},
+	})
+	accessToken, _ := tokens["access_token"].(string)
+	refreshToken, _ := tokens["refresh_token"].(string)
+	require.NotEmpty(t, accessToken)
+	require.NotEmpty(t, refreshToken)
+
+	// The access token is a JWT signed with the published key
+	resp, err = s.client.Get(s.url + JWKSPath)
+	require.NoError(t, err)
+	var jwks jose.JSONWebKeySet
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&jwks))
+	resp.Body.Close()
+	require.Len(t, jwks.Keys, 1)
+
+	parsed, err := josejwt.ParseSigned(accessToken, []jose.SignatureAlgorithm{jose.ES256})
+	require.NoError(t, err)
+	var claims josejwt.Claims
+	extra := map[string]any{}
+	require.NoError(t, parsed.Claims(jwks.Keys[0].Key, &claims, &extra))
+	assert.Equal(t, s.url, claims.Issuer)
+	assert.Equal(t, testSubject, claims.Subject)
+	assert.Contains(t, claims.Audience, testAudience)
+	assert.Equal(t, clientID, extra[session.ClientIDClaimKey])
+
+	// The upstream tokens are linked to the access token
+	tsid, _ := extra[session.TokenSessionIDClaimKey].(string)
+	require.NotEmpty(t, tsid)
+	upstreamTokens, err := s.storage.GetUpstreamTokens(context.Background(), tsid)
+	require.NoError(t, err)
+	assert.Equal(t, "upstream-access-token", upstreamTokens.AccessToken)
+	assert.Equal(t, testSubject, upstreamTokens.Subject)
+
+	refreshed := s.token(t, url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {refreshToken},
+		"client_id":     {clientID},
+	})
+	assert.NotEmpty(t, refreshed["access_token"])
+	assert.NotEqual(t, refreshToken, refreshed["refresh_token"])
+}
+
+func TestServer_AuthorizeRejects(t *testing.T) {
+	t.Parallel()
+	s := newTestServer(t)
+	clientID := s.register(t)
+
+	verifier, err := crypto.GeneratePKCEVerifier()
+	require.NoError(t, err)
+	challenge := crypto.ComputePKCEChallenge(verifier)
+
+	tests := []struct {
+		name          string
+		modify        func(url.Values)
+		expectedError string
+	}{
+		{
+			name:          "missing PKCE",
+			modify:        func(p url.Values) { p.Del("code_challenge"); p.Del("code_challenge_method") },
+			expectedError: "invalid_request",
+		},
+		{
+			name:          "plain PKCE",
+			modify:        func(p url.Values) { p.Set("code_challenge_method", "plain") },
+			expectedError: "invalid_request",
+		},
+		{
+			name:          "unknown resource",
+			modify:        func(p url.Values) { p.Set("resource", "https://other.example.com") },
+			expectedError: "invalid_target",
+		},
+		{
+			name:          "unsupported scope",
+			modify:        func(p url.Values) { p.Set("scope", "admin") },
+			expectedError: "invalid_scope",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			params := authorizeParams(clientID, challenge)
+			tt.modify(params)
+
+			location := s.authorize(t, params)
+			assert.True(t, strings.HasPrefix(location.String(), testRedirectURI))
+			assert.Equal(t, tt.expectedError, location.Query().Get("error"))
+		})
+	}
+}
+
+func TestServer_Metadata(t *testing.T) {
+	t.Parallel()
+	s := newTestServer(t)
+
+	for _, path := range []string{AuthorizationServerMetadataPath, OpenIDConfigurationPath} {
+		resp, err := s.client.Get(s.url + path)
+		require.NoError(t, err)
+		var metadata AuthorizationServerMetadata
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&metadata))
+		resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, s.url, metadata.Issuer)
+		assert.Equal(t, s.url+AuthorizationEndpointPath, metadata.AuthorizationEndpoint)
+		assert.Equal(t, s.url+TokenEndpointPath, metadata.TokenEndpoint)
+		assert.Equal(t, s.url+RegistrationEndpointPath, metadata.RegistrationEndpoint)
+		assert.Equal(t, s.url+JWKSPath, metadata.JWKSURI)
+		assert.Equal(t, []string{crypto.PKCEChallengeMethodS256}, metadata.CodeChallengeMethodsSupported)
+		if path == OpenIDConfigurationPath {
+			assert.Equal(t, []string{"ES256"}, metadata.IDTokenSigningAlgValuesSupported)
+		} else {
+			assert.Empty(t, metadata.IDTokenSigningAlgValuesSupported)
+		}
+	}
+}
+
+func TestNew_InvalidConfig(t *testing.T) {
+	t.Parallel()
+
+	store := storage.NewMemoryStorage()
+	t.Cleanup(func() { _ = store.Close() })
+
+	_, err := New(Config{Issuer: "https://mcp.example.com/path"}, store)
+	assert.Error(t, err)
+
+	_, err = New(Config{Issuer: "https://mcp.example.com"}, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,122 @@
+// Copyright 2025 Stacklok, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/stacklok/toolhive/pkg/authserver/server/registration"
+	"github.com/stacklok/toolhive/pkg/authserver/server/session"
+	"github.com/stacklok/toolhive/pkg/authserver/storage"
+	"github.com/stacklok/toolhive/pkg/logger"
+)
+
+// maxRegistrationRequestSize limits the size of dynamic client registration requests.
+const maxRegistrationRequestSize = 64 * 1024
+
+// handleToken exchanges authorization codes and refresh tokens for access tokens.
+func (s *Server) handleToken(w http.ResponseWriter, r *http.Request) {
+	if !requireMethod(w, r, http.MethodPost) {
+		return
+	}
+	ctx := r.Context()
+
+	// fosite replaces the placeholder session with the one stored for the code or refresh token
+	ar, err := s.provider.NewAccessRequest(ctx, r, session.New("", "", ""))
+	if err != nil {
+		s.provider.WriteAccessError(ctx, w, ar, err)
+		return
+	}
+
+	resp, err := s.provider.NewAccessResponse(ctx, ar)
+	if err != nil {
+		s.provider.WriteAccessError(ctx, w, ar, err)
+		return
+	}
+	s.provider.WriteAccessResponse(ctx, w, ar, resp)
+}
+
+// handleRevoke revokes access and refresh tokens (RFC 7009).
+func (s *Server) handleRevoke(w http.ResponseWriter, r *http.Request) {
+	if !requireMethod(w, r, http.MethodPost) {
+		return
+	}
+	ctx := r.Context()
+	err := s.provider.NewRevocationRequest(ctx, r)
+	s.provider.WriteRevocationResponse(ctx, w, err)
+}
+
+// handleRegister registers public clients dynamically (RFC 7591).
+func (s *Server) handleRegister(w http.ResponseWriter, r *http.Request) {
+	if !requireMethod(w, r, http.MethodPost) {
+		return
+	}
+	ctx := r.Context()
+
+	var req registration.DCRRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRegistrationRequestSize)).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, &registration.DCRError{
+			Error:            registration.DCRErrorInvalidClientMetadata,
+			ErrorDescription: "request body must be a JSON client metadata document",
+		})
+		return
+	}
+
+	validated, dcrErr := registration.ValidateDCRRequest(&req)
+	if dcrErr != nil {
+		writeJSON(w, http.StatusBadRequest, dcrErr)
+		return
+	}
+
+	client, err := registration.New(registration.Config{
+		ID:            uuid.NewString(),
+		RedirectURIs:  validated.RedirectURIs,
+		Public:        true,
+		GrantTypes:    validated.GrantTypes,
+		ResponseTypes: validated.ResponseTypes,
+		Scopes:        s.config.ScopesSupported,
+		Audience:      s.config.AllowedAudiences,
+	})
+	if err != nil {
+		logger.Errorf("Failed to create client: %v", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	if err := s.storage.RegisterClient(ctx, client); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, storage.ErrAlreadyExists) {
+			status = http.StatusConflict
+		}
+		logger.Errorf("Failed to register client: %v", err)
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, &registration.DCRResponse{
+		ClientID:                client.GetID(),
+		ClientIDIssuedAt:        time.Now().Unix(),
+		RedirectURIs:            validated.RedirectURIs,
+		ClientName:              validated.ClientName,
+		TokenEndpointAuthMethod: validated.TokenEndpointAuthMethod,
+		GrantTypes:              validated.GrantTypes,
+		ResponseTypes:           validated.ResponseTypes,
+	})
+}
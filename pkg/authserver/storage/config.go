@@ -14,7 +14,12 @@
 
 package storage
 
-import "time"
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+)
 
 // Type defines the type of storage backend.
 type Type string
@@ -23,6 +28,9 @@ const (
 	// TypeMemory uses in-memory storage (default).
 	TypeMemory Type = "memory"
 
+	// TypeSQL uses a SQLite or PostgreSQL database.
+	TypeSQL Type = "sql"
+
 	// DefaultCleanupInterval is how often the background cleanup runs.
 	DefaultCleanupInterval = 5 * time.Minute
 
@@ -42,10 +50,75 @@ const (
 	DefaultPKCETTL = 10 * time.Minute
 )
 
+// Dialect identifies the SQL dialect of the database.
+type Dialect string
+
+const (
+	// DialectSQLite targets SQLite databases.
+	DialectSQLite Dialect = "sqlite"
+
+	// DialectPostgres targets PostgreSQL databases.
+	DialectPostgres Dialect = "postgres"
+)
+
+// defaultDrivers maps each dialect to the database/sql driver name used when none is configured.
+var defaultDrivers = map[Dialect]string{
+	DialectSQLite:   "sqlite",
+	DialectPostgres: "pgx",
+}
+
 // Config configures the storage backend.
 type Config struct {
 	// Type specifies the storage backend type. Defaults to memory.
 	Type Type
+
+	// SQL configures the database when Type is TypeSQL.
+	SQL *SQLConfig
+}
+
+// SQLConfig configures SQL storage.
+type SQLConfig struct {
+	// Dialect is the SQL dialect of the database.
+	Dialect Dialect
+
+	// Driver is the name of the database/sql driver. Defaults to "sqlite" for SQLite
+	// and "pgx" for PostgreSQL, which are registered by this package. Other drivers
+	// must be registered by the binary.
+	Driver string
+
+	// DSN is the data source name passed to the driver.
+	DSN string
+
+	// EncryptionKey is the key material used to encrypt upstream tokens at rest.
+	// It must be at least MinEncryptionKeyLength bytes.
+	EncryptionKey []byte
+
+	// CleanupInterval is how often expired entries are deleted. Defaults to DefaultCleanupInterval.
+	CleanupInterval time.Duration
+}
+
+// Validate checks the SQL configuration.
+func (c *SQLConfig) Validate() error {
+	if _, ok := defaultDrivers[c.Dialect]; !ok {
+		return fmt.Errorf("unsupported SQL dialect %q: must be %q or %q", c.Dialect, DialectSQLite, DialectPostgres)
+	}
+	if c.DSN == "" {
+		return fmt.Errorf("SQL storage requires a DSN")
+	}
+	if len(c.EncryptionKey) < MinEncryptionKeyLength {
+		return fmt.Errorf("SQL storage requires an encryption key of at least %d bytes", MinEncryptionKeyLength)
+	}
+	if c.CleanupInterval < 0 {
+		return fmt.Errorf("cleanup interval must not be negative")
+	}
+	return nil
+}
+
+func (c *SQLConfig) driver() string {
+	if c.Driver != "" {
+		return c.Driver
+	}
+	return defaultDrivers[c.Dialect]
 }
 
 // DefaultConfig returns sensible defaults.
@@ -55,10 +128,109 @@ func DefaultConfig() *Config {
 	}
 }
 
+// New creates the storage backend described by cfg.
+// A nil config or an empty type selects in-memory storage.
+func New(ctx context.Context, cfg *Config) (Storage, error) {
+	if cfg == nil {
+		cfg = DefaultConfig()
+	}
+
+	switch cfg.Type {
+	case "", TypeMemory:
+		return NewMemoryStorage(), nil
+	case TypeSQL:
+		if cfg.SQL == nil {
+			return nil, fmt.Errorf("SQL storage requires SQL configuration")
+		}
+		return NewSQLStorage(ctx, cfg.SQL)
+	default:
+		return nil, fmt.Errorf("unsupported storage type: %s", cfg.Type)
+	}
+}
+
 // RunConfig is the serializable storage configuration for RunConfig.
 // This is used when the config needs to be passed across process boundaries
 // (e.g., in Kubernetes operator).
 type RunConfig struct {
-	// Type specifies the storage backend type. Defaults to "memory".
+	// Type specifies the storage backend type: "memory" (default) or "sql".
 	Type string `json:"type,omitempty" yaml:"type,omitempty"`
+
+	// SQL configures the database when Type is "sql".
+	SQL *SQLRunConfig `json:"sql,omitempty" yaml:"sql,omitempty"`
+}
+
+// SQLRunConfig is the serializable SQL storage configuration.
+// Secrets are referenced by environment variable or file rather than embedded.
+type SQLRunConfig struct {
+	// Dialect is the SQL dialect of the database: "sqlite" or "postgres".
+	Dialect string `json:"dialect" yaml:"dialect"`
+
+	// Driver overrides the database/sql driver name.
+	Driver string `json:"driver,omitempty" yaml:"driver,omitempty"`
+
+	// DSN is the data source name, e.g. a SQLite file path. Use DSNEnv for DSNs containing credentials.
+	DSN string `json:"dsn,omitempty" yaml:"dsn,omitempty"`
+
+	// DSNEnv is the name of the environment variable containing the DSN. Takes precedence over DSN.
+	DSNEnv string `json:"dsn_env,omitempty" yaml:"dsn_env,omitempty"`
+
+	// EncryptionKeyFile is the path to a file containing the key material used to
+	// encrypt upstream tokens at rest. It must contain at least 32 bytes.
+	EncryptionKeyFile string `json:"encryption_key_file" yaml:"encryption_key_file"`
+}
+
+// Validate checks the configuration without reading files or the environment.
+func (c *RunConfig) Validate() error {
+	switch Type(c.Type) {
+	case "", TypeMemory:
+		return nil
+	case TypeSQL:
+		if c.SQL == nil {
+			return fmt.Errorf("SQL storage requires SQL configuration")
+		}
+		if _, ok := defaultDrivers[Dialect(c.SQL.Dialect)]; !ok {
+			return fmt.Errorf("unsupported SQL dialect %q: must be %q or %q", c.SQL.Dialect, DialectSQLite, DialectPostgres)
+		}
+		if c.SQL.DSN == "" && c.SQL.DSNEnv == "" {
+			return fmt.Errorf("SQL storage requires dsn or dsn_env")
+		}
+		if c.SQL.EncryptionKeyFile == "" {
+			return fmt.Errorf("SQL storage requires encryption_key_file")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported storage type: %s", c.Type)
+	}
+}
+
+// ToConfig resolves the configuration, reading the DSN and encryption key it references.
+func (c *RunConfig) ToConfig() (*Config, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	if Type(c.Type) != TypeSQL {
+		return DefaultConfig(), nil
+	}
+
+	dsn := c.SQL.DSN
+	if c.SQL.DSNEnv != "" {
+		dsn = os.Getenv(c.SQL.DSNEnv)
+		if dsn == "" {
+			return nil, fmt.Errorf("environment variable %s is not set", c.SQL.DSNEnv)
+		}
+	}
+	key, err := loadEncryptionKey(c.SQL.EncryptionKeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Config{
+		Type: TypeSQL,
+		SQL: &SQLConfig{
+			Dialect:       Dialect(c.SQL.Dialect),
+			Driver:        c.SQL.Driver,
+			DSN:           dsn,
+			EncryptionKey: key,
+		},
+	}, nil
 }
@@ -0,0 +1,110 @@
+// Copyright 2025 Stacklok, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunConfig_Validate(t *testing.T) {
+	t.Parallel()
+
+	validSQL := func() *SQLRunConfig {
+		return &SQLRunConfig{Dialect: "postgres", DSNEnv: "DATABASE_URL", EncryptionKeyFile: "/etc/toolhive/key"}
+	}
+
+	tests := []struct {
+		name    string
+		config  *RunConfig
+		wantErr bool
+	}{
+		{name: "default", config: &RunConfig{}},
+		{name: "memory", config: &RunConfig{Type: "memory"}},
+		{name: "sql", config: &RunConfig{Type: "sql", SQL: validSQL()}},
+		{name: "unsupported type", config: &RunConfig{Type: "etcd"}, wantErr: true},
+		{name: "sql without config", config: &RunConfig{Type: "sql"}, wantErr: true},
+		{
+			name: "unsupported dialect",
+			config: &RunConfig{Type: "sql", SQL: func() *SQLRunConfig {
+				c := validSQL()
+				c.Dialect = "mysql"
+				return c
+			}()},
+			wantErr: true,
+		},
+		{
+			name: "no DSN",
+			config: &RunConfig{Type: "sql", SQL: func() *SQLRunConfig {
+				c := validSQL()
+				c.DSNEnv = ""
+				return c
+			}()},
+			wantErr: true,
+		},
+		{
+			name: "no encryption key",
+			config: &RunConfig{Type: "sql", SQL: func() *SQLRunConfig {
+				c := validSQL()
+				c.EncryptionKeyFile = ""
+				return c
+			}()},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := tt.config.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRunConfig_ToConfig(t *testing.T) { //nolint:paralleltest // uses t.Setenv
+	keyFile := filepath.Join(t.TempDir(), "key")
+	require.NoError(t, os.WriteFile(keyFile, []byte(strings.Repeat("k", MinEncryptionKeyLength)), 0600))
+
+	cfg, err := (&RunConfig{}).ToConfig()
+	require.NoError(t, err)
+	assert.Equal(t, TypeMemory, cfg.Type)
+
+	runConfig := &RunConfig{
+		Type: "sql",
+		SQL:  &SQLRunConfig{Dialect: "postgres", DSNEnv: "TEST_AUTHSERVER_DSN", EncryptionKeyFile: keyFile},
+	}
+	_, err = runConfig.ToConfig()
+	assert.Error(t, err, "unset DSN environment variable")
+
+	t.Setenv("TEST_AUTHSERVER_DSN", "postgres://localhost/toolhive")
+	cfg, err = runConfig.ToConfig()
+	require.NoError(t, err)
+	assert.Equal(t, TypeSQL, cfg.Type)
+	assert.Equal(t, DialectPostgres, cfg.SQL.Dialect)
+	assert.Equal(t, "pgx", cfg.SQL.driver())
+	assert.Equal(t, "postgres://localhost/toolhive", cfg.SQL.DSN)
+	assert.Len(t, cfg.SQL.EncryptionKey, MinEncryptionKeyLength)
+	require.NoError(t, cfg.SQL.Validate())
+}
@@ -0,0 +1,109 @@
+// Copyright 2025 Stacklok, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// MinEncryptionKeyLength is the minimum length of the key material used to encrypt
+// upstream tokens at rest. The AES-256 key is derived from it with SHA-256.
+const MinEncryptionKeyLength = 32
+
+// encryptedValuePrefix versions the format of encrypted values so that the
+// algorithm can be changed without a schema migration.
+const encryptedValuePrefix = "v1:"
+
+// tokenCipher encrypts token values with AES-256-GCM.
+type tokenCipher struct {
+	aead cipher.AEAD
+}
+
+// newTokenCipher creates a cipher from key material of at least MinEncryptionKeyLength bytes.
+func newTokenCipher(keyMaterial []byte) (*tokenCipher, error) {
+	if len(keyMaterial) < MinEncryptionKeyLength {
+		return nil, fmt.Errorf("encryption key must be at least %d bytes", MinEncryptionKeyLength)
+	}
+	key := sha256.Sum256(keyMaterial)
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCM cipher: %w", err)
+	}
+	return &tokenCipher{aead: aead}, nil
+}
+
+// encrypt returns the encrypted, encoded form of plaintext. Empty values stay empty
+// so that the absence of a token remains queryable.
+func (c *tokenCipher) encrypt(plaintext string) (string, error) {
+	if plaintext == "" {
+		return "", nil
+	}
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	sealed := c.aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return encryptedValuePrefix + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// decrypt reverses encrypt.
+func (c *tokenCipher) decrypt(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+	encoded, ok := strings.CutPrefix(value, encryptedValuePrefix)
+	if !ok {
+		return "", fmt.Errorf("unsupported encrypted value format")
+	}
+	sealed, err := base64.RawStdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode encrypted value: %w", err)
+	}
+	nonceSize := c.aead.NonceSize()
+	if len(sealed) < nonceSize {
+		return "", fmt.Errorf("encrypted value is too short")
+	}
+	plaintext, err := c.aead.Open(nil, sealed[:nonceSize], sealed[nonceSize:], nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt value: %w", err)
+	}
+	return string(plaintext), nil
+}
+
+// loadEncryptionKey reads encryption key material from a file.
+func loadEncryptionKey(path string) ([]byte, error) {
+	data, err := os.ReadFile(path) // #nosec G304 - path is provided by the user via config
+	if err != nil {
+		return nil, fmt.Errorf("failed to read encryption key file: %w", err)
+	}
+
+	// Trim whitespace (common in Kubernetes Secret mounts which often add trailing newlines)
+	key := []byte(strings.TrimSpace(string(data)))
+	if len(key) < MinEncryptionKeyLength {
+		return nil, fmt.Errorf("encryption key must be at least %d bytes", MinEncryptionKeyLength)
+	}
+	return key, nil
+}
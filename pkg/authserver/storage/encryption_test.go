@@ -0,0 +1,82 @@
+// Copyright 2025 Stacklok, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenCipher(t *testing.T) {
+	t.Parallel()
+
+	c, err := newTokenCipher([]byte(strings.Repeat("k", MinEncryptionKeyLength)))
+	require.NoError(t, err)
+
+	encrypted, err := c.encrypt("refresh-token")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(encrypted, encryptedValuePrefix))
+	assert.NotContains(t, encrypted, "refresh-token")
+
+	again, err := c.encrypt("refresh-token")
+	require.NoError(t, err)
+	assert.NotEqual(t, encrypted, again, "nonces must differ")
+
+	decrypted, err := c.decrypt(encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, "refresh-token", decrypted)
+
+	// Empty values stay empty
+	empty, err := c.encrypt("")
+	require.NoError(t, err)
+	assert.Empty(t, empty)
+
+	// A different key cannot decrypt the value
+	other, err := newTokenCipher([]byte(strings.Repeat("x", MinEncryptionKeyLength)))
+	require.NoError(t, err)
+	_, err = other.decrypt(encrypted)
+	assert.Error(t, err)
+
+	_, err = c.decrypt("plaintext")
+	assert.Error(t, err)
+
+	_, err = newTokenCipher([]byte("short"))
+	assert.Error(t, err)
+}
+
+func TestLoadEncryptionKey(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "key")
+	require.NoError(t, os.WriteFile(path, []byte(strings.Repeat("k", MinEncryptionKeyLength)+"\n"), 0600))
+
+	key, err := loadEncryptionKey(path)
+	require.NoError(t, err)
+	assert.Len(t, key, MinEncryptionKeyLength)
+
+	short := filepath.Join(dir, "short")
+	require.NoError(t, os.WriteFile(short, []byte("short"), 0600))
+	_, err = loadEncryptionKey(short)
+	assert.Error(t, err)
+
+	_, err = loadEncryptionKey(filepath.Join(dir, "missing"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,505 @@
+// Copyright 2025 Stacklok, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ory/fosite"
+)
+
+// entry wraps a stored value with its expiration time.
+type entry[T any] struct {
+	value     T
+	expiresAt time.Time
+}
+
+func (e entry[T]) expired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && now.After(e.expiresAt)
+}
+
+// authorizeCode is a stored authorization code session.
+// Invalidated codes are kept until they expire so that replays can be detected.
+type authorizeCode struct {
+	requester fosite.Requester
+	active    bool
+}
+
+// refreshToken is a stored refresh token session.
+type refreshToken struct {
+	requester fosite.Requester
+	active    bool
+}
+
+// MemoryStorage is an in-memory Storage implementation for single-instance deployments.
+// Entries expire according to the lifespans recorded in their sessions, falling back to
+// the Default*TTL constants, and are removed by a background cleanup goroutine.
+type MemoryStorage struct {
+	mu sync.RWMutex
+
+	clients               map[string]fosite.Client
+	clientAssertionJWTs   map[string]time.Time
+	authorizeCodes        map[string]entry[authorizeCode]
+	accessTokens          map[string]entry[fosite.Requester]
+	refreshTokens         map[string]entry[refreshToken]
+	pkceRequests          map[string]entry[fosite.Requester]
+	accessTokenRequestIDs map[string]string
+	refreshTokenRequests  map[string]string
+	pendingAuthorizations map[string]entry[*PendingAuthorization]
+	upstreamTokens        map[string]*UpstreamTokens
+
+	now       func() time.Time
+	stopCh    chan struct{}
+	closeOnce sync.Once
+}
+
+// NewMemoryStorage creates a new in-memory storage and starts its background cleanup.
+// Call Close to stop the cleanup goroutine.
+func NewMemoryStorage() *MemoryStorage {
+	s := &MemoryStorage{
+		clients:               make(map[string]fosite.Client),
+		clientAssertionJWTs:   make(map[string]time.Time),
+		authorizeCodes:        make(map[string]entry[authorizeCode]),
+		accessTokens:          make(map[string]entry[fosite.Requester]),
+		refreshTokens:         make(map[string]entry[refreshToken]),
+		pkceRequests:          make(map[string]entry[fosite.Requester]),
+		accessTokenRequestIDs: make(map[string]string),
+		refreshTokenRequests:  make(map[string]string),
+		pendingAuthorizations: make(map[string]entry[*PendingAuthorization]),
+		upstreamTokens:        make(map[string]*UpstreamTokens),
+		now:                   time.Now,
+		stopCh:                make(chan struct{}),
+	}
+	go s.cleanupLoop(DefaultCleanupInterval)
+	return s
+}
+
+// Close stops the background cleanup.
+func (s *MemoryStorage) Close() error {
+	s.closeOnce.Do(func() {
+		close(s.stopCh)
+	})
+	return nil
+}
+
+func (s *MemoryStorage) cleanupLoop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.cleanup()
+		case <-s.stopCh:
+			return
+		}
+	}
+}
+
+// cleanup removes all expired entries.
+func (s *MemoryStorage) cleanup() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	for jti, exp := range s.clientAssertionJWTs {
+		if now.After(exp) {
+			delete(s.clientAssertionJWTs, jti)
+		}
+	}
+	for code, e := range s.authorizeCodes {
+		if e.expired(now) {
+			delete(s.authorizeCodes, code)
+		}
+	}
+	for signature, e := range s.accessTokens {
+		if e.expired(now) {
+			delete(s.accessTokens, signature)
+			deleteBySignature(s.accessTokenRequestIDs, signature)
+		}
+	}
+	for signature, e := range s.refreshTokens {
+		if e.expired(now) {
+			delete(s.refreshTokens, signature)
+			deleteBySignature(s.refreshTokenRequests, signature)
+		}
+	}
+	for signature, e := range s.pkceRequests {
+		if e.expired(now) {
+			delete(s.pkceRequests, signature)
+		}
+	}
+	for state, e := range s.pendingAuthorizations {
+		if e.expired(now) {
+			delete(s.pendingAuthorizations, state)
+		}
+	}
+	for sessionID, tokens := range s.upstreamTokens {
+		// Keep tokens that can still be refreshed
+		if tokens.RefreshToken == "" && tokens.IsExpired(now) {
+			delete(s.upstreamTokens, sessionID)
+		}
+	}
+}
+
+// deleteBySignature removes the request ID mapping that points at signature.
+func deleteBySignature(requestIDs map[string]string, signature string) {
+	for requestID, sig := range requestIDs {
+		if sig == signature {
+			delete(requestIDs, requestID)
+		}
+	}
+}
+
+// expiresAt returns the expiry recorded in the requester's session for the token type,
+// or now plus the fallback TTL when the session does not record one.
+func expiresAt(req fosite.Requester, tokenType fosite.TokenType, fallback time.Duration, now time.Time) time.Time {
+	if session := req.GetSession(); session != nil {
+		if exp := session.GetExpiresAt(tokenType); !exp.IsZero() {
+			return exp
+		}
+	}
+	return now.Add(fallback)
+}
+
+// GetClient loads the client by its ID.
+func (s *MemoryStorage) GetClient(_ context.Context, id string) (fosite.Client, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	client, ok := s.clients[id]
+	if !ok {
+		return nil, fosite.ErrNotFound
+	}
+	return client, nil
+}
+
+// RegisterClient registers a new OAuth client.
+func (s *MemoryStorage) RegisterClient(_ context.Context, client fosite.Client) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.clients[client.GetID()]; ok {
+		return ErrAlreadyExists
+	}
+	s.clients[client.GetID()] = client
+	return nil
+}
+
+// ClientAssertionJWTValid returns an error if the JTI is known to have been used.
+func (s *MemoryStorage) ClientAssertionJWTValid(_ context.Context, jti string) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if exp, ok := s.clientAssertionJWTs[jti]; ok && exp.After(s.now()) {
+		return fosite.ErrJTIKnown
+	}
+	return nil
+}
+
+// SetClientAssertionJWT marks a JTI as known until the given expiry.
+func (s *MemoryStorage) SetClientAssertionJWT(_ context.Context, jti string, exp time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if known, ok := s.clientAssertionJWTs[jti]; ok && known.After(s.now()) {
+		return fosite.ErrJTIKnown
+	}
+	s.clientAssertionJWTs[jti] = exp
+	return nil
+}
+
+// CreateAuthorizeCodeSession stores the authorization request for a given authorization code.
+func (s *MemoryStorage) CreateAuthorizeCodeSession(_ context.Context, code string, req fosite.Requester) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.authorizeCodes[code] = entry[authorizeCode]{
+		value:     authorizeCode{requester: req, active: true},
+		expiresAt: expiresAt(req, fosite.AuthorizeCode, DefaultAuthCodeTTL, now),
+	}
+	return nil
+}
+
+// GetAuthorizeCodeSession retrieves the authorization request for a given code.
+// For invalidated codes the request is returned together with fosite.ErrInvalidatedAuthorizeCode
+// so that fosite can revoke the tokens issued for it.
+func (s *MemoryStorage) GetAuthorizeCodeSession(_ context.Context, code string, _ fosite.Session) (fosite.Requester, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	e, ok := s.authorizeCodes[code]
+	if !ok || e.expired(s.now()) {
+		return nil, fosite.ErrNotFound
+	}
+	if !e.value.active {
+		return e.value.requester, fosite.ErrInvalidatedAuthorizeCode
+	}
+	return e.value.requester, nil
+}
+
+// InvalidateAuthorizeCodeSession marks an authorization code as used.
+// The code is kept for DefaultInvalidatedCodeTTL to detect replays.
+func (s *MemoryStorage) InvalidateAuthorizeCodeSession(_ context.Context, code string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.authorizeCodes[code]
+	if !ok {
+		return fosite.ErrNotFound
+	}
+	e.value.active = false
+	e.expiresAt = s.now().Add(DefaultInvalidatedCodeTTL)
+	s.authorizeCodes[code] = e
+	return nil
+}
+
+// CreateAccessTokenSession stores an access token session.
+func (s *MemoryStorage) CreateAccessTokenSession(_ context.Context, signature string, req fosite.Requester) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.accessTokens[signature] = entry[fosite.Requester]{
+		value:     req,
+		expiresAt: expiresAt(req, fosite.AccessToken, DefaultAccessTokenTTL, now),
+	}
+	s.accessTokenRequestIDs[req.GetID()] = signature
+	return nil
+}
+
+// GetAccessTokenSession retrieves an access token session.
+func (s *MemoryStorage) GetAccessTokenSession(_ context.Context, signature string, _ fosite.Session) (fosite.Requester, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	e, ok := s.accessTokens[signature]
+	if !ok || e.expired(s.now()) {
+		return nil, fosite.ErrNotFound
+	}
+	return e.value, nil
+}
+
+// DeleteAccessTokenSession removes an access token session.
+func (s *MemoryStorage) DeleteAccessTokenSession(_ context.Context, signature string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.accessTokens, signature)
+	return nil
+}
+
+// CreateRefreshTokenSession stores a refresh token session.
+func (s *MemoryStorage) CreateRefreshTokenSession(
+	_ context.Context,
+	signature string,
+	_ string,
+	req fosite.Requester,
+) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.refreshTokens[signature] = entry[refreshToken]{
+		value:     refreshToken{requester: req, active: true},
+		expiresAt: expiresAt(req, fosite.RefreshToken, DefaultRefreshTokenTTL, now),
+	}
+	s.refreshTokenRequests[req.GetID()] = signature
+	return nil
+}
+
+// GetRefreshTokenSession retrieves a refresh token session.
+// For revoked tokens the request is returned together with fosite.ErrInactiveToken
+// so that fosite can detect refresh token reuse.
+func (s *MemoryStorage) GetRefreshTokenSession(_ context.Context, signature string, _ fosite.Session) (fosite.Requester, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	e, ok := s.refreshTokens[signature]
+	if !ok || e.expired(s.now()) {
+		return nil, fosite.ErrNotFound
+	}
+	if !e.value.active {
+		return e.value.requester, fosite.ErrInactiveToken
+	}
+	return e.value.requester, nil
+}
+
+// DeleteRefreshTokenSession removes a refresh token session.
+func (s *MemoryStorage) DeleteRefreshTokenSession(_ context.Context, signature string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.refreshTokens, signature)
+	return nil
+}
+
+// RotateRefreshToken revokes the refresh and access tokens of a request
+// before new ones are issued for it.
+func (s *MemoryStorage) RotateRefreshToken(ctx context.Context, requestID string, _ string) error {
+	if err := s.RevokeRefreshToken(ctx, requestID); err != nil {
+		return err
+	}
+	return s.RevokeAccessToken(ctx, requestID)
+}
+
+// RevokeRefreshToken marks the refresh token of a request as inactive.
+func (s *MemoryStorage) RevokeRefreshToken(_ context.Context, requestID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	signature, ok := s.refreshTokenRequests[requestID]
+	if !ok {
+		return nil
+	}
+	e, ok := s.refreshTokens[signature]
+	if !ok {
+		return fosite.ErrNotFound
+	}
+	e.value.active = false
+	s.refreshTokens[signature] = e
+	return nil
+}
+
+// RevokeAccessToken removes the access token of a request.
+func (s *MemoryStorage) RevokeAccessToken(_ context.Context, requestID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if signature, ok := s.accessTokenRequestIDs[requestID]; ok {
+		delete(s.accessTokens, signature)
+		delete(s.accessTokenRequestIDs, requestID)
+	}
+	return nil
+}
+
+// CreatePKCERequestSession stores the PKCE request for an authorization code.
+func (s *MemoryStorage) CreatePKCERequestSession(_ context.Context, signature string, req fosite.Requester) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pkceRequests[signature] = entry[fosite.Requester]{
+		value:     req,
+		expiresAt: s.now().Add(DefaultPKCETTL),
+	}
+	return nil
+}
+
+// GetPKCERequestSession retrieves the PKCE request for an authorization code.
+func (s *MemoryStorage) GetPKCERequestSession(_ context.Context, signature string, _ fosite.Session) (fosite.Requester, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	e, ok := s.pkceRequests[signature]
+	if !ok || e.expired(s.now()) {
+		return nil, fosite.ErrNotFound
+	}
+	return e.value, nil
+}
+
+// DeletePKCERequestSession removes the PKCE request for an authorization code.
+func (s *MemoryStorage) DeletePKCERequestSession(_ context.Context, signature string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.pkceRequests, signature)
+	return nil
+}
+
+// StorePendingAuthorization stores a pending authorization request for DefaultPendingAuthorizationTTL.
+func (s *MemoryStorage) StorePendingAuthorization(_ context.Context, state string, pending *PendingAuthorization) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	createdAt := pending.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = s.now()
+	}
+	s.pendingAuthorizations[state] = entry[*PendingAuthorization]{
+		value:     pending,
+		expiresAt: createdAt.Add(DefaultPendingAuthorizationTTL),
+	}
+	return nil
+}
+
+// LoadPendingAuthorization retrieves a pending authorization by internal state.
+func (s *MemoryStorage) LoadPendingAuthorization(_ context.Context, state string) (*PendingAuthorization, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	e, ok := s.pendingAuthorizations[state]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	if e.expired(s.now()) {
+		return nil, ErrExpired
+	}
+	return e.value, nil
+}
+
+// DeletePendingAuthorization removes a pending authorization.
+func (s *MemoryStorage) DeletePendingAuthorization(_ context.Context, state string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.pendingAuthorizations[state]; !ok {
+		return ErrNotFound
+	}
+	delete(s.pendingAuthorizations, state)
+	return nil
+}
+
+// StoreUpstreamTokens stores the upstream IDP tokens for a session.
+func (s *MemoryStorage) StoreUpstreamTokens(_ context.Context, sessionID string, tokens *UpstreamTokens) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.upstreamTokens[sessionID] = tokens
+	return nil
+}
+
+// GetUpstreamTokens retrieves the upstream IDP tokens for a session.
+// Expired tokens are returned together with ErrExpired so that callers can refresh them.
+func (s *MemoryStorage) GetUpstreamTokens(_ context.Context, sessionID string) (*UpstreamTokens, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	tokens, ok := s.upstreamTokens[sessionID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	if tokens.IsExpired(s.now()) {
+		return tokens, ErrExpired
+	}
+	return tokens, nil
+}
+
+// DeleteUpstreamTokens removes the upstream IDP tokens for a session.
+func (s *MemoryStorage) DeleteUpstreamTokens(_ context.Context, sessionID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.upstreamTokens[sessionID]; !ok {
+		return ErrNotFound
+	}
+	delete(s.upstreamTokens, sessionID)
+	return nil
+}
+
+// Ensure MemoryStorage implements Storage.
+var _ Storage = (*MemoryStorage)(nil)
@@ -0,0 +1,279 @@
+// Copyright 2025 Stacklok, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ory/fosite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestMemoryStorage(t *testing.T, now time.Time) (*MemoryStorage, *time.Time) {
+	t.Helper()
+	s := NewMemoryStorage()
+	t.Cleanup(func() { _ = s.Close() })
+
+	clock := now
+	s.now = func() time.Time { return clock }
+	return s, &clock
+}
+
+func newTestRequest(id string, expiresAt time.Time) *fosite.Request {
+	session := &fosite.DefaultSession{ExpiresAt: map[fosite.TokenType]time.Time{}}
+	if !expiresAt.IsZero() {
+		session.ExpiresAt[fosite.AccessToken] = expiresAt
+		session.ExpiresAt[fosite.RefreshToken] = expiresAt
+		session.ExpiresAt[fosite.AuthorizeCode] = expiresAt
+	}
+	req := fosite.NewRequest()
+	req.ID = id
+	req.Session = session
+	return req
+}
+
+func TestMemoryStorage_Clients(t *testing.T) {
+	t.Parallel()
+
+	s, _ := newTestMemoryStorage(t, time.Now())
+	ctx := context.Background()
+
+	_, err := s.GetClient(ctx, "client")
+	assert.ErrorIs(t, err, fosite.ErrNotFound)
+
+	client := &fosite.DefaultClient{ID: "client"}
+	require.NoError(t, s.RegisterClient(ctx, client))
+	assert.ErrorIs(t, s.RegisterClient(ctx, client), ErrAlreadyExists)
+
+	got, err := s.GetClient(ctx, "client")
+	require.NoError(t, err)
+	assert.Equal(t, client, got)
+}
+
+func TestMemoryStorage_ClientAssertionJWT(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+	s, clock := newTestMemoryStorage(t, now)
+	ctx := context.Background()
+
+	require.NoError(t, s.ClientAssertionJWTValid(ctx, "jti"))
+	require.NoError(t, s.SetClientAssertionJWT(ctx, "jti", now.Add(time.Minute)))
+	assert.ErrorIs(t, s.ClientAssertionJWTValid(ctx, "jti"), fosite.ErrJTIKnown)
+	assert.ErrorIs(t, s.SetClientAssertionJWT(ctx, "jti", now.Add(time.Minute)), fosite.ErrJTIKnown)
+
+	*clock = now.Add(2 * time.Minute)
+	assert.NoError(t, s.ClientAssertionJWTValid(ctx, "jti"))
+}
+
+func TestMemoryStorage_AuthorizeCode(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+	s, clock := newTestMemoryStorage(t, now)
+	ctx := context.Background()
+	req := newTestRequest("request-1", now.Add(DefaultAuthCodeTTL))
+
+	require.NoError(t, s.CreateAuthorizeCodeSession(ctx, "code", req))
+	got, err := s.GetAuthorizeCodeSession(ctx, "code", nil)
+	require.NoError(t, err)
+	assert.Equal(t, req, got)
+
+	// Invalidated codes still return the request so fosite can revoke issued tokens
+	require.NoError(t, s.InvalidateAuthorizeCodeSession(ctx, "code"))
+	got, err = s.GetAuthorizeCodeSession(ctx, "code", nil)
+	assert.ErrorIs(t, err, fosite.ErrInvalidatedAuthorizeCode)
+	assert.Equal(t, req, got)
+
+	// Invalidated codes are kept for replay detection, then expire
+	*clock = now.Add(DefaultInvalidatedCodeTTL - time.Minute)
+	_, err = s.GetAuthorizeCodeSession(ctx, "code", nil)
+	assert.ErrorIs(t, err, fosite.ErrInvalidatedAuthorizeCode)
+
+	*clock = now.Add(DefaultInvalidatedCodeTTL + time.Minute)
+	_, err = s.GetAuthorizeCodeSession(ctx, "code", nil)
+	assert.ErrorIs(t, err, fosite.ErrNotFound)
+
+	assert.ErrorIs(t, s.InvalidateAuthorizeCodeSession(ctx, "missing"), fosite.ErrNotFound)
+}
+
+func TestMemoryStorage_AccessTokens(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+	s, clock := newTestMemoryStorage(t, now)
+	ctx := context.Background()
+
+	require.NoError(t, s.CreateAccessTokenSession(ctx, "sig-1", newTestRequest("request-1", now.Add(time.Hour))))
+	_, err := s.GetAccessTokenSession(ctx, "sig-1", nil)
+	require.NoError(t, err)
+
+	// Access tokens expire with the session lifespan
+	*clock = now.Add(2 * time.Hour)
+	_, err = s.GetAccessTokenSession(ctx, "sig-1", nil)
+	assert.ErrorIs(t, err, fosite.ErrNotFound)
+
+	*clock = now
+	require.NoError(t, s.RevokeAccessToken(ctx, "request-1"))
+	_, err = s.GetAccessTokenSession(ctx, "sig-1", nil)
+	assert.ErrorIs(t, err, fosite.ErrNotFound)
+
+	// Sessions without a lifespan fall back to DefaultAccessTokenTTL
+	require.NoError(t, s.CreateAccessTokenSession(ctx, "sig-2", newTestRequest("request-2", time.Time{})))
+	*clock = now.Add(DefaultAccessTokenTTL - time.Minute)
+	_, err = s.GetAccessTokenSession(ctx, "sig-2", nil)
+	require.NoError(t, err)
+	*clock = now.Add(DefaultAccessTokenTTL + time.Minute)
+	_, err = s.GetAccessTokenSession(ctx, "sig-2", nil)
+	assert.ErrorIs(t, err, fosite.ErrNotFound)
+}
+
+func TestMemoryStorage_RefreshTokenRotation(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+	s, _ := newTestMemoryStorage(t, now)
+	ctx := context.Background()
+	req := newTestRequest("request-1", now.Add(DefaultRefreshTokenTTL))
+
+	require.NoError(t, s.CreateAccessTokenSession(ctx, "access-sig", req))
+	require.NoError(t, s.CreateRefreshTokenSession(ctx, "refresh-sig", "access-sig", req))
+
+	got, err := s.GetRefreshTokenSession(ctx, "refresh-sig", nil)
+	require.NoError(t, err)
+	assert.Equal(t, req, got)
+
+	require.NoError(t, s.RotateRefreshToken(ctx, "request-1", "refresh-sig"))
+
+	// The rotated refresh token is inactive and its access token is gone
+	got, err = s.GetRefreshTokenSession(ctx, "refresh-sig", nil)
+	assert.ErrorIs(t, err, fosite.ErrInactiveToken)
+	assert.Equal(t, req, got)
+	_, err = s.GetAccessTokenSession(ctx, "access-sig", nil)
+	assert.ErrorIs(t, err, fosite.ErrNotFound)
+
+	require.NoError(t, s.DeleteRefreshTokenSession(ctx, "refresh-sig"))
+	_, err = s.GetRefreshTokenSession(ctx, "refresh-sig", nil)
+	assert.ErrorIs(t, err, fosite.ErrNotFound)
+}
+
+func TestMemoryStorage_PKCE(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+	s, clock := newTestMemoryStorage(t, now)
+	ctx := context.Background()
+
+	require.NoError(t, s.CreatePKCERequestSession(ctx, "sig", newTestRequest("request-1", time.Time{})))
+	_, err := s.GetPKCERequestSession(ctx, "sig", nil)
+	require.NoError(t, err)
+
+	*clock = now.Add(DefaultPKCETTL + time.Minute)
+	_, err = s.GetPKCERequestSession(ctx, "sig", nil)
+	assert.ErrorIs(t, err, fosite.ErrNotFound)
+
+	require.NoError(t, s.DeletePKCERequestSession(ctx, "sig"))
+}
+
+func TestMemoryStorage_PendingAuthorization(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+	s, clock := newTestMemoryStorage(t, now)
+	ctx := context.Background()
+
+	_, err := s.LoadPendingAuthorization(ctx, "state")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	pending := &PendingAuthorization{ClientID: "client", InternalState: "state", CreatedAt: now}
+	require.NoError(t, s.StorePendingAuthorization(ctx, "state", pending))
+
+	got, err := s.LoadPendingAuthorization(ctx, "state")
+	require.NoError(t, err)
+	assert.Equal(t, pending, got)
+
+	*clock = now.Add(DefaultPendingAuthorizationTTL + time.Second)
+	_, err = s.LoadPendingAuthorization(ctx, "state")
+	assert.ErrorIs(t, err, ErrExpired)
+
+	require.NoError(t, s.DeletePendingAuthorization(ctx, "state"))
+	assert.ErrorIs(t, s.DeletePendingAuthorization(ctx, "state"), ErrNotFound)
+}
+
+func TestMemoryStorage_UpstreamTokens(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+	s, clock := newTestMemoryStorage(t, now)
+	ctx := context.Background()
+
+	tokens := &UpstreamTokens{AccessToken: "access", ExpiresAt: now.Add(time.Hour), Subject: "user"}
+	require.NoError(t, s.StoreUpstreamTokens(ctx, "session", tokens))
+
+	got, err := s.GetUpstreamTokens(ctx, "session")
+	require.NoError(t, err)
+	assert.Equal(t, tokens, got)
+
+	// Expired tokens are still returned so they can be refreshed
+	*clock = now.Add(2 * time.Hour)
+	got, err = s.GetUpstreamTokens(ctx, "session")
+	assert.ErrorIs(t, err, ErrExpired)
+	assert.Equal(t, tokens, got)
+
+	require.NoError(t, s.DeleteUpstreamTokens(ctx, "session"))
+	_, err = s.GetUpstreamTokens(ctx, "session")
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.ErrorIs(t, s.DeleteUpstreamTokens(ctx, "session"), ErrNotFound)
+}
+
+func TestMemoryStorage_Cleanup(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+	s, clock := newTestMemoryStorage(t, now)
+	ctx := context.Background()
+
+	require.NoError(t, s.CreateAccessTokenSession(ctx, "short", newTestRequest("request-1", now.Add(time.Minute))))
+	require.NoError(t, s.CreateAccessTokenSession(ctx, "long", newTestRequest("request-2", now.Add(time.Hour))))
+	require.NoError(t, s.StorePendingAuthorization(ctx, "state", &PendingAuthorization{CreatedAt: now}))
+	require.NoError(t, s.StoreUpstreamTokens(ctx, "expired", &UpstreamTokens{ExpiresAt: now}))
+	require.NoError(t, s.StoreUpstreamTokens(ctx, "refreshable",
+		&UpstreamTokens{ExpiresAt: now, RefreshToken: "refresh"}))
+
+	*clock = now.Add(30 * time.Minute)
+	s.cleanup()
+
+	assert.NotContains(t, s.accessTokens, "short")
+	assert.NotContains(t, s.accessTokenRequestIDs, "request-1")
+	assert.Contains(t, s.accessTokens, "long")
+	assert.NotContains(t, s.pendingAuthorizations, "state")
+	assert.NotContains(t, s.upstreamTokens, "expired")
+	assert.Contains(t, s.upstreamTokens, "refreshable")
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	s, err := New(context.Background(), nil)
+	require.NoError(t, err)
+	assert.IsType(t, &MemoryStorage{}, s)
+	require.NoError(t, s.Close())
+
+	_, err = New(context.Background(), &Config{Type: "unknown"})
+	assert.Error(t, err)
+}
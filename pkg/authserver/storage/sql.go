@@ -0,0 +1,717 @@
+// Copyright 2025 Stacklok, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+
+	// Register the database/sql drivers used by default for each dialect
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/ory/fosite"
+	_ "modernc.org/sqlite"
+
+	"github.com/stacklok/toolhive/pkg/authserver/server/registration"
+	"github.com/stacklok/toolhive/pkg/logger"
+)
+
+// SQLStorage is a Storage implementation backed by a SQLite or PostgreSQL database.
+// It survives restarts and can be shared by several authorization server replicas
+// when backed by PostgreSQL.
+//
+// The schema is migrated on startup. Expired entries are deleted by a background
+// goroutine, and upstream IDP tokens are encrypted at rest with AES-256-GCM.
+//
+// The pure-Go SQLite driver (modernc.org/sqlite) and the pgx PostgreSQL driver are
+// registered by this package; other drivers must be registered by the binary.
+type SQLStorage struct {
+	db      *sql.DB
+	dialect Dialect
+	cipher  *tokenCipher
+
+	now       func() time.Time
+	stopCh    chan struct{}
+	closeOnce sync.Once
+}
+
+// NewSQLStorage opens the configured database, migrates its schema and starts
+// the background cleanup. Call Close to stop the cleanup and close the database.
+func NewSQLStorage(ctx context.Context, cfg *SQLConfig) (*SQLStorage, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open(cfg.driver(), cfg.DSN)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	// SQLite allows a single writer; serialize access instead of failing with "database is locked"
+	if cfg.Dialect == DialectSQLite {
+		db.SetMaxOpenConns(1)
+	}
+	if err := db.PingContext(ctx); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	s, err := newSQLStorage(db, cfg.Dialect, cfg.EncryptionKey)
+	if err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	if err := migrate(ctx, db, cfg.Dialect, s.now()); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+
+	interval := cfg.CleanupInterval
+	if interval == 0 {
+		interval = DefaultCleanupInterval
+	}
+	go s.cleanupLoop(interval)
+	return s, nil
+}
+
+func newSQLStorage(db *sql.DB, dialect Dialect, encryptionKey []byte) (*SQLStorage, error) {
+	cipher, err := newTokenCipher(encryptionKey)
+	if err != nil {
+		return nil, err
+	}
+	return &SQLStorage{
+		db:      db,
+		dialect: dialect,
+		cipher:  cipher,
+		now:     time.Now,
+		stopCh:  make(chan struct{}),
+	}, nil
+}
+
+// Close stops the background cleanup and closes the database.
+func (s *SQLStorage) Close() error {
+	var err error
+	s.closeOnce.Do(func() {
+		close(s.stopCh)
+		err = s.db.Close()
+	})
+	return err
+}
+
+func (s *SQLStorage) exec(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return s.db.ExecContext(ctx, s.dialect.rebind(query), args...)
+}
+
+func (s *SQLStorage) queryRow(ctx context.Context, query string, args ...any) *sql.Row {
+	return s.db.QueryRowContext(ctx, s.dialect.rebind(query), args...)
+}
+
+func (s *SQLStorage) cleanupLoop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := s.cleanup(context.Background()); err != nil {
+				logger.Warnf("Failed to clean up expired authorization server entries: %v", err)
+			}
+		case <-s.stopCh:
+			return
+		}
+	}
+}
+
+// cleanup deletes all expired entries.
+func (s *SQLStorage) cleanup(ctx context.Context) error {
+	now := s.now().UnixMilli()
+	queries := []string{
+		`DELETE FROM oauth_client_assertion_jwts WHERE expires_at < ?`,
+		`DELETE FROM oauth_authorize_codes WHERE expires_at < ?`,
+		`DELETE FROM oauth_access_tokens WHERE expires_at < ?`,
+		`DELETE FROM oauth_refresh_tokens WHERE expires_at < ?`,
+		`DELETE FROM oauth_pkce_requests WHERE expires_at < ?`,
+		`DELETE FROM pending_authorizations WHERE expires_at < ?`,
+		// Keep tokens that can still be refreshed
+		`DELETE FROM upstream_tokens WHERE refresh_token = '' AND expires_at < ?`,
+	}
+	var errs []error
+	for _, query := range queries {
+		if _, err := s.exec(ctx, query, now); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// storedClient is the serialized form of a client.
+type storedClient struct {
+	Secret        []byte   `json:"secret,omitempty"`
+	RedirectURIs  []string `json:"redirect_uris"`
+	GrantTypes    []string `json:"grant_types"`
+	ResponseTypes []string `json:"response_types"`
+	Scopes        []string `json:"scopes"`
+	Audience      []string `json:"audience,omitempty"`
+	Public        bool     `json:"public"`
+}
+
+func marshalClient(client fosite.Client) (string, error) {
+	data, err := json.Marshal(storedClient{
+		Secret:        client.GetHashedSecret(),
+		RedirectURIs:  client.GetRedirectURIs(),
+		GrantTypes:    client.GetGrantTypes(),
+		ResponseTypes: client.GetResponseTypes(),
+		Scopes:        client.GetScopes(),
+		Audience:      client.GetAudience(),
+		Public:        client.IsPublic(),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to serialize client: %w", err)
+	}
+	return string(data), nil
+}
+
+// unmarshalClient restores a client. Public clients are restored as
+// registration.LoopbackClient, matching how they are registered.
+func unmarshalClient(id, data string) (fosite.Client, error) {
+	var stored storedClient
+	if err := json.Unmarshal([]byte(data), &stored); err != nil {
+		return nil, fmt.Errorf("failed to deserialize client: %w", err)
+	}
+	client := &fosite.DefaultClient{
+		ID:            id,
+		Secret:        stored.Secret,
+		RedirectURIs:  stored.RedirectURIs,
+		GrantTypes:    stored.GrantTypes,
+		ResponseTypes: stored.ResponseTypes,
+		Scopes:        stored.Scopes,
+		Audience:      stored.Audience,
+		Public:        stored.Public,
+	}
+	if stored.Public {
+		return registration.NewLoopbackClient(client), nil
+	}
+	return client, nil
+}
+
+// storedRequest is the serialized form of a fosite request. The client is stored by
+// ID and the session as JSON decoded into the session supplied by fosite.
+type storedRequest struct {
+	ID                string          `json:"id"`
+	RequestedAt       time.Time       `json:"requested_at"`
+	ClientID          string          `json:"client_id"`
+	RequestedScopes   []string        `json:"requested_scopes"`
+	GrantedScopes     []string        `json:"granted_scopes"`
+	RequestedAudience []string        `json:"requested_audience"`
+	GrantedAudience   []string        `json:"granted_audience"`
+	Form              url.Values      `json:"form"`
+	Session           json.RawMessage `json:"session,omitempty"`
+}
+
+func marshalRequest(req fosite.Requester) (string, error) {
+	stored := storedRequest{
+		ID:                req.GetID(),
+		RequestedAt:       req.GetRequestedAt(),
+		ClientID:          req.GetClient().GetID(),
+		RequestedScopes:   req.GetRequestedScopes(),
+		GrantedScopes:     req.GetGrantedScopes(),
+		RequestedAudience: req.GetRequestedAudience(),
+		GrantedAudience:   req.GetGrantedAudience(),
+		Form:              sanitizeForm(req.GetRequestForm()),
+	}
+	if session := req.GetSession(); session != nil {
+		data, err := json.Marshal(session)
+		if err != nil {
+			return "", fmt.Errorf("failed to serialize session: %w", err)
+		}
+		stored.Session = data
+	}
+
+	data, err := json.Marshal(stored)
+	if err != nil {
+		return "", fmt.Errorf("failed to serialize request: %w", err)
+	}
+	return string(data), nil
+}
+
+// sanitizeForm drops credentials from the request form before it is persisted.
+func sanitizeForm(form url.Values) url.Values {
+	sanitized := url.Values{}
+	for key, values := range form {
+		switch key {
+		case "client_secret", "code", "code_verifier", "refresh_token", "password":
+			continue
+		}
+		sanitized[key] = values
+	}
+	return sanitized
+}
+
+// unmarshalRequest restores a fosite request, loading its client from the database.
+func (s *SQLStorage) unmarshalRequest(ctx context.Context, data string, session fosite.Session) (fosite.Requester, error) {
+	var stored storedRequest
+	if err := json.Unmarshal([]byte(data), &stored); err != nil {
+		return nil, fmt.Errorf("failed to deserialize request: %w", err)
+	}
+	client, err := s.GetClient(ctx, stored.ClientID)
+	if err != nil {
+		return nil, err
+	}
+	return stored.toRequest(client, session)
+}
+
+// toRequest builds the fosite request. The stored session is decoded into session
+// when it is not nil; fosite passes nil when it only needs the request and client,
+// e.g. for token revocation.
+func (r *storedRequest) toRequest(client fosite.Client, session fosite.Session) (fosite.Requester, error) {
+	if session != nil && len(r.Session) > 0 {
+		if err := json.Unmarshal(r.Session, session); err != nil {
+			return nil, fmt.Errorf("failed to deserialize session: %w", err)
+		}
+	}
+
+	req := &fosite.Request{
+		ID:                r.ID,
+		RequestedAt:       r.RequestedAt,
+		Client:            client,
+		RequestedScope:    r.RequestedScopes,
+		GrantedScope:      r.GrantedScopes,
+		RequestedAudience: r.RequestedAudience,
+		GrantedAudience:   r.GrantedAudience,
+		Form:              r.Form,
+		Session:           session,
+	}
+	if req.Form == nil {
+		req.Form = url.Values{}
+	}
+	return req, nil
+}
+
+// GetClient loads the client by its ID.
+func (s *SQLStorage) GetClient(ctx context.Context, id string) (fosite.Client, error) {
+	var data string
+	err := s.queryRow(ctx, `SELECT data FROM oauth_clients WHERE id = ?`, id).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fosite.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load client: %w", err)
+	}
+	return unmarshalClient(id, data)
+}
+
+// RegisterClient registers a new OAuth client.
+func (s *SQLStorage) RegisterClient(ctx context.Context, client fosite.Client) error {
+	data, err := marshalClient(client)
+	if err != nil {
+		return err
+	}
+	result, err := s.exec(ctx,
+		`INSERT INTO oauth_clients (id, data, created_at) VALUES (?, ?, ?) ON CONFLICT (id) DO NOTHING`,
+		client.GetID(), data, s.now().UnixMilli())
+	if err != nil {
+		return fmt.Errorf("failed to register client: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return ErrAlreadyExists
+	}
+	return nil
+}
+
+// ClientAssertionJWTValid returns an error if the JTI is known to have been used.
+func (s *SQLStorage) ClientAssertionJWTValid(ctx context.Context, jti string) error {
+	var expiresAt int64
+	err := s.queryRow(ctx, `SELECT expires_at FROM oauth_client_assertion_jwts WHERE jti = ?`, jti).Scan(&expiresAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load client assertion JWT: %w", err)
+	}
+	if expiresAt > s.now().UnixMilli() {
+		return fosite.ErrJTIKnown
+	}
+	return nil
+}
+
+// SetClientAssertionJWT marks a JTI as known until the given expiry.
+func (s *SQLStorage) SetClientAssertionJWT(ctx context.Context, jti string, exp time.Time) error {
+	if err := s.ClientAssertionJWTValid(ctx, jti); err != nil {
+		return err
+	}
+	if _, err := s.exec(ctx,
+		`INSERT INTO oauth_client_assertion_jwts (jti, expires_at) VALUES (?, ?)
+		ON CONFLICT (jti) DO UPDATE SET expires_at = excluded.expires_at`,
+		jti, exp.UnixMilli()); err != nil {
+		return fmt.Errorf("failed to store client assertion JWT: %w", err)
+	}
+	return nil
+}
+
+// CreateAuthorizeCodeSession stores the authorization request for a given authorization code.
+func (s *SQLStorage) CreateAuthorizeCodeSession(ctx context.Context, code string, req fosite.Requester) error {
+	data, err := marshalRequest(req)
+	if err != nil {
+		return err
+	}
+	exp := expiresAt(req, fosite.AuthorizeCode, DefaultAuthCodeTTL, s.now())
+	if _, err := s.exec(ctx,
+		`INSERT INTO oauth_authorize_codes (signature, request_id, data, active, expires_at) VALUES (?, ?, ?, 1, ?)
+		ON CONFLICT (signature) DO UPDATE SET request_id = excluded.request_id, data = excluded.data,
+		active = excluded.active, expires_at = excluded.expires_at`,
+		code, req.GetID(), data, exp.UnixMilli()); err != nil {
+		return fmt.Errorf("failed to store authorization code: %w", err)
+	}
+	return nil
+}
+
+// GetAuthorizeCodeSession retrieves the authorization request for a given code.
+// For invalidated codes the request is returned together with fosite.ErrInvalidatedAuthorizeCode
+// so that fosite can revoke the tokens issued for it.
+func (s *SQLStorage) GetAuthorizeCodeSession(ctx context.Context, code string, session fosite.Session) (fosite.Requester, error) {
+	var data string
+	var active bool
+	err := s.queryRow(ctx,
+		`SELECT data, active FROM oauth_authorize_codes WHERE signature = ? AND expires_at >= ?`,
+		code, s.now().UnixMilli()).Scan(&data, &active)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fosite.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load authorization code: %w", err)
+	}
+
+	req, err := s.unmarshalRequest(ctx, data, session)
+	if err != nil {
+		return nil, err
+	}
+	if !active {
+		return req, fosite.ErrInvalidatedAuthorizeCode
+	}
+	return req, nil
+}
+
+// InvalidateAuthorizeCodeSession marks an authorization code as used.
+// The code is kept for DefaultInvalidatedCodeTTL to detect replays.
+func (s *SQLStorage) InvalidateAuthorizeCodeSession(ctx context.Context, code string) error {
+	result, err := s.exec(ctx,
+		`UPDATE oauth_authorize_codes SET active = 0, expires_at = ? WHERE signature = ?`,
+		s.now().Add(DefaultInvalidatedCodeTTL).UnixMilli(), code)
+	if err != nil {
+		return fmt.Errorf("failed to invalidate authorization code: %w", err)
+	}
+	return notFoundIfUnaffected(result, fosite.ErrNotFound)
+}
+
+// CreateAccessTokenSession stores an access token session.
+func (s *SQLStorage) CreateAccessTokenSession(ctx context.Context, signature string, req fosite.Requester) error {
+	data, err := marshalRequest(req)
+	if err != nil {
+		return err
+	}
+	exp := expiresAt(req, fosite.AccessToken, DefaultAccessTokenTTL, s.now())
+	if _, err := s.exec(ctx,
+		`INSERT INTO oauth_access_tokens (signature, request_id, data, expires_at) VALUES (?, ?, ?, ?)
+		ON CONFLICT (signature) DO UPDATE SET request_id = excluded.request_id, data = excluded.data,
+		expires_at = excluded.expires_at`,
+		signature, req.GetID(), data, exp.UnixMilli()); err != nil {
+		return fmt.Errorf("failed to store access token: %w", err)
+	}
+	return nil
+}
+
+// GetAccessTokenSession retrieves an access token session.
+func (s *SQLStorage) GetAccessTokenSession(
+	ctx context.Context,
+	signature string,
+	session fosite.Session,
+) (fosite.Requester, error) {
+	var data string
+	err := s.queryRow(ctx,
+		`SELECT data FROM oauth_access_tokens WHERE signature = ? AND expires_at >= ?`,
+		signature, s.now().UnixMilli()).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fosite.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load access token: %w", err)
+	}
+	return s.unmarshalRequest(ctx, data, session)
+}
+
+// DeleteAccessTokenSession removes an access token session.
+func (s *SQLStorage) DeleteAccessTokenSession(ctx context.Context, signature string) error {
+	if _, err := s.exec(ctx, `DELETE FROM oauth_access_tokens WHERE signature = ?`, signature); err != nil {
+		return fmt.Errorf("failed to delete access token: %w", err)
+	}
+	return nil
+}
+
+// CreateRefreshTokenSession stores a refresh token session.
+func (s *SQLStorage) CreateRefreshTokenSession(
+	ctx context.Context,
+	signature string,
+	accessSignature string,
+	req fosite.Requester,
+) error {
+	data, err := marshalRequest(req)
+	if err != nil {
+		return err
+	}
+	exp := expiresAt(req, fosite.RefreshToken, DefaultRefreshTokenTTL, s.now())
+	if _, err := s.exec(ctx,
+		`INSERT INTO oauth_refresh_tokens (signature, request_id, access_token_signature, data, active, expires_at)
+		VALUES (?, ?, ?, ?, 1, ?)
+		ON CONFLICT (signature) DO UPDATE SET request_id = excluded.request_id,
+		access_token_signature = excluded.access_token_signature, data = excluded.data,
+		active = excluded.active, expires_at = excluded.expires_at`,
+		signature, req.GetID(), accessSignature, data, exp.UnixMilli()); err != nil {
+		return fmt.Errorf("failed to store refresh token: %w", err)
+	}
+	return nil
+}
+
+// GetRefreshTokenSession retrieves a refresh token session.
+// For revoked tokens the request is returned together with fosite.ErrInactiveToken
+// so that fosite can detect refresh token reuse.
+func (s *SQLStorage) GetRefreshTokenSession(
+	ctx context.Context,
+	signature string,
+	session fosite.Session,
+) (fosite.Requester, error) {
+	var data string
+	var active bool
+	err := s.queryRow(ctx,
+		`SELECT data, active FROM oauth_refresh_tokens WHERE signature = ? AND expires_at >= ?`,
+		signature, s.now().UnixMilli()).Scan(&data, &active)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fosite.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load refresh token: %w", err)
+	}
+
+	req, err := s.unmarshalRequest(ctx, data, session)
+	if err != nil {
+		return nil, err
+	}
+	if !active {
+		return req, fosite.ErrInactiveToken
+	}
+	return req, nil
+}
+
+// DeleteRefreshTokenSession removes a refresh token session.
+func (s *SQLStorage) DeleteRefreshTokenSession(ctx context.Context, signature string) error {
+	if _, err := s.exec(ctx, `DELETE FROM oauth_refresh_tokens WHERE signature = ?`, signature); err != nil {
+		return fmt.Errorf("failed to delete refresh token: %w", err)
+	}
+	return nil
+}
+
+// RotateRefreshToken revokes the refresh and access tokens of a request
+// before new ones are issued for it.
+func (s *SQLStorage) RotateRefreshToken(ctx context.Context, requestID string, _ string) error {
+	if err := s.RevokeRefreshToken(ctx, requestID); err != nil {
+		return err
+	}
+	return s.RevokeAccessToken(ctx, requestID)
+}
+
+// RevokeRefreshToken marks the refresh tokens of a request as inactive.
+func (s *SQLStorage) RevokeRefreshToken(ctx context.Context, requestID string) error {
+	if _, err := s.exec(ctx, `UPDATE oauth_refresh_tokens SET active = 0 WHERE request_id = ?`, requestID); err != nil {
+		return fmt.Errorf("failed to revoke refresh token: %w", err)
+	}
+	return nil
+}
+
+// RevokeAccessToken removes the access tokens of a request.
+func (s *SQLStorage) RevokeAccessToken(ctx context.Context, requestID string) error {
+	if _, err := s.exec(ctx, `DELETE FROM oauth_access_tokens WHERE request_id = ?`, requestID); err != nil {
+		return fmt.Errorf("failed to revoke access token: %w", err)
+	}
+	return nil
+}
+
+// CreatePKCERequestSession stores the PKCE request for an authorization code.
+func (s *SQLStorage) CreatePKCERequestSession(ctx context.Context, signature string, req fosite.Requester) error {
+	data, err := marshalRequest(req)
+	if err != nil {
+		return err
+	}
+	if _, err := s.exec(ctx,
+		`INSERT INTO oauth_pkce_requests (signature, data, expires_at) VALUES (?, ?, ?)
+		ON CONFLICT (signature) DO UPDATE SET data = excluded.data, expires_at = excluded.expires_at`,
+		signature, data, s.now().Add(DefaultPKCETTL).UnixMilli()); err != nil {
+		return fmt.Errorf("failed to store PKCE request: %w", err)
+	}
+	return nil
+}
+
+// GetPKCERequestSession retrieves the PKCE request for an authorization code.
+func (s *SQLStorage) GetPKCERequestSession(
+	ctx context.Context,
+	signature string,
+	session fosite.Session,
+) (fosite.Requester, error) {
+	var data string
+	err := s.queryRow(ctx,
+		`SELECT data FROM oauth_pkce_requests WHERE signature = ? AND expires_at >= ?`,
+		signature, s.now().UnixMilli()).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fosite.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load PKCE request: %w", err)
+	}
+	return s.unmarshalRequest(ctx, data, session)
+}
+
+// DeletePKCERequestSession removes the PKCE request for an authorization code.
+func (s *SQLStorage) DeletePKCERequestSession(ctx context.Context, signature string) error {
+	if _, err := s.exec(ctx, `DELETE FROM oauth_pkce_requests WHERE signature = ?`, signature); err != nil {
+		return fmt.Errorf("failed to delete PKCE request: %w", err)
+	}
+	return nil
+}
+
+// StorePendingAuthorization stores a pending authorization request for DefaultPendingAuthorizationTTL.
+func (s *SQLStorage) StorePendingAuthorization(ctx context.Context, state string, pending *PendingAuthorization) error {
+	createdAt := pending.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = s.now()
+	}
+	data, err := json.Marshal(pending)
+	if err != nil {
+		return fmt.Errorf("failed to serialize pending authorization: %w", err)
+	}
+	if _, err := s.exec(ctx,
+		`INSERT INTO pending_authorizations (state, data, expires_at) VALUES (?, ?, ?)
+		ON CONFLICT (state) DO UPDATE SET data = excluded.data, expires_at = excluded.expires_at`,
+		state, string(data), createdAt.Add(DefaultPendingAuthorizationTTL).UnixMilli()); err != nil {
+		return fmt.Errorf("failed to store pending authorization: %w", err)
+	}
+	return nil
+}
+
+// LoadPendingAuthorization retrieves a pending authorization by internal state.
+func (s *SQLStorage) LoadPendingAuthorization(ctx context.Context, state string) (*PendingAuthorization, error) {
+	var data string
+	var expiresAt int64
+	err := s.queryRow(ctx, `SELECT data, expires_at FROM pending_authorizations WHERE state = ?`, state).
+		Scan(&data, &expiresAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load pending authorization: %w", err)
+	}
+	if s.now().UnixMilli() > expiresAt {
+		return nil, ErrExpired
+	}
+
+	var pending PendingAuthorization
+	if err := json.Unmarshal([]byte(data), &pending); err != nil {
+		return nil, fmt.Errorf("failed to deserialize pending authorization: %w", err)
+	}
+	return &pending, nil
+}
+
+// DeletePendingAuthorization removes a pending authorization.
+func (s *SQLStorage) DeletePendingAuthorization(ctx context.Context, state string) error {
+	result, err := s.exec(ctx, `DELETE FROM pending_authorizations WHERE state = ?`, state)
+	if err != nil {
+		return fmt.Errorf("failed to delete pending authorization: %w", err)
+	}
+	return notFoundIfUnaffected(result, ErrNotFound)
+}
+
+// StoreUpstreamTokens stores the upstream IDP tokens for a session. The token values are encrypted.
+func (s *SQLStorage) StoreUpstreamTokens(ctx context.Context, sessionID string, tokens *UpstreamTokens) error {
+	var encrypted [3]string
+	for i, value := range []string{tokens.AccessToken, tokens.RefreshToken, tokens.IDToken} {
+		var err error
+		if encrypted[i], err = s.cipher.encrypt(value); err != nil {
+			return fmt.Errorf("failed to encrypt upstream token: %w", err)
+		}
+	}
+	if _, err := s.exec(ctx,
+		`INSERT INTO upstream_tokens (session_id, access_token, refresh_token, id_token, subject, client_id, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (session_id) DO UPDATE SET access_token = excluded.access_token,
+		refresh_token = excluded.refresh_token, id_token = excluded.id_token, subject = excluded.subject,
+		client_id = excluded.client_id, expires_at = excluded.expires_at`,
+		sessionID, encrypted[0], encrypted[1], encrypted[2], tokens.Subject, tokens.ClientID,
+		tokens.ExpiresAt.UnixMilli()); err != nil {
+		return fmt.Errorf("failed to store upstream tokens: %w", err)
+	}
+	return nil
+}
+
+// GetUpstreamTokens retrieves the upstream IDP tokens for a session.
+// Expired tokens are returned together with ErrExpired so that callers can refresh them.
+func (s *SQLStorage) GetUpstreamTokens(ctx context.Context, sessionID string) (*UpstreamTokens, error) {
+	var encrypted [3]string
+	var expiresAt int64
+	tokens := &UpstreamTokens{}
+	err := s.queryRow(ctx,
+		`SELECT access_token, refresh_token, id_token, subject, client_id, expires_at
+		FROM upstream_tokens WHERE session_id = ?`, sessionID).
+		Scan(&encrypted[0], &encrypted[1], &encrypted[2], &tokens.Subject, &tokens.ClientID, &expiresAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load upstream tokens: %w", err)
+	}
+
+	for i, target := range []*string{&tokens.AccessToken, &tokens.RefreshToken, &tokens.IDToken} {
+		if *target, err = s.cipher.decrypt(encrypted[i]); err != nil {
+			return nil, fmt.Errorf("failed to decrypt upstream token: %w", err)
+		}
+	}
+	tokens.ExpiresAt = time.UnixMilli(expiresAt)
+
+	if tokens.IsExpired(s.now()) {
+		return tokens, ErrExpired
+	}
+	return tokens, nil
+}
+
+// DeleteUpstreamTokens removes the upstream IDP tokens for a session.
+func (s *SQLStorage) DeleteUpstreamTokens(ctx context.Context, sessionID string) error {
+	result, err := s.exec(ctx, `DELETE FROM upstream_tokens WHERE session_id = ?`, sessionID)
+	if err != nil {
+		return fmt.Errorf("failed to delete upstream tokens: %w", err)
+	}
+	return notFoundIfUnaffected(result, ErrNotFound)
+}
+
+// notFoundIfUnaffected returns notFound when a statement changed no rows.
+// Drivers that cannot report affected rows are treated as successful.
+func notFoundIfUnaffected(result sql.Result, notFound error) error {
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return notFound
+	}
+	return nil
+}
+
+// Ensure SQLStorage implements Storage.
+var _ Storage = (*SQLStorage)(nil)
@@ -0,0 +1,176 @@
+// Copyright 2025 Stacklok, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// migration is a versioned schema change. The statements of a migration are
+// applied in a single transaction.
+type migration struct {
+	version     int
+	description string
+	statements  []string
+}
+
+// migrations is the ordered list of schema changes. Append new migrations with the
+// next version; never modify a released migration.
+//
+// The schema only uses types and syntax supported by both SQLite and PostgreSQL.
+// Timestamps are stored as Unix milliseconds, booleans as integers, and structured
+// values as JSON text.
+var migrations = []migration{
+	{
+		version:     1,
+		description: "initial schema",
+		statements: []string{
+			`CREATE TABLE oauth_clients (
+				id TEXT PRIMARY KEY,
+				data TEXT NOT NULL,
+				created_at BIGINT NOT NULL
+			)`,
+			`CREATE TABLE oauth_client_assertion_jwts (
+				jti TEXT PRIMARY KEY,
+				expires_at BIGINT NOT NULL
+			)`,
+			`CREATE TABLE oauth_authorize_codes (
+				signature TEXT PRIMARY KEY,
+				request_id TEXT NOT NULL,
+				data TEXT NOT NULL,
+				active INTEGER NOT NULL,
+				expires_at BIGINT NOT NULL
+			)`,
+			`CREATE TABLE oauth_access_tokens (
+				signature TEXT PRIMARY KEY,
+				request_id TEXT NOT NULL,
+				data TEXT NOT NULL,
+				expires_at BIGINT NOT NULL
+			)`,
+			`CREATE INDEX oauth_access_tokens_request_id_idx ON oauth_access_tokens (request_id)`,
+			`CREATE TABLE oauth_refresh_tokens (
+				signature TEXT PRIMARY KEY,
+				request_id TEXT NOT NULL,
+				access_token_signature TEXT NOT NULL,
+				data TEXT NOT NULL,
+				active INTEGER NOT NULL,
+				expires_at BIGINT NOT NULL
+			)`,
+			`CREATE INDEX oauth_refresh_tokens_request_id_idx ON oauth_refresh_tokens (request_id)`,
+			`CREATE TABLE oauth_pkce_requests (
+				signature TEXT PRIMARY KEY,
+				data TEXT NOT NULL,
+				expires_at BIGINT NOT NULL
+			)`,
+			`CREATE TABLE pending_authorizations (
+				state TEXT PRIMARY KEY,
+				data TEXT NOT NULL,
+				expires_at BIGINT NOT NULL
+			)`,
+			`CREATE TABLE upstream_tokens (
+				session_id TEXT PRIMARY KEY,
+				access_token TEXT NOT NULL,
+				refresh_token TEXT NOT NULL,
+				id_token TEXT NOT NULL,
+				subject TEXT NOT NULL,
+				client_id TEXT NOT NULL,
+				expires_at BIGINT NOT NULL
+			)`,
+		},
+	},
+}
+
+// rebind rewrites the "?" placeholders of a query into the placeholder syntax of the dialect.
+func (d Dialect) rebind(query string) string {
+	if d != DialectPostgres {
+		return query
+	}
+
+	var b strings.Builder
+	b.Grow(len(query) + 8)
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			b.WriteByte('$')
+			b.WriteString(strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// migrate brings the database schema up to date. It refuses to run against a
+// schema newer than this binary supports.
+func migrate(ctx context.Context, db *sql.DB, dialect Dialect, now time.Time) error {
+	if _, err := db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INTEGER PRIMARY KEY,
+		applied_at BIGINT NOT NULL
+	)`); err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+
+	var current int
+	if err := db.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&current); err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+	latest := migrations[len(migrations)-1].version
+	if current > latest {
+		return fmt.Errorf("database schema version %d is newer than the supported version %d", current, latest)
+	}
+
+	for _, m := range migrations {
+		if m.version <= current {
+			continue
+		}
+		if err := applyMigration(ctx, db, dialect, m, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func applyMigration(ctx context.Context, db *sql.DB, dialect Dialect, m migration, now time.Time) (err error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin migration %d: %w", m.version, err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	for _, stmt := range m.statements {
+		if _, err = tx.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("migration %d (%s) failed: %w", m.version, m.description, err)
+		}
+	}
+	if _, err = tx.ExecContext(ctx,
+		dialect.rebind(`INSERT INTO schema_migrations (version, applied_at) VALUES (?, ?)`),
+		m.version, now.UnixMilli()); err != nil {
+		return fmt.Errorf("failed to record migration %d: %w", m.version, err)
+	}
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit migration %d: %w", m.version, err)
+	}
+	return nil
+}
@@ -0,0 +1,461 @@
+// Copyright 2025 Stacklok, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ory/fosite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stacklok/toolhive/pkg/authserver/server/registration"
+)
+
+func TestDialect_Rebind(t *testing.T) {
+	t.Parallel()
+
+	query := `SELECT data FROM oauth_access_tokens WHERE signature = ? AND expires_at >= ?`
+	assert.Equal(t, query, DialectSQLite.rebind(query))
+	assert.Equal(t,
+		`SELECT data FROM oauth_access_tokens WHERE signature = $1 AND expires_at >= $2`,
+		DialectPostgres.rebind(query))
+}
+
+func TestMigrations_Ordered(t *testing.T) {
+	t.Parallel()
+
+	for i, m := range migrations {
+		assert.Equal(t, i+1, m.version, "migrations must be numbered consecutively")
+		assert.NotEmpty(t, m.statements)
+	}
+}
+
+func TestClientSerialization(t *testing.T) {
+	t.Parallel()
+
+	confidential := &fosite.DefaultClient{
+		ID:            "confidential",
+		Secret:        []byte("hashed"),
+		RedirectURIs:  []string{"https://app.example.com/callback"},
+		GrantTypes:    []string{"authorization_code", "refresh_token"},
+		ResponseTypes: []string{"code"},
+		Scopes:        []string{"openid"},
+	}
+	data, err := marshalClient(confidential)
+	require.NoError(t, err)
+	got, err := unmarshalClient("confidential", data)
+	require.NoError(t, err)
+	assert.Equal(t, confidential, got)
+
+	public := registration.NewLoopbackClient(&fosite.DefaultClient{
+		ID:           "public",
+		RedirectURIs: []string{"http://127.0.0.1/callback"},
+		Public:       true,
+	})
+	data, err = marshalClient(public)
+	require.NoError(t, err)
+	got, err = unmarshalClient("public", data)
+	require.NoError(t, err)
+	assert.IsType(t, &registration.LoopbackClient{}, got)
+	assert.True(t, got.IsPublic())
+}
+
+func TestMarshalRequest(t *testing.T) {
+	t.Parallel()
+
+	expiresAt := time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+	req := newTestRequest("request-1", expiresAt)
+	req.Client = &fosite.DefaultClient{ID: "client"}
+	req.RequestedScope = fosite.Arguments{"openid"}
+	req.GrantedScope = fosite.Arguments{"openid"}
+	req.Form = url.Values{"redirect_uri": {"https://app.example.com"}, "code_verifier": {"secret"}}
+
+	data, err := marshalRequest(req)
+	require.NoError(t, err)
+	assert.NotContains(t, data, "code_verifier", "credentials must not be persisted")
+
+	var decoded storedRequest
+	require.NoError(t, json.Unmarshal([]byte(data), &decoded))
+	assert.Equal(t, "client", decoded.ClientID)
+
+	stored, err := decoded.toRequest(req.Client, &fosite.DefaultSession{})
+	require.NoError(t, err)
+	assert.Equal(t, "request-1", stored.GetID())
+	assert.Equal(t, fosite.Arguments{"openid"}, stored.GetGrantedScopes())
+	assert.Equal(t, "https://app.example.com", stored.GetRequestForm().Get("redirect_uri"))
+	assert.Equal(t, expiresAt, stored.GetSession().GetExpiresAt(fosite.AccessToken))
+
+	// fosite passes a nil session when revoking tokens
+	stored, err = decoded.toRequest(req.Client, nil)
+	require.NoError(t, err)
+	assert.Nil(t, stored.GetSession())
+}
+
+func TestNewSQLStorage_InvalidConfig(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewSQLStorage(context.Background(), &SQLConfig{Dialect: "mysql"})
+	assert.Error(t, err)
+}
+
+// testEncryptionKey is a fixed key for the SQLite-backed tests.
+var testEncryptionKey = []byte("0123456789abcdef0123456789abcdef")
+
+func newTestSQLStorage(t *testing.T, path string, now time.Time) (*SQLStorage, *time.Time) {
+	t.Helper()
+	s, err := NewSQLStorage(context.Background(), &SQLConfig{
+		Dialect:         DialectSQLite,
+		DSN:             path,
+		EncryptionKey:   testEncryptionKey,
+		CleanupInterval: time.Hour,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = s.Close() })
+
+	clock := now
+	s.now = func() time.Time { return clock }
+	return s, &clock
+}
+
+// newTestSQLStorageWithClient creates a SQLite storage in a temporary directory with the
+// client used by newTestSQLRequest registered, since stored requests reference their client.
+func newTestSQLStorageWithClient(t *testing.T, now time.Time) (*SQLStorage, *time.Time) {
+	t.Helper()
+	s, clock := newTestSQLStorage(t, filepath.Join(t.TempDir(), "authserver.db"), now)
+	require.NoError(t, s.RegisterClient(context.Background(), &fosite.DefaultClient{ID: "client"}))
+	return s, clock
+}
+
+func newTestSQLRequest(id string, expiresAt time.Time) *fosite.Request {
+	req := newTestRequest(id, expiresAt)
+	req.Client = &fosite.DefaultClient{ID: "client"}
+	req.GrantedScope = fosite.Arguments{"openid"}
+	return req
+}
+
+func TestSQLStorage_Clients(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "authserver.db")
+	s, _ := newTestSQLStorage(t, path, time.Now())
+	ctx := context.Background()
+
+	_, err := s.GetClient(ctx, "client")
+	assert.ErrorIs(t, err, fosite.ErrNotFound)
+
+	client := &fosite.DefaultClient{
+		ID:            "client",
+		Secret:        []byte("hashed"),
+		RedirectURIs:  []string{"https://app.example.com/callback"},
+		GrantTypes:    []string{"authorization_code"},
+		ResponseTypes: []string{"code"},
+		Scopes:        []string{"openid"},
+	}
+	require.NoError(t, s.RegisterClient(ctx, client))
+	assert.ErrorIs(t, s.RegisterClient(ctx, client), ErrAlreadyExists)
+
+	got, err := s.GetClient(ctx, "client")
+	require.NoError(t, err)
+	assert.Equal(t, client, got)
+
+	// Clients survive reopening the database
+	require.NoError(t, s.Close())
+	reopened, _ := newTestSQLStorage(t, path, time.Now())
+	got, err = reopened.GetClient(ctx, "client")
+	require.NoError(t, err)
+	assert.Equal(t, client, got)
+}
+
+func TestSQLStorage_ClientAssertionJWT(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+	s, clock := newTestSQLStorageWithClient(t, now)
+	ctx := context.Background()
+
+	require.NoError(t, s.ClientAssertionJWTValid(ctx, "jti"))
+	require.NoError(t, s.SetClientAssertionJWT(ctx, "jti", now.Add(time.Minute)))
+	assert.ErrorIs(t, s.ClientAssertionJWTValid(ctx, "jti"), fosite.ErrJTIKnown)
+	assert.ErrorIs(t, s.SetClientAssertionJWT(ctx, "jti", now.Add(time.Minute)), fosite.ErrJTIKnown)
+
+	*clock = now.Add(2 * time.Minute)
+	assert.NoError(t, s.ClientAssertionJWTValid(ctx, "jti"))
+	require.NoError(t, s.SetClientAssertionJWT(ctx, "jti", now.Add(time.Hour)))
+	assert.ErrorIs(t, s.ClientAssertionJWTValid(ctx, "jti"), fosite.ErrJTIKnown)
+}
+
+func TestSQLStorage_AuthorizeCode(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+	s, clock := newTestSQLStorageWithClient(t, now)
+	ctx := context.Background()
+	req := newTestSQLRequest("request-1", now.Add(DefaultAuthCodeTTL))
+
+	require.NoError(t, s.CreateAuthorizeCodeSession(ctx, "code", req))
+	got, err := s.GetAuthorizeCodeSession(ctx, "code", &fosite.DefaultSession{})
+	require.NoError(t, err)
+	assert.Equal(t, "request-1", got.GetID())
+	assert.Equal(t, "client", got.GetClient().GetID())
+	assert.Equal(t, fosite.Arguments{"openid"}, got.GetGrantedScopes())
+	assert.Equal(t, now.Add(DefaultAuthCodeTTL), got.GetSession().GetExpiresAt(fosite.AuthorizeCode))
+
+	// Invalidated codes still return the request so fosite can revoke issued tokens
+	require.NoError(t, s.InvalidateAuthorizeCodeSession(ctx, "code"))
+	got, err = s.GetAuthorizeCodeSession(ctx, "code", &fosite.DefaultSession{})
+	assert.ErrorIs(t, err, fosite.ErrInvalidatedAuthorizeCode)
+	require.NotNil(t, got)
+	assert.Equal(t, "request-1", got.GetID())
+
+	// Invalidated codes are kept for replay detection, then expire
+	*clock = now.Add(DefaultInvalidatedCodeTTL - time.Minute)
+	_, err = s.GetAuthorizeCodeSession(ctx, "code", nil)
+	assert.ErrorIs(t, err, fosite.ErrInvalidatedAuthorizeCode)
+
+	*clock = now.Add(DefaultInvalidatedCodeTTL + time.Minute)
+	_, err = s.GetAuthorizeCodeSession(ctx, "code", nil)
+	assert.ErrorIs(t, err, fosite.ErrNotFound)
+
+	assert.ErrorIs(t, s.InvalidateAuthorizeCodeSession(ctx, "missing"), fosite.ErrNotFound)
+}
+
+func TestSQLStorage_AccessTokens(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+	s, clock := newTestSQLStorageWithClient(t, now)
+	ctx := context.Background()
+
+	require.NoError(t, s.CreateAccessTokenSession(ctx, "sig-1", newTestSQLRequest("request-1", now.Add(time.Hour))))
+	got, err := s.GetAccessTokenSession(ctx, "sig-1", &fosite.DefaultSession{})
+	require.NoError(t, err)
+	assert.Equal(t, "request-1", got.GetID())
+
+	// Access tokens expire with the session lifespan
+	*clock = now.Add(2 * time.Hour)
+	_, err = s.GetAccessTokenSession(ctx, "sig-1", nil)
+	assert.ErrorIs(t, err, fosite.ErrNotFound)
+
+	*clock = now
+	require.NoError(t, s.RevokeAccessToken(ctx, "request-1"))
+	_, err = s.GetAccessTokenSession(ctx, "sig-1", nil)
+	assert.ErrorIs(t, err, fosite.ErrNotFound)
+
+	// Sessions without a lifespan fall back to DefaultAccessTokenTTL
+	require.NoError(t, s.CreateAccessTokenSession(ctx, "sig-2", newTestSQLRequest("request-2", time.Time{})))
+	*clock = now.Add(DefaultAccessTokenTTL - time.Minute)
+	_, err = s.GetAccessTokenSession(ctx, "sig-2", nil)
+	require.NoError(t, err)
+	*clock = now.Add(DefaultAccessTokenTTL + time.Minute)
+	_, err = s.GetAccessTokenSession(ctx, "sig-2", nil)
+	assert.ErrorIs(t, err, fosite.ErrNotFound)
+
+	*clock = now
+	require.NoError(t, s.DeleteAccessTokenSession(ctx, "sig-2"))
+	_, err = s.GetAccessTokenSession(ctx, "sig-2", nil)
+	assert.ErrorIs(t, err, fosite.ErrNotFound)
+}
+
+func TestSQLStorage_RefreshTokens(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+	s, clock := newTestSQLStorageWithClient(t, now)
+	ctx := context.Background()
+	req := newTestSQLRequest("request-1", now.Add(DefaultRefreshTokenTTL))
+
+	require.NoError(t, s.CreateAccessTokenSession(ctx, "access-sig", req))
+	require.NoError(t, s.CreateRefreshTokenSession(ctx, "refresh-sig", "access-sig", req))
+
+	got, err := s.GetRefreshTokenSession(ctx, "refresh-sig", &fosite.DefaultSession{})
+	require.NoError(t, err)
+	assert.Equal(t, "request-1", got.GetID())
+
+	require.NoError(t, s.RotateRefreshToken(ctx, "request-1", "refresh-sig"))
+
+	// The rotated refresh token is inactive and its access token is gone
+	got, err = s.GetRefreshTokenSession(ctx, "refresh-sig", &fosite.DefaultSession{})
+	assert.ErrorIs(t, err, fosite.ErrInactiveToken)
+	require.NotNil(t, got)
+	assert.Equal(t, "request-1", got.GetID())
+	_, err = s.GetAccessTokenSession(ctx, "access-sig", nil)
+	assert.ErrorIs(t, err, fosite.ErrNotFound)
+
+	require.NoError(t, s.DeleteRefreshTokenSession(ctx, "refresh-sig"))
+	_, err = s.GetRefreshTokenSession(ctx, "refresh-sig", nil)
+	assert.ErrorIs(t, err, fosite.ErrNotFound)
+
+	// Revoked refresh tokens are inactive, and all tokens expire with the session lifespan
+	require.NoError(t, s.CreateRefreshTokenSession(ctx, "revoked-sig", "", newTestSQLRequest("request-2", now.Add(time.Hour))))
+	require.NoError(t, s.RevokeRefreshToken(ctx, "request-2"))
+	_, err = s.GetRefreshTokenSession(ctx, "revoked-sig", nil)
+	assert.ErrorIs(t, err, fosite.ErrInactiveToken)
+
+	*clock = now.Add(2 * time.Hour)
+	_, err = s.GetRefreshTokenSession(ctx, "revoked-sig", nil)
+	assert.ErrorIs(t, err, fosite.ErrNotFound)
+}
+
+func TestSQLStorage_PKCE(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+	s, clock := newTestSQLStorageWithClient(t, now)
+	ctx := context.Background()
+
+	require.NoError(t, s.CreatePKCERequestSession(ctx, "sig", newTestSQLRequest("request-1", time.Time{})))
+	got, err := s.GetPKCERequestSession(ctx, "sig", &fosite.DefaultSession{})
+	require.NoError(t, err)
+	assert.Equal(t, "request-1", got.GetID())
+
+	*clock = now.Add(DefaultPKCETTL + time.Minute)
+	_, err = s.GetPKCERequestSession(ctx, "sig", nil)
+	assert.ErrorIs(t, err, fosite.ErrNotFound)
+
+	*clock = now
+	require.NoError(t, s.DeletePKCERequestSession(ctx, "sig"))
+	_, err = s.GetPKCERequestSession(ctx, "sig", nil)
+	assert.ErrorIs(t, err, fosite.ErrNotFound)
+}
+
+func TestSQLStorage_PendingAuthorization(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+	s, clock := newTestSQLStorageWithClient(t, now)
+	ctx := context.Background()
+
+	_, err := s.LoadPendingAuthorization(ctx, "state")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	pending := &PendingAuthorization{ClientID: "client", InternalState: "state", CreatedAt: now}
+	require.NoError(t, s.StorePendingAuthorization(ctx, "state", pending))
+
+	got, err := s.LoadPendingAuthorization(ctx, "state")
+	require.NoError(t, err)
+	assert.Equal(t, pending, got)
+
+	*clock = now.Add(DefaultPendingAuthorizationTTL + time.Second)
+	_, err = s.LoadPendingAuthorization(ctx, "state")
+	assert.ErrorIs(t, err, ErrExpired)
+
+	require.NoError(t, s.DeletePendingAuthorization(ctx, "state"))
+	assert.ErrorIs(t, s.DeletePendingAuthorization(ctx, "state"), ErrNotFound)
+}
+
+func TestSQLStorage_UpstreamTokens(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+	s, clock := newTestSQLStorageWithClient(t, now)
+	ctx := context.Background()
+
+	tokens := &UpstreamTokens{
+		AccessToken:  "access",
+		RefreshToken: "refresh",
+		IDToken:      "id",
+		ExpiresAt:    now.Add(time.Hour),
+		Subject:      "user",
+		ClientID:     "client",
+	}
+	require.NoError(t, s.StoreUpstreamTokens(ctx, "session", tokens))
+
+	got, err := s.GetUpstreamTokens(ctx, "session")
+	require.NoError(t, err)
+	assert.Equal(t, tokens.AccessToken, got.AccessToken)
+	assert.Equal(t, tokens.RefreshToken, got.RefreshToken)
+	assert.Equal(t, tokens.IDToken, got.IDToken)
+	assert.Equal(t, tokens.Subject, got.Subject)
+	assert.Equal(t, tokens.ClientID, got.ClientID)
+	assert.True(t, tokens.ExpiresAt.Equal(got.ExpiresAt))
+
+	// Token values are encrypted at rest
+	var stored string
+	require.NoError(t, s.queryRow(ctx, `SELECT access_token FROM upstream_tokens WHERE session_id = ?`, "session").
+		Scan(&stored))
+	assert.NotEqual(t, "access", stored)
+
+	// Expired tokens are still returned so they can be refreshed
+	*clock = now.Add(2 * time.Hour)
+	got, err = s.GetUpstreamTokens(ctx, "session")
+	assert.ErrorIs(t, err, ErrExpired)
+	require.NotNil(t, got)
+	assert.Equal(t, "refresh", got.RefreshToken)
+
+	require.NoError(t, s.DeleteUpstreamTokens(ctx, "session"))
+	_, err = s.GetUpstreamTokens(ctx, "session")
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.ErrorIs(t, s.DeleteUpstreamTokens(ctx, "session"), ErrNotFound)
+}
+
+func TestSQLStorage_Cleanup(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+	s, clock := newTestSQLStorageWithClient(t, now)
+	ctx := context.Background()
+
+	require.NoError(t, s.CreateAccessTokenSession(ctx, "short", newTestSQLRequest("request-1", now.Add(time.Minute))))
+	require.NoError(t, s.CreateAccessTokenSession(ctx, "long", newTestSQLRequest("request-2", now.Add(time.Hour))))
+	require.NoError(t, s.CreateRefreshTokenSession(ctx, "refresh", "short",
+		newTestSQLRequest("request-1", now.Add(time.Minute))))
+	require.NoError(t, s.CreateAuthorizeCodeSession(ctx, "code", newTestSQLRequest("request-1", now.Add(time.Minute))))
+	require.NoError(t, s.CreatePKCERequestSession(ctx, "pkce", newTestSQLRequest("request-1", time.Time{})))
+	require.NoError(t, s.SetClientAssertionJWT(ctx, "jti", now.Add(time.Minute)))
+	require.NoError(t, s.StorePendingAuthorization(ctx, "state", &PendingAuthorization{CreatedAt: now}))
+	require.NoError(t, s.StoreUpstreamTokens(ctx, "expired", &UpstreamTokens{ExpiresAt: now}))
+	require.NoError(t, s.StoreUpstreamTokens(ctx, "refreshable",
+		&UpstreamTokens{ExpiresAt: now, RefreshToken: "refresh"}))
+
+	*clock = now.Add(30 * time.Minute)
+	require.NoError(t, s.cleanup(ctx))
+
+	count := func(query string, args ...any) int {
+		var n int
+		require.NoError(t, s.queryRow(ctx, query, args...).Scan(&n))
+		return n
+	}
+	assert.Equal(t, 0, count(`SELECT COUNT(*) FROM oauth_access_tokens WHERE signature = ?`, "short"))
+	assert.Equal(t, 1, count(`SELECT COUNT(*) FROM oauth_access_tokens WHERE signature = ?`, "long"))
+	assert.Equal(t, 0, count(`SELECT COUNT(*) FROM oauth_refresh_tokens`))
+	assert.Equal(t, 0, count(`SELECT COUNT(*) FROM oauth_authorize_codes`))
+	assert.Equal(t, 0, count(`SELECT COUNT(*) FROM oauth_pkce_requests`))
+	assert.Equal(t, 0, count(`SELECT COUNT(*) FROM oauth_client_assertion_jwts`))
+	assert.Equal(t, 0, count(`SELECT COUNT(*) FROM pending_authorizations`))
+	assert.Equal(t, 0, count(`SELECT COUNT(*) FROM upstream_tokens WHERE session_id = ?`, "expired"))
+	assert.Equal(t, 1, count(`SELECT COUNT(*) FROM upstream_tokens WHERE session_id = ?`, "refreshable"))
+}
+
+func TestNew_SQL(t *testing.T) {
+	t.Parallel()
+
+	s, err := New(context.Background(), &Config{
+		Type: TypeSQL,
+		SQL: &SQLConfig{
+			Dialect:       DialectSQLite,
+			DSN:           filepath.Join(t.TempDir(), "authserver.db"),
+			EncryptionKey: testEncryptionKey,
+		},
+	})
+	require.NoError(t, err)
+	assert.IsType(t, &SQLStorage{}, s)
+	require.NoError(t, s.Close())
+}
@@ -101,6 +101,9 @@ type PendingAuthorization struct {
 	// Scopes are the OAuth scopes requested by the client.
 	Scopes []string
 
+	// Audiences are the resource servers the issued tokens are bound to (RFC 8707).
+	Audiences []string
+
 	// InternalState is our randomly generated state for correlating upstream callback.
 	InternalState string
 
@@ -0,0 +1,421 @@
+// Copyright 2025 Stacklok, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package upstream
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	josejwt "github.com/go-jose/go-jose/v4/jwt"
+	"golang.org/x/oauth2"
+)
+
+const (
+	// oidcDiscoveryPath is the OIDC discovery document path relative to the issuer.
+	oidcDiscoveryPath = "/.well-known/openid-configuration"
+
+	// defaultHTTPTimeout bounds requests to the upstream IDP.
+	defaultHTTPTimeout = 30 * time.Second
+
+	// maxResponseSize limits the size of responses read from the upstream IDP.
+	maxResponseSize = 1 << 20
+
+	// idTokenLeeway is the clock skew tolerated when validating ID token times.
+	idTokenLeeway = time.Minute
+)
+
+// supportedIDTokenAlgorithms are the signature algorithms accepted for upstream ID tokens.
+var supportedIDTokenAlgorithms = []jose.SignatureAlgorithm{
+	jose.RS256, jose.RS384, jose.RS512,
+	jose.PS256, jose.PS384, jose.PS512,
+	jose.ES256, jose.ES384, jose.ES512,
+}
+
+// ErrIDTokenMissing is returned when the upstream token response does not include an ID token.
+var ErrIDTokenMissing = errors.New("upstream token response did not include an ID token")
+
+// DefaultScopes are requested from the upstream IDP when none are configured.
+var DefaultScopes = []string{"openid", "profile", "email"}
+
+// OIDCProvider is a Provider for upstream OpenID Connect identity providers.
+// Endpoints are discovered from the issuer's OIDC discovery document.
+type OIDCProvider struct {
+	config     *Config
+	endpoints  *OIDCEndpoints
+	oauth2     *oauth2.Config
+	httpClient *http.Client
+
+	jwksMu sync.Mutex
+	jwks   *jose.JSONWebKeySet
+}
+
+// OIDCProviderOption configures an OIDCProvider.
+type OIDCProviderOption func(*OIDCProvider)
+
+// WithHTTPClient sets the HTTP client used to talk to the upstream IDP.
+func WithHTTPClient(client *http.Client) OIDCProviderOption {
+	return func(p *OIDCProvider) {
+		p.httpClient = client
+	}
+}
+
+// NewOIDCProvider creates a provider for an upstream OIDC identity provider.
+// It fetches the discovery document and verifies that it describes the configured issuer.
+func NewOIDCProvider(ctx context.Context, config *Config, opts ...OIDCProviderOption) (*OIDCProvider, error) {
+	if config == nil {
+		return nil, fmt.Errorf("upstream config is required")
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	p := &OIDCProvider{
+		config:     config,
+		httpClient: &http.Client{Timeout: defaultHTTPTimeout},
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	endpoints, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+	p.endpoints = endpoints
+
+	scopes := config.Scopes
+	if len(scopes) == 0 {
+		scopes = DefaultScopes
+	}
+	p.oauth2 = &oauth2.Config{
+		ClientID:     config.ClientID,
+		ClientSecret: config.ClientSecret,
+		RedirectURL:  config.RedirectURI,
+		Scopes:       scopes,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  endpoints.AuthorizationEndpoint,
+			TokenURL: endpoints.TokenEndpoint,
+		},
+	}
+	return p, nil
+}
+
+// discover fetches and validates the issuer's OIDC discovery document.
+func (p *OIDCProvider) discover(ctx context.Context) (*OIDCEndpoints, error) {
+	discoveryURL := strings.TrimSuffix(p.config.Issuer, "/") + oidcDiscoveryPath
+
+	var endpoints OIDCEndpoints
+	if err := p.getJSON(ctx, discoveryURL, "", &endpoints); err != nil {
+		return nil, fmt.Errorf("failed to discover upstream OIDC configuration: %w", err)
+	}
+
+	// OIDC Discovery Section 4.3: the issuer must match the one used for discovery
+	if strings.TrimSuffix(endpoints.Issuer, "/") != strings.TrimSuffix(p.config.Issuer, "/") {
+		return nil, fmt.Errorf("upstream issuer mismatch: expected %s, got %s", p.config.Issuer, endpoints.Issuer)
+	}
+	if endpoints.AuthorizationEndpoint == "" || endpoints.TokenEndpoint == "" {
+		return nil, fmt.Errorf("upstream discovery document is missing the authorization or token endpoint")
+	}
+	if endpoints.JWKSEndpoint == "" {
+		return nil, fmt.Errorf("upstream discovery document is missing jwks_uri")
+	}
+	return &endpoints, nil
+}
+
+// Name returns the provider name.
+func (*OIDCProvider) Name() string {
+	return "oidc"
+}
+
+// Endpoints returns the discovered upstream endpoints.
+func (p *OIDCProvider) Endpoints() *OIDCEndpoints {
+	return p.endpoints
+}
+
+// AuthorizationURL builds the URL to redirect the user to the upstream IDP.
+func (p *OIDCProvider) AuthorizationURL(state, codeChallenge, nonce string) (string, error) {
+	if state == "" {
+		return "", fmt.Errorf("state is required")
+	}
+
+	var opts []oauth2.AuthCodeOption
+	if codeChallenge != "" {
+		opts = append(opts,
+			oauth2.SetAuthURLParam("code_challenge", codeChallenge),
+			oauth2.SetAuthURLParam("code_challenge_method", "S256"),
+		)
+	}
+	if nonce != "" {
+		opts = append(opts, oauth2.SetAuthURLParam("nonce", nonce))
+	}
+	return p.oauth2.AuthCodeURL(state, opts...), nil
+}
+
+// ExchangeCode exchanges an authorization code for tokens with the upstream IDP.
+func (p *OIDCProvider) ExchangeCode(ctx context.Context, code, codeVerifier string) (*Tokens, error) {
+	if code == "" {
+		return nil, fmt.Errorf("authorization code is required")
+	}
+
+	var opts []oauth2.AuthCodeOption
+	if codeVerifier != "" {
+		opts = append(opts, oauth2.VerifierOption(codeVerifier))
+	}
+	token, err := p.oauth2.Exchange(p.clientContext(ctx), code, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to exchange authorization code: %w", err)
+	}
+	return tokensFromOAuth2(token), nil
+}
+
+// RefreshTokens refreshes the upstream IDP tokens.
+func (p *OIDCProvider) RefreshTokens(ctx context.Context, refreshToken string) (*Tokens, error) {
+	if refreshToken == "" {
+		return nil, fmt.Errorf("refresh token is required")
+	}
+
+	token, err := p.oauth2.TokenSource(p.clientContext(ctx), &oauth2.Token{RefreshToken: refreshToken}).Token()
+	if err != nil {
+		return nil, fmt.Errorf("failed to refresh upstream tokens: %w", err)
+	}
+
+	tokens := tokensFromOAuth2(token)
+	// Providers may omit the refresh token when it has not been rotated
+	if tokens.RefreshToken == "" {
+		tokens.RefreshToken = refreshToken
+	}
+	return tokens, nil
+}
+
+// UserInfo fetches user information from the upstream IDP.
+func (p *OIDCProvider) UserInfo(ctx context.Context, accessToken string) (*UserInfo, error) {
+	if p.endpoints.UserInfoEndpoint == "" {
+		return nil, fmt.Errorf("upstream IDP does not provide a userinfo endpoint")
+	}
+
+	var claims map[string]any
+	if err := p.getJSON(ctx, p.endpoints.UserInfoEndpoint, accessToken, &claims); err != nil {
+		return nil, fmt.Errorf("failed to fetch upstream user info: %w", err)
+	}
+
+	info := &UserInfo{Claims: claims}
+	info.Subject, _ = claims["sub"].(string)
+	info.Email, _ = claims["email"].(string)
+	info.Name, _ = claims["name"].(string)
+	if info.Subject == "" {
+		return nil, fmt.Errorf("upstream user info is missing the sub claim")
+	}
+	return info, nil
+}
+
+// UserInfoWithSubjectValidation fetches user info and validates that the subject matches.
+func (p *OIDCProvider) UserInfoWithSubjectValidation(
+	ctx context.Context,
+	accessToken string,
+	expectedSubject string,
+) (*UserInfo, error) {
+	info, err := p.UserInfo(ctx, accessToken)
+	if err != nil {
+		return nil, err
+	}
+	if info.Subject != expectedSubject {
+		return nil, ErrUserInfoSubjectMismatch
+	}
+	return info, nil
+}
+
+// ValidateIDTokenWithNonce validates an ID token issued by the upstream IDP
+// following OIDC Core Section 3.1.3.7, including the nonce check.
+func (p *OIDCProvider) ValidateIDTokenWithNonce(idToken, expectedNonce string) (*IDTokenClaims, error) {
+	return p.validateIDToken(context.Background(), idToken, expectedNonce)
+}
+
+func (p *OIDCProvider) validateIDToken(ctx context.Context, idToken, expectedNonce string) (*IDTokenClaims, error) {
+	if idToken == "" {
+		return nil, fmt.Errorf("ID token is required")
+	}
+
+	token, err := josejwt.ParseSigned(idToken, supportedIDTokenAlgorithms)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ID token: %w", err)
+	}
+	if len(token.Headers) != 1 {
+		return nil, fmt.Errorf("ID token must have exactly one signature")
+	}
+
+	key, err := p.signingKey(ctx, token.Headers[0].KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	var registered josejwt.Claims
+	var raw map[string]any
+	if err := token.Claims(key, &registered, &raw); err != nil {
+		return nil, fmt.Errorf("invalid ID token signature: %w", err)
+	}
+
+	expected := josejwt.Expected{
+		Issuer:      p.endpoints.Issuer,
+		AnyAudience: josejwt.Audience{p.config.ClientID},
+		Time:        time.Now(),
+	}
+	if err := registered.ValidateWithLeeway(expected, idTokenLeeway); err != nil {
+		return nil, fmt.Errorf("invalid ID token claims: %w", err)
+	}
+	if registered.Expiry == nil {
+		return nil, fmt.Errorf("ID token is missing the exp claim")
+	}
+
+	claims := idTokenClaimsFrom(&registered, raw)
+
+	// OIDC Core 3.1.3.7: azp is required with multiple audiences and must be our client ID
+	if len(claims.Audience) > 1 && claims.AuthorizedParty == "" {
+		return nil, fmt.Errorf("ID token with multiple audiences is missing the azp claim")
+	}
+	if claims.AuthorizedParty != "" && claims.AuthorizedParty != p.config.ClientID {
+		return nil, fmt.Errorf("ID token azp claim does not match client ID")
+	}
+	if expectedNonce != "" && claims.Nonce != expectedNonce {
+		return nil, fmt.Errorf("ID token nonce mismatch")
+	}
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("ID token is missing the sub claim")
+	}
+	return claims, nil
+}
+
+// signingKey returns the upstream JWKS key with the given key ID.
+// The JWKS is fetched again when the key is unknown to pick up key rotation.
+func (p *OIDCProvider) signingKey(ctx context.Context, keyID string) (*jose.JSONWebKey, error) {
+	p.jwksMu.Lock()
+	defer p.jwksMu.Unlock()
+
+	if key := lookupKey(p.jwks, keyID); key != nil {
+		return key, nil
+	}
+
+	var jwks jose.JSONWebKeySet
+	if err := p.getJSON(ctx, p.endpoints.JWKSEndpoint, "", &jwks); err != nil {
+		return nil, fmt.Errorf("failed to fetch upstream JWKS: %w", err)
+	}
+	p.jwks = &jwks
+
+	if key := lookupKey(p.jwks, keyID); key != nil {
+		return key, nil
+	}
+	return nil, fmt.Errorf("ID token signing key %q not found in upstream JWKS", keyID)
+}
+
+// lookupKey finds a signing key by ID. Without a key ID, the only key in the set is used.
+func lookupKey(jwks *jose.JSONWebKeySet, keyID string) *jose.JSONWebKey {
+	if jwks == nil {
+		return nil
+	}
+	if keyID == "" {
+		if len(jwks.Keys) == 1 {
+			return &jwks.Keys[0]
+		}
+		return nil
+	}
+	for i := range jwks.Keys {
+		if jwks.Keys[i].KeyID == keyID && jwks.Keys[i].Use != "enc" {
+			return &jwks.Keys[i]
+		}
+	}
+	return nil
+}
+
+// getJSON fetches url and decodes the JSON response into v.
+// A non-empty bearer token is sent in the Authorization header.
+func (p *OIDCProvider) getJSON(ctx context.Context, url, bearerToken string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if bearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+bearerToken)
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request to %s failed: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d from %s", resp.StatusCode, url)
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode response from %s: %w", url, err)
+	}
+	return nil
+}
+
+// clientContext makes x/oauth2 use the provider's HTTP client.
+func (p *OIDCProvider) clientContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, oauth2.HTTPClient, p.httpClient)
+}
+
+func tokensFromOAuth2(token *oauth2.Token) *Tokens {
+	tokens := &Tokens{
+		AccessToken:  token.AccessToken,
+		RefreshToken: token.RefreshToken,
+		ExpiresAt:    token.Expiry,
+	}
+	if idToken, ok := token.Extra("id_token").(string); ok {
+		tokens.IDToken = idToken
+	}
+	return tokens
+}
+
+func idTokenClaimsFrom(registered *josejwt.Claims, raw map[string]any) *IDTokenClaims {
+	claims := &IDTokenClaims{
+		Issuer:    registered.Issuer,
+		Subject:   registered.Subject,
+		Audience:  registered.Audience,
+		RawClaims: raw,
+	}
+	if registered.Expiry != nil {
+		claims.ExpiresAt = registered.Expiry.Time()
+	}
+	if registered.IssuedAt != nil {
+		claims.IssuedAt = registered.IssuedAt.Time()
+	}
+	claims.Nonce, _ = raw["nonce"].(string)
+	claims.AuthorizedParty, _ = raw["azp"].(string)
+	claims.Email, _ = raw["email"].(string)
+	claims.Name, _ = raw["name"].(string)
+	claims.EmailVerified, _ = raw["email_verified"].(bool)
+	if authTime, ok := raw["auth_time"].(float64); ok {
+		claims.AuthTime = time.Unix(int64(authTime), 0)
+	}
+	return claims
+}
+
+// Ensure OIDCProvider implements the provider interfaces.
+var (
+	_ Provider                 = (*OIDCProvider)(nil)
+	_ IDTokenNonceValidator    = (*OIDCProvider)(nil)
+	_ UserInfoSubjectValidator = (*OIDCProvider)(nil)
+)
@@ -0,0 +1,293 @@
+// Copyright 2025 Stacklok, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package upstream
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	josejwt "github.com/go-jose/go-jose/v4/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeIDP is a minimal OIDC provider for tests.
+type fakeIDP struct {
+	server   *httptest.Server
+	key      *rsa.PrivateKey
+	clientID string
+	issuer   string
+
+	// idTokenClaims are merged into the ID token returned by the token endpoint.
+	idTokenClaims map[string]any
+	// lastTokenForm is the form of the last token request.
+	lastTokenForm url.Values
+}
+
+func newFakeIDP(t *testing.T) *fakeIDP {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	idp := &fakeIDP{key: key, clientID: "upstream-client", idTokenClaims: map[string]any{}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, map[string]any{
+			"issuer":                 idp.issuer,
+			"authorization_endpoint": idp.issuer + "/authorize",
+			"token_endpoint":         idp.issuer + "/token",
+			"userinfo_endpoint":      idp.issuer + "/userinfo",
+			"jwks_uri":               idp.issuer + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, jose.JSONWebKeySet{Keys: []jose.JSONWebKey{
+			{Key: &key.PublicKey, KeyID: "key-1", Algorithm: string(jose.RS256), Use: "sig"},
+		}})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		idp.lastTokenForm = r.PostForm
+		writeJSON(w, map[string]any{
+			"access_token":  "upstream-access",
+			"refresh_token": "upstream-refresh",
+			"token_type":    "Bearer",
+			"expires_in":    3600,
+			"id_token":      idp.signIDToken(t, idp.idTokenClaims),
+		})
+	})
+	mux.HandleFunc("/userinfo", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer upstream-access" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		writeJSON(w, map[string]any{"sub": "user-1", "email": "user@example.com", "name": "User One"})
+	})
+
+	idp.server = httptest.NewServer(mux)
+	idp.issuer = idp.server.URL
+	t.Cleanup(idp.server.Close)
+	return idp
+}
+
+func (idp *fakeIDP) signIDToken(t *testing.T, extra map[string]any) string {
+	t.Helper()
+
+	signer, err := jose.NewSigner(
+		jose.SigningKey{Algorithm: jose.RS256, Key: idp.key},
+		(&jose.SignerOptions{}).WithHeader(jose.HeaderKey("kid"), "key-1"),
+	)
+	require.NoError(t, err)
+
+	now := time.Now()
+	claims := map[string]any{
+		"iss":   idp.issuer,
+		"sub":   "user-1",
+		"aud":   idp.clientID,
+		"exp":   now.Add(time.Hour).Unix(),
+		"iat":   now.Unix(),
+		"email": "user@example.com",
+		"name":  "User One",
+	}
+	for k, v := range extra {
+		claims[k] = v
+	}
+
+	token, err := josejwt.Signed(signer).Claims(claims).Serialize()
+	require.NoError(t, err)
+	return token
+}
+
+func (idp *fakeIDP) config() *Config {
+	return &Config{
+		Issuer:       idp.issuer,
+		ClientID:     idp.clientID,
+		ClientSecret: "upstream-secret",
+		RedirectURI:  "http://localhost:8080/oauth/callback",
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func TestNewOIDCProvider(t *testing.T) {
+	t.Parallel()
+
+	idp := newFakeIDP(t)
+	ctx := context.Background()
+
+	provider, err := NewOIDCProvider(ctx, idp.config())
+	require.NoError(t, err)
+	assert.Equal(t, "oidc", provider.Name())
+	assert.Equal(t, idp.issuer+"/token", provider.Endpoints().TokenEndpoint)
+
+	// The discovered issuer must match the configured one
+	cfg := idp.config()
+	cfg.Issuer = idp.issuer + "/other"
+	_, err = NewOIDCProvider(ctx, cfg)
+	assert.Error(t, err)
+
+	_, err = NewOIDCProvider(ctx, nil)
+	assert.Error(t, err)
+}
+
+func TestOIDCProvider_AuthorizationURL(t *testing.T) {
+	t.Parallel()
+
+	idp := newFakeIDP(t)
+	provider, err := NewOIDCProvider(context.Background(), idp.config())
+	require.NoError(t, err)
+
+	authURL, err := provider.AuthorizationURL("state-1", "challenge", "nonce-1")
+	require.NoError(t, err)
+
+	parsed, err := url.Parse(authURL)
+	require.NoError(t, err)
+	query := parsed.Query()
+	assert.Equal(t, idp.issuer+"/authorize", parsed.Scheme+"://"+parsed.Host+parsed.Path)
+	assert.Equal(t, "state-1", query.Get("state"))
+	assert.Equal(t, "challenge", query.Get("code_challenge"))
+	assert.Equal(t, "S256", query.Get("code_challenge_method"))
+	assert.Equal(t, "nonce-1", query.Get("nonce"))
+	assert.Equal(t, "upstream-client", query.Get("client_id"))
+	assert.Equal(t, "openid profile email", query.Get("scope"))
+
+	_, err = provider.AuthorizationURL("", "challenge", "nonce")
+	assert.Error(t, err)
+}
+
+func TestOIDCProvider_ExchangeCodeAndValidateIDToken(t *testing.T) {
+	t.Parallel()
+
+	idp := newFakeIDP(t)
+	idp.idTokenClaims["nonce"] = "nonce-1"
+	provider, err := NewOIDCProvider(context.Background(), idp.config())
+	require.NoError(t, err)
+
+	tokens, err := provider.ExchangeCode(context.Background(), "code-1", "verifier-1")
+	require.NoError(t, err)
+	assert.Equal(t, "upstream-access", tokens.AccessToken)
+	assert.Equal(t, "upstream-refresh", tokens.RefreshToken)
+	assert.NotEmpty(t, tokens.IDToken)
+	assert.False(t, tokens.IsExpired())
+	assert.Equal(t, "verifier-1", idp.lastTokenForm.Get("code_verifier"))
+	assert.Equal(t, "code-1", idp.lastTokenForm.Get("code"))
+
+	claims, err := provider.ValidateIDTokenWithNonce(tokens.IDToken, "nonce-1")
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", claims.Subject)
+	assert.Equal(t, "user@example.com", claims.Email)
+	assert.Equal(t, "User One", claims.Name)
+
+	_, err = provider.ValidateIDTokenWithNonce(tokens.IDToken, "other-nonce")
+	assert.Error(t, err)
+}
+
+func TestOIDCProvider_ValidateIDToken_Rejects(t *testing.T) {
+	t.Parallel()
+
+	idp := newFakeIDP(t)
+	provider, err := NewOIDCProvider(context.Background(), idp.config())
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		claims map[string]any
+	}{
+		{name: "wrong issuer", claims: map[string]any{"iss": "https://evil.example.com"}},
+		{name: "wrong audience", claims: map[string]any{"aud": "other-client"}},
+		{name: "expired", claims: map[string]any{"exp": time.Now().Add(-time.Hour).Unix()}},
+		{name: "wrong azp", claims: map[string]any{"azp": "other-client"}},
+		{name: "multiple audiences without azp", claims: map[string]any{"aud": []string{"upstream-client", "other"}}},
+		{name: "missing subject", claims: map[string]any{"sub": ""}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := provider.ValidateIDTokenWithNonce(idp.signIDToken(t, tt.claims), "")
+			assert.Error(t, err)
+		})
+	}
+
+	// A token signed with a key that is not in the JWKS is rejected
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	signer, err := jose.NewSigner(
+		jose.SigningKey{Algorithm: jose.RS256, Key: otherKey},
+		(&jose.SignerOptions{}).WithHeader(jose.HeaderKey("kid"), "key-1"),
+	)
+	require.NoError(t, err)
+	forged, err := josejwt.Signed(signer).Claims(josejwt.Claims{
+		Issuer:   idp.issuer,
+		Subject:  "user-1",
+		Audience: josejwt.Audience{idp.clientID},
+		Expiry:   josejwt.NewNumericDate(time.Now().Add(time.Hour)),
+	}).Serialize()
+	require.NoError(t, err)
+	_, err = provider.ValidateIDTokenWithNonce(forged, "")
+	assert.Error(t, err)
+}
+
+func TestOIDCProvider_RefreshTokens(t *testing.T) {
+	t.Parallel()
+
+	idp := newFakeIDP(t)
+	provider, err := NewOIDCProvider(context.Background(), idp.config())
+	require.NoError(t, err)
+
+	tokens, err := provider.RefreshTokens(context.Background(), "old-refresh")
+	require.NoError(t, err)
+	assert.Equal(t, "upstream-access", tokens.AccessToken)
+	assert.Equal(t, "refresh_token", idp.lastTokenForm.Get("grant_type"))
+	assert.Equal(t, "old-refresh", idp.lastTokenForm.Get("refresh_token"))
+
+	_, err = provider.RefreshTokens(context.Background(), "")
+	assert.Error(t, err)
+}
+
+func TestOIDCProvider_UserInfo(t *testing.T) {
+	t.Parallel()
+
+	idp := newFakeIDP(t)
+	provider, err := NewOIDCProvider(context.Background(), idp.config())
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	info, err := provider.UserInfo(ctx, "upstream-access")
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", info.Subject)
+	assert.Equal(t, "user@example.com", info.Email)
+
+	_, err = provider.UserInfoWithSubjectValidation(ctx, "upstream-access", "user-1")
+	require.NoError(t, err)
+
+	_, err = provider.UserInfoWithSubjectValidation(ctx, "upstream-access", "user-2")
+	assert.ErrorIs(t, err, ErrUserInfoSubjectMismatch)
+
+	_, err = provider.UserInfo(ctx, "invalid")
+	assert.Error(t, err)
+}
@@ -143,15 +143,11 @@ func NewCedarAuthorizer(options ConfigOptions) (authorizers.Authorizer, error) {
 		return nil, ErrNoPolicies
 	}
 
-	for i, policyStr := range options.Policies {
-		var policy cedar.Policy
-		if err := policy.UnmarshalCedar([]byte(policyStr)); err != nil {
-			return nil, fmt.Errorf("failed to parse policy %d: %w", i, err)
-		}
-
-		policyID := cedar.PolicyID(fmt.Sprintf("policy%d", i))
-		authorizer.policySet.Add(policyID, &policy)
+	policySet, err := newPolicySet(options.Policies)
+	if err != nil {
+		return nil, err
 	}
+	authorizer.policySet = policySet
 
 	// Load entities if provided
 	if options.EntitiesJSON != "" {
@@ -172,20 +168,34 @@ func (a *Authorizer) UpdatePolicies(policies []string) error {
 		return ErrNoPolicies
 	}
 
-	newPolicySet := cedar.NewPolicySet()
+	newPolicySet, err := newPolicySet(policies)
+	if err != nil {
+		return err
+	}
 
+	a.policySet = newPolicySet
+	return nil
+}
+
+// newPolicySet parses Cedar policies into a policy set. Policies are identified by their
+// @id annotation, or by their index (policy0, policy1...) when they have none.
+func newPolicySet(policies []string) (*cedar.PolicySet, error) {
+	policySet := cedar.NewPolicySet()
 	for i, policyStr := range policies {
 		var policy cedar.Policy
 		if err := policy.UnmarshalCedar([]byte(policyStr)); err != nil {
-			return fmt.Errorf("failed to parse policy %d: %w", i, err)
+			return nil, fmt.Errorf("failed to parse policy %d: %w", i, err)
 		}
 
 		policyID := cedar.PolicyID(fmt.Sprintf("policy%d", i))
-		newPolicySet.Add(policyID, &policy)
+		if id, ok := policy.Annotations()["id"]; ok && id != "" {
+			policyID = cedar.PolicyID(id)
+		}
+		if !policySet.Add(policyID, &policy) {
+			return nil, fmt.Errorf("failed to parse policy %d: duplicate policy ID %q", i, policyID)
+		}
 	}
-
-	a.policySet = newPolicySet
-	return nil
+	return policySet, nil
 }
 
 // UpdateEntities updates the Cedar entities.
@@ -245,35 +255,49 @@ func (a *Authorizer) IsAuthorized(
 	contextMap map[string]interface{},
 	entities ...cedar.EntityMap,
 ) (bool, error) {
+	decision, err := a.decide(principal, action, resource, contextMap, entities...)
+	if err != nil {
+		return false, err
+	}
+	return decision.Allowed, nil
+}
+
+// decide checks if a request is authorized like IsAuthorized, and returns the IDs of the
+// policies that determined the decision.
+func (a *Authorizer) decide(
+	principal, action, resource string,
+	contextMap map[string]interface{},
+	entities ...cedar.EntityMap,
+) (*authorizers.Decision, error) {
 	a.mu.RLock()
 	defer a.mu.RUnlock()
 
 	if principal == "" {
-		return false, ErrMissingPrincipal
+		return nil, ErrMissingPrincipal
 	}
 
 	if action == "" {
-		return false, ErrMissingAction
+		return nil, ErrMissingAction
 	}
 
 	if resource == "" {
-		return false, ErrMissingResource
+		return nil, ErrMissingResource
 	}
 
 	// Parse principal, action, and resource
 	principalType, principalID, err := parseCedarEntityID(principal)
 	if err != nil {
-		return false, err
+		return nil, err
 	}
 
 	actionType, actionID, err := parseCedarEntityID(action)
 	if err != nil {
-		return false, err
+		return nil, err
 	}
 
 	resourceType, resourceID, err := parseCedarEntityID(resource)
 	if err != nil {
-		return false, err
+		return nil, err
 	}
 
 	// Create context record
@@ -318,9 +342,15 @@ func (a *Authorizer) IsAuthorized(
 	// Cedar's Authorize returns a Decision and a Diagnostic
 	// Check if the Diagnostic contains any errors
 	if len(diagnostic.Errors) > 0 {
-		return false, fmt.Errorf("authorization error: %v", diagnostic.Errors)
+		return nil, fmt.Errorf("authorization error: %v", diagnostic.Errors)
+	}
+
+	// The reasons are the permit policies of allowed requests, and the forbid policies of denied ones
+	policies := make([]string, 0, len(diagnostic.Reasons))
+	for _, reason := range diagnostic.Reasons {
+		policies = append(policies, string(reason.PolicyID))
 	}
-	return decision == cedar.Allow, nil
+	return &authorizers.Decision{Allowed: decision == cedar.Allow, Policies: policies}, nil
 }
 
 // extractClientIDFromClaims extracts the client ID from JWT claims.
@@ -390,7 +420,7 @@ func (a *Authorizer) authorizeToolCall(
 	clientID, toolName string,
 	claimsMap map[string]interface{},
 	attrsMap map[string]interface{},
-) (bool, error) {
+) (*authorizers.Decision, error) {
 	// Extract principal from client ID
 	principal := fmt.Sprintf("Client::%s", clientID)
 
@@ -410,13 +440,13 @@ func (a *Authorizer) authorizeToolCall(
 	// Create Cedar entities
 	entities, err := a.entityFactory.CreateEntitiesForRequest(principal, action, resource, claimsMap, attributes)
 	if err != nil {
-		return false, fmt.Errorf("failed to create Cedar entities: %w", err)
+		return nil, fmt.Errorf("failed to create Cedar entities: %w", err)
 	}
 
 	contextMap := mergeContexts(claimsMap, attrsMap)
 
 	// Check authorization with entities
-	return a.IsAuthorized(principal, action, resource, contextMap, entities)
+	return a.decide(principal, action, resource, contextMap, entities)
 }
 
 // authorizePromptGet authorizes a prompt get operation.
@@ -426,7 +456,7 @@ func (a *Authorizer) authorizePromptGet(
 	clientID, promptName string,
 	claimsMap map[string]interface{},
 	attrsMap map[string]interface{},
-) (bool, error) {
+) (*authorizers.Decision, error) {
 	// Extract principal from client ID
 	principal := fmt.Sprintf("Client::%s", clientID)
 
@@ -446,13 +476,13 @@ func (a *Authorizer) authorizePromptGet(
 	// Create Cedar entities
 	entities, err := a.entityFactory.CreateEntitiesForRequest(principal, action, resource, claimsMap, attributes)
 	if err != nil {
-		return false, fmt.Errorf("failed to create Cedar entities: %w", err)
+		return nil, fmt.Errorf("failed to create Cedar entities: %w", err)
 	}
 
 	contextMap := mergeContexts(claimsMap, attrsMap)
 
 	// Check authorization with entities
-	return a.IsAuthorized(principal, action, resource, contextMap, entities)
+	return a.decide(principal, action, resource, contextMap, entities)
 }
 
 // authorizeResourceRead authorizes a resource read operation.
@@ -462,7 +492,7 @@ func (a *Authorizer) authorizeResourceRead(
 	clientID, resourceURI string,
 	claimsMap map[string]interface{},
 	attrsMap map[string]interface{},
-) (bool, error) {
+) (*authorizers.Decision, error) {
 	// Extract principal from client ID
 	principal := fmt.Sprintf("Client::%s", clientID)
 
@@ -484,13 +514,13 @@ func (a *Authorizer) authorizeResourceRead(
 	// Create Cedar entities
 	entities, err := a.entityFactory.CreateEntitiesForRequest(principal, action, resource, claimsMap, attributes)
 	if err != nil {
-		return false, fmt.Errorf("failed to create Cedar entities: %w", err)
+		return nil, fmt.Errorf("failed to create Cedar entities: %w", err)
 	}
 
 	contextMap := mergeContexts(claimsMap, attrsMap)
 
 	// Check authorization with entities
-	return a.IsAuthorized(principal, action, resource, contextMap, entities)
+	return a.decide(principal, action, resource, contextMap, entities)
 }
 
 // authorizeFeatureList authorizes a list operation for a feature.
@@ -501,7 +531,7 @@ func (a *Authorizer) authorizeFeatureList(
 	feature authorizers.MCPFeature,
 	claimsMap map[string]interface{},
 	attrsMap map[string]interface{},
-) (bool, error) {
+) (*authorizers.Decision, error) {
 	// Extract principal from client ID
 	principal := fmt.Sprintf("Client::%s", clientID)
 
@@ -521,13 +551,13 @@ func (a *Authorizer) authorizeFeatureList(
 	// Create Cedar entities
 	entities, err := a.entityFactory.CreateEntitiesForRequest(principal, action, resource, claimsMap, attributes)
 	if err != nil {
-		return false, fmt.Errorf("failed to create Cedar entities: %w", err)
+		return nil, fmt.Errorf("failed to create Cedar entities: %w", err)
 	}
 
 	contextMap := mergeContexts(claimsMap, attrsMap)
 
 	// Check authorization with entities
-	return a.IsAuthorized(principal, action, resource, contextMap, entities)
+	return a.decide(principal, action, resource, contextMap, entities)
 }
 
 // parseCedarEntityID parses a Cedar entity ID in the format "Type::ID".
@@ -573,17 +603,33 @@ func (a *Authorizer) AuthorizeWithJWTClaims(
 	resourceID string,
 	arguments map[string]interface{},
 ) (bool, error) {
+	decision, err := a.Decide(ctx, feature, operation, resourceID, arguments)
+	if err != nil {
+		return false, err
+	}
+	return decision.Allowed, nil
+}
+
+// Decide authorizes an MCP operation like AuthorizeWithJWTClaims, and returns the decision with
+// the IDs of the policies that determined it.
+func (a *Authorizer) Decide(
+	ctx context.Context,
+	feature authorizers.MCPFeature,
+	operation authorizers.MCPOperation,
+	resourceID string,
+	arguments map[string]interface{},
+) (*authorizers.Decision, error) {
 	// Extract Identity from the context
 	identity, ok := auth.IdentityFromContext(ctx)
 	if !ok {
-		return false, ErrMissingPrincipal
+		return nil, ErrMissingPrincipal
 	}
 
 	// Extract client ID from Identity claims
 	claims := jwt.MapClaims(identity.Claims)
 	clientID, ok := extractClientIDFromClaims(claims)
 	if !ok {
-		return false, ErrMissingPrincipal
+		return nil, ErrMissingPrincipal
 	}
 
 	// Preprocess claims and arguments
@@ -609,6 +655,6 @@ func (a *Authorizer) AuthorizeWithJWTClaims(
 		return a.authorizeFeatureList(clientID, feature, processedClaims, processedArgs)
 
 	default:
-		return false, fmt.Errorf("unsupported feature/operation combination: %s/%s", feature, operation)
+		return nil, fmt.Errorf("unsupported feature/operation combination: %s/%s", feature, operation)
 	}
 }
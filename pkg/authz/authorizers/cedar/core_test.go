@@ -952,3 +952,85 @@ func TestIsAuthorizedWithEntities(t *testing.T) {
 	assert.NoError(t, err)
 	assert.True(t, authorized)
 }
+
+// TestDecide tests that decisions report the policies that determined them.
+func TestDecide(t *testing.T) {
+	t.Parallel()
+
+	authorizer, err := NewCedarAuthorizer(ConfigOptions{
+		Policies: []string{
+			`@id("allow-weather") permit(principal, action == Action::"call_tool", resource == Tool::"weather");`,
+			`@id("deny-guests") forbid(principal, action, resource) when { context.claim_role == "guest" };`,
+			`permit(principal, action == Action::"get_prompt", resource);`,
+		},
+		EntitiesJSON: `[]`,
+	})
+	require.NoError(t, err)
+	cedarAuthorizer, ok := authorizer.(*Authorizer)
+	require.True(t, ok)
+
+	testCases := []struct {
+		name       string
+		role       string
+		feature    authorizers.MCPFeature
+		operation  authorizers.MCPOperation
+		resourceID string
+		expected   *authorizers.Decision
+	}{
+		{
+			name:       "Permitted by annotated policy",
+			role:       "user",
+			feature:    authorizers.MCPFeatureTool,
+			operation:  authorizers.MCPOperationCall,
+			resourceID: "weather",
+			expected:   &authorizers.Decision{Allowed: true, Policies: []string{"allow-weather"}},
+		},
+		{
+			name:       "Forbidden by annotated policy",
+			role:       "guest",
+			feature:    authorizers.MCPFeatureTool,
+			operation:  authorizers.MCPOperationCall,
+			resourceID: "weather",
+			expected:   &authorizers.Decision{Allowed: false, Policies: []string{"deny-guests"}},
+		},
+		{
+			name:       "Permitted by policy without ID",
+			role:       "user",
+			feature:    authorizers.MCPFeaturePrompt,
+			operation:  authorizers.MCPOperationGet,
+			resourceID: "greeting",
+			expected:   &authorizers.Decision{Allowed: true, Policies: []string{"policy2"}},
+		},
+		{
+			name:       "Denied by default",
+			role:       "user",
+			feature:    authorizers.MCPFeatureTool,
+			operation:  authorizers.MCPOperationCall,
+			resourceID: "fetch",
+			expected:   &authorizers.Decision{Allowed: false},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			identity := &auth.Identity{Subject: "user123", Claims: jwt.MapClaims{"sub": "user123", "role": tc.role}}
+			ctx := auth.WithIdentity(context.Background(), identity)
+
+			decision, err := cedarAuthorizer.Decide(ctx, tc.feature, tc.operation, tc.resourceID, nil)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected.Allowed, decision.Allowed)
+			assert.ElementsMatch(t, tc.expected.Policies, decision.Policies)
+		})
+	}
+
+	// Policy IDs must be unique
+	_, err = NewCedarAuthorizer(ConfigOptions{
+		Policies: []string{
+			`@id("allow") permit(principal, action, resource);`,
+			`@id("allow") forbid(principal, action, resource);`,
+		},
+		EntitiesJSON: `[]`,
+	})
+	assert.ErrorContains(t, err, `duplicate policy ID "allow"`)
+}
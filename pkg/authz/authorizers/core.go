@@ -51,3 +51,44 @@ type Authorizer interface {
 		arguments map[string]interface{},
 	) (bool, error)
 }
+
+// Decision is the result of an authorization check.
+type Decision struct {
+	// Allowed is true when the request is authorized.
+	Allowed bool
+	// Policies are the IDs of the policies that determined the decision.
+	Policies []string
+}
+
+// DecisionAuthorizer is implemented by authorizers that report the policies
+// determining their decisions, which are included in decision logs.
+type DecisionAuthorizer interface {
+	Authorizer
+	Decide(
+		ctx context.Context,
+		feature MCPFeature,
+		operation MCPOperation,
+		resourceID string,
+		arguments map[string]interface{},
+	) (*Decision, error)
+}
+
+// Decide authorizes an MCP operation and returns the decision. The policies of the
+// decision are only reported by authorizers implementing DecisionAuthorizer.
+func Decide(
+	ctx context.Context,
+	a Authorizer,
+	feature MCPFeature,
+	operation MCPOperation,
+	resourceID string,
+	arguments map[string]interface{},
+) (*Decision, error) {
+	if da, ok := a.(DecisionAuthorizer); ok {
+		return da.Decide(ctx, feature, operation, resourceID, arguments)
+	}
+	allowed, err := a.AuthorizeWithJWTClaims(ctx, feature, operation, resourceID, arguments)
+	if err != nil {
+		return nil, err
+	}
+	return &Decision{Allowed: allowed}, nil
+}
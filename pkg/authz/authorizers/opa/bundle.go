@@ -0,0 +1,187 @@
+package opa
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/open-policy-agent/opa/v1/ast"
+)
+
+// maxBundleSize is the maximum size of the uncompressed files of a bundle
+const maxBundleSize = 64 << 20
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	wasmMagic = []byte{0x00, 'a', 's', 'm'}
+)
+
+// errWasmPolicy is returned for policies only available compiled to Wasm, which are not supported
+var errWasmPolicy = errors.New("policies compiled to WebAssembly are not supported: " +
+	"use the Rego modules of the policy, or a bundle built without \"-t wasm\"")
+
+// bundle is a policy as Rego modules, with its data document
+type bundle struct {
+	// modules are the Rego modules by file name
+	modules     map[string]string
+	regoVersion ast.RegoVersion
+	data        map[string]any
+	// entrypoint is the entrypoint declared in the manifest, when the bundle has a single one.
+	// Manifests only declare the entrypoints of bundles built with "opa build -t wasm".
+	entrypoint string
+}
+
+// bundleManifest is the subset of the .manifest file of OPA bundles used by the authorizer
+type bundleManifest struct {
+	Wasm []struct {
+		Entrypoint string `json:"entrypoint"`
+		Module     string `json:"module"`
+	} `json:"wasm"`
+	// RegoVersion is the version of the Rego syntax of the modules, 1 by default
+	RegoVersion *int `json:"rego_version"`
+}
+
+// loadBundle loads a policy from an OPA bundle or from a Rego module. Bundles built with
+// "opa build -t wasm" are compiled from their Rego modules, and their Wasm policy is ignored.
+// The data.json files of the bundle are merged into the data document, at the path of their
+// directory.
+func loadBundle(content []byte) (*bundle, error) {
+	switch {
+	case bytes.HasPrefix(content, wasmMagic):
+		return nil, errWasmPolicy
+	case bytes.HasPrefix(content, gzipMagic):
+		return loadBundleArchive(content)
+	case utf8.Valid(content):
+		return &bundle{modules: map[string]string{"policy.rego": string(content)}, regoVersion: ast.RegoV1}, nil
+	default:
+		return nil, errors.New("bundle must be a gzipped tarball or a Rego module")
+	}
+}
+
+func loadBundleArchive(content []byte) (*bundle, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(content))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bundle: %w", err)
+	}
+	defer func() { _ = gz.Close() }()
+
+	b := &bundle{data: map[string]any{}, regoVersion: ast.RegoV1}
+	hasWasm := false
+	regoModules := map[string]string{}
+	var manifest *bundleManifest
+	remaining := int64(maxBundleSize)
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read bundle: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		name := path.Clean("/" + header.Name)
+		if path.Base(name) != "data.json" && path.Ext(name) != ".wasm" && path.Ext(name) != ".rego" && name != "/.manifest" {
+			continue
+		}
+		file, err := io.ReadAll(io.LimitReader(tr, remaining+1))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s from bundle: %w", name, err)
+		}
+		if remaining -= int64(len(file)); remaining < 0 {
+			return nil, fmt.Errorf("bundle exceeds the maximum size of %d bytes", maxBundleSize)
+		}
+
+		switch {
+		case name == "/.manifest":
+			manifest = &bundleManifest{}
+			if err := json.Unmarshal(file, manifest); err != nil {
+				return nil, fmt.Errorf("failed to parse bundle manifest: %w", err)
+			}
+		case path.Ext(name) == ".wasm":
+			hasWasm = true
+		case path.Ext(name) == ".rego":
+			regoModules[name] = string(file)
+		default:
+			var data any
+			if err := json.Unmarshal(file, &data); err != nil {
+				return nil, fmt.Errorf("failed to parse %s from bundle: %w", name, err)
+			}
+			if err := mergeData(b.data, strings.Split(strings.Trim(path.Dir(name), "/"), "/"), data); err != nil {
+				return nil, fmt.Errorf("failed to merge %s from bundle: %w", name, err)
+			}
+		}
+	}
+
+	if manifest != nil && manifest.RegoVersion != nil && *manifest.RegoVersion == 0 {
+		b.regoVersion = ast.RegoV0
+	}
+	switch {
+	case len(regoModules) > 0:
+		b.modules = regoModules
+	case hasWasm:
+		return nil, errWasmPolicy
+	default:
+		return nil, errors.New("bundle has no policy: it must hold Rego modules")
+	}
+
+	if manifest != nil && len(manifest.Wasm) == 1 {
+		b.entrypoint = manifest.Wasm[0].Entrypoint
+	}
+	return b, nil
+}
+
+// mergeData merges a value into the data document at the given path
+func mergeData(data map[string]any, keys []string, value any) error {
+	if len(keys) > 0 && keys[0] == "" {
+		keys = keys[1:]
+	}
+	if len(keys) == 0 {
+		object, ok := value.(map[string]any)
+		if !ok {
+			return errors.New("data at the root of the bundle must be an object")
+		}
+		for key, v := range object {
+			if err := mergeData(data, []string{key}, v); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	key := keys[0]
+	if len(keys) == 1 {
+		existing, exists := data[key]
+		if !exists {
+			data[key] = value
+			return nil
+		}
+		existingObject, ok1 := existing.(map[string]any)
+		object, ok2 := value.(map[string]any)
+		if !ok1 || !ok2 {
+			return fmt.Errorf("conflicting values for %q", key)
+		}
+		return mergeData(existingObject, nil, object)
+	}
+
+	child, exists := data[key]
+	if !exists {
+		child = map[string]any{}
+		data[key] = child
+	}
+	childObject, ok := child.(map[string]any)
+	if !ok {
+		return fmt.Errorf("conflicting values for %q", key)
+	}
+	return mergeData(childObject, keys[1:], value)
+}
@@ -0,0 +1,118 @@
+package opa
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"testing"
+
+	"github.com/open-policy-agent/opa/v1/ast"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testBundle builds a gzipped tarball with the given files
+func testBundle(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0600, Size: int64(len(content))}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+	return buf.Bytes()
+}
+
+func TestLoadBundle(t *testing.T) {
+	t.Parallel()
+
+	// Bundles built with "opa build -t wasm" are compiled from their Rego modules
+	b, err := loadBundle(testBundle(t, map[string]string{
+		"/policy.wasm":                   "\x00asm",
+		"/.manifest":                     `{"revision":"1","wasm":[{"entrypoint":"toolhive/authz/allow","module":"/policy.wasm"}]}`,
+		"/data.json":                     `{"toolhive":{"admins":["alice"]}}`,
+		"/toolhive/tools/data.json":      `{"readonly":["fetch"]}`,
+		"toolhive/authz/policy.rego":     "package toolhive.authz",
+		"/toolhive/authz/data.json":      `{"enabled":true}`,
+		"/toolhive/authz/data.json.orig": "not json",
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"/toolhive/authz/policy.rego": "package toolhive.authz"}, b.modules)
+	assert.Equal(t, "toolhive/authz/allow", b.entrypoint)
+	assert.Equal(t, map[string]any{
+		"toolhive": map[string]any{
+			"admins": []any{"alice"},
+			"tools":  map[string]any{"readonly": []any{"fetch"}},
+			"authz":  map[string]any{"enabled": true},
+		},
+	}, b.data)
+}
+
+func TestLoadBundle_Rego(t *testing.T) {
+	t.Parallel()
+
+	b, err := loadBundle([]byte("package toolhive.authz"))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"policy.rego": "package toolhive.authz"}, b.modules)
+	assert.Equal(t, ast.RegoV1, b.regoVersion)
+
+	b, err = loadBundle(testBundle(t, map[string]string{
+		"/toolhive/authz/policy.rego": "package toolhive.authz",
+		"/toolhive/data.json":         `{"admins":["alice"]}`,
+		"/.manifest":                  `{"revision":"1","rego_version":0}`,
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"/toolhive/authz/policy.rego": "package toolhive.authz"}, b.modules)
+	assert.Equal(t, ast.RegoV0, b.regoVersion)
+	assert.Equal(t, map[string]any{"toolhive": map[string]any{"admins": []any{"alice"}}}, b.data)
+}
+
+func TestLoadBundle_Errors(t *testing.T) {
+	t.Parallel()
+
+	const policy = "package toolhive.authz"
+	tests := []struct {
+		name    string
+		content []byte
+		wantErr string
+	}{
+		{name: "binary content", content: []byte{0xff, 0xfe, 0x00}, wantErr: "a gzipped tarball or a Rego module"},
+		{name: "wasm module", content: []byte("\x00asm\x01\x00\x00\x00"), wantErr: errWasmPolicy.Error()},
+		{name: "invalid archive", content: []byte{0x1f, 0x8b, 0x00}, wantErr: "failed to read bundle"},
+		{
+			name:    "no policy",
+			content: testBundle(t, map[string]string{"/data.json": "{}"}),
+			wantErr: "bundle has no policy",
+		},
+		{
+			name:    "wasm bundle without rego modules",
+			content: testBundle(t, map[string]string{"/policy.wasm": "\x00asm", "/data.json": "{}"}),
+			wantErr: errWasmPolicy.Error(),
+		},
+		{
+			name:    "invalid data",
+			content: testBundle(t, map[string]string{"/policy.rego": policy, "/data.json": "{"}),
+			wantErr: "failed to parse /data.json",
+		},
+		{
+			name: "conflicting data",
+			content: testBundle(t, map[string]string{
+				"/policy.rego":     policy,
+				"/data.json":       `{"roles":"admin"}`,
+				"/roles/data.json": `{"admin":["alice"]}`,
+			}),
+			wantErr: `conflicting values for "roles"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := loadBundle(tt.content)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
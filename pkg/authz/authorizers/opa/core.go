@@ -0,0 +1,369 @@
+// Package opa provides authorization using Open Policy Agent (OPA) Rego policies.
+//
+// Policies are evaluated in process, and no OPA server is needed: Rego modules and bundles
+// are compiled with the OPA rego package.
+package opa
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/stacklok/toolhive/pkg/auth"
+	"github.com/stacklok/toolhive/pkg/authz/authorizers"
+	"github.com/stacklok/toolhive/pkg/logger"
+)
+
+// ConfigType is the configuration type identifier for OPA authorization.
+const ConfigType = "opa"
+
+// DefaultBundleConfigMapKey is the default key of the bundle in a ConfigMap.
+const DefaultBundleConfigMapKey = "bundle.tar.gz"
+
+// BundleConfigMapMountPath is the directory in which the operator mounts the ConfigMaps
+// referenced by bundle_configmap, each in a directory named after the ConfigMap.
+const BundleConfigMapMountPath = "/etc/toolhive/authz-bundles"
+
+func init() {
+	// Register the OPA authorizer factory with the authorizers registry.
+	authorizers.Register(ConfigType, &Factory{})
+}
+
+// Common errors for OPA authorization
+var (
+	ErrMissingPrincipal = errors.New("missing principal")
+	ErrNoBundle         = errors.New("exactly one of bundle_file, bundle or bundle_configmap is required")
+)
+
+// Config represents the complete authorization configuration file structure
+// for OPA authorization: the common version/type fields plus the OPA-specific "opa" field.
+type Config struct {
+	Version string         `json:"version"`
+	Type    string         `json:"type"`
+	Options *ConfigOptions `json:"opa"`
+}
+
+// ConfigOptions represents the OPA-specific authorization configuration options.
+type ConfigOptions struct {
+	// BundleFile is the path of an OPA bundle or of a Rego module
+	BundleFile string `json:"bundle_file,omitempty" yaml:"bundle_file,omitempty"`
+
+	// Bundle is the base64 encoded content of a bundle
+	Bundle string `json:"bundle,omitempty" yaml:"bundle,omitempty"`
+
+	// BundleConfigMap is a ConfigMap key holding the bundle.
+	// It is only supported by the operator, which mounts the ConfigMap in the pod.
+	BundleConfigMap *ConfigMapRef `json:"bundle_configmap,omitempty" yaml:"bundle_configmap,omitempty"`
+
+	// Entrypoint is the rule deciding whether a request is allowed, e.g. "toolhive/authz/allow".
+	// It defaults to the entrypoint declared by the manifest of the bundle when it declares a
+	// single one, as bundles built with "opa build -t wasm -e" do, and is required otherwise.
+	Entrypoint string `json:"entrypoint,omitempty" yaml:"entrypoint,omitempty"`
+}
+
+// ConfigMapRef references a key of a ConfigMap in the namespace of the MCP server.
+type ConfigMapRef struct {
+	// Name is the name of the ConfigMap
+	Name string `json:"name" yaml:"name"`
+	// Key is the key of the bundle in the ConfigMap (default: bundle.tar.gz)
+	Key string `json:"key,omitempty" yaml:"key,omitempty"`
+}
+
+// MountedPath returns the path of the bundle in the ConfigMap mounted by the operator.
+func (r *ConfigMapRef) MountedPath() string {
+	key := r.Key
+	if key == "" {
+		key = DefaultBundleConfigMapKey
+	}
+	return filepath.Join(BundleConfigMapMountPath, r.Name, key)
+}
+
+// ExtractConfig extracts the OPA configuration from an authorizers.Config.
+func ExtractConfig(authzConfig *authorizers.Config) (*Config, error) {
+	if authzConfig == nil {
+		return nil, fmt.Errorf("config is nil")
+	}
+	rawConfig := authzConfig.RawConfig()
+	if len(rawConfig) == 0 {
+		return nil, fmt.Errorf("config has no raw data")
+	}
+
+	var config Config
+	if err := json.Unmarshal(rawConfig, &config); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
+	}
+	if config.Options == nil {
+		return nil, fmt.Errorf("opa config is nil")
+	}
+	return &config, nil
+}
+
+// Factory implements the authorizers.AuthorizerFactory interface for OPA.
+type Factory struct{}
+
+// ValidateConfig validates the OPA-specific configuration.
+func (*Factory) ValidateConfig(rawConfig json.RawMessage) error {
+	var config Config
+	if err := json.Unmarshal(rawConfig, &config); err != nil {
+		return fmt.Errorf("failed to parse configuration: %w", err)
+	}
+	if config.Options == nil {
+		return fmt.Errorf("opa configuration is required (missing 'opa' field)")
+	}
+	return config.Options.validate()
+}
+
+// CreateAuthorizer creates an OPA Authorizer from the configuration.
+func (*Factory) CreateAuthorizer(rawConfig json.RawMessage, _ string) (authorizers.Authorizer, error) {
+	var config Config
+	if err := json.Unmarshal(rawConfig, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse configuration: %w", err)
+	}
+	if config.Options == nil {
+		return nil, fmt.Errorf("opa configuration is required (missing 'opa' field)")
+	}
+	return NewOPAAuthorizer(context.Background(), *config.Options)
+}
+
+// ReferencedFiles returns the bundle file of the configuration, or the mounted bundle ConfigMap,
+// so that policies are reloaded when it changes.
+func (*Factory) ReferencedFiles(rawConfig json.RawMessage) []string {
+	var config Config
+	if err := json.Unmarshal(rawConfig, &config); err != nil || config.Options == nil {
+		return nil
+	}
+	switch {
+	case config.Options.BundleFile != "":
+		return []string{config.Options.BundleFile}
+	case config.Options.BundleConfigMap != nil:
+		return []string{config.Options.BundleConfigMap.MountedPath()}
+	default:
+		return nil
+	}
+}
+
+func (o *ConfigOptions) validate() error {
+	sources := 0
+	for _, set := range []bool{o.BundleFile != "", o.Bundle != "", o.BundleConfigMap != nil} {
+		if set {
+			sources++
+		}
+	}
+	if sources != 1 {
+		return ErrNoBundle
+	}
+	if o.BundleConfigMap != nil && o.BundleConfigMap.Name == "" {
+		return fmt.Errorf("bundle_configmap name is required")
+	}
+	if o.Bundle != "" {
+		if _, err := base64.StdEncoding.DecodeString(o.Bundle); err != nil {
+			return fmt.Errorf("bundle is not base64 encoded: %w", err)
+		}
+	}
+	return nil
+}
+
+// readBundle returns the content of the configured bundle
+func (o *ConfigOptions) readBundle() ([]byte, error) {
+	switch {
+	case o.BundleFile != "":
+		content, err := os.ReadFile(o.BundleFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read bundle file: %w", err)
+		}
+		return content, nil
+	case o.Bundle != "":
+		content, err := base64.StdEncoding.DecodeString(o.Bundle)
+		if err != nil {
+			return nil, fmt.Errorf("bundle is not base64 encoded: %w", err)
+		}
+		return content, nil
+	case o.BundleConfigMap != nil:
+		content, err := os.ReadFile(o.BundleConfigMap.MountedPath())
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("bundle_configmap %q is only supported when running in Kubernetes with the operator",
+				o.BundleConfigMap.Name)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read bundle of ConfigMap %q: %w", o.BundleConfigMap.Name, err)
+		}
+		return content, nil
+	default:
+		return nil, ErrNoBundle
+	}
+}
+
+// Input is the input document of the policies. It holds the same information as the
+// Cedar authorizer: the principal and its claims, the action, the resource and its arguments.
+type Input struct {
+	Principal Principal `json:"principal"`
+	// Action is the action of the request, as named in Cedar policies:
+	// call_tool, get_prompt, read_resource, list_tools, list_prompts or list_resources
+	Action    string `json:"action"`
+	Feature   string `json:"feature"`
+	Operation string `json:"operation"`
+	// Resource is the tool, prompt or resource, or the feature for list operations
+	Resource  Resource       `json:"resource"`
+	Arguments map[string]any `json:"arguments,omitempty"`
+}
+
+// Principal is the client making the request.
+type Principal struct {
+	// ClientID is the "sub" claim of the token
+	ClientID string         `json:"client_id"`
+	Claims   map[string]any `json:"claims"`
+}
+
+// Resource is the object accessed by the request.
+type Resource struct {
+	// Type is tool, prompt, resource or feature
+	Type string `json:"type"`
+	// ID is the name of the tool or prompt, the URI of the resource, or the listed feature
+	ID   string `json:"id"`
+	Name string `json:"name,omitempty"`
+	URI  string `json:"uri,omitempty"`
+}
+
+// Authorizer authorizes MCP operations using Rego policies.
+type Authorizer struct {
+	policy     *regoPolicy
+	entrypoint string
+}
+
+// NewOPAAuthorizer creates a new OPA authorizer, loading the policy of the configured bundle.
+func NewOPAAuthorizer(ctx context.Context, options ConfigOptions) (*Authorizer, error) {
+	if err := options.validate(); err != nil {
+		return nil, err
+	}
+	content, err := options.readBundle()
+	if err != nil {
+		return nil, err
+	}
+	b, err := loadBundle(content)
+	if err != nil {
+		return nil, err
+	}
+
+	entrypoint := options.Entrypoint
+	if entrypoint == "" {
+		entrypoint = b.entrypoint
+	}
+	p, err := newRegoPolicy(ctx, b.modules, b.data, b.regoVersion, entrypoint)
+	if err != nil {
+		return nil, err
+	}
+	return &Authorizer{policy: p, entrypoint: entrypoint}, nil
+}
+
+// Close releases the resources of the policy.
+func (a *Authorizer) Close(ctx context.Context) error {
+	return a.policy.Close(ctx)
+}
+
+// IsAuthorized evaluates the policy against an input document. Requests are only allowed
+// when the entrypoint is true: an undefined entrypoint denies them, and a non-boolean
+// entrypoint is an error.
+func (a *Authorizer) IsAuthorized(ctx context.Context, input *Input) (bool, error) {
+	inputJSON, err := json.Marshal(input)
+	if err != nil {
+		return false, fmt.Errorf("failed to encode policy input: %w", err)
+	}
+
+	logger.Debugf("OPA authorization check - Client: %s, Action: %s, Resource: %s",
+		input.Principal.ClientID, input.Action, input.Resource.ID)
+
+	result, err := a.policy.Eval(ctx, inputJSON)
+	if err != nil {
+		return false, err
+	}
+	logger.Debugf("OPA decision: %s", result)
+
+	if result == nil {
+		return false, nil
+	}
+	var allowed bool
+	if err := json.Unmarshal(result, &allowed); err != nil {
+		return false, fmt.Errorf("policy entrypoint must be a boolean, got %s", result)
+	}
+	return allowed, nil
+}
+
+// AuthorizeWithJWTClaims authorizes an MCP operation with the JWT claims of the identity in the context.
+func (a *Authorizer) AuthorizeWithJWTClaims(
+	ctx context.Context,
+	feature authorizers.MCPFeature,
+	operation authorizers.MCPOperation,
+	resourceID string,
+	arguments map[string]interface{},
+) (bool, error) {
+	input, err := NewInput(ctx, feature, operation, resourceID, arguments)
+	if err != nil {
+		return false, err
+	}
+	return a.IsAuthorized(ctx, input)
+}
+
+// Decide authorizes an MCP operation like AuthorizeWithJWTClaims. As the rules matching
+// the request are not known, the policy of the decision is the evaluated entrypoint.
+func (a *Authorizer) Decide(
+	ctx context.Context,
+	feature authorizers.MCPFeature,
+	operation authorizers.MCPOperation,
+	resourceID string,
+	arguments map[string]interface{},
+) (*authorizers.Decision, error) {
+	allowed, err := a.AuthorizeWithJWTClaims(ctx, feature, operation, resourceID, arguments)
+	if err != nil {
+		return nil, err
+	}
+	return &authorizers.Decision{Allowed: allowed, Policies: []string{a.entrypoint}}, nil
+}
+
+// NewInput builds the input document of an MCP operation, with the identity in the context.
+func NewInput(
+	ctx context.Context,
+	feature authorizers.MCPFeature,
+	operation authorizers.MCPOperation,
+	resourceID string,
+	arguments map[string]interface{},
+) (*Input, error) {
+	identity, ok := auth.IdentityFromContext(ctx)
+	if !ok {
+		return nil, ErrMissingPrincipal
+	}
+	clientID, err := jwt.MapClaims(identity.Claims).GetSubject()
+	if err != nil || clientID == "" {
+		return nil, ErrMissingPrincipal
+	}
+
+	input := &Input{
+		Principal: Principal{ClientID: clientID, Claims: identity.Claims},
+		Feature:   string(feature),
+		Operation: string(operation),
+		Arguments: arguments,
+	}
+	switch {
+	case feature == authorizers.MCPFeatureTool && operation == authorizers.MCPOperationCall:
+		input.Action = "call_tool"
+		input.Resource = Resource{Type: "tool", ID: resourceID, Name: resourceID}
+	case feature == authorizers.MCPFeaturePrompt && operation == authorizers.MCPOperationGet:
+		input.Action = "get_prompt"
+		input.Resource = Resource{Type: "prompt", ID: resourceID, Name: resourceID}
+	case feature == authorizers.MCPFeatureResource && operation == authorizers.MCPOperationRead:
+		input.Action = "read_resource"
+		input.Resource = Resource{Type: "resource", ID: resourceID, URI: resourceID}
+	case operation == authorizers.MCPOperationList:
+		input.Action = fmt.Sprintf("list_%ss", feature)
+		input.Resource = Resource{Type: "feature", ID: string(feature)}
+	default:
+		return nil, fmt.Errorf("unsupported feature/operation combination: %s/%s", feature, operation)
+	}
+	return input, nil
+}
@@ -0,0 +1,230 @@
+package opa
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stacklok/toolhive/pkg/auth"
+	"github.com/stacklok/toolhive/pkg/authz/authorizers"
+)
+
+// testEntrypointsPolicy is a Rego module whose entrypoints allow, deny, fail, return the
+// resource of the input, or are undefined.
+const testEntrypointsPolicy = `package test
+
+allow := true
+
+deny := false
+
+abort := count(input.resource.id) / 0
+
+echo := input.resource.id
+`
+
+func testConfig(t *testing.T, options ConfigOptions) json.RawMessage {
+	t.Helper()
+	raw, err := json.Marshal(Config{Version: "1.0", Type: ConfigType, Options: &options})
+	require.NoError(t, err)
+	return raw
+}
+
+func TestFactory_ValidateConfig(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, authorizers.IsRegistered(ConfigType))
+	bundle := base64.StdEncoding.EncodeToString([]byte(testEntrypointsPolicy))
+
+	tests := []struct {
+		name    string
+		raw     json.RawMessage
+		wantErr string
+	}{
+		{name: "bundle file", raw: testConfig(t, ConfigOptions{BundleFile: "/etc/toolhive/bundle.tar.gz"})},
+		{name: "bundle", raw: testConfig(t, ConfigOptions{Bundle: bundle, Entrypoint: "test/allow"})},
+		{name: "bundle configmap", raw: testConfig(t, ConfigOptions{BundleConfigMap: &ConfigMapRef{Name: "policies"}})},
+		{name: "missing opa field", raw: json.RawMessage(`{"version":"1.0","type":"opa"}`), wantErr: "missing 'opa' field"},
+		{name: "no bundle", raw: testConfig(t, ConfigOptions{}), wantErr: ErrNoBundle.Error()},
+		{
+			name:    "several bundles",
+			raw:     testConfig(t, ConfigOptions{BundleFile: "/etc/toolhive/bundle.tar.gz", Bundle: bundle}),
+			wantErr: ErrNoBundle.Error(),
+		},
+		{name: "invalid base64", raw: testConfig(t, ConfigOptions{Bundle: "not base64!"}), wantErr: "not base64 encoded"},
+		{
+			name:    "configmap without name",
+			raw:     testConfig(t, ConfigOptions{BundleConfigMap: &ConfigMapRef{Key: "bundle.tar.gz"}}),
+			wantErr: "bundle_configmap name is required",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := (&Factory{}).ValidateConfig(tt.raw)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFactory_CreateAuthorizer(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	// The entrypoint defaults to the one declared by the manifest of bundles built for Wasm
+	require.NoError(t, os.WriteFile(path, testBundle(t, map[string]string{
+		"/test/policy.rego": testEntrypointsPolicy,
+		"/policy.wasm":      "\x00asm",
+		"/.manifest":        `{"wasm":[{"entrypoint":"test/allow","module":"/policy.wasm"}]}`,
+	}), 0600))
+
+	authorizer, err := (&Factory{}).CreateAuthorizer(testConfig(t, ConfigOptions{BundleFile: path}), "fetch")
+	require.NoError(t, err)
+	ctx := auth.WithIdentity(context.Background(), &auth.Identity{Subject: "alice", Claims: map[string]any{"sub": "alice"}})
+	allowed, err := authorizer.AuthorizeWithJWTClaims(ctx, authorizers.MCPFeatureTool, authorizers.MCPOperationCall, "fetch", nil)
+	require.NoError(t, err)
+	assert.True(t, allowed)
+	require.NoError(t, authorizer.(*Authorizer).Close(context.Background()))
+
+	_, err = (&Factory{}).CreateAuthorizer(testConfig(t, ConfigOptions{BundleFile: filepath.Join(t.TempDir(), "missing")}), "fetch")
+	assert.ErrorContains(t, err, "failed to read bundle file")
+
+	_, err = (&Factory{}).CreateAuthorizer(testConfig(t, ConfigOptions{BundleConfigMap: &ConfigMapRef{Name: "policies"}}), "fetch")
+	assert.ErrorContains(t, err, "only supported when running in Kubernetes")
+}
+
+func TestFactory_ReferencedFiles(t *testing.T) {
+	t.Parallel()
+
+	factory := &Factory{}
+	assert.Equal(t, []string{"/etc/toolhive/bundle.tar.gz"},
+		factory.ReferencedFiles(testConfig(t, ConfigOptions{BundleFile: "/etc/toolhive/bundle.tar.gz"})))
+	assert.Equal(t, []string{"/etc/toolhive/authz-bundles/policies/bundle.tar.gz"},
+		factory.ReferencedFiles(testConfig(t, ConfigOptions{BundleConfigMap: &ConfigMapRef{Name: "policies"}})))
+	assert.Equal(t, []string{"/etc/toolhive/authz-bundles/policies/policy.rego"},
+		factory.ReferencedFiles(testConfig(t, ConfigOptions{BundleConfigMap: &ConfigMapRef{Name: "policies", Key: "policy.rego"}})))
+	assert.Empty(t, factory.ReferencedFiles(testConfig(t, ConfigOptions{Bundle: "AGFzbQ=="})))
+	assert.Empty(t, factory.ReferencedFiles(json.RawMessage(`{"version":"1.0","type":"opa"}`)))
+}
+
+func TestAuthorizer_AuthorizeWithJWTClaims(t *testing.T) {
+	t.Parallel()
+
+	bundle := base64.StdEncoding.EncodeToString([]byte(testEntrypointsPolicy))
+	identity := &auth.Identity{Subject: "alice", Claims: map[string]any{"sub": "alice", "roles": []any{"admin"}}}
+	ctx := auth.WithIdentity(context.Background(), identity)
+
+	tests := []struct {
+		entrypoint string
+		ctx        context.Context
+		want       bool
+		wantErr    string
+	}{
+		{entrypoint: "test/allow", ctx: ctx, want: true},
+		{entrypoint: "test/deny", ctx: ctx},
+		{entrypoint: "test/undefined", ctx: ctx},
+		{entrypoint: "test/echo", ctx: ctx, wantErr: "policy entrypoint must be a boolean"},
+		{entrypoint: "test/abort", ctx: ctx, wantErr: "failed to evaluate policy"},
+		{entrypoint: "test/allow", ctx: context.Background(), wantErr: ErrMissingPrincipal.Error()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.entrypoint, func(t *testing.T) {
+			t.Parallel()
+			authorizer, err := NewOPAAuthorizer(context.Background(), ConfigOptions{Bundle: bundle, Entrypoint: tt.entrypoint})
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = authorizer.Close(context.Background()) })
+
+			allowed, err := authorizer.AuthorizeWithJWTClaims(tt.ctx, authorizers.MCPFeatureTool, authorizers.MCPOperationCall,
+				"fetch", map[string]any{"url": "https://example.com"})
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, allowed)
+		})
+	}
+}
+
+func TestNewInput(t *testing.T) {
+	t.Parallel()
+
+	claims := map[string]any{"sub": "alice", "groups": []any{"engineering"}}
+	ctx := auth.WithIdentity(context.Background(), &auth.Identity{Subject: "alice", Claims: claims})
+	principal := Principal{ClientID: "alice", Claims: claims}
+
+	tests := []struct {
+		name       string
+		feature    authorizers.MCPFeature
+		operation  authorizers.MCPOperation
+		resourceID string
+		arguments  map[string]any
+		want       *Input
+	}{
+		{
+			name:       "tool call",
+			feature:    authorizers.MCPFeatureTool,
+			operation:  authorizers.MCPOperationCall,
+			resourceID: "fetch",
+			arguments:  map[string]any{"url": "https://example.com", "headers": map[string]any{"accept": "text/html"}},
+			want: &Input{
+				Principal: principal, Action: "call_tool", Feature: "tool", Operation: "call",
+				Resource:  Resource{Type: "tool", ID: "fetch", Name: "fetch"},
+				Arguments: map[string]any{"url": "https://example.com", "headers": map[string]any{"accept": "text/html"}},
+			},
+		},
+		{
+			name:       "prompt get",
+			feature:    authorizers.MCPFeaturePrompt,
+			operation:  authorizers.MCPOperationGet,
+			resourceID: "greeting",
+			want: &Input{
+				Principal: principal, Action: "get_prompt", Feature: "prompt", Operation: "get",
+				Resource: Resource{Type: "prompt", ID: "greeting", Name: "greeting"},
+			},
+		},
+		{
+			name:       "resource read",
+			feature:    authorizers.MCPFeatureResource,
+			operation:  authorizers.MCPOperationRead,
+			resourceID: "file:///data/report.txt",
+			want: &Input{
+				Principal: principal, Action: "read_resource", Feature: "resource", Operation: "read",
+				Resource: Resource{Type: "resource", ID: "file:///data/report.txt", URI: "file:///data/report.txt"},
+			},
+		},
+		{
+			name:      "list",
+			feature:   authorizers.MCPFeatureTool,
+			operation: authorizers.MCPOperationList,
+			want: &Input{
+				Principal: principal, Action: "list_tools", Feature: "tool", Operation: "list",
+				Resource: Resource{Type: "feature", ID: "tool"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			input, err := NewInput(ctx, tt.feature, tt.operation, tt.resourceID, tt.arguments)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, input)
+		})
+	}
+
+	_, err := NewInput(ctx, authorizers.MCPFeatureTool, authorizers.MCPOperationGet, "fetch", nil)
+	assert.ErrorContains(t, err, "unsupported feature/operation combination")
+
+	noSubject := auth.WithIdentity(context.Background(), &auth.Identity{Claims: map[string]any{"email": "alice@example.com"}})
+	_, err = NewInput(noSubject, authorizers.MCPFeatureTool, authorizers.MCPOperationCall, "fetch", nil)
+	assert.ErrorIs(t, err, ErrMissingPrincipal)
+}
@@ -0,0 +1,84 @@
+package opa
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/open-policy-agent/opa/v1/ast"
+	"github.com/open-policy-agent/opa/v1/rego"
+	"github.com/open-policy-agent/opa/v1/storage/inmem"
+	"github.com/open-policy-agent/opa/v1/util"
+)
+
+// unsafeRegoBuiltins are the built-in functions that Rego policies may not call: decisions do
+// not depend on the network, and the environment of the proxy, which holds secrets, is not
+// exposed to the policies.
+var unsafeRegoBuiltins = map[string]struct{}{
+	ast.HTTPSend.Name:        {},
+	ast.NetLookupIPAddr.Name: {},
+	ast.OPARuntime.Name:      {},
+}
+
+// regoPolicy is a policy compiled from Rego modules with the OPA rego package.
+type regoPolicy struct {
+	query rego.PreparedEvalQuery
+}
+
+// newRegoPolicy compiles Rego modules, with the data document, to evaluate the given entrypoint.
+// Rego modules do not declare entrypoints, so the entrypoint is required.
+func newRegoPolicy(
+	ctx context.Context, modules map[string]string, data map[string]any, regoVersion ast.RegoVersion, entrypoint string,
+) (*regoPolicy, error) {
+	if entrypoint == "" {
+		return nil, errors.New("entrypoint is required for Rego policies, e.g. toolhive/authz/allow")
+	}
+	if data == nil {
+		data = map[string]any{}
+	}
+
+	options := []func(*rego.Rego){
+		rego.Query("data." + strings.ReplaceAll(strings.Trim(entrypoint, "/"), "/", ".")),
+		rego.Store(inmem.NewFromObject(data)),
+		rego.SetRegoVersion(regoVersion),
+		rego.UnsafeBuiltins(unsafeRegoBuiltins),
+		rego.StrictBuiltinErrors(true),
+	}
+	for name, module := range modules {
+		options = append(options, rego.Module(name, module))
+	}
+	query, err := rego.New(options...).PrepareForEval(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile Rego policy: %w", err)
+	}
+	return &regoPolicy{query: query}, nil
+}
+
+// Eval evaluates the entrypoint of the policy against the input document. It returns the
+// result of the entrypoint, or nil when the result is undefined.
+func (p *regoPolicy) Eval(ctx context.Context, input []byte) (json.RawMessage, error) {
+	var value any
+	if err := util.NewJSONDecoder(bytes.NewReader(input)).Decode(&value); err != nil {
+		return nil, fmt.Errorf("failed to decode policy input: %w", err)
+	}
+	results, err := p.query.Eval(ctx, rego.EvalInput(value))
+	if err != nil {
+		return nil, fmt.Errorf("failed to evaluate policy: %w", err)
+	}
+	if len(results) == 0 || len(results[0].Expressions) == 0 {
+		return nil, nil
+	}
+	result, err := json.Marshal(results[0].Expressions[0].Value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode policy result: %w", err)
+	}
+	return result, nil
+}
+
+// Close releases the resources of the policy. Compiled Rego policies hold none.
+func (*regoPolicy) Close(context.Context) error {
+	return nil
+}
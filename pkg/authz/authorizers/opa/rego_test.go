@@ -0,0 +1,132 @@
+package opa
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stacklok/toolhive/pkg/auth"
+	"github.com/stacklok/toolhive/pkg/authz/authorizers"
+)
+
+const testRegoPolicy = `package toolhive.authz
+
+default allow := false
+
+allow if {
+	input.principal.claims.sub in data.toolhive.admins
+}
+
+allow if {
+	input.action == "call_tool"
+	input.resource.id in data.toolhive.readonly
+	not input.arguments.write
+}
+
+echo := input.resource.id
+`
+
+func TestAuthorizer_Rego(t *testing.T) {
+	t.Parallel()
+
+	bundle := base64.StdEncoding.EncodeToString(testBundle(t, map[string]string{
+		"/toolhive/authz/policy.rego": testRegoPolicy,
+		"/toolhive/data.json":         `{"admins":["alice"],"readonly":["fetch"]}`,
+	}))
+	tests := []struct {
+		name       string
+		subject    string
+		entrypoint string
+		tool       string
+		arguments  map[string]any
+		want       bool
+		wantErr    string
+	}{
+		{name: "admin", subject: "alice", entrypoint: "toolhive/authz/allow", tool: "delete", want: true},
+		{name: "read-only tool", subject: "bob", entrypoint: "toolhive/authz/allow", tool: "fetch", want: true},
+		{
+			name: "arguments", subject: "bob", entrypoint: "toolhive/authz/allow", tool: "fetch",
+			arguments: map[string]any{"write": true},
+		},
+		{name: "denied", subject: "bob", entrypoint: "toolhive/authz/allow", tool: "delete"},
+		{name: "undefined", subject: "alice", entrypoint: "toolhive/authz/undefined", tool: "fetch"},
+		{
+			name: "not a boolean", subject: "alice", entrypoint: "toolhive/authz/echo", tool: "fetch",
+			wantErr: "policy entrypoint must be a boolean",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			authorizer, err := NewOPAAuthorizer(context.Background(), ConfigOptions{Bundle: bundle, Entrypoint: tt.entrypoint})
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = authorizer.Close(context.Background()) })
+
+			ctx := auth.WithIdentity(context.Background(),
+				&auth.Identity{Subject: tt.subject, Claims: map[string]any{"sub": tt.subject}})
+			decision, err := authorizer.Decide(ctx, authorizers.MCPFeatureTool, authorizers.MCPOperationCall, tt.tool, tt.arguments)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, decision.Allowed)
+			assert.Equal(t, []string{tt.entrypoint}, decision.Policies)
+		})
+	}
+}
+
+func TestNewRegoPolicy_Errors(t *testing.T) {
+	t.Parallel()
+
+	encode := func(policy string) string { return base64.StdEncoding.EncodeToString([]byte(policy)) }
+	tests := []struct {
+		name       string
+		policy     string
+		entrypoint string
+		wantErr    string
+	}{
+		{name: "no entrypoint", policy: testRegoPolicy, wantErr: "entrypoint is required for Rego policies"},
+		{
+			name:       "syntax error",
+			policy:     "package toolhive.authz\n\nallow {",
+			entrypoint: "toolhive/authz/allow",
+			wantErr:    "failed to compile Rego policy",
+		},
+		{
+			name: "network access",
+			policy: `package toolhive.authz
+
+allow if http.send({"method": "get", "url": "https://example.com"}).body.allow`,
+			entrypoint: "toolhive/authz/allow",
+			wantErr:    "unsafe built-in function calls",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := NewOPAAuthorizer(context.Background(), ConfigOptions{Bundle: encode(tt.policy), Entrypoint: tt.entrypoint})
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestNewRegoPolicy_RegoV0(t *testing.T) {
+	t.Parallel()
+
+	// The Rego version of the bundle manifest is honored
+	bundle := base64.StdEncoding.EncodeToString(testBundle(t, map[string]string{
+		"/policy.rego": "package toolhive.authz\n\nallow { input.principal.client_id == \"alice\" }",
+		"/.manifest":   `{"rego_version":0}`,
+	}))
+	authorizer, err := NewOPAAuthorizer(context.Background(), ConfigOptions{Bundle: bundle, Entrypoint: "toolhive/authz/allow"})
+	require.NoError(t, err)
+
+	ctx := auth.WithIdentity(context.Background(), &auth.Identity{Subject: "alice", Claims: map[string]any{"sub": "alice"}})
+	allowed, err := authorizer.AuthorizeWithJWTClaims(ctx, authorizers.MCPFeatureTool, authorizers.MCPOperationCall, "fetch", nil)
+	require.NoError(t, err)
+	assert.True(t, allowed)
+}
@@ -20,6 +20,13 @@ type AuthorizerFactory interface {
 	CreateAuthorizer(rawConfig json.RawMessage, serverName string) (Authorizer, error)
 }
 
+// FileReferencingFactory is implemented by factories whose configurations reference
+// files, e.g. policy bundles, so that policies are reloaded when these files change.
+type FileReferencingFactory interface {
+	// ReferencedFiles returns the paths of the files referenced by the configuration.
+	ReferencedFiles(rawConfig json.RawMessage) []string
+}
+
 // registry holds the registered authorizer factories, keyed by config type.
 var (
 	registryMu sync.RWMutex
@@ -4,11 +4,7 @@
 package authz
 
 import (
-	"fmt"
-	"net/http"
-
 	"github.com/stacklok/toolhive/pkg/authz/authorizers"
-	"github.com/stacklok/toolhive/pkg/transport/types"
 )
 
 // ConfigType is an alias for authorizers.ConfigType for backward compatibility.
@@ -24,25 +20,15 @@ var LoadConfig = authorizers.LoadConfig
 var NewConfig = authorizers.NewConfig
 
 // CreateMiddlewareFromConfig creates an HTTP middleware from the configuration.
-func CreateMiddlewareFromConfig(c *Config, serverName string) (types.MiddlewareFunction, error) {
-	// Get the factory for this config type
-	factory := authorizers.GetFactory(string(c.Type))
-	if factory == nil {
-		return nil, fmt.Errorf("unsupported configuration type: %s", c.Type)
-	}
-
-	// Create the authorizer using the factory, passing the full raw config
-	authz, err := factory.CreateAuthorizer(c.RawConfig(), serverName)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create %s authorizer: %w", c.Type, err)
-	}
-
-	// Return the middleware
-	return func(handler http.Handler) http.Handler { return Middleware(authz, handler) }, nil
+// The caller must close the middleware, which stops reloading the policies and
+// flushes the decision logs.
+func CreateMiddlewareFromConfig(c *Config, serverName string) (*FactoryMiddleware, error) {
+	return newFactoryMiddleware(c, serverName, "")
 }
 
 // GetMiddlewareFromFile loads the authorization configuration from a file and creates an HTTP middleware.
-func GetMiddlewareFromFile(serverName, path string) (func(http.Handler) http.Handler, error) {
+// The policies are reloaded from the file if enabled. The caller must close the middleware.
+func GetMiddlewareFromFile(serverName, path string) (*FactoryMiddleware, error) {
 	// Load the configuration
 	config, err := LoadConfig(path)
 	if err != nil {
@@ -50,5 +36,15 @@ func GetMiddlewareFromFile(serverName, path string) (func(http.Handler) http.Han
 	}
 
 	// Create the middleware
-	return CreateMiddlewareFromConfig(config, serverName)
+	return newFactoryMiddleware(config, serverName, path)
+}
+
+// newFactoryMiddleware creates the middleware of a policy engine. configPath is the file the
+// configuration was loaded from, if any.
+func newFactoryMiddleware(c *Config, serverName, configPath string) (*FactoryMiddleware, error) {
+	engine, err := newPolicyEngine(c, serverName, configPath)
+	if err != nil {
+		return nil, err
+	}
+	return &FactoryMiddleware{middleware: engine.Middleware, engine: engine}, nil
 }
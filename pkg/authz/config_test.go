@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -263,6 +264,7 @@ func TestCreateMiddleware(t *testing.T) {
 	middleware, err := CreateMiddlewareFromConfig(config, "testmodule")
 	require.NoError(t, err, "Failed to create middleware")
 	require.NotNil(t, middleware, "Middleware is nil")
+	defer func() { _ = middleware.Close() }()
 
 	// Create a test handler
 	testHandler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
@@ -270,7 +272,7 @@ func TestCreateMiddleware(t *testing.T) {
 	})
 
 	// Apply the middleware chain: MCP parsing first, then authorization
-	handler := mcpparser.ParsingMiddleware(middleware(testHandler))
+	handler := mcpparser.ParsingMiddleware(middleware.Handler()(testHandler))
 	require.NotNil(t, handler, "Handler is nil")
 
 	// Create a test request with a valid JSON-RPC message
@@ -391,6 +393,28 @@ func TestGetMiddlewareFromFile(t *testing.T) {
 		middleware, err := GetMiddlewareFromFile("testserver", tempFile.Name())
 		require.NoError(t, err)
 		require.NotNil(t, middleware)
+		require.NotNil(t, middleware.Handler())
+		require.NoError(t, middleware.Close())
+	})
+
+	t.Run("Closing stops reloading", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "authz.json")
+		writeConfig(t, path, cedarConfig([]string{`permit(principal, action, resource);`}, map[string]any{
+			"reload": map[string]any{"interval": "10ms"},
+		}))
+		middleware, err := GetMiddlewareFromFile("testserver", path)
+		require.NoError(t, err)
+		require.NotNil(t, middleware.engine.done)
+
+		require.NoError(t, middleware.Close())
+		select {
+		case <-middleware.engine.done:
+		default:
+			t.Fatal("the configuration is still watched after closing the middleware")
+		}
+		require.NoError(t, middleware.Close())
 	})
 
 	t.Run("Non-existent file", func(t *testing.T) {
@@ -122,8 +122,8 @@ func handleUnauthorized(w http.ResponseWriter, msgID interface{}, err error) {
 // is not authorized to access based on the corresponding call/get/read policies.
 //
 // The authorizer parameter should implement the authorizers.Authorizer interface,
-// which can be created using authz.CreateMiddlewareFromConfig() or directly
-// from an authorizer package (e.g., cedar.NewCedarAuthorizer()).
+// which can be created directly from an authorizer package (e.g., cedar.NewCedarAuthorizer()).
+// To create the middleware from a configuration, use authz.CreateMiddlewareFromConfig().
 func Middleware(a authorizers.Authorizer, next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Check if we should skip authorization before checking parsed data
@@ -208,6 +208,7 @@ type FactoryMiddlewareParams struct {
 // FactoryMiddleware wraps authorization middleware functionality for factory pattern
 type FactoryMiddleware struct {
 	middleware types.MiddlewareFunction
+	engine     *policyEngine
 }
 
 // Handler returns the middleware function used by the proxy.
@@ -215,10 +216,12 @@ func (m *FactoryMiddleware) Handler() types.MiddlewareFunction {
 	return m.middleware
 }
 
-// Close cleans up any resources used by the middleware.
-func (*FactoryMiddleware) Close() error {
-	// Authorization middleware doesn't need cleanup
-	return nil
+// Close stops reloading the policies and closes the decision logs.
+func (m *FactoryMiddleware) Close() error {
+	if m.engine == nil {
+		return nil
+	}
+	return m.engine.Close()
 }
 
 // CreateMiddleware factory function for authorization middleware
@@ -245,12 +248,11 @@ func CreateMiddleware(config *types.MiddlewareConfig, runner types.MiddlewareRun
 		return fmt.Errorf("either config_data or config_path is required for authorization middleware")
 	}
 
-	middleware, err := CreateMiddlewareFromConfig(authzConfig, runner.GetConfig().GetName())
+	authzMw, err := newFactoryMiddleware(authzConfig, runner.GetConfig().GetName(), params.ConfigPath)
 	if err != nil {
 		return fmt.Errorf("failed to create authorization middleware: %w", err)
 	}
 
-	authzMw := &FactoryMiddleware{middleware: middleware}
 	runner.AddMiddleware(config.Type, authzMw)
 	return nil
 }
@@ -0,0 +1,412 @@
+package authz
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/stacklok/toolhive/pkg/audit"
+	"github.com/stacklok/toolhive/pkg/authz/authorizers"
+	"github.com/stacklok/toolhive/pkg/logger"
+)
+
+// DefaultReloadInterval is the default interval at which the authorization configuration is checked for changes.
+const DefaultReloadInterval = 10 * time.Second
+
+// Options are the options of an authorization configuration that apply to all the authorizer types.
+type Options struct {
+	// Reload enables reloading the policies when the configuration changes.
+	Reload *ReloadOptions `json:"reload,omitempty"`
+
+	// Shadow is a candidate authorization configuration, evaluated next to the enforced one.
+	// Its decisions are logged but not enforced.
+	Shadow *Config `json:"shadow,omitempty"`
+
+	// DecisionLogs enables logging the authorization decisions as audit events.
+	DecisionLogs *audit.Config `json:"decision_logs,omitempty"`
+}
+
+// ReloadOptions are the options for reloading the policies when the configuration changes.
+type ReloadOptions struct {
+	// Path is the path of the configuration file to watch.
+	// It defaults to the file the configuration was loaded from.
+	Path string `json:"path,omitempty"`
+
+	// Interval is the interval at which the configuration is checked for changes, e.g. "30s".
+	// It defaults to 10s.
+	Interval string `json:"interval,omitempty"`
+}
+
+// ParseOptions parses and validates the options of an authorization configuration.
+func ParseOptions(c *Config) (*Options, error) {
+	var options Options
+	if raw := c.RawConfig(); len(raw) > 0 {
+		if err := json.Unmarshal(raw, &options); err != nil {
+			return nil, fmt.Errorf("failed to parse authorization options: %w", err)
+		}
+	}
+
+	if options.Reload != nil {
+		if _, err := options.Reload.interval(); err != nil {
+			return nil, err
+		}
+	}
+	if options.Shadow != nil {
+		if err := options.Shadow.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid shadow configuration: %w", err)
+		}
+	}
+	if options.DecisionLogs != nil {
+		if err := options.DecisionLogs.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid decision_logs configuration: %w", err)
+		}
+	}
+
+	return &options, nil
+}
+
+// interval returns the reload interval.
+func (o *ReloadOptions) interval() (time.Duration, error) {
+	if o.Interval == "" {
+		return DefaultReloadInterval, nil
+	}
+	interval, err := time.ParseDuration(o.Interval)
+	if err != nil {
+		return 0, fmt.Errorf("invalid reload interval: %w", err)
+	}
+	if interval <= 0 {
+		return 0, fmt.Errorf("invalid reload interval: must be positive")
+	}
+	return interval, nil
+}
+
+// loadedAuthorizer is an authorizer created from a configuration.
+type loadedAuthorizer struct {
+	configType ConfigType
+	authorizer authorizers.Authorizer
+	files      []string
+}
+
+// policies are the enforced and shadow authorizers of a configuration.
+type policies struct {
+	enforced *loadedAuthorizer
+	shadow   *loadedAuthorizer
+}
+
+// policyEngine is an authorizer that evaluates the enforced policies and, if configured,
+// the shadow policies. It logs the decisions and reloads the policies when the configuration
+// or the files it references change, swapping them atomically.
+type policyEngine struct {
+	serverName string
+	auditor    *audit.DecisionAuditor
+
+	// mu guards policies. Evaluations hold the read lock, so that the policies
+	// replaced by a reload are only closed once no evaluation uses them.
+	mu       sync.RWMutex
+	policies *policies
+
+	reloadPath string
+	checksum   string
+	stop       chan struct{}
+	done       chan struct{}
+	closeOnce  sync.Once
+}
+
+// newPolicyEngine creates a policy engine from the configuration. configPath is the file the
+// configuration was loaded from, if any, which is watched when reloading is enabled.
+func newPolicyEngine(c *Config, serverName, configPath string) (*policyEngine, error) {
+	options, err := ParseOptions(c)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := createPolicies(c, options, serverName)
+	if err != nil {
+		return nil, err
+	}
+	e := &policyEngine{serverName: serverName, policies: p}
+
+	if options.DecisionLogs != nil {
+		e.auditor, err = audit.NewDecisionAuditor(options.DecisionLogs)
+		if err != nil {
+			p.close()
+			return nil, fmt.Errorf("failed to create decision logs auditor: %w", err)
+		}
+	}
+
+	if options.Reload != nil {
+		e.reloadPath = options.Reload.Path
+		if e.reloadPath == "" {
+			e.reloadPath = configPath
+		}
+		if e.reloadPath == "" {
+			_ = e.Close()
+			return nil, fmt.Errorf("reload requires reload.path when the configuration is not loaded from a file")
+		}
+		interval, _ := options.Reload.interval()
+		e.checksum = checksumFiles(e.reloadPath, p.files())
+		e.stop = make(chan struct{})
+		e.done = make(chan struct{})
+		go e.watch(interval)
+		logger.Infof("Watching authorization configuration %s for changes every %s", e.reloadPath, interval)
+	}
+
+	return e, nil
+}
+
+// createPolicies creates the enforced and shadow authorizers of a configuration.
+func createPolicies(c *Config, options *Options, serverName string) (*policies, error) {
+	enforced, err := createAuthorizer(c, serverName)
+	if err != nil {
+		return nil, err
+	}
+	p := &policies{enforced: enforced}
+
+	if options.Shadow != nil {
+		p.shadow, err = createAuthorizer(options.Shadow, serverName)
+		if err != nil {
+			p.close()
+			return nil, fmt.Errorf("failed to create shadow authorizer: %w", err)
+		}
+	}
+	return p, nil
+}
+
+// createAuthorizer creates the authorizer of a configuration using the registered factory.
+func createAuthorizer(c *Config, serverName string) (*loadedAuthorizer, error) {
+	// Get the factory for this config type
+	factory := authorizers.GetFactory(string(c.Type))
+	if factory == nil {
+		return nil, fmt.Errorf("unsupported configuration type: %s", c.Type)
+	}
+
+	// Create the authorizer using the factory, passing the full raw config
+	authz, err := factory.CreateAuthorizer(c.RawConfig(), serverName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s authorizer: %w", c.Type, err)
+	}
+
+	a := &loadedAuthorizer{configType: c.Type, authorizer: authz}
+	if f, ok := factory.(authorizers.FileReferencingFactory); ok {
+		a.files = f.ReferencedFiles(c.RawConfig())
+	}
+	return a, nil
+}
+
+// files returns the files referenced by the configurations of the policies.
+func (p *policies) files() []string {
+	files := p.enforced.files
+	if p.shadow != nil {
+		files = append(append([]string{}, files...), p.shadow.files...)
+	}
+	return files
+}
+
+// close releases the resources of the authorizers, e.g. the runtime of OPA policies.
+func (p *policies) close() {
+	for _, a := range []*loadedAuthorizer{p.enforced, p.shadow} {
+		if a == nil {
+			continue
+		}
+		var err error
+		switch closer := a.authorizer.(type) {
+		case io.Closer:
+			err = closer.Close()
+		case interface{ Close(context.Context) error }:
+			err = closer.Close(context.Background())
+		}
+		if err != nil {
+			logger.Warnf("Failed to close %s authorizer: %v", a.configType, err)
+		}
+	}
+}
+
+// AuthorizeWithJWTClaims authorizes the request with the enforced policies.
+func (e *policyEngine) AuthorizeWithJWTClaims(
+	ctx context.Context,
+	feature authorizers.MCPFeature,
+	operation authorizers.MCPOperation,
+	resourceID string,
+	arguments map[string]interface{},
+) (bool, error) {
+	decision, err := e.Decide(ctx, feature, operation, resourceID, arguments)
+	if err != nil {
+		return false, err
+	}
+	return decision.Allowed, nil
+}
+
+// Decide authorizes the request with the enforced policies. If shadow policies are configured,
+// they are evaluated too, and disagreements with the enforced policies are logged.
+func (e *policyEngine) Decide(
+	ctx context.Context,
+	feature authorizers.MCPFeature,
+	operation authorizers.MCPOperation,
+	resourceID string,
+	arguments map[string]interface{},
+) (*authorizers.Decision, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	decision, err := authorizers.Decide(ctx, e.policies.enforced.authorizer, feature, operation, resourceID, arguments)
+	if e.policies.shadow == nil && e.auditor == nil {
+		return decision, err
+	}
+
+	logged := newAuthzDecision(e.policies.enforced, decision, err)
+	logged.Feature = string(feature)
+	logged.Operation = string(operation)
+	logged.ResourceID = resourceID
+	logged.Arguments = arguments
+
+	if shadow := e.policies.shadow; shadow != nil {
+		shadowDecision, shadowErr := authorizers.Decide(ctx, shadow.authorizer, feature, operation, resourceID, arguments)
+		logged.Shadow = newAuthzDecision(shadow, shadowDecision, shadowErr)
+		if isAllowed(decision, err) != isAllowed(shadowDecision, shadowErr) {
+			logger.Warnf("Shadow authorization policies disagree on %s %s %q: enforced allowed=%t, shadow allowed=%t",
+				operation, feature, resourceID, isAllowed(decision, err), isAllowed(shadowDecision, shadowErr))
+		}
+	}
+
+	if e.auditor != nil {
+		e.auditor.LogDecision(ctx, logged)
+	}
+	return decision, err
+}
+
+// isAllowed reports whether a decision allows the request. Evaluation errors deny the request.
+func isAllowed(decision *authorizers.Decision, err error) bool {
+	return err == nil && decision != nil && decision.Allowed
+}
+
+// newAuthzDecision returns the audit representation of a decision.
+func newAuthzDecision(a *loadedAuthorizer, decision *authorizers.Decision, err error) *audit.AuthzDecision {
+	logged := &audit.AuthzDecision{Authorizer: string(a.configType), Err: err}
+	if decision != nil {
+		logged.Allowed = decision.Allowed
+		logged.Policies = decision.Policies
+	}
+	return logged
+}
+
+// watch reloads the policies when the configuration changes, until the engine is closed.
+func (e *policyEngine) watch(interval time.Duration) {
+	defer close(e.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-e.stop:
+			return
+		case <-ticker.C:
+			e.reloadIfChanged()
+		}
+	}
+}
+
+// reloadIfChanged reloads the policies if the configuration or the files it references changed.
+// If the new configuration is invalid, the current policies are kept.
+func (e *policyEngine) reloadIfChanged() {
+	e.mu.RLock()
+	files := e.policies.files()
+	e.mu.RUnlock()
+
+	checksum := checksumFiles(e.reloadPath, files)
+	if checksum == e.checksum {
+		return
+	}
+	// Failed reloads are not retried until the files change again
+	e.checksum = checksum
+
+	if err := e.reload(); err != nil {
+		logger.Warnf("Failed to reload authorization configuration %s, keeping the current policies: %v", e.reloadPath, err)
+		return
+	}
+	// Account for the files referenced by the new configuration
+	e.mu.RLock()
+	e.checksum = checksumFiles(e.reloadPath, e.policies.files())
+	e.mu.RUnlock()
+}
+
+// reload loads the configuration and swaps the policies. Changes to the reload and
+// decision_logs options only apply after a restart.
+func (e *policyEngine) reload() error {
+	c, err := LoadConfig(e.reloadPath)
+	if err != nil {
+		return err
+	}
+	options, err := ParseOptions(c)
+	if err != nil {
+		return err
+	}
+	p, err := createPolicies(c, options, e.serverName)
+	if err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	old := e.policies
+	e.policies = p
+	e.mu.Unlock()
+	old.close()
+
+	if p.shadow != nil {
+		logger.Infof("Reloaded authorization policies from %s (%s, shadow %s)",
+			e.reloadPath, p.enforced.configType, p.shadow.configType)
+	} else {
+		logger.Infof("Reloaded authorization policies from %s (%s)", e.reloadPath, p.enforced.configType)
+	}
+	return nil
+}
+
+// checksumFiles returns a checksum of the contents of the files. Files that cannot be
+// read contribute their error, so that a reload is attempted when they become readable.
+func checksumFiles(path string, files []string) string {
+	h := sha256.New()
+	for _, file := range append([]string{path}, files...) {
+		data, err := os.ReadFile(file) // #nosec G304 - the files are configured by the operator of the server
+		if err != nil {
+			data = []byte(err.Error())
+		}
+		sum := sha256.Sum256(data)
+		_, _ = fmt.Fprintf(h, "%s\x00%x\n", file, sum)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Middleware returns the authorization middleware evaluating the policies of the engine.
+func (e *policyEngine) Middleware(next http.Handler) http.Handler {
+	handler := Middleware(e, next)
+	if e.auditor != nil {
+		handler = e.auditor.Middleware(handler)
+	}
+	return handler
+}
+
+// Close stops watching the configuration and releases the resources of the policies.
+func (e *policyEngine) Close() error {
+	var err error
+	e.closeOnce.Do(func() {
+		if e.stop != nil {
+			close(e.stop)
+			<-e.done
+		}
+
+		e.mu.Lock()
+		e.policies.close()
+		e.mu.Unlock()
+
+		if e.auditor != nil {
+			err = e.auditor.Close()
+		}
+	})
+	return err
+}
@@ -0,0 +1,252 @@
+package authz
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stacklok/toolhive/pkg/audit"
+	"github.com/stacklok/toolhive/pkg/auth"
+	"github.com/stacklok/toolhive/pkg/authz/authorizers"
+)
+
+// cedarConfig returns a Cedar authorization configuration with the given policies and options.
+func cedarConfig(policies []string, options map[string]any) map[string]any {
+	config := map[string]any{
+		"version": "1.0",
+		"type":    "cedarv1",
+		"cedar":   map[string]any{"policies": policies, "entities_json": "[]"},
+	}
+	for key, value := range options {
+		config[key] = value
+	}
+	return config
+}
+
+// writeConfig writes an authorization configuration to a file.
+func writeConfig(t *testing.T, path string, config map[string]any) {
+	t.Helper()
+	data, err := json.Marshal(config)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, data, 0600))
+}
+
+// readEvents reads the audit events of a log file.
+func readEvents(t *testing.T, path string) []map[string]any {
+	t.Helper()
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer func() { _ = file.Close() }()
+
+	var events []map[string]any
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var event map[string]any
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &event))
+		events = append(events, event)
+	}
+	require.NoError(t, scanner.Err())
+	return events
+}
+
+func TestParseOptions(t *testing.T) {
+	t.Parallel()
+
+	permitAll := []string{`permit(principal, action, resource);`}
+	tests := []struct {
+		name    string
+		options map[string]any
+		check   func(t *testing.T, options *Options)
+		wantErr string
+	}{
+		{
+			name: "no options",
+			check: func(t *testing.T, options *Options) {
+				t.Helper()
+				assert.Equal(t, &Options{}, options)
+			},
+		},
+		{
+			name: "all options",
+			options: map[string]any{
+				"reload":        map[string]any{"path": "/etc/toolhive/authz/authz.json", "interval": "30s"},
+				"shadow":        cedarConfig(permitAll, nil),
+				"decision_logs": map[string]any{"logFile": "/var/log/toolhive/authz.log"},
+			},
+			check: func(t *testing.T, options *Options) {
+				t.Helper()
+				assert.Equal(t, &ReloadOptions{Path: "/etc/toolhive/authz/authz.json", Interval: "30s"}, options.Reload)
+				require.NotNil(t, options.Shadow)
+				assert.Equal(t, ConfigType("cedarv1"), options.Shadow.Type)
+				require.NotNil(t, options.DecisionLogs)
+				assert.Equal(t, "/var/log/toolhive/authz.log", options.DecisionLogs.LogFile)
+				assert.Equal(t, audit.DefaultConfig().MaxDataSize, options.DecisionLogs.MaxDataSize)
+			},
+		},
+		{
+			name:    "invalid interval",
+			options: map[string]any{"reload": map[string]any{"interval": "often"}},
+			wantErr: "invalid reload interval",
+		},
+		{
+			name:    "negative interval",
+			options: map[string]any{"reload": map[string]any{"interval": "-1s"}},
+			wantErr: "must be positive",
+		},
+		{
+			name:    "invalid shadow",
+			options: map[string]any{"shadow": map[string]any{"version": "1.0", "type": "unknown"}},
+			wantErr: "invalid shadow configuration",
+		},
+		{
+			name:    "invalid decision logs",
+			options: map[string]any{"decision_logs": map[string]any{"maxDataSize": -1}},
+			wantErr: "invalid decision_logs configuration",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			options, err := ParseOptions(mustNewConfig(t, cedarConfig(permitAll, tt.options)))
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			tt.check(t, options)
+		})
+	}
+}
+
+func TestPolicyEngine_Reload(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "authz.json")
+	// The interval is long, so that the test triggers the reloads
+	reload := map[string]any{"reload": map[string]any{"interval": "1h"}}
+	weather := `permit(principal, action == Action::"call_tool", resource == Tool::"weather");`
+	writeConfig(t, path, cedarConfig([]string{weather}, reload))
+	config, err := LoadConfig(path)
+	require.NoError(t, err)
+
+	engine, err := newPolicyEngine(config, "weather", path)
+	require.NoError(t, err)
+	defer func() { _ = engine.Close() }()
+
+	ctx := auth.WithIdentity(context.Background(), &auth.Identity{Subject: "user123", Claims: map[string]any{"sub": "user123"}})
+	authorize := func(tool string) bool {
+		t.Helper()
+		allowed, err := engine.AuthorizeWithJWTClaims(ctx, authorizers.MCPFeatureTool, authorizers.MCPOperationCall, tool, nil)
+		require.NoError(t, err)
+		return allowed
+	}
+	assert.True(t, authorize("weather"))
+	assert.False(t, authorize("fetch"))
+
+	// Unchanged configurations are not reloaded
+	policies := engine.policies
+	engine.reloadIfChanged()
+	assert.Same(t, policies, engine.policies)
+
+	// Changed policies are swapped
+	fetch := `permit(principal, action == Action::"call_tool", resource == Tool::"fetch");`
+	writeConfig(t, path, cedarConfig([]string{fetch}, reload))
+	engine.reloadIfChanged()
+	assert.False(t, authorize("weather"))
+	assert.True(t, authorize("fetch"))
+
+	// Invalid configurations keep the current policies
+	writeConfig(t, path, cedarConfig([]string{`invalid policy`}, reload))
+	engine.reloadIfChanged()
+	assert.True(t, authorize("fetch"))
+	require.NoError(t, os.Remove(path))
+	engine.reloadIfChanged()
+	assert.True(t, authorize("fetch"))
+
+	require.NoError(t, engine.Close())
+	require.NoError(t, engine.Close())
+}
+
+func TestPolicyEngine_ReloadInlineConfig(t *testing.T) {
+	t.Parallel()
+
+	// Inline configurations, e.g. the config_data the operator passes to the proxy runner,
+	// are reloaded from the mounted configuration file set in reload.path
+	path := filepath.Join(t.TempDir(), "authz.json")
+	reload := map[string]any{"reload": map[string]any{"path": path, "interval": "1h"}}
+	weather := `permit(principal, action == Action::"call_tool", resource == Tool::"weather");`
+	writeConfig(t, path, cedarConfig([]string{weather}, reload))
+
+	engine, err := newPolicyEngine(mustNewConfig(t, cedarConfig([]string{weather}, reload)), "weather", "")
+	require.NoError(t, err)
+	defer func() { _ = engine.Close() }()
+
+	ctx := auth.WithIdentity(context.Background(), &auth.Identity{Subject: "user123", Claims: map[string]any{"sub": "user123"}})
+	allowed, err := engine.AuthorizeWithJWTClaims(ctx, authorizers.MCPFeatureTool, authorizers.MCPOperationCall, "fetch", nil)
+	require.NoError(t, err)
+	assert.False(t, allowed)
+
+	fetch := `permit(principal, action == Action::"call_tool", resource == Tool::"fetch");`
+	writeConfig(t, path, cedarConfig([]string{fetch}, reload))
+	engine.reloadIfChanged()
+	allowed, err = engine.AuthorizeWithJWTClaims(ctx, authorizers.MCPFeatureTool, authorizers.MCPOperationCall, "fetch", nil)
+	require.NoError(t, err)
+	assert.True(t, allowed)
+}
+
+func TestPolicyEngine_ShadowDecisionLogs(t *testing.T) {
+	t.Parallel()
+
+	logFile := filepath.Join(t.TempDir(), "decisions.log")
+	config := mustNewConfig(t, cedarConfig(
+		[]string{`@id("allow-weather") permit(principal, action == Action::"call_tool", resource == Tool::"weather");`},
+		map[string]any{
+			"shadow": cedarConfig([]string{
+				`@id("allow-all") permit(principal, action, resource);`,
+				`@id("deny-weather") forbid(principal, action == Action::"call_tool", resource == Tool::"weather");`,
+			}, nil),
+			"decision_logs": map[string]any{"logFile": logFile},
+		},
+	))
+	engine, err := newPolicyEngine(config, "weather", "")
+	require.NoError(t, err)
+
+	ctx := auth.WithIdentity(context.Background(), &auth.Identity{Subject: "user123", Claims: map[string]any{"sub": "user123"}})
+	decision, err := engine.Decide(ctx, authorizers.MCPFeatureTool, authorizers.MCPOperationCall, "weather", nil)
+	require.NoError(t, err)
+	assert.Equal(t, &authorizers.Decision{Allowed: true, Policies: []string{"allow-weather"}}, decision)
+
+	// Shadow decisions are not enforced
+	allowed, err := engine.AuthorizeWithJWTClaims(ctx, authorizers.MCPFeatureTool, authorizers.MCPOperationCall, "fetch", nil)
+	require.NoError(t, err)
+	assert.False(t, allowed)
+
+	_, err = engine.Decide(context.Background(), authorizers.MCPFeatureTool, authorizers.MCPOperationCall, "weather", nil)
+	assert.Error(t, err)
+	require.NoError(t, engine.Close())
+
+	events := readEvents(t, logFile)
+	require.Len(t, events, 3)
+	assert.Equal(t, audit.EventTypeAuthzDecision, events[0]["type"])
+	assert.Equal(t, audit.OutcomeSuccess, events[0]["outcome"])
+	assert.Equal(t, map[string]any{"type": "tool", "name": "weather", "operation": "call"}, events[0]["target"])
+	assert.Equal(t, map[string]any{"extra": map[string]any{
+		"authorizer": "cedarv1",
+		"allowed":    true,
+		"policies":   []any{"allow-weather"},
+		"shadow": map[string]any{
+			"authorizer": "cedarv1",
+			"allowed":    false,
+			"policies":   []any{"deny-weather"},
+		},
+	}}, events[0]["metadata"])
+
+	assert.Equal(t, audit.OutcomeDenied, events[1]["outcome"])
+	assert.Equal(t, audit.OutcomeError, events[2]["outcome"])
+}
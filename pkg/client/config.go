@@ -11,6 +11,7 @@ import (
 	"runtime"
 	"time"
 
+	"github.com/pelletier/go-toml/v2"
 	"github.com/tailscale/hujson"
 	"gopkg.in/yaml.v3"
 
@@ -67,6 +68,8 @@ const (
 	Antigravity MCPClient = "antigravity"
 	// Zed represents the Zed editor.
 	Zed MCPClient = "zed"
+	// Codex represents the OpenAI Codex CLI.
+	Codex MCPClient = "codex"
 )
 
 // Extension is extension of the client config file.
@@ -77,6 +80,8 @@ const (
 	JSON Extension = "json"
 	// YAML represents a YAML extension.
 	YAML Extension = "yaml"
+	// TOML represents a TOML extension.
+	TOML Extension = "toml"
 )
 
 // YAMLStorageType represents how servers are stored in YAML configuration files.
@@ -491,6 +496,17 @@ var supportedClientIntegrations = []mcpClientConfig{
 		IsTransportTypeFieldSupported: false,
 		MCPServersUrlLabel:            "url",
 	},
+	{
+		ClientType:           Codex,
+		Description:          "OpenAI Codex CLI",
+		SettingsFile:         "config.toml",
+		MCPServersPathPrefix: "/mcp_servers",
+		RelPath:              []string{".codex"},
+		Extension:            TOML,
+		// Codex connects to streamable HTTP servers from their URL, without a type field
+		IsTransportTypeFieldSupported: false,
+		MCPServersUrlLabel:            "url",
+	},
 }
 
 // ConfigFile represents a client configuration file
@@ -613,8 +629,8 @@ func (cm *ClientManager) CreateClientConfig(clientType MCPClient) (*ConfigFile,
 	logger.Infof("Creating new client config file at %s", path)
 
 	var initialContent []byte
-	if clientCfg.Extension == YAML {
-		// For YAML files, create an empty file - the updater will initialize structure as needed
+	if clientCfg.Extension == YAML || clientCfg.Extension == TOML {
+		// For YAML and TOML files, create an empty file - the updater will initialize structure as needed
 		initialContent = []byte("")
 	} else {
 		// JSON files get empty object
@@ -704,6 +720,11 @@ func (cm *ClientManager) retrieveConfigFileMetadata(clientType MCPClient) (*Conf
 			Path:                 path,
 			MCPServersPathPrefix: clientCfg.MCPServersPathPrefix,
 		}
+	case TOML:
+		configUpdater = &TOMLConfigUpdater{
+			Path:                 path,
+			MCPServersPathPrefix: clientCfg.MCPServersPathPrefix,
+		}
 	}
 
 	// Return the configuration file metadata
@@ -738,7 +759,7 @@ func validateConfigFileFormat(cf *ConfigFile) error {
 		return fmt.Errorf("failed to read file %s: %w", cf.Path, err)
 	}
 
-	if len(data) == 0 {
+	if len(data) == 0 && cf.Extension != TOML {
 		data = []byte("{}") // Default to an empty JSON object if the file is empty
 	}
 
@@ -754,6 +775,12 @@ func validateConfigFileFormat(cf *ConfigFile) error {
 		if err != nil {
 			return fmt.Errorf("failed to parse JSON for file %s: %w", cf.Path, err)
 		}
+	case TOML:
+		var temp map[string]interface{}
+		err = toml.Unmarshal(data, &temp)
+		if err != nil {
+			return fmt.Errorf("failed to parse TOML for file %s: %w", cf.Path, err)
+		}
 	}
 	return nil
 }
@@ -8,6 +8,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/pelletier/go-toml/v2"
 	"github.com/tailscale/hujson"
 	"github.com/tidwall/gjson"
 	"gopkg.in/yaml.v3"
@@ -287,6 +288,146 @@ func (ycu *YAMLConfigUpdater) Remove(serverName string) error {
 	return nil
 }
 
+// TOMLConfigUpdater is a ConfigUpdater that is responsible for updating
+// TOML config files. Each MCP server is a table under MCPServersPathPrefix,
+// e.g. [mcp_servers.name]. The file is edited line by line, so that comments,
+// the order of the entries and the other keys of the server tables are preserved.
+type TOMLConfigUpdater struct {
+	Path                 string
+	MCPServersPathPrefix string
+}
+
+// tomlMCPServerKeys are the keys of a server table that are managed by ToolHive
+var tomlMCPServerKeys = []string{"url", "serverUrl", "type"}
+
+// Upsert inserts or updates an MCP server in the TOML config file
+func (tcu *TOMLConfigUpdater) Upsert(serverName string, data MCPServer) error {
+	// Create a lock file
+	lockPath := tcu.Path + ".lock"
+	fileLock := lockfile.NewTrackedLock(lockPath)
+
+	// Create a context with timeout
+	ctx, cancel := context.WithTimeout(context.Background(), lockTimeout)
+	defer cancel()
+
+	// Try to acquire the lock with a timeout
+	locked, err := fileLock.TryLockContext(ctx, 100*time.Millisecond)
+	if err != nil {
+		return fmt.Errorf("failed to acquire lock: %w", err)
+	}
+	if !locked {
+		return fmt.Errorf("failed to acquire lock: timeout after %v", lockTimeout)
+	}
+	defer lockfile.ReleaseTrackedLock(lockPath, fileLock)
+
+	content, err := os.ReadFile(tcu.Path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+
+	var fields []tomlField
+	var removed []string
+	for _, key := range tomlMCPServerKeys {
+		var value string
+		switch key {
+		case "url":
+			value = data.Url
+		case "serverUrl":
+			value = data.ServerUrl
+		case "type":
+			value = data.Type
+		}
+		if value == "" {
+			removed = append(removed, key)
+			continue
+		}
+		fields = append(fields, tomlField{key: key, value: formatTOMLString(value)})
+	}
+
+	doc := newTOMLDocument(content)
+	doc.upsertTable(tcu.serverKey(serverName), fields, removed)
+
+	updatedContent := doc.bytes()
+	if err := validateTOML(updatedContent); err != nil {
+		return fmt.Errorf("failed to update TOML config for server %s: %w", serverName, err)
+	}
+
+	// Write back to file
+	if err := os.WriteFile(tcu.Path, updatedContent, 0600); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+
+	logger.Infof("Successfully updated TOML client config file for server %s", serverName)
+	return nil
+}
+
+// Remove removes an MCP server from the TOML config file
+func (tcu *TOMLConfigUpdater) Remove(serverName string) error {
+	// Create a lock file
+	lockPath := tcu.Path + ".lock"
+	fileLock := lockfile.NewTrackedLock(lockPath)
+
+	ctx, cancel := context.WithTimeout(context.Background(), lockTimeout)
+	defer cancel()
+
+	// Try to acquire the lock with a timeout
+	locked, err := fileLock.TryLockContext(ctx, 100*time.Millisecond)
+	if err != nil {
+		return fmt.Errorf("failed to acquire lock: %w", err)
+	}
+	if !locked {
+		return fmt.Errorf("failed to acquire lock: timeout after %v", lockTimeout)
+	}
+	defer lockfile.ReleaseTrackedLock(lockPath, fileLock)
+
+	// Read existing config
+	content, err := os.ReadFile(tcu.Path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			// File doesn't exist, nothing to remove
+			return nil
+		}
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+
+	doc := newTOMLDocument(content)
+	if !doc.removeTable(tcu.serverKey(serverName)) {
+		logger.Infof("MCPServer %s not found in client config file, nothing to remove", serverName)
+		return nil
+	}
+
+	updatedContent := doc.bytes()
+	if err := validateTOML(updatedContent); err != nil {
+		return fmt.Errorf("failed to remove server %s from TOML config: %w", serverName, err)
+	}
+
+	// Write back to file
+	if err := os.WriteFile(tcu.Path, updatedContent, 0600); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+
+	logger.Infof("Successfully removed server %s from TOML config file", serverName)
+	return nil
+}
+
+// serverKey returns the key of the table of an MCP server
+func (tcu *TOMLConfigUpdater) serverKey(serverName string) []string {
+	var key []string
+	for _, segment := range strings.Split(tcu.MCPServersPathPrefix, "/") {
+		if segment != "" {
+			key = append(key, segment)
+		}
+	}
+	return append(key, serverName)
+}
+
+// validateTOML checks that the edited content is still a valid TOML document,
+// e.g. that the servers were not already defined as inline tables.
+func validateTOML(content []byte) error {
+	var parsed map[string]interface{}
+	return toml.Unmarshal(content, &parsed)
+}
+
 // ensurePathExists ensures that the path exists in the JSON content
 // and returns the updated content.
 // For example:
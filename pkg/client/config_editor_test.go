@@ -590,3 +590,230 @@ func setupExistingTestYAMLConfig(t *testing.T, testName string) (string, string)
 
 	return tempDir, configPath
 }
+
+func TestTOMLConfigUpdaterUpsert(t *testing.T) {
+	t.Parallel()
+
+	logger.Initialize()
+
+	t.Run("AddNewMCPServerToEmptyTOML", func(t *testing.T) {
+		t.Parallel()
+
+		configPath := filepath.Join(t.TempDir(), "config.toml")
+		if err := os.WriteFile(configPath, []byte(""), 0600); err != nil {
+			t.Fatalf("Failed to write empty TOML file: %v", err)
+		}
+
+		tcu := TOMLConfigUpdater{Path: configPath, MCPServersPathPrefix: "/mcp_servers"}
+		err := tcu.Upsert("fetch", MCPServer{Url: "http://localhost:8080/mcp"})
+		if err != nil {
+			t.Fatalf("Failed to update TOML config: %v", err)
+		}
+
+		content, err := os.ReadFile(configPath)
+		if err != nil {
+			t.Fatalf("Failed to read TOML file: %v", err)
+		}
+		assert.Equal(t, "[mcp_servers.fetch]\nurl = \"http://localhost:8080/mcp\"\n", string(content))
+	})
+
+	t.Run("AddNewMCPServerToMissingFile", func(t *testing.T) {
+		t.Parallel()
+
+		configPath := filepath.Join(t.TempDir(), "config.toml")
+
+		tcu := TOMLConfigUpdater{Path: configPath, MCPServersPathPrefix: "/mcp_servers"}
+		err := tcu.Upsert("fetch", MCPServer{Url: "http://localhost:8080/mcp"})
+		if err != nil {
+			t.Fatalf("Failed to update TOML config: %v", err)
+		}
+
+		content, err := os.ReadFile(configPath)
+		if err != nil {
+			t.Fatalf("Failed to read TOML file: %v", err)
+		}
+		assert.Equal(t, "[mcp_servers.fetch]\nurl = \"http://localhost:8080/mcp\"\n", string(content))
+	})
+
+	t.Run("PreserveCommentsAndOtherEntries", func(t *testing.T) {
+		t.Parallel()
+
+		configPath := filepath.Join(t.TempDir(), "config.toml")
+		initialConfig := `# Codex configuration
+model = "o3"
+
+# A local server
+[mcp_servers.other]
+command = "npx"
+args = ["-y", "other-server"]
+`
+		if err := os.WriteFile(configPath, []byte(initialConfig), 0600); err != nil {
+			t.Fatalf("Failed to write TOML file: %v", err)
+		}
+
+		tcu := TOMLConfigUpdater{Path: configPath, MCPServersPathPrefix: "/mcp_servers"}
+		err := tcu.Upsert("fetch", MCPServer{Url: "http://localhost:8080/mcp"})
+		if err != nil {
+			t.Fatalf("Failed to update TOML config: %v", err)
+		}
+
+		content, err := os.ReadFile(configPath)
+		if err != nil {
+			t.Fatalf("Failed to read TOML file: %v", err)
+		}
+		expected := initialConfig + "\n[mcp_servers.fetch]\nurl = \"http://localhost:8080/mcp\"\n"
+		assert.Equal(t, expected, string(content))
+	})
+
+	t.Run("UpdateExistingMCPServer", func(t *testing.T) {
+		t.Parallel()
+
+		configPath := filepath.Join(t.TempDir(), "config.toml")
+		initialConfig := `[mcp_servers.fetch]
+url = "http://localhost:1234/mcp" # managed by ToolHive
+startup_timeout_sec = 20
+
+[mcp_servers.other]
+command = "npx"
+`
+		if err := os.WriteFile(configPath, []byte(initialConfig), 0600); err != nil {
+			t.Fatalf("Failed to write TOML file: %v", err)
+		}
+
+		tcu := TOMLConfigUpdater{Path: configPath, MCPServersPathPrefix: "/mcp_servers"}
+		err := tcu.Upsert("fetch", MCPServer{Url: "http://localhost:8080/mcp"})
+		if err != nil {
+			t.Fatalf("Failed to update TOML config: %v", err)
+		}
+
+		content, err := os.ReadFile(configPath)
+		if err != nil {
+			t.Fatalf("Failed to read TOML file: %v", err)
+		}
+		expected := `[mcp_servers.fetch]
+url = "http://localhost:8080/mcp" # managed by ToolHive
+startup_timeout_sec = 20
+
+[mcp_servers.other]
+command = "npx"
+`
+		assert.Equal(t, expected, string(content))
+	})
+
+	t.Run("QuoteServerNamesThatAreNotBareKeys", func(t *testing.T) {
+		t.Parallel()
+
+		configPath := filepath.Join(t.TempDir(), "config.toml")
+
+		tcu := TOMLConfigUpdater{Path: configPath, MCPServersPathPrefix: "/mcp_servers"}
+		err := tcu.Upsert("my.server", MCPServer{Url: "http://localhost:8080/mcp"})
+		if err != nil {
+			t.Fatalf("Failed to update TOML config: %v", err)
+		}
+		err = tcu.Upsert("my.server", MCPServer{Url: "http://localhost:9090/mcp"})
+		if err != nil {
+			t.Fatalf("Failed to update TOML config: %v", err)
+		}
+
+		content, err := os.ReadFile(configPath)
+		if err != nil {
+			t.Fatalf("Failed to read TOML file: %v", err)
+		}
+		assert.Equal(t, "[mcp_servers.\"my.server\"]\nurl = \"http://localhost:9090/mcp\"\n", string(content))
+	})
+
+	t.Run("FailOnInlineTableServers", func(t *testing.T) {
+		t.Parallel()
+
+		configPath := filepath.Join(t.TempDir(), "config.toml")
+		initialConfig := "mcp_servers = { other = { command = \"npx\" } }\n"
+		if err := os.WriteFile(configPath, []byte(initialConfig), 0600); err != nil {
+			t.Fatalf("Failed to write TOML file: %v", err)
+		}
+
+		tcu := TOMLConfigUpdater{Path: configPath, MCPServersPathPrefix: "/mcp_servers"}
+		err := tcu.Upsert("fetch", MCPServer{Url: "http://localhost:8080/mcp"})
+		assert.Error(t, err, "Should fail to add a table to an inline table")
+
+		content, err := os.ReadFile(configPath)
+		if err != nil {
+			t.Fatalf("Failed to read TOML file: %v", err)
+		}
+		assert.Equal(t, initialConfig, string(content), "Config file should not be modified")
+	})
+}
+
+func TestTOMLConfigUpdaterRemove(t *testing.T) {
+	t.Parallel()
+
+	logger.Initialize()
+
+	t.Run("RemoveExistingMCPServerFromTOML", func(t *testing.T) {
+		t.Parallel()
+
+		configPath := filepath.Join(t.TempDir(), "config.toml")
+		initialConfig := `model = "o3"
+
+# Added by ToolHive
+[mcp_servers.fetch]
+url = "http://localhost:8080/mcp"
+
+[mcp_servers.fetch.env]
+DEBUG = "1"
+
+[mcp_servers.other]
+command = "npx"
+`
+		if err := os.WriteFile(configPath, []byte(initialConfig), 0600); err != nil {
+			t.Fatalf("Failed to write TOML file: %v", err)
+		}
+
+		tcu := TOMLConfigUpdater{Path: configPath, MCPServersPathPrefix: "/mcp_servers"}
+		if err := tcu.Remove("fetch"); err != nil {
+			t.Fatalf("Failed to remove server from TOML config: %v", err)
+		}
+
+		content, err := os.ReadFile(configPath)
+		if err != nil {
+			t.Fatalf("Failed to read TOML file: %v", err)
+		}
+		expected := `model = "o3"
+
+[mcp_servers.other]
+command = "npx"
+`
+		assert.Equal(t, expected, string(content))
+	})
+
+	t.Run("RemoveNonExistentMCPServerFromTOML", func(t *testing.T) {
+		t.Parallel()
+
+		configPath := filepath.Join(t.TempDir(), "config.toml")
+		initialConfig := "[mcp_servers.other]\ncommand = \"npx\"\n"
+		if err := os.WriteFile(configPath, []byte(initialConfig), 0600); err != nil {
+			t.Fatalf("Failed to write TOML file: %v", err)
+		}
+
+		tcu := TOMLConfigUpdater{Path: configPath, MCPServersPathPrefix: "/mcp_servers"}
+		if err := tcu.Remove("fetch"); err != nil {
+			t.Fatalf("Should not error when removing non-existent server: %v", err)
+		}
+
+		content, err := os.ReadFile(configPath)
+		if err != nil {
+			t.Fatalf("Failed to read TOML file: %v", err)
+		}
+		assert.Equal(t, initialConfig, string(content))
+	})
+
+	t.Run("RemoveFromMissingTOMLFile", func(t *testing.T) {
+		t.Parallel()
+
+		configPath := filepath.Join(t.TempDir(), "config.toml")
+
+		tcu := TOMLConfigUpdater{Path: configPath, MCPServersPathPrefix: "/mcp_servers"}
+		if err := tcu.Remove("fetch"); err != nil {
+			t.Fatalf("Should not error when removing from a missing file: %v", err)
+		}
+	})
+}
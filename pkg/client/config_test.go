@@ -22,6 +22,7 @@ import (
 
 const testValidJSON = `{"mcpServers": {}, "mcp": {"servers": {}}}`
 const testValidYAML = `extensions: {}`
+const testValidTOML = `model = "o3"`
 
 // createMockClientConfigs creates a set of mock client configurations for testing
 func createMockClientConfigs() []mcpClientConfig {
@@ -170,6 +171,14 @@ func createMockClientConfigs() []mcpClientConfig {
 			MCPServersPathPrefix: "/mcpServers",
 			Extension:            YAML,
 		},
+		{
+			ClientType:           Codex,
+			Description:          "OpenAI Codex CLI (Mock)",
+			RelPath:              []string{"mock_codex"},
+			SettingsFile:         "config.toml",
+			MCPServersPathPrefix: "/mcp_servers",
+			Extension:            TOML,
+		},
 	}
 }
 
@@ -353,6 +362,7 @@ func TestSuccessfulClientConfigOperations(t *testing.T) {
 				string(Kiro),
 				string(Antigravity),
 				string(Zed),
+				string(Codex),
 			},
 		},
 	}
@@ -457,6 +467,9 @@ func TestSuccessfulClientConfigOperations(t *testing.T) {
 				// YAML files are created empty and initialized on first use
 				// Just verify the file exists and is readable
 				assert.NotNil(t, content, "Continue config should be readable")
+			case Codex:
+				assert.Contains(t, string(content), `model = "o3"`,
+					"Codex config should contain the model key")
 			}
 		}
 	})
@@ -490,7 +503,8 @@ func TestSuccessfulClientConfigOperations(t *testing.T) {
 				assert.Contains(t, string(content), testURL,
 					"VSCode config should contain the server URL")
 			case Cursor, RooCode, ClaudeCode, Cline, Windsurf, WindsurfJetBrains, AmpCli,
-				AmpVSCode, AmpCursor, AmpVSCodeInsider, AmpWindsurf, LMStudio, Goose, Trae, Continue, OpenCode, Kiro, Antigravity, Zed:
+				AmpVSCode, AmpCursor, AmpVSCodeInsider, AmpWindsurf, LMStudio, Goose, Trae, Continue, OpenCode, Kiro, Antigravity, Zed,
+				Codex:
 				assert.Contains(t, string(content), testURL,
 					"Config should contain the server URL")
 			}
@@ -511,9 +525,12 @@ func createTestConfigFilesWithConfigs(t *testing.T, homeDir string, clientConfig
 
 			// Choose the appropriate content based on the file extension
 			var content []byte
-			if cfg.Extension == YAML {
+			switch cfg.Extension {
+			case YAML:
 				content = []byte(testValidYAML)
-			} else {
+			case TOML:
+				content = []byte(testValidTOML)
+			case JSON:
 				content = []byte(testValidJSON)
 			}
 
@@ -0,0 +1,359 @@
+package client
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// This file implements a minimal line-based editor for TOML documents. MCP clients
+// such as Codex keep their settings next to the MCP servers in a single TOML file,
+// so the editor only touches the lines of the edited table, leaving comments, blank
+// lines and the order of everything else untouched.
+
+// tomlField is a key/value pair of a TOML table, with the value already formatted.
+type tomlField struct {
+	key   string
+	value string
+}
+
+// tomlHeader is a table header line of a TOML document.
+type tomlHeader struct {
+	line  int
+	key   []string
+	array bool
+}
+
+// tomlDocument is a TOML document split into lines.
+type tomlDocument struct {
+	lines []string
+}
+
+func newTOMLDocument(content []byte) *tomlDocument {
+	text := strings.TrimRight(string(content), "\n")
+	if text == "" {
+		return &tomlDocument{}
+	}
+	return &tomlDocument{lines: strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")}
+}
+
+// bytes returns the document, ending with a newline.
+func (d *tomlDocument) bytes() []byte {
+	if len(d.lines) == 0 {
+		return nil
+	}
+	return []byte(strings.Join(d.lines, "\n") + "\n")
+}
+
+// headers returns the table headers of the document, skipping lines inside multi-line strings.
+func (d *tomlDocument) headers() []tomlHeader {
+	var headers []tomlHeader
+	multiline := ""
+	for i, line := range d.lines {
+		if multiline != "" {
+			if strings.Count(line, multiline)%2 == 1 {
+				multiline = ""
+			}
+			continue
+		}
+		for _, delimiter := range []string{`"""`, `'''`} {
+			if strings.Count(line, delimiter)%2 == 1 {
+				multiline = delimiter
+			}
+		}
+		if multiline != "" {
+			continue
+		}
+
+		trimmed := strings.TrimSpace(line)
+		if !strings.HasPrefix(trimmed, "[") {
+			continue
+		}
+		array := strings.HasPrefix(trimmed, "[[")
+		closing := "]"
+		if array {
+			trimmed, closing = trimmed[2:], "]]"
+		} else {
+			trimmed = trimmed[1:]
+		}
+		key, rest, err := parseTOMLKey(trimmed)
+		if err != nil || !strings.HasPrefix(strings.TrimSpace(rest), closing) {
+			continue
+		}
+		headers = append(headers, tomlHeader{line: i, key: key, array: array})
+	}
+	return headers
+}
+
+// findTable returns the index of the header of the table with the given key, or -1.
+func findTable(headers []tomlHeader, key []string) int {
+	for i, header := range headers {
+		if !header.array && equalTOMLKeys(header.key, key) {
+			return i
+		}
+	}
+	return -1
+}
+
+// leadingComments returns the first line of the comment block directly above a line.
+func (d *tomlDocument) leadingComments(line int) int {
+	for line > 0 && strings.HasPrefix(strings.TrimSpace(d.lines[line-1]), "#") {
+		line--
+	}
+	return line
+}
+
+// tableEnd returns the line after the last line of the body of the table whose header is
+// headers[i]. Comments directly above the next header belong to that header.
+func (d *tomlDocument) tableEnd(headers []tomlHeader, i int) int {
+	end := len(d.lines)
+	if i+1 < len(headers) {
+		end = d.leadingComments(headers[i+1].line)
+	}
+	for end > headers[i].line+1 && strings.TrimSpace(d.lines[end-1]) == "" {
+		end--
+	}
+	return end
+}
+
+// upsertTable sets the fields of the table with the given key, creating the table at the
+// end of the document if needed. Lines of the removed keys are deleted, other lines of
+// the table are kept.
+func (d *tomlDocument) upsertTable(key []string, fields []tomlField, removed []string) {
+	headers := d.headers()
+	i := findTable(headers, key)
+	if i < 0 {
+		if len(d.lines) > 0 {
+			d.lines = append(d.lines, "")
+		}
+		d.lines = append(d.lines, "["+formatTOMLKey(key)+"]")
+		for _, field := range fields {
+			d.lines = append(d.lines, field.key+" = "+field.value)
+		}
+		return
+	}
+
+	start, end := headers[i].line+1, d.tableEnd(headers, i)
+	body := append([]string{}, d.lines[start:end]...)
+	for _, field := range fields {
+		if j := findTOMLField(body, field.key); j >= 0 {
+			body[j] = replaceTOMLValue(body[j], field.key, field.value)
+			continue
+		}
+		// Add the field after the last key/value pair of the table
+		insert := len(body)
+		for insert > 0 && isTOMLBlankOrComment(body[insert-1]) {
+			insert--
+		}
+		body = append(body[:insert], append([]string{field.key + " = " + field.value}, body[insert:]...)...)
+	}
+	for _, key := range removed {
+		if j := findTOMLField(body, key); j >= 0 {
+			body = append(body[:j], body[j+1:]...)
+		}
+	}
+	d.lines = append(d.lines[:start], append(body, d.lines[end:]...)...)
+}
+
+// removeTable removes the table with the given key and its sub-tables, together with the
+// comments directly above their headers. It returns false if the table does not exist.
+func (d *tomlDocument) removeTable(key []string) bool {
+	headers := d.headers()
+	removed := false
+	// Remove from the bottom so that the line numbers of the remaining headers stay valid
+	for i := len(headers) - 1; i >= 0; i-- {
+		if !hasTOMLKeyPrefix(headers[i].key, key) {
+			continue
+		}
+		start, end := d.leadingComments(headers[i].line), d.tableEnd(headers, i)
+		// Also remove the blank line separating the table from the previous one
+		if start > 0 && strings.TrimSpace(d.lines[start-1]) == "" {
+			start--
+		}
+		d.lines = append(d.lines[:start], d.lines[end:]...)
+		removed = true
+	}
+	// Do not leave a blank line at the start of the document
+	for len(d.lines) > 0 && strings.TrimSpace(d.lines[0]) == "" {
+		d.lines = d.lines[1:]
+	}
+	return removed
+}
+
+// findTOMLField returns the index of the line defining the key in a table body, or -1.
+func findTOMLField(body []string, key string) int {
+	for i, line := range body {
+		if isTOMLBlankOrComment(line) {
+			continue
+		}
+		lineKey, rest, err := parseTOMLKey(line)
+		if err == nil && strings.HasPrefix(strings.TrimSpace(rest), "=") &&
+			equalTOMLKeys(lineKey, []string{key}) {
+			return i
+		}
+	}
+	return -1
+}
+
+// replaceTOMLValue replaces the value of a key/value line, keeping its indentation and,
+// for single-line string values, its trailing comment.
+func replaceTOMLValue(line, key, value string) string {
+	indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+	comment := ""
+	if _, rest, err := parseTOMLKey(line); err == nil {
+		rest = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(rest), "="))
+		if _, after, ok := cutTOMLString(rest); ok && strings.HasPrefix(strings.TrimSpace(after), "#") {
+			comment = " " + strings.TrimSpace(after)
+		}
+	}
+	return indent + key + " = " + value + comment
+}
+
+func isTOMLBlankOrComment(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return trimmed == "" || strings.HasPrefix(trimmed, "#")
+}
+
+// parseTOMLKey parses the dotted key at the start of s and returns its parts and the
+// rest of s.
+func parseTOMLKey(s string) ([]string, string, error) {
+	var parts []string
+	for {
+		s = strings.TrimLeft(s, " \t")
+		if s == "" {
+			return nil, "", fmt.Errorf("missing key")
+		}
+		var part string
+		switch s[0] {
+		case '"', '\'':
+			value, rest, ok := cutTOMLString(s)
+			if !ok {
+				return nil, "", fmt.Errorf("unterminated quoted key")
+			}
+			part, s = value, rest
+		default:
+			end := 0
+			for end < len(s) && isBareKeyChar(s[end]) {
+				end++
+			}
+			if end == 0 {
+				return nil, "", fmt.Errorf("invalid key character %q", s[0])
+			}
+			part, s = s[:end], s[end:]
+		}
+		parts = append(parts, part)
+
+		s = strings.TrimLeft(s, " \t")
+		if !strings.HasPrefix(s, ".") {
+			return parts, s, nil
+		}
+		s = s[1:]
+	}
+}
+
+// cutTOMLString parses the single-line basic or literal string at the start of s and
+// returns its value and the rest of s.
+func cutTOMLString(s string) (string, string, bool) {
+	if s == "" {
+		return "", "", false
+	}
+	if s[0] == '\'' {
+		end := strings.IndexByte(s[1:], '\'')
+		if end < 0 {
+			return "", "", false
+		}
+		return s[1 : end+1], s[end+2:], true
+	}
+	if s[0] != '"' {
+		return "", "", false
+	}
+	var sb strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '"':
+			return sb.String(), s[i+1:], true
+		case '\\':
+			if i+1 >= len(s) {
+				return "", "", false
+			}
+			i++
+			switch s[i] {
+			case 'b':
+				sb.WriteByte('\b')
+			case 'f':
+				sb.WriteByte('\f')
+			case 'n':
+				sb.WriteByte('\n')
+			case 'r':
+				sb.WriteByte('\r')
+			case 't':
+				sb.WriteByte('\t')
+			case 'u', 'U':
+				size := 4
+				if s[i] == 'U' {
+					size = 8
+				}
+				if i+size >= len(s) {
+					return "", "", false
+				}
+				r, err := strconv.ParseUint(s[i+1:i+1+size], 16, 32)
+				if err != nil {
+					return "", "", false
+				}
+				sb.WriteRune(rune(r))
+				i += size
+			default:
+				sb.WriteByte(s[i])
+			}
+		default:
+			sb.WriteByte(s[i])
+		}
+	}
+	return "", "", false
+}
+
+func isBareKeyChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-'
+}
+
+// formatTOMLKey formats a dotted key, quoting the parts that are not bare keys.
+func formatTOMLKey(parts []string) string {
+	formatted := make([]string, 0, len(parts))
+	for _, part := range parts {
+		bare := part != ""
+		for i := 0; i < len(part); i++ {
+			if !isBareKeyChar(part[i]) {
+				bare = false
+				break
+			}
+		}
+		if bare {
+			formatted = append(formatted, part)
+		} else {
+			formatted = append(formatted, formatTOMLString(part))
+		}
+	}
+	return strings.Join(formatted, ".")
+}
+
+// formatTOMLString formats a value as a TOML basic string.
+func formatTOMLString(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\t", `\t`, "\r", `\r`)
+	return `"` + replacer.Replace(value) + `"`
+}
+
+func equalTOMLKeys(a, b []string) bool {
+	return len(a) == len(b) && hasTOMLKeyPrefix(a, b)
+}
+
+func hasTOMLKeyPrefix(key, prefix []string) bool {
+	if len(key) < len(prefix) {
+		return false
+	}
+	for i := range prefix {
+		if key[i] != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -367,6 +367,7 @@ func (c *Client) ListWorkloads(ctx context.Context) ([]runtime.ContainerInfo, er
 		result = append(result, runtime.ContainerInfo{
 			Name:    name,
 			Image:   c.Image,
+			ImageID: c.ImageID,
 			Status:  c.Status,
 			State:   dockerToDomainStatus(c.State),
 			Created: created,
@@ -525,6 +526,43 @@ func (c *Client) IsWorkloadRunning(ctx context.Context, workloadName string) (bo
 	return info.State.Running, nil
 }
 
+// StreamEgressLog streams the access log of the egress proxy of a network-isolated workload.
+func (c *Client) StreamEgressLog(ctx context.Context, workloadName string, since time.Time) (io.ReadCloser, error) {
+	egressContainerName := fmt.Sprintf("%s-egress", workloadName)
+	egressContainer, err := c.inspectContainerByName(ctx, egressContainerName)
+	if err != nil {
+		return nil, err
+	}
+
+	logs, err := c.client.ContainerLogs(ctx, egressContainer.ID, container.LogsOptions{
+		ShowStdout: true,
+		Follow:     true,
+		Since:      strconv.FormatInt(since.Unix(), 10),
+	})
+	if err != nil {
+		return nil, NewContainerError(err, egressContainerName, fmt.Sprintf("failed to get egress proxy logs: %v", err))
+	}
+
+	// The log stream multiplexes stdout and stderr, so demultiplex it into a pipe
+	reader, writer := io.Pipe()
+	go func() {
+		_, err := stdcopy.StdCopy(writer, io.Discard, logs)
+		writer.CloseWithError(err)
+	}()
+	return &egressLogReader{PipeReader: reader, logs: logs}, nil
+}
+
+// egressLogReader closes the underlying log stream together with the demultiplexed pipe.
+type egressLogReader struct {
+	*io.PipeReader
+	logs io.Closer
+}
+
+// Close closes the pipe and the log stream.
+func (r *egressLogReader) Close() error {
+	return errors.Join(r.PipeReader.Close(), r.logs.Close())
+}
+
 // GetWorkloadInfo gets workload information
 func (c *Client) GetWorkloadInfo(ctx context.Context, workloadName string) (runtime.ContainerInfo, error) {
 	// Inspect workload
@@ -570,10 +608,12 @@ func (c *Client) GetWorkloadInfo(ctx context.Context, workloadName string) (runt
 	return runtime.ContainerInfo{
 		Name:      strings.TrimPrefix(info.Name, "/"),
 		Image:     info.Config.Image,
+		ImageID:   info.Image,
 		Status:    info.State.Status,
 		State:     dockerToDomainStatus(info.State.Status),
 		Created:   created,
 		StartedAt: startedAt,
+		ExitCode:  info.State.ExitCode,
 		Labels:    info.Config.Labels,
 		Ports:     ports,
 	}, nil
@@ -812,6 +852,12 @@ func (c *Client) getPermissionConfigFromProfile(
 		Privileged:  profile.Privileged,
 	}
 
+	// Add resource limits
+	if err := profile.Resources.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid resource limits: %w", err)
+	}
+	config.Resources = profile.Resources
+
 	// Add mounts
 	c.addReadOnlyMounts(config, profile.Read, ignoreConfig)
 	c.addReadWriteMounts(config, profile.Write, ignoreConfig)
@@ -963,6 +1009,42 @@ func compareHostConfig(existing *container.InspectResponse, desired *container.H
 		return false
 	}
 
+	// Compare resource limits
+	if !compareResources(existing.HostConfig, desired) {
+		return false
+	}
+
+	return true
+}
+
+// compareResources compares CPU, memory, process and tmpfs limits
+func compareResources(existing, desired *container.HostConfig) bool {
+	if existing.CPUShares != desired.CPUShares ||
+		existing.NanoCPUs != desired.NanoCPUs ||
+		existing.Memory != desired.Memory {
+		return false
+	}
+
+	// Docker reports an unset pids limit as nil or 0
+	var existingPids, desiredPids int64
+	if existing.PidsLimit != nil {
+		existingPids = *existing.PidsLimit
+	}
+	if desired.PidsLimit != nil {
+		desiredPids = *desired.PidsLimit
+	}
+	if existingPids != desiredPids {
+		return false
+	}
+
+	if len(existing.Tmpfs) != len(desired.Tmpfs) {
+		return false
+	}
+	for target, options := range desired.Tmpfs {
+		if existingOptions, ok := existing.Tmpfs[target]; !ok || existingOptions != options {
+			return false
+		}
+	}
 	return true
 }
 
@@ -1459,14 +1541,21 @@ func (c *Client) createMcpContainer(
 		CapDrop:     permissionConfig.CapDrop,
 		SecurityOpt: permissionConfig.SecurityOpt,
 		Privileged:  permissionConfig.Privileged,
+		// The proxy process restarts the container according to the restart policy
+		// of the workload, so Docker must not restart it on its own.
 		RestartPolicy: container.RestartPolicy{
-			Name: "unless-stopped",
+			Name: container.RestartPolicyDisabled,
 		},
 	}
 	if additionalDNS != "" {
 		hostConfig.DNS = []string{additionalDNS}
 	}
 
+	// Apply resource limits
+	if err := setupResources(hostConfig, permissionConfig.Resources); err != nil {
+		return NewContainerError(err, "", err.Error())
+	}
+
 	// Configure ports if options are provided
 	// Setup exposed ports
 	if err := setupExposedPorts(config, exposedPorts); err != nil {
@@ -1506,6 +1595,43 @@ func (c *Client) createMcpContainer(
 
 }
 
+// setupResources applies CPU, memory and process limits to the host configuration,
+// and mounts a size-limited tmpfs at /tmp if requested.
+func setupResources(hostConfig *container.HostConfig, resources *permissions.ResourceLimits) error {
+	if resources.IsEmpty() {
+		return nil
+	}
+
+	nanoCPUs, err := resources.NanoCPUs()
+	if err != nil {
+		return err
+	}
+	memory, err := resources.MemoryBytes()
+	if err != nil {
+		return err
+	}
+	tmpfsSize, err := resources.TmpfsBytes()
+	if err != nil {
+		return err
+	}
+
+	hostConfig.CPUShares = resources.CPUShares
+	hostConfig.NanoCPUs = nanoCPUs
+	hostConfig.Memory = memory
+	if memory > 0 {
+		// Disable swap, so that the memory limit is a hard limit
+		hostConfig.MemorySwap = memory
+	}
+	if resources.PidsLimit > 0 {
+		pidsLimit := resources.PidsLimit
+		hostConfig.PidsLimit = &pidsLimit
+	}
+	if tmpfsSize > 0 {
+		hostConfig.Tmpfs = map[string]string{"/tmp": fmt.Sprintf("rw,nosuid,nodev,size=%d", tmpfsSize)}
+	}
+	return nil
+}
+
 // addEgressEnvVars adds environment variables for egress proxy configuration.
 func addEgressEnvVars(envVars map[string]string, egressContainerName string) map[string]string {
 	egressHost := fmt.Sprintf("http://%s:3128", egressContainerName)
@@ -10,6 +10,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/stacklok/toolhive/pkg/container/runtime"
+	"github.com/stacklok/toolhive/pkg/permissions"
 )
 
 func TestSetupExposedPorts_SetsPorts(t *testing.T) {
@@ -147,6 +148,38 @@ func TestCompareHostConfig_EqualAndMismatch(t *testing.T) {
 	assert.False(t, compareHostConfig(existing, desired))
 }
 
+func TestCompareResources(t *testing.T) {
+	t.Parallel()
+
+	desired := &container.HostConfig{}
+	require.NoError(t, setupResources(desired, &permissions.ResourceLimits{
+		CPUs: "1", Memory: "1Gi", PidsLimit: 64, TmpfsSize: "16Mi",
+	}))
+
+	existing := &container.HostConfig{}
+	require.NoError(t, setupResources(existing, &permissions.ResourceLimits{
+		CPUs: "1", Memory: "1Gi", PidsLimit: 64, TmpfsSize: "16Mi",
+	}))
+	assert.True(t, compareResources(existing, desired))
+
+	// Docker reports a missing pids limit as 0
+	zero := int64(0)
+	existing = &container.HostConfig{Resources: container.Resources{PidsLimit: &zero}}
+	assert.True(t, compareResources(existing, &container.HostConfig{}))
+
+	existing = &container.HostConfig{}
+	require.NoError(t, setupResources(existing, &permissions.ResourceLimits{
+		CPUs: "1", Memory: "2Gi", PidsLimit: 64, TmpfsSize: "16Mi",
+	}))
+	assert.False(t, compareResources(existing, desired))
+
+	existing = &container.HostConfig{}
+	require.NoError(t, setupResources(existing, &permissions.ResourceLimits{
+		CPUs: "1", Memory: "1Gi", PidsLimit: 64, TmpfsSize: "32Mi",
+	}))
+	assert.False(t, compareResources(existing, desired))
+}
+
 func TestComparePortConfig_EqualAndMismatch(t *testing.T) {
 	t.Parallel()
 
@@ -13,6 +13,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/stacklok/toolhive/pkg/container/runtime"
+	"github.com/stacklok/toolhive/pkg/permissions"
 )
 
 func TestCreateMcpContainer_Isolated_WiresConfigAndNetworks(t *testing.T) {
@@ -423,6 +424,82 @@ func TestCreateMcpContainer_NoAdditionalDNS_DNSNotSet(t *testing.T) {
 	assert.True(t, len(gotHost.DNS) == 0, "expected DNS to be empty when additionalDNS is not provided")
 }
 
+func TestCreateMcpContainer_ResourceLimits(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+
+	var gotHost *container.HostConfig
+
+	api := &fakeDockerAPI{
+		createFunc: func(_ context.Context, _ *container.Config, host *container.HostConfig, _ *network.NetworkingConfig, _ *v1.Platform, _ string) (container.CreateResponse, error) {
+			gotHost = host
+			return container.CreateResponse{ID: "cid-limits"}, nil
+		},
+		startFunc: func(_ context.Context, _ string, _ container.StartOptions) error {
+			return nil
+		},
+	}
+	c := &Client{api: api}
+
+	perm := &runtime.PermissionConfig{
+		Resources: &permissions.ResourceLimits{
+			CPUShares: 512,
+			CPUs:      "1.5",
+			Memory:    "512Mi",
+			PidsLimit: 100,
+			TmpfsSize: "64Mi",
+		},
+	}
+	err := c.createMcpContainer(
+		ctx,
+		"limited",
+		"",
+		"img",
+		nil,
+		nil,
+		map[string]string{"toolhive": "true"},
+		false,
+		perm,
+		"",
+		map[string]struct{}{},
+		map[string][]runtime.PortBinding{},
+		false,
+	)
+	require.NoError(t, err)
+	require.NotNil(t, gotHost)
+
+	assert.Equal(t, int64(512), gotHost.CPUShares)
+	assert.Equal(t, int64(1_500_000_000), gotHost.NanoCPUs)
+	assert.Equal(t, int64(512<<20), gotHost.Memory)
+	assert.Equal(t, int64(512<<20), gotHost.MemorySwap)
+	require.NotNil(t, gotHost.PidsLimit)
+	assert.Equal(t, int64(100), *gotHost.PidsLimit)
+	assert.Equal(t, map[string]string{"/tmp": "rw,nosuid,nodev,size=67108864"}, gotHost.Tmpfs)
+}
+
+func TestCreateMcpContainer_InvalidResourceLimits(t *testing.T) {
+	t.Parallel()
+
+	c := &Client{api: &fakeDockerAPI{}}
+	err := c.createMcpContainer(
+		t.Context(),
+		"limited",
+		"",
+		"img",
+		nil,
+		nil,
+		nil,
+		false,
+		&runtime.PermissionConfig{Resources: &permissions.ResourceLimits{Memory: "lots"}},
+		"",
+		map[string]struct{}{},
+		map[string][]runtime.PortBinding{},
+		false,
+	)
+	assert.ErrorContains(t, err, "invalid memory")
+}
+
 func TestCreateContainer_ListError_Propagates(t *testing.T) {
 	t.Parallel()
 
@@ -159,7 +159,9 @@ func (m *ContainerMonitor) monitor(ctx context.Context) {
 	}
 }
 
-// IsContainerNotFound checks if the error is a container not found error
+// IsContainerNotFound checks if the error is a container not found error.
+// The workload not found error of other runtimes is treated the same way.
 func IsContainerNotFound(err error) bool {
-	return errors.Is(err, ErrContainerNotFound) || (err != nil && err.Error() == ErrContainerNotFound.Error())
+	return errors.Is(err, ErrContainerNotFound) || errors.Is(err, runtime.ErrWorkloadNotFound) ||
+		(err != nil && err.Error() == ErrContainerNotFound.Error())
 }
@@ -3,6 +3,7 @@ package docker
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
@@ -125,6 +126,12 @@ func TestIsContainerNotFound(t *testing.T) {
 		assert.True(t, IsContainerNotFound(err))
 	})
 
+	t.Run("workload not found", func(t *testing.T) {
+		t.Parallel()
+		err := fmt.Errorf("%w: my-server", rt.ErrWorkloadNotFound)
+		assert.True(t, IsContainerNotFound(err))
+	})
+
 	t.Run("other", func(t *testing.T) {
 		t.Parallel()
 		assert.False(t, IsContainerNotFound(errors.New("different")))
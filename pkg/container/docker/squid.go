@@ -11,6 +11,7 @@ import (
 	"github.com/docker/docker/api/types/network"
 
 	"github.com/stacklok/toolhive/pkg/container/runtime"
+	"github.com/stacklok/toolhive/pkg/egress"
 	lb "github.com/stacklok/toolhive/pkg/labels"
 	"github.com/stacklok/toolhive/pkg/logger"
 	"github.com/stacklok/toolhive/pkg/permissions"
@@ -210,17 +211,21 @@ func createTempEgressSquidConf(
 
 func writeCommonConfig(sb *strings.Builder, hostnameBase string, direction proxyDirection) {
 	var serverHostname string
+	accessLogFormat := "squid"
 
 	if direction == proxyEgress {
 		serverHostname = hostnameBase + "-egress"
 		sb.WriteString("http_port 3128\n")
+		// The access log of the egress proxy is collected as audit events by the proxy process
+		sb.WriteString(egress.SquidLogFormat + "\n")
+		accessLogFormat = egress.SquidLogFormatName
 	} else {
 		serverHostname = hostnameBase + "-ingress"
 	}
 
 	sb.WriteString(
 		"visible_hostname " + serverHostname + "\n" +
-			"access_log stdio:/dev/stdout squid\n" +
+			"access_log stdio:/dev/stdout " + accessLogFormat + "\n" +
 			"pid_filename none\n" +
 			"# Avoid allocation errors caused by max_filedescriptors inference\n" +
 			"max_filedescriptors 1024\n" +
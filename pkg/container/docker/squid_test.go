@@ -148,6 +148,8 @@ func TestCreateTempEgressSquidConf_WithACLs(t *testing.T) {
 	s := string(b)
 
 	assert.Contains(t, s, "visible_hostname edge-egress")
+	assert.Contains(t, s, "logformat toolhive %ts.%03tu %>a %Ss %>Hs %rm %>rd %>rP %>st %<st\n")
+	assert.Contains(t, s, "access_log stdio:/dev/stdout toolhive\n")
 	assert.Contains(t, s, "# Define allowed ports\nacl allowed_ports port 80 443")
 	assert.Contains(t, s, "# Define allowed destinations\nacl allowed_dsts dstdomain example.com api.github.com")
 	assert.Contains(t, s, "\n# Define http_access rules\n")
@@ -171,6 +173,8 @@ func TestCreateTempIngressSquidConf_Basics(t *testing.T) {
 	s := string(b)
 
 	assert.Contains(t, s, "visible_hostname svc-example-ingress")
+	assert.Contains(t, s, "access_log stdio:/dev/stdout squid\n")
+	assert.NotContains(t, s, "logformat")
 	assert.Contains(t, s, "\n# Reverse proxy setup for port 8080\n")
 	assert.Contains(t, s, "http_port 0.0.0.0:18080 accel defaultsite=svc-example")
 	assert.Contains(t, s, "cache_peer svc-example parent 8080 0 no-query originserver name=origin_8080")
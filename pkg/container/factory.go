@@ -11,6 +11,7 @@ import (
 
 	"github.com/stacklok/toolhive/pkg/container/docker"
 	"github.com/stacklok/toolhive/pkg/container/kubernetes"
+	"github.com/stacklok/toolhive/pkg/container/process"
 	"github.com/stacklok/toolhive/pkg/container/runtime"
 )
 
@@ -46,7 +47,7 @@ func NewFactory() *Factory {
 	return f
 }
 
-// registerDefaultRuntimes registers the built-in docker and kubernetes runtimes
+// registerDefaultRuntimes registers the built-in docker, kubernetes and process runtimes
 func (f *Factory) registerDefaultRuntimes() {
 	// Register Docker runtime
 	if err := f.Register(&RuntimeInfo{
@@ -77,6 +78,21 @@ func (f *Factory) registerDefaultRuntimes() {
 		// This should never happen for built-in runtimes
 		panic(fmt.Sprintf("failed to register built-in runtime: %v", err))
 	}
+
+	// Register the process runtime for hosts without a container engine
+	if err := f.Register(&RuntimeInfo{
+		Name: process.RuntimeName,
+		Initializer: func(ctx context.Context) (runtime.Runtime, error) {
+			return process.NewClient(ctx)
+		},
+		AutoDetector: func() bool {
+			// The process runtime needs Landlock to enforce permission profiles
+			return process.IsAvailable()
+		},
+	}); err != nil {
+		// This should never happen for built-in runtimes
+		panic(fmt.Sprintf("failed to register built-in runtime: %v", err))
+	}
 }
 
 // Register registers a new runtime with the factory
@@ -140,7 +156,7 @@ func (f *Factory) ListAvailableRuntimes() map[string]*RuntimeInfo {
 }
 
 // autoDetectRuntime returns the first available runtime based on auto-detection
-// This checks runtimes in a predictable order: Docker first, then Kubernetes
+// This checks runtimes in a predictable order: Docker first, then Kubernetes, then the process runtime
 func (f *Factory) autoDetectRuntime() (string, *RuntimeInfo) {
 	available := f.ListAvailableRuntimes()
 
@@ -148,6 +164,7 @@ func (f *Factory) autoDetectRuntime() (string, *RuntimeInfo) {
 	preferredOrder := []string{
 		docker.RuntimeName,     // "docker"
 		kubernetes.RuntimeName, // "kubernetes"
+		process.RuntimeName,    // "process"
 	}
 
 	// Check runtimes in the preferred order
@@ -166,6 +183,16 @@ func (f *Factory) autoDetectRuntime() (string, *RuntimeInfo) {
 	return "", nil
 }
 
+// SelectedRuntimeName returns the name of the runtime that Create uses, or an
+// empty string if no runtime is available.
+func (f *Factory) SelectedRuntimeName() string {
+	if name := f.getRuntimeFromEnv(); name != "" {
+		return name
+	}
+	name, _ := f.autoDetectRuntime()
+	return name
+}
+
 // Clear removes all registered runtimes
 // This is useful for testing or when you want to start with a clean slate
 func (f *Factory) Clear() {
@@ -246,7 +273,7 @@ func CheckRuntimeAvailable() error {
 
 	if len(available) == 0 {
 		return fmt.Errorf("no container runtime available. ToolHive requires Docker, Podman, Colima, " +
-			"or a Kubernetes environment to run MCP servers")
+			"a Kubernetes environment, or a Linux kernel with Landlock for the process runtime to run MCP servers")
 	}
 
 	return nil
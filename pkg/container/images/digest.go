@@ -0,0 +1,111 @@
+package images
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+
+	"github.com/stacklok/toolhive/pkg/container/docker/sdk"
+	"github.com/stacklok/toolhive/pkg/container/runtime"
+)
+
+// DigestResolver resolves the digests identifying images, locally and in their registries.
+// An image is up to date when its local and remote digests have one in common.
+type DigestResolver interface {
+	// LocalDigests returns the digests of a local image: its ID and its repository digests
+	LocalDigests(ctx context.Context, imageID string) ([]string, error)
+
+	// RemoteDigests returns the digests of the image a reference points to in its registry:
+	// the digest of the manifest or index, of the platform manifest and of the image config
+	RemoteDigests(ctx context.Context, image string) ([]string, error)
+}
+
+// NewDigestResolver creates a DigestResolver for the current environment,
+// or returns an error if images are not managed locally.
+func NewDigestResolver(ctx context.Context) (DigestResolver, error) {
+	if runtime.IsKubernetesRuntime() {
+		return nil, errors.New("image digests are not resolved in Kubernetes")
+	}
+
+	dockerClient, _, _, err := sdk.NewDockerClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create docker client: %w", err)
+	}
+
+	return NewRegistryImageManager(dockerClient), nil
+}
+
+// LocalDigests returns the ID and the repository digests of a local image
+func (r *RegistryImageManager) LocalDigests(ctx context.Context, imageID string) ([]string, error) {
+	inspect, err := r.dockerClient.ImageInspect(ctx, imageID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect image %s: %w", imageID, err)
+	}
+
+	digests := []string{inspect.ID}
+	for _, repoDigest := range inspect.RepoDigests {
+		// Repository digests have the form repository@sha256:...
+		if _, digest, ok := strings.Cut(repoDigest, "@"); ok && !slices.Contains(digests, digest) {
+			digests = append(digests, digest)
+		}
+	}
+	return digests, nil
+}
+
+// RemoteDigests returns the digests of the image a reference points to in its registry,
+// for the platform of the image manager
+func (r *RegistryImageManager) RemoteDigests(ctx context.Context, image string) ([]string, error) {
+	ref, err := name.ParseReference(image)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse image reference %q: %w", image, err)
+	}
+
+	remoteOpts := []remote.Option{
+		remote.WithAuthFromKeychain(r.keychain),
+		remote.WithContext(ctx),
+	}
+	if r.platform != nil {
+		remoteOpts = append(remoteOpts, remote.WithPlatform(*r.platform))
+	}
+
+	descriptor, err := remote.Get(ref, remoteOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get image %s from registry: %w", image, err)
+	}
+	digests := []string{descriptor.Digest.String()}
+
+	// Image resolves an index to the manifest of the platform
+	img, err := descriptor.Image()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get image %s for the platform: %w", image, err)
+	}
+	manifestDigest, err := img.Digest()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get manifest digest of image %s: %w", image, err)
+	}
+	configDigest, err := img.ConfigName()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get config digest of image %s: %w", image, err)
+	}
+	for _, digest := range []string{manifestDigest.String(), configDigest.String()} {
+		if !slices.Contains(digests, digest) {
+			digests = append(digests, digest)
+		}
+	}
+	return digests, nil
+}
+
+// IsUpToDate returns true if the local and remote digests of an image have one in common
+func IsUpToDate(localDigests, remoteDigests []string) bool {
+	for _, digest := range remoteDigests {
+		if slices.Contains(localDigests, digest) {
+			return true
+		}
+	}
+	return false
+}
@@ -297,7 +297,7 @@ func (c *Client) DeployWorkload(ctx context.Context,
 	command []string,
 	envVars map[string]string,
 	containerLabels map[string]string,
-	_ *permissions.Profile, // TODO: Implement permission profile support for Kubernetes
+	permissionProfile *permissions.Profile, // TODO: Implement mount and network permissions for Kubernetes
 	transportType string,
 	options *runtime.DeployWorkloadOptions,
 	_ bool,
@@ -357,6 +357,13 @@ func (c *Client) DeployWorkload(ctx context.Context,
 		return 0, err
 	}
 
+	// Apply the resource limits of the permission profile
+	if permissionProfile != nil {
+		if err := configureResourceLimits(podTemplateSpec, permissionProfile.Resources); err != nil {
+			return 0, err
+		}
+	}
+
 	// Create an apply configuration for the statefulset
 	statefulSetApply := appsv1apply.StatefulSet(containerName, namespace).
 		WithLabels(containerLabels).
@@ -0,0 +1,120 @@
+package kubernetes
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	corev1apply "k8s.io/client-go/applyconfigurations/core/v1"
+
+	"github.com/stacklok/toolhive/pkg/logger"
+	"github.com/stacklok/toolhive/pkg/permissions"
+)
+
+// tmpVolumeName is the name of the memory-backed volume mounted at /tmp
+const tmpVolumeName = "tmp"
+
+// buildResourceRequirements converts the resource limits of a permission profile into
+// Kubernetes container resources. CPU shares become a CPU request, as Kubernetes derives
+// the CPU weight of a container from its request. Process and tmpfs limits have no
+// equivalent in container resources and are ignored.
+func buildResourceRequirements(limits *permissions.ResourceLimits) (corev1.ResourceRequirements, error) {
+	var requirements corev1.ResourceRequirements
+	if limits.IsEmpty() {
+		return requirements, nil
+	}
+	if err := limits.Validate(); err != nil {
+		return requirements, fmt.Errorf("invalid resource limits: %w", err)
+	}
+
+	if limits.CPUs != "" || limits.Memory != "" {
+		requirements.Limits = corev1.ResourceList{}
+		if limits.CPUs != "" {
+			requirements.Limits[corev1.ResourceCPU] = resource.MustParse(limits.CPUs)
+		}
+		if limits.Memory != "" {
+			requirements.Limits[corev1.ResourceMemory] = resource.MustParse(limits.Memory)
+		}
+	}
+
+	if millis := limits.CPURequestMillis(); millis > 0 {
+		request := *resource.NewMilliQuantity(millis, resource.DecimalSI)
+		// A request above the limit is rejected by the API server
+		if limit, ok := requirements.Limits[corev1.ResourceCPU]; ok && request.Cmp(limit) > 0 {
+			request = limit
+		}
+		requirements.Requests = corev1.ResourceList{corev1.ResourceCPU: request}
+	}
+
+	return requirements, nil
+}
+
+// configureResourceLimits applies the resource limits of a permission profile to the MCP
+// container. Resources set by the pod template patch take precedence.
+func configureResourceLimits(
+	podTemplateSpec *corev1apply.PodTemplateSpecApplyConfiguration,
+	limits *permissions.ResourceLimits,
+) error {
+	if limits.IsEmpty() {
+		return nil
+	}
+	mcpContainer := getMCPContainer(podTemplateSpec)
+	if mcpContainer == nil {
+		return fmt.Errorf("%s container not found in pod template", mcpContainerName)
+	}
+
+	requirements, err := buildResourceRequirements(limits)
+	if err != nil {
+		return err
+	}
+	if mcpContainer.Resources != nil {
+		logger.Infof("Using the resources of the pod template patch instead of the permission profile")
+	} else if len(requirements.Limits) > 0 || len(requirements.Requests) > 0 {
+		resources := corev1apply.ResourceRequirements()
+		if len(requirements.Limits) > 0 {
+			resources.WithLimits(requirements.Limits)
+		}
+		if len(requirements.Requests) > 0 {
+			resources.WithRequests(requirements.Requests)
+		}
+		mcpContainer.WithResources(resources)
+	}
+
+	if limits.PidsLimit > 0 {
+		logger.Warnf("The pids limit of the permission profile is not supported by the Kubernetes runtime; " +
+			"the pod pids limit of the kubelet applies")
+	}
+
+	tmpfsSize, err := limits.TmpfsBytes()
+	if err != nil {
+		return err
+	}
+	if tmpfsSize > 0 {
+		configureTmpfs(podTemplateSpec, mcpContainer, tmpfsSize)
+	}
+	return nil
+}
+
+// configureTmpfs mounts a size-limited, memory-backed volume at /tmp in the MCP container,
+// unless the pod template patch already mounts something there.
+func configureTmpfs(
+	podTemplateSpec *corev1apply.PodTemplateSpecApplyConfiguration,
+	mcpContainer *corev1apply.ContainerApplyConfiguration,
+	size int64,
+) {
+	for _, mount := range mcpContainer.VolumeMounts {
+		if mount.MountPath != nil && *mount.MountPath == "/tmp" {
+			logger.Infof("Using the /tmp mount of the pod template patch instead of the permission profile")
+			return
+		}
+	}
+
+	podTemplateSpec.Spec.WithVolumes(corev1apply.Volume().
+		WithName(tmpVolumeName).
+		WithEmptyDir(corev1apply.EmptyDirVolumeSource().
+			WithMedium(corev1.StorageMediumMemory).
+			WithSizeLimit(*resource.NewQuantity(size, resource.BinarySI))))
+	mcpContainer.WithVolumeMounts(corev1apply.VolumeMount().
+		WithName(tmpVolumeName).
+		WithMountPath("/tmp"))
+}
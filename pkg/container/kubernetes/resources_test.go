@@ -0,0 +1,157 @@
+package kubernetes
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1apply "k8s.io/client-go/applyconfigurations/core/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+
+	"github.com/stacklok/toolhive/pkg/container/runtime"
+	"github.com/stacklok/toolhive/pkg/permissions"
+)
+
+func TestBuildResourceRequirements(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		limits   *permissions.ResourceLimits
+		expected corev1.ResourceRequirements
+		wantErr  string
+	}{
+		{
+			name: "nil limits",
+		},
+		{
+			name:   "cpu and memory limits",
+			limits: &permissions.ResourceLimits{CPUs: "1.5", Memory: "512Mi"},
+			expected: corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("1.5"),
+					corev1.ResourceMemory: resource.MustParse("512Mi"),
+				},
+			},
+		},
+		{
+			name:   "cpu shares become a cpu request",
+			limits: &permissions.ResourceLimits{CPUShares: 512, PidsLimit: 100},
+			expected: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")},
+			},
+		},
+		{
+			name:   "cpu request is capped at the limit",
+			limits: &permissions.ResourceLimits{CPUShares: 2048, CPUs: "1"},
+			expected: corev1.ResourceRequirements{
+				Limits:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+			},
+		},
+		{
+			name:    "invalid limits",
+			limits:  &permissions.ResourceLimits{CPUs: "fast"},
+			wantErr: "invalid resource limits",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			requirements, err := buildResourceRequirements(tt.limits)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, equalResourceLists(tt.expected.Limits, requirements.Limits), "limits: %v", requirements.Limits)
+			assert.True(t, equalResourceLists(tt.expected.Requests, requirements.Requests), "requests: %v", requirements.Requests)
+		})
+	}
+}
+
+// equalResourceLists compares resource lists by value, as quantities keep their original format.
+func equalResourceLists(expected, actual corev1.ResourceList) bool {
+	if len(expected) != len(actual) {
+		return false
+	}
+	for name, quantity := range expected {
+		if other, ok := actual[name]; !ok || quantity.Cmp(other) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func TestConfigureResourceLimits_PatchTakesPrecedence(t *testing.T) {
+	t.Parallel()
+
+	podTemplateSpec := corev1apply.PodTemplateSpec().WithSpec(corev1apply.PodSpec().WithContainers(
+		corev1apply.Container().
+			WithName(mcpContainerName).
+			WithResources(corev1apply.ResourceRequirements().WithLimits(corev1.ResourceList{
+				corev1.ResourceMemory: resource.MustParse("1Gi"),
+			})).
+			WithVolumeMounts(corev1apply.VolumeMount().WithName("scratch").WithMountPath("/tmp")),
+	))
+
+	err := configureResourceLimits(podTemplateSpec, &permissions.ResourceLimits{Memory: "256Mi", TmpfsSize: "64Mi"})
+	require.NoError(t, err)
+
+	mcpContainer := getMCPContainer(podTemplateSpec)
+	memory := (*mcpContainer.Resources.Limits)[corev1.ResourceMemory]
+	assert.Equal(t, "1Gi", memory.String())
+	assert.Len(t, mcpContainer.VolumeMounts, 1)
+	assert.Empty(t, podTemplateSpec.Spec.Volumes)
+}
+
+func TestDeployWorkload_ResourceLimits(t *testing.T) {
+	t.Parallel()
+
+	clientset := fake.NewClientset()
+	client := NewClientWithConfigAndPlatformDetector(clientset, &rest.Config{Host: "https://fake-k8s-api.example.com"},
+		&mockPlatformDetector{platform: PlatformKubernetes})
+	client.waitForStatefulSetReadyFunc = mockWaitForStatefulSetReady
+	client.namespaceFunc = func() string { return defaultNamespace }
+
+	profile := permissions.BuiltinNoneProfile()
+	profile.Resources = &permissions.ResourceLimits{CPUs: "500m", Memory: "256Mi", TmpfsSize: "32Mi"}
+
+	_, err := client.DeployWorkload(
+		context.Background(),
+		"test-image",
+		"limited",
+		nil,
+		map[string]string{},
+		map[string]string{},
+		profile,
+		"stdio",
+		runtime.NewDeployWorkloadOptions(),
+		false,
+	)
+	require.NoError(t, err)
+
+	statefulSet, err := clientset.AppsV1().StatefulSets(defaultNamespace).Get(
+		context.Background(), "limited", metav1.GetOptions{})
+	require.NoError(t, err)
+
+	podSpec := statefulSet.Spec.Template.Spec
+	require.Len(t, podSpec.Containers, 1)
+	mcpContainer := podSpec.Containers[0]
+	assert.Equal(t, "500m", mcpContainer.Resources.Limits.Cpu().String())
+	assert.Equal(t, "256Mi", mcpContainer.Resources.Limits.Memory().String())
+
+	require.Len(t, podSpec.Volumes, 1)
+	assert.Equal(t, tmpVolumeName, podSpec.Volumes[0].Name)
+	require.NotNil(t, podSpec.Volumes[0].EmptyDir)
+	assert.Equal(t, corev1.StorageMediumMemory, podSpec.Volumes[0].EmptyDir.Medium)
+	assert.Equal(t, int64(32<<20), podSpec.Volumes[0].EmptyDir.SizeLimit.Value())
+	assert.Equal(t, []corev1.VolumeMount{{Name: tmpVolumeName, MountPath: "/tmp"}}, mcpContainer.VolumeMounts)
+}
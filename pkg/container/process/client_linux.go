@@ -0,0 +1,624 @@
+package process
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/stacklok/toolhive/pkg/container/runtime"
+	lb "github.com/stacklok/toolhive/pkg/labels"
+	"github.com/stacklok/toolhive/pkg/logger"
+	"github.com/stacklok/toolhive/pkg/permissions"
+)
+
+const (
+	// stopTimeout is how long processes get to exit after SIGTERM before they are killed.
+	stopTimeout = 30 * time.Second
+
+	// logTailLines is the number of log lines returned by GetWorkloadLogs.
+	logTailLines = 100
+
+	// pollInterval is how often process state and logs are polled.
+	pollInterval = 100 * time.Millisecond
+)
+
+// IsAvailable checks if the process runtime can confine processes on this host.
+func IsAvailable() bool {
+	return landlockABI() >= 1
+}
+
+// Client implements the runtime.Runtime interface by running workloads as
+// sandboxed host processes.
+type Client struct {
+	baseDir     string
+	landlockABI int
+	// exePath is the executable started as the sandbox helper
+	exePath string
+}
+
+// NewClient creates a new process runtime client
+func NewClient(_ context.Context) (*Client, error) {
+	abi := landlockABI()
+	if abi < 1 {
+		return nil, fmt.Errorf("the %s runtime requires a Linux kernel with Landlock enabled", RuntimeName)
+	}
+	return newClient(stateDir(), abi)
+}
+
+func newClient(baseDir string, abi int) (*Client, error) {
+	if err := os.MkdirAll(baseDir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create state directory: %w", err)
+	}
+	return &Client{baseDir: baseDir, landlockABI: abi, exePath: "/proc/self/exe"}, nil
+}
+
+// DeployWorkload starts the MCP server as a sandboxed host process.
+// An existing process for the workload is stopped and replaced.
+func (c *Client) DeployWorkload(
+	ctx context.Context,
+	image,
+	name string,
+	command []string,
+	envVars,
+	labels map[string]string,
+	permissionProfile *permissions.Profile,
+	transportType string,
+	options *runtime.DeployWorkloadOptions,
+	isolateNetwork bool,
+) (int, error) {
+	cmd, err := resolveCommand(image, command)
+	if err != nil {
+		return 0, err
+	}
+	return c.deploy(ctx, image, name, cmd, envVars, labels, permissionProfile, transportType, options, isolateNetwork)
+}
+
+func (c *Client) deploy(
+	ctx context.Context,
+	image,
+	name string,
+	cmd *command,
+	envVars,
+	labels map[string]string,
+	permissionProfile *permissions.Profile,
+	transportType string,
+	options *runtime.DeployWorkloadOptions,
+	isolateNetwork bool,
+) (int, error) {
+	dir, err := workloadDir(c.baseDir, name)
+	if err != nil {
+		return 0, err
+	}
+	if err := c.StopWorkload(ctx, name); err != nil {
+		return 0, fmt.Errorf("failed to stop existing workload: %w", err)
+	}
+	if err := prepareWorkloadDir(dir); err != nil {
+		return 0, err
+	}
+
+	stdio := transportType == "stdio"
+	var ports []int
+	if !stdio {
+		if ports, err = exposedPorts(options); err != nil {
+			return 0, err
+		}
+	}
+
+	spec, newNetNS, err := newSandboxSpec(permissionProfile, cmd, dir, transportType, ports, isolateNetwork, c.landlockABI)
+	if err != nil {
+		return 0, err
+	}
+	if permissionProfile != nil && !permissionProfile.Resources.IsEmpty() {
+		logger.Warnf("Resource limits are not enforced by the %s runtime; workload %s runs without them", RuntimeName, name)
+	}
+
+	execCmd := exec.Command(cmd.Path) //nolint:gosec // the command is resolved from the workload image
+	execCmd.Args = cmd.Args
+	if spec != nil {
+		specPath := filepath.Join(dir, sandboxFile)
+		data, err := json.Marshal(spec)
+		if err != nil {
+			return 0, fmt.Errorf("failed to encode sandbox spec: %w", err)
+		}
+		if err := os.WriteFile(specPath, data, 0600); err != nil {
+			return 0, fmt.Errorf("failed to write sandbox spec: %w", err)
+		}
+		execCmd.Path = c.exePath
+		execCmd.Args = []string{sandboxInitArg0, specPath}
+	}
+	execCmd.Dir = cmd.Dir
+	if execCmd.Dir == "" {
+		execCmd.Dir = filepath.Join(dir, homeDir)
+	}
+	execCmd.Env = processEnv(dir, envVars)
+	execCmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	if spec != nil {
+		// An unprivileged user namespace allows creating the other namespaces. The mount
+		// and PID namespaces give the workload a private procfs, and a network namespace
+		// only contains a loopback interface.
+		uid, gid := os.Getuid(), os.Getgid()
+		execCmd.SysProcAttr.Cloneflags = syscall.CLONE_NEWUSER | syscall.CLONE_NEWNS | syscall.CLONE_NEWPID |
+			syscall.CLONE_NEWIPC | syscall.CLONE_NEWUTS
+		if newNetNS {
+			execCmd.SysProcAttr.Cloneflags |= syscall.CLONE_NEWNET
+		}
+		execCmd.SysProcAttr.UidMappings = []syscall.SysProcIDMap{{ContainerID: uid, HostID: uid, Size: 1}}
+		execCmd.SysProcAttr.GidMappings = []syscall.SysProcIDMap{{ContainerID: gid, HostID: gid, Size: 1}}
+	}
+
+	files, err := c.attachFiles(dir, execCmd, stdio)
+	if err != nil {
+		return 0, err
+	}
+	defer closeAll(files)
+
+	if err := execCmd.Start(); err != nil {
+		if spec != nil {
+			return 0, fmt.Errorf("failed to start %s in the sandbox namespaces, unprivileged user namespaces may be disabled: %w",
+				cmd.Args[0], err)
+		}
+		return 0, fmt.Errorf("failed to start %s: %w", cmd.Args[0], err)
+	}
+
+	pid := execCmd.Process.Pid
+	_, startTime, err := procStat(pid)
+	if err != nil {
+		logger.Warnf("Failed to read start time of process %d: %v", pid, err)
+	}
+
+	// Add a label to the MCP server indicating network isolation, as for containers
+	lb.AddNetworkIsolationLabel(labels, isolateNetwork)
+	if spec != nil {
+		labels[lb.LabelSandbox] = lb.LabelToolHiveValue
+	}
+
+	now := time.Now()
+	w := &workload{
+		Name:         name,
+		Image:        image,
+		Command:      cmd.Args,
+		Labels:       labels,
+		Ports:        ports,
+		Stdio:        stdio,
+		Created:      now,
+		StartedAt:    now,
+		PID:          pid,
+		PIDStartTime: startTime,
+	}
+	if err := writeWorkload(dir, w); err != nil {
+		_ = syscall.Kill(-pid, syscall.SIGKILL)
+		_ = execCmd.Wait()
+		return 0, fmt.Errorf("failed to save workload state: %w", err)
+	}
+	go c.reap(dir, execCmd)
+
+	if stdio {
+		return 0, nil
+	}
+	// The server listens on the target port on the host
+	return ports[0], nil
+}
+
+// prepareWorkloadDir removes the state of a previous run of the workload, keeping
+// its home directory so that package caches survive restarts.
+func prepareWorkloadDir(dir string) error {
+	for _, file := range []string{workloadFile, sandboxFile, stdinFile, stdoutFile} {
+		if err := os.Remove(filepath.Join(dir, file)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove %s: %w", file, err)
+		}
+	}
+	for _, sub := range []string{homeDir, tmpDir} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0700); err != nil {
+			return fmt.Errorf("failed to create workload directory: %w", err)
+		}
+	}
+	return nil
+}
+
+// attachFiles connects the standard streams of the process. Stdio workloads get
+// FIFOs that AttachToWorkload opens later; stderr, and stdout of other workloads,
+// go to the log file. The returned files must be closed once the process started.
+func (*Client) attachFiles(dir string, execCmd *exec.Cmd, stdio bool) ([]*os.File, error) {
+	logFile, err := os.OpenFile(filepath.Join(dir, stderrFile), os.O_CREATE|os.O_WRONLY|os.O_TRUNC|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create log file: %w", err)
+	}
+	files := []*os.File{logFile}
+	execCmd.Stderr = logFile
+	if !stdio {
+		execCmd.Stdout = logFile
+		return files, nil
+	}
+
+	for _, fifo := range []string{stdinFile, stdoutFile} {
+		path := filepath.Join(dir, fifo)
+		if err := unix.Mkfifo(path, 0600); err != nil {
+			closeAll(files)
+			return nil, fmt.Errorf("failed to create %s pipe: %w", fifo, err)
+		}
+		// Opening read-write does not block, and keeps the pipe open while
+		// no client is attached
+		f, err := os.OpenFile(path, os.O_RDWR, 0)
+		if err != nil {
+			closeAll(files)
+			return nil, fmt.Errorf("failed to open %s pipe: %w", fifo, err)
+		}
+		files = append(files, f)
+	}
+	execCmd.Stdin = files[1]
+	execCmd.Stdout = files[2]
+	return files, nil
+}
+
+// reap waits for a process started by this client and records its exit.
+func (*Client) reap(dir string, execCmd *exec.Cmd) {
+	_ = execCmd.Wait()
+
+	w, err := readWorkload(dir)
+	if err != nil || w.PID != execCmd.Process.Pid {
+		// The workload was removed or replaced
+		return
+	}
+	finishedAt := time.Now()
+	exitCode := execCmd.ProcessState.ExitCode()
+	w.FinishedAt = &finishedAt
+	w.ExitCode = &exitCode
+	if err := writeWorkload(dir, w); err != nil {
+		logger.Debugf("Failed to record exit of workload %s: %v", w.Name, err)
+	}
+}
+
+// ListWorkloads lists the workloads of this runtime
+func (c *Client) ListWorkloads(_ context.Context) ([]runtime.ContainerInfo, error) {
+	entries, err := os.ReadDir(c.baseDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workloads: %w", err)
+	}
+
+	result := make([]runtime.ContainerInfo, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		w, err := readWorkload(filepath.Join(c.baseDir, entry.Name()))
+		if err != nil {
+			logger.Debugf("Skipping process workload %s: %v", entry.Name(), err)
+			continue
+		}
+		result = append(result, workloadInfo(w))
+	}
+	return result, nil
+}
+
+// StopWorkload stops the process group of a workload with SIGTERM, and with
+// SIGKILL if it is still running after 30 seconds.
+// If the workload doesn't exist or is not running, it returns success.
+func (c *Client) StopWorkload(ctx context.Context, workloadName string) error {
+	w, err := c.load(workloadName)
+	if err != nil {
+		if errors.Is(err, runtime.ErrWorkloadNotFound) {
+			return nil
+		}
+		return err
+	}
+	if !isAlive(w) {
+		return nil
+	}
+
+	if err := syscall.Kill(-w.PID, syscall.SIGTERM); err != nil && !errors.Is(err, syscall.ESRCH) {
+		return fmt.Errorf("failed to stop workload %s: %w", workloadName, err)
+	}
+	if waitForExit(ctx, w, stopTimeout) {
+		return nil
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	logger.Warnf("Workload %s did not exit after %s, killing it", workloadName, stopTimeout)
+	if err := syscall.Kill(-w.PID, syscall.SIGKILL); err != nil && !errors.Is(err, syscall.ESRCH) {
+		return fmt.Errorf("failed to kill workload %s: %w", workloadName, err)
+	}
+	if !waitForExit(ctx, w, 5*time.Second) {
+		return fmt.Errorf("workload %s is still running after being killed", workloadName)
+	}
+	return nil
+}
+
+// waitForExit polls until the process of the workload exits or the timeout expires.
+func waitForExit(ctx context.Context, w *workload, timeout time.Duration) bool {
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for isAlive(w) {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-deadline.C:
+			return false
+		case <-ticker.C:
+		}
+	}
+	return true
+}
+
+// RemoveWorkload stops a workload and removes its state.
+// If the workload doesn't exist, it returns success.
+func (c *Client) RemoveWorkload(ctx context.Context, workloadName string) error {
+	dir, err := workloadDir(c.baseDir, workloadName)
+	if err != nil {
+		return err
+	}
+	if err := c.StopWorkload(ctx, workloadName); err != nil {
+		return err
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("failed to remove workload %s: %w", workloadName, err)
+	}
+	return nil
+}
+
+// GetWorkloadLogs returns the last lines written to stderr by the workload, and
+// stdout for workloads that do not use the stdio transport.
+// If follow is true, new lines are written to stdout until the workload exits.
+func (c *Client) GetWorkloadLogs(ctx context.Context, workloadName string, follow bool) (string, error) {
+	w, err := c.load(workloadName)
+	if err != nil {
+		return "", err
+	}
+	dir, _ := workloadDir(c.baseDir, workloadName)
+	logPath := filepath.Join(dir, stderrFile)
+
+	data, err := os.ReadFile(logPath)
+	if err != nil && !os.IsNotExist(err) {
+		return "", fmt.Errorf("failed to read logs of workload %s: %w", workloadName, err)
+	}
+	logs := tailLines(data, logTailLines)
+	if !follow {
+		return logs, nil
+	}
+
+	if _, err := io.WriteString(os.Stdout, logs); err != nil {
+		return "", err
+	}
+	return "", followLog(ctx, w, logPath, int64(len(data)))
+}
+
+// followLog copies data appended to the log file after offset to stdout until
+// the workload exits or ctx is canceled.
+func followLog(ctx context.Context, w *workload, logPath string, offset int64) error {
+	f, err := os.Open(logPath) //nolint:gosec // the path is within the workload directory
+	if err != nil {
+		return fmt.Errorf("failed to open logs: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to read logs: %w", err)
+	}
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		alive := isAlive(w)
+		if _, err := io.Copy(os.Stdout, f); err != nil {
+			return fmt.Errorf("failed to read logs: %w", err)
+		}
+		if !alive {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// tailLines returns the last n lines of data.
+func tailLines(data []byte, n int) string {
+	data = bytes.TrimSuffix(data, []byte("\n"))
+	if len(data) == 0 {
+		return ""
+	}
+	start := len(data)
+	for i := 0; i < n; i++ {
+		idx := bytes.LastIndexByte(data[:start], '\n')
+		if idx < 0 {
+			return string(data) + "\n"
+		}
+		start = idx
+	}
+	return string(data[start+1:]) + "\n"
+}
+
+// IsWorkloadRunning checks if the process of a workload is running
+func (c *Client) IsWorkloadRunning(_ context.Context, workloadName string) (bool, error) {
+	w, err := c.load(workloadName)
+	if err != nil {
+		return false, err
+	}
+	return isAlive(w), nil
+}
+
+// GetWorkloadInfo gets workload information
+func (c *Client) GetWorkloadInfo(_ context.Context, workloadName string) (runtime.ContainerInfo, error) {
+	w, err := c.load(workloadName)
+	if err != nil {
+		return runtime.ContainerInfo{}, err
+	}
+	return workloadInfo(w), nil
+}
+
+// AttachToWorkload opens the stdin and stdout pipes of a stdio workload.
+func (c *Client) AttachToWorkload(_ context.Context, workloadName string) (io.WriteCloser, io.ReadCloser, error) {
+	w, err := c.load(workloadName)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !isAlive(w) {
+		return nil, nil, fmt.Errorf("workload %s is not running", workloadName)
+	}
+	if !w.Stdio {
+		return nil, nil, fmt.Errorf("workload %s does not use the stdio transport", workloadName)
+	}
+
+	dir, _ := workloadDir(c.baseDir, workloadName)
+	// Non-blocking opens fail instead of hanging if the process has exited
+	stdin, err := os.OpenFile(filepath.Join(dir, stdinFile), os.O_WRONLY|syscall.O_NONBLOCK, 0)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to attach to workload %s: %w", workloadName, err)
+	}
+	stdout, err := os.OpenFile(filepath.Join(dir, stdoutFile), os.O_RDONLY|syscall.O_NONBLOCK, 0)
+	if err != nil {
+		_ = stdin.Close()
+		return nil, nil, fmt.Errorf("failed to attach to workload %s: %w", workloadName, err)
+	}
+	return stdin, stdout, nil
+}
+
+// IsRunning checks the health of the runtime.
+func (c *Client) IsRunning(_ context.Context) error {
+	if _, err := os.Stat(c.baseDir); err != nil {
+		return fmt.Errorf("process runtime state directory is not accessible: %w", err)
+	}
+	return nil
+}
+
+// load reads the state of a workload.
+func (c *Client) load(workloadName string) (*workload, error) {
+	dir, err := workloadDir(c.baseDir, workloadName)
+	if err != nil {
+		return nil, err
+	}
+	w, err := readWorkload(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: %s", runtime.ErrWorkloadNotFound, workloadName)
+		}
+		return nil, fmt.Errorf("failed to read workload %s: %w", workloadName, err)
+	}
+	return w, nil
+}
+
+// workloadInfo converts the state of a workload to container information.
+func workloadInfo(w *workload) runtime.ContainerInfo {
+	info := runtime.ContainerInfo{
+		Name:      w.Name,
+		Image:     w.Image,
+		Status:    "running",
+		State:     runtime.WorkloadStatusRunning,
+		Created:   w.Created,
+		StartedAt: w.StartedAt,
+		Labels:    w.Labels,
+		Ports:     make([]runtime.PortMapping, 0, len(w.Ports)),
+	}
+	if !isAlive(w) {
+		info.State = runtime.WorkloadStatusStopped
+		info.Status = "exited"
+		if w.ExitCode != nil {
+			info.Status = fmt.Sprintf("exited (%d)", *w.ExitCode)
+			info.ExitCode = *w.ExitCode
+		}
+	}
+	for _, port := range w.Ports {
+		info.Ports = append(info.Ports, runtime.PortMapping{ContainerPort: port, HostPort: port, Protocol: "tcp"})
+	}
+	return info
+}
+
+// isAlive returns true if the process of a workload is running. The start time
+// guards against the PID having been reused by an unrelated process.
+func isAlive(w *workload) bool {
+	if w.PID <= 0 {
+		return false
+	}
+	state, startTime, err := procStat(w.PID)
+	if err != nil {
+		return false
+	}
+	if state == 'Z' || state == 'X' {
+		return false
+	}
+	return w.PIDStartTime == 0 || startTime == w.PIDStartTime
+}
+
+// procStat returns the state and start time of a process from /proc/<pid>/stat.
+func procStat(pid int) (byte, uint64, error) {
+	data, err := os.ReadFile(filepath.Join("/proc", strconv.Itoa(pid), "stat"))
+	if err != nil {
+		return 0, 0, err
+	}
+	// The command name is in parentheses and may contain spaces
+	end := bytes.LastIndexByte(data, ')')
+	if end < 0 {
+		return 0, 0, fmt.Errorf("unexpected format of /proc/%d/stat", pid)
+	}
+	// Fields after the command name start at field 3 (state); start time is field 22
+	fields := strings.Fields(string(data[end+1:]))
+	if len(fields) < 20 {
+		return 0, 0, fmt.Errorf("unexpected format of /proc/%d/stat", pid)
+	}
+	startTime, err := strconv.ParseUint(fields[19], 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("unexpected format of /proc/%d/stat: %w", pid, err)
+	}
+	return fields[0][0], startTime, nil
+}
+
+// exposedPorts returns the container ports of the deployment options. Processes
+// listen on the host directly, so container and host ports are the same.
+func exposedPorts(options *runtime.DeployWorkloadOptions) ([]int, error) {
+	if options == nil || len(options.ExposedPorts) == 0 {
+		return nil, fmt.Errorf("no exposed ports specified in options.ExposedPorts")
+	}
+	ports := make([]int, 0, len(options.ExposedPorts))
+	for exposed := range options.ExposedPorts {
+		port, err := strconv.Atoi(strings.Split(exposed, "/")[0])
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert port %s to int: %w", exposed, err)
+		}
+		ports = append(ports, port)
+	}
+	sort.Ints(ports)
+	return ports, nil
+}
+
+// processEnv returns the environment of a workload process. Like a container, it
+// does not inherit the environment of ToolHive, except for PATH.
+func processEnv(dir string, envVars map[string]string) []string {
+	env := []string{
+		"PATH=" + os.Getenv("PATH"),
+		"HOME=" + filepath.Join(dir, homeDir),
+		"TMPDIR=" + filepath.Join(dir, tmpDir),
+	}
+	keys := make([]string, 0, len(envVars))
+	for key := range envVars {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		env = append(env, key+"="+envVars[key])
+	}
+	return env
+}
+
+func closeAll(files []*os.File) {
+	for _, f := range files {
+		_ = f.Close()
+	}
+}
@@ -0,0 +1,364 @@
+package process
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stacklok/toolhive/pkg/container/runtime"
+	"github.com/stacklok/toolhive/pkg/permissions"
+)
+
+// unixConnectArg0 runs the test binary as a workload that connects to a UNIX socket.
+const unixConnectArg0 = "toolhive-test-unix-connect"
+
+// TestMain lets the test binary act as the sandbox helper, as cmd/thv does.
+func TestMain(m *testing.M) {
+	if len(os.Args) == 2 && os.Args[0] == unixConnectArg0 {
+		conn, err := net.Dial("unix", os.Args[1])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "connect-denied: %v\n", err)
+			os.Exit(0)
+		}
+		_ = conn.Close()
+		fmt.Fprintln(os.Stderr, "connect-allowed")
+		os.Exit(0)
+	}
+	Init()
+	os.Exit(m.Run())
+}
+
+func newTestClient(t *testing.T) *Client {
+	t.Helper()
+
+	if !IsAvailable() {
+		t.Skip("Landlock is not available")
+	}
+	c, err := newClient(t.TempDir(), landlockABI())
+	require.NoError(t, err)
+	return c
+}
+
+func shellCommand(script string) *command {
+	return &command{Path: "/bin/sh", Args: []string{"sh", "-c", script}}
+}
+
+func readLog(t *testing.T, c *Client, name string) string {
+	t.Helper()
+
+	logs, err := c.GetWorkloadLogs(context.Background(), name, false)
+	require.NoError(t, err)
+	return logs
+}
+
+func TestClient_StdioWorkload(t *testing.T) {
+	t.Parallel()
+
+	c := newTestClient(t)
+	ctx := context.Background()
+	outside := filepath.Join(t.TempDir(), "outside")
+
+	script := `echo x > ` + outside + ` 2>/dev/null && echo outside-allowed >&2 || echo outside-denied >&2
+echo x > "$HOME/inside" && echo inside-allowed >&2
+grep -E '^(NoNewPrivs|Seccomp):' /proc/self/status >&2
+read line; echo "got $line"; sleep 60`
+	labels := map[string]string{"toolhive": "true", "toolhive-name": "echo"}
+	port, err := c.deploy(ctx, "npx://echo", "echo", shellCommand(script), map[string]string{"GREETING": "hi"},
+		labels, permissions.BuiltinNoneProfile(), "stdio", runtime.NewDeployWorkloadOptions(), false)
+	require.NoError(t, err)
+	assert.Zero(t, port)
+	t.Cleanup(func() { _ = c.RemoveWorkload(ctx, "echo") })
+
+	stdin, stdout, err := c.AttachToWorkload(ctx, "echo")
+	require.NoError(t, err)
+	defer stdin.Close()
+	defer stdout.Close()
+
+	_, err = stdin.Write([]byte("hello\n"))
+	require.NoError(t, err)
+	line, err := bufio.NewReader(stdout).ReadString('\n')
+	require.NoError(t, err)
+	assert.Equal(t, "got hello\n", line)
+
+	logs := readLog(t, c, "echo")
+	assert.Contains(t, logs, "outside-denied")
+	assert.Contains(t, logs, "inside-allowed")
+	assert.Regexp(t, `NoNewPrivs:\s+1`, logs)
+	assert.Regexp(t, `Seccomp:\s+2`, logs)
+
+	running, err := c.IsWorkloadRunning(ctx, "echo")
+	require.NoError(t, err)
+	assert.True(t, running)
+
+	workloads, err := c.ListWorkloads(ctx)
+	require.NoError(t, err)
+	require.Len(t, workloads, 1)
+	assert.Equal(t, "echo", workloads[0].Name)
+	assert.Equal(t, "npx://echo", workloads[0].Image)
+	assert.Equal(t, runtime.WorkloadStatusRunning, workloads[0].State)
+	assert.Equal(t, "true", workloads[0].Labels["toolhive"])
+	assert.Equal(t, "false", workloads[0].Labels["toolhive-network-isolation"])
+	assert.Equal(t, "true", workloads[0].Labels["toolhive-sandbox"])
+
+	require.NoError(t, c.StopWorkload(ctx, "echo"))
+	running, err = c.IsWorkloadRunning(ctx, "echo")
+	require.NoError(t, err)
+	assert.False(t, running)
+
+	info, err := c.GetWorkloadInfo(ctx, "echo")
+	require.NoError(t, err)
+	assert.Equal(t, runtime.WorkloadStatusStopped, info.State)
+
+	_, _, err = c.AttachToWorkload(ctx, "echo")
+	assert.Error(t, err)
+
+	require.NoError(t, c.RemoveWorkload(ctx, "echo"))
+	_, err = c.GetWorkloadInfo(ctx, "echo")
+	assert.ErrorIs(t, err, runtime.ErrWorkloadNotFound)
+}
+
+func TestClient_NetworkNamespace(t *testing.T) {
+	t.Parallel()
+
+	c := newTestClient(t)
+	ctx := context.Background()
+	hostNS, err := os.Readlink("/proc/self/ns/net")
+	require.NoError(t, err)
+
+	_, err = c.deploy(ctx, "npx://netns", "netns", shellCommand(`readlink /proc/self/ns/net >&2`), map[string]string{},
+		map[string]string{}, permissions.BuiltinNoneProfile(), "stdio", runtime.NewDeployWorkloadOptions(), true)
+	if err != nil && strings.Contains(err.Error(), "user namespaces") {
+		t.Skipf("Unprivileged user namespaces are not available: %v", err)
+	}
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = c.RemoveWorkload(ctx, "netns") })
+
+	require.Eventually(t, func() bool {
+		return strings.HasPrefix(readLog(t, c, "netns"), "net:")
+	}, 5*time.Second, 50*time.Millisecond)
+	assert.NotEqual(t, hostNS+"\n", readLog(t, c, "netns"))
+
+	info, err := c.GetWorkloadInfo(ctx, "netns")
+	require.NoError(t, err)
+	assert.Equal(t, "true", info.Labels["toolhive-network-isolation"])
+}
+
+func TestClient_PrivateProc(t *testing.T) {
+	t.Parallel()
+
+	c := newTestClient(t)
+	ctx := context.Background()
+	hostPID := strconv.Itoa(os.Getpid())
+
+	script := `test -e /proc/` + hostPID + `/environ && echo host-visible >&2 || echo host-hidden >&2
+echo "init $(tr '\0' ' ' < /proc/1/cmdline)" >&2`
+	_, err := c.deploy(ctx, "npx://proc", "proc", shellCommand(script), map[string]string{}, map[string]string{},
+		permissions.BuiltinNoneProfile(), "stdio", runtime.NewDeployWorkloadOptions(), false)
+	if err != nil && strings.Contains(err.Error(), "user namespaces") {
+		t.Skipf("Unprivileged user namespaces are not available: %v", err)
+	}
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = c.RemoveWorkload(ctx, "proc") })
+
+	// The MCP server runs in a PID namespace whose init is the sandbox helper
+	require.Eventually(t, func() bool {
+		return strings.Contains(readLog(t, c, "proc"), "init ")
+	}, 5*time.Second, 50*time.Millisecond)
+	logs := readLog(t, c, "proc")
+	assert.Contains(t, logs, "host-hidden")
+	assert.Contains(t, logs, "init "+sandboxInitArg0+" ")
+}
+
+func TestClient_Devices(t *testing.T) {
+	t.Parallel()
+
+	if _, err := os.Stat(sharedMemoryPath); err != nil {
+		t.Skipf("%s is not available: %v", sharedMemoryPath, err)
+	}
+	c := newTestClient(t)
+	ctx := context.Background()
+
+	// Shared memory of the host, which sandboxes must not see
+	hostFile, err := os.CreateTemp(sharedMemoryPath, "toolhive-test-")
+	require.NoError(t, err)
+	require.NoError(t, hostFile.Close())
+	t.Cleanup(func() { _ = os.Remove(hostFile.Name()) })
+
+	script := `test -e ` + hostFile.Name() + ` && echo shm-shared >&2 || echo shm-private >&2
+echo data > /dev/shm/sandbox && echo shm-writable >&2
+echo data > /dev/null && echo null-writable >&2
+head -c 1 /dev/full > /dev/null 2>&1 && echo full-readable >&2 || echo full-denied >&2
+echo done >&2`
+	_, err = c.deploy(ctx, "npx://devices", "devices", shellCommand(script), map[string]string{}, map[string]string{},
+		permissions.BuiltinNoneProfile(), "stdio", runtime.NewDeployWorkloadOptions(), false)
+	if err != nil && strings.Contains(err.Error(), "user namespaces") {
+		t.Skipf("Unprivileged user namespaces are not available: %v", err)
+	}
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = c.RemoveWorkload(ctx, "devices") })
+
+	require.Eventually(t, func() bool {
+		return strings.Contains(readLog(t, c, "devices"), "done")
+	}, 5*time.Second, 50*time.Millisecond)
+	logs := readLog(t, c, "devices")
+	assert.Contains(t, logs, "shm-private")
+	assert.Contains(t, logs, "shm-writable")
+	assert.Contains(t, logs, "null-writable")
+	assert.Contains(t, logs, "full-denied")
+	assert.NoFileExists(t, filepath.Join(sharedMemoryPath, "sandbox"))
+}
+
+func TestClient_UnixSocket(t *testing.T) {
+	t.Parallel()
+
+	c := newTestClient(t)
+	ctx := context.Background()
+	exe, err := os.Executable()
+	require.NoError(t, err)
+
+	// A host service listening on a UNIX socket, such as the Docker daemon
+	socketPath := filepath.Join(t.TempDir(), "host.sock")
+	listener, err := net.Listen("unix", socketPath)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			_ = conn.Close()
+		}
+	}()
+
+	connect := &command{Path: exe, Args: []string{unixConnectArg0, socketPath}, ReadPaths: []string{filepath.Dir(exe)}}
+	tests := []struct {
+		name    string
+		profile *permissions.Profile
+		want    string
+	}{
+		{name: "denied", profile: permissions.BuiltinNoneProfile(), want: "connect-denied"},
+		{
+			name:    "granted by the profile",
+			profile: &permissions.Profile{Read: []permissions.MountDeclaration{permissions.MountDeclaration(socketPath)}},
+			want:    "connect-allowed",
+		},
+	}
+	for _, tt := range tests {
+		name := "unix-" + strings.ReplaceAll(tt.name, " ", "-")
+		_, err := c.deploy(ctx, "npx://unix", name, connect, map[string]string{}, map[string]string{},
+			tt.profile, "stdio", runtime.NewDeployWorkloadOptions(), false)
+		if err != nil && strings.Contains(err.Error(), "user namespaces") {
+			t.Skipf("Unprivileged user namespaces are not available: %v", err)
+		}
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = c.RemoveWorkload(ctx, name) })
+
+		require.Eventually(t, func() bool {
+			return strings.Contains(readLog(t, c, name), "connect-")
+		}, 5*time.Second, 50*time.Millisecond, tt.name)
+		assert.Contains(t, readLog(t, c, name), tt.want, tt.name)
+	}
+}
+
+func TestClient_HTTPWorkload(t *testing.T) {
+	t.Parallel()
+
+	c := newTestClient(t)
+	ctx := context.Background()
+
+	options := runtime.NewDeployWorkloadOptions()
+	options.ExposedPorts["18080/tcp"] = struct{}{}
+	port, err := c.deploy(ctx, "uvx://server", "server", shellCommand(`echo "port $MCP_PORT"; sleep 60`),
+		map[string]string{"MCP_PORT": "18080"}, map[string]string{}, permissions.BuiltinNoneProfile(),
+		"streamable-http", options, false)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = c.RemoveWorkload(ctx, "server") })
+	assert.Equal(t, 18080, port)
+
+	// Stdout goes to the logs for workloads that do not use stdio
+	require.Eventually(t, func() bool {
+		return readLog(t, c, "server") == "port 18080\n"
+	}, 5*time.Second, 50*time.Millisecond)
+
+	info, err := c.GetWorkloadInfo(ctx, "server")
+	require.NoError(t, err)
+	assert.Equal(t, []runtime.PortMapping{{ContainerPort: 18080, HostPort: 18080, Protocol: "tcp"}}, info.Ports)
+
+	_, _, err = c.AttachToWorkload(ctx, "server")
+	assert.ErrorContains(t, err, "does not use the stdio transport")
+}
+
+func TestClient_RestrictedNetwork(t *testing.T) {
+	t.Parallel()
+
+	c := newTestClient(t)
+	if c.landlockABI < 4 {
+		t.Skip("Landlock network rules are not available")
+	}
+	ctx := context.Background()
+
+	profile := &permissions.Profile{Network: &permissions.NetworkPermissions{
+		Outbound: &permissions.OutboundNetworkPermissions{AllowPort: []int{443}},
+	}}
+	options := runtime.NewDeployWorkloadOptions()
+	options.ExposedPorts["18081/tcp"] = struct{}{}
+	_, err := c.deploy(ctx, "uvx://server", "restricted", shellCommand(`echo started`), map[string]string{},
+		map[string]string{}, profile, "sse", options, true)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = c.RemoveWorkload(ctx, "restricted") })
+
+	require.Eventually(t, func() bool {
+		return readLog(t, c, "restricted") == "started\n"
+	}, 5*time.Second, 50*time.Millisecond)
+}
+
+func TestClient_ExitStatus(t *testing.T) {
+	t.Parallel()
+
+	c := newTestClient(t)
+	ctx := context.Background()
+
+	_, err := c.deploy(ctx, "npx://fail", "fail", shellCommand(`exit 3`), map[string]string{}, map[string]string{},
+		permissions.BuiltinNoneProfile(), "stdio", runtime.NewDeployWorkloadOptions(), false)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = c.RemoveWorkload(ctx, "fail") })
+
+	require.Eventually(t, func() bool {
+		info, err := c.GetWorkloadInfo(ctx, "fail")
+		return err == nil && info.Status == "exited (3)"
+	}, 5*time.Second, 50*time.Millisecond)
+}
+
+func TestClient_NotFound(t *testing.T) {
+	t.Parallel()
+
+	c := newTestClient(t)
+	ctx := context.Background()
+
+	_, err := c.IsWorkloadRunning(ctx, "missing")
+	assert.ErrorIs(t, err, runtime.ErrWorkloadNotFound)
+	_, err = c.GetWorkloadLogs(ctx, "missing", false)
+	assert.ErrorIs(t, err, runtime.ErrWorkloadNotFound)
+	assert.NoError(t, c.StopWorkload(ctx, "missing"))
+	assert.NoError(t, c.RemoveWorkload(ctx, "missing"))
+}
+
+func TestTailLines(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "", tailLines(nil, 2))
+	assert.Equal(t, "a\n", tailLines([]byte("a"), 2))
+	assert.Equal(t, "a\nb\n", tailLines([]byte("a\nb\n"), 2))
+	assert.Equal(t, "b\nc\n", tailLines([]byte("a\nb\nc\n"), 2))
+}
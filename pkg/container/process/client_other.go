@@ -0,0 +1,23 @@
+//go:build !linux
+
+package process
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/stacklok/toolhive/pkg/container/runtime"
+)
+
+// IsAvailable returns false, as the process runtime relies on Linux sandboxing.
+func IsAvailable() bool {
+	return false
+}
+
+// NewClient returns an error, as the process runtime relies on Linux sandboxing.
+func NewClient(_ context.Context) (runtime.Runtime, error) {
+	return nil, fmt.Errorf("the %s runtime is only supported on Linux", RuntimeName)
+}
+
+// Init does nothing, as sandboxed processes are only started on Linux.
+func Init() {}
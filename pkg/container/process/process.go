@@ -0,0 +1,211 @@
+// Package process provides a container-less runtime that runs MCP servers as
+// supervised host processes. On Linux, each process is confined with Landlock,
+// seccomp and, where needed, user and network namespaces, so that the
+// permission profile of the workload is enforced without a container engine.
+package process
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/adrg/xdg"
+)
+
+// RuntimeName is the name identifier for the process runtime
+const RuntimeName = "process"
+
+// Protocol schemes that can be run as host processes.
+const (
+	npxScheme = "npx://"
+	uvxScheme = "uvx://"
+	goScheme  = "go://"
+)
+
+// command is the host command that runs an MCP server.
+type command struct {
+	// Path is the absolute path of the executable
+	Path string
+	// Args are the arguments, including the program name
+	Args []string
+	// Dir is the working directory, empty for the workload home directory
+	Dir string
+	// ReadPaths are additional paths the command needs to read and execute
+	ReadPaths []string
+}
+
+// IsSupportedImage returns true if the image can be run by the process runtime.
+// Only protocol schemes are supported, as container images need a container engine.
+func IsSupportedImage(image string) bool {
+	for _, scheme := range []string{npxScheme, uvxScheme, goScheme} {
+		if strings.HasPrefix(image, scheme) {
+			return true
+		}
+	}
+	return false
+}
+
+// ResolveImage returns the protocol scheme to store in the run configuration of a
+// workload. Local go:// paths are made absolute, so that the workload can be
+// restarted from any directory.
+func ResolveImage(image string) (string, error) {
+	if !IsSupportedImage(image) {
+		return "", fmt.Errorf("the %s runtime only supports npx://, uvx:// and go:// servers, not %q", RuntimeName, image)
+	}
+	pkg, ok := strings.CutPrefix(image, goScheme)
+	if !ok || !isLocalGoPath(pkg) {
+		return image, nil
+	}
+	abs, err := filepath.Abs(pkg)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve local Go package %s: %w", pkg, err)
+	}
+	return goScheme + abs, nil
+}
+
+// resolveCommand converts a protocol scheme into the host command that runs it,
+// appending args to the package arguments.
+func resolveCommand(image string, args []string) (*command, error) {
+	var program, dir string
+	var programArgs []string
+	var readPaths []string
+
+	switch {
+	case strings.HasPrefix(image, npxScheme):
+		pkg := strings.TrimPrefix(image, npxScheme)
+		program, programArgs = "npx", []string{"-y", pkg}
+	case strings.HasPrefix(image, uvxScheme):
+		pkg := strings.TrimPrefix(image, uvxScheme)
+		program, programArgs = "uvx", []string{pkg}
+	case strings.HasPrefix(image, goScheme):
+		pkg := strings.TrimPrefix(image, goScheme)
+		program = "go"
+		if isLocalGoPath(pkg) {
+			// go run only builds local packages from inside their module
+			abs, err := filepath.Abs(pkg)
+			if err != nil {
+				return nil, fmt.Errorf("failed to resolve local Go package %s: %w", pkg, err)
+			}
+			dir = abs
+			readPaths = append(readPaths, abs)
+			programArgs = []string{"run", "."}
+		} else {
+			if !strings.Contains(pkg, "@") {
+				pkg += "@latest"
+			}
+			programArgs = []string{"run", pkg}
+		}
+	default:
+		return nil, fmt.Errorf("the %s runtime only supports npx://, uvx:// and go:// servers, not %q", RuntimeName, image)
+	}
+
+	if strings.TrimSpace(programArgs[len(programArgs)-1]) == "" {
+		return nil, fmt.Errorf("missing package name in %q", image)
+	}
+
+	path, err := exec.LookPath(program)
+	if err != nil {
+		return nil, fmt.Errorf("%s is required to run %s but was not found in PATH: %w", program, image, err)
+	}
+	path, err = filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", program, err)
+	}
+
+	return &command{
+		Path:      path,
+		Args:      append(append([]string{program}, programArgs...), args...),
+		Dir:       dir,
+		ReadPaths: append(readPaths, installPrefix(path)),
+	}, nil
+}
+
+// isLocalGoPath returns true if the go:// package refers to a directory on the host.
+func isLocalGoPath(pkg string) bool {
+	return pkg == "." || strings.HasPrefix(pkg, "./") || strings.HasPrefix(pkg, "../") || filepath.IsAbs(pkg)
+}
+
+// installPrefix returns the installation prefix of an executable, e.g. the Node.js
+// installation for npx, so that its runtime and libraries remain readable.
+// Symlinks are resolved because version managers link executables into shims.
+func installPrefix(path string) string {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	dir := filepath.Dir(path)
+	if filepath.Base(dir) == "bin" {
+		return filepath.Dir(dir)
+	}
+	return dir
+}
+
+// workload is the persisted state of a workload, stored in workload.json.
+type workload struct {
+	Name      string            `json:"name"`
+	Image     string            `json:"image"`
+	Command   []string          `json:"command"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	Ports     []int             `json:"ports,omitempty"`
+	Stdio     bool              `json:"stdio,omitempty"`
+	Created   time.Time         `json:"created"`
+	StartedAt time.Time         `json:"startedAt"`
+	PID       int               `json:"pid"`
+	// PIDStartTime is the start time of the process in clock ticks since boot,
+	// which detects reuse of the PID by another process.
+	PIDStartTime uint64     `json:"pidStartTime"`
+	FinishedAt   *time.Time `json:"finishedAt,omitempty"`
+	ExitCode     *int       `json:"exitCode,omitempty"`
+}
+
+// Files in the directory of a workload.
+const (
+	workloadFile = "workload.json"
+	sandboxFile  = "sandbox.json"
+	stdinFile    = "stdin"
+	stdoutFile   = "stdout"
+	stderrFile   = "stderr.log"
+	homeDir      = "home"
+	tmpDir       = "tmp"
+)
+
+// stateDir returns the directory holding the state of all workloads.
+func stateDir() string {
+	return filepath.Join(xdg.StateHome, "toolhive", RuntimeName)
+}
+
+// workloadDir returns the directory of a workload.
+func workloadDir(base, name string) (string, error) {
+	if name == "" || name != filepath.Base(name) || name == "." || name == ".." {
+		return "", fmt.Errorf("invalid workload name: %q", name)
+	}
+	return filepath.Join(base, name), nil
+}
+
+func readWorkload(dir string) (*workload, error) {
+	data, err := os.ReadFile(filepath.Join(dir, workloadFile))
+	if err != nil {
+		return nil, err
+	}
+	var w workload
+	if err := json.Unmarshal(data, &w); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", workloadFile, err)
+	}
+	return &w, nil
+}
+
+// writeWorkload atomically replaces the state of a workload.
+func writeWorkload(dir string, w *workload) error {
+	data, err := json.MarshalIndent(w, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := filepath.Join(dir, workloadFile+".tmp")
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(dir, workloadFile))
+}
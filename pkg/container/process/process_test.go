@@ -0,0 +1,130 @@
+package process
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeInstall creates executables in <prefix>/bin and puts them on PATH.
+func fakeInstall(t *testing.T, programs ...string) string {
+	t.Helper()
+
+	prefix := t.TempDir()
+	bin := filepath.Join(prefix, "bin")
+	require.NoError(t, os.Mkdir(bin, 0o700))
+	for _, program := range programs {
+		require.NoError(t, os.WriteFile(filepath.Join(bin, program), []byte("#!/bin/sh\n"), 0o700)) //nolint:gosec
+	}
+	t.Setenv("PATH", bin)
+	return prefix
+}
+
+func TestResolveCommand(t *testing.T) {
+	prefix := fakeInstall(t, "npx", "uvx", "go")
+	cwd, err := os.Getwd()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		image    string
+		args     []string
+		wantArgs []string
+		wantDir  string
+	}{
+		{
+			name:     "npx",
+			image:    "npx://@modelcontextprotocol/server-everything@1.0.0",
+			args:     []string{"stdio"},
+			wantArgs: []string{"npx", "-y", "@modelcontextprotocol/server-everything@1.0.0", "stdio"},
+		},
+		{
+			name:     "uvx",
+			image:    "uvx://mcp-server-fetch",
+			wantArgs: []string{"uvx", "mcp-server-fetch"},
+		},
+		{
+			name:     "remote go package defaults to latest",
+			image:    "go://github.com/example/server",
+			wantArgs: []string{"go", "run", "github.com/example/server@latest"},
+		},
+		{
+			name:     "remote go package with version",
+			image:    "go://github.com/example/server@v1.2.3",
+			args:     []string{"--verbose"},
+			wantArgs: []string{"go", "run", "github.com/example/server@v1.2.3", "--verbose"},
+		},
+		{
+			name:     "local go package",
+			image:    "go://./cmd/server",
+			wantArgs: []string{"go", "run", "."},
+			wantDir:  filepath.Join(cwd, "cmd", "server"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, err := resolveCommand(tt.image, tt.args)
+			require.NoError(t, err)
+
+			assert.Equal(t, filepath.Join(prefix, "bin", tt.wantArgs[0]), cmd.Path)
+			assert.Equal(t, tt.wantArgs, cmd.Args)
+			assert.Equal(t, tt.wantDir, cmd.Dir)
+			assert.Contains(t, cmd.ReadPaths, prefix)
+			if tt.wantDir != "" {
+				assert.Contains(t, cmd.ReadPaths, tt.wantDir)
+			}
+		})
+	}
+}
+
+func TestResolveCommand_Errors(t *testing.T) {
+	fakeInstall(t, "npx")
+
+	_, err := resolveCommand("ghcr.io/example/server:latest", nil)
+	assert.ErrorContains(t, err, "only supports npx://, uvx:// and go://")
+
+	_, err = resolveCommand("npx://", nil)
+	assert.ErrorContains(t, err, "missing package name")
+
+	_, err = resolveCommand("uvx://mcp-server-fetch", nil)
+	assert.ErrorContains(t, err, "uvx is required")
+}
+
+func TestResolveImage(t *testing.T) {
+	t.Parallel()
+
+	cwd, err := os.Getwd()
+	require.NoError(t, err)
+
+	image, err := ResolveImage("npx://server")
+	require.NoError(t, err)
+	assert.Equal(t, "npx://server", image)
+
+	image, err = ResolveImage("go://github.com/example/server")
+	require.NoError(t, err)
+	assert.Equal(t, "go://github.com/example/server", image)
+
+	image, err = ResolveImage("go://./server")
+	require.NoError(t, err)
+	assert.Equal(t, "go://"+filepath.Join(cwd, "server"), image)
+
+	_, err = ResolveImage("alpine:latest")
+	assert.Error(t, err)
+}
+
+func TestWorkloadDir(t *testing.T) {
+	t.Parallel()
+
+	dir, err := workloadDir("/state", "fetch")
+	require.NoError(t, err)
+	assert.Equal(t, "/state/fetch", dir)
+
+	for _, name := range []string{"", ".", "..", "../fetch", "a/b"} {
+		_, err := workloadDir("/state", name)
+		assert.Error(t, err, name)
+	}
+}
@@ -0,0 +1,448 @@
+package process
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/stacklok/toolhive/pkg/logger"
+	"github.com/stacklok/toolhive/pkg/permissions"
+)
+
+const (
+	// sandboxInitArg0 is the program name the process runtime uses to re-execute
+	// the current binary as the sandbox helper. The helper runs as the init process
+	// of the sandbox namespaces: it mounts a private procfs and starts the second
+	// stage, which confines itself and then executes the MCP server.
+	sandboxInitArg0 = "toolhive-sandbox-init"
+
+	// sandboxExecArg0 is the program name of the second stage of the sandbox helper.
+	sandboxExecArg0 = "toolhive-sandbox-exec"
+)
+
+// systemReadPaths are readable by every sandboxed process, so that interpreters,
+// shared libraries, certificates and name resolution keep working. /proc is the
+// private procfs of the sandbox, which only shows the processes of the workload.
+var systemReadPaths = []string{
+	"/usr", "/lib", "/lib32", "/lib64", "/bin", "/sbin", "/etc", "/opt", "/nix",
+	"/proc", "/sys", "/run/systemd/resolve",
+}
+
+// deviceWritePaths are the device nodes every sandboxed process can read and write.
+// The rest of /dev, such as GPU, KVM, fuse and terminal devices, stays out of reach.
+var deviceWritePaths = []string{"/dev/null", "/dev/zero", "/dev/random", "/dev/urandom", "/dev/tty", "/dev/pts"}
+
+// sharedMemoryPath is replaced by a tmpfs private to the sandbox, so that sandboxed
+// processes cannot exchange data through the shared memory of the host.
+const sharedMemoryPath = "/dev/shm"
+
+// landlockNetPortAttr mirrors struct landlock_net_port_attr, which is not defined
+// by golang.org/x/sys/unix.
+type landlockNetPortAttr struct {
+	AllowedAccess uint64
+	Port          uint64
+}
+
+// landlockRuleNetPort is LANDLOCK_RULE_NET_PORT.
+const landlockRuleNetPort = 2
+
+const (
+	// accessFSRead is granted on read-only paths.
+	accessFSRead = unix.LANDLOCK_ACCESS_FS_EXECUTE | unix.LANDLOCK_ACCESS_FS_READ_FILE | unix.LANDLOCK_ACCESS_FS_READ_DIR
+
+	// accessFSFile is the subset of rights that apply to files rather than directories.
+	accessFSFile = unix.LANDLOCK_ACCESS_FS_EXECUTE | unix.LANDLOCK_ACCESS_FS_WRITE_FILE |
+		unix.LANDLOCK_ACCESS_FS_READ_FILE | unix.LANDLOCK_ACCESS_FS_TRUNCATE | unix.LANDLOCK_ACCESS_FS_IOCTL_DEV
+
+	// accessFSV1 contains the rights handled by Landlock ABI 1.
+	accessFSV1 = unix.LANDLOCK_ACCESS_FS_EXECUTE | unix.LANDLOCK_ACCESS_FS_WRITE_FILE |
+		unix.LANDLOCK_ACCESS_FS_READ_FILE | unix.LANDLOCK_ACCESS_FS_READ_DIR |
+		unix.LANDLOCK_ACCESS_FS_REMOVE_DIR | unix.LANDLOCK_ACCESS_FS_REMOVE_FILE |
+		unix.LANDLOCK_ACCESS_FS_MAKE_CHAR | unix.LANDLOCK_ACCESS_FS_MAKE_DIR |
+		unix.LANDLOCK_ACCESS_FS_MAKE_REG | unix.LANDLOCK_ACCESS_FS_MAKE_SOCK |
+		unix.LANDLOCK_ACCESS_FS_MAKE_FIFO | unix.LANDLOCK_ACCESS_FS_MAKE_BLOCK |
+		unix.LANDLOCK_ACCESS_FS_MAKE_SYM
+)
+
+// sandboxSpec is the confinement applied by the sandbox helper before it
+// executes the MCP server. It is written to sandbox.json by DeployWorkload.
+type sandboxSpec struct {
+	// Path and Args are the command to execute once confined
+	Path string   `json:"path"`
+	Args []string `json:"args"`
+	// ReadOnly paths can be read and executed
+	ReadOnly []string `json:"readOnly"`
+	// ReadWrite paths can be read, executed and modified
+	ReadWrite []string `json:"readWrite"`
+	// RestrictNetwork limits TCP connections to ConnectPorts and binding to BindPorts
+	RestrictNetwork bool  `json:"restrictNetwork,omitempty"`
+	ConnectPorts    []int `json:"connectPorts,omitempty"`
+	BindPorts       []int `json:"bindPorts,omitempty"`
+	// AllowUnixSockets allows creating UNIX sockets. Landlock does not restrict
+	// connecting to UNIX sockets, so they are only allowed when the profile grants
+	// access to a socket.
+	AllowUnixSockets bool `json:"allowUnixSockets,omitempty"`
+}
+
+// Init runs the sandbox helper if the process was started as one by the process
+// runtime; in that case it does not return. Programs that use the process runtime
+// must call Init at the start of main.
+func Init() {
+	if len(os.Args) != 2 {
+		return
+	}
+	switch os.Args[0] {
+	case sandboxInitArg0:
+		code, err := runSandboxInit(os.Args[1])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "toolhive sandbox: %v\n", err)
+			os.Exit(127)
+		}
+		os.Exit(code)
+	case sandboxExecArg0:
+		if err := execSandboxed(os.Args[1]); err != nil {
+			fmt.Fprintf(os.Stderr, "toolhive sandbox: %v\n", err)
+			os.Exit(127)
+		}
+	}
+}
+
+// runSandboxInit runs as the init process of the sandbox PID namespace. It mounts a
+// private procfs, runs the second stage of the helper and reaps the processes of the
+// namespace until the second stage exits, returning its exit code.
+//
+// Stop signals reach the MCP server through its process group. The init process
+// catches them and keeps running until the MCP server exits, since the exit of the
+// init kills all remaining processes of the namespace.
+func runSandboxInit(specPath string) (int, error) {
+	if err := mountPrivateProc(); err != nil {
+		return 0, err
+	}
+	signal.Notify(make(chan os.Signal, 1), unix.SIGTERM, unix.SIGINT, unix.SIGHUP)
+
+	child, err := os.StartProcess("/proc/self/exe", []string{sandboxExecArg0, specPath}, &os.ProcAttr{
+		Env:   os.Environ(),
+		Files: []*os.File{os.Stdin, os.Stdout, os.Stderr},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to start sandboxed process: %w", err)
+	}
+	// Only the MCP server keeps the standard streams of a stdio workload open
+	_ = os.Stdin.Close()
+	_ = os.Stdout.Close()
+
+	for {
+		var status unix.WaitStatus
+		pid, err := unix.Wait4(-1, &status, 0, nil)
+		if errors.Is(err, unix.EINTR) {
+			continue
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to wait for sandboxed process: %w", err)
+		}
+		if pid != child.Pid {
+			continue
+		}
+		if status.Signaled() {
+			return 128 + int(status.Signal()), nil
+		}
+		return status.ExitStatus(), nil
+	}
+}
+
+// mountPrivateProc replaces /proc with a procfs of the sandbox PID namespace, so that
+// the workload cannot read the environment or command line of host processes. It also
+// replaces /dev/shm with a private tmpfs.
+func mountPrivateProc() error {
+	// Keep the mounts of the sandbox from propagating to the host
+	if err := unix.Mount("", "/", "", unix.MS_REC|unix.MS_PRIVATE, ""); err != nil {
+		return fmt.Errorf("failed to make mounts private: %w", err)
+	}
+	if err := unix.Mount("proc", "/proc", "proc", unix.MS_NOSUID|unix.MS_NODEV|unix.MS_NOEXEC, ""); err != nil {
+		return fmt.Errorf("failed to mount private procfs: %w", err)
+	}
+	if _, err := os.Stat(sharedMemoryPath); err == nil {
+		if err := unix.Mount("tmpfs", sharedMemoryPath, "tmpfs", unix.MS_NOSUID|unix.MS_NODEV|unix.MS_NOEXEC,
+			"mode=1777,size=64m"); err != nil {
+			return fmt.Errorf("failed to mount private %s: %w", sharedMemoryPath, err)
+		}
+	}
+	return nil
+}
+
+// execSandboxed confines the current thread according to the spec and replaces
+// the process with the MCP server. Landlock domains, seccomp filters and the
+// no_new_privs flag are per thread and are inherited across execve.
+func execSandboxed(specPath string) error {
+	runtime.LockOSThread()
+
+	data, err := os.ReadFile(specPath)
+	if err != nil {
+		return fmt.Errorf("failed to read sandbox spec: %w", err)
+	}
+	var spec sandboxSpec
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return fmt.Errorf("failed to parse sandbox spec: %w", err)
+	}
+
+	if err := unix.Prctl(unix.PR_SET_NO_NEW_PRIVS, 1, 0, 0, 0); err != nil {
+		return fmt.Errorf("failed to set no_new_privs: %w", err)
+	}
+	if err := applyLandlock(&spec); err != nil {
+		return err
+	}
+	if err := applySeccomp(&spec); err != nil {
+		return err
+	}
+
+	if err := syscall.Exec(spec.Path, spec.Args, os.Environ()); err != nil {
+		return fmt.Errorf("failed to execute %s: %w", spec.Path, err)
+	}
+	return nil
+}
+
+// newSandboxSpec derives the confinement of a workload from its permission profile.
+// It returns a nil spec for privileged profiles, and whether the workload must run
+// in its own network namespace. Sandboxed workloads always run in their own user,
+// mount and PID namespaces.
+func newSandboxSpec(
+	profile *permissions.Profile,
+	cmd *command,
+	dir string,
+	transportType string,
+	ports []int,
+	isolateNetwork bool,
+	abi int,
+) (*sandboxSpec, bool, error) {
+	if profile == nil {
+		profile = &permissions.Profile{}
+	}
+	if profile.Privileged {
+		logger.Warnf("Permission profile is privileged, running %s without a sandbox", cmd.Args[0])
+		return nil, false, nil
+	}
+
+	spec := &sandboxSpec{
+		Path:      cmd.Path,
+		Args:      cmd.Args,
+		ReadOnly:  existingPaths(append(append([]string{}, systemReadPaths...), cmd.ReadPaths...)),
+		ReadWrite: []string{filepath.Join(dir, homeDir), filepath.Join(dir, tmpDir)},
+	}
+	spec.ReadWrite = append(spec.ReadWrite, existingPaths(deviceWritePaths)...)
+	// The sandbox helper mounts a private tmpfs on /dev/shm before the rules are applied
+	spec.ReadWrite = append(spec.ReadWrite, existingPaths([]string{sharedMemoryPath})...)
+	readPaths, writePaths := mountPaths(profile.Read), mountPaths(profile.Write)
+	spec.ReadOnly = append(spec.ReadOnly, readPaths...)
+	spec.ReadWrite = append(spec.ReadWrite, writePaths...)
+	spec.AllowUnixSockets = containsSocket(readPaths) || containsSocket(writePaths)
+	if spec.AllowUnixSockets {
+		logger.Warnf("The permission profile of %s grants access to a UNIX socket; the %s runtime cannot restrict "+
+			"which UNIX sockets it connects to", cmd.Args[0], RuntimeName)
+	}
+
+	newNetNS := profile.Network != nil && profile.Network.Mode == "none"
+	if !isolateNetwork || newNetNS {
+		return spec, newNetNS, nil
+	}
+
+	var outbound permissions.OutboundNetworkPermissions
+	if profile.Network != nil && profile.Network.Outbound != nil {
+		outbound = *profile.Network.Outbound
+	}
+	if outbound.InsecureAllowAll {
+		return spec, false, nil
+	}
+	// Landlock can only filter connections by port, so allowing the ports of the hosts would
+	// allow every host on these ports
+	if len(outbound.AllowHost) > 0 {
+		return nil, false, fmt.Errorf("the %s runtime cannot restrict outbound connections to hosts %v: "+
+			"remove allow_host from the permission profile, or use a container runtime", RuntimeName, outbound.AllowHost)
+	}
+
+	// A stdio server without outbound access needs no network at all
+	if transportType == "stdio" && len(outbound.AllowPort) == 0 {
+		return spec, true, nil
+	}
+
+	if abi < 4 {
+		return nil, false, fmt.Errorf("network isolation requires Landlock ABI 4 (Linux 6.7 or later), found ABI %d", abi)
+	}
+	spec.RestrictNetwork = true
+	spec.ConnectPorts = outbound.AllowPort
+	spec.BindPorts = ports
+	return spec, false, nil
+}
+
+// mountPaths returns the host paths of mount declarations. Processes see the host
+// filesystem, so a declaration with a different target is granted at its source.
+func mountPaths(mounts []permissions.MountDeclaration) []string {
+	var paths []string
+	for _, mountDecl := range mounts {
+		source, target, err := mountDecl.Parse()
+		if err != nil {
+			logger.Warnf("Warning: Skipping invalid mount declaration: %s (%v)", mountDecl, err)
+			continue
+		}
+		if strings.Contains(source, "://") {
+			logger.Warnf("Warning: Resource URI mounts not yet supported: %s", source)
+			continue
+		}
+		absPath, err := filepath.Abs(source)
+		if err != nil {
+			logger.Warnf("Warning: Failed to convert to absolute path: %s (%v)", mountDecl, err)
+			continue
+		}
+		if target != source && target != absPath {
+			logger.Warnf("The %s runtime cannot remap paths, %s is available at its host path instead of %s",
+				RuntimeName, absPath, target)
+		}
+		paths = append(paths, absPath)
+	}
+	return existingPaths(paths)
+}
+
+// existingPaths drops the paths that do not exist, as Landlock rules need an open file.
+func existingPaths(paths []string) []string {
+	result := make([]string, 0, len(paths))
+	for _, path := range paths {
+		if _, err := os.Stat(path); err == nil {
+			result = append(result, path)
+		}
+	}
+	return result
+}
+
+// containsSocket reports whether any of the paths is a UNIX socket.
+func containsSocket(paths []string) bool {
+	for _, path := range paths {
+		if info, err := os.Stat(path); err == nil && info.Mode()&os.ModeSocket != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// landlockABI returns the Landlock ABI version supported by the kernel, or 0 if
+// Landlock is unavailable.
+func landlockABI() int {
+	abi, _, errno := unix.Syscall(unix.SYS_LANDLOCK_CREATE_RULESET, 0, 0, unix.LANDLOCK_CREATE_RULESET_VERSION)
+	if errno != 0 {
+		return 0
+	}
+	return int(abi)
+}
+
+// handledFSAccess returns the filesystem rights supported by a Landlock ABI version.
+func handledFSAccess(abi int) uint64 {
+	access := uint64(accessFSV1)
+	if abi >= 2 {
+		access |= unix.LANDLOCK_ACCESS_FS_REFER
+	}
+	if abi >= 3 {
+		access |= unix.LANDLOCK_ACCESS_FS_TRUNCATE
+	}
+	if abi >= 5 {
+		access |= unix.LANDLOCK_ACCESS_FS_IOCTL_DEV
+	}
+	return access
+}
+
+// applyLandlock restricts filesystem access to the paths of the spec and, if
+// requested, TCP connections and binding to its ports.
+func applyLandlock(spec *sandboxSpec) error {
+	abi := landlockABI()
+	if abi < 1 {
+		return fmt.Errorf("landlock is not supported by the kernel")
+	}
+
+	handled := handledFSAccess(abi)
+	attr := unix.LandlockRulesetAttr{Access_fs: handled}
+	if spec.RestrictNetwork {
+		if abi < 4 {
+			return fmt.Errorf("landlock ABI %d cannot restrict network access", abi)
+		}
+		attr.Access_net = unix.LANDLOCK_ACCESS_NET_BIND_TCP | unix.LANDLOCK_ACCESS_NET_CONNECT_TCP
+	}
+
+	fd, _, errno := unix.Syscall(unix.SYS_LANDLOCK_CREATE_RULESET, uintptr(unsafe.Pointer(&attr)), unsafe.Sizeof(attr), 0)
+	if errno != 0 {
+		return fmt.Errorf("failed to create landlock ruleset: %w", errno)
+	}
+	rulesetFD := int(fd)
+	defer unix.Close(rulesetFD)
+
+	for _, path := range spec.ReadOnly {
+		if err := addPathRule(rulesetFD, path, accessFSRead&handled); err != nil {
+			return err
+		}
+	}
+	for _, path := range spec.ReadWrite {
+		if err := addPathRule(rulesetFD, path, handled); err != nil {
+			return err
+		}
+	}
+	if spec.RestrictNetwork {
+		for _, port := range spec.ConnectPorts {
+			if err := addPortRule(rulesetFD, port, unix.LANDLOCK_ACCESS_NET_CONNECT_TCP); err != nil {
+				return err
+			}
+		}
+		for _, port := range spec.BindPorts {
+			if err := addPortRule(rulesetFD, port, unix.LANDLOCK_ACCESS_NET_BIND_TCP); err != nil {
+				return err
+			}
+		}
+	}
+
+	if _, _, errno := unix.Syscall(unix.SYS_LANDLOCK_RESTRICT_SELF, uintptr(rulesetFD), 0, 0); errno != 0 {
+		return fmt.Errorf("failed to enforce landlock ruleset: %w", errno)
+	}
+	return nil
+}
+
+func addPathRule(rulesetFD int, path string, access uint64) error {
+	fd, err := unix.Open(path, unix.O_PATH|unix.O_CLOEXEC, 0)
+	if err != nil {
+		// The path may have been removed since the spec was written
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer unix.Close(fd)
+
+	var stat unix.Stat_t
+	if err := unix.Fstat(fd, &stat); err != nil {
+		return fmt.Errorf("failed to stat %s: %w", path, err)
+	}
+	if stat.Mode&unix.S_IFMT != unix.S_IFDIR {
+		access &= accessFSFile
+	}
+
+	attr := unix.LandlockPathBeneathAttr{Allowed_access: access, Parent_fd: int32(fd)}
+	_, _, errno := unix.Syscall6(unix.SYS_LANDLOCK_ADD_RULE, uintptr(rulesetFD), unix.LANDLOCK_RULE_PATH_BENEATH,
+		uintptr(unsafe.Pointer(&attr)), 0, 0, 0)
+	if errno != 0 {
+		return fmt.Errorf("failed to add landlock rule for %s: %w", path, errno)
+	}
+	return nil
+}
+
+func addPortRule(rulesetFD int, port int, access uint64) error {
+	attr := landlockNetPortAttr{AllowedAccess: access, Port: uint64(port)}
+	_, _, errno := unix.Syscall6(unix.SYS_LANDLOCK_ADD_RULE, uintptr(rulesetFD), landlockRuleNetPort,
+		uintptr(unsafe.Pointer(&attr)), 0, 0, 0)
+	if errno != 0 {
+		return fmt.Errorf("failed to add landlock rule for port %d: %w", port, errno)
+	}
+	return nil
+}
@@ -0,0 +1,208 @@
+package process
+
+import (
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+
+	"github.com/stacklok/toolhive/pkg/permissions"
+)
+
+func TestNewSandboxSpec_Filesystem(t *testing.T) {
+	t.Parallel()
+
+	readDir := t.TempDir()
+	writeDir := t.TempDir()
+	profile := &permissions.Profile{
+		Read:  []permissions.MountDeclaration{permissions.MountDeclaration(readDir), "/does/not/exist"},
+		Write: []permissions.MountDeclaration{permissions.MountDeclaration(writeDir + ":/data")},
+	}
+	cmd := &command{Path: "/usr/bin/npx", Args: []string{"npx", "-y", "server"}, ReadPaths: []string{"/usr"}}
+
+	spec, newNetNS, err := newSandboxSpec(profile, cmd, "/state/server", "stdio", nil, false, 4)
+	require.NoError(t, err)
+	assert.False(t, newNetNS)
+	assert.Equal(t, cmd.Path, spec.Path)
+	assert.Equal(t, cmd.Args, spec.Args)
+	assert.Contains(t, spec.ReadOnly, "/usr")
+	assert.Contains(t, spec.ReadOnly, readDir)
+	assert.NotContains(t, spec.ReadOnly, "/does/not/exist")
+	assert.Equal(t, []string{"/state/server/home", "/state/server/tmp"}, spec.ReadWrite[:2])
+	assert.Equal(t, writeDir, spec.ReadWrite[len(spec.ReadWrite)-1])
+	assert.Contains(t, spec.ReadWrite, "/dev/null")
+	assert.False(t, spec.RestrictNetwork)
+	assert.False(t, spec.AllowUnixSockets)
+}
+
+func TestNewSandboxSpec_Devices(t *testing.T) {
+	t.Parallel()
+
+	spec, _, err := newSandboxSpec(nil, &command{Args: []string{"sh"}}, "/state/server", "stdio", nil, false, 4)
+	require.NoError(t, err)
+	// Only an allow-list of devices is writable, not the whole of /dev
+	assert.NotContains(t, spec.ReadWrite, "/dev")
+	assert.NotContains(t, spec.ReadOnly, "/dev")
+	for _, path := range spec.ReadWrite {
+		if strings.HasPrefix(path, "/dev/") {
+			assert.Contains(t, append(append([]string{}, deviceWritePaths...), sharedMemoryPath), path)
+		}
+	}
+}
+
+func TestNewSandboxSpec_UnixSockets(t *testing.T) {
+	t.Parallel()
+
+	socketPath := filepath.Join(t.TempDir(), "agent.sock")
+	listener, err := net.Listen("unix", socketPath)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	profile := &permissions.Profile{Write: []permissions.MountDeclaration{permissions.MountDeclaration(socketPath)}}
+	spec, _, err := newSandboxSpec(profile, &command{Args: []string{"sh"}}, "/state/server", "stdio", nil, false, 4)
+	require.NoError(t, err)
+	assert.True(t, spec.AllowUnixSockets)
+	assert.Contains(t, spec.ReadWrite, socketPath)
+}
+
+func TestNewSandboxSpec_Network(t *testing.T) {
+	t.Parallel()
+
+	outbound := func(out *permissions.OutboundNetworkPermissions) *permissions.Profile {
+		return &permissions.Profile{Network: &permissions.NetworkPermissions{Outbound: out}}
+	}
+
+	tests := []struct {
+		name             string
+		profile          *permissions.Profile
+		transport        string
+		isolate          bool
+		abi              int
+		wantNetNS        bool
+		wantRestrict     bool
+		wantConnectPorts []int
+		wantErr          string
+	}{
+		{
+			name:      "no isolation",
+			profile:   outbound(&permissions.OutboundNetworkPermissions{}),
+			transport: "stdio",
+			abi:       4,
+		},
+		{
+			name:      "network mode none",
+			profile:   &permissions.Profile{Network: &permissions.NetworkPermissions{Mode: "none"}},
+			transport: "sse",
+			abi:       1,
+			wantNetNS: true,
+		},
+		{
+			name:      "insecure allow all",
+			profile:   outbound(&permissions.OutboundNetworkPermissions{InsecureAllowAll: true}),
+			transport: "stdio",
+			isolate:   true,
+			abi:       1,
+		},
+		{
+			name:      "stdio without outbound access",
+			profile:   outbound(&permissions.OutboundNetworkPermissions{}),
+			transport: "stdio",
+			isolate:   true,
+			abi:       1,
+			wantNetNS: true,
+		},
+		{
+			name:         "http without outbound access",
+			profile:      outbound(nil),
+			transport:    "streamable-http",
+			isolate:      true,
+			abi:          4,
+			wantRestrict: true,
+		},
+		{
+			name:             "allowed ports",
+			profile:          outbound(&permissions.OutboundNetworkPermissions{AllowPort: []int{5432}}),
+			transport:        "stdio",
+			isolate:          true,
+			abi:              4,
+			wantRestrict:     true,
+			wantConnectPorts: []int{5432},
+		},
+		{
+			name: "allowed hosts cannot be enforced",
+			profile: outbound(&permissions.OutboundNetworkPermissions{
+				AllowHost: []string{"api.github.com"}, AllowPort: []int{443},
+			}),
+			transport: "sse",
+			isolate:   true,
+			abi:       6,
+			wantErr:   "cannot restrict outbound connections to hosts [api.github.com]",
+		},
+		{
+			name: "allowed hosts with insecure allow all",
+			profile: outbound(&permissions.OutboundNetworkPermissions{
+				AllowHost: []string{"api.github.com"}, InsecureAllowAll: true,
+			}),
+			transport: "sse",
+			isolate:   true,
+			abi:       6,
+		},
+		{
+			name:      "port filtering needs landlock abi 4",
+			profile:   outbound(&permissions.OutboundNetworkPermissions{AllowPort: []int{443}}),
+			transport: "stdio",
+			isolate:   true,
+			abi:       3,
+			wantErr:   "requires Landlock ABI 4",
+		},
+	}
+
+	cmd := &command{Path: "/bin/sh", Args: []string{"sh"}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var ports []int
+			if tt.transport != "stdio" {
+				ports = []int{8080}
+			}
+			spec, newNetNS, err := newSandboxSpec(tt.profile, cmd, "/state/server", tt.transport, ports, tt.isolate, tt.abi)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantNetNS, newNetNS)
+			assert.Equal(t, tt.wantRestrict, spec.RestrictNetwork)
+			if tt.wantRestrict {
+				assert.Equal(t, tt.wantConnectPorts, spec.ConnectPorts)
+				assert.Equal(t, ports, spec.BindPorts)
+			}
+		})
+	}
+}
+
+func TestNewSandboxSpec_Privileged(t *testing.T) {
+	t.Parallel()
+
+	spec, newNetNS, err := newSandboxSpec(&permissions.Profile{Privileged: true}, &command{Args: []string{"sh"}},
+		"/state/server", "stdio", nil, true, 4)
+	require.NoError(t, err)
+	assert.Nil(t, spec)
+	assert.False(t, newNetNS)
+}
+
+func TestHandledFSAccess(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, uint64(accessFSV1), handledFSAccess(1))
+	assert.NotZero(t, handledFSAccess(2)&unix.LANDLOCK_ACCESS_FS_REFER)
+	assert.Zero(t, handledFSAccess(2)&unix.LANDLOCK_ACCESS_FS_TRUNCATE)
+	assert.NotZero(t, handledFSAccess(3)&unix.LANDLOCK_ACCESS_FS_TRUNCATE)
+	assert.Zero(t, handledFSAccess(4)&unix.LANDLOCK_ACCESS_FS_IOCTL_DEV)
+	assert.NotZero(t, handledFSAccess(5)&unix.LANDLOCK_ACCESS_FS_IOCTL_DEV)
+}
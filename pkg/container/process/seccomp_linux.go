@@ -0,0 +1,102 @@
+//go:build linux && (amd64 || arm64)
+
+package process
+
+import (
+	"fmt"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// deniedSyscalls fail with EPERM in sandboxed processes. They manipulate mounts,
+// namespaces, other processes or the kernel, none of which an MCP server needs.
+// io_uring is denied because its operations, such as creating sockets, bypass
+// the checks on the corresponding syscalls.
+var deniedSyscalls = []uint32{
+	unix.SYS_MOUNT, unix.SYS_UMOUNT2, unix.SYS_PIVOT_ROOT,
+	unix.SYS_FSOPEN, unix.SYS_FSMOUNT, unix.SYS_FSCONFIG, unix.SYS_MOVE_MOUNT, unix.SYS_OPEN_TREE,
+	unix.SYS_PTRACE, unix.SYS_PROCESS_VM_READV, unix.SYS_PROCESS_VM_WRITEV,
+	unix.SYS_KEXEC_LOAD, unix.SYS_KEXEC_FILE_LOAD,
+	unix.SYS_INIT_MODULE, unix.SYS_FINIT_MODULE, unix.SYS_DELETE_MODULE,
+	unix.SYS_BPF, unix.SYS_PERF_EVENT_OPEN,
+	unix.SYS_KEYCTL, unix.SYS_ADD_KEY, unix.SYS_REQUEST_KEY,
+	unix.SYS_SETNS, unix.SYS_UNSHARE, unix.SYS_USERFAULTFD,
+	unix.SYS_SWAPON, unix.SYS_SWAPOFF, unix.SYS_REBOOT, unix.SYS_ACCT, unix.SYS_QUOTACTL, unix.SYS_SYSLOG,
+	unix.SYS_OPEN_BY_HANDLE_AT, unix.SYS_NAME_TO_HANDLE_AT,
+	unix.SYS_IO_URING_SETUP, unix.SYS_IO_URING_ENTER, unix.SYS_IO_URING_REGISTER,
+}
+
+// Offsets of the fields of struct seccomp_data. The first argument is read
+// through its low 32 bits, which come first on little-endian architectures.
+const (
+	seccompDataNR   = 0
+	seccompDataArch = 4
+	seccompDataArg0 = 16
+)
+
+// x32SyscallBit marks syscalls of the x32 ABI, which have their own numbers.
+const x32SyscallBit = 0x40000000
+
+// seccompFilter builds a BPF program that kills processes using a foreign
+// architecture and denies the syscalls in deniedSyscalls. Unless UNIX sockets
+// are allowed, it also denies creating them, so that the process cannot connect
+// to host services such as the Docker daemon, the user's D-Bus session or an
+// SSH agent. Connected socket pairs are still allowed.
+func seccompFilter(allowUnixSockets bool) []unix.SockFilter {
+	deny := unix.SECCOMP_RET_ERRNO | uint32(unix.EPERM)
+
+	// The program ends with the allow and deny returns, which the checks jump to
+	length := 4 + len(deniedSyscalls) + 2
+	if seccompArch == unix.AUDIT_ARCH_X86_64 {
+		length++
+	}
+	if !allowUnixSockets {
+		length += 3
+	}
+	allowIdx, denyIdx := length-2, length-1
+
+	filter := make([]unix.SockFilter, 0, length)
+	jumpTo := func(target int) uint8 { return uint8(target - len(filter) - 1) }
+
+	filter = append(filter,
+		bpfStmt(unix.BPF_LD|unix.BPF_W|unix.BPF_ABS, seccompDataArch),
+		bpfJump(unix.BPF_JMP|unix.BPF_JEQ|unix.BPF_K, seccompArch, 1, 0),
+		bpfStmt(unix.BPF_RET|unix.BPF_K, unix.SECCOMP_RET_KILL_PROCESS),
+		bpfStmt(unix.BPF_LD|unix.BPF_W|unix.BPF_ABS, seccompDataNR),
+	)
+	if seccompArch == unix.AUDIT_ARCH_X86_64 {
+		filter = append(filter, bpfJump(unix.BPF_JMP|unix.BPF_JGE|unix.BPF_K, x32SyscallBit, jumpTo(denyIdx), 0))
+	}
+	for _, nr := range deniedSyscalls {
+		filter = append(filter, bpfJump(unix.BPF_JMP|unix.BPF_JEQ|unix.BPF_K, nr, jumpTo(denyIdx), 0))
+	}
+	if !allowUnixSockets {
+		filter = append(filter, bpfJump(unix.BPF_JMP|unix.BPF_JEQ|unix.BPF_K, unix.SYS_SOCKET, 0, jumpTo(allowIdx)))
+		filter = append(filter, bpfStmt(unix.BPF_LD|unix.BPF_W|unix.BPF_ABS, seccompDataArg0))
+		filter = append(filter, bpfJump(unix.BPF_JMP|unix.BPF_JEQ|unix.BPF_K, unix.AF_UNIX, jumpTo(denyIdx), 0))
+	}
+	return append(filter,
+		bpfStmt(unix.BPF_RET|unix.BPF_K, unix.SECCOMP_RET_ALLOW),
+		bpfStmt(unix.BPF_RET|unix.BPF_K, deny),
+	)
+}
+
+// applySeccomp installs the seccomp filter on the current thread.
+// It requires no_new_privs to be set.
+func applySeccomp(spec *sandboxSpec) error {
+	filter := seccompFilter(spec.AllowUnixSockets)
+	prog := unix.SockFprog{Len: uint16(len(filter)), Filter: &filter[0]}
+	if err := unix.Prctl(unix.PR_SET_SECCOMP, unix.SECCOMP_MODE_FILTER, uintptr(unsafe.Pointer(&prog)), 0, 0); err != nil {
+		return fmt.Errorf("failed to install seccomp filter: %w", err)
+	}
+	return nil
+}
+
+func bpfStmt(code uint16, k uint32) unix.SockFilter {
+	return unix.SockFilter{Code: code, K: k}
+}
+
+func bpfJump(code uint16, k uint32, jt, jf uint8) unix.SockFilter {
+	return unix.SockFilter{Code: code, Jt: jt, Jf: jf, K: k}
+}
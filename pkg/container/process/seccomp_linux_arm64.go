@@ -0,0 +1,6 @@
+package process
+
+import "golang.org/x/sys/unix"
+
+// seccompArch is the audit architecture of the syscalls allowed by the seccomp filter.
+const seccompArch = unix.AUDIT_ARCH_AARCH64
@@ -0,0 +1,15 @@
+//go:build linux && !amd64 && !arm64
+
+package process
+
+import (
+	"fmt"
+	"os"
+)
+
+// applySeccomp is a no-op on architectures without a seccomp filter.
+// Landlock and namespaces still confine the process, but it can create UNIX sockets.
+func applySeccomp(*sandboxSpec) error {
+	fmt.Fprintln(os.Stderr, "toolhive sandbox: seccomp filtering is not supported on this architecture")
+	return nil
+}
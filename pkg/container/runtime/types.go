@@ -50,6 +50,8 @@ type ContainerInfo struct {
 	Name string
 	// Image is the container image
 	Image string
+	// ImageID is the ID of the image the container was created from, if the runtime uses images
+	ImageID string
 	// Status is the container status
 	// This is usually some human-readable context.
 	Status string
@@ -59,6 +61,9 @@ type ContainerInfo struct {
 	Created time.Time
 	// StartedAt is when the container was last started (changes on restart)
 	StartedAt time.Time
+	// ExitCode is the exit code of the last run of the container.
+	// It is only meaningful when the container is not running.
+	ExitCode int
 	// Labels is the container labels
 	Labels map[string]string
 	// Ports is the container port mappings
@@ -174,6 +179,14 @@ type Runtime interface {
 	IsRunning(ctx context.Context) error
 }
 
+// EgressLogStreamer is implemented by runtimes that route the outbound traffic of
+// network-isolated workloads through an egress proxy.
+type EgressLogStreamer interface {
+	// StreamEgressLog streams the access log of the egress proxy of a workload,
+	// starting at the given time, until the context is cancelled or the proxy stops.
+	StreamEgressLog(ctx context.Context, workloadName string, since time.Time) (io.ReadCloser, error)
+}
+
 // Monitor defines the interface for container monitoring
 type Monitor interface {
 	// StartMonitoring starts monitoring the container
@@ -227,6 +240,8 @@ type PermissionConfig struct {
 	SecurityOpt []string
 	// Privileged indicates whether the container should run in privileged mode
 	Privileged bool
+	// Resources are the CPU, memory and process limits of the container
+	Resources *permissions.ResourceLimits
 }
 
 // DeployWorkloadOptions represents configuration options for deploying a workload.
@@ -35,6 +35,10 @@ type Workload struct {
 	StatusContext string `json:"status_context,omitempty"`
 	// CreatedAt is the timestamp when the workload was created.
 	CreatedAt time.Time `json:"created_at"`
+	// Restarts is the number of times the workload was restarted after exiting unexpectedly.
+	Restarts int `json:"restarts,omitempty"`
+	// LastRestartAt is the timestamp of the last restart of the workload, if any.
+	LastRestartAt *time.Time `json:"last_restart_at,omitempty"`
 	// Labels are the container labels (excluding standard ToolHive labels)
 	Labels map[string]string `json:"labels,omitempty"`
 	// Group is the name of the group this workload belongs to, if any.
@@ -43,6 +47,21 @@ type Workload struct {
 	ToolsFilter []string `json:"tools,omitempty"`
 	// Remote indicates whether this is a remote workload (true) or a container workload (false).
 	Remote bool `json:"remote,omitempty"`
+	// ImageUpdate is the result of the last check for a newer image of the workload, if any.
+	ImageUpdate *ImageUpdate `json:"image_update,omitempty"`
+}
+
+// ImageUpdate is the result of checking if the registry has a newer image than the one
+// a workload runs, for the same image reference.
+type ImageUpdate struct {
+	// Image is the image reference that was checked.
+	Image string `json:"image"`
+	// Available indicates whether the registry has a different image for the reference.
+	Available bool `json:"available"`
+	// LatestDigest is the digest of the image the reference points to in the registry.
+	LatestDigest string `json:"latest_digest,omitempty"`
+	// CheckedAt is the timestamp of the check.
+	CheckedAt time.Time `json:"checked_at"`
 }
 
 // SortWorkloadsByName sorts a slice of Workload by the Name field in ascending alphabetical order.
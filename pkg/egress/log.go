@@ -0,0 +1,158 @@
+package egress
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/adrg/xdg"
+
+	"github.com/stacklok/toolhive/pkg/audit"
+	"github.com/stacklok/toolhive/pkg/logger"
+)
+
+// logDir is the directory of the egress logs, relative to the XDG data directory
+const logDir = "toolhive/egress"
+
+// LogPath returns the path of the egress log of a workload. The egress log is a
+// JSON lines file of audit events, one per outbound request.
+func LogPath(workloadName string) (string, error) {
+	// Workload names may contain slashes, which are not valid in file names
+	fileName := strings.ReplaceAll(workloadName, "/", "-") + ".jsonl"
+	path, err := xdg.DataFile(filepath.Join(logDir, fileName))
+	if err != nil {
+		return "", fmt.Errorf("failed to get egress log path for workload %s: %w", workloadName, err)
+	}
+	return path, nil
+}
+
+// Collect reads the access log of the egress proxy of a workload and appends an audit
+// event to w for each request, until r is exhausted or the context is cancelled.
+// Lines that are not in SquidLogFormat are skipped.
+func Collect(ctx context.Context, workloadName string, r io.Reader, w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if ctx.Err() != nil {
+			return nil
+		}
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		record, err := ParseLine(workloadName, line)
+		if err != nil {
+			logger.Debugf("Skipping egress proxy log line %q: %v", line, err)
+			continue
+		}
+		event, err := record.AuditEvent()
+		if err != nil {
+			return err
+		}
+		if err := encoder.Encode(event); err != nil {
+			return fmt.Errorf("failed to write egress audit event: %w", err)
+		}
+	}
+	if err := scanner.Err(); err != nil && ctx.Err() == nil {
+		return fmt.Errorf("failed to read egress proxy log: %w", err)
+	}
+	return nil
+}
+
+// CollectToFile appends the audit events of the access log of the egress proxy of a
+// workload to its egress log.
+func CollectToFile(ctx context.Context, workloadName string, r io.Reader) error {
+	path, err := LogPath(workloadName)
+	if err != nil {
+		return err
+	}
+	// #nosec G304 - the path is derived from the workload name
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open egress log: %w", err)
+	}
+	defer func() {
+		if err := f.Close(); err != nil {
+			logger.Warnf("Failed to close egress log: %v", err)
+		}
+	}()
+	return Collect(ctx, workloadName, r, f)
+}
+
+// Filter selects records of an egress log.
+type Filter struct {
+	// Since excludes records before this time
+	Since time.Time
+	// Host only includes records for this destination host
+	Host string
+	// DeniedOnly only includes requests denied by the egress proxy
+	DeniedOnly bool
+}
+
+// Matches returns true if the record is selected by the filter.
+func (f *Filter) Matches(record *Record) bool {
+	if !f.Since.IsZero() && record.Time.Before(f.Since) {
+		return false
+	}
+	if f.Host != "" && !strings.EqualFold(record.Host, f.Host) {
+		return false
+	}
+	return !f.DeniedOnly || !record.Allowed
+}
+
+// Read reads the records of an egress log that match the filter.
+// Lines that are not egress audit events are skipped.
+func Read(r io.Reader, filter Filter) ([]*Record, error) {
+	var records []*Record
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var event audit.AuditEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			logger.Debugf("Skipping invalid egress log line: %v", err)
+			continue
+		}
+		record, err := RecordFromAuditEvent(&event)
+		if err != nil {
+			logger.Debugf("Skipping egress log entry: %v", err)
+			continue
+		}
+		if filter.Matches(record) {
+			records = append(records, record)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read egress log: %w", err)
+	}
+	return records, nil
+}
+
+// ReadWorkload reads the records of the egress log of a workload that match the filter.
+// It returns no records if the workload has no egress log.
+func ReadWorkload(workloadName string, filter Filter) ([]*Record, error) {
+	path, err := LogPath(workloadName)
+	if err != nil {
+		return nil, err
+	}
+	// #nosec G304 - the path is derived from the workload name
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open egress log: %w", err)
+	}
+	defer func() {
+		if err := f.Close(); err != nil {
+			logger.Debugf("Failed to close egress log: %v", err)
+		}
+	}()
+	return Read(f, filter)
+}
@@ -0,0 +1,75 @@
+package egress
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testAccessLog = `1718000000.000 172.18.0.2 TCP_TUNNEL 200 CONNECT api.github.com 443 1200 5300
+this is not an access log line
+
+1718000060.000 172.18.0.2 TCP_DENIED 403 CONNECT evil.example.com 443 0 3900
+1718000120.000 172.18.0.2 TCP_MISS 200 GET pypi.org 80 300 1000
+`
+
+func TestCollectAndRead(t *testing.T) {
+	t.Parallel()
+
+	var log bytes.Buffer
+	require.NoError(t, Collect(context.Background(), "fetch", strings.NewReader(testAccessLog), &log))
+	assert.Equal(t, 3, strings.Count(log.String(), "\n"), "one audit event per valid line")
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   []string
+	}{
+		{name: "no filter", want: []string{"api.github.com", "evil.example.com", "pypi.org"}},
+		{name: "since", filter: Filter{Since: time.Unix(1718000060, 0)}, want: []string{"evil.example.com", "pypi.org"}},
+		{name: "host", filter: Filter{Host: "PyPI.org"}, want: []string{"pypi.org"}},
+		{name: "denied only", filter: Filter{DeniedOnly: true}, want: []string{"evil.example.com"}},
+		{name: "no match", filter: Filter{Host: "example.org"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			records, err := Read(bytes.NewReader(log.Bytes()), tt.filter)
+			require.NoError(t, err)
+
+			var hosts []string
+			for _, record := range records {
+				assert.Equal(t, "fetch", record.Workload)
+				hosts = append(hosts, record.Host)
+			}
+			assert.Equal(t, tt.want, hosts)
+		})
+	}
+}
+
+func TestCollect_CancelledContext(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var log bytes.Buffer
+	require.NoError(t, Collect(ctx, "fetch", strings.NewReader(testAccessLog), &log))
+	assert.Empty(t, log.String())
+}
+
+func TestRead_SkipsInvalidLines(t *testing.T) {
+	t.Parallel()
+
+	input := "not json\n" + `{"type":"mcp_tool_call","metadata":{"auditId":"1"}}` + "\n"
+	records, err := Read(strings.NewReader(input), Filter{})
+	require.NoError(t, err)
+	assert.Empty(t, records)
+}
@@ -0,0 +1,168 @@
+// Package egress collects the outbound traffic of MCP servers recorded by the egress
+// proxy, turns it into audit events and suggests least-privilege outbound permissions.
+package egress
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stacklok/toolhive/pkg/audit"
+)
+
+// SquidLogFormat is the squid logformat directive of the access log of the egress proxy.
+// The fields are: time, client address, squid result code, HTTP status, method,
+// destination host, destination port, bytes received from the client and bytes sent to it.
+const SquidLogFormat = "logformat toolhive %ts.%03tu %>a %Ss %>Hs %rm %>rd %>rP %>st %<st"
+
+// SquidLogFormatName is the name of the log format defined by SquidLogFormat.
+const SquidLogFormatName = "toolhive"
+
+// squidLogFields is the number of fields of a line in SquidLogFormat
+const squidLogFields = 9
+
+const (
+	// EventTypeEgressRequest is the audit event type of an outbound request of an MCP server
+	EventTypeEgressRequest = "egress_request"
+
+	// ComponentEgressProxy is the audit component of events recorded by the egress proxy
+	ComponentEgressProxy = "egress-proxy"
+
+	// SubjectKeyWorkload is the key of the workload in the subjects of an egress audit event
+	SubjectKeyWorkload = "workload"
+
+	// TargetKeyHost is the key of the destination host in the target of an egress audit event
+	TargetKeyHost = "host"
+	// TargetKeyPort is the key of the destination port in the target of an egress audit event
+	TargetKeyPort = "port"
+)
+
+// Record is an outbound request of an MCP server handled by the egress proxy.
+type Record struct {
+	// Time is when the egress proxy finished handling the request
+	Time time.Time `json:"time"`
+	// Workload is the name of the workload that made the request
+	Workload string `json:"workload"`
+	// ClientAddress is the address of the MCP server container
+	ClientAddress string `json:"client_address"`
+	// Method is the HTTP method of the request (CONNECT for HTTPS)
+	Method string `json:"method"`
+	// Host is the destination host
+	Host string `json:"host"`
+	// Port is the destination port, or 0 if unknown
+	Port int `json:"port"`
+	// Allowed is false if the egress proxy denied the request
+	Allowed bool `json:"allowed"`
+	// ResultCode is the squid result code (e.g. TCP_TUNNEL, TCP_DENIED)
+	ResultCode string `json:"result_code"`
+	// HTTPStatus is the HTTP status returned to the MCP server
+	HTTPStatus int `json:"http_status"`
+	// BytesSent is the number of bytes the MCP server sent
+	BytesSent int64 `json:"bytes_sent"`
+	// BytesReceived is the number of bytes the MCP server received
+	BytesReceived int64 `json:"bytes_received"`
+}
+
+// Verdict returns the outcome of the request as an audit outcome.
+func (r *Record) Verdict() string {
+	if r.Allowed {
+		return audit.OutcomeSuccess
+	}
+	return audit.OutcomeDenied
+}
+
+// ParseLine parses a line of the access log of the egress proxy, written in SquidLogFormat.
+func ParseLine(workload, line string) (*Record, error) {
+	fields := strings.Fields(line)
+	if len(fields) != squidLogFields {
+		return nil, fmt.Errorf("expected %d fields, got %d", squidLogFields, len(fields))
+	}
+
+	seconds, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid time %q: %w", fields[0], err)
+	}
+	httpStatus, err := parseOptionalInt(fields[3])
+	if err != nil {
+		return nil, fmt.Errorf("invalid HTTP status %q: %w", fields[3], err)
+	}
+	port, err := parseOptionalInt(fields[6])
+	if err != nil {
+		return nil, fmt.Errorf("invalid port %q: %w", fields[6], err)
+	}
+	bytesSent, err := parseOptionalInt(fields[7])
+	if err != nil {
+		return nil, fmt.Errorf("invalid request size %q: %w", fields[7], err)
+	}
+	bytesReceived, err := parseOptionalInt(fields[8])
+	if err != nil {
+		return nil, fmt.Errorf("invalid reply size %q: %w", fields[8], err)
+	}
+
+	host := fields[5]
+	if host == "-" {
+		host = ""
+	}
+
+	return &Record{
+		Time:          time.UnixMilli(int64(seconds * 1000)).UTC(),
+		Workload:      workload,
+		ClientAddress: fields[1],
+		ResultCode:    fields[2],
+		HTTPStatus:    int(httpStatus),
+		Method:        fields[4],
+		Host:          strings.ToLower(host),
+		Port:          int(port),
+		Allowed:       !strings.Contains(fields[2], "DENIED"),
+		BytesSent:     bytesSent,
+		BytesReceived: bytesReceived,
+	}, nil
+}
+
+// parseOptionalInt parses an integer field, where squid logs "-" for missing values.
+func parseOptionalInt(field string) (int64, error) {
+	if field == "-" {
+		return 0, nil
+	}
+	return strconv.ParseInt(field, 10, 64)
+}
+
+// AuditEvent converts the record into an audit event. The record itself is the data of the event.
+func (r *Record) AuditEvent() (*audit.AuditEvent, error) {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal egress record: %w", err)
+	}
+
+	event := audit.NewAuditEvent(
+		EventTypeEgressRequest,
+		audit.EventSource{Type: audit.SourceTypeNetwork, Value: r.ClientAddress},
+		r.Verdict(),
+		map[string]string{SubjectKeyWorkload: r.Workload},
+		ComponentEgressProxy,
+	).WithTarget(map[string]string{
+		TargetKeyHost:         r.Host,
+		TargetKeyPort:         strconv.Itoa(r.Port),
+		audit.TargetKeyMethod: r.Method,
+	})
+	event.LoggedAt = r.Time
+	raw := json.RawMessage(data)
+	return event.WithData(&raw), nil
+}
+
+// RecordFromAuditEvent extracts the record from an egress audit event.
+func RecordFromAuditEvent(event *audit.AuditEvent) (*Record, error) {
+	if event.Type != EventTypeEgressRequest {
+		return nil, fmt.Errorf("unexpected audit event type %q", event.Type)
+	}
+	if event.Data == nil {
+		return nil, fmt.Errorf("audit event %s has no data", event.Metadata.AuditID)
+	}
+	var record Record
+	if err := json.Unmarshal(*event.Data, &record); err != nil {
+		return nil, fmt.Errorf("invalid data in audit event %s: %w", event.Metadata.AuditID, err)
+	}
+	return &record, nil
+}
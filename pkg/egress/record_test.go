@@ -0,0 +1,133 @@
+package egress
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stacklok/toolhive/pkg/audit"
+)
+
+func TestParseLine(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		line    string
+		want    *Record
+		wantErr string
+	}{
+		{
+			name: "allowed HTTPS request",
+			line: "1718000000.123 172.18.0.2 TCP_TUNNEL 200 CONNECT API.GitHub.com 443 1200 5300",
+			want: &Record{
+				Time:          time.UnixMilli(1718000000123).UTC(),
+				Workload:      "fetch",
+				ClientAddress: "172.18.0.2",
+				Method:        "CONNECT",
+				Host:          "api.github.com",
+				Port:          443,
+				Allowed:       true,
+				ResultCode:    "TCP_TUNNEL",
+				HTTPStatus:    200,
+				BytesSent:     1200,
+				BytesReceived: 5300,
+			},
+		},
+		{
+			name: "denied request with missing fields",
+			line: "1718000001.000 172.18.0.2 TCP_DENIED 403 GET example.com - - 3900",
+			want: &Record{
+				Time:          time.Unix(1718000001, 0).UTC(),
+				Workload:      "fetch",
+				ClientAddress: "172.18.0.2",
+				Method:        "GET",
+				Host:          "example.com",
+				Allowed:       false,
+				ResultCode:    "TCP_DENIED",
+				HTTPStatus:    403,
+				BytesReceived: 3900,
+			},
+		},
+		{
+			name: "missing host",
+			line: "1718000002.000 172.18.0.2 NONE_NONE 400 - - - 0 0",
+			want: &Record{
+				Time:          time.Unix(1718000002, 0).UTC(),
+				Workload:      "fetch",
+				ClientAddress: "172.18.0.2",
+				Method:        "-",
+				Allowed:       true,
+				ResultCode:    "NONE_NONE",
+				HTTPStatus:    400,
+			},
+		},
+		{
+			name:    "default squid format",
+			line:    "1718000000.123 5 172.18.0.2 TCP_TUNNEL/200 5300 CONNECT api.github.com:443 - HIER_DIRECT/1.2.3.4 -",
+			wantErr: "expected 9 fields",
+		},
+		{
+			name:    "invalid time",
+			line:    "yesterday 172.18.0.2 TCP_TUNNEL 200 CONNECT api.github.com 443 0 0",
+			wantErr: "invalid time",
+		},
+		{
+			name:    "invalid port",
+			line:    "1718000000.123 172.18.0.2 TCP_TUNNEL 200 CONNECT api.github.com https 0 0",
+			wantErr: "invalid port",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			record, err := ParseLine("fetch", tt.line)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, record)
+		})
+	}
+}
+
+func TestRecord_AuditEvent(t *testing.T) {
+	t.Parallel()
+
+	record, err := ParseLine("fetch", "1718000001.000 172.18.0.2 TCP_DENIED 403 CONNECT evil.example.com 443 0 3900")
+	require.NoError(t, err)
+
+	event, err := record.AuditEvent()
+	require.NoError(t, err)
+
+	assert.Equal(t, EventTypeEgressRequest, event.Type)
+	assert.Equal(t, ComponentEgressProxy, event.Component)
+	assert.Equal(t, audit.OutcomeDenied, event.Outcome)
+	assert.Equal(t, record.Time, event.LoggedAt)
+	assert.Equal(t, audit.EventSource{Type: audit.SourceTypeNetwork, Value: "172.18.0.2"}, event.Source)
+	assert.Equal(t, "fetch", event.Subjects[SubjectKeyWorkload])
+	assert.Equal(t, "evil.example.com", event.Target[TargetKeyHost])
+	assert.Equal(t, "443", event.Target[TargetKeyPort])
+	assert.Equal(t, "CONNECT", event.Target[audit.TargetKeyMethod])
+
+	roundTrip, err := RecordFromAuditEvent(event)
+	require.NoError(t, err)
+	assert.Equal(t, record, roundTrip)
+}
+
+func TestRecordFromAuditEvent_Invalid(t *testing.T) {
+	t.Parallel()
+
+	other := audit.NewAuditEvent("mcp_tool_call", audit.EventSource{}, audit.OutcomeSuccess, nil, "toolhive")
+	_, err := RecordFromAuditEvent(other)
+	assert.ErrorContains(t, err, "unexpected audit event type")
+
+	noData := audit.NewAuditEvent(EventTypeEgressRequest, audit.EventSource{}, audit.OutcomeSuccess, nil, ComponentEgressProxy)
+	_, err = RecordFromAuditEvent(noData)
+	assert.ErrorContains(t, err, "has no data")
+}
@@ -0,0 +1,88 @@
+package egress
+
+import (
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/stacklok/toolhive/pkg/permissions"
+)
+
+// HostSummary aggregates the requests of a workload to a destination host.
+type HostSummary struct {
+	// Host is the destination host
+	Host string `json:"host"`
+	// Ports are the destination ports
+	Ports []int `json:"ports"`
+	// Requests is the number of requests
+	Requests int `json:"requests"`
+	// Denied is the number of requests denied by the egress proxy
+	Denied int `json:"denied"`
+	// BytesSent is the number of bytes sent to the host
+	BytesSent int64 `json:"bytes_sent"`
+	// BytesReceived is the number of bytes received from the host
+	BytesReceived int64 `json:"bytes_received"`
+	// LastSeen is the time of the last request
+	LastSeen time.Time `json:"last_seen"`
+}
+
+// Summarize aggregates records per destination host, sorted by host.
+func Summarize(records []*Record) []HostSummary {
+	byHost := map[string]*HostSummary{}
+	for _, record := range records {
+		if record.Host == "" {
+			continue
+		}
+		summary, ok := byHost[record.Host]
+		if !ok {
+			summary = &HostSummary{Host: record.Host}
+			byHost[record.Host] = summary
+		}
+		if record.Port > 0 && !slices.Contains(summary.Ports, record.Port) {
+			summary.Ports = append(summary.Ports, record.Port)
+		}
+		summary.Requests++
+		if !record.Allowed {
+			summary.Denied++
+		}
+		summary.BytesSent += record.BytesSent
+		summary.BytesReceived += record.BytesReceived
+		if record.Time.After(summary.LastSeen) {
+			summary.LastSeen = record.Time
+		}
+	}
+
+	summaries := make([]HostSummary, 0, len(byHost))
+	for _, summary := range byHost {
+		slices.Sort(summary.Ports)
+		summaries = append(summaries, *summary)
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].Host < summaries[j].Host
+	})
+	return summaries
+}
+
+// SuggestOutboundPermissions returns the least-privilege outbound permissions that allow
+// the hosts and ports of the records. Requests denied by the egress proxy are only
+// included if includeDenied is true, e.g. to find what a restrictive profile is missing.
+func SuggestOutboundPermissions(records []*Record, includeDenied bool) *permissions.OutboundNetworkPermissions {
+	outbound := &permissions.OutboundNetworkPermissions{
+		AllowHost: []string{},
+		AllowPort: []int{},
+	}
+	for _, record := range records {
+		if record.Host == "" || (!record.Allowed && !includeDenied) {
+			continue
+		}
+		if !slices.Contains(outbound.AllowHost, record.Host) {
+			outbound.AllowHost = append(outbound.AllowHost, record.Host)
+		}
+		if record.Port > 0 && !slices.Contains(outbound.AllowPort, record.Port) {
+			outbound.AllowPort = append(outbound.AllowPort, record.Port)
+		}
+	}
+	slices.Sort(outbound.AllowHost)
+	slices.Sort(outbound.AllowPort)
+	return outbound
+}
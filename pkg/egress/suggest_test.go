@@ -0,0 +1,50 @@
+package egress
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testRecords() []*Record {
+	return []*Record{
+		{Time: time.Unix(100, 0), Host: "pypi.org", Port: 443, Allowed: true, BytesSent: 10, BytesReceived: 100},
+		{Time: time.Unix(300, 0), Host: "api.github.com", Port: 443, Allowed: true, BytesSent: 20, BytesReceived: 200},
+		{Time: time.Unix(200, 0), Host: "pypi.org", Port: 80, Allowed: true, BytesSent: 30, BytesReceived: 300},
+		{Time: time.Unix(400, 0), Host: "evil.example.com", Port: 8443, Allowed: false},
+		{Time: time.Unix(500, 0), Method: "-", Allowed: true},
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	t.Parallel()
+
+	summaries := Summarize(testRecords())
+
+	assert.Equal(t, []HostSummary{
+		{Host: "api.github.com", Ports: []int{443}, Requests: 1, BytesSent: 20, BytesReceived: 200, LastSeen: time.Unix(300, 0)},
+		{Host: "evil.example.com", Ports: []int{8443}, Requests: 1, Denied: 1, LastSeen: time.Unix(400, 0)},
+		{Host: "pypi.org", Ports: []int{80, 443}, Requests: 2, BytesSent: 40, BytesReceived: 400, LastSeen: time.Unix(200, 0)},
+	}, summaries)
+
+	assert.Empty(t, Summarize(nil))
+}
+
+func TestSuggestOutboundPermissions(t *testing.T) {
+	t.Parallel()
+
+	allowed := SuggestOutboundPermissions(testRecords(), false)
+	assert.False(t, allowed.InsecureAllowAll)
+	assert.Equal(t, []string{"api.github.com", "pypi.org"}, allowed.AllowHost)
+	assert.Equal(t, []int{80, 443}, allowed.AllowPort)
+
+	withDenied := SuggestOutboundPermissions(testRecords(), true)
+	assert.Equal(t, []string{"api.github.com", "evil.example.com", "pypi.org"}, withDenied.AllowHost)
+	assert.Equal(t, []int{80, 443, 8443}, withDenied.AllowPort)
+
+	empty := SuggestOutboundPermissions(nil, false)
+	assert.NotNil(t, empty.AllowHost)
+	assert.NotNil(t, empty.AllowPort)
+	assert.Empty(t, empty.AllowHost)
+}
@@ -0,0 +1,87 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"golang.org/x/mod/semver"
+
+	"github.com/stacklok/toolhive/pkg/groups"
+	"github.com/stacklok/toolhive/pkg/runner"
+)
+
+// CurrentBundleSchemaVersion is the current version of the group bundle schema
+const CurrentBundleSchemaVersion = "v0.1.0"
+
+// Bundle is the export of a group: its defaults and the run configurations of its workloads.
+// The defaults are also merged into the run configurations, they are kept so that
+// workloads run in the group after it is imported use them too.
+type Bundle struct {
+	// SchemaVersion is the version of the bundle schema
+	SchemaVersion string `json:"schema_version"`
+	// Group is the name of the group
+	Group string `json:"group"`
+	// Defaults are the defaults of the group
+	Defaults *groups.Defaults `json:"defaults,omitempty"`
+	// Workloads are the run configurations of the workloads of the group
+	Workloads []*runner.RunConfig `json:"workloads"`
+}
+
+// bundleJSON is a bundle with its workloads kept as JSON, so that they are read like
+// exported run configurations
+type bundleJSON struct {
+	SchemaVersion string            `json:"schema_version"`
+	Group         string            `json:"group"`
+	Defaults      *groups.Defaults  `json:"defaults,omitempty"`
+	Workloads     []json.RawMessage `json:"workloads"`
+}
+
+// WriteBundle writes a group bundle as JSON
+func WriteBundle(bundle *Bundle, w io.Writer) error {
+	if bundle.SchemaVersion == "" {
+		bundle.SchemaVersion = CurrentBundleSchemaVersion
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(bundle)
+}
+
+// ReadBundle reads a group bundle from JSON and validates the schema versions of
+// the bundle and of its run configurations
+func ReadBundle(r io.Reader) (*Bundle, error) {
+	var raw bundleJSON
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("failed to parse bundle: %w", err)
+	}
+
+	if !semver.IsValid(raw.SchemaVersion) {
+		return nil, fmt.Errorf("invalid bundle schema version %q", raw.SchemaVersion)
+	}
+	if semver.Compare(semver.MajorMinor(raw.SchemaVersion), semver.MajorMinor(CurrentBundleSchemaVersion)) > 0 {
+		return nil, fmt.Errorf("bundle schema version %s is newer than the supported version %s, upgrade ToolHive to read it",
+			raw.SchemaVersion, CurrentBundleSchemaVersion)
+	}
+	if raw.Group == "" {
+		return nil, fmt.Errorf("bundle has no group")
+	}
+	if err := raw.Defaults.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid group defaults in bundle: %w", err)
+	}
+
+	bundle := &Bundle{
+		SchemaVersion: raw.SchemaVersion,
+		Group:         raw.Group,
+		Defaults:      raw.Defaults,
+		Workloads:     make([]*runner.RunConfig, 0, len(raw.Workloads)),
+	}
+	for i, data := range raw.Workloads {
+		config, err := ReadRunConfig(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("invalid workload %d in bundle: %w", i, err)
+		}
+		bundle.Workloads = append(bundle.Workloads, config)
+	}
+	return bundle, nil
+}
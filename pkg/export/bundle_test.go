@@ -0,0 +1,95 @@
+package export
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stacklok/toolhive/pkg/groups"
+	"github.com/stacklok/toolhive/pkg/runner"
+)
+
+func TestWriteBundle_ReadBundle(t *testing.T) {
+	t.Parallel()
+
+	fetch := runner.NewRunConfig()
+	fetch.Image = "ghcr.io/stackloklabs/gofetch/server:latest"
+	fetch.Name = "fetch"
+	fetch.Group = "team-tools"
+	git := runner.NewRunConfig()
+	git.Image = "ghcr.io/example/git:latest"
+	git.Name = "git"
+	git.Group = "team-tools"
+	git.Secrets = []string{"github-token,target=GITHUB_TOKEN"}
+
+	original := &Bundle{
+		Group:     "team-tools",
+		Defaults:  &groups.Defaults{EnvVars: map[string]string{"LOG_LEVEL": "info"}},
+		Workloads: []*runner.RunConfig{fetch, git},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteBundle(original, &buf))
+
+	format, err := DetectFormat(buf.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, FormatBundle, format)
+
+	bundle, err := ReadBundle(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, CurrentBundleSchemaVersion, bundle.SchemaVersion)
+	assert.Equal(t, "team-tools", bundle.Group)
+	assert.Equal(t, original.Defaults, bundle.Defaults)
+	require.Len(t, bundle.Workloads, 2)
+	assert.Equal(t, "fetch", bundle.Workloads[0].Name)
+	assert.Equal(t, git.Secrets, bundle.Workloads[1].Secrets)
+}
+
+func TestReadBundle_Invalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{
+			name:    "newer bundle schema",
+			data:    `{"schema_version": "v2.0.0", "group": "team-tools", "workloads": []}`,
+			wantErr: "bundle schema version v2.0.0 is newer",
+		},
+		{
+			name:    "invalid bundle schema",
+			data:    `{"schema_version": "latest", "group": "team-tools", "workloads": []}`,
+			wantErr: `invalid bundle schema version "latest"`,
+		},
+		{
+			name:    "missing group",
+			data:    `{"schema_version": "v0.1.0", "workloads": []}`,
+			wantErr: "bundle has no group",
+		},
+		{
+			name:    "invalid defaults",
+			data:    `{"schema_version": "v0.1.0", "group": "team-tools", "defaults": {"secrets": ["bad"]}, "workloads": []}`,
+			wantErr: "invalid group defaults",
+		},
+		{
+			name: "newer workload schema",
+			data: `{"schema_version": "v0.1.0", "group": "team-tools", "workloads": [` +
+				`{"schema_version": "v99.0.0", "image": "fetch"}]}`,
+			wantErr: "invalid workload 0 in bundle",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := ReadBundle(strings.NewReader(tt.data))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
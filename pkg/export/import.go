@@ -0,0 +1,55 @@
+package export
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/stacklok/toolhive/pkg/runner"
+)
+
+// Format is the format of an exported file
+type Format string
+
+const (
+	// FormatJSON is a RunConfig as JSON, written by `thv export`
+	FormatJSON Format = "json"
+	// FormatK8s is a Kubernetes MCPServer resource, written by `thv export --format k8s`
+	FormatK8s Format = "k8s"
+	// FormatBundle is a group bundle, written by `thv export --group`
+	FormatBundle Format = "bundle"
+)
+
+// DetectFormat returns the format of an exported file from its content
+func DetectFormat(data []byte) (Format, error) {
+	// YAML is a superset of JSON, so this reads both the JSON and the YAML formats
+	var fields map[string]json.RawMessage
+	if err := yaml.Unmarshal(data, &fields); err != nil {
+		return "", fmt.Errorf("failed to parse file: %w", err)
+	}
+
+	switch {
+	case fields["kind"] != nil:
+		return FormatK8s, nil
+	case fields["workloads"] != nil:
+		return FormatBundle, nil
+	case fields["schema_version"] != nil || fields["image"] != nil || fields["remote_url"] != nil:
+		return FormatJSON, nil
+	}
+	return "", errors.New("unrecognized file format: expected a RunConfig, an MCPServer resource or a group bundle")
+}
+
+// ReadRunConfig reads an exported RunConfig from JSON and validates its schema version
+func ReadRunConfig(r io.Reader) (*runner.RunConfig, error) {
+	config, err := runner.ReadJSON(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse run configuration: %w", err)
+	}
+	if err := runner.ValidateSchemaVersion(config.SchemaVersion); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
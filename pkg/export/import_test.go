@@ -0,0 +1,93 @@
+package export
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stacklok/toolhive/pkg/runner"
+	"github.com/stacklok/toolhive/pkg/transport/types"
+)
+
+func TestDetectFormat(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		data    string
+		want    Format
+		wantErr string
+	}{
+		{
+			name: "run config",
+			data: `{"schema_version": "v0.1.0", "image": "fetch", "name": "fetch"}`,
+			want: FormatJSON,
+		},
+		{
+			name: "remote run config",
+			data: `{"remote_url": "https://mcp.example.com", "name": "remote"}`,
+			want: FormatJSON,
+		},
+		{
+			name: "mcp server",
+			data: "apiVersion: toolhive.stacklok.dev/v1alpha1\nkind: MCPServer\nmetadata:\n  name: fetch\n",
+			want: FormatK8s,
+		},
+		{
+			name: "bundle",
+			data: `{"schema_version": "v0.1.0", "group": "team-tools", "workloads": []}`,
+			want: FormatBundle,
+		},
+		{
+			name:    "unknown",
+			data:    `{"group": "team-tools"}`,
+			wantErr: "unrecognized file format",
+		},
+		{
+			name:    "not a document",
+			data:    "- fetch\n- git\n",
+			wantErr: "failed to parse file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			format, err := DetectFormat([]byte(tt.data))
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, format)
+		})
+	}
+}
+
+func TestReadRunConfig(t *testing.T) {
+	t.Parallel()
+
+	original := runner.NewRunConfig()
+	original.Image = "ghcr.io/stackloklabs/gofetch/server:latest"
+	original.Name = "fetch"
+	original.Transport = types.TransportTypeStreamableHTTP
+
+	var buf bytes.Buffer
+	require.NoError(t, original.WriteJSON(&buf))
+	config, err := ReadRunConfig(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, original.Image, config.Image)
+	assert.Equal(t, original.Name, config.Name)
+
+	_, err = ReadRunConfig(strings.NewReader(`{"schema_version": "v99.0.0", "image": "fetch"}`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "newer than the supported version")
+
+	_, err = ReadRunConfig(strings.NewReader(`{"schema_version": 1}`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to parse run configuration")
+}
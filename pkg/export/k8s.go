@@ -1,17 +1,23 @@
-// Package export provides functionality for exporting ToolHive configurations to various formats.
+// Package export provides functionality for exporting ToolHive configurations to various formats
+// and importing them back.
 package export
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"strings"
 
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/yaml"
 
 	v1alpha1 "github.com/stacklok/toolhive/cmd/thv-operator/api/v1alpha1"
+	"github.com/stacklok/toolhive/cmd/thv-operator/pkg/runconfig"
+	"github.com/stacklok/toolhive/pkg/authz"
 	"github.com/stacklok/toolhive/pkg/authz/authorizers/cedar"
+	"github.com/stacklok/toolhive/pkg/permissions"
 	"github.com/stacklok/toolhive/pkg/runner"
 	"github.com/stacklok/toolhive/pkg/transport/types"
 )
@@ -120,6 +126,12 @@ func runConfigToMCPServer(config *runner.RunConfig) (*v1alpha1.MCPServer, error)
 			Type: v1alpha1.PermissionProfileTypeBuiltin,
 			Name: "none", // Default to none, user should adjust based on their needs
 		}
+
+		resources, err := convertResourceLimits(config.PermissionProfile.Resources)
+		if err != nil {
+			return nil, err
+		}
+		mcpServer.Spec.Resources = resources
 	}
 
 	// Convert OIDC config
@@ -196,6 +208,32 @@ func runConfigToMCPServer(config *runner.RunConfig) (*v1alpha1.MCPServer, error)
 	return mcpServer, nil
 }
 
+// convertResourceLimits converts the resource limits of a permission profile to the resources
+// of the MCP server container. CPU shares become a CPU request, capped at the CPU limit.
+// The pids limit and /tmp size have no MCPServer equivalent and are not exported.
+func convertResourceLimits(limits *permissions.ResourceLimits) (v1alpha1.ResourceRequirements, error) {
+	var resources v1alpha1.ResourceRequirements
+	if limits.IsEmpty() {
+		return resources, nil
+	}
+	if err := limits.Validate(); err != nil {
+		return resources, fmt.Errorf("invalid resource limits: %w", err)
+	}
+
+	resources.Limits.CPU = limits.CPUs
+	resources.Limits.Memory = limits.Memory
+
+	if millis := limits.CPURequestMillis(); millis > 0 {
+		request := resource.NewMilliQuantity(millis, resource.DecimalSI)
+		if limits.CPUs != "" && request.Cmp(resource.MustParse(limits.CPUs)) > 0 {
+			resources.Requests.CPU = limits.CPUs
+		} else {
+			resources.Requests.CPU = request.String()
+		}
+	}
+	return resources, nil
+}
+
 // parseVolumeString parses a volume string in the format "host-path:container-path[:ro]"
 func parseVolumeString(volStr string, index int) (v1alpha1.Volume, error) {
 	parts := strings.Split(volStr, ":")
@@ -247,3 +285,146 @@ func sanitizeK8sName(name string) string {
 
 	return sanitized
 }
+
+// ReadK8sManifest reads a Kubernetes MCPServer resource from YAML or JSON
+func ReadK8sManifest(r io.Reader) (*v1alpha1.MCPServer, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read MCPServer manifest: %w", err)
+	}
+
+	var mcpServer v1alpha1.MCPServer
+	if err := yaml.UnmarshalStrict(data, &mcpServer); err != nil {
+		return nil, fmt.Errorf("failed to parse MCPServer manifest: %w", err)
+	}
+	if mcpServer.Kind != "MCPServer" {
+		return nil, fmt.Errorf("unsupported resource kind %q: expected MCPServer", mcpServer.Kind)
+	}
+	if mcpServer.Name == "" {
+		return nil, fmt.Errorf("MCPServer manifest has no name")
+	}
+	if mcpServer.Spec.Image == "" {
+		return nil, fmt.Errorf("MCPServer manifest has no image")
+	}
+	return &mcpServer, nil
+}
+
+// K8sManifestToRunConfig converts a Kubernetes MCPServer resource to a RunConfig, the inverse of
+// WriteK8sManifest. The options are applied after the settings of the resource, e.g. to set the runtime.
+//
+// Kubernetes secrets are referenced as the local secrets with the same name. Settings that only
+// exist in Kubernetes, such as ConfigMap permission profiles, pod templates, service accounts and
+// OIDC or authorization configurations held in other resources, are not converted.
+func K8sManifestToRunConfig(
+	ctx context.Context,
+	mcpServer *v1alpha1.MCPServer,
+	opts ...runner.RunConfigBuilderOption,
+) (*runner.RunConfig, error) {
+	spec := mcpServer.Spec
+
+	envVars := make(map[string]string, len(spec.Env))
+	for _, env := range spec.Env {
+		envVars[env.Name] = env.Value
+	}
+
+	volumes := make([]string, 0, len(spec.Volumes))
+	for _, volume := range spec.Volumes {
+		volumeString := volume.HostPath + ":" + volume.MountPath
+		if volume.ReadOnly {
+			volumeString += ":ro"
+		}
+		volumes = append(volumes, volumeString)
+	}
+
+	secretParams := make([]string, 0, len(spec.Secrets))
+	for _, secret := range spec.Secrets {
+		target := secret.TargetEnvName
+		if target == "" {
+			target = secret.Key
+		}
+		secretParams = append(secretParams, fmt.Sprintf("%s,target=%s", secret.Name, target))
+	}
+
+	proxyPort := spec.ProxyPort
+	if proxyPort == 0 {
+		proxyPort = spec.Port
+	}
+	mcpPort := spec.McpPort
+	if mcpPort == 0 {
+		mcpPort = spec.TargetPort
+	}
+
+	options := []runner.RunConfigBuilderOption{
+		runner.WithName(mcpServer.Name),
+		runner.WithImage(spec.Image),
+		runner.WithCmdArgs(spec.Args),
+		runner.WithTransportAndPorts(spec.Transport, int(proxyPort), int(mcpPort)),
+		runner.WithProxyMode(types.ProxyMode(spec.ProxyMode)),
+		runner.WithVolumes(volumes),
+		runner.WithSecrets(secretParams),
+		runner.WithToolsFilter(spec.ToolsFilter),
+		runner.WithTrustProxyHeaders(spec.TrustProxyHeaders),
+		runner.WithEndpointPrefix(spec.EndpointPrefix),
+		runner.WithGroup(spec.GroupRef),
+	}
+
+	if spec.PermissionProfile != nil {
+		if spec.PermissionProfile.Type != v1alpha1.PermissionProfileTypeBuiltin {
+			return nil, fmt.Errorf("permission profiles of type %q cannot be imported", spec.PermissionProfile.Type)
+		}
+		options = append(options, runner.WithPermissionProfileNameOrPath(spec.PermissionProfile.Name))
+	}
+
+	if spec.Resources.Limits.CPU != "" || spec.Resources.Limits.Memory != "" {
+		options = append(options, runner.WithResourceLimits(&permissions.ResourceLimits{
+			CPUs:   spec.Resources.Limits.CPU,
+			Memory: spec.Resources.Limits.Memory,
+		}))
+	}
+
+	if oidcConfig := spec.OIDCConfig; oidcConfig != nil {
+		if oidcConfig.Type != v1alpha1.OIDCConfigTypeInline || oidcConfig.Inline == nil {
+			return nil, fmt.Errorf("OIDC configurations of type %q cannot be imported", oidcConfig.Type)
+		}
+		inline := oidcConfig.Inline
+		options = append(options, runner.WithOIDCConfig(inline.Issuer, inline.Audience, inline.JWKSURL,
+			inline.IntrospectionURL, inline.ClientID, inline.ClientSecret, "", "", oidcConfig.ResourceURL,
+			inline.JWKSAllowPrivateIP, inline.InsecureAllowHTTP, inline.Scopes))
+	}
+
+	if authzConfig := spec.AuthzConfig; authzConfig != nil {
+		if authzConfig.Type != v1alpha1.AuthzConfigTypeInline || authzConfig.Inline == nil {
+			return nil, fmt.Errorf("authorization configurations of type %q cannot be imported", authzConfig.Type)
+		}
+		config, err := authz.NewConfig(cedar.Config{
+			Version: "v1",
+			Type:    cedar.ConfigType,
+			Options: &cedar.ConfigOptions{
+				Policies:     authzConfig.Inline.Policies,
+				EntitiesJSON: authzConfig.Inline.EntitiesJSON,
+			},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create authorization configuration: %w", err)
+		}
+		options = append(options, runner.WithAuthzConfig(config))
+	}
+
+	runconfig.AddAuditConfigOptions(&options, spec.Audit)
+	runconfig.AddTelemetryConfigOptions(ctx, &options, spec.Telemetry, mcpServer.Name)
+	if spec.RateLimit != nil {
+		options = append(options, runner.WithRateLimitConfig(spec.RateLimit))
+	}
+
+	options = append(options, opts...)
+	config, err := runner.NewRunConfigBuilder(ctx, nil, envVars, &runner.DetachedEnvVarValidator{}, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Populate the middlewares from the configuration, as the operator does
+	if err := runner.PopulateMiddlewareConfigs(config); err != nil {
+		return nil, fmt.Errorf("failed to populate middleware configs: %w", err)
+	}
+	return config, nil
+}
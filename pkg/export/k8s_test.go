@@ -145,6 +145,32 @@ func TestWriteK8sManifest(t *testing.T) {
 				require.NotNil(t, mcpServer.Spec.PermissionProfile)
 				assert.Equal(t, v1alpha1.PermissionProfileTypeBuiltin, mcpServer.Spec.PermissionProfile.Type)
 				assert.Equal(t, "none", mcpServer.Spec.PermissionProfile.Name)
+				assert.Equal(t, v1alpha1.ResourceRequirements{}, mcpServer.Spec.Resources)
+			},
+		},
+		{
+			name: "config with resource limits",
+			config: &runner.RunConfig{
+				Image:     "ghcr.io/stacklok/mcp-server:latest",
+				Name:      "test",
+				BaseName:  "test",
+				Transport: types.TransportTypeStdio,
+				PermissionProfile: &permissions.Profile{
+					Resources: &permissions.ResourceLimits{
+						CPUShares: 512,
+						CPUs:      "2",
+						Memory:    "1Gi",
+						PidsLimit: 100,
+						TmpfsSize: "64Mi",
+					},
+				},
+			},
+			validateFn: func(t *testing.T, mcpServer *v1alpha1.MCPServer) {
+				t.Helper()
+				assert.Equal(t, v1alpha1.ResourceRequirements{
+					Limits:   v1alpha1.ResourceList{CPU: "2", Memory: "1Gi"},
+					Requests: v1alpha1.ResourceList{CPU: "500m"},
+				}, mcpServer.Spec.Resources)
 			},
 		},
 		{
@@ -540,4 +566,171 @@ func TestRunConfigToMCPServer(t *testing.T) {
 		require.NoError(t, err)
 		assert.Equal(t, "my-name-with-caps", mcpServer.Name)
 	})
+
+	t.Run("caps cpu request at the cpu limit", func(t *testing.T) {
+		t.Parallel()
+
+		config := &runner.RunConfig{
+			Image:     "test:latest",
+			Name:      "my-name",
+			Transport: types.TransportTypeStdio,
+			PermissionProfile: &permissions.Profile{
+				Resources: &permissions.ResourceLimits{CPUShares: 4096, CPUs: "500m"},
+			},
+		}
+
+		mcpServer, err := runConfigToMCPServer(config)
+		require.NoError(t, err)
+		assert.Equal(t, "500m", mcpServer.Spec.Resources.Requests.CPU)
+	})
+
+	t.Run("rejects invalid resource limits", func(t *testing.T) {
+		t.Parallel()
+
+		config := &runner.RunConfig{
+			Image:     "test:latest",
+			Name:      "my-name",
+			Transport: types.TransportTypeStdio,
+			PermissionProfile: &permissions.Profile{
+				Resources: &permissions.ResourceLimits{Memory: "plenty"},
+			},
+		}
+
+		_, err := runConfigToMCPServer(config)
+		assert.ErrorContains(t, err, "invalid resource limits")
+	})
+}
+
+func TestReadK8sManifest(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{
+			name: "valid",
+			data: "apiVersion: toolhive.stacklok.dev/v1alpha1\nkind: MCPServer\nmetadata:\n  name: fetch\n" +
+				"spec:\n  image: ghcr.io/stackloklabs/gofetch/server:latest\n",
+		},
+		{
+			name:    "wrong kind",
+			data:    "kind: MCPRemoteProxy\nmetadata:\n  name: fetch\n",
+			wantErr: `unsupported resource kind "MCPRemoteProxy"`,
+		},
+		{
+			name:    "unknown field",
+			data:    "kind: MCPServer\nmetadata:\n  name: fetch\nspec:\n  imagez: fetch\n",
+			wantErr: `unknown field "imagez"`,
+		},
+		{
+			name:    "missing name",
+			data:    "kind: MCPServer\nspec:\n  image: fetch\n",
+			wantErr: "has no name",
+		},
+		{
+			name:    "missing image",
+			data:    "kind: MCPServer\nmetadata:\n  name: fetch\n",
+			wantErr: "has no image",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			mcpServer, err := ReadK8sManifest(strings.NewReader(tt.data))
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "fetch", mcpServer.Name)
+			assert.Equal(t, "ghcr.io/stackloklabs/gofetch/server:latest", mcpServer.Spec.Image)
+		})
+	}
+}
+
+func TestK8sManifestToRunConfig(t *testing.T) {
+	t.Parallel()
+
+	t.Run("round trip", func(t *testing.T) {
+		t.Parallel()
+
+		original := &runner.RunConfig{
+			Image:       "ghcr.io/stacklok/mcp-server-github:latest",
+			Name:        "github",
+			Transport:   types.TransportTypeStdio,
+			ProxyMode:   types.ProxyModeStreamableHTTP,
+			CmdArgs:     []string{"--verbose"},
+			EnvVars:     map[string]string{"LOG_LEVEL": "debug"},
+			ToolsFilter: []string{"search_issues"},
+			AuthzConfig: mustNewAuthzConfig(t, cedar.ConfigOptions{
+				Policies:     []string{`permit(principal, action, resource);`},
+				EntitiesJSON: "[]",
+			}),
+		}
+
+		var buf bytes.Buffer
+		require.NoError(t, WriteK8sManifest(original, &buf))
+		mcpServer, err := ReadK8sManifest(&buf)
+		require.NoError(t, err)
+
+		config, err := K8sManifestToRunConfig(t.Context(), mcpServer)
+		require.NoError(t, err)
+		assert.Equal(t, original.Image, config.Image)
+		assert.Equal(t, original.Name, config.Name)
+		assert.Equal(t, original.Transport, config.Transport)
+		assert.Equal(t, original.ProxyMode, config.ProxyMode)
+		assert.Equal(t, original.CmdArgs, config.CmdArgs)
+		assert.Equal(t, "debug", config.EnvVars["LOG_LEVEL"])
+		assert.Equal(t, original.ToolsFilter, config.ToolsFilter)
+		assert.NotZero(t, config.Port, "an unset proxy port must be allocated")
+		require.NotNil(t, config.AuthzConfig)
+		cedarConfig, err := cedar.ExtractConfig(config.AuthzConfig)
+		require.NoError(t, err)
+		assert.Equal(t, []string{`permit(principal, action, resource);`}, cedarConfig.Options.Policies)
+	})
+
+	t.Run("maps secrets and volumes", func(t *testing.T) {
+		t.Parallel()
+
+		mcpServer := &v1alpha1.MCPServer{}
+		mcpServer.Name = "github"
+		mcpServer.Spec = v1alpha1.MCPServerSpec{
+			Image:     "ghcr.io/stacklok/mcp-server-github:latest",
+			Transport: "stdio",
+			Secrets: []v1alpha1.SecretRef{
+				{Name: "github-token", Key: "token", TargetEnvName: "GITHUB_TOKEN"},
+				{Name: "api-key", Key: "API_KEY"},
+			},
+			Volumes: []v1alpha1.Volume{
+				{Name: "data", HostPath: "/tmp/data", MountPath: "/data", ReadOnly: true},
+			},
+		}
+
+		config, err := K8sManifestToRunConfig(t.Context(), mcpServer, runner.WithGroup("team-tools"))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"github-token,target=GITHUB_TOKEN", "api-key,target=API_KEY"}, config.Secrets)
+		assert.Equal(t, []string{"/tmp/data:/data:ro"}, config.Volumes)
+		assert.Equal(t, "team-tools", config.Group)
+	})
+
+	t.Run("rejects configmap permission profiles", func(t *testing.T) {
+		t.Parallel()
+
+		mcpServer := &v1alpha1.MCPServer{}
+		mcpServer.Name = "github"
+		mcpServer.Spec = v1alpha1.MCPServerSpec{
+			Image: "ghcr.io/stacklok/mcp-server-github:latest",
+			PermissionProfile: &v1alpha1.PermissionProfileRef{
+				Type: v1alpha1.PermissionProfileTypeConfigMap,
+				Name: "profiles",
+			},
+		}
+
+		_, err := K8sManifestToRunConfig(t.Context(), mcpServer)
+		assert.ErrorContains(t, err, "cannot be imported")
+	})
 }
@@ -184,6 +184,37 @@ func (m *cliManager) UnregisterClients(ctx context.Context, groupNames []string,
 	return nil
 }
 
+// SetDefaults replaces the defaults of a group
+func (m *cliManager) SetDefaults(ctx context.Context, name string, defaults *Defaults) error {
+	if err := defaults.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidGroupDefaults, err)
+	}
+
+	exists, err := m.groupStore.Exists(ctx, name)
+	if err != nil {
+		return fmt.Errorf("failed to check if group exists: %w", err)
+	}
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrGroupNotFound, name)
+	}
+
+	group, err := m.Get(ctx, name)
+	if err != nil {
+		return fmt.Errorf("failed to get group %s: %w", name, err)
+	}
+
+	if defaults.IsEmpty() {
+		group.Defaults = nil
+	} else {
+		group.Defaults = defaults
+	}
+
+	if err := m.saveGroup(ctx, group); err != nil {
+		return fmt.Errorf("failed to save group %s: %w", name, err)
+	}
+	return nil
+}
+
 // saveGroup saves the group to the group state store
 func (m *cliManager) saveGroup(ctx context.Context, group *Group) error {
 	writer, err := m.groupStore.GetWriter(ctx, group.Name)
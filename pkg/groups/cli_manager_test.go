@@ -649,3 +649,93 @@ func (m *mockWriteCloser) Write(p []byte) (n int, err error) {
 func (*mockWriteCloser) Close() error {
 	return nil
 }
+
+// TestManager_SetDefaults tests setting the defaults of a group
+func TestManager_SetDefaults(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		defaults    *Defaults
+		setupMock   func(*mocks.MockStore, *mockWriteCloser)
+		expectError bool
+		errorMsg    string
+		expected    string
+	}{
+		{
+			name:     "set defaults",
+			defaults: &Defaults{EnvVars: map[string]string{"LOG_LEVEL": "debug"}, Secrets: []string{"token,target=API_TOKEN"}},
+			setupMock: func(mock *mocks.MockStore, writer *mockWriteCloser) {
+				groupData := `{"name": "` + testGroupName + `", "registered_clients": ["vscode"]}`
+				mock.EXPECT().Exists(gomock.Any(), testGroupName).Return(true, nil)
+				mock.EXPECT().
+					GetReader(gomock.Any(), testGroupName).
+					Return(io.NopCloser(strings.NewReader(groupData)), nil)
+				mock.EXPECT().GetWriter(gomock.Any(), testGroupName).Return(writer, nil)
+			},
+			expected: `"defaults": {
+    "env_vars": {
+      "LOG_LEVEL": "debug"
+    },
+    "secrets": [
+      "token,target=API_TOKEN"
+    ]
+  }`,
+		},
+		{
+			name:     "empty defaults are removed",
+			defaults: &Defaults{},
+			setupMock: func(mock *mocks.MockStore, writer *mockWriteCloser) {
+				groupData := `{"name": "` + testGroupName + `", "registered_clients": [], "defaults": {"secrets": ["a,target=B"]}}`
+				mock.EXPECT().Exists(gomock.Any(), testGroupName).Return(true, nil)
+				mock.EXPECT().
+					GetReader(gomock.Any(), testGroupName).
+					Return(io.NopCloser(strings.NewReader(groupData)), nil)
+				mock.EXPECT().GetWriter(gomock.Any(), testGroupName).Return(writer, nil)
+			},
+			expected: `"registered_clients": []
+}`,
+		},
+		{
+			name:        "invalid secret",
+			defaults:    &Defaults{Secrets: []string{"no-target"}},
+			setupMock:   func(*mocks.MockStore, *mockWriteCloser) {},
+			expectError: true,
+			errorMsg:    "invalid group defaults",
+		},
+		{
+			name:     "group not found",
+			defaults: &Defaults{EnvVars: map[string]string{"LOG_LEVEL": "debug"}},
+			setupMock: func(mock *mocks.MockStore, _ *mockWriteCloser) {
+				mock.EXPECT().Exists(gomock.Any(), testGroupName).Return(false, nil)
+			},
+			expectError: true,
+			errorMsg:    "group not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockStore := mocks.NewMockStore(ctrl)
+			manager := &cliManager{groupStore: mockStore}
+			writer := &mockWriteCloser{}
+
+			tt.setupMock(mockStore, writer)
+
+			err := manager.SetDefaults(context.Background(), testGroupName, tt.defaults)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorMsg)
+			} else {
+				assert.NoError(t, err)
+				assert.Contains(t, string(writer.data), tt.expected)
+			}
+		})
+	}
+}
@@ -150,6 +150,11 @@ func (*crdManager) UnregisterClients(context.Context, []string, []string) error
 	return nil
 }
 
+func (*crdManager) SetDefaults(context.Context, string, *Defaults) error {
+	// MCPGroup resources do not hold defaults, workloads are configured by their MCPServer resources.
+	return ErrGroupDefaultsNotSupported
+}
+
 // mcpGroupListToGroups converts an MCPGroupList to a slice of Groups
 func mcpGroupListToGroups(mcpGroupList *mcpv1alpha1.MCPGroupList) []*Group {
 	groups := make([]*Group, 0, len(mcpGroupList.Items))
@@ -0,0 +1,75 @@
+package groups
+
+import (
+	"fmt"
+
+	"github.com/stacklok/toolhive/pkg/audit"
+	"github.com/stacklok/toolhive/pkg/auth"
+	"github.com/stacklok/toolhive/pkg/authz"
+	"github.com/stacklok/toolhive/pkg/permissions"
+	"github.com/stacklok/toolhive/pkg/secrets"
+)
+
+// Defaults are RunConfig settings shared by the workloads of a group.
+// They are merged into the configuration of each workload run in the group,
+// and the settings of the workload take precedence over them.
+type Defaults struct {
+	// EnvVars are environment variables set in the workloads of the group.
+	// Variables set on a workload override the variables with the same name.
+	EnvVars map[string]string `json:"env_vars,omitempty" yaml:"env_vars,omitempty"`
+
+	// Secrets are secrets set as environment variables in the workloads of the group
+	// (format: NAME,target=TARGET). Secrets of a workload override the secrets with the same target.
+	Secrets []string `json:"secrets,omitempty" yaml:"secrets,omitempty"`
+
+	// PermissionProfile is the permission profile of the workloads that do not set one
+	PermissionProfile *permissions.Profile `json:"permission_profile,omitempty" yaml:"permission_profile,omitempty"` //nolint:lll
+
+	// OIDCConfig is the OIDC configuration of the workloads that do not set one
+	OIDCConfig *auth.TokenValidatorConfig `json:"oidc_config,omitempty" yaml:"oidc_config,omitempty"`
+
+	// AuthzConfig is the authorization configuration of the workloads that do not set one
+	AuthzConfig *authz.Config `json:"authz_config,omitempty" yaml:"authz_config,omitempty"`
+
+	// AuditConfig is the audit configuration of the workloads that do not set one
+	AuditConfig *audit.Config `json:"audit_config,omitempty" yaml:"audit_config,omitempty"`
+}
+
+// IsEmpty returns true if the defaults do not set anything.
+func (d *Defaults) IsEmpty() bool {
+	return d == nil || (len(d.EnvVars) == 0 && len(d.Secrets) == 0 && d.PermissionProfile == nil &&
+		d.OIDCConfig == nil && d.AuthzConfig == nil && d.AuditConfig == nil)
+}
+
+// Validate checks that the defaults can be merged into the configuration of a workload.
+func (d *Defaults) Validate() error {
+	if d == nil {
+		return nil
+	}
+	for name := range d.EnvVars {
+		if name == "" {
+			return fmt.Errorf("environment variable name cannot be empty")
+		}
+	}
+	for _, secret := range d.Secrets {
+		if _, err := secrets.ParseSecretParameter(secret); err != nil {
+			return fmt.Errorf("invalid secret: %w", err)
+		}
+	}
+	if d.PermissionProfile != nil {
+		if err := d.PermissionProfile.Resources.Validate(); err != nil {
+			return fmt.Errorf("invalid permission profile: %w", err)
+		}
+	}
+	if d.AuthzConfig != nil {
+		if err := d.AuthzConfig.Validate(); err != nil {
+			return fmt.Errorf("invalid authorization configuration: %w", err)
+		}
+	}
+	if d.AuditConfig != nil {
+		if err := d.AuditConfig.Validate(); err != nil {
+			return fmt.Errorf("invalid audit configuration: %w", err)
+		}
+	}
+	return nil
+}
@@ -25,4 +25,16 @@ var (
 		errors.New("invalid group name"),
 		http.StatusBadRequest,
 	)
+
+	// ErrInvalidGroupDefaults is returned when the defaults of a group are invalid
+	ErrInvalidGroupDefaults = thverrors.WithCode(
+		errors.New("invalid group defaults"),
+		http.StatusBadRequest,
+	)
+
+	// ErrGroupDefaultsNotSupported is returned when the group manager cannot store group defaults
+	ErrGroupDefaultsNotSupported = thverrors.WithCode(
+		errors.New("group defaults are not supported in Kubernetes"),
+		http.StatusNotImplemented,
+	)
 )
@@ -13,8 +13,9 @@ const DefaultGroup = "default"
 
 // Group represents a logical grouping of MCP servers.
 type Group struct {
-	Name              string   `json:"name"`
-	RegisteredClients []string `json:"registered_clients"`
+	Name              string    `json:"name"`
+	RegisteredClients []string  `json:"registered_clients"`
+	Defaults          *Defaults `json:"defaults,omitempty"`
 }
 
 // WriteJSON serializes the Group to JSON and writes it to the provided writer
@@ -52,4 +53,8 @@ type Manager interface {
 
 	// UnregisterClients removes multiple clients from multiple groups.
 	UnregisterClients(ctx context.Context, groupNames []string, clientNames []string) error
+
+	// SetDefaults replaces the defaults merged into the workloads of a group.
+	// Nil or empty defaults remove them.
+	SetDefaults(ctx context.Context, name string, defaults *Defaults) error
 }
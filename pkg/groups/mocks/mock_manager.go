@@ -128,6 +128,20 @@ func (mr *MockManagerMockRecorder) RegisterClients(ctx, groupNames, clientNames
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterClients", reflect.TypeOf((*MockManager)(nil).RegisterClients), ctx, groupNames, clientNames)
 }
 
+// SetDefaults mocks base method.
+func (m *MockManager) SetDefaults(ctx context.Context, name string, defaults *groups.Defaults) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetDefaults", ctx, name, defaults)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetDefaults indicates an expected call of SetDefaults.
+func (mr *MockManagerMockRecorder) SetDefaults(ctx, name, defaults any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDefaults", reflect.TypeOf((*MockManager)(nil).SetDefaults), ctx, name, defaults)
+}
+
 // UnregisterClients mocks base method.
 func (m *MockManager) UnregisterClients(ctx context.Context, groupNames, clientNames []string) error {
 	m.ctrl.T.Helper()
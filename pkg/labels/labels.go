@@ -36,6 +36,13 @@ const (
 	// LabelAuxiliary is the label that indicates this is an auxiliary workload (like inspector)
 	LabelAuxiliary = "toolhive-auxiliary"
 
+	// LabelManifestHash is the label that contains the hash of the manifest entry a workload was applied from
+	LabelManifestHash = "toolhive-manifest-hash"
+
+	// LabelSandbox is the label that indicates a workload runs in a sandbox, against which recorded
+	// tool calls can be replayed. It is set by the process runtime, and can be set by users.
+	LabelSandbox = "toolhive-sandbox"
+
 	// LabelToolHiveValue is the value for the LabelToolHive label
 	LabelToolHiveValue = "true"
 )
@@ -127,6 +134,12 @@ func IsAuxiliaryWorkload(labels map[string]string) bool {
 	return ok && strings.ToLower(value) == LabelToolHiveValue
 }
 
+// IsSandboxWorkload checks if a workload is labelled as running in a sandbox
+func IsSandboxWorkload(labels map[string]string) bool {
+	value, ok := labels[LabelSandbox]
+	return ok && strings.ToLower(value) == LabelToolHiveValue
+}
+
 // IsStandardToolHiveLabel checks if a label key is a standard ToolHive label
 // that should not be passed through from user input or displayed to users
 func IsStandardToolHiveLabel(key string) bool {
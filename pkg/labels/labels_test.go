@@ -355,6 +355,45 @@ func TestHasNetworkIsolation(t *testing.T) {
 	}
 }
 
+func TestIsSandboxWorkload(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		labels   map[string]string
+		expected bool
+	}{
+		{
+			name: "Sandbox label set",
+			labels: map[string]string{
+				LabelSandbox: "true",
+			},
+			expected: true,
+		},
+		{
+			name: "Sandbox label disabled",
+			labels: map[string]string{
+				LabelSandbox: "false",
+			},
+			expected: false,
+		},
+		{
+			name:     "Workload without label",
+			labels:   map[string]string{},
+			expected: false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			result := IsSandboxWorkload(tc.labels)
+			if result != tc.expected {
+				t.Errorf("Expected IsSandboxWorkload to be %t, but got %t", tc.expected, result)
+			}
+		})
+	}
+}
+
 func TestIsStandardToolHiveLabel(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -0,0 +1,189 @@
+// Package manifest provides declarative manifests of a group of MCP servers.
+//
+// A manifest lists a group, the MCP servers that run in it, the defaults shared by
+// these servers and the clients registered with the group. It is reconciled against
+// the local state by computing a Plan of the changes to apply.
+package manifest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/stacklok/toolhive/pkg/groups"
+	"github.com/stacklok/toolhive/pkg/labels"
+	"github.com/stacklok/toolhive/pkg/secrets"
+	"github.com/stacklok/toolhive/pkg/validation"
+	"github.com/stacklok/toolhive/pkg/workloads/types"
+)
+
+// hashLength is the length of the hash of a server, which must fit in a label value
+const hashLength = 32
+
+// Manifest is the desired state of a group of MCP servers.
+type Manifest struct {
+	// Group is the name of the group
+	Group string `json:"group" yaml:"group"`
+
+	// Defaults are the defaults merged into the configuration of the servers of the group
+	Defaults *groups.Defaults `json:"defaults,omitempty" yaml:"defaults,omitempty"`
+
+	// Clients are the clients registered with the group
+	Clients []string `json:"clients,omitempty" yaml:"clients,omitempty"`
+
+	// Servers are the MCP servers that run in the group
+	Servers []Server `json:"servers,omitempty" yaml:"servers,omitempty"`
+}
+
+// Server is an MCP server of a manifest.
+// Its fields match the flags of `thv run`.
+type Server struct {
+	// Name is the name of the workload
+	Name string `json:"name" yaml:"name"`
+
+	// Server is the server to run: a registry server name, a container image,
+	// a protocol scheme (uvx://, npx://, go://) or the URL of a remote server
+	Server string `json:"server" yaml:"server"`
+
+	// Transport is the transport mode (sse, streamable-http or stdio)
+	Transport string `json:"transport,omitempty" yaml:"transport,omitempty"`
+
+	// ProxyMode is the proxy mode for stdio transport (sse or streamable-http)
+	ProxyMode string `json:"proxy_mode,omitempty" yaml:"proxy_mode,omitempty"`
+
+	// ProxyPort is the port of the HTTP proxy, a random port is used when not set
+	ProxyPort int `json:"proxy_port,omitempty" yaml:"proxy_port,omitempty"`
+
+	// TargetPort is the port of the container to expose
+	TargetPort int `json:"target_port,omitempty" yaml:"target_port,omitempty"`
+
+	// Args are the arguments passed to the MCP server
+	Args []string `json:"args,omitempty" yaml:"args,omitempty"`
+
+	// Env are the environment variables of the MCP server
+	Env map[string]string `json:"env,omitempty" yaml:"env,omitempty"`
+
+	// Secrets are the secrets set as environment variables (format: NAME,target=TARGET)
+	Secrets []string `json:"secrets,omitempty" yaml:"secrets,omitempty"`
+
+	// PermissionProfile is the name (none, network) or path of the permission profile
+	PermissionProfile string `json:"permission_profile,omitempty" yaml:"permission_profile,omitempty"`
+
+	// Volumes are the volumes to mount (format: host-path:container-path[:ro])
+	Volumes []string `json:"volumes,omitempty" yaml:"volumes,omitempty"`
+
+	// Tools are the tools exposed by the MCP server, all tools are exposed when empty
+	Tools []string `json:"tools,omitempty" yaml:"tools,omitempty"`
+
+	// IsolateNetwork enables network isolation of the MCP server
+	IsolateNetwork bool `json:"isolate_network,omitempty" yaml:"isolate_network,omitempty"`
+
+	// Labels are the labels set on the workload
+	Labels map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+}
+
+// Load reads and validates a manifest from a YAML or JSON file.
+func Load(path string) (*Manifest, error) {
+	// #nosec G304 - the manifest path is provided by the user
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+	return Parse(data)
+}
+
+// Parse parses and validates a manifest from YAML or JSON data.
+// Unknown fields are rejected.
+func Parse(data []byte) (*Manifest, error) {
+	var m Manifest
+	if err := yaml.UnmarshalStrict(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+	if err := m.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid manifest: %w", err)
+	}
+	return &m, nil
+}
+
+// Validate checks that the manifest is valid.
+func (m *Manifest) Validate() error {
+	if err := validation.ValidateGroupName(m.Group); err != nil {
+		return fmt.Errorf("invalid group: %w", err)
+	}
+	if err := m.Defaults.Validate(); err != nil {
+		return fmt.Errorf("invalid defaults: %w", err)
+	}
+
+	clients := make(map[string]bool, len(m.Clients))
+	for _, client := range m.Clients {
+		if client == "" {
+			return errors.New("client name cannot be empty")
+		}
+		if clients[client] {
+			return fmt.Errorf("duplicate client %q", client)
+		}
+		clients[client] = true
+	}
+
+	names := make(map[string]bool, len(m.Servers))
+	for i := range m.Servers {
+		server := &m.Servers[i]
+		if err := server.Validate(); err != nil {
+			return fmt.Errorf("invalid server %q: %w", server.Name, err)
+		}
+		if names[server.Name] {
+			return fmt.Errorf("duplicate server %q", server.Name)
+		}
+		names[server.Name] = true
+	}
+	return nil
+}
+
+// Validate checks that the server is valid.
+func (s *Server) Validate() error {
+	if err := types.ValidateWorkloadName(s.Name); err != nil {
+		return err
+	}
+	if s.Server == "" {
+		return errors.New("server cannot be empty")
+	}
+	for name := range s.Env {
+		if name == "" {
+			return errors.New("environment variable name cannot be empty")
+		}
+	}
+	for _, secret := range s.Secrets {
+		if _, err := secrets.ParseSecretParameter(secret); err != nil {
+			return fmt.Errorf("invalid secret %q: %w", secret, err)
+		}
+	}
+	for key, value := range s.Labels {
+		if key == labels.LabelManifestHash {
+			return fmt.Errorf("label %q is reserved", key)
+		}
+		if _, _, err := labels.ParseLabel(key + "=" + value); err != nil {
+			return fmt.Errorf("invalid label %q: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// Hash returns the hash of the desired configuration of a server, which includes the
+// defaults of its group. It is set as a label on the workload to detect changes.
+func (s *Server) Hash(defaults *groups.Defaults) string {
+	if defaults.IsEmpty() {
+		defaults = nil
+	}
+	// Marshaling cannot fail: the server and the defaults only hold serializable values
+	data, _ := json.Marshal(struct {
+		Server   *Server          `json:"server"`
+		Defaults *groups.Defaults `json:"defaults,omitempty"`
+	}{s, defaults})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])[:hashLength]
+}
@@ -0,0 +1,148 @@
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stacklok/toolhive/pkg/groups"
+	"github.com/stacklok/toolhive/pkg/labels"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{
+			name: "valid",
+			data: `
+group: team-tools
+defaults:
+  env_vars:
+    LOG_LEVEL: info
+clients: [vscode, cursor]
+servers:
+  - name: fetch
+    server: fetch
+  - name: git
+    server: uvx://mcp-server-git
+    args: ["--repository", "/projects/repo"]
+    env:
+      GIT_AUTHOR: bot
+    secrets: ["github-token,target=GITHUB_TOKEN"]
+    permission_profile: network
+    labels:
+      team: platform
+`,
+		},
+		{
+			name:    "unknown field",
+			data:    "group: team-tools\nserverz: []\n",
+			wantErr: `unknown field "serverz"`,
+		},
+		{
+			name:    "missing group",
+			data:    "servers:\n  - name: fetch\n    server: fetch\n",
+			wantErr: "invalid group",
+		},
+		{
+			name:    "invalid defaults",
+			data:    "group: team-tools\ndefaults:\n  secrets: [bad]\n",
+			wantErr: "invalid defaults",
+		},
+		{
+			name:    "duplicate client",
+			data:    "group: team-tools\nclients: [vscode, vscode]\n",
+			wantErr: `duplicate client "vscode"`,
+		},
+		{
+			name:    "duplicate server",
+			data:    "group: team-tools\nservers:\n  - {name: fetch, server: fetch}\n  - {name: fetch, server: fetch}\n",
+			wantErr: `duplicate server "fetch"`,
+		},
+		{
+			name:    "missing server",
+			data:    "group: team-tools\nservers:\n  - name: fetch\n",
+			wantErr: "server cannot be empty",
+		},
+		{
+			name:    "invalid workload name",
+			data:    "group: team-tools\nservers:\n  - {name: ../fetch, server: fetch}\n",
+			wantErr: `invalid server "../fetch"`,
+		},
+		{
+			name:    "invalid secret",
+			data:    "group: team-tools\nservers:\n  - {name: fetch, server: fetch, secrets: [token]}\n",
+			wantErr: `invalid secret "token"`,
+		},
+		{
+			name:    "reserved label",
+			data:    "group: team-tools\nservers:\n  - {name: fetch, server: fetch, labels: {toolhive-manifest-hash: x}}\n",
+			wantErr: "is reserved",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			m, err := Parse([]byte(tt.data))
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "team-tools", m.Group)
+			assert.Equal(t, []string{"vscode", "cursor"}, m.Clients)
+			assert.Equal(t, map[string]string{"LOG_LEVEL": "info"}, m.Defaults.EnvVars)
+			require.Len(t, m.Servers, 2)
+			assert.Equal(t, "uvx://mcp-server-git", m.Servers[1].Server)
+			assert.Equal(t, []string{"--repository", "/projects/repo"}, m.Servers[1].Args)
+			assert.Equal(t, "network", m.Servers[1].PermissionProfile)
+			assert.Equal(t, map[string]string{"team": "platform"}, m.Servers[1].Labels)
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "stack.json")
+	data := `{"group": "team-tools", "servers": [{"name": "fetch", "server": "fetch"}]}`
+	require.NoError(t, os.WriteFile(path, []byte(data), 0600))
+
+	m, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, "team-tools", m.Group)
+	require.Len(t, m.Servers, 1)
+	assert.Equal(t, "fetch", m.Servers[0].Name)
+
+	_, err = Load(filepath.Join(t.TempDir(), "missing.yaml"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to read manifest")
+}
+
+func TestServer_Hash(t *testing.T) {
+	t.Parallel()
+
+	server := &Server{Name: "fetch", Server: "fetch", Env: map[string]string{"A": "1", "B": "2"}}
+	hash := server.Hash(nil)
+	assert.Len(t, hash, hashLength)
+	_, _, err := labels.ParseLabel(labels.LabelManifestHash + "=" + hash)
+	require.NoError(t, err, "the hash must be a valid label value")
+
+	same := &Server{Name: "fetch", Server: "fetch", Env: map[string]string{"B": "2", "A": "1"}}
+	assert.Equal(t, hash, same.Hash(nil))
+	assert.Equal(t, hash, same.Hash(&groups.Defaults{}), "empty defaults must not change the hash")
+
+	changed := &Server{Name: "fetch", Server: "fetch", Env: map[string]string{"A": "1", "B": "3"}}
+	assert.NotEqual(t, hash, changed.Hash(nil))
+	assert.NotEqual(t, hash, server.Hash(&groups.Defaults{EnvVars: map[string]string{"C": "3"}}))
+}
@@ -0,0 +1,221 @@
+package manifest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+
+	"github.com/stacklok/toolhive/pkg/container/runtime"
+	"github.com/stacklok/toolhive/pkg/core"
+	"github.com/stacklok/toolhive/pkg/groups"
+	"github.com/stacklok/toolhive/pkg/labels"
+)
+
+// Action is the change applied to a server of a group.
+type Action string
+
+const (
+	// ActionCreate runs a server that does not exist
+	ActionCreate Action = "create"
+	// ActionUpdate recreates an existing server with the configuration of the manifest
+	ActionUpdate Action = "update"
+	// ActionDelete deletes a server that is not in the manifest
+	ActionDelete Action = "delete"
+	// ActionNone leaves a server unchanged
+	ActionNone Action = "none"
+)
+
+// ServerChange is the change applied to a server of a group.
+type ServerChange struct {
+	// Name is the name of the workload
+	Name string
+	// Action is the change applied to the workload
+	Action Action
+	// Reason explains why the workload is updated or left unchanged
+	Reason string
+	// Server is the server of the manifest, nil for deleted servers
+	Server *Server
+}
+
+// Plan are the changes that reconcile the local state with a manifest.
+type Plan struct {
+	// Group is the name of the group
+	Group string
+	// CreateGroup is true if the group does not exist
+	CreateGroup bool
+	// UpdateDefaults is true if the defaults of the group are replaced by Defaults
+	UpdateDefaults bool
+	// Defaults are the defaults of the group in the manifest
+	Defaults *groups.Defaults
+	// RegisterClients are the clients to register with the group
+	RegisterClients []string
+	// UnregisterClients are the clients to unregister from the group
+	UnregisterClients []string
+	// Servers are the changes of the servers of the manifest, followed by
+	// the other workloads of the group
+	Servers []ServerChange
+
+	// hasDefaults is true if the group has defaults
+	hasDefaults bool
+}
+
+// NewPlan computes the changes that reconcile the local state with a manifest.
+// The group is nil if it does not exist, and workloads are all the existing workloads.
+// When prune is true, the workloads and clients of the group that are not in the manifest
+// are removed, as well as the defaults of the group if the manifest does not set any.
+func NewPlan(m *Manifest, group *groups.Group, workloads []core.Workload, prune bool) *Plan {
+	plan := &Plan{
+		Group:       m.Group,
+		CreateGroup: group == nil,
+		Defaults:    m.Defaults,
+	}
+	if group == nil {
+		group = &groups.Group{Name: m.Group}
+	}
+	plan.hasDefaults = !group.Defaults.IsEmpty()
+
+	if !defaultsEqual(group.Defaults, m.Defaults) && (!m.Defaults.IsEmpty() || prune) {
+		plan.UpdateDefaults = true
+	}
+
+	for _, client := range m.Clients {
+		if !slices.Contains(group.RegisteredClients, client) {
+			plan.RegisterClients = append(plan.RegisterClients, client)
+		}
+	}
+	if prune {
+		for _, client := range group.RegisteredClients {
+			if !slices.Contains(m.Clients, client) {
+				plan.UnregisterClients = append(plan.UnregisterClients, client)
+			}
+		}
+	}
+
+	existing := make(map[string]core.Workload, len(workloads))
+	for _, workload := range workloads {
+		existing[workload.Name] = workload
+	}
+
+	inManifest := make(map[string]bool, len(m.Servers))
+	for i := range m.Servers {
+		server := &m.Servers[i]
+		inManifest[server.Name] = true
+
+		change := ServerChange{Name: server.Name, Server: server, Action: ActionNone}
+		workload, ok := existing[server.Name]
+		switch {
+		case !ok:
+			change.Action = ActionCreate
+		case workload.Group != m.Group:
+			change.Action = ActionUpdate
+			change.Reason = fmt.Sprintf("in group %q", workload.Group)
+		case workload.Labels[labels.LabelManifestHash] == "":
+			change.Action = ActionUpdate
+			change.Reason = "not applied from a manifest"
+		case workload.Labels[labels.LabelManifestHash] != server.Hash(m.Defaults):
+			change.Action = ActionUpdate
+			change.Reason = "configuration changed"
+		case !isActive(workload.Status):
+			change.Action = ActionUpdate
+			change.Reason = fmt.Sprintf("workload is %s", workload.Status)
+		}
+		plan.Servers = append(plan.Servers, change)
+	}
+
+	var others []ServerChange
+	for _, workload := range workloads {
+		if workload.Group != m.Group || inManifest[workload.Name] {
+			continue
+		}
+		if prune {
+			others = append(others, ServerChange{Name: workload.Name, Action: ActionDelete})
+		} else {
+			others = append(others, ServerChange{Name: workload.Name, Action: ActionNone, Reason: "not in the manifest"})
+		}
+	}
+	sort.Slice(others, func(i, j int) bool { return others[i].Name < others[j].Name })
+	plan.Servers = append(plan.Servers, others...)
+
+	return plan
+}
+
+// HasChanges returns true if the plan changes the local state.
+func (p *Plan) HasChanges() bool {
+	if p.CreateGroup || p.UpdateDefaults || len(p.RegisterClients) > 0 || len(p.UnregisterClients) > 0 {
+		return true
+	}
+	for _, change := range p.Servers {
+		if change.Action != ActionNone {
+			return true
+		}
+	}
+	return false
+}
+
+// Write writes a human-readable summary of the plan, with one line per change.
+// Lines start with + for additions, ~ for updates and - for removals.
+func (p *Plan) Write(w io.Writer) error {
+	var buf bytes.Buffer
+	if p.CreateGroup {
+		fmt.Fprintf(&buf, "+ group %s\n", p.Group)
+	} else {
+		fmt.Fprintf(&buf, "  group %s\n", p.Group)
+	}
+	if p.UpdateDefaults {
+		switch {
+		case p.Defaults.IsEmpty():
+			fmt.Fprintf(&buf, "- defaults\n")
+		case p.hasDefaults:
+			fmt.Fprintf(&buf, "~ defaults\n")
+		default:
+			fmt.Fprintf(&buf, "+ defaults\n")
+		}
+	}
+	for _, client := range p.RegisterClients {
+		fmt.Fprintf(&buf, "+ client %s\n", client)
+	}
+	for _, client := range p.UnregisterClients {
+		fmt.Fprintf(&buf, "- client %s\n", client)
+	}
+	for _, change := range p.Servers {
+		switch change.Action {
+		case ActionCreate:
+			fmt.Fprintf(&buf, "+ server %s (%s)\n", change.Name, change.Server.Server)
+		case ActionUpdate:
+			fmt.Fprintf(&buf, "~ server %s: %s\n", change.Name, change.Reason)
+		case ActionDelete:
+			fmt.Fprintf(&buf, "- server %s\n", change.Name)
+		case ActionNone:
+			if change.Reason != "" {
+				fmt.Fprintf(&buf, "  server %s: %s\n", change.Name, change.Reason)
+			} else {
+				fmt.Fprintf(&buf, "  server %s\n", change.Name)
+			}
+		}
+	}
+	if !p.HasChanges() {
+		fmt.Fprintf(&buf, "No changes.\n")
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// isActive returns true if a workload runs or is about to run
+func isActive(status runtime.WorkloadStatus) bool {
+	return status == runtime.WorkloadStatusRunning || status == runtime.WorkloadStatusStarting ||
+		status == runtime.WorkloadStatusUnauthenticated
+}
+
+// defaultsEqual returns true if two defaults set the same settings
+func defaultsEqual(a, b *groups.Defaults) bool {
+	if a.IsEmpty() || b.IsEmpty() {
+		return a.IsEmpty() && b.IsEmpty()
+	}
+	// Marshaling cannot fail: defaults only hold serializable values
+	dataA, _ := json.Marshal(a)
+	dataB, _ := json.Marshal(b)
+	return bytes.Equal(dataA, dataB)
+}
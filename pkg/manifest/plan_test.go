@@ -0,0 +1,161 @@
+package manifest
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stacklok/toolhive/pkg/container/runtime"
+	"github.com/stacklok/toolhive/pkg/core"
+	"github.com/stacklok/toolhive/pkg/groups"
+	"github.com/stacklok/toolhive/pkg/labels"
+)
+
+func TestNewPlan(t *testing.T) {
+	t.Parallel()
+
+	defaults := &groups.Defaults{EnvVars: map[string]string{"LOG_LEVEL": "info"}}
+	m := &Manifest{
+		Group:    "team-tools",
+		Defaults: defaults,
+		Clients:  []string{"vscode"},
+		Servers: []Server{
+			{Name: "fetch", Server: "fetch"},
+			{Name: "git", Server: "uvx://mcp-server-git"},
+			{Name: "time", Server: "time"},
+		},
+	}
+	applied := func(name, group string, server *Server, status runtime.WorkloadStatus) core.Workload {
+		workload := core.Workload{Name: name, Group: group, Status: status}
+		if server != nil {
+			workload.Labels = map[string]string{labels.LabelManifestHash: server.Hash(defaults)}
+		}
+		return workload
+	}
+
+	t.Run("group does not exist", func(t *testing.T) {
+		t.Parallel()
+
+		plan := NewPlan(m, nil, nil, false)
+		assert.True(t, plan.CreateGroup)
+		assert.True(t, plan.UpdateDefaults)
+		assert.Equal(t, []string{"vscode"}, plan.RegisterClients)
+		require.Len(t, plan.Servers, 3)
+		for _, change := range plan.Servers {
+			assert.Equal(t, ActionCreate, change.Action)
+		}
+		assert.True(t, plan.HasChanges())
+	})
+
+	t.Run("group is up to date", func(t *testing.T) {
+		t.Parallel()
+
+		group := &groups.Group{Name: "team-tools", RegisteredClients: []string{"vscode"}, Defaults: defaults}
+		workloads := []core.Workload{
+			applied("fetch", "team-tools", &m.Servers[0], runtime.WorkloadStatusRunning),
+			applied("git", "team-tools", &m.Servers[1], runtime.WorkloadStatusRunning),
+			applied("time", "team-tools", &m.Servers[2], runtime.WorkloadStatusStarting),
+			applied("other", "other-group", nil, runtime.WorkloadStatusRunning),
+		}
+
+		plan := NewPlan(m, group, workloads, true)
+		assert.False(t, plan.HasChanges())
+
+		var buf bytes.Buffer
+		require.NoError(t, plan.Write(&buf))
+		assert.Contains(t, buf.String(), "No changes.")
+	})
+
+	t.Run("servers changed", func(t *testing.T) {
+		t.Parallel()
+
+		group := &groups.Group{Name: "team-tools", RegisteredClients: []string{"vscode", "cursor"}, Defaults: defaults}
+		workloads := []core.Workload{
+			applied("fetch", "team-tools", &Server{Name: "fetch", Server: "fetch", Args: []string{"--old"}},
+				runtime.WorkloadStatusRunning),
+			applied("git", "other-group", &m.Servers[1], runtime.WorkloadStatusRunning),
+			applied("time", "team-tools", &m.Servers[2], runtime.WorkloadStatusStopped),
+			applied("manual", "team-tools", nil, runtime.WorkloadStatusRunning),
+		}
+
+		plan := NewPlan(m, group, workloads, false)
+		assert.False(t, plan.CreateGroup)
+		assert.False(t, plan.UpdateDefaults)
+		assert.Empty(t, plan.RegisterClients)
+		assert.Empty(t, plan.UnregisterClients, "clients are only unregistered when pruning")
+		require.Len(t, plan.Servers, 4)
+		assert.Equal(t, ServerChange{Name: "fetch", Action: ActionUpdate, Reason: "configuration changed", Server: &m.Servers[0]},
+			plan.Servers[0])
+		assert.Equal(t, ActionUpdate, plan.Servers[1].Action)
+		assert.Equal(t, `in group "other-group"`, plan.Servers[1].Reason)
+		assert.Equal(t, ActionUpdate, plan.Servers[2].Action)
+		assert.Equal(t, "workload is stopped", plan.Servers[2].Reason)
+		assert.Equal(t, ServerChange{Name: "manual", Action: ActionNone, Reason: "not in the manifest"}, plan.Servers[3])
+
+		plan = NewPlan(m, group, workloads, true)
+		assert.Equal(t, []string{"cursor"}, plan.UnregisterClients)
+		assert.Equal(t, ServerChange{Name: "manual", Action: ActionDelete}, plan.Servers[3])
+	})
+
+	t.Run("workload not applied from a manifest", func(t *testing.T) {
+		t.Parallel()
+
+		group := &groups.Group{Name: "team-tools", RegisteredClients: []string{"vscode"}, Defaults: defaults}
+		workloads := []core.Workload{applied("fetch", "team-tools", nil, runtime.WorkloadStatusRunning)}
+
+		plan := NewPlan(m, group, workloads, false)
+		assert.Equal(t, ActionUpdate, plan.Servers[0].Action)
+		assert.Equal(t, "not applied from a manifest", plan.Servers[0].Reason)
+	})
+
+	t.Run("defaults are only removed when pruning", func(t *testing.T) {
+		t.Parallel()
+
+		withoutDefaults := &Manifest{Group: "team-tools"}
+		group := &groups.Group{Name: "team-tools", Defaults: defaults}
+
+		plan := NewPlan(withoutDefaults, group, nil, false)
+		assert.False(t, plan.UpdateDefaults)
+		assert.False(t, plan.HasChanges())
+
+		plan = NewPlan(withoutDefaults, group, nil, true)
+		assert.True(t, plan.UpdateDefaults)
+		var buf bytes.Buffer
+		require.NoError(t, plan.Write(&buf))
+		assert.Contains(t, buf.String(), "- defaults\n")
+	})
+}
+
+func TestPlan_Write(t *testing.T) {
+	t.Parallel()
+
+	server := &Server{Name: "fetch", Server: "fetch"}
+	plan := &Plan{
+		Group:             "team-tools",
+		CreateGroup:       true,
+		UpdateDefaults:    true,
+		Defaults:          &groups.Defaults{Secrets: []string{"token,target=TOKEN"}},
+		RegisterClients:   []string{"vscode"},
+		UnregisterClients: []string{"cursor"},
+		Servers: []ServerChange{
+			{Name: "fetch", Action: ActionCreate, Server: server},
+			{Name: "git", Action: ActionUpdate, Reason: "configuration changed", Server: server},
+			{Name: "time", Action: ActionNone, Server: server},
+			{Name: "old", Action: ActionDelete},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, plan.Write(&buf))
+	assert.Equal(t, `+ group team-tools
++ defaults
++ client vscode
+- client cursor
++ server fetch (fetch)
+~ server git: configuration changed
+  server time
+- server old
+`, buf.String())
+}
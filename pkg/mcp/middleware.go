@@ -12,6 +12,7 @@ const (
 	ParserMiddlewareType         = "mcp-parser"
 	ToolFilterMiddlewareType     = "tool-filter"
 	ToolCallFilterMiddlewareType = "tool-call-filter"
+	ResponseCacheMiddlewareType  = "mcp-response-cache"
 )
 
 // ParserMiddlewareParams represents the parameters for MCP parser middleware
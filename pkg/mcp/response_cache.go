@@ -0,0 +1,538 @@
+package mcp
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/exp/jsonrpc2"
+
+	"github.com/stacklok/toolhive/pkg/auth"
+	"github.com/stacklok/toolhive/pkg/logger"
+	"github.com/stacklok/toolhive/pkg/transport/types"
+)
+
+const (
+	// DefaultResponseCacheTTL is how long a cached response is served when no TTL is configured.
+	DefaultResponseCacheTTL = 5 * time.Minute
+	// DefaultResponseCacheMaxEntries is the number of cached responses kept when no limit is configured.
+	DefaultResponseCacheMaxEntries = 1000
+
+	// maxCachedResponseSize is the size above which responses are passed through without being cached.
+	maxCachedResponseSize = 1 << 20
+	// maxNotificationLineSize is the longest event stream line inspected for notifications.
+	maxNotificationLineSize = 64 << 10
+)
+
+// cacheableMethods are the idempotent MCP methods whose responses are cached.
+// tools/call is only cached for opted-in tools; see ResponseCacheConfig.Tools.
+var cacheableMethods = map[string]struct{}{
+	"tools/list":               {},
+	"prompts/list":             {},
+	"prompts/get":              {},
+	"resources/list":           {},
+	"resources/templates/list": {},
+	"resources/read":           {},
+}
+
+// ResponseCacheConfig configures the response cache middleware.
+type ResponseCacheConfig struct {
+	// TTL is how long a response is served from the cache, e.g. "5m". Defaults to 5 minutes.
+	TTL string `json:"ttl,omitempty" yaml:"ttl,omitempty"`
+
+	// MaxEntries is the maximum number of cached responses. The least recently used
+	// response is evicted when the cache is full. Defaults to 1000.
+	MaxEntries int `json:"max_entries,omitempty" yaml:"max_entries,omitempty"`
+
+	// Tools lists the tools whose tools/call responses may be cached. A tool is only
+	// cached if the server also annotates it with readOnlyHint in its tools/list response.
+	Tools []string `json:"tools,omitempty" yaml:"tools,omitempty"`
+}
+
+// Validate checks the response cache configuration.
+func (c *ResponseCacheConfig) Validate() error {
+	if _, err := c.ttl(); err != nil {
+		return err
+	}
+	if c.MaxEntries < 0 {
+		return fmt.Errorf("response cache max_entries must not be negative")
+	}
+	return nil
+}
+
+func (c *ResponseCacheConfig) ttl() (time.Duration, error) {
+	if c.TTL == "" {
+		return DefaultResponseCacheTTL, nil
+	}
+	ttl, err := time.ParseDuration(c.TTL)
+	if err != nil {
+		return 0, fmt.Errorf("invalid response cache ttl %q: %w", c.TTL, err)
+	}
+	if ttl <= 0 {
+		return 0, fmt.Errorf("response cache ttl must be positive")
+	}
+	return ttl, nil
+}
+
+// ResponseCacheMiddlewareParams represents the parameters for the response cache middleware
+type ResponseCacheMiddlewareParams struct {
+	Config *ResponseCacheConfig `json:"config"`
+}
+
+// ResponseCacheMiddleware wraps response cache middleware functionality
+type ResponseCacheMiddleware struct {
+	middleware types.MiddlewareFunction
+}
+
+// Handler returns the middleware function used by the proxy.
+func (m *ResponseCacheMiddleware) Handler() types.MiddlewareFunction {
+	return m.middleware
+}
+
+// Close cleans up any resources used by the middleware.
+func (*ResponseCacheMiddleware) Close() error {
+	// The cache is garbage collected with the middleware
+	return nil
+}
+
+// CreateResponseCacheMiddleware factory function for response cache middleware
+func CreateResponseCacheMiddleware(config *types.MiddlewareConfig, runner types.MiddlewareRunner) error {
+	var params ResponseCacheMiddlewareParams
+	if err := json.Unmarshal(config.Parameters, &params); err != nil {
+		return fmt.Errorf("failed to unmarshal response cache middleware parameters: %w", err)
+	}
+	if params.Config == nil {
+		params.Config = &ResponseCacheConfig{}
+	}
+
+	middleware, err := NewResponseCacheMiddleware(params.Config)
+	if err != nil {
+		return fmt.Errorf("failed to create response cache middleware: %w", err)
+	}
+
+	runner.AddMiddleware(config.Type, &ResponseCacheMiddleware{middleware: middleware})
+	return nil
+}
+
+// NewResponseCacheMiddleware creates an HTTP middleware that caches the responses of
+// idempotent MCP requests. It relies on the parsed request stored by ParsingMiddleware.
+//
+// Responses are cached per method, parameters (excluding _meta) and authenticated
+// identity, and replayed with the ID of the request being answered. Only successful
+// responses returned in the HTTP response body (streamable HTTP) are cached.
+//
+// Cached responses are invalidated when a notifications/<kind>/list_changed
+// notification passes through the proxy: every entry whose method starts with
+// "<kind>/" is dropped. notifications/resources/updated drops all resources/read entries.
+func NewResponseCacheMiddleware(config *ResponseCacheConfig) (types.MiddlewareFunction, error) {
+	cache, err := newResponseCache(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			parsed := GetParsedMCPRequest(r.Context())
+			if parsed == nil || parsed.ID == nil || !cache.isCacheable(parsed) {
+				next.ServeHTTP(&notificationWatcher{ResponseWriter: w, cache: cache}, r)
+				return
+			}
+
+			key, err := cacheKey(r, parsed)
+			if err != nil {
+				logger.Debugf("Not caching %s response: %v", parsed.Method, err)
+				next.ServeHTTP(&notificationWatcher{ResponseWriter: w, cache: cache}, r)
+				return
+			}
+
+			if result, ok := cache.get(key); ok {
+				writeCachedResponse(w, parsed.ID, result)
+				return
+			}
+
+			rec := &cacheRecorder{ResponseWriter: w}
+			next.ServeHTTP(rec, r)
+			cache.record(key, parsed, rec)
+		})
+	}, nil
+}
+
+// responseCache is an LRU cache of JSON-RPC results with a fixed TTL.
+type responseCache struct {
+	ttl        time.Duration
+	maxEntries int
+	tools      map[string]struct{}
+	now        func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List
+	// readOnlyTools holds the tools annotated with readOnlyHint in tools/list responses
+	readOnlyTools map[string]struct{}
+}
+
+type responseCacheEntry struct {
+	key       string
+	method    string
+	result    json.RawMessage
+	expiresAt time.Time
+}
+
+func newResponseCache(config *ResponseCacheConfig) (*responseCache, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	ttl, _ := config.ttl()
+	maxEntries := config.MaxEntries
+	if maxEntries == 0 {
+		maxEntries = DefaultResponseCacheMaxEntries
+	}
+
+	tools := make(map[string]struct{}, len(config.Tools))
+	for _, tool := range config.Tools {
+		tools[tool] = struct{}{}
+	}
+
+	return &responseCache{
+		ttl:           ttl,
+		maxEntries:    maxEntries,
+		tools:         tools,
+		now:           time.Now,
+		entries:       make(map[string]*list.Element),
+		lru:           list.New(),
+		readOnlyTools: make(map[string]struct{}),
+	}, nil
+}
+
+// isCacheable reports whether the response to a request may be cached.
+func (c *responseCache) isCacheable(parsed *ParsedMCPRequest) bool {
+	if parsed.IsBatch {
+		return false
+	}
+	if _, ok := cacheableMethods[parsed.Method]; ok {
+		return true
+	}
+	if parsed.Method != "tools/call" {
+		return false
+	}
+	if _, ok := c.tools[parsed.ResourceID]; !ok {
+		return false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, ok := c.readOnlyTools[parsed.ResourceID]
+	return ok
+}
+
+func (c *responseCache) get(key string) (json.RawMessage, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := elem.Value.(*responseCacheEntry)
+	if !c.now().Before(entry.expiresAt) {
+		c.lru.Remove(elem)
+		delete(c.entries, key)
+		return nil, false
+	}
+	c.lru.MoveToFront(elem)
+	return entry.result, true
+}
+
+func (c *responseCache) set(key, method string, result json.RawMessage) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := &responseCacheEntry{key: key, method: method, result: result, expiresAt: c.now().Add(c.ttl)}
+	if elem, ok := c.entries[key]; ok {
+		elem.Value = entry
+		c.lru.MoveToFront(elem)
+		return
+	}
+	c.entries[key] = c.lru.PushFront(entry)
+
+	for c.lru.Len() > c.maxEntries {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*responseCacheEntry).key)
+	}
+}
+
+// invalidate drops all entries whose method starts with prefix.
+func (c *responseCache) invalidate(prefix string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, elem := range c.entries {
+		if strings.HasPrefix(elem.Value.(*responseCacheEntry).method, prefix) {
+			c.lru.Remove(elem)
+			delete(c.entries, key)
+		}
+	}
+	if prefix == "tools/" {
+		// Annotations are learned again from the next tools/list response
+		c.readOnlyTools = make(map[string]struct{})
+	}
+}
+
+// handleNotification invalidates the entries affected by a server notification.
+func (c *responseCache) handleNotification(method string) {
+	if method == "notifications/resources/updated" {
+		logger.Debugf("Invalidating cached resources/read responses after %s", method)
+		c.invalidate("resources/read")
+		return
+	}
+	kind, ok := strings.CutPrefix(method, "notifications/")
+	if !ok {
+		return
+	}
+	if kind, ok = strings.CutSuffix(kind, "/list_changed"); ok {
+		logger.Debugf("Invalidating cached %s responses after %s", kind, method)
+		c.invalidate(kind + "/")
+	}
+}
+
+// learnReadOnlyTools records the tools annotated with readOnlyHint in a tools/list result.
+func (c *responseCache) learnReadOnlyTools(result json.RawMessage) {
+	var toolsList struct {
+		Tools []struct {
+			Name        string `json:"name"`
+			Annotations struct {
+				ReadOnlyHint *bool `json:"readOnlyHint"`
+			} `json:"annotations"`
+		} `json:"tools"`
+	}
+	if err := json.Unmarshal(result, &toolsList); err != nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, tool := range toolsList.Tools {
+		if tool.Annotations.ReadOnlyHint != nil && *tool.Annotations.ReadOnlyHint {
+			c.readOnlyTools[tool.Name] = struct{}{}
+		} else {
+			delete(c.readOnlyTools, tool.Name)
+		}
+	}
+}
+
+// record caches the result of a successful response and processes any
+// notifications sent along with it in an event stream.
+func (c *responseCache) record(key string, parsed *ParsedMCPRequest, rec *cacheRecorder) {
+	if rec.status != http.StatusOK || rec.overflow {
+		return
+	}
+
+	var result json.RawMessage
+	mimeType := strings.Split(rec.Header().Get("Content-Type"), ";")[0]
+	switch mimeType {
+	case "application/json":
+		result = matchingResult(rec.body.Bytes(), parsed.ID)
+	case "text/event-stream":
+		forEachEventData(rec.body.Bytes(), func(data []byte) {
+			if r := matchingResult(data, parsed.ID); r != nil {
+				result = r
+				return
+			}
+			c.inspectNotification(data)
+		})
+	}
+	if result == nil {
+		return
+	}
+
+	if parsed.Method == "tools/list" {
+		c.learnReadOnlyTools(result)
+	}
+	c.set(key, parsed.Method, result)
+}
+
+// inspectNotification handles data if it is a JSON-RPC notification.
+func (c *responseCache) inspectNotification(data []byte) {
+	if !bytes.Contains(data, []byte("notifications/")) {
+		return
+	}
+	msg, err := jsonrpc2.DecodeMessage(data)
+	if err != nil {
+		return
+	}
+	if req, ok := msg.(*jsonrpc2.Request); ok && !req.ID.IsValid() {
+		c.handleNotification(req.Method)
+	}
+}
+
+// matchingResult returns the result of data if it is a successful JSON-RPC response with the given ID.
+func matchingResult(data []byte, id any) json.RawMessage {
+	msg, err := jsonrpc2.DecodeMessage(data)
+	if err != nil {
+		return nil
+	}
+	resp, ok := msg.(*jsonrpc2.Response)
+	if !ok || resp.Error != nil || resp.Result == nil || resp.ID.Raw() != id {
+		return nil
+	}
+	return resp.Result
+}
+
+// forEachEventData calls fn with the data of each single-line data field of an event stream.
+func forEachEventData(stream []byte, fn func(data []byte)) {
+	for _, line := range bytes.Split(stream, []byte("\n")) {
+		if data, ok := bytes.CutPrefix(bytes.TrimRight(line, "\r"), []byte("data:")); ok {
+			fn(bytes.TrimSpace(data))
+		}
+	}
+}
+
+// cacheKey derives the cache key from the identity, method and parameters of a request.
+// The _meta parameter is excluded because it carries per-request data such as progress tokens.
+func cacheKey(r *http.Request, parsed *ParsedMCPRequest) (string, error) {
+	var subject string
+	if identity, ok := auth.IdentityFromContext(r.Context()); ok {
+		subject = identity.Subject
+	}
+
+	params := map[string]any{}
+	if len(parsed.Params) > 0 {
+		decoder := json.NewDecoder(bytes.NewReader(parsed.Params))
+		decoder.UseNumber()
+		if err := decoder.Decode(&params); err != nil {
+			return "", fmt.Errorf("failed to decode params: %w", err)
+		}
+		delete(params, "_meta")
+	}
+	// Map keys are marshaled in sorted order, which makes the encoding canonical
+	canonical, err := json.Marshal(params)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode params: %w", err)
+	}
+
+	h := sha256.New()
+	for _, part := range [][]byte{[]byte(subject), []byte(parsed.Method), canonical} {
+		h.Write(part)
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// writeCachedResponse writes a cached result as the JSON-RPC response to the request with the given ID.
+func writeCachedResponse(w http.ResponseWriter, id any, result json.RawMessage) {
+	body, err := json.Marshal(struct {
+		JSONRPC string          `json:"jsonrpc"`
+		ID      any             `json:"id"`
+		Result  json.RawMessage `json:"result"`
+	}{JSONRPC: "2.0", ID: id, Result: result})
+	if err != nil {
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(body); err != nil {
+		logger.Debugf("Error writing cached response: %v", err)
+	}
+}
+
+// cacheRecorder passes the response through to the client while keeping a copy for the cache.
+type cacheRecorder struct {
+	http.ResponseWriter
+	status   int
+	body     bytes.Buffer
+	overflow bool
+}
+
+// WriteHeader captures the status code
+func (rw *cacheRecorder) WriteHeader(statusCode int) {
+	if rw.status == 0 {
+		rw.status = statusCode
+	}
+	rw.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Write captures a copy of the response body
+func (rw *cacheRecorder) Write(data []byte) (int, error) {
+	if rw.status == 0 {
+		rw.status = http.StatusOK
+	}
+	if !rw.overflow {
+		if rw.body.Len()+len(data) > maxCachedResponseSize {
+			rw.overflow = true
+			rw.body = bytes.Buffer{}
+		} else {
+			rw.body.Write(data)
+		}
+	}
+	return rw.ResponseWriter.Write(data)
+}
+
+// Flush implements http.Flusher
+func (rw *cacheRecorder) Flush() {
+	if flusher, ok := rw.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// notificationWatcher passes event streams through to the client while looking
+// for notifications that invalidate cached responses.
+type notificationWatcher struct {
+	http.ResponseWriter
+	cache *responseCache
+	line  []byte
+	// skipping is set while discarding the rest of a line longer than maxNotificationLineSize
+	skipping bool
+}
+
+// Write inspects event stream lines before passing them through
+func (rw *notificationWatcher) Write(data []byte) (int, error) {
+	if strings.HasPrefix(rw.Header().Get("Content-Type"), "text/event-stream") {
+		rw.scan(data)
+	}
+	return rw.ResponseWriter.Write(data)
+}
+
+func (rw *notificationWatcher) scan(data []byte) {
+	for len(data) > 0 {
+		i := bytes.IndexByte(data, '\n')
+		if i < 0 {
+			rw.appendLine(data)
+			return
+		}
+		rw.appendLine(data[:i])
+		if !rw.skipping {
+			forEachEventData(rw.line, rw.cache.inspectNotification)
+		}
+		rw.line = rw.line[:0]
+		rw.skipping = false
+		data = data[i+1:]
+	}
+}
+
+func (rw *notificationWatcher) appendLine(data []byte) {
+	if rw.skipping {
+		return
+	}
+	if len(rw.line)+len(data) > maxNotificationLineSize {
+		rw.skipping = true
+		rw.line = rw.line[:0]
+		return
+	}
+	rw.line = append(rw.line, data...)
+}
+
+// Flush implements http.Flusher
+func (rw *notificationWatcher) Flush() {
+	if flusher, ok := rw.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
@@ -0,0 +1,261 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stacklok/toolhive/pkg/auth"
+)
+
+// fakeMCPBackend answers every request with a result that counts the calls it received.
+type fakeMCPBackend struct {
+	calls       atomic.Int32
+	contentType string
+	tools       string
+}
+
+func (b *fakeMCPBackend) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	n := b.calls.Add(1)
+	var req struct {
+		ID     any    `json:"id"`
+		Method string `json:"method"`
+	}
+	_ = json.NewDecoder(r.Body).Decode(&req)
+	id, _ := json.Marshal(req.ID)
+
+	result := fmt.Sprintf(`{"call":%d}`, n)
+	if req.Method == "tools/list" {
+		result = b.tools
+	}
+	body := fmt.Sprintf(`{"jsonrpc":"2.0","id":%s,"result":%s}`, id, result)
+	if req.Method == "prompts/get" {
+		body = fmt.Sprintf(`{"jsonrpc":"2.0","id":%s,"error":{"code":-32602,"message":"unknown prompt"}}`, id)
+	}
+
+	if b.contentType == "text/event-stream" {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = fmt.Fprintf(w, "event: message\ndata: %s\n\n", body)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write([]byte(body))
+}
+
+func mcpRequest(t *testing.T, handler http.Handler, id int, method, params string, identity *auth.Identity) map[string]any {
+	t.Helper()
+	body := fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"method":%q,"params":%s}`, id, method, params)
+	req := httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if identity != nil {
+		req = req.WithContext(auth.WithIdentity(req.Context(), identity))
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	data := rec.Body.String()
+	if strings.HasPrefix(rec.Header().Get("Content-Type"), "text/event-stream") {
+		for _, line := range strings.Split(data, "\n") {
+			if d, ok := strings.CutPrefix(line, "data: "); ok {
+				data = d
+			}
+		}
+	}
+	var resp map[string]any
+	require.NoError(t, json.Unmarshal([]byte(data), &resp))
+	return resp
+}
+
+func resultCall(resp map[string]any) any {
+	result, _ := resp["result"].(map[string]any)
+	return result["call"]
+}
+
+func TestResponseCache_CachesIdempotentRequests(t *testing.T) {
+	t.Parallel()
+
+	for _, contentType := range []string{"application/json", "text/event-stream"} {
+		t.Run(contentType, func(t *testing.T) {
+			t.Parallel()
+
+			backend := &fakeMCPBackend{contentType: contentType}
+			middleware, err := NewResponseCacheMiddleware(&ResponseCacheConfig{})
+			require.NoError(t, err)
+			handler := ParsingMiddleware(middleware(backend))
+			alice := &auth.Identity{Subject: "alice"}
+
+			first := mcpRequest(t, handler, 1, "resources/read", `{"uri":"file:///a"}`, alice)
+			assert.Equal(t, float64(1), resultCall(first))
+
+			// Same request with a different ID and _meta is served from the cache
+			second := mcpRequest(t, handler, 2, "resources/read",
+				`{"_meta":{"progressToken":"p"},"uri":"file:///a"}`, alice)
+			assert.Equal(t, float64(1), resultCall(second))
+			assert.Equal(t, float64(2), second["id"])
+			assert.Equal(t, int32(1), backend.calls.Load())
+
+			// Different params, identity and method are cached separately
+			mcpRequest(t, handler, 3, "resources/read", `{"uri":"file:///b"}`, alice)
+			mcpRequest(t, handler, 4, "resources/read", `{"uri":"file:///a"}`, &auth.Identity{Subject: "bob"})
+			mcpRequest(t, handler, 5, "resources/list", `{}`, alice)
+			assert.Equal(t, int32(4), backend.calls.Load())
+
+			// Non-idempotent methods are never cached
+			mcpRequest(t, handler, 6, "tools/call", `{"name":"write"}`, alice)
+			mcpRequest(t, handler, 7, "tools/call", `{"name":"write"}`, alice)
+			assert.Equal(t, int32(6), backend.calls.Load())
+		})
+	}
+}
+
+func TestResponseCache_DoesNotCacheErrors(t *testing.T) {
+	t.Parallel()
+
+	backend := &fakeMCPBackend{}
+	middleware, err := NewResponseCacheMiddleware(&ResponseCacheConfig{})
+	require.NoError(t, err)
+	handler := ParsingMiddleware(middleware(backend))
+
+	mcpRequest(t, handler, 1, "prompts/get", `{"name":"missing"}`, nil)
+	resp := mcpRequest(t, handler, 2, "prompts/get", `{"name":"missing"}`, nil)
+	assert.Contains(t, resp, "error")
+	assert.Equal(t, int32(2), backend.calls.Load())
+}
+
+func TestResponseCache_ReadOnlyToolCalls(t *testing.T) {
+	t.Parallel()
+
+	backend := &fakeMCPBackend{tools: `{"tools":[
+		{"name":"search","annotations":{"readOnlyHint":true}},
+		{"name":"lookup","annotations":{"readOnlyHint":true}},
+		{"name":"write","annotations":{"readOnlyHint":false}}
+	]}`}
+	middleware, err := NewResponseCacheMiddleware(&ResponseCacheConfig{Tools: []string{"search", "write"}})
+	require.NoError(t, err)
+	handler := ParsingMiddleware(middleware(backend))
+
+	// Annotations are unknown until tools/list has been seen
+	mcpRequest(t, handler, 1, "tools/call", `{"name":"search","arguments":{"q":"x"}}`, nil)
+	mcpRequest(t, handler, 2, "tools/call", `{"name":"search","arguments":{"q":"x"}}`, nil)
+	assert.Equal(t, int32(2), backend.calls.Load())
+
+	mcpRequest(t, handler, 3, "tools/list", `{}`, nil)
+	calls := backend.calls.Load()
+
+	// Opted in and read-only
+	mcpRequest(t, handler, 4, "tools/call", `{"name":"search","arguments":{"q":"x"}}`, nil)
+	mcpRequest(t, handler, 5, "tools/call", `{"name":"search","arguments":{"q":"x"}}`, nil)
+	assert.Equal(t, calls+1, backend.calls.Load())
+
+	// Read-only but not opted in
+	mcpRequest(t, handler, 6, "tools/call", `{"name":"lookup"}`, nil)
+	mcpRequest(t, handler, 7, "tools/call", `{"name":"lookup"}`, nil)
+	assert.Equal(t, calls+3, backend.calls.Load())
+
+	// Opted in but not read-only
+	mcpRequest(t, handler, 8, "tools/call", `{"name":"write"}`, nil)
+	mcpRequest(t, handler, 9, "tools/call", `{"name":"write"}`, nil)
+	assert.Equal(t, calls+5, backend.calls.Load())
+}
+
+func TestResponseCache_InvalidatesOnListChanged(t *testing.T) {
+	t.Parallel()
+
+	backend := &fakeMCPBackend{}
+	var notification string
+	mux := http.NewServeMux()
+	mux.Handle("POST /mcp", backend)
+	mux.HandleFunc("GET /mcp", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		// Split the event across writes to exercise line reassembly
+		data := fmt.Sprintf("event: message\ndata: {\"jsonrpc\":\"2.0\",\"method\":%q}\n\n", notification)
+		_, _ = w.Write([]byte(data[:20]))
+		_, _ = w.Write([]byte(data[20:]))
+	})
+
+	middleware, err := NewResponseCacheMiddleware(&ResponseCacheConfig{})
+	require.NoError(t, err)
+	handler := ParsingMiddleware(middleware(mux))
+
+	stream := func(method string) {
+		notification = method
+		req := httptest.NewRequest(http.MethodGet, "/mcp", nil)
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	mcpRequest(t, handler, 1, "prompts/list", `{}`, nil)
+	mcpRequest(t, handler, 2, "resources/list", `{}`, nil)
+	assert.Equal(t, int32(2), backend.calls.Load())
+
+	stream("notifications/prompts/list_changed")
+
+	mcpRequest(t, handler, 3, "prompts/list", `{}`, nil)
+	mcpRequest(t, handler, 4, "resources/list", `{}`, nil)
+	assert.Equal(t, int32(3), backend.calls.Load(), "only prompts entries are invalidated")
+
+	stream("notifications/resources/list_changed")
+
+	mcpRequest(t, handler, 5, "resources/list", `{}`, nil)
+	assert.Equal(t, int32(4), backend.calls.Load())
+}
+
+func TestResponseCache_ExpiryAndEviction(t *testing.T) {
+	t.Parallel()
+
+	cache, err := newResponseCache(&ResponseCacheConfig{TTL: "1m", MaxEntries: 2})
+	require.NoError(t, err)
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	cache.set("a", "resources/read", json.RawMessage(`1`))
+	cache.set("b", "resources/read", json.RawMessage(`2`))
+	_, ok := cache.get("a")
+	require.True(t, ok)
+
+	// "b" is the least recently used entry
+	cache.set("c", "resources/read", json.RawMessage(`3`))
+	_, ok = cache.get("b")
+	assert.False(t, ok)
+	_, ok = cache.get("a")
+	assert.True(t, ok)
+
+	now = now.Add(time.Minute)
+	_, ok = cache.get("a")
+	assert.False(t, ok)
+}
+
+func TestResponseCacheConfig_Validate(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, (&ResponseCacheConfig{}).Validate())
+	assert.NoError(t, (&ResponseCacheConfig{TTL: "30s", MaxEntries: 10}).Validate())
+	assert.Error(t, (&ResponseCacheConfig{TTL: "soon"}).Validate())
+	assert.Error(t, (&ResponseCacheConfig{TTL: "-1m"}).Validate())
+	assert.Error(t, (&ResponseCacheConfig{MaxEntries: -1}).Validate())
+}
+
+func TestCacheKey_IgnoresMetaAndKeyOrder(t *testing.T) {
+	t.Parallel()
+
+	key := func(params string) string {
+		req := httptest.NewRequest(http.MethodPost, "/mcp", nil).WithContext(context.Background())
+		k, err := cacheKey(req, &ParsedMCPRequest{Method: "prompts/get", Params: json.RawMessage(params)})
+		require.NoError(t, err)
+		return k
+	}
+
+	assert.Equal(t,
+		key(`{"name":"p","arguments":{"a":"1","b":"2"}}`),
+		key(`{"_meta":{"progressToken":1},"arguments":{"b":"2","a":"1"},"name":"p"}`))
+	assert.NotEqual(t, key(`{"name":"p"}`), key(`{"name":"q"}`))
+}
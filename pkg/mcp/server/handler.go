@@ -6,8 +6,10 @@ import (
 	"fmt"
 
 	"github.com/stacklok/toolhive/pkg/config"
+	"github.com/stacklok/toolhive/pkg/logger"
 	"github.com/stacklok/toolhive/pkg/registry"
 	"github.com/stacklok/toolhive/pkg/workloads"
+	"github.com/stacklok/toolhive/pkg/workloads/imageupdates"
 )
 
 // Handler handles MCP tool requests for ToolHive
@@ -16,6 +18,7 @@ type Handler struct {
 	workloadManager  workloads.Manager
 	registryProvider registry.Provider
 	configProvider   config.Provider
+	imageUpdates     *imageupdates.Checker
 }
 
 // NewHandler creates a new ToolHive handler
@@ -35,10 +38,17 @@ func NewHandler(ctx context.Context) (*Handler, error) {
 	// Create config provider
 	configProvider := config.NewDefaultProvider()
 
+	// Create image update checker, which is not available in all environments
+	imageUpdates, err := imageupdates.NewChecker(ctx)
+	if err != nil {
+		logger.Debugf("Image update tools are disabled: %v", err)
+	}
+
 	return &Handler{
 		ctx:              ctx,
 		workloadManager:  workloadManager,
 		registryProvider: registryProvider,
 		configProvider:   configProvider,
+		imageUpdates:     imageUpdates,
 	}, nil
 }
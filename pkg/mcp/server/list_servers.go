@@ -9,11 +9,12 @@ import (
 
 // WorkloadInfo represents workload information returned by list
 type WorkloadInfo struct {
-	Name      string `json:"name"`
-	Server    string `json:"server,omitempty"`
-	Status    string `json:"status"`
-	CreatedAt string `json:"created_at"`
-	URL       string `json:"url,omitempty"`
+	Name            string `json:"name"`
+	Server          string `json:"server,omitempty"`
+	Status          string `json:"status"`
+	CreatedAt       string `json:"created_at"`
+	URL             string `json:"url,omitempty"`
+	UpdateAvailable bool   `json:"update_available,omitempty"`
 }
 
 // ListServersResponse represents the response from listing servers
@@ -38,6 +39,11 @@ func (h *Handler) ListServers(ctx context.Context, _ mcp.CallToolRequest) (*mcp.
 			CreatedAt: workload.CreatedAt.Format("2006-01-02 15:04:05"),
 		}
 
+		// Report a newer image found by the last image update check
+		if workload.ImageUpdate != nil {
+			info.UpdateAvailable = workload.ImageUpdate.Available
+		}
+
 		// Add server name from labels if available
 		if serverName, ok := workload.Labels["toolhive.server"]; ok {
 			info.Server = serverName
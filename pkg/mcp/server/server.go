@@ -224,6 +224,48 @@ func registerTools(mcpServer *server.MCPServer, handler *Handler) {
 		},
 	}, handler.RemoveServer)
 
+	mcpServer.AddTool(mcp.Tool{
+		Name:        "check_server_updates",
+		Description: "Check if the registries have newer images for running MCP servers",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"name": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the server to check (checks all running servers if omitted)",
+				},
+			},
+		},
+		Annotations: mcp.ToolAnnotation{
+			Title:        "Check Server Updates",
+			ReadOnlyHint: boolPtr(true),
+		},
+	}, handler.CheckServerUpdates)
+
+	mcpServer.AddTool(mcp.Tool{
+		Name:        "upgrade_server",
+		Description: "Upgrade an MCP server to the latest image of its image reference",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"name": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the server to upgrade",
+				},
+				"image_verification": map[string]interface{}{
+					"type":        "string",
+					"description": "Image verification mode (warn, enabled, disabled), defaults to warn",
+					"enum":        []string{"warn", "enabled", "disabled"},
+				},
+			},
+			Required: []string{"name"},
+		},
+		Annotations: mcp.ToolAnnotation{
+			Title:           "Upgrade Server",
+			DestructiveHint: boolPtr(true),
+		},
+	}, handler.UpgradeServer)
+
 	mcpServer.AddTool(mcp.Tool{
 		Name:        "get_server_logs",
 		Description: "Get logs from a running MCP server",
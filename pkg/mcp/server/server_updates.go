@@ -0,0 +1,96 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/stacklok/toolhive/pkg/core"
+	"github.com/stacklok/toolhive/pkg/runner/retriever"
+)
+
+// checkServerUpdatesArgs holds the arguments for checking server updates
+type checkServerUpdatesArgs struct {
+	Name string `json:"name,omitempty"`
+}
+
+// CheckServerUpdatesResponse represents the response from checking server updates
+type CheckServerUpdatesResponse struct {
+	Updates map[string]*core.ImageUpdate `json:"updates"`
+	Errors  string                       `json:"errors,omitempty"`
+}
+
+// upgradeServerArgs holds the arguments for upgrading a server
+type upgradeServerArgs struct {
+	Name              string `json:"name"`
+	ImageVerification string `json:"image_verification,omitempty"`
+}
+
+// CheckServerUpdates checks if the registries have newer images for running MCP servers
+func (h *Handler) CheckServerUpdates(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := &checkServerUpdatesArgs{}
+	if err := request.BindArguments(args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to parse arguments: %v", err)), nil
+	}
+	if h.imageUpdates == nil {
+		return mcp.NewToolResultError("Image updates cannot be checked in this environment"), nil
+	}
+
+	if args.Name != "" {
+		update, err := h.imageUpdates.CheckWorkload(ctx, args.Name)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to check server for updates: %v", err)), nil
+		}
+		return mcp.NewToolResultStructuredOnly(CheckServerUpdatesResponse{
+			Updates: map[string]*core.ImageUpdate{args.Name: update},
+		}), nil
+	}
+
+	// Report the failed checks with the successful ones
+	updates, err := h.imageUpdates.CheckWorkloads(ctx)
+	response := CheckServerUpdatesResponse{Updates: updates}
+	if err != nil {
+		if updates == nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to check servers for updates: %v", err)), nil
+		}
+		response.Errors = err.Error()
+	}
+	return mcp.NewToolResultStructuredOnly(response), nil
+}
+
+// UpgradeServer upgrades an MCP server to the latest image of its image reference
+func (h *Handler) UpgradeServer(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := &upgradeServerArgs{}
+	if err := request.BindArguments(args); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to parse arguments: %v", err)), nil
+	}
+	if h.imageUpdates == nil {
+		return mcp.NewToolResultError("Servers cannot be upgraded in this environment"), nil
+	}
+	if args.ImageVerification == "" {
+		args.ImageVerification = retriever.VerifyImageWarn
+	}
+
+	update, err := h.imageUpdates.CheckWorkload(ctx, args.Name)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to check server for updates: %v", err)), nil
+	}
+	if !update.Available {
+		return mcp.NewToolResultStructuredOnly(map[string]interface{}{
+			"status": "up_to_date",
+			"name":   args.Name,
+			"image":  update.Image,
+		}), nil
+	}
+
+	if err := h.imageUpdates.Upgrade(ctx, h.workloadManager, args.Name, args.ImageVerification); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to upgrade server: %v", err)), nil
+	}
+
+	return mcp.NewToolResultStructuredOnly(map[string]interface{}{
+		"status": "upgraded",
+		"name":   args.Name,
+		"image":  update.Image,
+	}), nil
+}
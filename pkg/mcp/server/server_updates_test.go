@@ -0,0 +1,41 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_ServerUpdatesUnavailable(t *testing.T) {
+	t.Parallel()
+
+	// The image update checker is nil when images are not managed locally
+	handler := &Handler{ctx: context.Background()}
+
+	tests := []struct {
+		name string
+		call func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error)
+	}{
+		{name: "check_server_updates", call: handler.CheckServerUpdates},
+		{name: "upgrade_server", call: handler.UpgradeServer},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			request := mcp.CallToolRequest{
+				Params: mcp.CallToolParams{
+					Name:      tt.name,
+					Arguments: map[string]interface{}{"name": "fetch"},
+				},
+			}
+
+			result, err := tt.call(context.Background(), request)
+			require.NoError(t, err)
+			assert.True(t, result.IsError)
+		})
+	}
+}
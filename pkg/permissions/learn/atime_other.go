@@ -0,0 +1,13 @@
+//go:build !linux && !darwin
+
+package learn
+
+import (
+	"io/fs"
+	"time"
+)
+
+// accessTime returns false, as reads are not detected on this platform.
+func accessTime(_ fs.FileInfo) (time.Time, bool) {
+	return time.Time{}, false
+}
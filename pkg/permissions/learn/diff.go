@@ -0,0 +1,94 @@
+package learn
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/stacklok/toolhive/pkg/permissions"
+)
+
+// Change is a permission that is only in one of two compared profiles.
+type Change struct {
+	// Field is the field of the profile, e.g. "network.outbound.allow_host"
+	Field string `json:"field"`
+	// Value is the permission
+	Value string `json:"value"`
+	// Added is true if the permission is only in the learned profile, and false
+	// if it is only in the base profile
+	Added bool `json:"added"`
+}
+
+// String formats the change as a line of a diff.
+func (c Change) String() string {
+	sign := "-"
+	if c.Added {
+		sign = "+"
+	}
+	return fmt.Sprintf("%s %s: %s", sign, c.Field, c.Value)
+}
+
+// Diff returns the permissions that differ between a base profile, e.g. the profile of
+// the server in the registry, and a learned profile.
+func Diff(base, learned *permissions.Profile) []Change {
+	if base == nil {
+		base = permissions.NewProfile()
+	}
+	var changes []Change
+	changes = appendChanges(changes, "read", mountStrings(base.Read), mountStrings(learned.Read))
+	changes = appendChanges(changes, "write", mountStrings(base.Write), mountStrings(learned.Write))
+
+	baseOutbound, learnedOutbound := outbound(base), outbound(learned)
+	changes = appendChanges(changes, "network.outbound.insecure_allow_all",
+		boolStrings(baseOutbound.InsecureAllowAll), boolStrings(learnedOutbound.InsecureAllowAll))
+	changes = appendChanges(changes, "network.outbound.allow_host", baseOutbound.AllowHost, learnedOutbound.AllowHost)
+	changes = appendChanges(changes, "network.outbound.allow_port",
+		portStrings(baseOutbound.AllowPort), portStrings(learnedOutbound.AllowPort))
+	return changes
+}
+
+// appendChanges appends the values that are only in one of the two lists, removals first.
+func appendChanges(changes []Change, field string, base, learned []string) []Change {
+	for _, value := range base {
+		if !slices.Contains(learned, value) {
+			changes = append(changes, Change{Field: field, Value: value})
+		}
+	}
+	for _, value := range learned {
+		if !slices.Contains(base, value) {
+			changes = append(changes, Change{Field: field, Value: value, Added: true})
+		}
+	}
+	return changes
+}
+
+func outbound(profile *permissions.Profile) *permissions.OutboundNetworkPermissions {
+	if profile.Network == nil || profile.Network.Outbound == nil {
+		return &permissions.OutboundNetworkPermissions{}
+	}
+	return profile.Network.Outbound
+}
+
+func mountStrings(mounts []permissions.MountDeclaration) []string {
+	values := make([]string, 0, len(mounts))
+	for _, mount := range mounts {
+		values = append(values, string(mount))
+	}
+	return values
+}
+
+func portStrings(ports []int) []string {
+	values := make([]string, 0, len(ports))
+	for _, port := range ports {
+		values = append(values, strconv.Itoa(port))
+	}
+	return values
+}
+
+// boolStrings represents a flag as a list that only contains "true" when it is set.
+func boolStrings(value bool) []string {
+	if value {
+		return []string{"true"}
+	}
+	return nil
+}
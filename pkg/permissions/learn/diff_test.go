@@ -0,0 +1,61 @@
+package learn
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stacklok/toolhive/pkg/permissions"
+)
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+
+	base := &permissions.Profile{
+		Read:  []permissions.MountDeclaration{"/data"},
+		Write: []permissions.MountDeclaration{"/output"},
+		Network: &permissions.NetworkPermissions{
+			Outbound: &permissions.OutboundNetworkPermissions{
+				InsecureAllowAll: true,
+				AllowHost:        []string{"api.github.com"},
+				AllowPort:        []int{443},
+			},
+		},
+	}
+	learned := &permissions.Profile{
+		Read: []permissions.MountDeclaration{"/data", "/output"},
+		Network: &permissions.NetworkPermissions{
+			Outbound: &permissions.OutboundNetworkPermissions{
+				AllowHost: []string{"api.github.com", "pypi.org"},
+				AllowPort: []int{443},
+			},
+		},
+	}
+
+	changes := Diff(base, learned)
+
+	assert.Equal(t, []Change{
+		{Field: "read", Value: "/output", Added: true},
+		{Field: "write", Value: "/output"},
+		{Field: "network.outbound.insecure_allow_all", Value: "true"},
+		{Field: "network.outbound.allow_host", Value: "pypi.org", Added: true},
+	}, changes)
+	assert.Equal(t, "+ read: /output", changes[0].String())
+	assert.Equal(t, "- write: /output", changes[1].String())
+
+	assert.Empty(t, Diff(learned, learned))
+}
+
+func TestDiff_NilBase(t *testing.T) {
+	t.Parallel()
+
+	learned := &permissions.Profile{
+		Network: &permissions.NetworkPermissions{
+			Outbound: &permissions.OutboundNetworkPermissions{AllowPort: []int{443}},
+		},
+	}
+
+	assert.Equal(t, []Change{
+		{Field: "network.outbound.allow_port", Value: "443", Added: true},
+	}, Diff(nil, learned))
+}
@@ -0,0 +1,143 @@
+// Package learn generates least-privilege permission profiles from what an MCP server
+// does while it runs with the permissive "learn" profile.
+//
+// In learn mode, the outbound network is open but goes through the egress proxy, which
+// records the hosts and ports the server connects to, and every host path mounted in the
+// base profile is mounted read-write. When the workload stops, the learned profile keeps
+// only the recorded hosts and ports. Mounts are kept read-only unless they were written
+// to; mounts without an observed access are kept read-only too, and reported, because
+// reads cannot always be observed.
+package learn
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/adrg/xdg"
+
+	"github.com/stacklok/toolhive/pkg/egress"
+	"github.com/stacklok/toolhive/pkg/permissions"
+)
+
+// profileDir is the directory of the learned profiles, relative to the XDG data directory
+const profileDir = "toolhive/learned-profiles"
+
+// PermissiveProfile returns the profile a workload runs with in learn mode: the base
+// profile with all outbound traffic allowed and its host path mounts made writable.
+func PermissiveProfile(base *permissions.Profile) *permissions.Profile {
+	if base == nil {
+		base = permissions.BuiltinNetworkProfile()
+	}
+
+	profile := &permissions.Profile{
+		Name:       permissions.ProfileLearn,
+		Read:       []permissions.MountDeclaration{},
+		Write:      []permissions.MountDeclaration{},
+		Resources:  base.Resources,
+		Privileged: false,
+		Network: &permissions.NetworkPermissions{
+			Outbound: &permissions.OutboundNetworkPermissions{
+				InsecureAllowAll: true,
+				AllowHost:        []string{},
+				AllowPort:        []int{},
+			},
+		},
+	}
+	if base.Network != nil {
+		profile.Network.Mode = base.Network.Mode
+		profile.Network.Inbound = base.Network.Inbound
+	}
+
+	for _, mount := range base.Read {
+		if isHostMount(mount) {
+			profile.Write = append(profile.Write, mount)
+		} else {
+			profile.Read = append(profile.Read, mount)
+		}
+	}
+	profile.Write = append(profile.Write, base.Write...)
+	return profile
+}
+
+// Generate returns the least-privilege profile for a workload that ran with the learning
+// profile since the given time and made the given outbound requests. The accesses to
+// mounts are those recorded by the observer, which may be nil, and those shown by file
+// access and modification times.
+//
+// Generate also returns the host path mounts without an observed access. They are kept
+// read-only, as reads may go unobserved, and should be reviewed.
+func Generate(
+	learning *permissions.Profile,
+	since time.Time,
+	records []*egress.Record,
+	observer *Observer,
+) (*permissions.Profile, []permissions.MountDeclaration) {
+	profile := &permissions.Profile{
+		Read:  []permissions.MountDeclaration{},
+		Write: []permissions.MountDeclaration{},
+		Network: &permissions.NetworkPermissions{
+			Outbound: egress.SuggestOutboundPermissions(records, false),
+		},
+		Resources: learning.Resources,
+	}
+	if learning.Network != nil {
+		profile.Network.Mode = learning.Network.Mode
+		profile.Network.Inbound = learning.Network.Inbound
+	}
+
+	var unobserved []permissions.MountDeclaration
+	observe := func(mount permissions.MountDeclaration) access {
+		a := max(observer.access(mount), observeMount(mount, since))
+		if a == accessNone {
+			unobserved = append(unobserved, mount)
+		}
+		return a
+	}
+
+	for _, mount := range learning.Read {
+		if isHostMount(mount) {
+			observe(mount)
+		}
+		profile.Read = append(profile.Read, mount)
+	}
+	for _, mount := range learning.Write {
+		// Mounts that cannot be observed keep their access mode
+		if !isHostMount(mount) || observe(mount) == accessWrite {
+			profile.Write = append(profile.Write, mount)
+		} else {
+			profile.Read = append(profile.Read, mount)
+		}
+	}
+	return profile, unobserved
+}
+
+// ProfilePath returns the path of the learned profile of a workload.
+func ProfilePath(workloadName string) (string, error) {
+	// Workload names may contain slashes, which are not valid in file names
+	fileName := strings.ReplaceAll(workloadName, "/", "-") + ".json"
+	path, err := xdg.DataFile(filepath.Join(profileDir, fileName))
+	if err != nil {
+		return "", fmt.Errorf("failed to get learned profile path for workload %s: %w", workloadName, err)
+	}
+	return path, nil
+}
+
+// WriteProfile writes the learned profile of a workload and returns its path.
+func WriteProfile(workloadName string, profile *permissions.Profile) (string, error) {
+	path, err := ProfilePath(workloadName)
+	if err != nil {
+		return "", err
+	}
+	data, err := json.MarshalIndent(profile, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal learned profile: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0600); err != nil {
+		return "", fmt.Errorf("failed to write learned profile: %w", err)
+	}
+	return path, nil
+}
@@ -0,0 +1,136 @@
+package learn
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stacklok/toolhive/pkg/egress"
+	"github.com/stacklok/toolhive/pkg/permissions"
+)
+
+func TestPermissiveProfile(t *testing.T) {
+	t.Parallel()
+
+	base := &permissions.Profile{
+		Name:  "registry",
+		Read:  []permissions.MountDeclaration{"/data", "volume://cache:/cache"},
+		Write: []permissions.MountDeclaration{"/output"},
+		Network: &permissions.NetworkPermissions{
+			Mode: "bridge",
+			Outbound: &permissions.OutboundNetworkPermissions{
+				AllowHost: []string{"api.github.com"},
+				AllowPort: []int{443},
+			},
+			Inbound: &permissions.InboundNetworkPermissions{AllowHost: []string{"localhost"}},
+		},
+		Privileged: true,
+		Resources:  &permissions.ResourceLimits{Memory: "512Mi"},
+	}
+
+	profile := PermissiveProfile(base)
+
+	assert.Equal(t, permissions.ProfileLearn, profile.Name)
+	assert.Equal(t, []permissions.MountDeclaration{"volume://cache:/cache"}, profile.Read)
+	assert.Equal(t, []permissions.MountDeclaration{"/data", "/output"}, profile.Write)
+	assert.True(t, profile.Network.Outbound.InsecureAllowAll)
+	assert.Empty(t, profile.Network.Outbound.AllowHost)
+	assert.Equal(t, "bridge", profile.Network.Mode)
+	assert.Equal(t, base.Network.Inbound, profile.Network.Inbound)
+	assert.False(t, profile.Privileged, "learn mode never runs privileged")
+	assert.Equal(t, base.Resources, profile.Resources)
+
+	fallback := PermissiveProfile(nil)
+	assert.Equal(t, permissions.ProfileLearn, fallback.Name)
+	assert.True(t, fallback.Network.Outbound.InsecureAllowAll)
+}
+
+// touchTree creates a directory with a file and sets the access and modification times
+// of both.
+func touchTree(t *testing.T, dir string, atime, mtime time.Time) {
+	t.Helper()
+
+	require.NoError(t, os.MkdirAll(dir, 0750))
+	file := filepath.Join(dir, "file.txt")
+	require.NoError(t, os.WriteFile(file, []byte("data"), 0600))
+	require.NoError(t, os.Chtimes(file, atime, mtime))
+	require.NoError(t, os.Chtimes(dir, mtime, mtime))
+}
+
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+
+	since := time.Now().Add(-time.Hour)
+	before := since.Add(-time.Hour)
+	after := since.Add(30 * time.Minute)
+
+	root := t.TempDir()
+	written := filepath.Join(root, "written")
+	read := filepath.Join(root, "read")
+	untouched := filepath.Join(root, "untouched")
+	readOnly := filepath.Join(root, "read-only")
+	touchTree(t, written, after, after)
+	touchTree(t, read, after, before)
+	touchTree(t, untouched, before, before)
+	touchTree(t, readOnly, before, before)
+
+	learning := &permissions.Profile{
+		Name: permissions.ProfileLearn,
+		Read: []permissions.MountDeclaration{
+			permissions.MountDeclaration(readOnly + ":/read-only"),
+			"volume://cache:/cache",
+		},
+		Write: []permissions.MountDeclaration{
+			permissions.MountDeclaration(written + ":/written"),
+			permissions.MountDeclaration(read + ":/read"),
+			permissions.MountDeclaration(untouched + ":/untouched"),
+		},
+		Network: &permissions.NetworkPermissions{
+			Outbound: &permissions.OutboundNetworkPermissions{InsecureAllowAll: true},
+		},
+	}
+	records := []*egress.Record{
+		{Host: "pypi.org", Port: 443, Allowed: true},
+		{Host: "api.github.com", Port: 443, Allowed: true},
+	}
+
+	profile, unobserved := Generate(learning, since, records, nil)
+
+	assert.Empty(t, profile.Name)
+	assert.Equal(t, []permissions.MountDeclaration{permissions.MountDeclaration(written + ":/written")}, profile.Write)
+	// Mounts without an observed access are kept read-only
+	assert.Equal(t, []permissions.MountDeclaration{
+		permissions.MountDeclaration(readOnly + ":/read-only"),
+		"volume://cache:/cache",
+		permissions.MountDeclaration(read + ":/read"),
+		permissions.MountDeclaration(untouched + ":/untouched"),
+	}, profile.Read)
+	if _, ok := accessTime(mustStat(t, read)); ok {
+		assert.Equal(t, []permissions.MountDeclaration{
+			permissions.MountDeclaration(readOnly + ":/read-only"),
+			permissions.MountDeclaration(untouched + ":/untouched"),
+		}, unobserved)
+	}
+	assert.False(t, profile.Network.Outbound.InsecureAllowAll)
+	assert.Equal(t, []string{"api.github.com", "pypi.org"}, profile.Network.Outbound.AllowHost)
+	assert.Equal(t, []int{443}, profile.Network.Outbound.AllowPort)
+}
+
+func TestObserveMount_MissingSource(t *testing.T) {
+	t.Parallel()
+
+	missing := permissions.MountDeclaration(filepath.Join(t.TempDir(), "missing") + ":/data")
+	assert.Equal(t, accessNone, observeMount(missing, time.Now()))
+}
+
+func mustStat(t *testing.T, path string) os.FileInfo {
+	t.Helper()
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	return info
+}
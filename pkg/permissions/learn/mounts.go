@@ -0,0 +1,80 @@
+package learn
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"time"
+
+	"github.com/stacklok/toolhive/pkg/logger"
+	"github.com/stacklok/toolhive/pkg/permissions"
+)
+
+// maxObservedEntries bounds the number of files inspected per mount, so that learning
+// does not stall on huge directory trees. Mounts with more files are kept as writable.
+const maxObservedEntries = 100000
+
+// errTooManyEntries stops the walk of a mount with more than maxObservedEntries files
+var errTooManyEntries = errors.New("too many entries")
+
+// access is how a workload accessed a mount
+type access int
+
+const (
+	accessNone access = iota
+	accessRead
+	accessWrite
+)
+
+// isHostMount returns true if the mount is a host path that can be observed.
+func isHostMount(mount permissions.MountDeclaration) bool {
+	return mount.IsValid() && !mount.IsResourceURI()
+}
+
+// observeMount returns how the host path of a mount was accessed since the given time.
+// A file modified after that time counts as a write, a file accessed after that time
+// as a read. Reads are best effort: they are only detected on Linux and macOS, and
+// filesystems mounted with noatime or relatime may not record them, see Observer.
+// Directory access times are ignored, as the Observer lists the mounted directories.
+func observeMount(mount permissions.MountDeclaration, since time.Time) access {
+	source, _, err := mount.Parse()
+	if err != nil {
+		return accessNone
+	}
+	root, err := filepath.Abs(source)
+	if err != nil {
+		logger.Debugf("Failed to resolve mount source %s: %v", source, err)
+		return accessNone
+	}
+
+	result := accessNone
+	entries := 0
+	err = filepath.WalkDir(root, func(_ string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			// Skip unreadable entries, the workload could not access them either
+			return nil
+		}
+		entries++
+		if entries > maxObservedEntries {
+			return errTooManyEntries
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil
+		}
+		if info.ModTime().After(since) {
+			result = accessWrite
+			return fs.SkipAll
+		}
+		// Listing directories updates their access time, so only reads of files count
+		if atime, ok := accessTime(info); ok && !info.IsDir() && atime.After(since) {
+			result = accessRead
+		}
+		return nil
+	})
+	if errors.Is(err, errTooManyEntries) {
+		logger.Warnf("Mount %s has more than %d files, keeping it writable", source, maxObservedEntries)
+		return accessWrite
+	}
+	return result
+}
@@ -0,0 +1,59 @@
+package learn
+
+import (
+	"sync"
+
+	"github.com/stacklok/toolhive/pkg/permissions"
+)
+
+// Observer records the accesses to the host path mounts of a learning profile while the
+// workload runs. Unlike file access times, which Generate falls back to, its observations
+// do not depend on how the filesystems are mounted. Observation is only supported on
+// Linux; on other platforms an Observer records nothing.
+type Observer struct {
+	mu       sync.Mutex
+	accesses map[permissions.MountDeclaration]access
+
+	// stop stops the observation and records the pending events
+	stop     func()
+	stopOnce sync.Once
+}
+
+// Close stops observing the mounts. It must be called before Generate, so that the
+// files Generate inspects are not recorded as accesses of the workload.
+func (o *Observer) Close() {
+	if o == nil || o.stop == nil {
+		return
+	}
+	o.stopOnce.Do(o.stop)
+}
+
+// record records an access to a mount, keeping the strongest access.
+func (o *Observer) record(mount permissions.MountDeclaration, a access) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if a > o.accesses[mount] {
+		o.accesses[mount] = a
+	}
+}
+
+// access returns the strongest recorded access to a mount.
+func (o *Observer) access(mount permissions.MountDeclaration) access {
+	if o == nil {
+		return accessNone
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.accesses[mount]
+}
+
+// observedMounts returns the host path mounts of a profile.
+func observedMounts(profile *permissions.Profile) []permissions.MountDeclaration {
+	var mounts []permissions.MountDeclaration
+	for _, mount := range append(append([]permissions.MountDeclaration{}, profile.Read...), profile.Write...) {
+		if isHostMount(mount) {
+			mounts = append(mounts, mount)
+		}
+	}
+	return mounts
+}
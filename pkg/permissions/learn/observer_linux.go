@@ -0,0 +1,143 @@
+//go:build linux
+
+package learn
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/stacklok/toolhive/pkg/logger"
+	"github.com/stacklok/toolhive/pkg/permissions"
+)
+
+const (
+	// inotifyReadEvents are the events of a directory or file that count as reads
+	inotifyReadEvents = unix.IN_ACCESS | unix.IN_OPEN
+
+	// inotifyWriteEvents are the events of a directory or file that count as writes
+	inotifyWriteEvents = unix.IN_MODIFY | unix.IN_CLOSE_WRITE | unix.IN_CREATE | unix.IN_DELETE |
+		unix.IN_MOVED_FROM | unix.IN_MOVED_TO | unix.IN_DELETE_SELF | unix.IN_MOVE_SELF
+)
+
+// NewObserver starts observing the host path mounts of a learning profile with inotify.
+// Directories are watched up to maxObservedEntries per mount. If inotify is unavailable
+// or its watch limit is reached, the remaining mounts are left to file access times.
+func NewObserver(profile *permissions.Profile) *Observer {
+	o := &Observer{accesses: map[permissions.MountDeclaration]access{}}
+	mounts := observedMounts(profile)
+	if len(mounts) == 0 {
+		return o
+	}
+
+	fd, err := unix.InotifyInit1(unix.IN_NONBLOCK | unix.IN_CLOEXEC)
+	if err != nil {
+		logger.Warnf("Failed to observe mounted paths, only file access times are used: %v", err)
+		return o
+	}
+	file := os.NewFile(uintptr(fd), "inotify")
+
+	watches := map[int][]permissions.MountDeclaration{}
+	for _, mount := range mounts {
+		if err := addWatches(fd, mount, watches); err != nil {
+			logger.Warnf("Failed to observe mount %s, only file access times are used: %v", mount, err)
+		}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		buf := make([]byte, 64*1024)
+		for {
+			n, err := file.Read(buf)
+			if err != nil {
+				return
+			}
+			o.recordEvents(buf[:n], watches)
+		}
+	}()
+
+	o.stop = func() {
+		// Stop the reader, then record the events that are still queued
+		_ = file.SetReadDeadline(time.Now())
+		<-done
+		buf := make([]byte, 64*1024)
+		for {
+			n, err := unix.Read(fd, buf)
+			if err != nil || n <= 0 {
+				break
+			}
+			o.recordEvents(buf[:n], watches)
+		}
+		_ = file.Close()
+	}
+	return o
+}
+
+// addWatches watches the directories of a mount, or the mounted file. The tree is walked
+// before any watch is added, so that the walk itself is not recorded.
+func addWatches(fd int, mount permissions.MountDeclaration, watches map[int][]permissions.MountDeclaration) error {
+	source, _, err := mount.Parse()
+	if err != nil {
+		return err
+	}
+	root, err := filepath.Abs(source)
+	if err != nil {
+		return err
+	}
+
+	var paths []string
+	entries := 0
+	err = filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		entries++
+		if entries > maxObservedEntries {
+			return errTooManyEntries
+		}
+		if entry.IsDir() || path == root {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errTooManyEntries) {
+		return err
+	}
+
+	for _, path := range paths {
+		wd, err := unix.InotifyAddWatch(fd, path, inotifyReadEvents|inotifyWriteEvents|unix.IN_DONT_FOLLOW)
+		if err != nil {
+			return err
+		}
+		watches[wd] = append(watches[wd], mount)
+	}
+	return nil
+}
+
+// recordEvents records the accesses reported by a buffer of inotify events.
+func (o *Observer) recordEvents(buf []byte, watches map[int][]permissions.MountDeclaration) {
+	for offset := 0; offset+unix.SizeofInotifyEvent <= len(buf); {
+		event := (*unix.InotifyEvent)(unsafe.Pointer(&buf[offset]))
+		offset += unix.SizeofInotifyEvent + int(event.Len)
+
+		a := accessNone
+		switch {
+		case event.Mask&inotifyWriteEvents != 0:
+			a = accessWrite
+		case event.Mask&inotifyReadEvents != 0:
+			a = accessRead
+		}
+		if a == accessNone {
+			continue
+		}
+		for _, mount := range watches[int(event.Wd)] {
+			o.record(mount, a)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package learn
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stacklok/toolhive/pkg/permissions"
+)
+
+func TestObserver(t *testing.T) {
+	t.Parallel()
+
+	// Times are reset after every access, as on filesystems mounted with noatime
+	since := time.Now().Add(-time.Hour)
+	before := since.Add(-time.Hour)
+
+	root := t.TempDir()
+	read := filepath.Join(root, "read")
+	written := filepath.Join(root, "written")
+	untouched := filepath.Join(root, "untouched")
+	touchTree(t, read, before, before)
+	touchTree(t, written, before, before)
+	touchTree(t, untouched, before, before)
+
+	learning := &permissions.Profile{
+		Write: []permissions.MountDeclaration{
+			permissions.MountDeclaration(read + ":/read"),
+			permissions.MountDeclaration(written + ":/written"),
+			permissions.MountDeclaration(untouched + ":/untouched"),
+		},
+	}
+	observer := NewObserver(learning)
+	defer observer.Close()
+
+	_, err := os.ReadFile(filepath.Join(read, "file.txt"))
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(written, "file.txt"), []byte("changed"), 0600))
+	for _, path := range []string{read, written} {
+		require.NoError(t, os.Chtimes(filepath.Join(path, "file.txt"), before, before))
+		require.NoError(t, os.Chtimes(path, before, before))
+	}
+	observer.Close()
+
+	profile, unobserved := Generate(learning, since, nil, observer)
+	assert.Equal(t, []permissions.MountDeclaration{permissions.MountDeclaration(written + ":/written")}, profile.Write)
+	assert.Equal(t, []permissions.MountDeclaration{
+		permissions.MountDeclaration(read + ":/read"),
+		permissions.MountDeclaration(untouched + ":/untouched"),
+	}, profile.Read)
+	assert.Equal(t, []permissions.MountDeclaration{permissions.MountDeclaration(untouched + ":/untouched")}, unobserved)
+}
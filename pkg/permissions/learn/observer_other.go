@@ -0,0 +1,13 @@
+//go:build !linux
+
+package learn
+
+import (
+	"github.com/stacklok/toolhive/pkg/permissions"
+)
+
+// NewObserver returns an Observer that records nothing, as accesses are not observed
+// on this platform. Generate falls back to file access and modification times.
+func NewObserver(_ *permissions.Profile) *Observer {
+	return &Observer{accesses: map[permissions.MountDeclaration]access{}}
+}
@@ -18,6 +18,9 @@ const (
 	ProfileNone = "none"
 	// ProfileNetwork is the name of the built-in profile with network permissions
 	ProfileNetwork = "network"
+	// ProfileLearn is the name of the built-in profile that runs a workload with permissive
+	// network and mounts and records what it uses to generate a least-privilege profile
+	ProfileLearn = "learn"
 )
 
 // Profile represents a permission profile for a container
@@ -43,6 +46,10 @@ type Profile struct {
 	// When true, the container has access to all host devices and capabilities
 	// Use with extreme caution as this removes most security isolation
 	Privileged bool `json:"privileged,omitempty" yaml:"privileged,omitempty"`
+
+	// Resources defines CPU, memory and process limits for the container
+	// When nil, the defaults of the container runtime apply
+	Resources *ResourceLimits `json:"resources,omitempty" yaml:"resources,omitempty"`
 }
 
 // NetworkPermissions defines network permissions for a container
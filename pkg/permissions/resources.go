@@ -0,0 +1,150 @@
+package permissions
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// minMemoryBytes is the smallest memory limit accepted by Docker. It also catches
+// Docker-style values such as "512m", which are millibytes as quantities.
+const minMemoryBytes = 6 * 1024 * 1024
+
+// ResourceLimits defines the compute resources a container may use.
+// Quantities use the Kubernetes syntax (e.g. "500m" or "0.5" CPUs, "512Mi" of memory),
+// so that the same profile applies to every runtime.
+type ResourceLimits struct {
+	// CPUShares is the relative CPU weight of the container (Docker default 1024)
+	CPUShares int64 `json:"cpu_shares,omitempty" yaml:"cpu_shares,omitempty"`
+
+	// CPUs is the maximum number of CPUs the container can use (e.g. "1.5" or "500m")
+	CPUs string `json:"cpus,omitempty" yaml:"cpus,omitempty"`
+
+	// Memory is the maximum amount of memory the container can use (e.g. "512Mi", "2Gi")
+	Memory string `json:"memory,omitempty" yaml:"memory,omitempty"`
+
+	// PidsLimit is the maximum number of processes the container can run
+	PidsLimit int64 `json:"pids_limit,omitempty" yaml:"pids_limit,omitempty"`
+
+	// TmpfsSize is the size of the in-memory filesystem mounted at /tmp (e.g. "64Mi").
+	// When empty, /tmp is part of the container filesystem.
+	TmpfsSize string `json:"tmpfs_size,omitempty" yaml:"tmpfs_size,omitempty"`
+}
+
+// IsEmpty returns true if no resource limit is set.
+func (r *ResourceLimits) IsEmpty() bool {
+	return r == nil || *r == ResourceLimits{}
+}
+
+// Validate checks that all resource limits are well-formed.
+func (r *ResourceLimits) Validate() error {
+	if r == nil {
+		return nil
+	}
+	if r.CPUShares < 0 {
+		return fmt.Errorf("cpu_shares must not be negative, got %d", r.CPUShares)
+	}
+	if r.PidsLimit < 0 {
+		return fmt.Errorf("pids_limit must not be negative, got %d", r.PidsLimit)
+	}
+	if _, err := r.NanoCPUs(); err != nil {
+		return err
+	}
+	if _, err := r.MemoryBytes(); err != nil {
+		return err
+	}
+	if _, err := r.TmpfsBytes(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// NanoCPUs returns the CPU limit in units of 10^-9 CPUs, or 0 if there is no limit.
+func (r *ResourceLimits) NanoCPUs() (int64, error) {
+	if r == nil || r.CPUs == "" {
+		return 0, nil
+	}
+	q, err := parsePositiveQuantity("cpus", r.CPUs)
+	if err != nil {
+		return 0, err
+	}
+	// Docker rejects limits below one millicore
+	if q.Cmp(*resource.NewMilliQuantity(1, resource.DecimalSI)) < 0 {
+		return 0, fmt.Errorf("cpus must be at least 1m, got %q", r.CPUs)
+	}
+	return q.ScaledValue(resource.Nano), nil
+}
+
+// MemoryBytes returns the memory limit in bytes, or 0 if there is no limit.
+func (r *ResourceLimits) MemoryBytes() (int64, error) {
+	if r == nil || r.Memory == "" {
+		return 0, nil
+	}
+	q, err := parsePositiveQuantity("memory", r.Memory)
+	if err != nil {
+		return 0, err
+	}
+	if q.Value() < minMemoryBytes {
+		return 0, fmt.Errorf("memory must be at least 6Mi, got %q", r.Memory)
+	}
+	return q.Value(), nil
+}
+
+// TmpfsBytes returns the size of the /tmp filesystem in bytes, or 0 if none is requested.
+func (r *ResourceLimits) TmpfsBytes() (int64, error) {
+	if r == nil || r.TmpfsSize == "" {
+		return 0, nil
+	}
+	q, err := parsePositiveQuantity("tmpfs_size", r.TmpfsSize)
+	if err != nil {
+		return 0, err
+	}
+	return q.Value(), nil
+}
+
+// CPURequestMillis converts CPU shares into the equivalent Kubernetes CPU request,
+// where 1024 shares correspond to one CPU. It returns 0 if no shares are set.
+func (r *ResourceLimits) CPURequestMillis() int64 {
+	if r == nil || r.CPUShares <= 0 {
+		return 0
+	}
+	return max(r.CPUShares*1000/1024, 1)
+}
+
+// Merge returns a copy of r with the non-zero fields of override applied.
+func (r *ResourceLimits) Merge(override *ResourceLimits) *ResourceLimits {
+	merged := &ResourceLimits{}
+	if r != nil {
+		*merged = *r
+	}
+	if override == nil {
+		return merged
+	}
+	if override.CPUShares != 0 {
+		merged.CPUShares = override.CPUShares
+	}
+	if override.CPUs != "" {
+		merged.CPUs = override.CPUs
+	}
+	if override.Memory != "" {
+		merged.Memory = override.Memory
+	}
+	if override.PidsLimit != 0 {
+		merged.PidsLimit = override.PidsLimit
+	}
+	if override.TmpfsSize != "" {
+		merged.TmpfsSize = override.TmpfsSize
+	}
+	return merged
+}
+
+func parsePositiveQuantity(field, value string) (resource.Quantity, error) {
+	q, err := resource.ParseQuantity(value)
+	if err != nil {
+		return resource.Quantity{}, fmt.Errorf("invalid %s %q: %w", field, value, err)
+	}
+	if q.Sign() <= 0 {
+		return resource.Quantity{}, fmt.Errorf("%s must be positive, got %q", field, value)
+	}
+	return q, nil
+}
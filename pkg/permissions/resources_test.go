@@ -0,0 +1,116 @@
+package permissions
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResourceLimits_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		resources *ResourceLimits
+		wantErr   string
+	}{
+		{name: "nil"},
+		{name: "empty", resources: &ResourceLimits{}},
+		{
+			name: "all limits",
+			resources: &ResourceLimits{
+				CPUShares: 512, CPUs: "1.5", Memory: "512Mi", PidsLimit: 256, TmpfsSize: "64Mi",
+			},
+		},
+		{name: "millicores", resources: &ResourceLimits{CPUs: "250m"}},
+		{name: "negative cpu shares", resources: &ResourceLimits{CPUShares: -1}, wantErr: "cpu_shares"},
+		{name: "negative pids limit", resources: &ResourceLimits{PidsLimit: -1}, wantErr: "pids_limit"},
+		{name: "invalid cpus", resources: &ResourceLimits{CPUs: "lots"}, wantErr: "invalid cpus"},
+		{name: "zero cpus", resources: &ResourceLimits{CPUs: "0"}, wantErr: "cpus must be positive"},
+		{name: "sub-millicore cpus", resources: &ResourceLimits{CPUs: "0.0001"}, wantErr: "at least 1m"},
+		{name: "invalid memory", resources: &ResourceLimits{Memory: "1GB"}, wantErr: "invalid memory"},
+		{name: "docker-style memory", resources: &ResourceLimits{Memory: "512m"}, wantErr: "at least 6Mi"},
+		{name: "negative tmpfs size", resources: &ResourceLimits{TmpfsSize: "-1Mi"}, wantErr: "tmpfs_size must be positive"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.resources.Validate()
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestResourceLimits_Conversions(t *testing.T) {
+	t.Parallel()
+
+	r := &ResourceLimits{CPUShares: 512, CPUs: "500m", Memory: "1Gi", TmpfsSize: "64Mi"}
+
+	nanoCPUs, err := r.NanoCPUs()
+	require.NoError(t, err)
+	assert.Equal(t, int64(500_000_000), nanoCPUs)
+
+	memory, err := r.MemoryBytes()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1<<30), memory)
+
+	tmpfs, err := r.TmpfsBytes()
+	require.NoError(t, err)
+	assert.Equal(t, int64(64<<20), tmpfs)
+
+	assert.Equal(t, int64(500), r.CPURequestMillis())
+	assert.Equal(t, int64(1), (&ResourceLimits{CPUShares: 2}).CPURequestMillis())
+
+	var empty *ResourceLimits
+	nanoCPUs, err = empty.NanoCPUs()
+	require.NoError(t, err)
+	assert.Zero(t, nanoCPUs)
+	assert.Zero(t, empty.CPURequestMillis())
+	assert.True(t, empty.IsEmpty())
+	assert.True(t, (&ResourceLimits{}).IsEmpty())
+	assert.False(t, r.IsEmpty())
+}
+
+func TestResourceLimits_Merge(t *testing.T) {
+	t.Parallel()
+
+	base := &ResourceLimits{CPUs: "1", Memory: "1Gi", PidsLimit: 100}
+	merged := base.Merge(&ResourceLimits{Memory: "2Gi", TmpfsSize: "64Mi"})
+
+	assert.Equal(t, &ResourceLimits{CPUs: "1", Memory: "2Gi", PidsLimit: 100, TmpfsSize: "64Mi"}, merged)
+	assert.Equal(t, "1Gi", base.Memory, "the receiver must not be modified")
+
+	var empty *ResourceLimits
+	assert.Equal(t, &ResourceLimits{PidsLimit: 5}, empty.Merge(&ResourceLimits{PidsLimit: 5}))
+	assert.Equal(t, base, base.Merge(nil))
+}
+
+func TestFromFile_Resources(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "profile.json")
+	data := `{"network": {"outbound": {"insecure_allow_all": true}},
+"resources": {"cpu_shares": 512, "cpus": "0.5", "memory": "512Mi", "pids_limit": 128, "tmpfs_size": "64Mi"}}`
+	require.NoError(t, os.WriteFile(path, []byte(data), 0600))
+
+	profile, err := FromFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, &ResourceLimits{
+		CPUShares: 512, CPUs: "0.5", Memory: "512Mi", PidsLimit: 128, TmpfsSize: "64Mi",
+	}, profile.Resources)
+
+	// Profiles without limits do not serialize an empty section
+	out, err := json.Marshal(BuiltinNoneProfile())
+	require.NoError(t, err)
+	assert.NotContains(t, string(out), "resources")
+}
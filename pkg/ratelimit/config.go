@@ -0,0 +1,125 @@
+// Package ratelimit provides token bucket rate limiting for MCP requests.
+package ratelimit
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"sigs.k8s.io/yaml"
+)
+
+// KeyType determines how requests are grouped into token buckets.
+type KeyType string
+
+const (
+	// KeyIdentity groups requests by the authenticated identity's subject.
+	// Unauthenticated requests are grouped by client IP address.
+	KeyIdentity KeyType = "identity"
+	// KeyClientIP groups requests by client IP address.
+	KeyClientIP KeyType = "clientIP"
+	// KeyTool groups tools/call requests by tool name. Other requests are not limited.
+	KeyTool KeyType = "tool"
+	// KeyMethod groups requests by MCP method.
+	KeyMethod KeyType = "method"
+)
+
+// Config configures rate limiting of MCP requests.
+//
+// +kubebuilder:object:generate=true
+type Config struct {
+	// Limits are the token bucket limits to enforce. A request must be allowed
+	// by every limit that applies to it.
+	// +kubebuilder:validation:MinItems=1
+	Limits []Limit `json:"limits" yaml:"limits"`
+}
+
+// Limit is a token bucket limit applied to a group of requests.
+//
+// +kubebuilder:object:generate=true
+type Limit struct {
+	// Name identifies the limit in metrics, audit logs and error responses.
+	// +kubebuilder:validation:Required
+	Name string `json:"name" yaml:"name"`
+
+	// Key determines how requests are grouped; each group has its own bucket.
+	// +kubebuilder:validation:Enum=identity;clientIP;tool;method
+	Key KeyType `json:"key" yaml:"key"`
+
+	// RequestsPerMinute is the rate at which tokens are added to a bucket.
+	// +kubebuilder:validation:Minimum=1
+	RequestsPerMinute int32 `json:"requestsPerMinute" yaml:"requestsPerMinute"`
+
+	// Burst is the size of a bucket, i.e. the number of requests allowed at once.
+	// Defaults to RequestsPerMinute.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Burst int32 `json:"burst,omitempty" yaml:"burst,omitempty"`
+
+	// Methods restricts the limit to the given MCP methods, e.g. "tools/call".
+	// When empty, the limit applies to all methods.
+	// +optional
+	Methods []string `json:"methods,omitempty" yaml:"methods,omitempty"`
+
+	// Tools restricts the limit to tools/call requests for the given tools.
+	// When empty, the limit is not restricted by tool.
+	// +optional
+	Tools []string `json:"tools,omitempty" yaml:"tools,omitempty"`
+}
+
+// LoadConfig reads and validates a rate limit configuration from a YAML or JSON file.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path) // #nosec G304 - path is provided by the user
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rate limit config: %w", err)
+	}
+
+	var cfg Config
+	if err := yaml.UnmarshalStrict(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse rate limit config: %w", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid rate limit config: %w", err)
+	}
+	return &cfg, nil
+}
+
+// Validate checks the rate limit configuration.
+func (c *Config) Validate() error {
+	if len(c.Limits) == 0 {
+		return fmt.Errorf("rate limit configuration requires at least one limit")
+	}
+
+	var errs []error
+	names := make(map[string]struct{}, len(c.Limits))
+	for i, limit := range c.Limits {
+		if limit.Name == "" {
+			errs = append(errs, fmt.Errorf("limits[%d]: name is required", i))
+		} else if _, ok := names[limit.Name]; ok {
+			errs = append(errs, fmt.Errorf("limits[%d]: duplicate name %q", i, limit.Name))
+		}
+		names[limit.Name] = struct{}{}
+
+		switch limit.Key {
+		case KeyIdentity, KeyClientIP, KeyTool, KeyMethod:
+		default:
+			errs = append(errs, fmt.Errorf("limits[%d]: unsupported key %q: must be one of %s, %s, %s or %s",
+				i, limit.Key, KeyIdentity, KeyClientIP, KeyTool, KeyMethod))
+		}
+		if limit.RequestsPerMinute <= 0 {
+			errs = append(errs, fmt.Errorf("limits[%d]: requestsPerMinute must be positive", i))
+		}
+		if limit.Burst < 0 {
+			errs = append(errs, fmt.Errorf("limits[%d]: burst must not be negative", i))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// burst returns the bucket size of the limit.
+func (l *Limit) burst() int {
+	if l.Burst == 0 {
+		return int(l.RequestsPerMinute)
+	}
+	return int(l.Burst)
+}
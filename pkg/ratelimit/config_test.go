@@ -0,0 +1,101 @@
+package ratelimit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		config  Config
+		wantErr string
+	}{
+		{
+			name: "valid",
+			config: Config{Limits: []Limit{
+				{Name: "per-user", Key: KeyIdentity, RequestsPerMinute: 60},
+				{Name: "search", Key: KeyTool, RequestsPerMinute: 10, Burst: 2, Tools: []string{"search"}},
+			}},
+		},
+		{
+			name:    "no limits",
+			config:  Config{},
+			wantErr: "at least one limit",
+		},
+		{
+			name:    "missing name",
+			config:  Config{Limits: []Limit{{Key: KeyMethod, RequestsPerMinute: 1}}},
+			wantErr: "name is required",
+		},
+		{
+			name: "duplicate name",
+			config: Config{Limits: []Limit{
+				{Name: "a", Key: KeyMethod, RequestsPerMinute: 1},
+				{Name: "a", Key: KeyClientIP, RequestsPerMinute: 1},
+			}},
+			wantErr: `duplicate name "a"`,
+		},
+		{
+			name:    "unsupported key",
+			config:  Config{Limits: []Limit{{Name: "a", Key: "header", RequestsPerMinute: 1}}},
+			wantErr: `unsupported key "header"`,
+		},
+		{
+			name:    "zero rate",
+			config:  Config{Limits: []Limit{{Name: "a", Key: KeyMethod}}},
+			wantErr: "requestsPerMinute must be positive",
+		},
+		{
+			name:    "negative burst",
+			config:  Config{Limits: []Limit{{Name: "a", Key: KeyMethod, RequestsPerMinute: 1, Burst: -1}}},
+			wantErr: "burst must not be negative",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.config.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "ratelimit.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`limits:
+- name: per-user
+  key: identity
+  requestsPerMinute: 30
+  burst: 5
+`), 0o600))
+
+	cfg, err := LoadConfig(path)
+	require.NoError(t, err)
+	require.Len(t, cfg.Limits, 1)
+	assert.Equal(t, Limit{Name: "per-user", Key: KeyIdentity, RequestsPerMinute: 30, Burst: 5}, cfg.Limits[0])
+
+	unknown := filepath.Join(dir, "unknown.yaml")
+	require.NoError(t, os.WriteFile(unknown, []byte("limits: []\nrate: 1\n"), 0o600))
+	_, err = LoadConfig(unknown)
+	assert.Error(t, err)
+
+	_, err = LoadConfig(filepath.Join(dir, "missing.yaml"))
+	assert.Error(t, err)
+}
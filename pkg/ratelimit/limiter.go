@@ -0,0 +1,274 @@
+package ratelimit
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"golang.org/x/time/rate"
+
+	"github.com/stacklok/toolhive/pkg/auth"
+	"github.com/stacklok/toolhive/pkg/logger"
+	"github.com/stacklok/toolhive/pkg/mcp"
+)
+
+const (
+	instrumentationName = "github.com/stacklok/toolhive/pkg/ratelimit"
+
+	// cleanupInterval is how often idle buckets are removed.
+	cleanupInterval = time.Minute
+)
+
+// Limiter enforces the configured token bucket limits.
+type Limiter struct {
+	limits     []*limitState
+	serverName string
+	now        func() time.Time
+
+	checked metric.Int64Counter
+	limited metric.Int64Counter
+
+	stopCh    chan struct{}
+	closeOnce sync.Once
+}
+
+// limitState holds the buckets of a single limit.
+type limitState struct {
+	Limit
+	methods map[string]struct{}
+	tools   map[string]struct{}
+
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+type bucket struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// NewLimiter creates a Limiter and starts the background removal of idle buckets.
+// serverName is recorded as an attribute of the emitted metrics.
+// Call Close to stop the background cleanup.
+func NewLimiter(config *Config, serverName string) (*Limiter, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	limits := make([]*limitState, 0, len(config.Limits))
+	for _, limit := range config.Limits {
+		state := &limitState{
+			Limit:   limit,
+			methods: toSet(limit.Methods),
+			tools:   toSet(limit.Tools),
+			buckets: make(map[string]*bucket),
+		}
+		limits = append(limits, state)
+	}
+
+	meter := otel.Meter(instrumentationName)
+	checked, err := meter.Int64Counter(
+		"toolhive_mcp_rate_limit_checks", // The exporter adds the _total suffix automatically
+		metric.WithDescription("Total number of MCP requests checked against a rate limit"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	limited, err := meter.Int64Counter(
+		"toolhive_mcp_rate_limited_requests",
+		metric.WithDescription("Total number of MCP requests rejected by a rate limit"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &Limiter{
+		limits:     limits,
+		serverName: serverName,
+		now:        time.Now,
+		checked:    checked,
+		limited:    limited,
+		stopCh:     make(chan struct{}),
+	}
+	go l.cleanupLoop()
+	return l, nil
+}
+
+// Close stops the background cleanup.
+func (l *Limiter) Close() error {
+	l.closeOnce.Do(func() { close(l.stopCh) })
+	return nil
+}
+
+// Middleware rejects MCP requests that exceed a limit with HTTP 429 and a JSON-RPC error.
+// It relies on the parsed request stored by mcp.ParsingMiddleware; requests that were not
+// parsed and notifications, which cannot receive an error response, are not limited.
+func (l *Limiter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		parsed := mcp.GetParsedMCPRequest(r.Context())
+		if parsed == nil || parsed.ID == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		limit, retryAfter := l.reserve(r, parsed)
+		if limit != nil {
+			logger.Debugf("Rate limit %q exceeded for %s request", limit.Name, parsed.Method)
+			writeRateLimited(w, parsed.ID, limit.Name, retryAfter)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// reserve takes a token from the bucket of every limit that applies to the request.
+// If any bucket is empty, no tokens are taken and the exceeded limit is returned
+// along with the time until a token is available.
+func (l *Limiter) reserve(r *http.Request, parsed *mcp.ParsedMCPRequest) (*limitState, time.Duration) {
+	now := l.now()
+	ctx := r.Context()
+
+	var reservations []*rate.Reservation
+	for _, limit := range l.limits {
+		key, ok := limit.key(r, parsed)
+		if !ok {
+			continue
+		}
+
+		reservation := limit.bucket(key, now).ReserveN(now, 1)
+		l.record(ctx, l.checked, limit, parsed.Method)
+
+		delay := reservation.DelayFrom(now)
+		if reservation.OK() && delay == 0 {
+			reservations = append(reservations, reservation)
+			continue
+		}
+
+		// Return the tokens taken from the other buckets
+		reservation.CancelAt(now)
+		for _, taken := range reservations {
+			taken.CancelAt(now)
+		}
+		l.record(ctx, l.limited, limit, parsed.Method)
+		return limit, delay
+	}
+	return nil, 0
+}
+
+func (l *Limiter) record(ctx context.Context, counter metric.Int64Counter, limit *limitState, method string) {
+	counter.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("server", l.serverName),
+		attribute.String("limit", limit.Name),
+		attribute.String("key", string(limit.Key)),
+		attribute.String("mcp_method", method),
+	))
+}
+
+// key returns the bucket key of the request, or false if the limit does not apply to it.
+func (s *limitState) key(r *http.Request, parsed *mcp.ParsedMCPRequest) (string, bool) {
+	if len(s.methods) > 0 {
+		if _, ok := s.methods[parsed.Method]; !ok {
+			return "", false
+		}
+	}
+	if len(s.tools) > 0 {
+		if parsed.Method != "tools/call" {
+			return "", false
+		}
+		if _, ok := s.tools[parsed.ResourceID]; !ok {
+			return "", false
+		}
+	}
+
+	switch s.Key {
+	case KeyIdentity:
+		if identity, ok := auth.IdentityFromContext(r.Context()); ok && identity.Subject != "" {
+			return "sub:" + identity.Subject, true
+		}
+		return "ip:" + clientIP(r), true
+	case KeyClientIP:
+		return clientIP(r), true
+	case KeyTool:
+		if parsed.Method != "tools/call" || parsed.ResourceID == "" {
+			return "", false
+		}
+		return parsed.ResourceID, true
+	case KeyMethod:
+		return parsed.Method, true
+	default:
+		return "", false
+	}
+}
+
+// bucket returns the bucket for key, creating it if needed.
+func (s *limitState) bucket(key string, now time.Time) *rate.Limiter {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, ok := s.buckets[key]
+	if !ok {
+		perSecond := rate.Limit(float64(s.RequestsPerMinute) / 60)
+		b = &bucket{limiter: rate.NewLimiter(perSecond, s.burst())}
+		s.buckets[key] = b
+	}
+	b.lastSeen = now
+	return b.limiter
+}
+
+// refillTime is how long an unused bucket takes to fill up completely. Full buckets
+// behave like new ones, so buckets idle for this long can be removed.
+func (s *limitState) refillTime() time.Duration {
+	return time.Duration(float64(s.burst()) / float64(s.RequestsPerMinute) * float64(time.Minute))
+}
+
+func (l *Limiter) cleanupLoop() {
+	ticker := time.NewTicker(cleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			l.cleanup()
+		case <-l.stopCh:
+			return
+		}
+	}
+}
+
+// cleanup removes the buckets that have been idle long enough to be full again.
+func (l *Limiter) cleanup() {
+	now := l.now()
+	for _, limit := range l.limits {
+		idle := limit.refillTime()
+		limit.mu.Lock()
+		for key, b := range limit.buckets {
+			if now.Sub(b.lastSeen) >= idle {
+				delete(limit.buckets, key)
+			}
+		}
+		limit.mu.Unlock()
+	}
+}
+
+// clientIP returns the IP address of the client. Forwarding headers are ignored
+// because clients could set them to evade the limits.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+func toSet(values []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(values))
+	for _, v := range values {
+		set[v] = struct{}{}
+	}
+	return set
+}
@@ -0,0 +1,204 @@
+package ratelimit
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stacklok/toolhive/pkg/auth"
+	"github.com/stacklok/toolhive/pkg/mcp"
+)
+
+// newTestLimiter creates a limiter whose clock only moves when the returned function is called.
+func newTestLimiter(t *testing.T, limits ...Limit) (http.Handler, func(time.Duration)) {
+	t.Helper()
+
+	limiter, err := NewLimiter(&Config{Limits: limits}, "test-server")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = limiter.Close() })
+
+	now := time.Now()
+	limiter.now = func() time.Time { return now }
+	advance := func(d time.Duration) { now = now.Add(d) }
+
+	backend := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{}}`))
+	})
+	return mcp.ParsingMiddleware(limiter.Middleware(backend)), advance
+}
+
+type testRequest struct {
+	id       any
+	method   string
+	params   string
+	subject  string
+	clientIP string
+}
+
+func send(handler http.Handler, tr testRequest) *httptest.ResponseRecorder {
+	if tr.params == "" {
+		tr.params = "{}"
+	}
+	id, _ := json.Marshal(tr.id)
+	body := fmt.Sprintf(`{"jsonrpc":"2.0","method":%q,"params":%s`, tr.method, tr.params)
+	if tr.id != nil {
+		body += fmt.Sprintf(`,"id":%s`, id)
+	}
+	body += "}"
+
+	req := httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if tr.clientIP != "" {
+		req.RemoteAddr = tr.clientIP + ":51234"
+	}
+	if tr.subject != "" {
+		req = req.WithContext(auth.WithIdentity(req.Context(), &auth.Identity{Subject: tr.subject}))
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestLimiter_RejectsWithJSONRPCError(t *testing.T) {
+	t.Parallel()
+
+	handler, _ := newTestLimiter(t, Limit{Name: "per-user", Key: KeyIdentity, RequestsPerMinute: 30, Burst: 1})
+
+	rec := send(handler, testRequest{id: 1, method: "tools/list", subject: "alice"})
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	rec = send(handler, testRequest{id: "req-2", method: "tools/list", subject: "alice"})
+	require.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "2", rec.Header().Get("Retry-After"))
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	var resp struct {
+		JSONRPC string `json:"jsonrpc"`
+		ID      any    `json:"id"`
+		Error   struct {
+			Code    int            `json:"code"`
+			Message string         `json:"message"`
+			Data    map[string]any `json:"data"`
+		} `json:"error"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, "2.0", resp.JSONRPC)
+	assert.Equal(t, "req-2", resp.ID)
+	assert.Equal(t, ErrorCodeRateLimited, resp.Error.Code)
+	assert.Contains(t, resp.Error.Message, `"per-user"`)
+	assert.Equal(t, "per-user", resp.Error.Data["limit"])
+}
+
+func TestLimiter_IdentityBuckets(t *testing.T) {
+	t.Parallel()
+
+	handler, _ := newTestLimiter(t, Limit{Name: "per-user", Key: KeyIdentity, RequestsPerMinute: 1})
+
+	assert.Equal(t, http.StatusOK, send(handler, testRequest{id: 1, method: "ping", subject: "alice"}).Code)
+	assert.Equal(t, http.StatusTooManyRequests, send(handler, testRequest{id: 2, method: "ping", subject: "alice"}).Code)
+	assert.Equal(t, http.StatusOK, send(handler, testRequest{id: 3, method: "ping", subject: "bob"}).Code)
+
+	// Anonymous requests fall back to the client IP
+	assert.Equal(t, http.StatusOK, send(handler, testRequest{id: 4, method: "ping", clientIP: "10.0.0.1"}).Code)
+	assert.Equal(t, http.StatusTooManyRequests, send(handler, testRequest{id: 5, method: "ping", clientIP: "10.0.0.1"}).Code)
+	assert.Equal(t, http.StatusOK, send(handler, testRequest{id: 6, method: "ping", clientIP: "10.0.0.2"}).Code)
+}
+
+func TestLimiter_ToolFilter(t *testing.T) {
+	t.Parallel()
+
+	handler, _ := newTestLimiter(t, Limit{
+		Name: "search", Key: KeyTool, RequestsPerMinute: 1, Tools: []string{"search"},
+	})
+
+	call := func(id int, tool string) int {
+		return send(handler, testRequest{id: id, method: "tools/call", params: fmt.Sprintf(`{"name":%q}`, tool)}).Code
+	}
+
+	assert.Equal(t, http.StatusOK, call(1, "search"))
+	assert.Equal(t, http.StatusTooManyRequests, call(2, "search"))
+
+	// Other tools and methods are not limited
+	assert.Equal(t, http.StatusOK, call(3, "fetch"))
+	assert.Equal(t, http.StatusOK, call(4, "fetch"))
+	assert.Equal(t, http.StatusOK, send(handler, testRequest{id: 5, method: "tools/list"}).Code)
+}
+
+func TestLimiter_BurstAndRefill(t *testing.T) {
+	t.Parallel()
+
+	handler, advance := newTestLimiter(t, Limit{Name: "methods", Key: KeyMethod, RequestsPerMinute: 60, Burst: 3})
+
+	for i := range 3 {
+		assert.Equal(t, http.StatusOK, send(handler, testRequest{id: i, method: "tools/list"}).Code)
+	}
+	assert.Equal(t, http.StatusTooManyRequests, send(handler, testRequest{id: 3, method: "tools/list"}).Code)
+
+	// One token is added per second
+	advance(time.Second)
+	assert.Equal(t, http.StatusOK, send(handler, testRequest{id: 4, method: "tools/list"}).Code)
+	assert.Equal(t, http.StatusTooManyRequests, send(handler, testRequest{id: 5, method: "tools/list"}).Code)
+}
+
+func TestLimiter_RejectionDoesNotConsumeOtherLimits(t *testing.T) {
+	t.Parallel()
+
+	handler, _ := newTestLimiter(t,
+		Limit{Name: "per-user", Key: KeyIdentity, RequestsPerMinute: 2},
+		Limit{Name: "search", Key: KeyTool, RequestsPerMinute: 1, Tools: []string{"search"}},
+	)
+
+	search := func(id int) int {
+		return send(handler, testRequest{id: id, method: "tools/call", params: `{"name":"search"}`, subject: "alice"}).Code
+	}
+
+	assert.Equal(t, http.StatusOK, search(1))
+	assert.Equal(t, http.StatusTooManyRequests, search(2))
+
+	// The rejected search did not use alice's second token
+	assert.Equal(t, http.StatusOK, send(handler, testRequest{id: 3, method: "ping", subject: "alice"}).Code)
+	assert.Equal(t, http.StatusTooManyRequests, send(handler, testRequest{id: 4, method: "ping", subject: "alice"}).Code)
+}
+
+func TestLimiter_SkipsNotifications(t *testing.T) {
+	t.Parallel()
+
+	handler, _ := newTestLimiter(t, Limit{Name: "methods", Key: KeyMethod, RequestsPerMinute: 1})
+
+	for range 3 {
+		rec := send(handler, testRequest{method: "notifications/initialized"})
+		assert.Equal(t, http.StatusOK, rec.Code)
+	}
+}
+
+func TestLimiter_CleanupRemovesFullBuckets(t *testing.T) {
+	t.Parallel()
+
+	limiter, err := NewLimiter(&Config{Limits: []Limit{
+		{Name: "per-user", Key: KeyIdentity, RequestsPerMinute: 60, Burst: 10},
+	}}, "test-server")
+	require.NoError(t, err)
+	defer limiter.Close()
+
+	now := time.Now()
+	limiter.now = func() time.Time { return now }
+	limit := limiter.limits[0]
+	limit.bucket("sub:alice", now)
+
+	// The bucket refills in 10 seconds
+	now = now.Add(9 * time.Second)
+	limiter.cleanup()
+	assert.Len(t, limit.buckets, 1)
+
+	now = now.Add(time.Second)
+	limiter.cleanup()
+	assert.Empty(t, limit.buckets)
+}
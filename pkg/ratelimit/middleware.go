@@ -0,0 +1,100 @@
+package ratelimit
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/stacklok/toolhive/pkg/logger"
+	"github.com/stacklok/toolhive/pkg/transport/types"
+)
+
+const (
+	// MiddlewareType is the type constant for rate limit middleware
+	MiddlewareType = "ratelimit"
+
+	// ErrorCodeRateLimited is the JSON-RPC error code returned for rate limited requests.
+	// It is in the range reserved for implementation-defined server errors.
+	ErrorCodeRateLimited = -32029
+)
+
+// MiddlewareParams represents the parameters for rate limit middleware
+type MiddlewareParams struct {
+	Config *Config `json:"config"`
+}
+
+// FactoryMiddleware wraps rate limit middleware functionality for the factory pattern.
+type FactoryMiddleware struct {
+	limiter *Limiter
+}
+
+// Handler returns the middleware function used by the proxy.
+func (m *FactoryMiddleware) Handler() types.MiddlewareFunction {
+	return m.limiter.Middleware
+}
+
+// Close stops the removal of idle buckets.
+func (m *FactoryMiddleware) Close() error {
+	return m.limiter.Close()
+}
+
+// CreateMiddleware factory function for rate limit middleware
+func CreateMiddleware(config *types.MiddlewareConfig, runner types.MiddlewareRunner) error {
+	var params MiddlewareParams
+	if err := json.Unmarshal(config.Parameters, &params); err != nil {
+		return fmt.Errorf("failed to unmarshal rate limit middleware parameters: %w", err)
+	}
+	if params.Config == nil {
+		return fmt.Errorf("rate limit middleware requires a configuration")
+	}
+
+	limiter, err := NewLimiter(params.Config, runner.GetConfig().GetName())
+	if err != nil {
+		return fmt.Errorf("failed to create rate limit middleware: %w", err)
+	}
+
+	runner.AddMiddleware(config.Type, &FactoryMiddleware{limiter: limiter})
+	return nil
+}
+
+// writeRateLimited writes the response to a rate limited request: HTTP 429 with a
+// Retry-After header and a JSON-RPC error for the request ID.
+func writeRateLimited(w http.ResponseWriter, id any, limitName string, retryAfter time.Duration) {
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+
+	type rpcError struct {
+		Code    int64  `json:"code"`
+		Message string `json:"message"`
+		Data    any    `json:"data,omitempty"`
+	}
+	body, err := json.Marshal(struct {
+		JSONRPC string   `json:"jsonrpc"`
+		ID      any      `json:"id"`
+		Error   rpcError `json:"error"`
+	}{
+		JSONRPC: "2.0",
+		ID:      id,
+		Error: rpcError{
+			Code:    ErrorCodeRateLimited,
+			Message: fmt.Sprintf("Rate limit %q exceeded, retry after %d seconds", limitName, seconds),
+			Data:    map[string]any{"limit": limitName, "retryAfterSeconds": seconds},
+		},
+	})
+	if err != nil {
+		http.Error(w, "Too Many Requests", http.StatusTooManyRequests)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", strconv.Itoa(seconds))
+	w.WriteHeader(http.StatusTooManyRequests)
+	if _, err := w.Write(body); err != nil {
+		logger.Debugf("Error writing rate limit response: %v", err)
+	}
+}
@@ -7,11 +7,14 @@ import (
 	"fmt"
 	"io"
 
+	"golang.org/x/mod/semver"
+
 	"github.com/stacklok/toolhive/pkg/audit"
 	"github.com/stacklok/toolhive/pkg/auth"
 	"github.com/stacklok/toolhive/pkg/auth/remote"
 	authsecrets "github.com/stacklok/toolhive/pkg/auth/secrets"
 	"github.com/stacklok/toolhive/pkg/auth/tokenexchange"
+	"github.com/stacklok/toolhive/pkg/authserver"
 	"github.com/stacklok/toolhive/pkg/authz"
 	"github.com/stacklok/toolhive/pkg/container"
 	rt "github.com/stacklok/toolhive/pkg/container/runtime"
@@ -19,11 +22,14 @@ import (
 	"github.com/stacklok/toolhive/pkg/ignore"
 	"github.com/stacklok/toolhive/pkg/labels"
 	"github.com/stacklok/toolhive/pkg/logger"
+	"github.com/stacklok/toolhive/pkg/mcp"
 	"github.com/stacklok/toolhive/pkg/networking"
 	"github.com/stacklok/toolhive/pkg/permissions"
+	"github.com/stacklok/toolhive/pkg/ratelimit"
 	"github.com/stacklok/toolhive/pkg/secrets"
 	"github.com/stacklok/toolhive/pkg/state"
 	"github.com/stacklok/toolhive/pkg/telemetry"
+	"github.com/stacklok/toolhive/pkg/transport/session"
 	"github.com/stacklok/toolhive/pkg/transport/types"
 	workloadtypes "github.com/stacklok/toolhive/pkg/workloads/types"
 )
@@ -32,6 +38,20 @@ import (
 // TODO: Set to "v1.0.0" when we clean up the middleware configuration.
 const CurrentSchemaVersion = "v0.1.0"
 
+// ValidateSchemaVersion checks that a RunConfig with the given schema version can be read.
+// Configurations with a newer major or minor schema version are rejected, since they may
+// contain settings that this version of ToolHive would silently ignore.
+func ValidateSchemaVersion(version string) error {
+	if !semver.IsValid(version) {
+		return fmt.Errorf("invalid schema version %q", version)
+	}
+	if semver.Compare(semver.MajorMinor(version), semver.MajorMinor(CurrentSchemaVersion)) > 0 {
+		return fmt.Errorf("schema version %s is newer than the supported version %s, upgrade ToolHive to read it",
+			version, CurrentSchemaVersion)
+	}
+	return nil
+}
+
 // RunConfig contains all the configuration needed to run an MCP server
 // It is serializable to JSON and YAML
 // NOTE: This format is importable and exportable, and as a result should be
@@ -82,6 +102,10 @@ type RunConfig struct {
 	// PermissionProfile is the permission profile to use
 	PermissionProfile *permissions.Profile `json:"permission_profile" yaml:"permission_profile"`
 
+	// LearnBaseProfile is the profile the learned permission profile is compared to when the
+	// workload runs with the learn permission profile, usually the profile from the registry
+	LearnBaseProfile *permissions.Profile `json:"learn_base_profile,omitempty" yaml:"learn_base_profile,omitempty"`
+
 	// EnvVars are the parsed environment variables as key-value pairs
 	EnvVars map[string]string `json:"env_vars,omitempty" yaml:"env_vars,omitempty"`
 
@@ -169,6 +193,16 @@ type RunConfig struct {
 	// ToolsOverride is a map from an actual tool to its overridden name and/or description
 	ToolsOverride map[string]ToolOverride `json:"tools_override,omitempty" yaml:"tools_override,omitempty"`
 
+	// ResponseCacheConfig contains the configuration for caching responses to idempotent MCP requests
+	ResponseCacheConfig *mcp.ResponseCacheConfig `json:"response_cache_config,omitempty" yaml:"response_cache_config,omitempty"`
+
+	// RateLimitConfig contains the token bucket limits applied to MCP requests
+	RateLimitConfig *ratelimit.Config `json:"rate_limit_config,omitempty" yaml:"rate_limit_config,omitempty"`
+
+	// RestartPolicy defines how the proxy process restarts the workload when it exits.
+	// When nil, the default restart policy applies.
+	RestartPolicy *RestartPolicy `json:"restart_policy,omitempty" yaml:"restart_policy,omitempty"`
+
 	// IgnoreConfig contains configuration for ignore processing
 	IgnoreConfig *ignore.Config `json:"ignore_config,omitempty" yaml:"ignore_config,omitempty"`
 
@@ -183,6 +217,17 @@ type RunConfig struct {
 	// EndpointPrefix is an explicit prefix to prepend to SSE endpoint URLs.
 	// This is used to handle path-based ingress routing scenarios.
 	EndpointPrefix string `json:"endpoint_prefix,omitempty" yaml:"endpoint_prefix,omitempty"`
+
+	// SessionStorage configures where the proxy stores MCP sessions.
+	// Use a networked backend such as Redis to share sessions between proxy replicas.
+	// Only applies to SSE and streamable HTTP transports.
+	SessionStorage *session.StorageConfig `json:"session_storage,omitempty" yaml:"session_storage,omitempty"`
+
+	// EmbeddedAuthServerConfig configures an OAuth authorization server served by the proxy.
+	// When set, MCP clients obtain access tokens from the proxy, which federates user
+	// authentication to an upstream identity provider.
+	// Only applies to SSE and streamable HTTP transports.
+	EmbeddedAuthServerConfig *authserver.RunConfig `json:"embedded_auth_server_config,omitempty" yaml:"embedded_auth_server_config,omitempty"` //nolint:lll
 }
 
 // WriteJSON serializes the RunConfig to JSON and writes it to the provided writer
@@ -578,6 +623,11 @@ func (c *RunConfig) GetBaseName() string {
 	return c.BaseName
 }
 
+// IsLearnMode returns true if the workload runs with the learn permission profile
+func (c *RunConfig) IsLearnMode() bool {
+	return c.PermissionProfile != nil && c.PermissionProfile.Name == permissions.ProfileLearn
+}
+
 // SaveState saves the run configuration to the state store
 func (c *RunConfig) SaveState(ctx context.Context) error {
 	return state.SaveRunConfig(ctx, c)
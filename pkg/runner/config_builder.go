@@ -11,17 +11,22 @@ import (
 	"github.com/stacklok/toolhive/pkg/auth"
 	"github.com/stacklok/toolhive/pkg/auth/remote"
 	"github.com/stacklok/toolhive/pkg/auth/tokenexchange"
+	"github.com/stacklok/toolhive/pkg/authserver"
 	"github.com/stacklok/toolhive/pkg/authz"
 	rt "github.com/stacklok/toolhive/pkg/container/runtime"
+	"github.com/stacklok/toolhive/pkg/groups"
 	"github.com/stacklok/toolhive/pkg/ignore"
 	"github.com/stacklok/toolhive/pkg/labels"
 	"github.com/stacklok/toolhive/pkg/logger"
 	"github.com/stacklok/toolhive/pkg/mcp"
 	"github.com/stacklok/toolhive/pkg/permissions"
+	"github.com/stacklok/toolhive/pkg/permissions/learn"
+	"github.com/stacklok/toolhive/pkg/ratelimit"
 	"github.com/stacklok/toolhive/pkg/recovery"
 	regtypes "github.com/stacklok/toolhive/pkg/registry/registry"
 	"github.com/stacklok/toolhive/pkg/telemetry"
 	"github.com/stacklok/toolhive/pkg/transport"
+	"github.com/stacklok/toolhive/pkg/transport/session"
 	"github.com/stacklok/toolhive/pkg/transport/types"
 	"github.com/stacklok/toolhive/pkg/usagemetrics"
 )
@@ -46,8 +51,14 @@ type runConfigBuilder struct {
 	targetPort int
 	// Store network mode to apply to permission profile after it's loaded
 	networkMode string
+	// Store resource limits to apply to permission profile after it's loaded
+	resources *permissions.ResourceLimits
 	// Build context determines which validation and features are enabled
 	buildContext BuildContext
+	// Store group defaults to merge them after all the settings of the workload are applied
+	groupDefaults *groups.Defaults
+	// Whether a group default configures a middleware
+	groupMiddlewares bool
 }
 
 // RunConfigBuilderOption is a function that modifies the RunConfigBuilder
@@ -240,6 +251,31 @@ func WithEndpointPrefix(prefix string) RunConfigBuilderOption {
 	}
 }
 
+// WithSessionStorage sets the session storage backend used by the proxy
+func WithSessionStorage(storageConfig *session.StorageConfig) RunConfigBuilderOption {
+	return func(b *runConfigBuilder) error {
+		if err := storageConfig.Validate(); err != nil {
+			return fmt.Errorf("invalid session storage configuration: %w", err)
+		}
+		b.config.SessionStorage = storageConfig
+		return nil
+	}
+}
+
+// WithEmbeddedAuthServer configures an OAuth authorization server served by the proxy
+func WithEmbeddedAuthServer(authServerConfig *authserver.RunConfig) RunConfigBuilderOption {
+	return func(b *runConfigBuilder) error {
+		if authServerConfig == nil {
+			return nil
+		}
+		if err := authServerConfig.Validate(); err != nil {
+			return fmt.Errorf("invalid embedded authorization server configuration: %w", err)
+		}
+		b.config.EmbeddedAuthServerConfig = authServerConfig
+		return nil
+	}
+}
+
 // WithNetworkMode sets the network mode for the container.
 // The network mode will be applied to the permission profile after it is loaded.
 func WithNetworkMode(networkMode string) RunConfigBuilderOption {
@@ -249,6 +285,15 @@ func WithNetworkMode(networkMode string) RunConfigBuilderOption {
 	}
 }
 
+// WithResourceLimits sets CPU, memory and process limits for the container.
+// Non-zero limits override those of the permission profile after it is loaded.
+func WithResourceLimits(resources *permissions.ResourceLimits) RunConfigBuilderOption {
+	return func(b *runConfigBuilder) error {
+		b.resources = resources
+		return nil
+	}
+}
+
 // WithK8sPodPatch sets the Kubernetes pod template patch
 func WithK8sPodPatch(patch string) RunConfigBuilderOption {
 	return func(b *runConfigBuilder) error {
@@ -273,6 +318,15 @@ func WithGroup(groupName string) RunConfigBuilderOption {
 	}
 }
 
+// WithGroupDefaults merges the defaults of the group of the workload into the configuration.
+// The settings of the workload take precedence over the group defaults.
+func WithGroupDefaults(defaults *groups.Defaults) RunConfigBuilderOption {
+	return func(b *runConfigBuilder) error {
+		b.groupDefaults = defaults
+		return nil
+	}
+}
+
 // WithLabels sets custom labels from command-line flags
 func WithLabels(labelStrings []string) RunConfigBuilderOption {
 	return func(b *runConfigBuilder) error {
@@ -328,6 +382,17 @@ func WithAuditEnabled(enableAudit bool, auditConfigPath string) RunConfigBuilder
 	}
 }
 
+// WithAuditSinks sets additional destinations of the audit events.
+// It must be applied after WithAuditEnabled, and has no effect if auditing is disabled.
+func WithAuditSinks(sinks []audit.SinkConfig) RunConfigBuilderOption {
+	return func(b *runConfigBuilder) error {
+		if b.config.AuditConfig != nil && len(sinks) > 0 {
+			b.config.AuditConfig.Sinks = sinks
+		}
+		return nil
+	}
+}
+
 // WithOIDCConfig configures OIDC settings
 func WithOIDCConfig(
 	oidcIssuer string,
@@ -436,6 +501,30 @@ func WithToolsOverride(toolOverride map[string]ToolOverride) RunConfigBuilderOpt
 	}
 }
 
+// WithRateLimitConfig sets the rate limit configuration
+func WithRateLimitConfig(rateLimitConfig *ratelimit.Config) RunConfigBuilderOption {
+	return func(b *runConfigBuilder) error {
+		if rateLimitConfig != nil {
+			if err := rateLimitConfig.Validate(); err != nil {
+				return fmt.Errorf("invalid rate limit configuration: %w", err)
+			}
+		}
+		b.config.RateLimitConfig = rateLimitConfig
+		return nil
+	}
+}
+
+// WithRestartPolicy sets the restart policy of the workload
+func WithRestartPolicy(restartPolicy *RestartPolicy) RunConfigBuilderOption {
+	return func(b *runConfigBuilder) error {
+		if err := restartPolicy.Validate(); err != nil {
+			return fmt.Errorf("invalid restart policy: %w", err)
+		}
+		b.config.RestartPolicy = restartPolicy
+		return nil
+	}
+}
+
 // WithIgnoreConfig sets the ignore configuration
 func WithIgnoreConfig(ignoreConfig *ignore.Config) RunConfigBuilderOption {
 	return func(b *runConfigBuilder) error {
@@ -457,6 +546,8 @@ func WithMiddlewareFromFlags(
 	serverName string,
 	transportType string,
 	disableUsageMetrics bool,
+	responseCacheConfig *mcp.ResponseCacheConfig,
+	rateLimitConfig *ratelimit.Config,
 ) RunConfigBuilderOption {
 	return func(b *runConfigBuilder) error {
 		var middlewareConfigs []types.MiddlewareConfig
@@ -482,6 +573,31 @@ func WithMiddlewareFromFlags(
 		middlewareConfigs = addAuthzMiddleware(middlewareConfigs, authzConfigPath)
 		middlewareConfigs = addAuditMiddleware(middlewareConfigs, enableAudit, auditConfigPath, serverName, transportType)
 
+		// Add response cache middleware after authorization and audit so that
+		// cached responses are still authorized and audited
+		if responseCacheConfig != nil {
+			if err := responseCacheConfig.Validate(); err != nil {
+				return err
+			}
+			b.config.ResponseCacheConfig = responseCacheConfig
+		}
+		middlewareConfigs, err := addResponseCacheMiddleware(middlewareConfigs, responseCacheConfig)
+		if err != nil {
+			return err
+		}
+
+		// Add rate limit middleware after audit so that rejected requests are audited
+		if rateLimitConfig != nil {
+			if err := rateLimitConfig.Validate(); err != nil {
+				return fmt.Errorf("invalid rate limit configuration: %w", err)
+			}
+			b.config.RateLimitConfig = rateLimitConfig
+		}
+		middlewareConfigs, err = addRateLimitMiddleware(middlewareConfigs, rateLimitConfig)
+		if err != nil {
+			return err
+		}
+
 		// Add recovery middleware (always present, added last to be outermost wrapper)
 		middlewareConfigs = addRecoveryMiddleware(middlewareConfigs)
 
@@ -729,6 +845,12 @@ func internalRunConfigBuilder(
 		}
 	}
 
+	// Merge the group defaults now that all the settings of the workload are known
+	envVars, err := b.applyGroupDefaults(envVars)
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply group defaults: %w", err)
+	}
+
 	// When using the CLI validation strategy, this is where the prompting for
 	// missing environment variables will happen.
 	processedEnvVars := envVars
@@ -746,6 +868,11 @@ func internalRunConfigBuilder(
 		return nil, fmt.Errorf("failed to validate run config: %w", err)
 	}
 
+	// Rebuild the middlewares if they were configured before the group defaults were merged
+	if err := b.populateGroupDefaultsMiddlewares(); err != nil {
+		return nil, fmt.Errorf("failed to configure middlewares from group defaults: %w", err)
+	}
+
 	// Now set environment variables with the correct transport and ports resolved
 	if _, err := b.config.WithEnvironmentVariables(processedEnvVars); err != nil {
 		return nil, fmt.Errorf("failed to set environment variables: %w", err)
@@ -814,6 +941,12 @@ func (b *runConfigBuilder) validateConfig(imageMetadata *regtypes.ImageMetadata)
 		return err
 	}
 
+	// Learn mode records the outbound traffic of the server through the egress proxy
+	if c.IsLearnMode() && !c.IsolateNetwork {
+		logger.Infof("Enabling network isolation to record outbound traffic in learn mode")
+		c.IsolateNetwork = true
+	}
+
 	// Apply network mode to permission profile if specified
 	if b.networkMode != "" {
 		// Ensure Network permissions struct exists
@@ -824,6 +957,14 @@ func (b *runConfigBuilder) validateConfig(imageMetadata *regtypes.ImageMetadata)
 		logger.Infof("Setting network mode to '%s' on permission profile", b.networkMode)
 	}
 
+	// Apply resource limits to permission profile if specified
+	if !b.resources.IsEmpty() {
+		c.PermissionProfile.Resources = c.PermissionProfile.Resources.Merge(b.resources)
+	}
+	if err := c.PermissionProfile.Resources.Validate(); err != nil {
+		return fmt.Errorf("invalid resource limits: %w", err)
+	}
+
 	// Process volume mounts
 	if err = b.processVolumeMounts(); err != nil {
 		return err
@@ -916,22 +1057,32 @@ func (b *runConfigBuilder) loadPermissionProfile(imageMetadata *regtypes.ImageMe
 			return permissions.BuiltinNoneProfile(), nil
 		case permissions.ProfileNetwork:
 			return permissions.BuiltinNetworkProfile(), nil
+		case permissions.ProfileLearn:
+			// Learn what the server needs on top of the profile it would otherwise run with
+			base := defaultPermissionProfile(imageMetadata)
+			b.config.LearnBaseProfile = base
+			return learn.PermissiveProfile(base), nil
 		default:
 			// Try to load from file
 			return permissions.FromFile(b.config.PermissionProfileNameOrPath)
 		}
 	}
 
+	return defaultPermissionProfile(imageMetadata), nil
+}
+
+// defaultPermissionProfile returns the permission profile used when none is set by name or path
+func defaultPermissionProfile(imageMetadata *regtypes.ImageMetadata) *permissions.Profile {
 	// If a profile was not set by name or path, check the image metadata.
 	if imageMetadata != nil && imageMetadata.Permissions != nil {
 
 		logger.Debugf("Using registry permission profile: %v", imageMetadata.Permissions)
-		return imageMetadata.Permissions, nil
+		return imageMetadata.Permissions
 	}
 
 	// If no metadata is available, use the network permission profile as default.
 	logger.Debugf("Using default permission profile: %s", permissions.ProfileNetwork)
-	return permissions.BuiltinNetworkProfile(), nil
+	return permissions.BuiltinNetworkProfile()
 }
 
 // processVolumeMounts processes volume mounts and adds them to the permission profile
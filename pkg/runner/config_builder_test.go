@@ -9,12 +9,16 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/stacklok/toolhive/pkg/audit"
 	"github.com/stacklok/toolhive/pkg/auth"
 	"github.com/stacklok/toolhive/pkg/auth/tokenexchange"
+	"github.com/stacklok/toolhive/pkg/authserver"
+	"github.com/stacklok/toolhive/pkg/groups"
 	"github.com/stacklok/toolhive/pkg/logger"
 	"github.com/stacklok/toolhive/pkg/mcp"
 	"github.com/stacklok/toolhive/pkg/permissions"
 	regtypes "github.com/stacklok/toolhive/pkg/registry/registry"
+	"github.com/stacklok/toolhive/pkg/transport/session"
 	"github.com/stacklok/toolhive/pkg/transport/types"
 )
 
@@ -234,6 +238,198 @@ func TestRunConfigBuilder_Build_WithPermissionProfile(t *testing.T) {
 	}
 }
 
+func TestRunConfigBuilder_Build_WithLearnProfile(t *testing.T) {
+	t.Parallel()
+
+	// Needed to prevent a nil pointer dereference in the logger.
+	logger.Initialize()
+
+	registryProfile := &permissions.Profile{
+		Network: &permissions.NetworkPermissions{
+			Outbound: &permissions.OutboundNetworkPermissions{
+				AllowHost: []string{"api.github.com"},
+				AllowPort: []int{443},
+			},
+		},
+		Read:  []permissions.MountDeclaration{"/test/read"},
+		Write: []permissions.MountDeclaration{"/test/write"},
+	}
+	imageMetadata := &regtypes.ImageMetadata{
+		BaseServerMetadata: regtypes.BaseServerMetadata{Name: "test-image"},
+		Permissions:        registryProfile,
+	}
+
+	config, err := NewRunConfigBuilder(
+		context.Background(),
+		imageMetadata,
+		nil,
+		&mockEnvVarValidator{},
+		WithPermissionProfileNameOrPath(permissions.ProfileLearn),
+		WithNetworkIsolation(false),
+	)
+	require.NoError(t, err)
+
+	assert.True(t, config.IsLearnMode())
+	assert.True(t, config.IsolateNetwork, "learn mode records outbound traffic through the egress proxy")
+	assert.Equal(t, registryProfile, config.LearnBaseProfile)
+	assert.True(t, config.PermissionProfile.Network.Outbound.InsecureAllowAll)
+	assert.Empty(t, config.PermissionProfile.Read)
+	assert.Equal(t, []permissions.MountDeclaration{"/test/read", "/test/write"}, config.PermissionProfile.Write)
+}
+
+func TestRunConfigBuilder_Build_WithGroupDefaults(t *testing.T) {
+	t.Parallel()
+
+	// Needed to prevent a nil pointer dereference in the logger.
+	logger.Initialize()
+
+	groupProfile := &permissions.Profile{
+		Network: &permissions.NetworkPermissions{
+			Outbound: &permissions.OutboundNetworkPermissions{
+				AllowHost: []string{"api.example.com"},
+				AllowPort: []int{443},
+			},
+		},
+	}
+	newDefaults := func() *groups.Defaults {
+		return &groups.Defaults{
+			EnvVars:           map[string]string{"LOG_LEVEL": "info", "REGION": "eu"},
+			Secrets:           []string{"shared-token,target=API_TOKEN", "shared-key,target=API_KEY"},
+			PermissionProfile: groupProfile,
+			OIDCConfig:        &auth.TokenValidatorConfig{Issuer: "https://issuer.example.com", Audience: "tools"},
+			AuditConfig:       &audit.Config{IncludeRequestData: true},
+		}
+	}
+
+	t.Run("group defaults fill the settings of the workload", func(t *testing.T) {
+		t.Parallel()
+
+		config, err := NewRunConfigBuilder(
+			context.Background(),
+			nil,
+			map[string]string{"REGION": "us"},
+			&mockEnvVarValidator{},
+			WithName("test-server"),
+			WithGroup("security-tools"),
+			WithSecrets([]string{"own-token,target=API_TOKEN"}),
+			WithMiddlewareFromFlags(nil, nil, nil, nil, nil, "", false, "", "test-server", "stdio", true, nil, nil),
+			WithGroupDefaults(newDefaults()),
+		)
+		require.NoError(t, err)
+
+		assert.Equal(t, "info", config.EnvVars["LOG_LEVEL"])
+		assert.Equal(t, "us", config.EnvVars["REGION"], "workload environment variables take precedence")
+		assert.ElementsMatch(t, []string{"own-token,target=API_TOKEN", "shared-key,target=API_KEY"}, config.Secrets)
+		assert.Equal(t, groupProfile.Network, config.PermissionProfile.Network)
+		require.NotNil(t, config.OIDCConfig)
+		assert.Equal(t, "https://issuer.example.com", config.OIDCConfig.Issuer)
+		require.NotNil(t, config.AuditConfig)
+		assert.Equal(t, "test-server", config.AuditConfig.Component)
+
+		var middlewareTypes []string
+		for _, middleware := range config.MiddlewareConfigs {
+			middlewareTypes = append(middlewareTypes, middleware.Type)
+		}
+		assert.Contains(t, middlewareTypes, audit.MiddlewareType, "the audit middleware of the group is configured")
+	})
+
+	t.Run("workload settings take precedence over group defaults", func(t *testing.T) {
+		t.Parallel()
+
+		config, err := NewRunConfigBuilder(
+			context.Background(),
+			nil,
+			nil,
+			&mockEnvVarValidator{},
+			WithName("test-server"),
+			WithGroup("security-tools"),
+			WithPermissionProfileNameOrPath(permissions.ProfileNone),
+			WithOIDCConfig("https://own.example.com", "", "", "", "", "", "", "", "", false, false, nil),
+			WithAuditEnabled(true, ""),
+			WithGroupDefaults(newDefaults()),
+		)
+		require.NoError(t, err)
+
+		assert.Equal(t, permissions.BuiltinNoneProfile(), config.PermissionProfile)
+		assert.Equal(t, "https://own.example.com", config.OIDCConfig.Issuer)
+		assert.False(t, config.AuditConfig.IncludeRequestData)
+	})
+}
+
+func TestRunConfigBuilder_Build_WithResourceLimits(t *testing.T) {
+	t.Parallel()
+
+	logger.Initialize()
+
+	profileJSON := `{"resources": {"cpus": "1", "memory": "1Gi", "pids_limit": 100}}`
+
+	testCases := []struct {
+		name              string
+		builderOptions    []RunConfigBuilderOption
+		useProfileFile    bool
+		expectedResources *permissions.ResourceLimits
+		expectError       string
+	}{
+		{
+			name: "No resource limits",
+			builderOptions: []RunConfigBuilderOption{
+				WithPermissionProfileNameOrPath(permissions.ProfileNone),
+			},
+		},
+		{
+			name: "Resource limits on a built-in profile",
+			builderOptions: []RunConfigBuilderOption{
+				WithPermissionProfileNameOrPath(permissions.ProfileNetwork),
+				WithResourceLimits(&permissions.ResourceLimits{Memory: "512Mi", TmpfsSize: "64Mi"}),
+			},
+			expectedResources: &permissions.ResourceLimits{Memory: "512Mi", TmpfsSize: "64Mi"},
+		},
+		{
+			name:              "Resource limits from the profile file",
+			useProfileFile:    true,
+			expectedResources: &permissions.ResourceLimits{CPUs: "1", Memory: "1Gi", PidsLimit: 100},
+		},
+		{
+			name: "Resource limits override the profile file",
+			builderOptions: []RunConfigBuilderOption{
+				WithResourceLimits(&permissions.ResourceLimits{Memory: "2Gi", CPUShares: 512}),
+			},
+			useProfileFile:    true,
+			expectedResources: &permissions.ResourceLimits{CPUShares: 512, CPUs: "1", Memory: "2Gi", PidsLimit: 100},
+		},
+		{
+			name: "Invalid resource limits",
+			builderOptions: []RunConfigBuilderOption{
+				WithPermissionProfileNameOrPath(permissions.ProfileNone),
+				WithResourceLimits(&permissions.ResourceLimits{Memory: "512m"}),
+			},
+			expectError: "invalid resource limits",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			opts := tc.builderOptions
+			if tc.useProfileFile {
+				tempFilePath, cleanup := createTempProfileFile(t, profileJSON)
+				defer cleanup()
+				opts = append(opts, WithPermissionProfileNameOrPath(tempFilePath))
+			}
+
+			config, err := NewRunConfigBuilder(context.Background(), nil, nil, &mockEnvVarValidator{}, opts...)
+			if tc.expectError != "" {
+				assert.ErrorContains(t, err, tc.expectError)
+				return
+			}
+			require.NoError(t, err)
+			require.NotNil(t, config.PermissionProfile)
+			assert.Equal(t, tc.expectedResources, config.PermissionProfile.Resources)
+		})
+	}
+}
+
 func TestRunConfigBuilder_Build_WithVolumeMounts(t *testing.T) {
 	t.Parallel()
 
@@ -712,6 +908,56 @@ func TestNewOperatorRunConfigBuilder(t *testing.T) {
 	assert.NotNil(t, config.ContainerLabels, "ContainerLabels should be initialized")
 }
 
+// TestWithSessionStorage tests that session storage configuration is validated and stored
+func TestWithSessionStorage(t *testing.T) {
+	t.Parallel()
+
+	storageConfig := &session.StorageConfig{
+		Type:  session.StorageTypeRedis,
+		Redis: &session.RedisConfig{Address: "redis:6379", PasswordEnv: "REDIS_PASSWORD"},
+	}
+	config, err := NewOperatorRunConfigBuilder(context.Background(), nil, nil, nil, WithSessionStorage(storageConfig))
+	require.NoError(t, err)
+	assert.Equal(t, storageConfig, config.SessionStorage)
+
+	data, err := json.Marshal(config)
+	require.NoError(t, err)
+	var decoded RunConfig
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, storageConfig, decoded.SessionStorage)
+
+	_, err = NewOperatorRunConfigBuilder(context.Background(), nil, nil, nil,
+		WithSessionStorage(&session.StorageConfig{Type: session.StorageTypeRedis}))
+	assert.Error(t, err)
+}
+
+// TestWithEmbeddedAuthServer tests that the embedded authorization server configuration is validated and stored
+func TestWithEmbeddedAuthServer(t *testing.T) {
+	t.Parallel()
+
+	authServerConfig := &authserver.RunConfig{
+		Issuer:           "https://mcp.example.com",
+		AllowedAudiences: []string{"https://mcp.example.com/mcp"},
+		Upstream: &authserver.UpstreamRunConfig{
+			Issuer:   "https://idp.example.com",
+			ClientID: "toolhive",
+		},
+	}
+	config, err := NewOperatorRunConfigBuilder(context.Background(), nil, nil, nil, WithEmbeddedAuthServer(authServerConfig))
+	require.NoError(t, err)
+	assert.Equal(t, authServerConfig, config.EmbeddedAuthServerConfig)
+
+	data, err := json.Marshal(config)
+	require.NoError(t, err)
+	var decoded RunConfig
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, authServerConfig, decoded.EmbeddedAuthServerConfig)
+
+	_, err = NewOperatorRunConfigBuilder(context.Background(), nil, nil, nil,
+		WithEmbeddedAuthServer(&authserver.RunConfig{Issuer: "https://mcp.example.com"}))
+	assert.Error(t, err)
+}
+
 // TestWithEnvVars tests the WithEnvVars method
 func TestWithEnvVars(t *testing.T) {
 	t.Parallel()
@@ -976,6 +976,36 @@ func TestRunConfig_WriteJSON_ReadJSON(t *testing.T) {
 	assert.Equal(t, originalConfig.EnvVars, readConfig.EnvVars, "EnvVars should match")
 }
 
+func TestValidateSchemaVersion(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		version string
+		wantErr string
+	}{
+		{version: CurrentSchemaVersion},
+		{version: "v0.0.1"},
+		{version: "v0.1.7"},
+		{version: "v0.2.0", wantErr: "newer than the supported version"},
+		{version: "v1.0.0", wantErr: "newer than the supported version"},
+		{version: "0.1.0", wantErr: "invalid schema version"},
+		{version: "latest", wantErr: "invalid schema version"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			t.Parallel()
+			err := ValidateSchemaVersion(tt.version)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
 func TestCommaSeparatedEnvVars(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -0,0 +1,92 @@
+package runner
+
+import (
+	"fmt"
+
+	"github.com/stacklok/toolhive/pkg/logger"
+	"github.com/stacklok/toolhive/pkg/secrets"
+)
+
+// applyGroupDefaults merges the group defaults into the configuration and the environment
+// variables of the workload, and returns the merged environment variables.
+// A group default only applies if the workload does not set the same setting.
+func (b *runConfigBuilder) applyGroupDefaults(envVars map[string]string) (map[string]string, error) {
+	defaults := b.groupDefaults
+	if defaults.IsEmpty() {
+		return envVars, nil
+	}
+	c := b.config
+
+	// Environment variables of the workload override the group variables with the same name
+	mergedEnvVars := make(map[string]string, len(envVars)+len(defaults.EnvVars))
+	for name, value := range defaults.EnvVars {
+		if _, ok := c.EnvVars[name]; !ok {
+			mergedEnvVars[name] = value
+		}
+	}
+	for name, value := range envVars {
+		mergedEnvVars[name] = value
+	}
+
+	// Secrets of the workload override the group secrets with the same target
+	targets := make(map[string]bool, len(c.Secrets))
+	for _, secret := range c.Secrets {
+		if parameter, err := secrets.ParseSecretParameter(secret); err == nil {
+			targets[parameter.Target] = true
+		}
+	}
+	for _, secret := range defaults.Secrets {
+		parameter, err := secrets.ParseSecretParameter(secret)
+		if err != nil {
+			return nil, fmt.Errorf("invalid secret in group %s: %w", c.Group, err)
+		}
+		if !targets[parameter.Target] {
+			c.Secrets = append(c.Secrets, secret)
+		}
+	}
+
+	if defaults.PermissionProfile != nil && c.PermissionProfile == nil && c.PermissionProfileNameOrPath == "" {
+		logger.Debugf("Using permission profile of group %s", c.Group)
+		profile := *defaults.PermissionProfile
+		c.PermissionProfile = &profile
+	}
+
+	if defaults.OIDCConfig != nil && c.OIDCConfig == nil {
+		logger.Debugf("Using OIDC configuration of group %s", c.Group)
+		oidcConfig := *defaults.OIDCConfig
+		c.OIDCConfig = &oidcConfig
+		b.groupMiddlewares = true
+	}
+
+	if defaults.AuthzConfig != nil && c.AuthzConfig == nil && c.AuthzConfigPath == "" {
+		logger.Debugf("Using authorization configuration of group %s", c.Group)
+		c.AuthzConfig = defaults.AuthzConfig
+		b.groupMiddlewares = true
+	}
+
+	if defaults.AuditConfig != nil && c.AuditConfig == nil && c.AuditConfigPath == "" {
+		logger.Debugf("Using audit configuration of group %s", c.Group)
+		auditConfig := *defaults.AuditConfig
+		c.AuditConfig = &auditConfig
+		b.groupMiddlewares = true
+	}
+
+	return mergedEnvVars, nil
+}
+
+// populateGroupDefaultsMiddlewares rebuilds the middlewares of the workload from its configuration
+// when a group default configures a middleware. The middlewares built from the options of the
+// workload do not include the group defaults, which are merged after all the options are applied.
+func (b *runConfigBuilder) populateGroupDefaultsMiddlewares() error {
+	c := b.config
+	if !b.groupMiddlewares || len(c.MiddlewareConfigs) == 0 {
+		// Without middlewares, they are populated from the configuration when the workload runs
+		return nil
+	}
+
+	// The audit events of the workload use the name of the server as component
+	if c.AuditConfig != nil && c.AuditConfig.Component == "" {
+		c.AuditConfig.Component = c.Name
+	}
+	return PopulateMiddlewareConfigs(c)
+}
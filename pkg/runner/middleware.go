@@ -9,6 +9,7 @@ import (
 	"github.com/stacklok/toolhive/pkg/authz"
 	cfg "github.com/stacklok/toolhive/pkg/config"
 	"github.com/stacklok/toolhive/pkg/mcp"
+	"github.com/stacklok/toolhive/pkg/ratelimit"
 	"github.com/stacklok/toolhive/pkg/recovery"
 	"github.com/stacklok/toolhive/pkg/telemetry"
 	"github.com/stacklok/toolhive/pkg/transport/types"
@@ -23,10 +24,12 @@ func GetSupportedMiddlewareFactories() map[string]types.MiddlewareFactory {
 		mcp.ParserMiddlewareType:         mcp.CreateParserMiddleware,
 		mcp.ToolFilterMiddlewareType:     mcp.CreateToolFilterMiddleware,
 		mcp.ToolCallFilterMiddlewareType: mcp.CreateToolCallFilterMiddleware,
+		mcp.ResponseCacheMiddlewareType:  mcp.CreateResponseCacheMiddleware,
 		usagemetrics.MiddlewareType:      usagemetrics.CreateMiddleware,
 		telemetry.MiddlewareType:         telemetry.CreateMiddleware,
 		authz.MiddlewareType:             authz.CreateMiddleware,
 		audit.MiddlewareType:             audit.CreateMiddleware,
+		ratelimit.MiddlewareType:         ratelimit.CreateMiddleware,
 		recovery.MiddlewareType:          recovery.CreateMiddleware,
 	}
 }
@@ -145,6 +148,21 @@ func PopulateMiddlewareConfigs(config *RunConfig) error {
 		middlewareConfigs = append(middlewareConfigs, *auditConfig)
 	}
 
+	// Response cache middleware (if enabled). Added after authorization and audit
+	// so that cached responses are still authorized and audited.
+	middlewareConfigs, err = addResponseCacheMiddleware(middlewareConfigs, config.ResponseCacheConfig)
+	if err != nil {
+		return err
+	}
+
+	// Rate limit middleware (if enabled). Added after audit so that rejected
+	// requests are audited, and after the response cache so that cache hits,
+	// which never reach the MCP server, do not consume tokens.
+	middlewareConfigs, err = addRateLimitMiddleware(middlewareConfigs, config.RateLimitConfig)
+	if err != nil {
+		return err
+	}
+
 	// Recovery middleware (always present, added last to be outermost wrapper)
 	// Middleware is applied in reverse order, so adding last means it executes first
 	// and catches panics from all other middleware and handlers.
@@ -181,6 +199,44 @@ func addTokenExchangeMiddleware(
 	return append(middlewares, *tokenExchangeMwConfig), nil
 }
 
+// addResponseCacheMiddleware adds response cache middleware if configured
+func addResponseCacheMiddleware(
+	middlewares []types.MiddlewareConfig,
+	responseCacheConfig *mcp.ResponseCacheConfig,
+) ([]types.MiddlewareConfig, error) {
+	if responseCacheConfig == nil {
+		return middlewares, nil
+	}
+
+	responseCacheParams := mcp.ResponseCacheMiddlewareParams{
+		Config: responseCacheConfig,
+	}
+	responseCacheMwConfig, err := types.NewMiddlewareConfig(mcp.ResponseCacheMiddlewareType, responseCacheParams)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create response cache middleware config: %w", err)
+	}
+	return append(middlewares, *responseCacheMwConfig), nil
+}
+
+// addRateLimitMiddleware adds rate limit middleware if configured
+func addRateLimitMiddleware(
+	middlewares []types.MiddlewareConfig,
+	rateLimitConfig *ratelimit.Config,
+) ([]types.MiddlewareConfig, error) {
+	if rateLimitConfig == nil {
+		return middlewares, nil
+	}
+
+	rateLimitParams := ratelimit.MiddlewareParams{
+		Config: rateLimitConfig,
+	}
+	rateLimitMwConfig, err := types.NewMiddlewareConfig(ratelimit.MiddlewareType, rateLimitParams)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create rate limit middleware config: %w", err)
+	}
+	return append(middlewares, *rateLimitMwConfig), nil
+}
+
 // addUsageMetricsMiddleware adds usage metrics middleware if enabled
 func addUsageMetricsMiddleware(middlewares []types.MiddlewareConfig, configDisabled bool) ([]types.MiddlewareConfig, error) {
 	if !usagemetrics.ShouldEnableMetrics(configDisabled) {
@@ -486,10 +486,18 @@ func writeAuthFiles(buildContextDir string, authFiles map[string]string, isLocal
 	return cleanupFunc, nil
 }
 
+// localImagePrefix is the repository prefix of the images built from protocol schemes
+const localImagePrefix = "toolhivelocal/"
+
+// IsLocallyBuiltImage returns true if an image was built from a protocol scheme, so it is not in a registry
+func IsLocallyBuiltImage(image string) bool {
+	return strings.HasPrefix(image, localImagePrefix)
+}
+
 // generateImageName generates a unique Docker image name based on the package and transport type.
 func generateImageName(transportType templates.TransportType, packageName string) string {
 	tag := time.Now().Format("20060102150405")
-	return strings.ToLower(fmt.Sprintf("toolhivelocal/%s-%s:%s",
+	return strings.ToLower(fmt.Sprintf(localImagePrefix+"%s-%s:%s",
 		string(transportType),
 		PackageNameToImageName(packageName),
 		tag))
@@ -178,6 +178,41 @@ func TestIsImageProtocolScheme(t *testing.T) {
 	}
 }
 
+func TestIsLocallyBuiltImage(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		input    string
+		expected bool
+	}{
+		{
+			name:     "image built from uvx scheme",
+			input:    generateImageName("stdio", "mcp-server-git"),
+			expected: true,
+		},
+		{
+			name:     "registry image",
+			input:    "ghcr.io/stackloklabs/mcp-fetch:latest",
+			expected: false,
+		},
+		{
+			name:     "protocol scheme",
+			input:    "uvx://mcp-server-git",
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			result := IsLocallyBuiltImage(tt.input)
+			if result != tt.expected {
+				t.Errorf("IsLocallyBuiltImage(%q) = %v, want %v", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
 func TestTemplateDataWithLocalPath(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -0,0 +1,153 @@
+package runner
+
+import (
+	"fmt"
+	"time"
+)
+
+// RestartPolicyName is the name of a restart policy
+type RestartPolicyName string
+
+const (
+	// RestartPolicyNever never restarts a workload after it exits
+	RestartPolicyNever RestartPolicyName = "never"
+	// RestartPolicyOnFailure restarts a workload only if it exits with a non-zero exit code
+	RestartPolicyOnFailure RestartPolicyName = "on-failure"
+	// RestartPolicyAlways restarts a workload whenever it exits
+	RestartPolicyAlways RestartPolicyName = "always"
+)
+
+const (
+	// DefaultRestartMaxRetries is the default number of consecutive restarts before giving up
+	DefaultRestartMaxRetries = 10
+	// DefaultRestartBackoff is the default delay before the first restart
+	DefaultRestartBackoff = 5 * time.Second
+	// DefaultRestartMaxBackoff is the default upper bound of the delay between restarts
+	DefaultRestartMaxBackoff = time.Minute
+
+	// restartResetAfter is how long a workload must run before its consecutive
+	// restarts are forgotten and the backoff starts over.
+	restartResetAfter = 10 * time.Minute
+)
+
+// RestartPolicy defines how the proxy process restarts a workload that exits unexpectedly.
+// The delay between consecutive restarts starts at InitialBackoff and doubles up to MaxBackoff.
+type RestartPolicy struct {
+	// Policy is the restart policy (never, on-failure or always)
+	Policy RestartPolicyName `json:"policy" yaml:"policy"`
+
+	// MaxRetries is the maximum number of consecutive restarts. 0 means unlimited.
+	MaxRetries int `json:"max_retries,omitempty" yaml:"max_retries,omitempty"`
+
+	// InitialBackoff is the delay before the first restart (e.g. "5s")
+	InitialBackoff string `json:"initial_backoff,omitempty" yaml:"initial_backoff,omitempty"`
+
+	// MaxBackoff is the upper bound of the delay between restarts (e.g. "1m")
+	MaxBackoff string `json:"max_backoff,omitempty" yaml:"max_backoff,omitempty"`
+}
+
+// DefaultRestartPolicy returns the restart policy used when a workload does not define one.
+func DefaultRestartPolicy() *RestartPolicy {
+	return &RestartPolicy{
+		Policy:         RestartPolicyAlways,
+		MaxRetries:     DefaultRestartMaxRetries,
+		InitialBackoff: DefaultRestartBackoff.String(),
+		MaxBackoff:     DefaultRestartMaxBackoff.String(),
+	}
+}
+
+// Validate checks that the restart policy is well-formed.
+func (p *RestartPolicy) Validate() error {
+	if p == nil {
+		return nil
+	}
+	switch p.Policy {
+	case RestartPolicyNever, RestartPolicyOnFailure, RestartPolicyAlways:
+	default:
+		return fmt.Errorf("invalid restart policy %q, must be one of: %s, %s, %s",
+			p.Policy, RestartPolicyNever, RestartPolicyOnFailure, RestartPolicyAlways)
+	}
+	if p.MaxRetries < 0 {
+		return fmt.Errorf("max_retries must not be negative, got %d", p.MaxRetries)
+	}
+	initial, err := parseBackoff("initial_backoff", p.InitialBackoff, DefaultRestartBackoff)
+	if err != nil {
+		return err
+	}
+	maxBackoff, err := parseBackoff("max_backoff", p.MaxBackoff, DefaultRestartMaxBackoff)
+	if err != nil {
+		return err
+	}
+	if maxBackoff < initial {
+		return fmt.Errorf("max_backoff (%s) must not be less than initial_backoff (%s)", maxBackoff, initial)
+	}
+	return nil
+}
+
+// ShouldRestart returns true if the policy restarts a workload that exited with the given
+// exit code. A negative exit code means the exit code is unknown and counts as a failure.
+func (p *RestartPolicy) ShouldRestart(exitCode int) bool {
+	switch p.Policy {
+	case RestartPolicyAlways:
+		return true
+	case RestartPolicyOnFailure:
+		return exitCode != 0
+	default:
+		return false
+	}
+}
+
+// RetriesExhausted returns true if the given number of consecutive restarts exceeds
+// the maximum number of retries.
+func (p *RestartPolicy) RetriesExhausted(retries int) bool {
+	return p.MaxRetries > 0 && retries > p.MaxRetries
+}
+
+// Backoff returns the delay before the given restart, counting from 1. Invalid durations
+// fall back to the defaults, as the policy is validated when the workload is created.
+func (p *RestartPolicy) Backoff(retry int) time.Duration {
+	delay, err := parseBackoff("initial_backoff", p.InitialBackoff, DefaultRestartBackoff)
+	if err != nil {
+		delay = DefaultRestartBackoff
+	}
+	maxBackoff, err := parseBackoff("max_backoff", p.MaxBackoff, DefaultRestartMaxBackoff)
+	if err != nil {
+		maxBackoff = DefaultRestartMaxBackoff
+	}
+	for i := 1; i < retry && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, maxBackoff)
+}
+
+// ResetsRetries returns true if a workload that ran for the given duration ran long enough
+// for its consecutive restarts to be forgotten.
+func (*RestartPolicy) ResetsRetries(uptime time.Duration) bool {
+	return uptime >= restartResetAfter
+}
+
+func parseBackoff(field, value string, defaultValue time.Duration) (time.Duration, error) {
+	if value == "" {
+		return defaultValue, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q: %w", field, value, err)
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("%s must be positive, got %q", field, value)
+	}
+	return d, nil
+}
+
+// ContainerExitError is returned by Runner.Run when the workload exited while it was
+// still expected to run, so that the caller can restart it.
+type ContainerExitError struct {
+	// ExitCode is the exit code of the workload, or -1 if it is unknown
+	ExitCode int
+}
+
+// Error implements the error interface.
+func (*ContainerExitError) Error() string {
+	return "container exited, restart needed"
+}
@@ -0,0 +1,114 @@
+package runner
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRestartPolicy_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		policy  *RestartPolicy
+		wantErr string
+	}{
+		{name: "nil"},
+		{name: "default", policy: DefaultRestartPolicy()},
+		{name: "never", policy: &RestartPolicy{Policy: RestartPolicyNever}},
+		{name: "on-failure", policy: &RestartPolicy{Policy: RestartPolicyOnFailure, MaxRetries: 3, InitialBackoff: "1s"}},
+		{name: "unknown policy", policy: &RestartPolicy{Policy: "sometimes"}, wantErr: "invalid restart policy"},
+		{name: "empty policy", policy: &RestartPolicy{}, wantErr: "invalid restart policy"},
+		{
+			name:    "negative max retries",
+			policy:  &RestartPolicy{Policy: RestartPolicyAlways, MaxRetries: -1},
+			wantErr: "max_retries",
+		},
+		{
+			name:    "invalid backoff",
+			policy:  &RestartPolicy{Policy: RestartPolicyAlways, InitialBackoff: "soon"},
+			wantErr: "invalid initial_backoff",
+		},
+		{
+			name:    "zero max backoff",
+			policy:  &RestartPolicy{Policy: RestartPolicyAlways, MaxBackoff: "0s"},
+			wantErr: "max_backoff must be positive",
+		},
+		{
+			name:    "max backoff below initial backoff",
+			policy:  &RestartPolicy{Policy: RestartPolicyAlways, InitialBackoff: "2m", MaxBackoff: "1m"},
+			wantErr: "must not be less than",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.policy.Validate()
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestRestartPolicy_ShouldRestart(t *testing.T) {
+	t.Parallel()
+
+	never := &RestartPolicy{Policy: RestartPolicyNever}
+	onFailure := &RestartPolicy{Policy: RestartPolicyOnFailure}
+	always := &RestartPolicy{Policy: RestartPolicyAlways}
+
+	for _, code := range []int{-1, 0, 1, 137} {
+		assert.False(t, never.ShouldRestart(code), "never, exit code %d", code)
+		assert.True(t, always.ShouldRestart(code), "always, exit code %d", code)
+		assert.Equal(t, code != 0, onFailure.ShouldRestart(code), "on-failure, exit code %d", code)
+	}
+}
+
+func TestRestartPolicy_RetriesExhausted(t *testing.T) {
+	t.Parallel()
+
+	limited := &RestartPolicy{Policy: RestartPolicyAlways, MaxRetries: 3}
+	assert.False(t, limited.RetriesExhausted(3))
+	assert.True(t, limited.RetriesExhausted(4))
+
+	unlimited := &RestartPolicy{Policy: RestartPolicyAlways}
+	assert.False(t, unlimited.RetriesExhausted(1000))
+}
+
+func TestRestartPolicy_Backoff(t *testing.T) {
+	t.Parallel()
+
+	policy := DefaultRestartPolicy()
+	expected := []time.Duration{5 * time.Second, 10 * time.Second, 20 * time.Second, 40 * time.Second, time.Minute, time.Minute}
+	for i, delay := range expected {
+		assert.Equal(t, delay, policy.Backoff(i+1), "retry %d", i+1)
+	}
+
+	custom := &RestartPolicy{Policy: RestartPolicyAlways, InitialBackoff: "100ms", MaxBackoff: "250ms"}
+	assert.Equal(t, 100*time.Millisecond, custom.Backoff(1))
+	assert.Equal(t, 200*time.Millisecond, custom.Backoff(2))
+	assert.Equal(t, 250*time.Millisecond, custom.Backoff(3))
+
+	assert.True(t, policy.ResetsRetries(restartResetAfter))
+	assert.False(t, policy.ResetsRetries(time.Minute))
+}
+
+func TestContainerExitError(t *testing.T) {
+	t.Parallel()
+
+	err := fmt.Errorf("run failed: %w", &ContainerExitError{ExitCode: 2})
+
+	var exitErr *ContainerExitError
+	assert.True(t, errors.As(err, &exitErr))
+	assert.Equal(t, 2, exitErr.ExitCode)
+	assert.Equal(t, "run failed: container exited, restart needed", err.Error())
+}
@@ -10,7 +10,9 @@ import (
 	nameref "github.com/google/go-containerregistry/pkg/name"
 
 	"github.com/stacklok/toolhive/pkg/config"
+	"github.com/stacklok/toolhive/pkg/container"
 	"github.com/stacklok/toolhive/pkg/container/images"
+	"github.com/stacklok/toolhive/pkg/container/process"
 	"github.com/stacklok/toolhive/pkg/container/verifier"
 	thverrors "github.com/stacklok/toolhive/pkg/errors"
 	"github.com/stacklok/toolhive/pkg/logger"
@@ -60,6 +62,16 @@ func GetMCPServer(
 	var imageMetadata *types.ImageMetadata
 	var imageToUse string
 
+	// The process runtime runs protocol schemes directly, without building an image
+	if runner.IsImageProtocolScheme(serverOrImage) && container.NewFactory().SelectedRuntimeName() == process.RuntimeName {
+		logger.Debugf("Running protocol scheme %s with the %s runtime", serverOrImage, process.RuntimeName)
+		imageToUse, err := process.ResolveImage(serverOrImage)
+		if err != nil {
+			return "", nil, errors.Join(ErrBadProtocolScheme, err)
+		}
+		return imageToUse, imageMetadata, nil
+	}
+
 	imageManager := images.NewImageManager(ctx)
 	// Check if the serverOrImage is a protocol scheme, e.g., uvx://, npx://, or go://
 	if runner.IsImageProtocolScheme(serverOrImage) {
@@ -118,6 +130,61 @@ func GetMCPServer(
 	return imageToUse, imageMetadata, nil
 }
 
+// PullVerifiedImage verifies the provenance of an image and pulls it, even if it exists locally.
+// The provenance is the one of the registry server with the same image repository, if any.
+// This is used to upgrade the image of a workload to the image its reference points to.
+func PullVerifiedImage(ctx context.Context, image string, verificationType string) error {
+	imageMetadata, err := findImageMetadata(image)
+	if err != nil {
+		return err
+	}
+
+	// Verify the image against the expected provenance info (if applicable)
+	if err := verifyImage(image, imageMetadata, verificationType); err != nil {
+		return err
+	}
+
+	imageManager := images.NewImageManager(ctx)
+	if err := imageManager.PullImage(ctx, image); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return fmt.Errorf("image pull timed out for %s - the image may be too large or the connection too slow", image)
+		}
+		return fmt.Errorf("failed to pull image %s: %w", image, err)
+	}
+	return nil
+}
+
+// findImageMetadata returns the registry server with the same image repository as an image,
+// or nil if the registry has none
+func findImageMetadata(image string) (*types.ImageMetadata, error) {
+	ref, err := nameref.ParseReference(image)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse image reference %q: %w", image, err)
+	}
+
+	provider, err := registry.GetDefaultProvider()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get registry provider: %w", err)
+	}
+	servers, err := provider.ListImageServers()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list registry servers: %w", err)
+	}
+
+	for _, server := range servers {
+		serverRef, err := nameref.ParseReference(server.Image)
+		if err != nil {
+			logger.Debugf("Skipping registry server %s with invalid image %s: %v", server.Name, server.Image, err)
+			continue
+		}
+		if serverRef.Context().Name() == ref.Context().Name() {
+			logger.Debugf("Found registry server %s for image %s", server.Name, image)
+			return server, nil
+		}
+	}
+	return nil, nil
+}
+
 // handleProtocolScheme handles the protocol scheme case
 func handleProtocolScheme(
 	ctx context.Context,
@@ -15,21 +15,28 @@ import (
 
 	"github.com/stacklok/toolhive/pkg/auth"
 	"github.com/stacklok/toolhive/pkg/auth/remote"
+	"github.com/stacklok/toolhive/pkg/authserver"
 	"github.com/stacklok/toolhive/pkg/client"
 	"github.com/stacklok/toolhive/pkg/config"
 	ct "github.com/stacklok/toolhive/pkg/container"
 	rt "github.com/stacklok/toolhive/pkg/container/runtime"
+	"github.com/stacklok/toolhive/pkg/egress"
 	"github.com/stacklok/toolhive/pkg/labels"
 	"github.com/stacklok/toolhive/pkg/logger"
+	"github.com/stacklok/toolhive/pkg/permissions/learn"
 	"github.com/stacklok/toolhive/pkg/process"
 	"github.com/stacklok/toolhive/pkg/runtime"
 	"github.com/stacklok/toolhive/pkg/secrets"
 	"github.com/stacklok/toolhive/pkg/telemetry"
 	"github.com/stacklok/toolhive/pkg/transport"
+	"github.com/stacklok/toolhive/pkg/transport/session"
 	"github.com/stacklok/toolhive/pkg/transport/types"
 	"github.com/stacklok/toolhive/pkg/workloads/statuses"
 )
 
+// egressLogDrainTimeout is how long to wait for the egress log to be fully collected on stop
+const egressLogDrainTimeout = 2 * time.Second
+
 // Runner is responsible for running an MCP server with the provided configuration
 type Runner struct {
 	// Config is the configuration for the runner
@@ -62,6 +69,9 @@ type Runner struct {
 	// It is cancelled during Cleanup() to stop monitoring
 	monitoringCtx    context.Context
 	monitoringCancel context.CancelFunc
+
+	// authServer is the embedded OAuth authorization server, if configured
+	authServer *authserver.Server
 }
 
 // statusManagerAdapter adapts statuses.StatusManager to auth.StatusUpdater interface
@@ -122,6 +132,46 @@ func (c *RunConfig) GetPort() int {
 	return c.Port
 }
 
+// sessionStorageOption creates the configured session storage backend and returns
+// a transport option that uses it. Returns nil if the transport keeps sessions in memory.
+func (r *Runner) sessionStorageOption(ctx context.Context) (transport.Option, error) {
+	storageType := r.Config.SessionStorage.Type
+	if storageType == "" || storageType == session.StorageTypeLocal {
+		return nil, nil
+	}
+
+	// stdio proxies hold live connections to a single attached process,
+	// so their sessions cannot be shared between instances.
+	if r.Config.Transport == types.TransportTypeStdio {
+		logger.Warnf("Session storage %q is not supported for the stdio transport, using in-memory sessions", storageType)
+		return nil, nil
+	}
+
+	logger.Infof("Using %s session storage", storageType)
+	storage, err := session.NewStorage(ctx, r.Config.SessionStorage, session.DefaultSessionTTL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session storage: %w", err)
+	}
+	return transport.WithSessionStorage(storage), nil
+}
+
+// authServerOption creates the embedded authorization server and returns a transport
+// option that serves it from the proxy.
+func (r *Runner) authServerOption(ctx context.Context) (transport.Option, error) {
+	// stdio proxies do not expose custom routes
+	if r.Config.Transport == types.TransportTypeStdio {
+		return nil, fmt.Errorf("the embedded authorization server requires the sse or streamable-http transport")
+	}
+
+	authServer, err := authserver.New(ctx, r.Config.EmbeddedAuthServerConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create embedded authorization server: %w", err)
+	}
+	r.authServer = authServer
+	logger.Infof("Embedded authorization server enabled with issuer %s", authServer.Issuer())
+	return transport.WithAuthServer(authServer.Handler(), authServer.Paths()), nil
+}
+
 // Run runs the MCP server with the provided configuration
 //
 //nolint:gocyclo // This function is complex but manageable
@@ -212,8 +262,25 @@ func (r *Runner) Run(ctx context.Context) error {
 	var transportOpts []transport.Option
 	var setupResult *runtime.SetupResult
 
+	// Serve the embedded authorization server from the proxy if configured.
+	// This is done before deploying the workload so that configuration errors fail fast.
+	if r.Config.EmbeddedAuthServerConfig != nil {
+		authServerOpt, err := r.authServerOption(ctx)
+		if err != nil {
+			return err
+		}
+		transportOpts = append(transportOpts, authServerOpt)
+	}
+
 	if r.Config.RemoteURL == "" {
 		// For local workloads, deploy the container using runtime.Setup first
+		deployedAt := time.Now()
+		var observer *learn.Observer
+		if r.Config.IsLearnMode() {
+			// Observe the mounted paths from before the workload starts
+			observer = learn.NewObserver(r.Config.PermissionProfile)
+			defer observer.Close()
+		}
 		result, err := runtime.Setup(
 			ctx,
 			r.Config.Transport,
@@ -241,6 +308,27 @@ func (r *Runner) Run(ctx context.Context) error {
 		if setupResult.TargetURI != "" {
 			transportOpts = append(transportOpts, transport.WithTargetURI(setupResult.TargetURI))
 		}
+
+		// Record the outbound traffic of network-isolated workloads as audit events
+		if r.Config.IsolateNetwork {
+			// Deferred first, so that the learned profile is written once the egress log is drained
+			if r.Config.IsLearnMode() {
+				defer r.saveLearnedProfile(deployedAt, observer)
+			}
+			stopEgressLog := r.collectEgressLog(ctx, setupResult.ContainerName, deployedAt)
+			defer stopEgressLog()
+		}
+	}
+
+	// Use a shared session storage backend if configured
+	if r.Config.SessionStorage != nil {
+		storageOpt, err := r.sessionStorageOption(ctx)
+		if err != nil {
+			return err
+		}
+		if storageOpt != nil {
+			transportOpts = append(transportOpts, storageOpt)
+		}
 	}
 
 	// Create transport with options
@@ -470,7 +558,7 @@ func (r *Runner) Run(ctx context.Context) error {
 		// Check if workload still exists (using status manager and runtime)
 		// If it doesn't exist, it was removed - clean up client config
 		// If it exists, it exited unexpectedly - signal restart needed
-		exists, checkErr := r.doesWorkloadExist(ctx, r.Config.BaseName)
+		exists, exitCode, checkErr := r.doesWorkloadExist(ctx, r.Config.BaseName)
 		if checkErr != nil {
 			logger.Warnf("Warning: Failed to check if workload exists: %v", checkErr)
 			// Assume restart needed if we can't check
@@ -480,51 +568,127 @@ func (r *Runner) Run(ctx context.Context) error {
 				"Workload %s no longer exists. Removing from client configurations.",
 				r.Config.BaseName,
 			)
-			clientManager, clientErr := client.NewManager(ctx)
-			if clientErr == nil {
-				removeErr := clientManager.RemoveServerFromClients(
-					ctx,
-					r.Config.ContainerName,
-					r.Config.Group,
-				)
-				if removeErr != nil {
-					logger.Warnf("Warning: Failed to remove from client config: %v", removeErr)
-				} else {
-					logger.Infof(
-						"Successfully removed %s from client configurations",
-						r.Config.ContainerName,
-					)
-				}
-			}
+			r.removeFromClients(ctx)
 			logger.Infof("MCP server %s stopped and cleaned up", r.Config.ContainerName)
 			return nil // Exit gracefully, no restart
 		}
 
-		// Workload still exists - signal restart needed
+		// Workload still exists - signal restart needed. Remove it from client
+		// configurations so clients notice the restart.
 		logger.Infof("MCP server %s stopped, restart needed", r.Config.ContainerName)
-		return fmt.Errorf("container exited, restart needed")
+		r.removeFromClients(ctx)
+		return &ContainerExitError{ExitCode: exitCode}
 	}
 
 	return nil
 }
 
+// collectEgressLog appends the requests recorded by the egress proxy of the workload
+// to its egress log in the background. It returns a function that stops the collection
+// once the requests logged until the egress proxy stopped have been collected.
+func (r *Runner) collectEgressLog(ctx context.Context, containerName string, since time.Time) func() {
+	streamer, ok := r.Config.Deployer.(rt.EgressLogStreamer)
+	if !ok {
+		logger.Debugf("Runtime does not support egress proxy logs, not recording outbound traffic")
+		return func() {}
+	}
+
+	// The collection outlives the context of the workload, so that it can drain the log on stop
+	ctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	logs, err := streamer.StreamEgressLog(ctx, containerName, since)
+	if err != nil {
+		logger.Warnf("Failed to read egress proxy logs of %s: %v", containerName, err)
+		return cancel
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer func() {
+			if err := logs.Close(); err != nil {
+				logger.Debugf("Failed to close egress proxy logs: %v", err)
+			}
+		}()
+		if err := egress.CollectToFile(ctx, r.Config.BaseName, logs); err != nil {
+			logger.Warnf("Failed to record outbound traffic of %s: %v", r.Config.BaseName, err)
+		}
+	}()
+	return func() {
+		// The log ends when the egress proxy stops, which may not happen if the workload exited
+		select {
+		case <-done:
+		case <-time.After(egressLogDrainTimeout):
+		}
+		cancel()
+	}
+}
+
+// saveLearnedProfile writes the least-privilege permission profile learned from what the
+// workload did since it was deployed, and logs how it differs from the base profile.
+func (r *Runner) saveLearnedProfile(since time.Time, observer *learn.Observer) {
+	observer.Close()
+
+	records, err := egress.ReadWorkload(r.Config.BaseName, egress.Filter{Since: since})
+	if err != nil {
+		logger.Warnf("Failed to read outbound traffic of %s, the learned profile allows none: %v", r.Config.BaseName, err)
+	}
+
+	profile, unobserved := learn.Generate(r.Config.PermissionProfile, since, records, observer)
+	path, err := learn.WriteProfile(r.Config.BaseName, profile)
+	if err != nil {
+		logger.Errorf("Failed to write learned permission profile of %s: %v", r.Config.BaseName, err)
+		return
+	}
+	logger.Infof("Learned permission profile of %s written to %s, use it with --permission-profile %s",
+		r.Config.BaseName, path, path)
+	for _, mount := range unobserved {
+		logger.Warnf("No access to mount %s was observed; it is kept read-only, remove it if the server does not need it",
+			mount)
+	}
+
+	changes := learn.Diff(r.Config.LearnBaseProfile, profile)
+	if len(changes) == 0 {
+		logger.Infof("Learned permission profile is the same as the base profile")
+		return
+	}
+	logger.Infof("Learned permission profile compared to the base profile:")
+	for _, change := range changes {
+		logger.Infof("  %s", change)
+	}
+}
+
+// removeFromClients removes the workload from the configurations of all registered clients.
+func (r *Runner) removeFromClients(ctx context.Context) {
+	clientManager, err := client.NewManager(ctx)
+	if err != nil {
+		logger.Warnf("Warning: Failed to create client manager: %v", err)
+		return
+	}
+	if err := clientManager.RemoveServerFromClients(ctx, r.Config.ContainerName, r.Config.Group); err != nil {
+		logger.Warnf("Warning: Failed to remove from client config: %v", err)
+		return
+	}
+	logger.Infof("Successfully removed %s from client configurations", r.Config.ContainerName)
+}
+
 // doesWorkloadExist checks if a workload exists in the status manager and runtime.
 // For remote workloads, it trusts the status manager.
 // For container workloads, it verifies the container exists in the runtime.
-func (r *Runner) doesWorkloadExist(ctx context.Context, workloadName string) (bool, error) {
+// It also returns the exit code of the container, or -1 if it is unknown.
+func (r *Runner) doesWorkloadExist(ctx context.Context, workloadName string) (bool, int, error) {
 	// Check if workload exists by trying to get it from status manager
 	workload, err := r.statusManager.GetWorkload(ctx, workloadName)
 	if err != nil {
 		if errors.Is(err, rt.ErrWorkloadNotFound) {
-			return false, nil
+			return false, -1, nil
 		}
-		return false, fmt.Errorf("failed to check if workload exists: %w", err)
+		return false, -1, fmt.Errorf("failed to check if workload exists: %w", err)
 	}
 
 	// If remote workload, check if it should exist
 	if workload.Remote {
 		// For remote workloads, trust the status manager
-		return workload.Status != rt.WorkloadStatusError, nil
+		return workload.Status != rt.WorkloadStatusError, -1, nil
 	}
 
 	// For container workloads, verify the container actually exists in the runtime
@@ -533,20 +697,24 @@ func (r *Runner) doesWorkloadExist(ctx context.Context, workloadName string) (bo
 	if err != nil {
 		logger.Warnf("Failed to create runtime to check container existence: %v", err)
 		// Fall back to status manager only
-		return workload.Status != rt.WorkloadStatusError, nil
+		return workload.Status != rt.WorkloadStatusError, -1, nil
 	}
 
 	// Check if container exists in the runtime (not just running)
 	// GetWorkloadInfo will return an error if the container doesn't exist
-	_, err = backend.GetWorkloadInfo(ctx, workloadName)
+	info, err := backend.GetWorkloadInfo(ctx, workloadName)
 	if err != nil {
 		// Container doesn't exist
 		logger.Debugf("Container %s not found in runtime: %v", workloadName, err)
-		return false, nil
+		return false, -1, nil
 	}
 
-	// Container exists (may be running or stopped)
-	return true, nil
+	// Container exists (may be running or stopped). The exit code of a running
+	// container, e.g. one restarted outside ToolHive, is meaningless.
+	if info.IsRunning() {
+		return true, -1, nil
+	}
+	return true, info.ExitCode, nil
 }
 
 // handleRemoteAuthentication handles authentication for remote MCP servers
@@ -596,6 +764,14 @@ func (r *Runner) Cleanup(ctx context.Context) error {
 		r.monitoringCancel = nil
 	}
 
+	if r.authServer != nil {
+		if err := r.authServer.Close(); err != nil {
+			logger.Warnf("Failed to close embedded authorization server: %v", err)
+			lastErr = err
+		}
+		r.authServer = nil
+	}
+
 	return lastErr
 }
 
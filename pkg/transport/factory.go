@@ -3,7 +3,11 @@
 package transport
 
 import (
+	"fmt"
+	"net/http"
+
 	"github.com/stacklok/toolhive/pkg/transport/errors"
+	"github.com/stacklok/toolhive/pkg/transport/session"
 	"github.com/stacklok/toolhive/pkg/transport/types"
 )
 
@@ -38,6 +42,33 @@ func WithTargetURI(targetURI string) Option {
 	}
 }
 
+// WithSessionStorage returns an option that sets the session storage backend on a transport.
+// Only HTTP-based transports (SSE and streamable HTTP) support custom session storage.
+func WithSessionStorage(storage session.Storage) Option {
+	return func(t types.Transport) error {
+		setter, ok := t.(interface{ setSessionStorage(session.Storage) })
+		if !ok {
+			return fmt.Errorf("transport does not support custom session storage")
+		}
+		setter.setSessionStorage(storage)
+		return nil
+	}
+}
+
+// WithAuthServer returns an option that serves an embedded OAuth authorization server
+// from the proxy. The handler is mounted at the given paths, bypassing the middlewares.
+// Only HTTP-based transports (SSE and streamable HTTP) can serve an authorization server.
+func WithAuthServer(handler http.Handler, paths []string) Option {
+	return func(t types.Transport) error {
+		setter, ok := t.(interface{ setAuthServer(http.Handler, []string) })
+		if !ok {
+			return fmt.Errorf("transport does not support an embedded authorization server")
+		}
+		setter.setAuthServer(handler, paths)
+		return nil
+	}
+}
+
 // Create creates a transport based on the provided configuration
 func (*Factory) Create(config types.Config, opts ...Option) (types.Transport, error) {
 	var tr types.Transport
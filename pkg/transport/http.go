@@ -17,6 +17,7 @@ import (
 	transporterrors "github.com/stacklok/toolhive/pkg/transport/errors"
 	"github.com/stacklok/toolhive/pkg/transport/middleware"
 	"github.com/stacklok/toolhive/pkg/transport/proxy/transparent"
+	"github.com/stacklok/toolhive/pkg/transport/session"
 	"github.com/stacklok/toolhive/pkg/transport/types"
 )
 
@@ -54,6 +55,13 @@ type HTTPTransport struct {
 	// tokenSource is the OAuth token source for remote authentication
 	tokenSource oauth2.TokenSource
 
+	// sessionStorage is an optional storage backend for proxy sessions
+	sessionStorage session.Storage
+
+	// authServerHandler and authServerPaths serve an optional embedded OAuth authorization server
+	authServerHandler http.Handler
+	authServerPaths   []string
+
 	// onHealthCheckFailed is called when a health check fails for remote servers
 	onHealthCheckFailed types.HealthCheckFailedCallback
 
@@ -191,6 +199,19 @@ func (t *HTTPTransport) setContainerName(containerName string) {
 	t.containerName = containerName
 }
 
+// setSessionStorage configures the transport with a storage backend for proxy sessions.
+// This is an unexported method used by the option pattern.
+func (t *HTTPTransport) setSessionStorage(storage session.Storage) {
+	t.sessionStorage = storage
+}
+
+// setAuthServer configures the transport to serve an embedded authorization server.
+// This is an unexported method used by the option pattern.
+func (t *HTTPTransport) setAuthServer(handler http.Handler, paths []string) {
+	t.authServerHandler = handler
+	t.authServerPaths = paths
+}
+
 // setTargetURI configures the transport with the target URI for proxying.
 // This is an unexported method used by the option pattern.
 func (t *HTTPTransport) setTargetURI(targetURI string) {
@@ -255,8 +276,16 @@ func (t *HTTPTransport) Start(ctx context.Context) error {
 		})
 	}
 
+	var proxyOpts []transparent.Option
+	if t.sessionStorage != nil {
+		proxyOpts = append(proxyOpts, transparent.WithSessionStorage(t.sessionStorage))
+	}
+	if t.authServerHandler != nil {
+		proxyOpts = append(proxyOpts, transparent.WithAuthServerHandler(t.authServerHandler, t.authServerPaths))
+	}
+
 	// Create the transparent proxy
-	t.proxy = transparent.NewTransparentProxy(
+	t.proxy = transparent.NewTransparentProxyWithOptions(
 		t.host,
 		t.proxyPort,
 		targetURI,
@@ -269,7 +298,8 @@ func (t *HTTPTransport) Start(ctx context.Context) error {
 		t.onUnauthorizedResponse,
 		t.endpointPrefix,
 		t.trustProxyHeaders,
-		middlewares...)
+		middlewares,
+		proxyOpts...)
 	if err := t.proxy.Start(ctx); err != nil {
 		return err
 	}
@@ -67,6 +67,13 @@ type TransparentProxy struct {
 	// Sessions for tracking state
 	sessionManager *session.Manager
 
+	// Optional storage backend for sessions (default: in-memory)
+	sessionStorage session.Storage
+
+	// Optional embedded OAuth authorization server handler and the paths it serves
+	authServerHandler http.Handler
+	authServerPaths   []string
+
 	// If mcp server has been initialized (atomic access)
 	isServerInitialized atomic.Bool
 
@@ -116,6 +123,25 @@ const (
 // Option is a functional option for configuring TransparentProxy
 type Option func(*TransparentProxy)
 
+// WithSessionStorage stores sessions in the given storage backend instead of process memory.
+// Use a networked backend to share sessions between proxy replicas.
+// The proxy takes ownership of the storage and closes it on shutdown.
+func WithSessionStorage(storage session.Storage) Option {
+	return func(p *TransparentProxy) {
+		p.sessionStorage = storage
+	}
+}
+
+// WithAuthServerHandler serves the endpoints of an embedded OAuth authorization server
+// at the given paths, without middlewares. The paths take precedence over the
+// proxied MCP server and the RFC 9728 discovery endpoints.
+func WithAuthServerHandler(handler http.Handler, paths []string) Option {
+	return func(p *TransparentProxy) {
+		p.authServerHandler = handler
+		p.authServerPaths = paths
+	}
+}
+
 // withHealthCheckInterval sets the health check interval.
 // This is primarily useful for testing with shorter intervals.
 // Ignores non-positive intervals; default will be used.
@@ -169,7 +195,7 @@ func NewTransparentProxy(
 	trustProxyHeaders bool,
 	middlewares ...types.NamedMiddleware,
 ) *TransparentProxy {
-	return newTransparentProxyWithOptions(
+	return NewTransparentProxyWithOptions(
 		host,
 		port,
 		targetURI,
@@ -186,8 +212,8 @@ func NewTransparentProxy(
 	)
 }
 
-// newTransparentProxyWithOptions creates a new transparent proxy with optional configuration.
-func newTransparentProxyWithOptions(
+// NewTransparentProxyWithOptions creates a new transparent proxy with optional configuration.
+func NewTransparentProxyWithOptions(
 	host string,
 	port int,
 	targetURI string,
@@ -211,7 +237,6 @@ func newTransparentProxyWithOptions(
 		shutdownCh:             make(chan struct{}),
 		prometheusHandler:      prometheusHandler,
 		authInfoHandler:        authInfoHandler,
-		sessionManager:         session.NewManager(session.DefaultSessionTTL, session.NewProxySession),
 		isRemote:               isRemote,
 		transportType:          transportType,
 		onHealthCheckFailed:    onHealthCheckFailed,
@@ -228,6 +253,13 @@ func newTransparentProxyWithOptions(
 		opt(proxy)
 	}
 
+	if proxy.sessionStorage != nil {
+		factory := func(id string) session.Session { return session.NewProxySession(id) }
+		proxy.sessionManager = session.NewManagerWithStorage(session.DefaultSessionTTL, factory, proxy.sessionStorage)
+	} else {
+		proxy.sessionManager = session.NewManager(session.DefaultSessionTTL, session.NewProxySession)
+	}
+
 	// Create appropriate response processor based on transport type
 	proxy.responseProcessor = createResponseProcessor(
 		transportType,
@@ -451,6 +483,14 @@ func (p *TransparentProxy) Start(ctx context.Context) error {
 		logger.Info("RFC 9728 OAuth discovery endpoints enabled at /.well-known/ (no middlewares)")
 	}
 
+	// Add embedded authorization server endpoints if configured (no middlewares)
+	if p.authServerHandler != nil {
+		for _, path := range p.authServerPaths {
+			mux.Handle(path, p.authServerHandler)
+		}
+		logger.Info("Embedded OAuth authorization server endpoints enabled (no middlewares)")
+	}
+
 	ln, err := net.Listen("tcp", fmt.Sprintf("%s:%d", p.host, p.port))
 	if err != nil {
 		return fmt.Errorf("failed to listen: %w", err)
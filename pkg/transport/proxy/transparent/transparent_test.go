@@ -3,6 +3,7 @@ package transparent
 import (
 	"bufio"
 	"context"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"net/http/httputil"
@@ -355,6 +356,58 @@ func TestWellKnownPathWithoutAuthHandler(t *testing.T) {
 		"Without auth handler, well-known path should return 404")
 }
 
+func TestTransparentProxy_AuthServerHandler(t *testing.T) {
+	t.Parallel()
+
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte("target"))
+	}))
+	defer target.Close()
+
+	authServer := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte("authserver"))
+	})
+
+	// The middleware rejects every request; authorization server endpoints must bypass it
+	denyAll := types.NamedMiddleware{
+		Name: "deny-all",
+		Function: func(http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusUnauthorized)
+			})
+		},
+	}
+
+	proxy := NewTransparentProxyWithOptions(
+		"127.0.0.1", 0, target.URL, nil, nil, false, false, "streamable-http", nil, nil, "", false,
+		[]types.NamedMiddleware{denyAll},
+		WithAuthServerHandler(authServer, []string{"/oauth/token", "/.well-known/oauth-authorization-server"}),
+	)
+	ctx := context.Background()
+	require.NoError(t, proxy.Start(ctx))
+	defer proxy.Stop(ctx)
+
+	baseURL := "http://" + proxy.listener.Addr().String()
+	tests := []struct {
+		path           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{path: "/oauth/token", expectedStatus: http.StatusOK, expectedBody: "authserver"},
+		{path: "/.well-known/oauth-authorization-server", expectedStatus: http.StatusOK, expectedBody: "authserver"},
+		{path: "/mcp", expectedStatus: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		resp, err := http.Get(baseURL + tt.path)
+		require.NoError(t, err)
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		require.NoError(t, err)
+		assert.Equal(t, tt.expectedStatus, resp.StatusCode, tt.path)
+		assert.Equal(t, tt.expectedBody, string(body), tt.path)
+	}
+}
+
 // TestTransparentProxy_IdempotentStop tests that Stop() can be called multiple times safely
 func TestTransparentProxy_IdempotentStop(t *testing.T) {
 	t.Parallel()
@@ -981,7 +1034,7 @@ func setupRemoteProxyTest(t *testing.T, serverURL string, callback types.HealthC
 func setupRemoteProxyTestWithTimeout(t *testing.T, serverURL string, callback types.HealthCheckFailedCallback, timeout time.Duration) (*TransparentProxy, context.Context, context.CancelFunc) {
 	t.Helper()
 
-	proxy := newTransparentProxyWithOptions(
+	proxy := NewTransparentProxyWithOptions(
 		"127.0.0.1",
 		0,
 		serverURL,
@@ -4,6 +4,7 @@ package session
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/stacklok/toolhive/pkg/logger"
@@ -30,8 +31,15 @@ type Manager struct {
 	ttl     time.Duration
 	stopCh  chan struct{}
 	factory Factory
+
+	expirationMu       sync.RWMutex
+	expirationHandlers []ExpirationHandler
 }
 
+// ExpirationHandler is called with the ID of each session removed by TTL cleanup.
+// Handlers run on the cleanup goroutine and should not block for long.
+type ExpirationHandler func(id string)
+
 // Factory defines a function type for creating new sessions.
 // It now returns the Session interface to support different session types.
 type Factory func(id string) Session
@@ -110,18 +118,25 @@ func (m *Manager) cleanupRoutine() {
 	for {
 		select {
 		case <-ticker.C:
-			cutoff := time.Now().Add(-m.ttl)
-			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-			if err := m.storage.DeleteExpired(ctx, cutoff); err != nil {
+			if err := m.cleanupExpiredOnce(); err != nil {
 				logger.Errorf("Failed to delete expired sessions: %v", err)
 			}
-			cancel()
 		case <-m.stopCh:
 			return
 		}
 	}
 }
 
+// OnExpire registers a handler that is called for every session removed by TTL cleanup.
+// This lets components that keep per-session state elsewhere release it when the
+// session expires. Storage backends that expire sessions on their own, such as
+// RedisStorage, do not report expirations, so handlers are not called for them.
+func (m *Manager) OnExpire(handler ExpirationHandler) {
+	m.expirationMu.Lock()
+	defer m.expirationMu.Unlock()
+	m.expirationHandlers = append(m.expirationHandlers, handler)
+}
+
 // AddWithID creates (and adds) a new session with the provided ID.
 // Returns error if ID is empty or already exists.
 func (m *Manager) AddWithID(id string) error {
@@ -174,6 +189,14 @@ func (m *Manager) Get(id string) (Session, bool) {
 	}
 	// Touch the session to update its timestamp
 	sess.Touch()
+
+	// Networked storage backends return a copy of the session, so the new
+	// timestamp must be stored to extend the session's expiry.
+	if _, isLocal := m.storage.(*LocalStorage); !isLocal {
+		if err := m.storage.Store(ctx, sess); err != nil {
+			logger.Warnf("Failed to refresh session %s: %v", id, err)
+		}
+	}
 	return sess, true
 }
 
@@ -231,5 +254,23 @@ func (m *Manager) cleanupExpiredOnce() error {
 	cutoff := time.Now().Add(-m.ttl)
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
-	return m.storage.DeleteExpired(ctx, cutoff)
+
+	expired, err := m.storage.DeleteExpired(ctx, cutoff)
+	if len(expired) > 0 {
+		m.notifyExpired(expired)
+	}
+	return err
+}
+
+// notifyExpired calls the registered expiration handlers for each expired session.
+func (m *Manager) notifyExpired(ids []string) {
+	m.expirationMu.RLock()
+	handlers := m.expirationHandlers
+	m.expirationMu.RUnlock()
+
+	for _, id := range ids {
+		for _, handler := range handlers {
+			handler(id)
+		}
+	}
 }
@@ -124,6 +124,36 @@ func TestCleanupExpired_ManualTrigger(t *testing.T) {
 	assert.True(t, okNew, "new session should still exist after cleanup")
 }
 
+func TestOnExpireCalledForExpiredSessions(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	factory := &stubFactory{fixedTime: now}
+	ttl := time.Hour
+
+	m := NewManager(ttl, factory.New)
+	defer m.Stop()
+
+	var expired []string
+	m.OnExpire(func(id string) {
+		expired = append(expired, id)
+	})
+
+	require.NoError(t, m.AddWithID("old"))
+	require.NoError(t, m.AddWithID("new"))
+
+	sess, ok := m.Get("old")
+	require.True(t, ok)
+	sess.(*ProxySession).updated = now.Add(-ttl * 2)
+
+	require.NoError(t, m.cleanupExpiredOnce())
+	assert.Equal(t, []string{"old"}, expired)
+
+	// Nothing left to expire
+	require.NoError(t, m.cleanupExpiredOnce())
+	assert.Equal(t, []string{"old"}, expired)
+}
+
 func TestStopDisablesCleanup(t *testing.T) {
 	t.Parallel()
 	ttl := 50 * time.Millisecond
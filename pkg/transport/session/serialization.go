@@ -6,12 +6,11 @@ import (
 	"time"
 )
 
-// The following serialization functions are prepared for Phase 4 (Redis/Valkey implementation)
-// They are currently unused but will be needed when implementing distributed storage backends.
+// The following serialization functions are used by networked storage backends
+// such as RedisStorage, which cannot hold session objects directly.
 
 // sessionData is the JSON representation of a session.
 // This structure is used for serializing sessions to/from storage backends.
-// nolint:unused // Will be used in Phase 4 for Redis/Valkey storage
 type sessionData struct {
 	ID        string            `json:"id"`
 	Type      SessionType       `json:"type"`
@@ -22,7 +21,6 @@ type sessionData struct {
 }
 
 // serializeSession converts a Session to its JSON representation.
-// nolint:unused // Will be used in Phase 4 for Redis/Valkey storage
 func serializeSession(s Session) ([]byte, error) {
 	if s == nil {
 		return nil, fmt.Errorf("cannot serialize nil session")
@@ -50,7 +48,6 @@ func serializeSession(s Session) ([]byte, error) {
 
 // deserializeSession reconstructs a Session from its JSON representation.
 // It creates the appropriate session type based on the Type field.
-// nolint:unused // Will be used in Phase 4 for Redis/Valkey storage
 func deserializeSession(data []byte) (Session, error) {
 	if len(data) == 0 {
 		return nil, fmt.Errorf("cannot deserialize empty data")
@@ -26,7 +26,8 @@ type Storage interface {
 
 	// DeleteExpired removes all sessions that haven't been updated since the given time.
 	// This is used by the cleanup routine to remove stale sessions.
-	DeleteExpired(ctx context.Context, before time.Time) error
+	// Returns the IDs of the sessions that were removed.
+	DeleteExpired(ctx context.Context, before time.Time) ([]string, error)
 
 	// Close performs cleanup of the storage backend.
 	// For local storage, this clears all sessions. For remote storage, it closes connections.
@@ -0,0 +1,82 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+)
+
+const (
+	// StorageTypeLocal keeps sessions in process memory.
+	StorageTypeLocal = "local"
+
+	// StorageTypeRedis keeps sessions in a Redis or Valkey server.
+	StorageTypeRedis = "redis"
+)
+
+// StorageConfig selects and configures a session storage backend.
+type StorageConfig struct {
+	// Type is the storage backend: "local" (default) or "redis".
+	Type string `json:"type,omitempty" yaml:"type,omitempty"`
+
+	// Redis configures the Redis/Valkey backend when Type is "redis".
+	Redis *RedisConfig `json:"redis,omitempty" yaml:"redis,omitempty"`
+}
+
+// RedisConfig configures a Redis/Valkey session storage backend.
+type RedisConfig struct {
+	// Address is the Redis server address (host:port).
+	Address string `json:"address" yaml:"address"`
+
+	// DB is the Redis database number.
+	DB int `json:"db,omitempty" yaml:"db,omitempty"`
+
+	// KeyPrefix is prepended to every session key. Defaults to DefaultRedisKeyPrefix.
+	KeyPrefix string `json:"key_prefix,omitempty" yaml:"key_prefix,omitempty"`
+
+	// PasswordEnv is the name of the environment variable containing the Redis password.
+	PasswordEnv string `json:"password_env,omitempty" yaml:"password_env,omitempty"`
+
+	// TLS enables TLS for the Redis connection.
+	TLS bool `json:"tls,omitempty" yaml:"tls,omitempty"`
+}
+
+// Validate checks that the storage configuration is complete.
+func (c *StorageConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+
+	switch c.Type {
+	case "", StorageTypeLocal:
+		return nil
+	case StorageTypeRedis:
+		if c.Redis == nil || c.Redis.Address == "" {
+			return fmt.Errorf("redis address is required for %q session storage", StorageTypeRedis)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported session storage type: %s", c.Type)
+	}
+}
+
+// NewStorage creates the storage backend described by cfg.
+// A nil configuration selects local storage. Sessions in networked backends expire after ttl.
+func NewStorage(ctx context.Context, cfg *StorageConfig, ttl time.Duration) (Storage, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if cfg == nil || cfg.Type == "" || cfg.Type == StorageTypeLocal {
+		return NewLocalStorage(), nil
+	}
+
+	var password string
+	if cfg.Redis.PasswordEnv != "" {
+		password = os.Getenv(cfg.Redis.PasswordEnv)
+		if password == "" {
+			return nil, fmt.Errorf("environment variable %s not set or empty", cfg.Redis.PasswordEnv)
+		}
+	}
+	return NewRedisStorage(ctx, cfg.Redis, password, ttl)
+}
@@ -62,7 +62,7 @@ func (s *LocalStorage) Delete(_ context.Context, id string) error {
 }
 
 // DeleteExpired removes all sessions that haven't been updated since the given time.
-func (s *LocalStorage) DeleteExpired(ctx context.Context, before time.Time) error {
+func (s *LocalStorage) DeleteExpired(ctx context.Context, before time.Time) ([]string, error) {
 	var toDelete []string
 
 	// First pass: collect IDs of expired sessions
@@ -89,7 +89,7 @@ func (s *LocalStorage) DeleteExpired(ctx context.Context, before time.Time) erro
 		s.sessions.Delete(id)
 	}
 
-	return nil
+	return toDelete, nil
 }
 
 // Close clears all sessions from local storage.
@@ -0,0 +1,129 @@
+package session
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultRedisKeyPrefix is the default prefix for session keys in Redis.
+const DefaultRedisKeyPrefix = "toolhive:sessions:"
+
+// RedisStorage implements the Storage interface using Redis or Valkey.
+// Sessions are serialized to JSON and shared by every proxy instance that uses
+// the same server, so a session created by one instance is known to all of them.
+//
+// Expiry is delegated to Redis: every Store sets the key's TTL, so idle sessions
+// are removed by the server rather than by DeleteExpired scans.
+type RedisStorage struct {
+	client    *redis.Client
+	keyPrefix string
+	ttl       time.Duration
+}
+
+// NewRedisStorage connects to a Redis server and returns a storage backend using it.
+// Sessions expire after ttl without being stored again. The connection is verified before returning.
+func NewRedisStorage(ctx context.Context, cfg *RedisConfig, password string, ttl time.Duration) (*RedisStorage, error) {
+	if cfg == nil || cfg.Address == "" {
+		return nil, fmt.Errorf("redis address is required")
+	}
+	if ttl <= 0 {
+		return nil, fmt.Errorf("session TTL must be positive")
+	}
+
+	opts := &redis.Options{
+		Addr:     cfg.Address,
+		Password: password,
+		DB:       cfg.DB,
+	}
+	if cfg.TLS {
+		opts.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+
+	client := redis.NewClient(opts)
+	if err := client.Ping(ctx).Err(); err != nil {
+		_ = client.Close()
+		return nil, fmt.Errorf("failed to connect to redis at %s: %w", cfg.Address, err)
+	}
+
+	keyPrefix := cfg.KeyPrefix
+	if keyPrefix == "" {
+		keyPrefix = DefaultRedisKeyPrefix
+	}
+
+	return &RedisStorage{
+		client:    client,
+		keyPrefix: keyPrefix,
+		ttl:       ttl,
+	}, nil
+}
+
+// Store serializes a session to Redis and resets its expiry.
+func (s *RedisStorage) Store(ctx context.Context, session Session) error {
+	if session == nil {
+		return fmt.Errorf("cannot store nil session")
+	}
+	if session.ID() == "" {
+		return fmt.Errorf("cannot store session with empty ID")
+	}
+
+	data, err := serializeSession(session)
+	if err != nil {
+		return fmt.Errorf("failed to serialize session: %w", err)
+	}
+
+	if err := s.client.Set(ctx, s.keyPrefix+session.ID(), data, s.ttl).Err(); err != nil {
+		return fmt.Errorf("failed to store session in redis: %w", err)
+	}
+	return nil
+}
+
+// Load retrieves a session from Redis.
+// The returned session is a copy; changes must be stored again to be persisted.
+func (s *RedisStorage) Load(ctx context.Context, id string) (Session, error) {
+	if id == "" {
+		return nil, fmt.Errorf("cannot load session with empty ID")
+	}
+
+	data, err := s.client.Get(ctx, s.keyPrefix+id).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrSessionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load session from redis: %w", err)
+	}
+
+	session, err := deserializeSession(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to deserialize session: %w", err)
+	}
+	return session, nil
+}
+
+// Delete removes a session from Redis.
+func (s *RedisStorage) Delete(ctx context.Context, id string) error {
+	if id == "" {
+		return fmt.Errorf("cannot delete session with empty ID")
+	}
+
+	if err := s.client.Del(ctx, s.keyPrefix+id).Err(); err != nil {
+		return fmt.Errorf("failed to delete session from redis: %w", err)
+	}
+	return nil
+}
+
+// DeleteExpired is a no-op for Redis storage.
+// Sessions expire through their Redis TTL, so there is nothing to scan for and
+// no expired session IDs are reported.
+func (*RedisStorage) DeleteExpired(_ context.Context, _ time.Time) ([]string, error) {
+	return nil, nil
+}
+
+// Close closes the Redis connection. Stored sessions are left in place for other instances.
+func (s *RedisStorage) Close() error {
+	return s.client.Close()
+}
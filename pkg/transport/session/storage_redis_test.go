@@ -0,0 +1,171 @@
+package session
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRedisStorage(t *testing.T, ttl time.Duration) (*RedisStorage, *miniredis.Miniredis) {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	storage, err := NewRedisStorage(context.Background(), &RedisConfig{Address: server.Addr()}, "", ttl)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = storage.Close() })
+	return storage, server
+}
+
+func TestRedisStorage_StoreLoadDelete(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	storage, server := newTestRedisStorage(t, time.Hour)
+
+	session := NewStreamableSession("redis-session")
+	session.SetMetadata("key1", "value1")
+	require.NoError(t, storage.Store(ctx, session))
+
+	// Keys are namespaced and expire with the session TTL
+	assert.True(t, server.Exists(DefaultRedisKeyPrefix+"redis-session"))
+	assert.Equal(t, time.Hour, server.TTL(DefaultRedisKeyPrefix+"redis-session"))
+
+	loaded, err := storage.Load(ctx, "redis-session")
+	require.NoError(t, err)
+	assert.Equal(t, "redis-session", loaded.ID())
+	assert.Equal(t, SessionTypeStreamable, loaded.Type())
+	assert.Equal(t, "value1", loaded.GetMetadata()["key1"])
+
+	require.NoError(t, storage.Delete(ctx, "redis-session"))
+	_, err = storage.Load(ctx, "redis-session")
+	assert.ErrorIs(t, err, ErrSessionNotFound)
+
+	// Deleting a missing session is not an error
+	assert.NoError(t, storage.Delete(ctx, "redis-session"))
+
+	assert.Error(t, storage.Store(ctx, nil))
+	assert.Error(t, storage.Store(ctx, &ProxySession{}))
+	_, err = storage.Load(ctx, "")
+	assert.Error(t, err)
+}
+
+func TestRedisStorage_Expiry(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	storage, server := newTestRedisStorage(t, time.Minute)
+
+	require.NoError(t, storage.Store(ctx, NewProxySession("expiring")))
+
+	// DeleteExpired does not scan; Redis removes the key when its TTL elapses
+	ids, err := storage.DeleteExpired(ctx, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.Empty(t, ids)
+	_, err = storage.Load(ctx, "expiring")
+	require.NoError(t, err)
+
+	server.FastForward(2 * time.Minute)
+	_, err = storage.Load(ctx, "expiring")
+	assert.ErrorIs(t, err, ErrSessionNotFound)
+}
+
+func TestRedisStorage_SharedBetweenInstances(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	first, server := newTestRedisStorage(t, time.Hour)
+	second, err := NewRedisStorage(ctx, &RedisConfig{Address: server.Addr()}, "", time.Hour)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = second.Close() })
+
+	require.NoError(t, first.Store(ctx, NewProxySession("shared")))
+
+	loaded, err := second.Load(ctx, "shared")
+	require.NoError(t, err)
+	assert.Equal(t, "shared", loaded.ID())
+}
+
+func TestRedisStorage_ManagerGetRefreshesTTL(t *testing.T) {
+	t.Parallel()
+
+	storage, server := newTestRedisStorage(t, time.Minute)
+	manager := NewManagerWithStorage(time.Minute, func(id string) Session { return NewProxySession(id) }, storage)
+	t.Cleanup(func() { _ = manager.Stop() })
+
+	require.NoError(t, manager.AddWithID("refreshed"))
+
+	// Accessing the session before it expires resets its TTL
+	server.FastForward(45 * time.Second)
+	_, ok := manager.Get("refreshed")
+	require.True(t, ok)
+	assert.Equal(t, time.Minute, server.TTL(DefaultRedisKeyPrefix+"refreshed"))
+
+	server.FastForward(45 * time.Second)
+	_, ok = manager.Get("refreshed")
+	assert.True(t, ok)
+
+	server.FastForward(2 * time.Minute)
+	_, ok = manager.Get("refreshed")
+	assert.False(t, ok)
+}
+
+func TestNewRedisStorage_Errors(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	_, err := NewRedisStorage(ctx, nil, "", time.Minute)
+	assert.Error(t, err)
+
+	server := miniredis.RunT(t)
+	_, err = NewRedisStorage(ctx, &RedisConfig{Address: server.Addr()}, "", 0)
+	assert.Error(t, err)
+
+	server.RequireAuth("secret")
+	_, err = NewRedisStorage(ctx, &RedisConfig{Address: server.Addr()}, "wrong", time.Minute)
+	assert.Error(t, err)
+	storage, err := NewRedisStorage(ctx, &RedisConfig{Address: server.Addr()}, "secret", time.Minute)
+	require.NoError(t, err)
+	_ = storage.Close()
+
+	addr := server.Addr()
+	server.Close()
+	_, err = NewRedisStorage(ctx, &RedisConfig{Address: addr}, "", time.Minute)
+	assert.Error(t, err)
+}
+
+func TestNewStorage(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	storage, err := NewStorage(ctx, nil, time.Minute)
+	require.NoError(t, err)
+	assert.IsType(t, &LocalStorage{}, storage)
+	_ = storage.Close()
+
+	storage, err = NewStorage(ctx, &StorageConfig{Type: StorageTypeLocal}, time.Minute)
+	require.NoError(t, err)
+	assert.IsType(t, &LocalStorage{}, storage)
+	_ = storage.Close()
+
+	_, err = NewStorage(ctx, &StorageConfig{Type: "memcached"}, time.Minute)
+	assert.Error(t, err)
+
+	_, err = NewStorage(ctx, &StorageConfig{Type: StorageTypeRedis}, time.Minute)
+	assert.Error(t, err)
+
+	server := miniredis.RunT(t)
+	storage, err = NewStorage(ctx, &StorageConfig{
+		Type:  StorageTypeRedis,
+		Redis: &RedisConfig{Address: server.Addr(), KeyPrefix: "custom:"},
+	}, time.Minute)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = storage.Close() })
+	require.NoError(t, storage.Store(ctx, NewProxySession("prefixed")))
+	assert.True(t, server.Exists("custom:prefixed"))
+}
@@ -161,8 +161,9 @@ func TestLocalStorage(t *testing.T) {
 
 		// Delete sessions older than 1 hour
 		cutoff := time.Now().Add(-1 * time.Hour)
-		err = storage.DeleteExpired(ctx, cutoff)
+		expired, err := storage.DeleteExpired(ctx, cutoff)
 		require.NoError(t, err)
+		assert.Equal(t, []string{"old-session"}, expired)
 
 		// Old session should be gone
 		_, err = storage.Load(ctx, "old-session")
@@ -295,7 +296,7 @@ func TestLocalStorage(t *testing.T) {
 		cancel()
 
 		// DeleteExpired should handle cancelled context gracefully
-		err := storage.DeleteExpired(ctx, time.Now())
+		_, err := storage.DeleteExpired(ctx, time.Now())
 		// Should not error, just stop early
 		assert.NoError(t, err)
 	})
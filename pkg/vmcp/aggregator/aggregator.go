@@ -98,6 +98,12 @@ type ResolvedCapabilities struct {
 
 	// SupportsSampling is true if any backend supports sampling.
 	SupportsSampling bool
+
+	// ReplicaGroups maps replica group names to the IDs of the member backends
+	// that serve the group's capabilities, in configuration order. The first
+	// member is the group's primary backend. Capabilities served by a replica
+	// group use the group name as their BackendID.
+	ReplicaGroups map[string][]string
 }
 
 // ResolvedTool represents a tool after conflict resolution.
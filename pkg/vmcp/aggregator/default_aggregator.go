@@ -18,6 +18,19 @@ type defaultAggregator struct {
 	backendClient    vmcp.BackendClient
 	conflictResolver ConflictResolver
 	toolConfigMap    map[string]*config.WorkloadToolConfig // Maps backend ID to tool config
+	replicaGroups    []*config.ReplicaGroupConfig
+}
+
+// Option configures optional behavior of the default aggregator.
+type Option func(*defaultAggregator)
+
+// WithReplicaGroups configures groups of backends that are replicas of the same MCP server.
+// Each group's capabilities are aggregated once under the group name, and the routing
+// table lists every healthy member so requests can be load balanced across them.
+func WithReplicaGroups(groups []*config.ReplicaGroupConfig) Option {
+	return func(a *defaultAggregator) {
+		a.replicaGroups = groups
+	}
 }
 
 // NewDefaultAggregator creates a new default aggregator implementation.
@@ -27,6 +40,7 @@ func NewDefaultAggregator(
 	backendClient vmcp.BackendClient,
 	conflictResolver ConflictResolver,
 	workloadConfigs []*config.WorkloadToolConfig,
+	opts ...Option,
 ) Aggregator {
 	// Build tool config map for quick lookup by backend ID
 	toolConfigMap := make(map[string]*config.WorkloadToolConfig)
@@ -36,11 +50,15 @@ func NewDefaultAggregator(
 		}
 	}
 
-	return &defaultAggregator{
+	a := &defaultAggregator{
 		backendClient:    backendClient,
 		conflictResolver: conflictResolver,
 		toolConfigMap:    toolConfigMap,
 	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
 }
 
 // QueryCapabilities queries a single backend for its MCP capabilities.
@@ -134,6 +152,9 @@ func (a *defaultAggregator) ResolveConflicts(
 ) (*ResolvedCapabilities, error) {
 	logger.Debugf("Resolving conflicts across %d backends", len(capabilities))
 
+	// Collapse replica groups into a single capability set per group
+	capabilities, replicaGroups := a.groupReplicas(capabilities)
+
 	// Group tools by backend for conflict resolution
 	toolsByBackend := make(map[string][]vmcp.Tool)
 	for backendID, caps := range capabilities {
@@ -173,9 +194,10 @@ func (a *defaultAggregator) ResolveConflicts(
 
 	// Build resolved capabilities
 	resolved := &ResolvedCapabilities{
-		Tools:     resolvedTools,
-		Resources: []vmcp.Resource{},
-		Prompts:   []vmcp.Prompt{},
+		Tools:         resolvedTools,
+		Resources:     []vmcp.Resource{},
+		Prompts:       []vmcp.Prompt{},
+		ReplicaGroups: replicaGroups,
 	}
 
 	// Collect resources and prompts (no conflict resolution for these yet)
@@ -220,58 +242,20 @@ func (*defaultAggregator) MergeCapabilities(
 			BackendID:   resolvedTool.BackendID,
 		})
 
-		// Look up full backend information from registry
-		backend := registry.Get(ctx, resolvedTool.BackendID)
-		if backend == nil {
-			logger.Warnf("Backend %s not found in registry for tool %s, creating minimal target",
-				resolvedTool.BackendID, resolvedTool.ResolvedName)
-			routingTable.Tools[resolvedTool.ResolvedName] = &vmcp.BackendTarget{
-				WorkloadID:             resolvedTool.BackendID,
-				OriginalCapabilityName: resolvedTool.OriginalName,
-			}
-		} else {
-			// Use the backendToTarget helper from registry package
-			target := vmcp.BackendToTarget(backend)
-			// Store the original tool name for forwarding to backend
-			target.OriginalCapabilityName = resolvedTool.OriginalName
-			routingTable.Tools[resolvedTool.ResolvedName] = target
-		}
+		routingTable.Tools[resolvedTool.ResolvedName] = resolveTarget(
+			ctx, registry, resolved.ReplicaGroups, resolvedTool.BackendID, resolvedTool.OriginalName, "tool")
 	}
 
 	// Add resources to routing table
 	for _, resource := range resolved.Resources {
-		backend := registry.Get(ctx, resource.BackendID)
-		if backend == nil {
-			logger.Warnf("Backend %s not found in registry for resource %s, creating minimal target",
-				resource.BackendID, resource.URI)
-			routingTable.Resources[resource.URI] = &vmcp.BackendTarget{
-				WorkloadID:             resource.BackendID,
-				OriginalCapabilityName: resource.URI,
-			}
-		} else {
-			target := vmcp.BackendToTarget(backend)
-			// Store the original resource URI for forwarding to backend
-			target.OriginalCapabilityName = resource.URI
-			routingTable.Resources[resource.URI] = target
-		}
+		routingTable.Resources[resource.URI] = resolveTarget(
+			ctx, registry, resolved.ReplicaGroups, resource.BackendID, resource.URI, "resource")
 	}
 
 	// Add prompts to routing table
 	for _, prompt := range resolved.Prompts {
-		backend := registry.Get(ctx, prompt.BackendID)
-		if backend == nil {
-			logger.Warnf("Backend %s not found in registry for prompt %s, creating minimal target",
-				prompt.BackendID, prompt.Name)
-			routingTable.Prompts[prompt.Name] = &vmcp.BackendTarget{
-				WorkloadID:             prompt.BackendID,
-				OriginalCapabilityName: prompt.Name,
-			}
-		} else {
-			target := vmcp.BackendToTarget(backend)
-			// Store the original prompt name for forwarding to backend
-			target.OriginalCapabilityName = prompt.Name
-			routingTable.Prompts[prompt.Name] = target
-		}
+		routingTable.Prompts[prompt.Name] = resolveTarget(
+			ctx, registry, resolved.ReplicaGroups, prompt.BackendID, prompt.Name, "prompt")
 	}
 
 	// Determine conflict strategy used
@@ -0,0 +1,173 @@
+package aggregator
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/stacklok/toolhive/pkg/logger"
+	"github.com/stacklok/toolhive/pkg/vmcp"
+)
+
+// groupReplicas collapses the capabilities of each replica group's members into a single
+// entry keyed by the group name, so the group is aggregated (and conflict-resolved) as one
+// backend. The first member in configuration order that returned capabilities becomes the
+// group's primary. Members whose capabilities differ from the primary's are excluded, since
+// requests routed to them could fail.
+//
+// Returns the rewritten capabilities and the members serving each group.
+func (a *defaultAggregator) groupReplicas(
+	capabilities map[string]*BackendCapabilities,
+) (map[string]*BackendCapabilities, map[string][]string) {
+	if len(a.replicaGroups) == 0 {
+		return capabilities, nil
+	}
+
+	// Backends that belong to a group are never aggregated on their own
+	memberOf := make(map[string]string)
+	for _, group := range a.replicaGroups {
+		for _, workload := range group.Workloads {
+			memberOf[workload] = group.Name
+		}
+	}
+
+	result := make(map[string]*BackendCapabilities, len(capabilities))
+	for backendID, caps := range capabilities {
+		if _, isMember := memberOf[backendID]; !isMember {
+			result[backendID] = caps
+		}
+	}
+
+	groups := make(map[string][]string)
+	for _, group := range a.replicaGroups {
+		if _, exists := result[group.Name]; exists {
+			logger.Warnf("Replica group %s has the same name as a backend, ignoring group members", group.Name)
+			continue
+		}
+
+		var primary *BackendCapabilities
+		var members []string
+		for _, workload := range group.Workloads {
+			caps, ok := capabilities[workload]
+			if !ok {
+				logger.Debugf("Replica %s of group %s returned no capabilities, skipping", workload, group.Name)
+				continue
+			}
+			if primary == nil {
+				primary = caps
+			} else if capabilitySignature(caps) != capabilitySignature(primary) {
+				logger.Warnf("Replica %s of group %s exposes different capabilities than %s, excluding it from the group",
+					workload, group.Name, primary.BackendID)
+				continue
+			}
+			members = append(members, workload)
+		}
+
+		if primary == nil {
+			logger.Warnf("No replicas of group %s returned capabilities", group.Name)
+			continue
+		}
+
+		result[group.Name] = rebindCapabilities(primary, group.Name)
+		groups[group.Name] = members
+		logger.Debugf("Replica group %s served by %d backends: %v", group.Name, len(members), members)
+	}
+
+	return result, groups
+}
+
+// capabilitySignature returns a string identifying the set of capability names a backend exposes.
+func capabilitySignature(caps *BackendCapabilities) string {
+	names := make([]string, 0, len(caps.Tools)+len(caps.Resources)+len(caps.Prompts))
+	for _, tool := range caps.Tools {
+		names = append(names, "tool:"+tool.Name)
+	}
+	for _, resource := range caps.Resources {
+		names = append(names, "resource:"+resource.URI)
+	}
+	for _, prompt := range caps.Prompts {
+		names = append(names, "prompt:"+prompt.Name)
+	}
+	slices.Sort(names)
+	return strings.Join(names, "\n")
+}
+
+// rebindCapabilities returns a copy of caps with every capability attributed to backendID.
+func rebindCapabilities(caps *BackendCapabilities, backendID string) *BackendCapabilities {
+	rebound := &BackendCapabilities{
+		BackendID:        backendID,
+		Tools:            make([]vmcp.Tool, len(caps.Tools)),
+		Resources:        make([]vmcp.Resource, len(caps.Resources)),
+		Prompts:          make([]vmcp.Prompt, len(caps.Prompts)),
+		SupportsLogging:  caps.SupportsLogging,
+		SupportsSampling: caps.SupportsSampling,
+	}
+	for i, tool := range caps.Tools {
+		tool.BackendID = backendID
+		rebound.Tools[i] = tool
+	}
+	for i, resource := range caps.Resources {
+		resource.BackendID = backendID
+		rebound.Resources[i] = resource
+	}
+	for i, prompt := range caps.Prompts {
+		prompt.BackendID = backendID
+		rebound.Prompts[i] = prompt
+	}
+	return rebound
+}
+
+// resolveTarget builds the routing target for a capability served by backendID.
+//
+// If backendID names a replica group, the target is the group's primary member and
+// the remaining members are listed as Replicas. If a backend is missing from the
+// registry, a minimal target carrying only its ID is created.
+func resolveTarget(
+	ctx context.Context,
+	registry vmcp.BackendRegistry,
+	replicaGroups map[string][]string,
+	backendID string,
+	originalName string,
+	kind string,
+) *vmcp.BackendTarget {
+	members, isGroup := replicaGroups[backendID]
+	if !isGroup {
+		return lookupTarget(ctx, registry, backendID, originalName, kind)
+	}
+
+	var primary *vmcp.BackendTarget
+	for _, member := range members {
+		target := lookupTarget(ctx, registry, member, originalName, kind)
+		target.ReplicaGroup = backendID
+		if primary == nil {
+			primary = target
+			continue
+		}
+		primary.Replicas = append(primary.Replicas, target)
+	}
+	return primary
+}
+
+// lookupTarget builds the routing target for a single backend from the registry.
+func lookupTarget(
+	ctx context.Context,
+	registry vmcp.BackendRegistry,
+	backendID string,
+	originalName string,
+	kind string,
+) *vmcp.BackendTarget {
+	backend := registry.Get(ctx, backendID)
+	if backend == nil {
+		logger.Warnf("Backend %s not found in registry for %s %s, creating minimal target",
+			backendID, kind, originalName)
+		return &vmcp.BackendTarget{
+			WorkloadID:             backendID,
+			OriginalCapabilityName: originalName,
+		}
+	}
+
+	// Store the original capability name for forwarding to backend
+	target := vmcp.BackendToTarget(backend)
+	target.OriginalCapabilityName = originalName
+	return target
+}
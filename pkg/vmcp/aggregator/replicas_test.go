@@ -0,0 +1,135 @@
+package aggregator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/stacklok/toolhive/pkg/vmcp"
+	"github.com/stacklok/toolhive/pkg/vmcp/config"
+	"github.com/stacklok/toolhive/pkg/vmcp/mocks"
+)
+
+func TestDefaultAggregator_ReplicaGroups(t *testing.T) {
+	t.Parallel()
+
+	fetchCaps := func(backendID string) *vmcp.CapabilityList {
+		return newTestCapabilityList(
+			withTools(newTestTool("fetch", backendID)),
+			withResources(newTestResource("fetch://status", backendID)),
+			withPrompts(newTestPrompt("summarize", backendID)))
+	}
+
+	t.Run("aggregates replicas once and lists them in the routing table", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+
+		mockClient := mocks.NewMockBackendClient(ctrl)
+		backends := []vmcp.Backend{
+			newTestBackend("fetch-a"),
+			newTestBackend("fetch-b", withBackendURL("http://localhost:8081")),
+			newTestBackend("fetch-c", withBackendURL("http://localhost:8082")),
+			newTestBackend("github", withBackendURL("http://localhost:8083")),
+		}
+		mockClient.EXPECT().ListCapabilities(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, target *vmcp.BackendTarget) (*vmcp.CapabilityList, error) {
+				if target.WorkloadID == "github" {
+					return newTestCapabilityList(withTools(newTestTool("fetch", "github"))), nil
+				}
+				return fetchCaps(target.WorkloadID), nil
+			}).Times(4)
+
+		agg := NewDefaultAggregator(mockClient, NewPrefixConflictResolver("{workload}_"), nil,
+			WithReplicaGroups([]*config.ReplicaGroupConfig{
+				{Name: "fetch", Workloads: []string{"fetch-a", "fetch-b", "fetch-c"}},
+			}))
+		result, err := agg.AggregateCapabilities(context.Background(), backends)
+		require.NoError(t, err)
+
+		// Replicas are aggregated once under the group name
+		assert.Len(t, result.Tools, 2)
+		assert.Len(t, result.Resources, 1)
+		assert.Len(t, result.Prompts, 1)
+		assert.Equal(t, "fetch", result.Resources[0].BackendID)
+		assert.Equal(t, "fetch", result.Prompts[0].BackendID)
+
+		target := result.RoutingTable.Tools["fetch_fetch"]
+		require.NotNil(t, target)
+		assert.Equal(t, "fetch-a", target.WorkloadID)
+		assert.Equal(t, "fetch", target.ReplicaGroup)
+		assert.Equal(t, "fetch", target.OriginalCapabilityName)
+		require.Len(t, target.Replicas, 2)
+		for i, id := range []string{"fetch-b", "fetch-c"} {
+			assert.Equal(t, id, target.Replicas[i].WorkloadID)
+			assert.Equal(t, "fetch", target.Replicas[i].ReplicaGroup)
+			assert.Equal(t, "fetch", target.Replicas[i].OriginalCapabilityName)
+			assert.NotEmpty(t, target.Replicas[i].BaseURL)
+		}
+
+		assert.Len(t, result.RoutingTable.Resources["fetch://status"].Replicas, 2)
+		assert.Len(t, result.RoutingTable.Prompts["summarize"].Replicas, 2)
+
+		// Backends outside the group are unaffected
+		github := result.RoutingTable.Tools["github_fetch"]
+		require.NotNil(t, github)
+		assert.Equal(t, "github", github.WorkloadID)
+		assert.Empty(t, github.ReplicaGroup)
+		assert.Empty(t, github.Replicas)
+	})
+
+	t.Run("excludes replicas with different capabilities", func(t *testing.T) {
+		t.Parallel()
+
+		agg := NewDefaultAggregator(nil, nil, nil, WithReplicaGroups([]*config.ReplicaGroupConfig{
+			{Name: "fetch", Workloads: []string{"fetch-a", "fetch-b", "fetch-c"}},
+		}))
+		capabilities := map[string]*BackendCapabilities{
+			"fetch-a": {BackendID: "fetch-a", Tools: []vmcp.Tool{newTestTool("fetch", "fetch-a")}},
+			"fetch-b": {BackendID: "fetch-b", Tools: []vmcp.Tool{newTestTool("fetch_v2", "fetch-b")}},
+			"fetch-c": {BackendID: "fetch-c", Tools: []vmcp.Tool{newTestTool("fetch", "fetch-c")}},
+		}
+
+		resolved, err := agg.ResolveConflicts(context.Background(), capabilities)
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string][]string{"fetch": {"fetch-a", "fetch-c"}}, resolved.ReplicaGroups)
+		require.Len(t, resolved.Tools, 1)
+		assert.Equal(t, "fetch", resolved.Tools["fetch"].BackendID)
+	})
+
+	t.Run("uses the first responding replica as primary", func(t *testing.T) {
+		t.Parallel()
+
+		agg := NewDefaultAggregator(nil, nil, nil, WithReplicaGroups([]*config.ReplicaGroupConfig{
+			{Name: "fetch", Workloads: []string{"fetch-a", "fetch-b"}},
+		}))
+		capabilities := map[string]*BackendCapabilities{
+			"fetch-b": {BackendID: "fetch-b", Tools: []vmcp.Tool{newTestTool("fetch", "fetch-b")}},
+		}
+
+		resolved, err := agg.ResolveConflicts(context.Background(), capabilities)
+		require.NoError(t, err)
+		assert.Equal(t, map[string][]string{"fetch": {"fetch-b"}}, resolved.ReplicaGroups)
+	})
+
+	t.Run("ignores groups whose name collides with a backend", func(t *testing.T) {
+		t.Parallel()
+
+		agg := NewDefaultAggregator(nil, nil, nil, WithReplicaGroups([]*config.ReplicaGroupConfig{
+			{Name: "fetch", Workloads: []string{"fetch-a"}},
+		}))
+		capabilities := map[string]*BackendCapabilities{
+			"fetch":   {BackendID: "fetch", Tools: []vmcp.Tool{newTestTool("other", "fetch")}},
+			"fetch-a": {BackendID: "fetch-a", Tools: []vmcp.Tool{newTestTool("fetch", "fetch-a")}},
+		}
+
+		resolved, err := agg.ResolveConflicts(context.Background(), capabilities)
+		require.NoError(t, err)
+		assert.Empty(t, resolved.ReplicaGroups)
+		require.Len(t, resolved.Tools, 1)
+		assert.Equal(t, "fetch", resolved.Tools["other"].BackendID)
+	})
+}
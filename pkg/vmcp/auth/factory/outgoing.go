@@ -38,6 +38,7 @@ import (
 // Parameters:
 //   - ctx: Context for any initialization that requires it
 //   - envReader: Environment variable reader for dependency injection
+//   - tokenExchangeOpts: Options for the token_exchange strategy (e.g., strategies.WithTokenCache)
 //
 // Returns:
 //   - auth.OutgoingAuthRegistry: Registry with all strategies registered
@@ -45,6 +46,7 @@ import (
 func NewOutgoingAuthRegistry(
 	_ context.Context,
 	envReader env.Reader,
+	tokenExchangeOpts ...strategies.TokenExchangeOption,
 ) (auth.OutgoingAuthRegistry, error) {
 	registry := auth.NewDefaultOutgoingAuthRegistry()
 
@@ -63,7 +65,7 @@ func NewOutgoingAuthRegistry(
 	}
 	if err := registry.RegisterStrategy(
 		authtypes.StrategyTypeTokenExchange,
-		strategies.NewTokenExchangeStrategy(envReader),
+		strategies.NewTokenExchangeStrategy(envReader, tokenExchangeOpts...),
 	); err != nil {
 		return nil, err
 	}
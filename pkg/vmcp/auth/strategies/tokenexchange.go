@@ -7,17 +7,27 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/sync/singleflight"
 
 	"github.com/stacklok/toolhive/pkg/auth"
 	"github.com/stacklok/toolhive/pkg/auth/tokenexchange"
 	"github.com/stacklok/toolhive/pkg/env"
+	"github.com/stacklok/toolhive/pkg/logger"
 	authtypes "github.com/stacklok/toolhive/pkg/vmcp/auth/types"
+	"github.com/stacklok/toolhive/pkg/vmcp/cache"
 	"github.com/stacklok/toolhive/pkg/vmcp/health"
 )
 
 const (
 	// nonePlaceholder is used to represent empty or missing values in cache keys
 	nonePlaceholder = "<none>"
+
+	// backgroundRefreshTimeout bounds token exchanges that refresh cached tokens
+	// outside of a request.
+	backgroundRefreshTimeout = 30 * time.Second
 )
 
 // TokenExchangeStrategy exchanges the client's token for a backend-specific token
@@ -27,8 +37,9 @@ const (
 // token into a backend-specific token that the backend MCP server can validate.
 //
 // The strategy caches ExchangeConfig instances per backend configuration to avoid
-// recreating configuration objects. Per-user token caching is handled by the upper
-// vMCP TokenCache layer.
+// recreating configuration objects. When a TokenCache is configured (see WithTokenCache),
+// exchanged tokens are also cached per (backend, user token, audience) until they expire,
+// and are refreshed in the background shortly before expiry.
 //
 // Required metadata fields:
 //   - token_url: The OAuth 2.0 token endpoint URL for token exchange
@@ -52,14 +63,39 @@ type TokenExchangeStrategy struct {
 	exchangeConfigs map[string]*tokenexchange.ExchangeConfig
 	mu              sync.RWMutex
 	envReader       env.Reader
+
+	// tokenCache caches exchanged tokens per user. Nil disables caching.
+	tokenCache    cache.TokenCache
+	keyBuilder    cache.KeyBuilder
+	refreshOffset time.Duration
+	// exchanges deduplicates concurrent exchanges for the same cache key.
+	exchanges singleflight.Group
+}
+
+// TokenExchangeOption configures a TokenExchangeStrategy.
+type TokenExchangeOption func(*TokenExchangeStrategy)
+
+// WithTokenCache caches exchanged tokens in the given cache.
+// Cached tokens that expire within refreshOffset are returned immediately and
+// refreshed in the background. Tokens without an expiry are never cached.
+func WithTokenCache(tokenCache cache.TokenCache, refreshOffset time.Duration) TokenExchangeOption {
+	return func(s *TokenExchangeStrategy) {
+		s.tokenCache = tokenCache
+		s.refreshOffset = refreshOffset
+	}
 }
 
 // NewTokenExchangeStrategy creates a new TokenExchangeStrategy instance.
-func NewTokenExchangeStrategy(envReader env.Reader) *TokenExchangeStrategy {
-	return &TokenExchangeStrategy{
+func NewTokenExchangeStrategy(envReader env.Reader, opts ...TokenExchangeOption) *TokenExchangeStrategy {
+	s := &TokenExchangeStrategy{
 		exchangeConfigs: make(map[string]*tokenexchange.ExchangeConfig),
 		envReader:       envReader,
+		keyBuilder:      cache.NewKeyBuilder(),
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+	return s
 }
 
 // Name returns the strategy identifier.
@@ -73,13 +109,9 @@ func (*TokenExchangeStrategy) Name() string {
 //  1. Skips authentication if this is a health check request
 //  2. Retrieves the client's identity and token from the context
 //  3. Parses and validates the token exchange configuration from strategy
-//  4. Gets or creates a cached ExchangeConfig for this backend configuration
-//  5. Creates a TokenSource with the current identity token
-//  6. Obtains an access token by performing the exchange
-//  7. Injects the token into the backend request's Authorization header
-//
-// Token caching per user is handled by the upper vMCP TokenCache layer.
-// This strategy only caches the ExchangeConfig template per backend.
+//  4. Returns a cached token for this user and backend, if a TokenCache is configured
+//  5. Otherwise, performs the exchange using a cached ExchangeConfig for this backend
+//  6. Injects the token into the backend request's Authorization header
 //
 // Parameters:
 //   - ctx: Request context containing the authenticated identity (or health check marker)
@@ -113,21 +145,110 @@ func (s *TokenExchangeStrategy) Authenticate(
 		return fmt.Errorf("invalid strategy configuration: %w", err)
 	}
 
-	// Get user-specific exchange config. This creates a fresh config instance
-	// with the current user's token. The underlying server config is cached.
-	exchangeConfig := s.createUserConfig(config, identity.Token)
-	tokenSource := exchangeConfig.TokenSource(ctx)
-
-	token, err := tokenSource.Token()
+	accessToken, err := s.accessToken(ctx, config, identity.Token)
 	if err != nil {
-		return fmt.Errorf("token exchange failed: %w", err)
+		return err
 	}
 
 	// Inject exchanged token into request
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token.AccessToken))
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", accessToken))
 	return nil
 }
 
+// accessToken returns a backend access token for the user, using the token cache when configured.
+func (s *TokenExchangeStrategy) accessToken(
+	ctx context.Context, config *tokenExchangeConfig, identityToken string,
+) (string, error) {
+	if s.tokenCache == nil {
+		token, err := s.exchange(ctx, config, identityToken)
+		if err != nil {
+			return "", err
+		}
+		return token.AccessToken, nil
+	}
+
+	key := s.keyBuilder.BuildKey(buildCacheKey(config), identityToken, config.Audience)
+
+	cached, err := s.tokenCache.Get(ctx, key)
+	if err != nil {
+		// A failing cache must not block requests; fall back to exchanging.
+		logger.Warnf("Failed to read token cache: %v", err)
+	}
+	if cached != nil {
+		if cached.ShouldRefresh(s.refreshOffset) {
+			s.refreshInBackground(ctx, key, config, identityToken)
+		}
+		return cached.Token, nil
+	}
+
+	result, err, _ := s.exchanges.Do(key, func() (any, error) {
+		return s.exchangeAndCache(ctx, key, config, identityToken)
+	})
+	if err != nil {
+		return "", err
+	}
+	return result.(*oauth2.Token).AccessToken, nil
+}
+
+// refreshInBackground exchanges a new token for a cached entry that is about to expire.
+// At most one refresh runs per cache key, and it is not tied to the request's lifetime.
+func (s *TokenExchangeStrategy) refreshInBackground(
+	ctx context.Context, key string, config *tokenExchangeConfig, identityToken string,
+) {
+	refreshCtx := context.WithoutCancel(ctx)
+	s.exchanges.DoChan(key, func() (any, error) {
+		refreshCtx, cancel := context.WithTimeout(refreshCtx, backgroundRefreshTimeout)
+		defer cancel()
+
+		token, err := s.exchangeAndCache(refreshCtx, key, config, identityToken)
+		if err != nil {
+			logger.Warnf("Failed to refresh cached token: %v", err)
+		}
+		return token, err
+	})
+}
+
+// exchangeAndCache performs a token exchange and stores the result in the token cache.
+// Tokens without an expiry are not cached.
+func (s *TokenExchangeStrategy) exchangeAndCache(
+	ctx context.Context, key string, config *tokenExchangeConfig, identityToken string,
+) (*oauth2.Token, error) {
+	token, err := s.exchange(ctx, config, identityToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if token.Expiry.IsZero() {
+		return token, nil
+	}
+
+	if err := s.tokenCache.Set(ctx, key, &cache.CachedToken{
+		Token:        token.AccessToken,
+		TokenType:    token.TokenType,
+		ExpiresAt:    token.Expiry,
+		RefreshToken: token.RefreshToken,
+		Scopes:       config.Scopes,
+	}); err != nil {
+		logger.Warnf("Failed to store token in cache: %v", err)
+	}
+	return token, nil
+}
+
+// exchange performs an RFC 8693 token exchange for the user's token.
+func (s *TokenExchangeStrategy) exchange(
+	ctx context.Context, config *tokenExchangeConfig, identityToken string,
+) (*oauth2.Token, error) {
+	// Get user-specific exchange config. This creates a fresh config instance
+	// with the current user's token. The underlying server config is cached.
+	exchangeConfig := s.createUserConfig(config, identityToken)
+
+	token, err := exchangeConfig.TokenSource(ctx).Token()
+	if err != nil {
+		return nil, fmt.Errorf("token exchange failed: %w", err)
+	}
+	return token, nil
+}
+
 // Validate checks if the required configuration fields are present and valid.
 //
 // This method verifies that:
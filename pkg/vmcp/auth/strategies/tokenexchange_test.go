@@ -3,9 +3,12 @@ package strategies
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -14,6 +17,7 @@ import (
 	"github.com/stacklok/toolhive/pkg/auth"
 	"github.com/stacklok/toolhive/pkg/env/mocks"
 	authtypes "github.com/stacklok/toolhive/pkg/vmcp/auth/types"
+	"github.com/stacklok/toolhive/pkg/vmcp/cache"
 	"github.com/stacklok/toolhive/pkg/vmcp/health"
 )
 
@@ -687,3 +691,93 @@ func TestTokenExchangeStrategy_ClientSecretEnv(t *testing.T) {
 		})
 	}
 }
+
+// createCountingTokenServer returns a token server that issues "token-<n>" for the n-th exchange.
+// A non-positive expiresIn omits the expiry from the response.
+func createCountingTokenServer(t *testing.T, expiresIn int, exchanges *atomic.Int32) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		n := exchanges.Add(1)
+		resp := map[string]any{
+			"access_token":      fmt.Sprintf("token-%d", n),
+			"token_type":        "Bearer",
+			"issued_token_type": "urn:ietf:params:oauth:token-type:access_token",
+		}
+		if expiresIn > 0 {
+			resp["expires_in"] = expiresIn
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// newAuthenticator returns a function that authenticates a request and returns its Authorization header.
+func newAuthenticator(
+	t *testing.T, s *TokenExchangeStrategy, strategy *authtypes.BackendAuthStrategy,
+) func(context.Context) string {
+	t.Helper()
+	return func(ctx context.Context) string {
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		require.NoError(t, s.Authenticate(ctx, req, strategy))
+		return req.Header.Get("Authorization")
+	}
+}
+
+func TestTokenExchangeStrategy_TokenCache(t *testing.T) {
+	t.Parallel()
+
+	t.Run("reuses cached tokens per user", func(t *testing.T) {
+		t.Parallel()
+
+		var exchanges atomic.Int32
+		server := createCountingTokenServer(t, 3600, &exchanges)
+		s := NewTokenExchangeStrategy(createMockEnvReader(t),
+			WithTokenCache(cache.NewMemoryCache(10), time.Minute))
+		authenticate := newAuthenticator(t, s, createTokenExchangeStrategy(server.URL,
+			func(c *authtypes.TokenExchangeConfig) { c.Audience = "backend" }))
+
+		alice := createContextWithIdentity("alice", "alice-token")
+		assert.Equal(t, "Bearer token-1", authenticate(alice))
+		assert.Equal(t, "Bearer token-1", authenticate(alice))
+
+		bob := createContextWithIdentity("bob", "bob-token")
+		assert.Equal(t, "Bearer token-2", authenticate(bob))
+		assert.Equal(t, int32(2), exchanges.Load())
+	})
+
+	t.Run("refreshes tokens close to expiry in the background", func(t *testing.T) {
+		t.Parallel()
+
+		var exchanges atomic.Int32
+		server := createCountingTokenServer(t, 60, &exchanges)
+		s := NewTokenExchangeStrategy(createMockEnvReader(t),
+			WithTokenCache(cache.NewMemoryCache(10), 5*time.Minute))
+		authenticate := newAuthenticator(t, s, createTokenExchangeStrategy(server.URL))
+		ctx := createContextWithIdentity("alice", "alice-token")
+
+		assert.Equal(t, "Bearer token-1", authenticate(ctx))
+
+		// The cached token is still valid, so it is returned while a refresh runs
+		assert.Equal(t, "Bearer token-1", authenticate(ctx))
+		assert.Eventually(t, func() bool { return exchanges.Load() == 2 }, 5*time.Second, 10*time.Millisecond)
+		assert.Eventually(t, func() bool {
+			return authenticate(ctx) != "Bearer token-1"
+		}, 5*time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("does not cache tokens without expiry", func(t *testing.T) {
+		t.Parallel()
+
+		var exchanges atomic.Int32
+		server := createCountingTokenServer(t, 0, &exchanges)
+		s := NewTokenExchangeStrategy(createMockEnvReader(t),
+			WithTokenCache(cache.NewMemoryCache(10), time.Minute))
+		authenticate := newAuthenticator(t, s, createTokenExchangeStrategy(server.URL))
+		ctx := createContextWithIdentity("alice", "alice-token")
+
+		assert.Equal(t, "Bearer token-1", authenticate(ctx))
+		assert.Equal(t, "Bearer token-2", authenticate(ctx))
+	})
+}
@@ -0,0 +1,53 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/stacklok/toolhive/pkg/env"
+	"github.com/stacklok/toolhive/pkg/vmcp/config"
+)
+
+const (
+	// ProviderMemory selects the in-memory LRU token cache.
+	ProviderMemory = "memory"
+
+	// ProviderRedis selects the Redis/Valkey token cache.
+	ProviderRedis = "redis"
+)
+
+// NewFromConfig creates a token cache from configuration.
+// Redis passwords are read from the environment variable named in the configuration.
+func NewFromConfig(ctx context.Context, cfg *config.TokenCacheConfig, envReader env.Reader) (TokenCache, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("token cache configuration is required")
+	}
+
+	switch cfg.Provider {
+	case "", ProviderMemory:
+		maxEntries := DefaultMaxEntries
+		if cfg.Memory != nil && cfg.Memory.MaxEntries > 0 {
+			maxEntries = cfg.Memory.MaxEntries
+		}
+		return NewMemoryCache(maxEntries), nil
+	case ProviderRedis:
+		if cfg.Redis == nil {
+			return nil, fmt.Errorf("redis configuration is required when provider is %q", ProviderRedis)
+		}
+		opts := RedisOptions{
+			Address:   cfg.Redis.Address,
+			DB:        cfg.Redis.DB,
+			KeyPrefix: cfg.Redis.KeyPrefix,
+			TLS:       cfg.Redis.TLS,
+		}
+		if cfg.Redis.PasswordEnv != "" {
+			opts.Password = envReader.Getenv(cfg.Redis.PasswordEnv)
+			if opts.Password == "" {
+				return nil, fmt.Errorf("environment variable %s not set or empty", cfg.Redis.PasswordEnv)
+			}
+		}
+		return NewRedisCache(ctx, opts)
+	default:
+		return nil, fmt.Errorf("unsupported token cache provider: %s", cfg.Provider)
+	}
+}
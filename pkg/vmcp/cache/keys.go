@@ -0,0 +1,21 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// defaultKeyBuilder builds keys in the format {backend}:{sha256(subject_token)}:{audience}.
+type defaultKeyBuilder struct{}
+
+// NewKeyBuilder creates a KeyBuilder that hashes the subject token, so raw client
+// tokens are never used as cache keys or stored in external caches.
+func NewKeyBuilder() KeyBuilder {
+	return defaultKeyBuilder{}
+}
+
+// BuildKey creates a cache key for a token.
+func (defaultKeyBuilder) BuildKey(backend string, subjectToken string, audience string) string {
+	sum := sha256.Sum256([]byte(subjectToken))
+	return backend + ":" + hex.EncodeToString(sum[:]) + ":" + audience
+}
@@ -0,0 +1,152 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"sync"
+)
+
+// DefaultMaxEntries is the default capacity of the in-memory token cache.
+const DefaultMaxEntries = 1000
+
+// MemoryCache is an in-process LRU token cache.
+//
+// Entries expire at the token's ExpiresAt and are removed lazily on access.
+// When the cache is full, the least recently used entry is evicted.
+// Suitable for single-instance deployments.
+type MemoryCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	lru        *list.List
+	entries    map[string]*list.Element
+
+	hits      int64
+	misses    int64
+	evictions int64
+}
+
+type memoryEntry struct {
+	key   string
+	token CachedToken
+}
+
+// NewMemoryCache creates an in-memory LRU token cache holding up to maxEntries tokens.
+// If maxEntries is not positive, DefaultMaxEntries is used.
+func NewMemoryCache(maxEntries int) *MemoryCache {
+	if maxEntries <= 0 {
+		maxEntries = DefaultMaxEntries
+	}
+	return &MemoryCache{
+		maxEntries: maxEntries,
+		lru:        list.New(),
+		entries:    make(map[string]*list.Element),
+	}
+}
+
+// Get retrieves a cached token.
+// Returns nil if the token doesn't exist or has expired.
+func (c *MemoryCache) Get(_ context.Context, key string) (*CachedToken, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		c.misses++
+		return nil, nil
+	}
+
+	entry := elem.Value.(*memoryEntry)
+	if entry.token.IsExpired() {
+		c.removeElement(elem)
+		c.misses++
+		return nil, nil
+	}
+
+	c.lru.MoveToFront(elem)
+	c.hits++
+	token := entry.token
+	return &token, nil
+}
+
+// Set stores a token in the cache until it expires.
+// Tokens that have already expired are not stored.
+func (c *MemoryCache) Set(_ context.Context, key string, token *CachedToken) error {
+	if key == "" {
+		return fmt.Errorf("cache key cannot be empty")
+	}
+	if token == nil {
+		return fmt.Errorf("token cannot be nil")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		if token.IsExpired() {
+			c.removeElement(elem)
+			return nil
+		}
+		elem.Value.(*memoryEntry).token = *token
+		c.lru.MoveToFront(elem)
+		return nil
+	}
+
+	if token.IsExpired() {
+		return nil
+	}
+
+	c.entries[key] = c.lru.PushFront(&memoryEntry{key: key, token: *token})
+	for c.lru.Len() > c.maxEntries {
+		c.removeElement(c.lru.Back())
+		c.evictions++
+	}
+	return nil
+}
+
+// Delete removes a token from the cache.
+func (c *MemoryCache) Delete(_ context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		c.removeElement(elem)
+	}
+	return nil
+}
+
+// Clear removes all tokens from the cache.
+func (c *MemoryCache) Clear(_ context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.lru.Init()
+	c.entries = make(map[string]*list.Element)
+	return nil
+}
+
+// Close releases all cached tokens.
+func (c *MemoryCache) Close() error {
+	return c.Clear(context.Background())
+}
+
+// Stats returns current cache statistics.
+func (c *MemoryCache) Stats(_ context.Context) (*Stats, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return &Stats{
+		Hits:      c.hits,
+		Misses:    c.misses,
+		Evictions: c.evictions,
+		Size:      c.lru.Len(),
+		MaxSize:   c.maxEntries,
+	}, nil
+}
+
+// removeElement removes an entry from both the LRU list and the index.
+// Must be called with c.mu held.
+func (c *MemoryCache) removeElement(elem *list.Element) {
+	c.lru.Remove(elem)
+	delete(c.entries, elem.Value.(*memoryEntry).key)
+}
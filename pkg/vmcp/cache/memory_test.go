@@ -0,0 +1,120 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestToken(value string, ttl time.Duration) *CachedToken {
+	return &CachedToken{
+		Token:     value,
+		TokenType: "Bearer",
+		ExpiresAt: time.Now().Add(ttl),
+	}
+}
+
+func TestMemoryCache_GetSet(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	c := NewMemoryCache(10)
+
+	token, err := c.Get(ctx, "missing")
+	require.NoError(t, err)
+	assert.Nil(t, token)
+
+	require.NoError(t, c.Set(ctx, "key", newTestToken("token-1", time.Hour)))
+	token, err = c.Get(ctx, "key")
+	require.NoError(t, err)
+	require.NotNil(t, token)
+	assert.Equal(t, "token-1", token.Token)
+
+	// Overwriting replaces the token
+	require.NoError(t, c.Set(ctx, "key", newTestToken("token-2", time.Hour)))
+	token, err = c.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.Equal(t, "token-2", token.Token)
+
+	require.NoError(t, c.Delete(ctx, "key"))
+	token, err = c.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.Nil(t, token)
+
+	assert.Error(t, c.Set(ctx, "", newTestToken("token", time.Hour)))
+	assert.Error(t, c.Set(ctx, "key", nil))
+}
+
+func TestMemoryCache_Expiry(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	c := NewMemoryCache(10)
+
+	// Expired tokens are not stored
+	require.NoError(t, c.Set(ctx, "expired", newTestToken("token", -time.Second)))
+	token, err := c.Get(ctx, "expired")
+	require.NoError(t, err)
+	assert.Nil(t, token)
+
+	// Tokens expire at ExpiresAt
+	require.NoError(t, c.Set(ctx, "short", newTestToken("token", 20*time.Millisecond)))
+	time.Sleep(50 * time.Millisecond)
+	token, err = c.Get(ctx, "short")
+	require.NoError(t, err)
+	assert.Nil(t, token)
+
+	stats, err := c.Stats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, stats.Size)
+}
+
+func TestMemoryCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	c := NewMemoryCache(3)
+
+	for i := range 3 {
+		require.NoError(t, c.Set(ctx, fmt.Sprintf("key-%d", i), newTestToken("token", time.Hour)))
+	}
+
+	// Touch key-0 so key-1 becomes the least recently used entry
+	token, err := c.Get(ctx, "key-0")
+	require.NoError(t, err)
+	require.NotNil(t, token)
+
+	require.NoError(t, c.Set(ctx, "key-3", newTestToken("token", time.Hour)))
+
+	for key, present := range map[string]bool{"key-0": true, "key-1": false, "key-2": true, "key-3": true} {
+		token, err := c.Get(ctx, key)
+		require.NoError(t, err)
+		assert.Equal(t, present, token != nil, key)
+	}
+
+	stats, err := c.Stats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &Stats{Hits: 4, Misses: 1, Evictions: 1, Size: 3, MaxSize: 3}, stats)
+
+	require.NoError(t, c.Clear(ctx))
+	stats, err = c.Stats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, stats.Size)
+}
+
+func TestKeyBuilder_BuildKey(t *testing.T) {
+	t.Parallel()
+
+	kb := NewKeyBuilder()
+	key := kb.BuildKey("github", "user-token", "github-api")
+
+	assert.NotContains(t, key, "user-token", "subject tokens must be hashed")
+	assert.Equal(t, key, kb.BuildKey("github", "user-token", "github-api"))
+	assert.NotEqual(t, key, kb.BuildKey("github", "other-token", "github-api"))
+	assert.NotEqual(t, key, kb.BuildKey("jira", "user-token", "github-api"))
+	assert.NotEqual(t, key, kb.BuildKey("github", "user-token", "other-api"))
+}
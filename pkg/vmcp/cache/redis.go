@@ -0,0 +1,189 @@
+package cache
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultRedisKeyPrefix is the default prefix for token cache keys in Redis.
+const DefaultRedisKeyPrefix = "vmcp:tokens:"
+
+// clearScanCount is the number of keys requested per SCAN iteration when clearing the cache.
+const clearScanCount = 100
+
+// RedisOptions configures a Redis/Valkey token cache.
+type RedisOptions struct {
+	// Address is the Redis server address (host:port).
+	Address string
+
+	// Password is the Redis password. Optional.
+	Password string
+
+	// DB is the Redis database number.
+	DB int
+
+	// KeyPrefix is prepended to every cache key. Defaults to DefaultRedisKeyPrefix.
+	KeyPrefix string
+
+	// TLS enables TLS for the Redis connection.
+	TLS bool
+}
+
+// RedisCache is a token cache backed by Redis or Valkey.
+//
+// Tokens are stored as JSON with a Redis expiry matching the token's ExpiresAt,
+// so cached tokens are shared by every vMCP instance using the same server.
+type RedisCache struct {
+	client    *redis.Client
+	keyPrefix string
+}
+
+// redisEntry is the serialized form of a CachedToken.
+type redisEntry struct {
+	Token        string            `json:"token"`
+	TokenType    string            `json:"token_type,omitempty"`
+	ExpiresAt    time.Time         `json:"expires_at"`
+	RefreshToken string            `json:"refresh_token,omitempty"`
+	Scopes       []string          `json:"scopes,omitempty"`
+	Metadata     map[string]string `json:"metadata,omitempty"`
+}
+
+// NewRedisCache connects to a Redis server and returns a token cache backed by it.
+// The connection is verified before returning.
+func NewRedisCache(ctx context.Context, opts RedisOptions) (*RedisCache, error) {
+	if opts.Address == "" {
+		return nil, fmt.Errorf("redis address is required")
+	}
+
+	redisOpts := &redis.Options{
+		Addr:     opts.Address,
+		Password: opts.Password,
+		DB:       opts.DB,
+	}
+	if opts.TLS {
+		redisOpts.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+
+	client := redis.NewClient(redisOpts)
+	if err := client.Ping(ctx).Err(); err != nil {
+		_ = client.Close()
+		return nil, fmt.Errorf("failed to connect to redis at %s: %w", opts.Address, err)
+	}
+
+	keyPrefix := opts.KeyPrefix
+	if keyPrefix == "" {
+		keyPrefix = DefaultRedisKeyPrefix
+	}
+
+	return &RedisCache{
+		client:    client,
+		keyPrefix: keyPrefix,
+	}, nil
+}
+
+// Get retrieves a cached token.
+// Returns nil if the token doesn't exist or has expired.
+func (c *RedisCache) Get(ctx context.Context, key string) (*CachedToken, error) {
+	data, err := c.client.Get(ctx, c.keyPrefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get token from redis: %w", err)
+	}
+
+	var entry redisEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("failed to decode cached token: %w", err)
+	}
+
+	token := &CachedToken{
+		Token:        entry.Token,
+		TokenType:    entry.TokenType,
+		ExpiresAt:    entry.ExpiresAt,
+		RefreshToken: entry.RefreshToken,
+		Scopes:       entry.Scopes,
+		Metadata:     entry.Metadata,
+	}
+	if token.IsExpired() {
+		return nil, nil
+	}
+	return token, nil
+}
+
+// Set stores a token in the cache until it expires.
+// Tokens that have already expired are removed instead of stored.
+func (c *RedisCache) Set(ctx context.Context, key string, token *CachedToken) error {
+	if key == "" {
+		return fmt.Errorf("cache key cannot be empty")
+	}
+	if token == nil {
+		return fmt.Errorf("token cannot be nil")
+	}
+
+	ttl := time.Until(token.ExpiresAt)
+	if ttl <= 0 {
+		return c.Delete(ctx, key)
+	}
+
+	data, err := json.Marshal(redisEntry{
+		Token:        token.Token,
+		TokenType:    token.TokenType,
+		ExpiresAt:    token.ExpiresAt,
+		RefreshToken: token.RefreshToken,
+		Scopes:       token.Scopes,
+		Metadata:     token.Metadata,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode token: %w", err)
+	}
+
+	if err := c.client.Set(ctx, c.keyPrefix+key, data, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to store token in redis: %w", err)
+	}
+	return nil
+}
+
+// Delete removes a token from the cache.
+func (c *RedisCache) Delete(ctx context.Context, key string) error {
+	if err := c.client.Del(ctx, c.keyPrefix+key).Err(); err != nil {
+		return fmt.Errorf("failed to delete token from redis: %w", err)
+	}
+	return nil
+}
+
+// Clear removes all tokens under the cache's key prefix.
+// Keys outside the prefix are left untouched.
+func (c *RedisCache) Clear(ctx context.Context) error {
+	iter := c.client.Scan(ctx, 0, c.keyPrefix+"*", clearScanCount).Iterator()
+	var batch []string
+	for iter.Next(ctx) {
+		batch = append(batch, iter.Val())
+		if len(batch) >= clearScanCount {
+			if err := c.client.Del(ctx, batch...).Err(); err != nil {
+				return fmt.Errorf("failed to clear tokens from redis: %w", err)
+			}
+			batch = batch[:0]
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return fmt.Errorf("failed to scan tokens in redis: %w", err)
+	}
+	if len(batch) > 0 {
+		if err := c.client.Del(ctx, batch...).Err(); err != nil {
+			return fmt.Errorf("failed to clear tokens from redis: %w", err)
+		}
+	}
+	return nil
+}
+
+// Close closes the Redis connection.
+func (c *RedisCache) Close() error {
+	return c.client.Close()
+}
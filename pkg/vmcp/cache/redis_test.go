@@ -0,0 +1,99 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRedisCache(t *testing.T) (*RedisCache, *miniredis.Miniredis) {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	c, err := NewRedisCache(context.Background(), RedisOptions{Address: server.Addr()})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = c.Close() })
+	return c, server
+}
+
+func TestRedisCache_GetSet(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	c, server := newTestRedisCache(t)
+
+	token, err := c.Get(ctx, "missing")
+	require.NoError(t, err)
+	assert.Nil(t, token)
+
+	stored := newTestToken("token-1", time.Hour)
+	stored.Scopes = []string{"repo"}
+	require.NoError(t, c.Set(ctx, "key", stored))
+
+	token, err = c.Get(ctx, "key")
+	require.NoError(t, err)
+	require.NotNil(t, token)
+	assert.Equal(t, "token-1", token.Token)
+	assert.Equal(t, "Bearer", token.TokenType)
+	assert.Equal(t, []string{"repo"}, token.Scopes)
+	assert.WithinDuration(t, stored.ExpiresAt, token.ExpiresAt, time.Second)
+
+	// Keys are namespaced and expire with the token
+	assert.True(t, server.Exists(DefaultRedisKeyPrefix+"key"))
+	assert.InDelta(t, time.Hour.Seconds(), server.TTL(DefaultRedisKeyPrefix+"key").Seconds(), 5)
+
+	require.NoError(t, c.Delete(ctx, "key"))
+	token, err = c.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.Nil(t, token)
+}
+
+func TestRedisCache_Expiry(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	c, server := newTestRedisCache(t)
+
+	require.NoError(t, c.Set(ctx, "key", newTestToken("token", time.Minute)))
+	server.FastForward(2 * time.Minute)
+
+	token, err := c.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.Nil(t, token)
+
+	// Storing an expired token removes the existing entry
+	require.NoError(t, c.Set(ctx, "key", newTestToken("token", time.Hour)))
+	require.NoError(t, c.Set(ctx, "key", newTestToken("token", -time.Second)))
+	assert.False(t, server.Exists(DefaultRedisKeyPrefix+"key"))
+}
+
+func TestRedisCache_ClearOnlyRemovesPrefixedKeys(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	c, server := newTestRedisCache(t)
+
+	for _, key := range []string{"a", "b", "c"} {
+		require.NoError(t, c.Set(ctx, key, newTestToken("token", time.Hour)))
+	}
+	require.NoError(t, server.Set("unrelated", "value"))
+
+	require.NoError(t, c.Clear(ctx))
+
+	assert.Equal(t, []string{"unrelated"}, server.Keys())
+}
+
+func TestNewRedisCache_ConnectionFailure(t *testing.T) {
+	t.Parallel()
+
+	server := miniredis.RunT(t)
+	addr := server.Addr()
+	server.Close()
+
+	_, err := NewRedisCache(context.Background(), RedisOptions{Address: addr})
+	require.Error(t, err)
+}
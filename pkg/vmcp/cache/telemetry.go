@@ -0,0 +1,116 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const instrumentationName = "github.com/stacklok/toolhive/pkg/vmcp/cache"
+
+// instrumentedTokenCache records hit, miss, and error metrics for a TokenCache.
+type instrumentedTokenCache struct {
+	TokenCache
+	attrs  metric.MeasurementOption
+	hits   metric.Int64Counter
+	misses metric.Int64Counter
+	errors metric.Int64Counter
+}
+
+// NewInstrumentedTokenCache decorates a TokenCache so it records hit, miss, and error counters.
+// If the cache implements StatsProvider, its size and eviction count are also exposed.
+// The provider name (e.g., "memory", "redis") is recorded as the cache.provider attribute.
+func NewInstrumentedTokenCache(
+	tokenCache TokenCache, meterProvider metric.MeterProvider, provider string,
+) (TokenCache, error) {
+	meter := meterProvider.Meter(instrumentationName)
+	attrs := metric.WithAttributes(attribute.String("cache.provider", provider))
+
+	hits, err := meter.Int64Counter(
+		"toolhive_vmcp_token_cache_hits",
+		metric.WithDescription("Total number of token cache hits"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create token cache hits counter: %w", err)
+	}
+	misses, err := meter.Int64Counter(
+		"toolhive_vmcp_token_cache_misses",
+		metric.WithDescription("Total number of token cache misses"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create token cache misses counter: %w", err)
+	}
+	errorsTotal, err := meter.Int64Counter(
+		"toolhive_vmcp_token_cache_errors",
+		metric.WithDescription("Total number of token cache errors"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create token cache errors counter: %w", err)
+	}
+
+	if statsProvider, ok := tokenCache.(StatsProvider); ok {
+		if err := observeStats(meter, statsProvider, attrs); err != nil {
+			return nil, err
+		}
+	}
+
+	return &instrumentedTokenCache{
+		TokenCache: tokenCache,
+		attrs:      attrs,
+		hits:       hits,
+		misses:     misses,
+		errors:     errorsTotal,
+	}, nil
+}
+
+// observeStats exposes cache size and evictions from a StatsProvider as observable instruments.
+func observeStats(meter metric.Meter, statsProvider StatsProvider, attrs metric.MeasurementOption) error {
+	size, err := meter.Int64ObservableGauge(
+		"toolhive_vmcp_token_cache_size",
+		metric.WithDescription("Number of tokens currently cached"))
+	if err != nil {
+		return fmt.Errorf("failed to create token cache size gauge: %w", err)
+	}
+	evictions, err := meter.Int64ObservableCounter(
+		"toolhive_vmcp_token_cache_evictions",
+		metric.WithDescription("Total number of tokens evicted from the cache"))
+	if err != nil {
+		return fmt.Errorf("failed to create token cache evictions counter: %w", err)
+	}
+
+	_, err = meter.RegisterCallback(func(ctx context.Context, observer metric.Observer) error {
+		stats, err := statsProvider.Stats(ctx)
+		if err != nil {
+			return err
+		}
+		observer.ObserveInt64(size, int64(stats.Size), attrs)
+		observer.ObserveInt64(evictions, stats.Evictions, attrs)
+		return nil
+	}, size, evictions)
+	if err != nil {
+		return fmt.Errorf("failed to register token cache stats callback: %w", err)
+	}
+	return nil
+}
+
+// Get retrieves a cached token and records whether it was a hit or a miss.
+func (c *instrumentedTokenCache) Get(ctx context.Context, key string) (*CachedToken, error) {
+	token, err := c.TokenCache.Get(ctx, key)
+	switch {
+	case err != nil:
+		c.errors.Add(ctx, 1, c.attrs)
+	case token == nil:
+		c.misses.Add(ctx, 1, c.attrs)
+	default:
+		c.hits.Add(ctx, 1, c.attrs)
+	}
+	return token, err
+}
+
+// Set stores a token in the cache and records failures.
+func (c *instrumentedTokenCache) Set(ctx context.Context, key string, token *CachedToken) error {
+	err := c.TokenCache.Set(ctx, key, token)
+	if err != nil {
+		c.errors.Add(ctx, 1, c.attrs)
+	}
+	return err
+}
@@ -229,42 +229,61 @@ type StepResult struct {
 }
 
 // WorkflowStatus represents the current state of a workflow execution.
+// It is serialized to JSON by persistent WorkflowStateStore implementations.
 type WorkflowStatus struct {
 	// WorkflowID identifies the workflow.
-	WorkflowID string
+	WorkflowID string `json:"workflowId"`
+
+	// WorkflowName is the name of the workflow definition being executed.
+	WorkflowName string `json:"workflowName,omitempty"`
 
 	// Status is the current workflow status.
-	Status WorkflowStatusType
+	Status WorkflowStatusType `json:"status"`
 
 	// CurrentStep is the currently executing step (if running).
-	CurrentStep string
+	CurrentStep string `json:"currentStep,omitempty"`
 
 	// CompletedSteps are the steps that have completed.
-	CompletedSteps []string
+	CompletedSteps []string `json:"completedSteps,omitempty"`
+
+	// StepOutputs are the outputs of the completed steps, keyed by step ID.
+	// They allow an interrupted workflow to resume without re-running completed steps.
+	StepOutputs map[string]map[string]any `json:"stepOutputs,omitempty"`
+
+	// Params are the workflow input parameters (with defaults applied).
+	Params map[string]any `json:"params,omitempty"`
 
 	// PendingElicitations are elicitations waiting for user response.
-	PendingElicitations []*PendingElicitation
+	PendingElicitations []*PendingElicitation `json:"pendingElicitations,omitempty"`
+
+	// ResumeKey identifies the invocation (workflow, parameters and caller) that
+	// started the workflow. An interrupted workflow is resumed when an invocation
+	// with the same key is made.
+	ResumeKey string `json:"resumeKey,omitempty"`
 
 	// StartTime is when the workflow started.
-	StartTime time.Time
+	StartTime time.Time `json:"startTime"`
+
+	// Deadline is when the workflow times out.
+	Deadline time.Time `json:"deadline,omitempty"`
 
 	// LastUpdateTime is when the status was last updated.
-	LastUpdateTime time.Time
+	LastUpdateTime time.Time `json:"lastUpdateTime"`
 }
 
 // PendingElicitation represents an elicitation awaiting user response.
 type PendingElicitation struct {
 	// StepID is the elicitation step ID.
-	StepID string
+	StepID string `json:"stepId"`
 
 	// Message is the elicitation message.
-	Message string
+	Message string `json:"message,omitempty"`
 
 	// Schema is the requested data schema.
-	Schema map[string]any
+	Schema map[string]any `json:"schema,omitempty"`
 
 	// ExpiresAt is when the elicitation times out.
-	ExpiresAt time.Time
+	ExpiresAt time.Time `json:"expiresAt"`
 }
 
 // WorkflowStatusType represents the state of a workflow.
@@ -345,6 +364,22 @@ type WorkflowContext struct {
 	// Access must be synchronized using mu.
 	Workflow *WorkflowMetadata
 
+	// workflowName is the name of the workflow definition being executed.
+	// Set before execution starts and read-only afterwards.
+	workflowName string
+
+	// resumeKey identifies the invocation that started the workflow.
+	// Set before execution starts and read-only afterwards.
+	resumeKey string
+
+	// deadline is when the workflow times out.
+	// Set before execution starts and read-only afterwards.
+	deadline time.Time
+
+	// pendingElicitations are the elicitation steps waiting for a user response.
+	// Access must be synchronized using mu.
+	pendingElicitations map[string]*PendingElicitation
+
 	// mu protects concurrent access to Steps map and Workflow metadata during parallel execution.
 	mu sync.RWMutex
 }
@@ -384,6 +419,18 @@ type WorkflowStateStore interface {
 	ListActiveWorkflows(ctx context.Context) ([]string, error)
 }
 
+// WorkflowRecoverer is implemented by composers that can resume workflows
+// interrupted by a restart.
+//
+// RecoverWorkflows loads the active workflows from the WorkflowStateStore. Each one is
+// resumed from its last checkpoint the next time the same caller invokes the same
+// workflow with the same parameters: completed steps are not executed again and
+// pending elicitations keep their original deadline.
+type WorkflowRecoverer interface {
+	// RecoverWorkflows loads interrupted workflows and returns how many can be resumed.
+	RecoverWorkflows(ctx context.Context) (int, error)
+}
+
 // ElicitationProtocolHandler handles MCP elicitation protocol interactions.
 //
 // This interface provides an SDK-agnostic abstraction for elicitation requests,
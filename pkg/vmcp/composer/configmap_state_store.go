@@ -0,0 +1,162 @@
+// Package composer provides composite tool workflow execution for Virtual MCP Server.
+package composer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// WorkflowStateLabel labels the ConfigMaps holding workflow state.
+	// Its value is the name prefix of the store the ConfigMap belongs to.
+	WorkflowStateLabel = "toolhive.stacklok.dev/vmcp-workflow-state"
+
+	// workflowStateDataKey is the ConfigMap data key holding the workflow state.
+	workflowStateDataKey = "state.json"
+)
+
+// configMapStateBackend stores each workflow state in its own ConfigMap.
+// ConfigMaps are named "<namePrefix>-<workflowID>" and labelled with WorkflowStateLabel.
+type configMapStateBackend struct {
+	client     client.Client
+	namespace  string
+	namePrefix string
+}
+
+// NewConfigMapStateStore creates a workflow state store that keeps state in ConfigMaps
+// in the given namespace. namePrefix is typically the VirtualMCPServer name, which keeps
+// the state of different servers apart.
+//
+// The client's service account needs permission to create, get, list, update and
+// delete ConfigMaps in the namespace.
+func NewConfigMapStateStore(
+	c client.Client,
+	namespace string,
+	namePrefix string,
+	cleanupInterval time.Duration,
+	maxAge time.Duration,
+) (*PersistentStateStore, error) {
+	if c == nil {
+		return nil, fmt.Errorf("kubernetes client is required")
+	}
+	if namespace == "" {
+		return nil, fmt.Errorf("namespace is required")
+	}
+	if errs := validation.IsDNS1123Label(namePrefix); len(errs) > 0 {
+		return nil, fmt.Errorf("invalid ConfigMap name prefix %q: %s", namePrefix, strings.Join(errs, ", "))
+	}
+
+	backend := &configMapStateBackend{
+		client:     c,
+		namespace:  namespace,
+		namePrefix: namePrefix,
+	}
+	return newPersistentStateStore(backend, cleanupInterval, maxAge), nil
+}
+
+func (b *configMapStateBackend) name(workflowID string) string {
+	return b.namePrefix + "-" + workflowID
+}
+
+func (b *configMapStateBackend) key(workflowID string) client.ObjectKey {
+	return client.ObjectKey{Namespace: b.namespace, Name: b.name(workflowID)}
+}
+
+func (b *configMapStateBackend) write(ctx context.Context, workflowID string, data []byte) error {
+	if err := validateWorkflowID(workflowID); err != nil {
+		return err
+	}
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var configMap corev1.ConfigMap
+		err := b.client.Get(ctx, b.key(workflowID), &configMap)
+		if apierrors.IsNotFound(err) {
+			configMap = corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      b.name(workflowID),
+					Namespace: b.namespace,
+					Labels:    map[string]string{WorkflowStateLabel: b.namePrefix},
+				},
+				Data: map[string]string{workflowStateDataKey: string(data)},
+			}
+			err = b.client.Create(ctx, &configMap)
+			if apierrors.IsAlreadyExists(err) {
+				// Created concurrently; retry as an update
+				return apierrors.NewConflict(corev1.Resource("configmaps"), configMap.Name, err)
+			}
+			return err
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get ConfigMap: %w", err)
+		}
+
+		configMap.Data = map[string]string{workflowStateDataKey: string(data)}
+		return b.client.Update(ctx, &configMap)
+	})
+}
+
+func (b *configMapStateBackend) read(ctx context.Context, workflowID string) ([]byte, error) {
+	if err := validateWorkflowID(workflowID); err != nil {
+		return nil, err
+	}
+
+	var configMap corev1.ConfigMap
+	if err := b.client.Get(ctx, b.key(workflowID), &configMap); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, ErrWorkflowNotFound
+		}
+		return nil, fmt.Errorf("failed to get ConfigMap: %w", err)
+	}
+
+	data, ok := configMap.Data[workflowStateDataKey]
+	if !ok {
+		return nil, ErrWorkflowNotFound
+	}
+	return []byte(data), nil
+}
+
+func (b *configMapStateBackend) remove(ctx context.Context, workflowID string) error {
+	if err := validateWorkflowID(workflowID); err != nil {
+		return err
+	}
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: b.name(workflowID), Namespace: b.namespace},
+	}
+	if err := b.client.Delete(ctx, configMap); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete ConfigMap: %w", err)
+	}
+	return nil
+}
+
+func (b *configMapStateBackend) readAll(ctx context.Context) (map[string][]byte, error) {
+	var configMaps corev1.ConfigMapList
+	if err := b.client.List(ctx, &configMaps,
+		client.InNamespace(b.namespace),
+		client.MatchingLabels{WorkflowStateLabel: b.namePrefix},
+	); err != nil {
+		return nil, fmt.Errorf("failed to list ConfigMaps: %w", err)
+	}
+
+	prefix := b.namePrefix + "-"
+	records := make(map[string][]byte, len(configMaps.Items))
+	for _, configMap := range configMaps.Items {
+		workflowID, ok := strings.CutPrefix(configMap.Name, prefix)
+		if !ok {
+			continue
+		}
+		if data, ok := configMap.Data[workflowStateDataKey]; ok {
+			records[workflowID] = []byte(data)
+		}
+	}
+	return records, nil
+}
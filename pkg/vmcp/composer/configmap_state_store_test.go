@@ -0,0 +1,106 @@
+package composer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newTestConfigMapClient(t *testing.T, objs ...client.Object) client.Client {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+}
+
+func TestNewConfigMapStateStore_Validation(t *testing.T) {
+	t.Parallel()
+
+	c := newTestConfigMapClient(t)
+
+	_, err := NewConfigMapStateStore(nil, "default", "my-vmcp", time.Minute, time.Hour)
+	assert.Error(t, err)
+
+	_, err = NewConfigMapStateStore(c, "", "my-vmcp", time.Minute, time.Hour)
+	assert.Error(t, err)
+
+	_, err = NewConfigMapStateStore(c, "default", "Invalid_Prefix", time.Minute, time.Hour)
+	assert.Error(t, err)
+}
+
+func TestConfigMapStateStore_SaveLoadDelete(t *testing.T) {
+	t.Parallel()
+
+	c := newTestConfigMapClient(t)
+	store, err := NewConfigMapStateStore(c, "default", "my-vmcp", time.Minute, time.Hour)
+	require.NoError(t, err)
+	t.Cleanup(store.Stop)
+	ctx := context.Background()
+
+	state := &WorkflowStatus{
+		WorkflowID:     "workflow-1",
+		WorkflowName:   "deploy",
+		Status:         WorkflowStatusRunning,
+		CompletedSteps: []string{"fetch"},
+		StepOutputs:    map[string]map[string]any{"fetch": {"version": "1.2.3"}},
+	}
+	require.NoError(t, store.SaveState(ctx, state.WorkflowID, state))
+
+	// The state is stored in a labelled ConfigMap named after the prefix and workflow ID
+	var configMap corev1.ConfigMap
+	require.NoError(t, c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "my-vmcp-workflow-1"}, &configMap))
+	assert.Equal(t, "my-vmcp", configMap.Labels[WorkflowStateLabel])
+	assert.Contains(t, configMap.Data, workflowStateDataKey)
+
+	// Saving again updates the existing ConfigMap
+	state.Status = WorkflowStatusCompleted
+	require.NoError(t, store.SaveState(ctx, state.WorkflowID, state))
+
+	loaded, err := store.LoadState(ctx, state.WorkflowID)
+	require.NoError(t, err)
+	assert.Equal(t, WorkflowStatusCompleted, loaded.Status)
+	assert.Equal(t, state.StepOutputs, loaded.StepOutputs)
+
+	require.NoError(t, store.DeleteState(ctx, state.WorkflowID))
+	_, err = store.LoadState(ctx, state.WorkflowID)
+	assert.ErrorIs(t, err, ErrWorkflowNotFound)
+
+	// Deleting again is not an error
+	require.NoError(t, store.DeleteState(ctx, state.WorkflowID))
+}
+
+func TestConfigMapStateStore_ListActiveWorkflows(t *testing.T) {
+	t.Parallel()
+
+	// A ConfigMap belonging to another server must not be listed
+	other := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "other-vmcp-workflow-9",
+			Namespace: "default",
+			Labels:    map[string]string{WorkflowStateLabel: "other-vmcp"},
+		},
+		Data: map[string]string{workflowStateDataKey: `{"workflowId":"workflow-9","status":"running"}`},
+	}
+	c := newTestConfigMapClient(t, other)
+	store, err := NewConfigMapStateStore(c, "default", "my-vmcp", time.Minute, time.Hour)
+	require.NoError(t, err)
+	t.Cleanup(store.Stop)
+	ctx := context.Background()
+
+	require.NoError(t, store.SaveState(ctx, "workflow-1",
+		&WorkflowStatus{WorkflowID: "workflow-1", Status: WorkflowStatusWaitingForElicitation}))
+	require.NoError(t, store.SaveState(ctx, "workflow-2",
+		&WorkflowStatus{WorkflowID: "workflow-2", Status: WorkflowStatusCompleted}))
+
+	active, err := store.ListActiveWorkflows(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"workflow-1"}, active)
+}
@@ -0,0 +1,154 @@
+// Package composer provides composite tool workflow execution for Virtual MCP Server.
+package composer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// stateFileExtension is the extension of workflow state files.
+const stateFileExtension = ".json"
+
+// workflowIDPattern restricts workflow IDs to names that are safe to use as
+// file names and Kubernetes object names.
+var workflowIDPattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// validateWorkflowID checks that a workflow ID can be used as a storage key.
+func validateWorkflowID(workflowID string) error {
+	if !workflowIDPattern.MatchString(workflowID) {
+		return fmt.Errorf("invalid workflow ID %q", workflowID)
+	}
+	return nil
+}
+
+// fileStateBackend stores each workflow state as a JSON file in a directory.
+// Files are replaced atomically so a crash never leaves a partially written state.
+type fileStateBackend struct {
+	dir string
+	mu  sync.RWMutex
+}
+
+// NewFileStateStore creates a workflow state store that keeps state in files under dir.
+// The directory is created if it does not exist.
+func NewFileStateStore(dir string, cleanupInterval, maxAge time.Duration) (*PersistentStateStore, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("state directory is required")
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create workflow state directory: %w", err)
+	}
+
+	return newPersistentStateStore(&fileStateBackend{dir: dir}, cleanupInterval, maxAge), nil
+}
+
+func (b *fileStateBackend) path(workflowID string) string {
+	return filepath.Join(b.dir, workflowID+stateFileExtension)
+}
+
+func (b *fileStateBackend) write(_ context.Context, workflowID string, data []byte) error {
+	if err := validateWorkflowID(workflowID); err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	tmp, err := os.CreateTemp(b.dir, workflowID+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create state file: %w", err)
+	}
+	tmpName := tmp.Name()
+	defer func() {
+		// No-op once the file has been renamed
+		_ = os.Remove(tmpName)
+	}()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to sync state file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close state file: %w", err)
+	}
+
+	if err := os.Rename(tmpName, b.path(workflowID)); err != nil {
+		return fmt.Errorf("failed to replace state file: %w", err)
+	}
+	return nil
+}
+
+func (b *fileStateBackend) read(_ context.Context, workflowID string) ([]byte, error) {
+	if err := validateWorkflowID(workflowID); err != nil {
+		return nil, err
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	data, err := os.ReadFile(b.path(workflowID))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrWorkflowNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state file: %w", err)
+	}
+	return data, nil
+}
+
+func (b *fileStateBackend) remove(_ context.Context, workflowID string) error {
+	if err := validateWorkflowID(workflowID); err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err := os.Remove(b.path(workflowID)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove state file: %w", err)
+	}
+	return nil
+}
+
+func (b *fileStateBackend) readAll(_ context.Context) (map[string][]byte, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	entries, err := os.ReadDir(b.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state directory: %w", err)
+	}
+
+	records := make(map[string][]byte, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, stateFileExtension) {
+			continue
+		}
+		workflowID := strings.TrimSuffix(name, stateFileExtension)
+		if validateWorkflowID(workflowID) != nil {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(b.dir, name))
+		if err != nil {
+			// The file may have been removed since the directory was listed
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to read state file: %w", err)
+		}
+		records[workflowID] = data
+	}
+	return records, nil
+}
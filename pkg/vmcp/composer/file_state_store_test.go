@@ -0,0 +1,171 @@
+package composer
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestFileStateStore(t *testing.T, dir string) *PersistentStateStore {
+	t.Helper()
+	store, err := NewFileStateStore(dir, 1*time.Minute, 1*time.Hour)
+	require.NoError(t, err)
+	t.Cleanup(store.Stop)
+	return store
+}
+
+func TestFileStateStore_SaveAndLoad(t *testing.T) {
+	t.Parallel()
+	store := newTestFileStateStore(t, t.TempDir())
+	ctx := context.Background()
+
+	now := time.Now().UTC().Truncate(time.Second)
+	state := &WorkflowStatus{
+		WorkflowID:     "6f1c2a3e-0d4b-4c8e-9a7f-1b2c3d4e5f60",
+		WorkflowName:   "deploy",
+		Status:         WorkflowStatusWaitingForElicitation,
+		CompletedSteps: []string{"fetch"},
+		StepOutputs:    map[string]map[string]any{"fetch": {"version": "1.2.3"}},
+		Params:         map[string]any{"env": "prod"},
+		PendingElicitations: []*PendingElicitation{
+			{StepID: "confirm", Message: "Deploy?", ExpiresAt: now.Add(5 * time.Minute)},
+		},
+		ResumeKey:      "key",
+		StartTime:      now,
+		Deadline:       now.Add(30 * time.Minute),
+		LastUpdateTime: now,
+	}
+
+	require.NoError(t, store.SaveState(ctx, state.WorkflowID, state))
+
+	loaded, err := store.LoadState(ctx, state.WorkflowID)
+	require.NoError(t, err)
+	assert.Equal(t, state.WorkflowName, loaded.WorkflowName)
+	assert.Equal(t, state.Status, loaded.Status)
+	assert.Equal(t, state.CompletedSteps, loaded.CompletedSteps)
+	assert.Equal(t, state.StepOutputs, loaded.StepOutputs)
+	assert.Equal(t, state.Params, loaded.Params)
+	require.Len(t, loaded.PendingElicitations, 1)
+	assert.Equal(t, "confirm", loaded.PendingElicitations[0].StepID)
+	assert.True(t, state.PendingElicitations[0].ExpiresAt.Equal(loaded.PendingElicitations[0].ExpiresAt))
+	assert.True(t, state.Deadline.Equal(loaded.Deadline))
+}
+
+func TestFileStateStore_SurvivesReopen(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	first, err := NewFileStateStore(dir, 1*time.Minute, 1*time.Hour)
+	require.NoError(t, err)
+	require.NoError(t, first.SaveState(ctx, "workflow-1", &WorkflowStatus{
+		WorkflowID: "workflow-1",
+		Status:     WorkflowStatusRunning,
+	}))
+	first.Stop()
+
+	second := newTestFileStateStore(t, dir)
+	loaded, err := second.LoadState(ctx, "workflow-1")
+	require.NoError(t, err)
+	assert.Equal(t, WorkflowStatusRunning, loaded.Status)
+}
+
+func TestFileStateStore_LoadNotFound(t *testing.T) {
+	t.Parallel()
+	store := newTestFileStateStore(t, t.TempDir())
+
+	_, err := store.LoadState(context.Background(), "missing")
+	assert.ErrorIs(t, err, ErrWorkflowNotFound)
+}
+
+func TestFileStateStore_Delete(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	store := newTestFileStateStore(t, dir)
+	ctx := context.Background()
+
+	require.NoError(t, store.SaveState(ctx, "workflow-1", &WorkflowStatus{WorkflowID: "workflow-1"}))
+	require.NoError(t, store.DeleteState(ctx, "workflow-1"))
+
+	_, err := store.LoadState(ctx, "workflow-1")
+	assert.ErrorIs(t, err, ErrWorkflowNotFound)
+
+	// Deleting again is not an error
+	require.NoError(t, store.DeleteState(ctx, "workflow-1"))
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries, "no state or temporary files should be left behind")
+}
+
+func TestFileStateStore_InvalidWorkflowID(t *testing.T) {
+	t.Parallel()
+	store := newTestFileStateStore(t, t.TempDir())
+	ctx := context.Background()
+
+	for _, id := range []string{"../escape", "UPPER", "has/slash", "-leading"} {
+		err := store.SaveState(ctx, id, &WorkflowStatus{WorkflowID: id})
+		assert.Error(t, err, "workflow ID %q should be rejected", id)
+	}
+}
+
+func TestFileStateStore_ListActiveWorkflows(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	store := newTestFileStateStore(t, dir)
+	ctx := context.Background()
+
+	states := map[string]WorkflowStatusType{
+		"pending":   WorkflowStatusPending,
+		"running":   WorkflowStatusRunning,
+		"waiting":   WorkflowStatusWaitingForElicitation,
+		"completed": WorkflowStatusCompleted,
+		"failed":    WorkflowStatusFailed,
+		"cancelled": WorkflowStatusCancelled,
+		"timed-out": WorkflowStatusTimedOut,
+	}
+	for id, status := range states {
+		require.NoError(t, store.SaveState(ctx, id, &WorkflowStatus{WorkflowID: id, Status: status}))
+	}
+
+	// Unrelated and unreadable files are ignored
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("hello"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "corrupt.json"), []byte("{"), 0600))
+
+	active, err := store.ListActiveWorkflows(ctx)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"pending", "running", "waiting"}, active)
+}
+
+func TestFileStateStore_Cleanup(t *testing.T) {
+	t.Parallel()
+	store := newTestFileStateStore(t, t.TempDir())
+	ctx := context.Background()
+
+	now := time.Now()
+	states := []*WorkflowStatus{
+		{WorkflowID: "old-completed", Status: WorkflowStatusCompleted, LastUpdateTime: now.Add(-2 * time.Hour)},
+		{WorkflowID: "new-completed", Status: WorkflowStatusCompleted, LastUpdateTime: now},
+		{WorkflowID: "abandoned", Status: WorkflowStatusRunning, Deadline: now.Add(-2 * time.Hour)},
+		{WorkflowID: "running", Status: WorkflowStatusRunning, Deadline: now.Add(time.Hour)},
+	}
+	for _, state := range states {
+		require.NoError(t, store.SaveState(ctx, state.WorkflowID, state))
+	}
+
+	store.cleanup(ctx)
+
+	for _, id := range []string{"old-completed", "abandoned"} {
+		_, err := store.LoadState(ctx, id)
+		assert.ErrorIs(t, err, ErrWorkflowNotFound, "workflow %s should be cleaned up", id)
+	}
+	for _, id := range []string{"new-completed", "running"} {
+		_, err := store.LoadState(ctx, id)
+		assert.NoError(t, err, "workflow %s should be kept", id)
+	}
+}
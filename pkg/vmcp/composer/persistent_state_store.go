@@ -0,0 +1,244 @@
+// Package composer provides composite tool workflow execution for Virtual MCP Server.
+package composer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/stacklok/toolhive/pkg/logger"
+)
+
+const (
+	// DefaultStateCleanupInterval is how often persistent state stores remove stale workflows.
+	DefaultStateCleanupInterval = 5 * time.Minute
+
+	// DefaultStateMaxAge is how long persistent state stores keep finished workflows.
+	DefaultStateMaxAge = 1 * time.Hour
+)
+
+// stateBackend stores serialized workflow state records.
+type stateBackend interface {
+	// write stores the record for a workflow, replacing any existing record.
+	write(ctx context.Context, workflowID string, data []byte) error
+
+	// read returns the record for a workflow, or ErrWorkflowNotFound.
+	read(ctx context.Context, workflowID string) ([]byte, error)
+
+	// remove deletes the record for a workflow. Removing a missing record is not an error.
+	remove(ctx context.Context, workflowID string) error
+
+	// readAll returns every stored record keyed by workflow ID.
+	readAll(ctx context.Context) (map[string][]byte, error)
+}
+
+// PersistentStateStore implements WorkflowStateStore on top of durable storage,
+// so workflow state survives vMCP restarts.
+//
+// State is stored as JSON. Use NewFileStateStore for local deployments and
+// NewConfigMapStateStore for Kubernetes.
+//
+// Finished workflows are removed once they are older than maxAge. Active workflows
+// whose deadline passed more than maxAge ago are considered abandoned and removed too.
+//
+// Thread-safety: Safe for concurrent access. A store must not be shared between
+// vMCP instances that resume workflows, since each instance recovers every active workflow.
+type PersistentStateStore struct {
+	backend stateBackend
+
+	// maxAge defines how long to keep finished and abandoned workflows.
+	maxAge time.Duration
+
+	// stopCleanup signals the cleanup goroutine to stop.
+	stopCleanup chan struct{}
+
+	// cleanupDone signals when cleanup goroutine has stopped.
+	cleanupDone chan struct{}
+
+	stopOnce sync.Once
+}
+
+// newPersistentStateStore creates a store using the given backend and starts periodic cleanup.
+func newPersistentStateStore(backend stateBackend, cleanupInterval, maxAge time.Duration) *PersistentStateStore {
+	if cleanupInterval <= 0 {
+		cleanupInterval = DefaultStateCleanupInterval
+	}
+	if maxAge <= 0 {
+		maxAge = DefaultStateMaxAge
+	}
+
+	store := &PersistentStateStore{
+		backend:     backend,
+		maxAge:      maxAge,
+		stopCleanup: make(chan struct{}),
+		cleanupDone: make(chan struct{}),
+	}
+
+	go store.runCleanup(cleanupInterval)
+
+	return store
+}
+
+// SaveState persists workflow state.
+func (s *PersistentStateStore) SaveState(ctx context.Context, workflowID string, state *WorkflowStatus) error {
+	if workflowID == "" {
+		return fmt.Errorf("workflow ID is required")
+	}
+	if state == nil {
+		return fmt.Errorf("state is required")
+	}
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to encode state of workflow %s: %w", workflowID, err)
+	}
+
+	if err := s.backend.write(ctx, workflowID, data); err != nil {
+		return fmt.Errorf("failed to save state of workflow %s: %w", workflowID, err)
+	}
+
+	logger.Debugf("Saved state for workflow %s (status: %s)", workflowID, state.Status)
+	return nil
+}
+
+// LoadState retrieves workflow state.
+// Returns ErrWorkflowNotFound if the workflow does not exist.
+func (s *PersistentStateStore) LoadState(ctx context.Context, workflowID string) (*WorkflowStatus, error) {
+	if workflowID == "" {
+		return nil, fmt.Errorf("workflow ID is required")
+	}
+
+	data, err := s.backend.read(ctx, workflowID)
+	if err != nil {
+		if errors.Is(err, ErrWorkflowNotFound) {
+			return nil, fmt.Errorf("%w: workflow %s", ErrWorkflowNotFound, workflowID)
+		}
+		return nil, fmt.Errorf("failed to load state of workflow %s: %w", workflowID, err)
+	}
+
+	return decodeWorkflowStatus(workflowID, data)
+}
+
+// DeleteState removes workflow state.
+// This is idempotent - deleting a non-existent workflow is not an error.
+func (s *PersistentStateStore) DeleteState(ctx context.Context, workflowID string) error {
+	if workflowID == "" {
+		return fmt.Errorf("workflow ID is required")
+	}
+
+	if err := s.backend.remove(ctx, workflowID); err != nil {
+		return fmt.Errorf("failed to delete state of workflow %s: %w", workflowID, err)
+	}
+
+	logger.Debugf("Deleted state for workflow %s", workflowID)
+	return nil
+}
+
+// ListActiveWorkflows returns the IDs of workflows that are pending, running or
+// waiting for elicitation.
+func (s *PersistentStateStore) ListActiveWorkflows(ctx context.Context) ([]string, error) {
+	states, err := s.loadAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var activeIDs []string
+	for workflowID, state := range states {
+		if !isTerminalWorkflowStatus(state.Status) {
+			activeIDs = append(activeIDs, workflowID)
+		}
+	}
+
+	return activeIDs, nil
+}
+
+// Stop stops the cleanup goroutine and waits for it to finish.
+// Stored state is left in place.
+func (s *PersistentStateStore) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.stopCleanup)
+	})
+	<-s.cleanupDone
+}
+
+// loadAll decodes every stored workflow state. Records that cannot be decoded are skipped.
+func (s *PersistentStateStore) loadAll(ctx context.Context) (map[string]*WorkflowStatus, error) {
+	records, err := s.backend.readAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workflow states: %w", err)
+	}
+
+	states := make(map[string]*WorkflowStatus, len(records))
+	for workflowID, data := range records {
+		state, err := decodeWorkflowStatus(workflowID, data)
+		if err != nil {
+			logger.Warnf("Skipping unreadable workflow state %s: %v", workflowID, err)
+			continue
+		}
+		states[workflowID] = state
+	}
+
+	return states, nil
+}
+
+// runCleanup periodically removes stale workflows from the store.
+func (s *PersistentStateStore) runCleanup(interval time.Duration) {
+	defer close(s.cleanupDone)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), interval)
+			s.cleanup(ctx)
+			cancel()
+		case <-s.stopCleanup:
+			logger.Debugf("State store cleanup goroutine stopped")
+			return
+		}
+	}
+}
+
+// cleanup removes finished workflows older than maxAge and abandoned active workflows.
+func (s *PersistentStateStore) cleanup(ctx context.Context) {
+	states, err := s.loadAll(ctx)
+	if err != nil {
+		logger.Warnf("Failed to clean up workflow states: %v", err)
+		return
+	}
+
+	now := time.Now()
+	removed := 0
+	for workflowID, state := range states {
+		stale := isTerminalWorkflowStatus(state.Status) && now.Sub(state.LastUpdateTime) > s.maxAge
+		abandoned := !isTerminalWorkflowStatus(state.Status) && !state.Deadline.IsZero() &&
+			now.Sub(state.Deadline) > s.maxAge
+		if !stale && !abandoned {
+			continue
+		}
+
+		if err := s.backend.remove(ctx, workflowID); err != nil {
+			logger.Warnf("Failed to remove stale workflow state %s: %v", workflowID, err)
+			continue
+		}
+		removed++
+	}
+
+	if removed > 0 {
+		logger.Debugf("Cleaned up %d stale workflow(s)", removed)
+	}
+}
+
+// decodeWorkflowStatus decodes a stored workflow state record.
+func decodeWorkflowStatus(workflowID string, data []byte) (*WorkflowStatus, error) {
+	var state WorkflowStatus
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to decode state of workflow %s: %w", workflowID, err)
+	}
+	return &state, nil
+}
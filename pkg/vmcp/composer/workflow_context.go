@@ -48,6 +48,63 @@ func (m *workflowContextManager) CreateContext(params map[string]any) *WorkflowC
 	return ctx
 }
 
+// RestoreContext recreates the context of an interrupted workflow from its saved state.
+// Completed steps are restored with their outputs and pending elicitations are rehydrated.
+func (m *workflowContextManager) RestoreContext(state *WorkflowStatus) *WorkflowContext {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ctx := &WorkflowContext{
+		WorkflowID:   state.WorkflowID,
+		Params:       cloneMap(state.Params),
+		Steps:        make(map[string]*StepResult, len(state.CompletedSteps)),
+		Variables:    make(map[string]any),
+		workflowName: state.WorkflowName,
+		resumeKey:    state.ResumeKey,
+		deadline:     state.Deadline,
+		Workflow: &WorkflowMetadata{
+			ID:         state.WorkflowID,
+			StartTime:  state.StartTime,
+			StepCount:  len(state.CompletedSteps),
+			Status:     WorkflowStatusRunning,
+			DurationMs: 0,
+		},
+	}
+	if ctx.Params == nil {
+		ctx.Params = make(map[string]any)
+	}
+
+	// CompletedSteps is saved in completion order. The original step timings are not
+	// persisted, so restored steps get increasing end times to preserve that order.
+	for i, stepID := range state.CompletedSteps {
+		output, ok := state.StepOutputs[stepID]
+		if !ok {
+			continue
+		}
+		endTime := state.LastUpdateTime.Add(time.Duration(i-len(state.CompletedSteps)) * time.Nanosecond)
+		ctx.Steps[stepID] = &StepResult{
+			StepID:    stepID,
+			Status:    StepStatusCompleted,
+			Output:    cloneMap(output),
+			StartTime: endTime,
+			EndTime:   endTime,
+		}
+	}
+
+	for _, pe := range state.PendingElicitations {
+		if pe == nil {
+			continue
+		}
+		if ctx.pendingElicitations == nil {
+			ctx.pendingElicitations = make(map[string]*PendingElicitation)
+		}
+		ctx.pendingElicitations[pe.StepID] = clonePendingElicitation(pe)
+	}
+
+	m.contexts[ctx.WorkflowID] = ctx
+	return ctx
+}
+
 // GetContext retrieves a workflow context by ID.
 func (m *workflowContextManager) GetContext(workflowID string) (*WorkflowContext, error) {
 	m.mu.RLock()
@@ -156,6 +213,37 @@ func (ctx *WorkflowContext) HasStepFailed(stepID string) bool {
 	return exists && result.Status == StepStatusFailed
 }
 
+// SetPendingElicitation records that an elicitation step is waiting for a user response.
+// Thread-safe for concurrent step execution.
+func (ctx *WorkflowContext) SetPendingElicitation(pending *PendingElicitation) {
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+
+	if ctx.pendingElicitations == nil {
+		ctx.pendingElicitations = make(map[string]*PendingElicitation)
+	}
+	ctx.pendingElicitations[pending.StepID] = pending
+}
+
+// GetPendingElicitation retrieves the pending elicitation for a step.
+// Thread-safe for concurrent step execution.
+func (ctx *WorkflowContext) GetPendingElicitation(stepID string) (*PendingElicitation, bool) {
+	ctx.mu.RLock()
+	defer ctx.mu.RUnlock()
+
+	pending, exists := ctx.pendingElicitations[stepID]
+	return pending, exists
+}
+
+// ClearPendingElicitation removes the pending elicitation for a step.
+// Thread-safe for concurrent step execution.
+func (ctx *WorkflowContext) ClearPendingElicitation(stepID string) {
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+
+	delete(ctx.pendingElicitations, stepID)
+}
+
 // GetLastStepOutput retrieves the output of the most recently completed step.
 // This is useful for getting the final workflow output.
 // Thread-safe for concurrent step execution.
@@ -6,6 +6,8 @@ import (
 	"errors"
 	"fmt"
 	"maps"
+	"slices"
+	"sync"
 	"time"
 
 	"github.com/cenkalti/backoff/v5"
@@ -59,6 +61,11 @@ type workflowEngine struct {
 
 	// auditor provides audit logging for workflow execution (optional).
 	auditor *audit.WorkflowAuditor
+
+	// recoverable maps the resume key of each interrupted workflow loaded by
+	// RecoverWorkflows to its workflow ID.
+	recoverable   map[string]string
+	recoverableMu sync.Mutex
 }
 
 // NewWorkflowEngine creates a new workflow execution engine.
@@ -86,6 +93,7 @@ func NewWorkflowEngine(
 		dagExecutor:        newDAGExecutor(defaultMaxParallelSteps),
 		stateStore:         stateStore,
 		auditor:            auditor,
+		recoverable:        make(map[string]string),
 	}
 }
 
@@ -108,15 +116,30 @@ func (e *workflowEngine) ExecuteWorkflow(
 	// Apply parameter defaults from JSON Schema before execution
 	paramsWithDefaults := applyParameterDefaults(def.Parameters, params)
 
-	// Create workflow context
-	workflowCtx := e.contextManager.CreateContext(paramsWithDefaults)
-	defer e.contextManager.DeleteContext(workflowCtx.WorkflowID)
+	// Resume the workflow if this invocation was interrupted by a restart
+	resumeKey := workflowResumeKey(ctx, def.Name, paramsWithDefaults)
+	workflowCtx := e.takeRecoverableWorkflow(ctx, resumeKey)
+	resumed := workflowCtx != nil
 
 	// Apply workflow timeout
 	timeout := def.Timeout
 	if timeout == 0 {
 		timeout = defaultWorkflowTimeout
 	}
+
+	if resumed {
+		logger.Infof("Resuming interrupted workflow %s (%s)", def.Name, workflowCtx.WorkflowID)
+		// Resumed workflows keep the deadline of the original invocation
+		timeout = time.Until(workflowCtx.deadline)
+	} else {
+		// Create workflow context
+		workflowCtx = e.contextManager.CreateContext(paramsWithDefaults)
+		workflowCtx.workflowName = def.Name
+		workflowCtx.resumeKey = resumeKey
+		workflowCtx.deadline = time.Now().Add(timeout)
+	}
+	defer e.contextManager.DeleteContext(workflowCtx.WorkflowID)
+
 	execCtx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
@@ -128,21 +151,19 @@ func (e *workflowEngine) ExecuteWorkflow(
 		StartTime:  time.Now(),
 		Metadata:   make(map[string]string),
 	}
+	if resumed {
+		result.StartTime = workflowCtx.Workflow.StartTime
+		result.Metadata["resumed"] = "true"
+	}
 
 	// Audit workflow start
 	e.auditWorkflowStart(ctx, workflowCtx.WorkflowID, def.Name, paramsWithDefaults, timeout)
 
 	// Save initial workflow state
 	if e.stateStore != nil {
-		initialState := &WorkflowStatus{
-			WorkflowID:          workflowCtx.WorkflowID,
-			Status:              WorkflowStatusRunning,
-			CurrentStep:         "",
-			CompletedSteps:      []string{},
-			PendingElicitations: []*PendingElicitation{},
-			StartTime:           result.StartTime,
-			LastUpdateTime:      result.StartTime,
-		}
+		initialState := e.buildWorkflowStatus(workflowCtx, WorkflowStatusRunning)
+		initialState.StartTime = result.StartTime
+		initialState.LastUpdateTime = time.Now()
 		if err := e.stateStore.SaveState(execCtx, workflowCtx.WorkflowID, initialState); err != nil {
 			logger.Warnf("Failed to save initial workflow state: %v", err)
 		}
@@ -161,6 +182,12 @@ func (e *workflowEngine) ExecuteWorkflow(
 		default:
 		}
 
+		// Steps completed before the workflow was interrupted keep their outputs
+		if resumed && workflowCtx.HasStepCompleted(step.ID) {
+			logger.Debugf("Step %s already completed before workflow was resumed", step.ID)
+			return nil
+		}
+
 		// Execute step
 		return e.executeStep(ctx, step, workflowCtx, def.FailureMode)
 	}
@@ -566,9 +593,38 @@ func (e *workflowEngine) executeElicitationStep(
 		return err
 	}
 
+	// An elicitation rehydrated from an interrupted workflow keeps its original deadline
+	elicitationConfig := step.Elicitation
+	if pending, ok := workflowCtx.GetPendingElicitation(step.ID); ok {
+		remaining := time.Until(pending.ExpiresAt)
+		if remaining <= 0 {
+			workflowCtx.ClearPendingElicitation(step.ID)
+			return e.handleElicitationTimeout(step, workflowCtx)
+		}
+		resumedConfig := *step.Elicitation
+		resumedConfig.Timeout = remaining
+		elicitationConfig = &resumedConfig
+	}
+
+	// Record the pending elicitation so it survives a restart
+	timeout := elicitationConfig.Timeout
+	if timeout == 0 {
+		timeout = defaultElicitationTimeout
+	}
+	timeout = min(timeout, maxElicitationTimeout)
+	workflowCtx.SetPendingElicitation(&PendingElicitation{
+		StepID:    step.ID,
+		Message:   elicitationConfig.Message,
+		Schema:    elicitationConfig.Schema,
+		ExpiresAt: time.Now().Add(timeout),
+	})
+	e.checkpointWorkflowState(ctx, workflowCtx)
+
 	// Request elicitation (synchronous - blocks until response or timeout)
 	// Per MCP 2025-06-18: SDK handles JSON-RPC ID correlation internally
-	response, err := e.elicitationHandler.RequestElicitation(ctx, workflowCtx.WorkflowID, step.ID, step.Elicitation)
+	response, err := e.elicitationHandler.RequestElicitation(ctx, workflowCtx.WorkflowID, step.ID, elicitationConfig)
+	workflowCtx.ClearPendingElicitation(step.ID)
+	defer e.checkpointWorkflowState(ctx, workflowCtx)
 	if err != nil {
 		// Handle timeout
 		if errors.Is(err, ErrElicitationTimeout) {
@@ -721,25 +777,49 @@ func (*workflowEngine) handleElicitationAction(
 }
 
 // buildWorkflowStatus creates a WorkflowStatus from the current workflow context.
+// A running workflow with pending elicitations is reported as waiting for elicitation.
 func (*workflowEngine) buildWorkflowStatus(workflowCtx *WorkflowContext, status WorkflowStatusType) *WorkflowStatus {
 	workflowCtx.mu.RLock()
 	defer workflowCtx.mu.RUnlock()
 
-	// Build list of completed steps
+	// Build list of completed steps in completion order, with their outputs
 	completedSteps := make([]string, 0, len(workflowCtx.Steps))
+	stepOutputs := make(map[string]map[string]any, len(workflowCtx.Steps))
 	for stepID, result := range workflowCtx.Steps {
 		if result.Status == StepStatusCompleted {
 			completedSteps = append(completedSteps, stepID)
+			stepOutputs[stepID] = cloneMap(result.Output)
 		}
 	}
+	slices.SortStableFunc(completedSteps, func(a, b string) int {
+		return workflowCtx.Steps[a].EndTime.Compare(workflowCtx.Steps[b].EndTime)
+	})
+
+	pendingElicitations := make([]*PendingElicitation, 0, len(workflowCtx.pendingElicitations))
+	for _, pending := range workflowCtx.pendingElicitations {
+		pendingElicitations = append(pendingElicitations, clonePendingElicitation(pending))
+	}
+	if status == WorkflowStatusRunning && len(pendingElicitations) > 0 {
+		status = WorkflowStatusWaitingForElicitation
+	}
+
+	startTime := time.Now()
+	if workflowCtx.Workflow != nil {
+		startTime = workflowCtx.Workflow.StartTime
+	}
 
 	return &WorkflowStatus{
 		WorkflowID:          workflowCtx.WorkflowID,
+		WorkflowName:        workflowCtx.workflowName,
 		Status:              status,
 		CurrentStep:         "",
 		CompletedSteps:      completedSteps,
-		PendingElicitations: []*PendingElicitation{},
-		StartTime:           time.Now(),
+		StepOutputs:         stepOutputs,
+		Params:              cloneMap(workflowCtx.Params),
+		PendingElicitations: pendingElicitations,
+		ResumeKey:           workflowCtx.resumeKey,
+		StartTime:           startTime,
+		Deadline:            workflowCtx.deadline,
 		LastUpdateTime:      time.Now(),
 	}
 }
@@ -0,0 +1,135 @@
+// Package composer provides composite tool workflow execution for Virtual MCP Server.
+package composer
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/stacklok/toolhive/pkg/auth"
+	"github.com/stacklok/toolhive/pkg/logger"
+)
+
+// workflowResumeKey identifies a workflow invocation by workflow name, parameters and caller.
+// Parameters are hashed through their JSON encoding, which sorts map keys.
+func workflowResumeKey(ctx context.Context, workflowName string, params map[string]any) string {
+	subject := ""
+	if identity, ok := auth.IdentityFromContext(ctx); ok && identity != nil {
+		subject = identity.Subject
+	}
+
+	paramsJSON, err := json.Marshal(params)
+	if err != nil {
+		// Parameters that cannot be encoded cannot be persisted either
+		return ""
+	}
+
+	h := sha256.New()
+	h.Write([]byte(workflowName))
+	h.Write([]byte{0})
+	h.Write([]byte(subject))
+	h.Write([]byte{0})
+	h.Write(paramsJSON)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// RecoverWorkflows loads the active workflows from the state store so they can be
+// resumed when their invocation is repeated.
+//
+// Workflows whose deadline has passed are marked as timed out. Pending elicitations
+// are rehydrated when the workflow is resumed and keep their original expiry.
+func (e *workflowEngine) RecoverWorkflows(ctx context.Context) (int, error) {
+	if e.stateStore == nil {
+		return 0, nil
+	}
+
+	workflowIDs, err := e.stateStore.ListActiveWorkflows(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list active workflows: %w", err)
+	}
+
+	e.recoverableMu.Lock()
+	defer e.recoverableMu.Unlock()
+
+	recovered := 0
+	for _, workflowID := range workflowIDs {
+		state, err := e.stateStore.LoadState(ctx, workflowID)
+		if err != nil {
+			logger.Warnf("Failed to load state of interrupted workflow %s: %v", workflowID, err)
+			continue
+		}
+		if isTerminalWorkflowStatus(state.Status) {
+			continue
+		}
+
+		if state.ResumeKey == "" || state.Deadline.IsZero() || !time.Now().Before(state.Deadline) {
+			e.markWorkflowTimedOut(ctx, state)
+			continue
+		}
+
+		e.recoverable[state.ResumeKey] = workflowID
+		recovered++
+		logger.Infof("Recovered interrupted workflow %s (%s, status: %s, %d completed steps, %d pending elicitations)",
+			state.WorkflowName, workflowID, state.Status, len(state.CompletedSteps), len(state.PendingElicitations))
+	}
+
+	return recovered, nil
+}
+
+// takeRecoverableWorkflow returns the restored context of the interrupted workflow
+// matching resumeKey, or nil if there is none. A recovered workflow is resumed at most once.
+func (e *workflowEngine) takeRecoverableWorkflow(ctx context.Context, resumeKey string) *WorkflowContext {
+	if e.stateStore == nil || resumeKey == "" {
+		return nil
+	}
+
+	e.recoverableMu.Lock()
+	workflowID, ok := e.recoverable[resumeKey]
+	delete(e.recoverable, resumeKey)
+	e.recoverableMu.Unlock()
+	if !ok {
+		return nil
+	}
+
+	state, err := e.stateStore.LoadState(ctx, workflowID)
+	if err != nil {
+		if !errors.Is(err, ErrWorkflowNotFound) {
+			logger.Warnf("Failed to load state of interrupted workflow %s: %v", workflowID, err)
+		}
+		return nil
+	}
+
+	// The workflow may have been cancelled or expired since it was recovered
+	if isTerminalWorkflowStatus(state.Status) {
+		return nil
+	}
+	if !time.Now().Before(state.Deadline) {
+		e.markWorkflowTimedOut(ctx, state)
+		return nil
+	}
+
+	return e.contextManager.RestoreContext(state)
+}
+
+// markWorkflowTimedOut records that an interrupted workflow can no longer be resumed.
+func (e *workflowEngine) markWorkflowTimedOut(ctx context.Context, state *WorkflowStatus) {
+	logger.Infof("Interrupted workflow %s (%s) expired before it was resumed", state.WorkflowName, state.WorkflowID)
+
+	state.Status = WorkflowStatusTimedOut
+	state.PendingElicitations = nil
+	if err := e.stateStore.SaveState(ctx, state.WorkflowID, state); err != nil {
+		logger.Warnf("Failed to save state of expired workflow %s: %v", state.WorkflowID, err)
+	}
+}
+
+// isTerminalWorkflowStatus reports whether a workflow in the given status has finished.
+func isTerminalWorkflowStatus(status WorkflowStatusType) bool {
+	return status == WorkflowStatusCompleted ||
+		status == WorkflowStatusFailed ||
+		status == WorkflowStatusCancelled ||
+		status == WorkflowStatusTimedOut
+}
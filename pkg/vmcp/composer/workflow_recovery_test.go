@@ -0,0 +1,152 @@
+package composer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/stacklok/toolhive/pkg/vmcp"
+)
+
+// recordingElicitationHandler accepts every elicitation and records the requested config.
+type recordingElicitationHandler struct {
+	configs []*ElicitationConfig
+	content map[string]any
+}
+
+func (h *recordingElicitationHandler) RequestElicitation(
+	_ context.Context,
+	_ string,
+	_ string,
+	elicitConfig *ElicitationConfig,
+) (*ElicitationResponse, error) {
+	h.configs = append(h.configs, elicitConfig)
+	return &ElicitationResponse{Action: elicitationActionAccept, Content: h.content}, nil
+}
+
+func resumableWorkflow() *WorkflowDefinition {
+	return &WorkflowDefinition{
+		Name:    "deploy",
+		Timeout: 30 * time.Minute,
+		Steps: []WorkflowStep{
+			toolStep("fetch", "fetch_tool", map[string]any{"env": "{{.params.env}}"}),
+			{
+				ID:        "confirm",
+				Type:      StepTypeElicitation,
+				DependsOn: []string{"fetch"},
+				Elicitation: &ElicitationConfig{
+					Message: "Deploy?",
+					Schema:  map[string]any{"type": "object"},
+					Timeout: 10 * time.Minute,
+				},
+			},
+			toolStepWithDeps("deploy", "deploy_tool",
+				map[string]any{"version": "{{.steps.fetch.output.version}}"}, []string{"confirm"}),
+		},
+	}
+}
+
+func TestWorkflowEngine_ResumeInterruptedWorkflow(t *testing.T) {
+	t.Parallel()
+
+	te := newTestEngine(t)
+	ctx := context.Background()
+	store := newTestFileStateStore(t, t.TempDir())
+
+	// State left behind by a vMCP instance that stopped while waiting for the elicitation
+	params := map[string]any{"env": "prod"}
+	now := time.Now()
+	saved := &WorkflowStatus{
+		WorkflowID:     "0b6f7a44-3a3c-4d6e-8f55-2f3e1c9d8a10",
+		WorkflowName:   "deploy",
+		Status:         WorkflowStatusWaitingForElicitation,
+		CompletedSteps: []string{"fetch"},
+		StepOutputs:    map[string]map[string]any{"fetch": {"version": "1.2.3"}},
+		Params:         params,
+		PendingElicitations: []*PendingElicitation{
+			{StepID: "confirm", Message: "Deploy?", ExpiresAt: now.Add(3 * time.Minute)},
+		},
+		ResumeKey:      workflowResumeKey(ctx, "deploy", params),
+		StartTime:      now.Add(-5 * time.Minute),
+		Deadline:       now.Add(25 * time.Minute),
+		LastUpdateTime: now.Add(-1 * time.Minute),
+	}
+	require.NoError(t, store.SaveState(ctx, saved.WorkflowID, saved))
+
+	handler := &recordingElicitationHandler{}
+	engine := NewWorkflowEngine(te.Router, te.Backend, handler, store, nil)
+
+	recovered, err := engine.(WorkflowRecoverer).RecoverWorkflows(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, recovered)
+
+	// Only the step after the elicitation runs; fetch is not called again
+	target := &vmcp.BackendTarget{WorkloadID: "test-backend", BaseURL: "http://test:8080"}
+	te.Router.EXPECT().RouteTool(gomock.Any(), "deploy_tool").Return(target, nil)
+	te.Backend.EXPECT().CallTool(gomock.Any(), target, "deploy_tool", map[string]any{"version": "1.2.3"}).
+		Return(map[string]any{"status": "deployed"}, nil)
+
+	result, err := engine.ExecuteWorkflow(ctx, resumableWorkflow(), params)
+	require.NoError(t, err)
+	assert.Equal(t, WorkflowStatusCompleted, result.Status)
+	assert.Equal(t, saved.WorkflowID, result.WorkflowID)
+	assert.Equal(t, "true", result.Metadata["resumed"])
+
+	// The elicitation keeps its original expiry
+	require.Len(t, handler.configs, 1)
+	assert.LessOrEqual(t, handler.configs[0].Timeout, 3*time.Minute)
+	assert.Greater(t, handler.configs[0].Timeout, 2*time.Minute)
+
+	final, err := store.LoadState(ctx, saved.WorkflowID)
+	require.NoError(t, err)
+	assert.Equal(t, WorkflowStatusCompleted, final.Status)
+	assert.Empty(t, final.PendingElicitations)
+}
+
+func TestWorkflowEngine_RecoverWorkflows_ExpiredWorkflowTimesOut(t *testing.T) {
+	t.Parallel()
+
+	te := newTestEngine(t)
+	ctx := context.Background()
+	store := newTestFileStateStore(t, t.TempDir())
+
+	params := map[string]any{"env": "prod"}
+	saved := &WorkflowStatus{
+		WorkflowID:     "expired",
+		WorkflowName:   "deploy",
+		Status:         WorkflowStatusRunning,
+		CompletedSteps: []string{"fetch"},
+		Params:         params,
+		ResumeKey:      workflowResumeKey(ctx, "deploy", params),
+		Deadline:       time.Now().Add(-1 * time.Minute),
+	}
+	require.NoError(t, store.SaveState(ctx, saved.WorkflowID, saved))
+
+	engine := NewWorkflowEngine(te.Router, te.Backend, &recordingElicitationHandler{}, store, nil)
+	recovered, err := engine.(WorkflowRecoverer).RecoverWorkflows(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, recovered)
+
+	final, err := store.LoadState(ctx, saved.WorkflowID)
+	require.NoError(t, err)
+	assert.Equal(t, WorkflowStatusTimedOut, final.Status)
+
+	active, err := store.ListActiveWorkflows(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, active)
+}
+
+func TestWorkflowResumeKey(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	key := workflowResumeKey(ctx, "deploy", map[string]any{"a": 1, "b": "x"})
+
+	assert.Equal(t, key, workflowResumeKey(ctx, "deploy", map[string]any{"b": "x", "a": 1}))
+	assert.NotEqual(t, key, workflowResumeKey(ctx, "deploy", map[string]any{"a": 2, "b": "x"}))
+	assert.NotEqual(t, key, workflowResumeKey(ctx, "rollback", map[string]any{"a": 1, "b": "x"}))
+}
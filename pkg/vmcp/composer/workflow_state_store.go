@@ -124,16 +124,28 @@ func cloneWorkflowStatus(state *WorkflowStatus) *WorkflowStatus {
 
 	clone := &WorkflowStatus{
 		WorkflowID:     state.WorkflowID,
+		WorkflowName:   state.WorkflowName,
 		Status:         state.Status,
 		CurrentStep:    state.CurrentStep,
 		CompletedSteps: make([]string, len(state.CompletedSteps)),
+		Params:         cloneMap(state.Params),
+		ResumeKey:      state.ResumeKey,
 		StartTime:      state.StartTime,
+		Deadline:       state.Deadline,
 		LastUpdateTime: state.LastUpdateTime,
 	}
 
 	// Clone completed steps
 	copy(clone.CompletedSteps, state.CompletedSteps)
 
+	// Clone step outputs
+	if state.StepOutputs != nil {
+		clone.StepOutputs = make(map[string]map[string]any, len(state.StepOutputs))
+		for stepID, output := range state.StepOutputs {
+			clone.StepOutputs[stepID] = cloneMap(output)
+		}
+	}
+
 	// Clone pending elicitations
 	if len(state.PendingElicitations) > 0 {
 		clone.PendingElicitations = make([]*PendingElicitation, len(state.PendingElicitations))
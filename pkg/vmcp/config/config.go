@@ -12,6 +12,7 @@ import (
 
 	"github.com/stacklok/toolhive/pkg/audit"
 	thvjson "github.com/stacklok/toolhive/pkg/json"
+	"github.com/stacklok/toolhive/pkg/ratelimit"
 	"github.com/stacklok/toolhive/pkg/telemetry"
 	"github.com/stacklok/toolhive/pkg/vmcp"
 	authtypes "github.com/stacklok/toolhive/pkg/vmcp/auth/types"
@@ -92,6 +93,18 @@ type Config struct {
 	// +optional
 	OutgoingAuth *OutgoingAuthConfig `json:"outgoingAuth,omitempty" yaml:"outgoingAuth,omitempty"`
 
+	// TokenCache configures caching of tokens obtained through outgoing token exchange.
+	// When omitted, every backend request performs a token exchange.
+	// +optional
+	TokenCache *TokenCacheConfig `json:"tokenCache,omitempty" yaml:"tokenCache,omitempty"`
+
+	// SessionStorage configures where client sessions and session affinity pins are stored.
+	// A shared store lets any vMCP replica serve a client session initialized on another replica,
+	// and route it to the same backend replica.
+	// When omitted, sessions and pins are kept in memory.
+	// +optional
+	SessionStorage *SessionStorageConfig `json:"sessionStorage,omitempty" yaml:"sessionStorage,omitempty"`
+
 	// Aggregation defines tool aggregation and conflict resolution strategies.
 	// Supports ToolConfigRef for Kubernetes-native MCPToolConfig resource references.
 	// +optional
@@ -109,6 +122,12 @@ type Config struct {
 	// +optional
 	CompositeToolRefs []CompositeToolRef `json:"compositeToolRefs,omitempty" yaml:"compositeToolRefs,omitempty"`
 
+	// WorkflowState configures where composite tool workflow state is stored.
+	// A persistent store lets workflows interrupted by a restart resume where they stopped.
+	// When omitted, workflow state is kept in memory.
+	// +optional
+	WorkflowState *WorkflowStateConfig `json:"workflowState,omitempty" yaml:"workflowState,omitempty"`
+
 	// Operational configures operational settings.
 	Operational *OperationalConfig `json:"operational,omitempty" yaml:"operational,omitempty"`
 
@@ -125,6 +144,11 @@ type Config struct {
 	// See audit.Config for available configuration options.
 	// +optional
 	Audit *audit.Config `json:"audit,omitempty" yaml:"audit,omitempty"`
+
+	// RateLimit configures token bucket limits for MCP requests to the Virtual MCP server.
+	// Rejected requests receive HTTP 429 with a Retry-After header.
+	// +optional
+	RateLimit *ratelimit.Config `json:"rateLimit,omitempty" yaml:"rateLimit,omitempty"`
 }
 
 // IncomingAuthConfig configures client authentication to the virtual MCP server.
@@ -243,6 +267,140 @@ func (c *OutgoingAuthConfig) ResolveForBackend(backendID string) *authtypes.Back
 	return nil
 }
 
+// TokenCacheConfig configures the cache for exchanged backend tokens.
+// +kubebuilder:object:generate=true
+// +gendoc
+type TokenCacheConfig struct {
+	// Provider selects the cache backend.
+	// - memory: In-process LRU cache (single instance)
+	// - redis: Redis or Valkey server shared by all instances
+	// +kubebuilder:validation:Enum=memory;redis
+	// +kubebuilder:default=memory
+	// +optional
+	Provider string `json:"provider,omitempty" yaml:"provider,omitempty"`
+
+	// RefreshOffset is how long before expiry a cached token is refreshed in the background.
+	// Requests keep using the cached token while it is being refreshed.
+	// +kubebuilder:default="5m"
+	// +optional
+	RefreshOffset Duration `json:"refreshOffset,omitempty" yaml:"refreshOffset,omitempty"`
+
+	// Memory configures the in-memory cache (when Provider = "memory").
+	// +optional
+	Memory *MemoryTokenCacheConfig `json:"memory,omitempty" yaml:"memory,omitempty"`
+
+	// Redis configures the Redis/Valkey cache (when Provider = "redis").
+	// +optional
+	Redis *RedisTokenCacheConfig `json:"redis,omitempty" yaml:"redis,omitempty"`
+}
+
+// MemoryTokenCacheConfig configures the in-memory token cache.
+// +kubebuilder:object:generate=true
+// +gendoc
+type MemoryTokenCacheConfig struct {
+	// MaxEntries is the maximum number of cached tokens.
+	// The least recently used token is evicted when the cache is full.
+	// +kubebuilder:default=1000
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxEntries int `json:"maxEntries,omitempty" yaml:"maxEntries,omitempty"`
+}
+
+// RedisTokenCacheConfig configures the Redis/Valkey token cache.
+// +kubebuilder:object:generate=true
+// +gendoc
+type RedisTokenCacheConfig struct {
+	// Address is the Redis server address (host:port).
+	// +kubebuilder:validation:Required
+	Address string `json:"address" yaml:"address"`
+
+	// DB is the Redis database number.
+	// +optional
+	DB int `json:"db,omitempty" yaml:"db,omitempty"`
+
+	// KeyPrefix is prepended to every cache key.
+	// +kubebuilder:default="vmcp:tokens:"
+	// +optional
+	KeyPrefix string `json:"keyPrefix,omitempty" yaml:"keyPrefix,omitempty"`
+
+	// PasswordEnv is the name of the environment variable containing the Redis password.
+	// The password value is never stored in configuration files.
+	// +optional
+	PasswordEnv string `json:"passwordEnv,omitempty" yaml:"passwordEnv,omitempty"`
+
+	// TLS enables TLS for the Redis connection.
+	// +optional
+	TLS bool `json:"tls,omitempty" yaml:"tls,omitempty"`
+}
+
+// SessionStorageConfig configures the store for client sessions and session affinity pins.
+// +kubebuilder:object:generate=true
+// +gendoc
+type SessionStorageConfig struct {
+	// Provider selects the session store.
+	// - memory: In-process store (single instance)
+	// - redis: Redis or Valkey server shared by all instances
+	// +kubebuilder:validation:Enum=memory;redis
+	// +kubebuilder:default=memory
+	// +optional
+	Provider string `json:"provider,omitempty" yaml:"provider,omitempty"`
+
+	// Redis configures the Redis/Valkey store (when Provider = "redis").
+	// +optional
+	Redis *RedisSessionStorageConfig `json:"redis,omitempty" yaml:"redis,omitempty"`
+}
+
+// RedisSessionStorageConfig configures the Redis/Valkey session store.
+// +kubebuilder:object:generate=true
+// +gendoc
+type RedisSessionStorageConfig struct {
+	// Address is the Redis server address (host:port).
+	// +kubebuilder:validation:Required
+	Address string `json:"address" yaml:"address"`
+
+	// DB is the Redis database number.
+	// +optional
+	DB int `json:"db,omitempty" yaml:"db,omitempty"`
+
+	// KeyPrefix is prepended to every session key.
+	// +kubebuilder:default="vmcp:sessions:"
+	// +optional
+	KeyPrefix string `json:"keyPrefix,omitempty" yaml:"keyPrefix,omitempty"`
+
+	// PasswordEnv is the name of the environment variable containing the Redis password.
+	// The password value is never stored in configuration files.
+	// +optional
+	PasswordEnv string `json:"passwordEnv,omitempty" yaml:"passwordEnv,omitempty"`
+
+	// TLS enables TLS for the Redis connection.
+	// +optional
+	TLS bool `json:"tls,omitempty" yaml:"tls,omitempty"`
+}
+
+// WorkflowStateConfig configures the composite tool workflow state store.
+// +kubebuilder:object:generate=true
+// +gendoc
+type WorkflowStateConfig struct {
+	// Provider selects the state store.
+	// - memory: In-process store, workflows are lost on restart
+	// - file: JSON files on local disk (CLI deployments)
+	// - configmap: One ConfigMap per workflow in the server's namespace (Kubernetes deployments)
+	// +kubebuilder:validation:Enum=memory;file;configmap
+	// +kubebuilder:default=memory
+	// +optional
+	Provider string `json:"provider,omitempty" yaml:"provider,omitempty"`
+
+	// Path is the directory holding workflow state files (when Provider = "file").
+	// Defaults to $XDG_STATE_HOME/toolhive/vmcp/<server name>/workflows.
+	// +optional
+	Path string `json:"path,omitempty" yaml:"path,omitempty"`
+
+	// RetentionPeriod is how long finished workflows are kept in the store.
+	// +kubebuilder:default="1h"
+	// +optional
+	RetentionPeriod Duration `json:"retentionPeriod,omitempty" yaml:"retentionPeriod,omitempty"`
+}
+
 // AggregationConfig defines tool aggregation and conflict resolution strategies.
 // +kubebuilder:object:generate=true
 // +gendoc
@@ -267,6 +425,49 @@ type AggregationConfig struct {
 	// ExcludeAllTools excludes all tools from aggregation when true.
 	// +optional
 	ExcludeAllTools bool `json:"excludeAllTools,omitempty" yaml:"excludeAllTools,omitempty"`
+
+	// ReplicaGroups groups backend workloads that are replicas of the same MCP server.
+	// The capabilities of a replica group are aggregated once, and requests are
+	// load balanced across the healthy replicas in the group.
+	// +optional
+	ReplicaGroups []*ReplicaGroupConfig `json:"replicaGroups,omitempty" yaml:"replicaGroups,omitempty"`
+}
+
+// ReplicaGroupConfig defines a pool of backend workloads that expose the same capability set.
+// +kubebuilder:object:generate=true
+// +gendoc
+type ReplicaGroupConfig struct {
+	// Name is the name of the replica group.
+	// It is used in place of the workload name during conflict resolution
+	// (e.g., as the {workload} placeholder of the prefix strategy).
+	// +kubebuilder:validation:Required
+	Name string `json:"name" yaml:"name"`
+
+	// Workloads lists the backend workloads in the group.
+	// All workloads must expose the same tools, resources, and prompts.
+	// Workloads whose capabilities differ from the first responding workload are excluded.
+	// +kubebuilder:validation:MinItems=1
+	Workloads []string `json:"workloads" yaml:"workloads"`
+
+	// Strategy selects how a replica is chosen for each request.
+	// - round_robin: Rotate through healthy replicas
+	// - least_in_flight: Pick the replica with the fewest outstanding requests
+	// - weighted_random: Pick a replica at random, proportionally to Weights
+	// +kubebuilder:validation:Enum=round_robin;least_in_flight;weighted_random
+	// +kubebuilder:default=round_robin
+	// +optional
+	Strategy string `json:"strategy,omitempty" yaml:"strategy,omitempty"`
+
+	// Weights assigns relative weights to workloads for the weighted_random strategy.
+	// Workloads without an explicit weight default to 1.
+	// +optional
+	Weights map[string]int `json:"weights,omitempty" yaml:"weights,omitempty"`
+
+	// SessionAffinity pins each client session to the first replica it is routed to.
+	// Enable this for backends that keep per-session state (e.g., browser automation).
+	// A session is re-pinned to another replica if its replica becomes unhealthy.
+	// +optional
+	SessionAffinity bool `json:"sessionAffinity,omitempty" yaml:"sessionAffinity,omitempty"`
 }
 
 // ConflictResolutionConfig provides configuration for conflict resolution strategies.
@@ -46,6 +46,19 @@ func (v *DefaultValidator) Validate(cfg *Config) error {
 		errors = append(errors, err.Error())
 	}
 
+	// Validate token cache configuration
+	if err := v.validateTokenCache(cfg.TokenCache); err != nil {
+		errors = append(errors, err.Error())
+	}
+
+	if err := v.validateSessionStorage(cfg.SessionStorage); err != nil {
+		errors = append(errors, err.Error())
+	}
+
+	if err := v.validateWorkflowState(cfg.WorkflowState); err != nil {
+		errors = append(errors, err.Error())
+	}
+
 	// Validate aggregation configuration
 	if err := v.validateAggregation(cfg.Aggregation); err != nil {
 		errors = append(errors, err.Error())
@@ -66,6 +79,13 @@ func (v *DefaultValidator) Validate(cfg *Config) error {
 		errors = append(errors, err.Error())
 	}
 
+	// Validate rate limits
+	if cfg.RateLimit != nil {
+		if err := cfg.RateLimit.Validate(); err != nil {
+			errors = append(errors, fmt.Sprintf("rateLimit: %v", err))
+		}
+	}
+
 	if len(errors) > 0 {
 		return fmt.Errorf("%w:\n  - %s", vmcp.ErrInvalidConfig, strings.Join(errors, "\n  - "))
 	}
@@ -172,6 +192,80 @@ func (v *DefaultValidator) validateOutgoingAuth(auth *OutgoingAuthConfig) error
 	return nil
 }
 
+func (*DefaultValidator) validateTokenCache(tc *TokenCacheConfig) error {
+	if tc == nil {
+		return nil
+	}
+
+	switch tc.Provider {
+	case "", "memory":
+		if tc.Memory != nil && tc.Memory.MaxEntries < 0 {
+			return fmt.Errorf("tokenCache.memory.maxEntries must be positive")
+		}
+	case "redis":
+		if tc.Redis == nil || tc.Redis.Address == "" {
+			return fmt.Errorf("tokenCache.redis.address is required when provider is 'redis'")
+		}
+		if tc.Redis.DB < 0 {
+			return fmt.Errorf("tokenCache.redis.db must be non-negative")
+		}
+	default:
+		return fmt.Errorf("tokenCache.provider must be one of: memory, redis")
+	}
+
+	if tc.RefreshOffset < 0 {
+		return fmt.Errorf("tokenCache.refreshOffset must be non-negative")
+	}
+
+	return nil
+}
+
+func (*DefaultValidator) validateSessionStorage(ss *SessionStorageConfig) error {
+	if ss == nil {
+		return nil
+	}
+
+	switch ss.Provider {
+	case "", "memory":
+		if ss.Redis != nil {
+			return fmt.Errorf("sessionStorage.redis is only valid when provider is 'redis'")
+		}
+	case "redis":
+		if ss.Redis == nil || ss.Redis.Address == "" {
+			return fmt.Errorf("sessionStorage.redis.address is required when provider is 'redis'")
+		}
+		if ss.Redis.DB < 0 {
+			return fmt.Errorf("sessionStorage.redis.db must be non-negative")
+		}
+	default:
+		return fmt.Errorf("sessionStorage.provider must be one of: memory, redis")
+	}
+
+	return nil
+}
+
+func (*DefaultValidator) validateWorkflowState(ws *WorkflowStateConfig) error {
+	if ws == nil {
+		return nil
+	}
+
+	switch ws.Provider {
+	case "", "memory", "configmap":
+		if ws.Path != "" {
+			return fmt.Errorf("workflowState.path is only valid when provider is 'file'")
+		}
+	case "file":
+	default:
+		return fmt.Errorf("workflowState.provider must be one of: memory, file, configmap")
+	}
+
+	if ws.RetentionPeriod < 0 {
+		return fmt.Errorf("workflowState.retentionPeriod must be non-negative")
+	}
+
+	return nil
+}
+
 func (*DefaultValidator) validateBackendAuthStrategy(_ string, strategy *authtypes.BackendAuthStrategy) error {
 	if strategy == nil {
 		return fmt.Errorf("strategy is nil")
@@ -239,7 +333,62 @@ func (v *DefaultValidator) validateAggregation(agg *AggregationConfig) error {
 		return err
 	}
 
-	return v.validateToolConfigurations(agg.Tools)
+	if err := v.validateToolConfigurations(agg.Tools); err != nil {
+		return err
+	}
+
+	return v.validateReplicaGroups(agg.ReplicaGroups)
+}
+
+// validateReplicaGroups validates replica group configurations
+func (*DefaultValidator) validateReplicaGroups(groups []*ReplicaGroupConfig) error {
+	validStrategies := []string{"round_robin", "least_in_flight", "weighted_random"}
+	groupNames := make(map[string]bool)
+	workloadGroups := make(map[string]string)
+
+	for i, group := range groups {
+		if group == nil {
+			return fmt.Errorf("replicaGroups[%d] cannot be null", i)
+		}
+		if group.Name == "" {
+			return fmt.Errorf("replicaGroups[%d].name is required", i)
+		}
+		if groupNames[group.Name] {
+			return fmt.Errorf("duplicate replica group name: %s", group.Name)
+		}
+		groupNames[group.Name] = true
+
+		if len(group.Workloads) == 0 {
+			return fmt.Errorf("replicaGroups[%d].workloads must contain at least one workload", i)
+		}
+		for _, workload := range group.Workloads {
+			if workload == "" {
+				return fmt.Errorf("replicaGroups[%d].workloads cannot contain empty names", i)
+			}
+			if existing, exists := workloadGroups[workload]; exists {
+				return fmt.Errorf("workload %s is in multiple replica groups: %s, %s", workload, existing, group.Name)
+			}
+			workloadGroups[workload] = group.Name
+		}
+
+		if group.Strategy != "" && !contains(validStrategies, group.Strategy) {
+			return fmt.Errorf("replicaGroups[%d].strategy must be one of: %s", i, strings.Join(validStrategies, ", "))
+		}
+
+		if len(group.Weights) > 0 && group.Strategy != "weighted_random" {
+			return fmt.Errorf("replicaGroups[%d].weights is only valid for the weighted_random strategy", i)
+		}
+		for workload, weight := range group.Weights {
+			if !contains(group.Workloads, workload) {
+				return fmt.Errorf("replicaGroups[%d].weights references unknown workload: %s", i, workload)
+			}
+			if weight <= 0 {
+				return fmt.Errorf("replicaGroups[%d].weights.%s must be positive", i, workload)
+			}
+		}
+	}
+
+	return nil
 }
 
 // validateConflictStrategy validates strategy-specific configuration
@@ -316,6 +316,215 @@ func TestValidator_ValidateOutgoingAuth(t *testing.T) {
 	}
 }
 
+func TestValidator_ValidateTokenCache(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		cache   *TokenCacheConfig
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name:    "nil token cache",
+			cache:   nil,
+			wantErr: false,
+		},
+		{
+			name: "valid memory cache",
+			cache: &TokenCacheConfig{
+				Provider:      "memory",
+				RefreshOffset: Duration(5 * time.Minute),
+				Memory:        &MemoryTokenCacheConfig{MaxEntries: 100},
+			},
+			wantErr: false,
+		},
+		{
+			name: "valid redis cache",
+			cache: &TokenCacheConfig{
+				Provider: "redis",
+				Redis:    &RedisTokenCacheConfig{Address: "localhost:6379"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "redis without address",
+			cache: &TokenCacheConfig{
+				Provider: "redis",
+				Redis:    &RedisTokenCacheConfig{},
+			},
+			wantErr: true,
+			errMsg:  "tokenCache.redis.address is required",
+		},
+		{
+			name: "invalid provider",
+			cache: &TokenCacheConfig{
+				Provider: "memcached",
+			},
+			wantErr: true,
+			errMsg:  "tokenCache.provider must be one of",
+		},
+		{
+			name: "negative refresh offset",
+			cache: &TokenCacheConfig{
+				RefreshOffset: Duration(-time.Minute),
+			},
+			wantErr: true,
+			errMsg:  "tokenCache.refreshOffset must be non-negative",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			v := NewValidator()
+			err := v.validateTokenCache(tt.cache)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateTokenCache() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if tt.wantErr && err != nil && tt.errMsg != "" {
+				if !strings.Contains(err.Error(), tt.errMsg) {
+					t.Errorf("validateTokenCache() error message = %v, want to contain %v", err.Error(), tt.errMsg)
+				}
+			}
+		})
+	}
+}
+
+func TestValidator_ValidateSessionStorage(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		storage *SessionStorageConfig
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name:    "nil session storage",
+			storage: nil,
+			wantErr: false,
+		},
+		{
+			name:    "valid memory store",
+			storage: &SessionStorageConfig{Provider: "memory"},
+			wantErr: false,
+		},
+		{
+			name:    "valid redis store",
+			storage: &SessionStorageConfig{Provider: "redis", Redis: &RedisSessionStorageConfig{Address: "localhost:6379"}},
+			wantErr: false,
+		},
+		{
+			name:    "redis without address",
+			storage: &SessionStorageConfig{Provider: "redis", Redis: &RedisSessionStorageConfig{}},
+			wantErr: true,
+			errMsg:  "sessionStorage.redis.address is required",
+		},
+		{
+			name:    "redis settings without redis provider",
+			storage: &SessionStorageConfig{Redis: &RedisSessionStorageConfig{Address: "localhost:6379"}},
+			wantErr: true,
+			errMsg:  "sessionStorage.redis is only valid",
+		},
+		{
+			name:    "negative redis db",
+			storage: &SessionStorageConfig{Provider: "redis", Redis: &RedisSessionStorageConfig{Address: "localhost:6379", DB: -1}},
+			wantErr: true,
+			errMsg:  "sessionStorage.redis.db must be non-negative",
+		},
+		{
+			name:    "invalid provider",
+			storage: &SessionStorageConfig{Provider: "memcached"},
+			wantErr: true,
+			errMsg:  "sessionStorage.provider must be one of",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			v := NewValidator()
+			err := v.validateSessionStorage(tt.storage)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateSessionStorage() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if tt.wantErr && err != nil && tt.errMsg != "" {
+				if !strings.Contains(err.Error(), tt.errMsg) {
+					t.Errorf("validateSessionStorage() error message = %v, want to contain %v", err.Error(), tt.errMsg)
+				}
+			}
+		})
+	}
+}
+
+func TestValidator_ValidateWorkflowState(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		state   *WorkflowStateConfig
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name:    "nil workflow state",
+			state:   nil,
+			wantErr: false,
+		},
+		{
+			name:    "valid file store",
+			state:   &WorkflowStateConfig{Provider: "file", Path: "/var/lib/vmcp", RetentionPeriod: Duration(time.Hour)},
+			wantErr: false,
+		},
+		{
+			name:    "valid configmap store",
+			state:   &WorkflowStateConfig{Provider: "configmap"},
+			wantErr: false,
+		},
+		{
+			name:    "path without file provider",
+			state:   &WorkflowStateConfig{Provider: "configmap", Path: "/var/lib/vmcp"},
+			wantErr: true,
+			errMsg:  "workflowState.path is only valid",
+		},
+		{
+			name:    "invalid provider",
+			state:   &WorkflowStateConfig{Provider: "bbolt"},
+			wantErr: true,
+			errMsg:  "workflowState.provider must be one of",
+		},
+		{
+			name:    "negative retention period",
+			state:   &WorkflowStateConfig{RetentionPeriod: Duration(-time.Minute)},
+			wantErr: true,
+			errMsg:  "workflowState.retentionPeriod must be non-negative",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			v := NewValidator()
+			err := v.validateWorkflowState(tt.state)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateWorkflowState() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if tt.wantErr && err != nil && tt.errMsg != "" {
+				if !strings.Contains(err.Error(), tt.errMsg) {
+					t.Errorf("validateWorkflowState() error message = %v, want to contain %v", err.Error(), tt.errMsg)
+				}
+			}
+		})
+	}
+}
+
 func TestValidator_ValidateAggregation(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -389,6 +598,137 @@ func TestValidator_ValidateAggregation(t *testing.T) {
 			wantErr: true,
 			errMsg:  "tool overrides are required",
 		},
+		{
+			name: "valid replica groups",
+			agg: &AggregationConfig{
+				ConflictResolution: vmcp.ConflictStrategyPrefix,
+				ConflictResolutionConfig: &ConflictResolutionConfig{
+					PrefixFormat: "{workload}_",
+				},
+				ReplicaGroups: []*ReplicaGroupConfig{
+					{Name: "fetch", Workloads: []string{"fetch-a", "fetch-b"}, Strategy: "least_in_flight"},
+					{Name: "search", Workloads: []string{"search-a", "search-b"}, Strategy: "weighted_random",
+						Weights: map[string]int{"search-a": 3}},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "replica group missing name",
+			agg: &AggregationConfig{
+				ConflictResolution: vmcp.ConflictStrategyPrefix,
+				ConflictResolutionConfig: &ConflictResolutionConfig{
+					PrefixFormat: "{workload}_",
+				},
+				ReplicaGroups: []*ReplicaGroupConfig{
+					{Workloads: []string{"fetch-a"}},
+				},
+			},
+			wantErr: true,
+			errMsg:  "replicaGroups[0].name is required",
+		},
+		{
+			name: "duplicate replica group name",
+			agg: &AggregationConfig{
+				ConflictResolution: vmcp.ConflictStrategyPrefix,
+				ConflictResolutionConfig: &ConflictResolutionConfig{
+					PrefixFormat: "{workload}_",
+				},
+				ReplicaGroups: []*ReplicaGroupConfig{
+					{Name: "fetch", Workloads: []string{"fetch-a"}},
+					{Name: "fetch", Workloads: []string{"fetch-b"}},
+				},
+			},
+			wantErr: true,
+			errMsg:  "duplicate replica group name",
+		},
+		{
+			name: "replica group without workloads",
+			agg: &AggregationConfig{
+				ConflictResolution: vmcp.ConflictStrategyPrefix,
+				ConflictResolutionConfig: &ConflictResolutionConfig{
+					PrefixFormat: "{workload}_",
+				},
+				ReplicaGroups: []*ReplicaGroupConfig{
+					{Name: "fetch"},
+				},
+			},
+			wantErr: true,
+			errMsg:  "must contain at least one workload",
+		},
+		{
+			name: "workload in multiple replica groups",
+			agg: &AggregationConfig{
+				ConflictResolution: vmcp.ConflictStrategyPrefix,
+				ConflictResolutionConfig: &ConflictResolutionConfig{
+					PrefixFormat: "{workload}_",
+				},
+				ReplicaGroups: []*ReplicaGroupConfig{
+					{Name: "fetch", Workloads: []string{"shared"}},
+					{Name: "search", Workloads: []string{"shared"}},
+				},
+			},
+			wantErr: true,
+			errMsg:  "is in multiple replica groups",
+		},
+		{
+			name: "invalid replica group strategy",
+			agg: &AggregationConfig{
+				ConflictResolution: vmcp.ConflictStrategyPrefix,
+				ConflictResolutionConfig: &ConflictResolutionConfig{
+					PrefixFormat: "{workload}_",
+				},
+				ReplicaGroups: []*ReplicaGroupConfig{
+					{Name: "fetch", Workloads: []string{"fetch-a"}, Strategy: "fastest"},
+				},
+			},
+			wantErr: true,
+			errMsg:  "strategy must be one of",
+		},
+		{
+			name: "weights without weighted_random",
+			agg: &AggregationConfig{
+				ConflictResolution: vmcp.ConflictStrategyPrefix,
+				ConflictResolutionConfig: &ConflictResolutionConfig{
+					PrefixFormat: "{workload}_",
+				},
+				ReplicaGroups: []*ReplicaGroupConfig{
+					{Name: "fetch", Workloads: []string{"fetch-a"}, Weights: map[string]int{"fetch-a": 2}},
+				},
+			},
+			wantErr: true,
+			errMsg:  "only valid for the weighted_random strategy",
+		},
+		{
+			name: "weights for unknown workload",
+			agg: &AggregationConfig{
+				ConflictResolution: vmcp.ConflictStrategyPrefix,
+				ConflictResolutionConfig: &ConflictResolutionConfig{
+					PrefixFormat: "{workload}_",
+				},
+				ReplicaGroups: []*ReplicaGroupConfig{
+					{Name: "fetch", Workloads: []string{"fetch-a"}, Strategy: "weighted_random",
+						Weights: map[string]int{"fetch-z": 2}},
+				},
+			},
+			wantErr: true,
+			errMsg:  "references unknown workload",
+		},
+		{
+			name: "non-positive weight",
+			agg: &AggregationConfig{
+				ConflictResolution: vmcp.ConflictStrategyPrefix,
+				ConflictResolutionConfig: &ConflictResolutionConfig{
+					PrefixFormat: "{workload}_",
+				},
+				ReplicaGroups: []*ReplicaGroupConfig{
+					{Name: "fetch", Workloads: []string{"fetch-a"}, Strategy: "weighted_random",
+						Weights: map[string]int{"fetch-a": 0}},
+				},
+			},
+			wantErr: true,
+			errMsg:  "must be positive",
+		},
 	}
 
 	for _, tt := range tests {
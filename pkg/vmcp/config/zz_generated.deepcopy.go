@@ -22,6 +22,7 @@ package config
 
 import (
 	"github.com/stacklok/toolhive/pkg/audit"
+	"github.com/stacklok/toolhive/pkg/ratelimit"
 	"github.com/stacklok/toolhive/pkg/telemetry"
 	"github.com/stacklok/toolhive/pkg/vmcp/auth/types"
 )
@@ -45,6 +46,17 @@ func (in *AggregationConfig) DeepCopyInto(out *AggregationConfig) {
 			}
 		}
 	}
+	if in.ReplicaGroups != nil {
+		in, out := &in.ReplicaGroups, &out.ReplicaGroups
+		*out = make([]*ReplicaGroupConfig, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(ReplicaGroupConfig)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AggregationConfig.
@@ -148,6 +160,16 @@ func (in *Config) DeepCopyInto(out *Config) {
 		*out = new(OutgoingAuthConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.TokenCache != nil {
+		in, out := &in.TokenCache, &out.TokenCache
+		*out = new(TokenCacheConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SessionStorage != nil {
+		in, out := &in.SessionStorage, &out.SessionStorage
+		*out = new(SessionStorageConfig)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Aggregation != nil {
 		in, out := &in.Aggregation, &out.Aggregation
 		*out = new(AggregationConfig)
@@ -165,6 +187,11 @@ func (in *Config) DeepCopyInto(out *Config) {
 		*out = make([]CompositeToolRef, len(*in))
 		copy(*out, *in)
 	}
+	if in.WorkflowState != nil {
+		in, out := &in.WorkflowState, &out.WorkflowState
+		*out = new(WorkflowStateConfig)
+		**out = **in
+	}
 	if in.Operational != nil {
 		in, out := &in.Operational, &out.Operational
 		*out = new(OperationalConfig)
@@ -187,6 +214,11 @@ func (in *Config) DeepCopyInto(out *Config) {
 		*out = new(audit.Config)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.RateLimit != nil {
+		in, out := &in.RateLimit, &out.RateLimit
+		*out = new(ratelimit.Config)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Config.
@@ -279,6 +311,21 @@ func (in *IncomingAuthConfig) DeepCopy() *IncomingAuthConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MemoryTokenCacheConfig) DeepCopyInto(out *MemoryTokenCacheConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemoryTokenCacheConfig.
+func (in *MemoryTokenCacheConfig) DeepCopy() *MemoryTokenCacheConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(MemoryTokenCacheConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OIDCConfig) DeepCopyInto(out *OIDCConfig) {
 	*out = *in
@@ -410,6 +457,83 @@ func (in *OutputProperty) DeepCopy() *OutputProperty {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RedisSessionStorageConfig) DeepCopyInto(out *RedisSessionStorageConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisSessionStorageConfig.
+func (in *RedisSessionStorageConfig) DeepCopy() *RedisSessionStorageConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(RedisSessionStorageConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RedisTokenCacheConfig) DeepCopyInto(out *RedisTokenCacheConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisTokenCacheConfig.
+func (in *RedisTokenCacheConfig) DeepCopy() *RedisTokenCacheConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(RedisTokenCacheConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplicaGroupConfig) DeepCopyInto(out *ReplicaGroupConfig) {
+	*out = *in
+	if in.Workloads != nil {
+		in, out := &in.Workloads, &out.Workloads
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Weights != nil {
+		in, out := &in.Weights, &out.Weights
+		*out = make(map[string]int, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicaGroupConfig.
+func (in *ReplicaGroupConfig) DeepCopy() *ReplicaGroupConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ReplicaGroupConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SessionStorageConfig) DeepCopyInto(out *SessionStorageConfig) {
+	*out = *in
+	if in.Redis != nil {
+		in, out := &in.Redis, &out.Redis
+		*out = new(RedisSessionStorageConfig)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SessionStorageConfig.
+func (in *SessionStorageConfig) DeepCopy() *SessionStorageConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(SessionStorageConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StepErrorHandling) DeepCopyInto(out *StepErrorHandling) {
 	*out = *in
@@ -447,6 +571,31 @@ func (in *TimeoutConfig) DeepCopy() *TimeoutConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TokenCacheConfig) DeepCopyInto(out *TokenCacheConfig) {
+	*out = *in
+	if in.Memory != nil {
+		in, out := &in.Memory, &out.Memory
+		*out = new(MemoryTokenCacheConfig)
+		**out = **in
+	}
+	if in.Redis != nil {
+		in, out := &in.Redis, &out.Redis
+		*out = new(RedisTokenCacheConfig)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TokenCacheConfig.
+func (in *TokenCacheConfig) DeepCopy() *TokenCacheConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(TokenCacheConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ToolConfigRef) DeepCopyInto(out *ToolConfigRef) {
 	*out = *in
@@ -555,3 +704,18 @@ func (in *WorkloadToolConfig) DeepCopy() *WorkloadToolConfig {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkflowStateConfig) DeepCopyInto(out *WorkflowStateConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkflowStateConfig.
+func (in *WorkflowStateConfig) DeepCopy() *WorkflowStateConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkflowStateConfig)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -58,6 +58,11 @@ var (
 	// Wrapping errors should include the backend ID and underlying cause.
 	ErrBackendUnavailable = errors.New("backend unavailable")
 
+	// ErrCircuitOpen indicates a request was rejected because the backend's circuit breaker is open.
+	// The backend was not contacted; the request fails fast until the breaker allows a trial request.
+	// Wrapping errors should include the backend ID and when the request may be retried.
+	ErrCircuitOpen = errors.New("circuit breaker open")
+
 	// ErrToolNameConflict indicates a composite tool name conflicts with a backend tool name.
 	// This prevents ambiguity in routing/execution where the same name could refer to
 	// either a backend tool or a composite workflow tool.
@@ -0,0 +1,331 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/stacklok/toolhive/pkg/logger"
+	"github.com/stacklok/toolhive/pkg/vmcp"
+)
+
+// CircuitState represents the state of a backend circuit breaker.
+type CircuitState string
+
+const (
+	// CircuitClosed indicates requests flow to the backend normally.
+	CircuitClosed CircuitState = "closed"
+
+	// CircuitOpen indicates the backend is failing and requests are rejected
+	// immediately without contacting the backend.
+	CircuitOpen CircuitState = "open"
+
+	// CircuitHalfOpen indicates the open timeout has elapsed (or a health check
+	// succeeded) and a single trial request is allowed through to probe the backend.
+	CircuitHalfOpen CircuitState = "half-open"
+)
+
+// CircuitBreakerConfig contains configuration for per-backend circuit breakers.
+type CircuitBreakerConfig struct {
+	// FailureThreshold is the number of consecutive failures before opening the circuit.
+	// Must be >= 1.
+	FailureThreshold int
+
+	// Timeout is how long the circuit stays open before allowing a trial request.
+	// Must be > 0.
+	Timeout time.Duration
+}
+
+// CircuitBreakerState is an immutable snapshot of a backend's circuit breaker.
+type CircuitBreakerState struct {
+	// State is the current circuit state.
+	State CircuitState `json:"state"`
+
+	// ConsecutiveFailures is the number of consecutive failures recorded.
+	ConsecutiveFailures int `json:"consecutive_failures"`
+
+	// OpenedAt is when the circuit last transitioned to open.
+	// Zero if the circuit has never been opened.
+	OpenedAt time.Time `json:"opened_at,omitempty"`
+
+	// RetryAt is the earliest time the circuit will allow a trial request.
+	// Only meaningful while the circuit is open.
+	RetryAt time.Time `json:"retry_at,omitempty"`
+}
+
+// StateChangeFunc is invoked when a backend's circuit transitions between states.
+// It is called without holding any circuit breaker locks.
+type StateChangeFunc func(backendID string, from, to CircuitState)
+
+// circuitBreaker tracks the state of a single backend.
+type circuitBreaker struct {
+	state               CircuitState
+	consecutiveFailures int
+	openedAt            time.Time
+
+	// trialInFlight is true while a half-open trial request is outstanding.
+	trialInFlight bool
+}
+
+// CircuitBreakers manages circuit breakers for multiple backends.
+// Breakers are created lazily on first use and are safe for concurrent use.
+//
+// Breakers are fed from two sources:
+//   - Backend call outcomes, via the client returned by NewCircuitBreakerClient
+//   - Health check outcomes, via Monitor.SetCircuitBreakers
+type CircuitBreakers struct {
+	mu       sync.Mutex
+	breakers map[string]*circuitBreaker
+	config   CircuitBreakerConfig
+
+	// now returns the current time. Overridable for testing.
+	now func() time.Time
+
+	// onStateChange is an optional callback for state transitions.
+	onStateChange StateChangeFunc
+}
+
+// NewCircuitBreakers creates a new set of per-backend circuit breakers.
+// Returns an error if the configuration is invalid.
+func NewCircuitBreakers(config CircuitBreakerConfig) (*CircuitBreakers, error) {
+	if config.FailureThreshold < 1 {
+		return nil, fmt.Errorf("circuit breaker failure threshold must be >= 1, got %d", config.FailureThreshold)
+	}
+	if config.Timeout <= 0 {
+		return nil, fmt.Errorf("circuit breaker timeout must be > 0, got %v", config.Timeout)
+	}
+
+	return &CircuitBreakers{
+		breakers: make(map[string]*circuitBreaker),
+		config:   config,
+		now:      time.Now,
+	}, nil
+}
+
+// OnStateChange registers a callback invoked on every state transition.
+// Must be called before the breakers are used concurrently.
+func (c *CircuitBreakers) OnStateChange(fn StateChangeFunc) {
+	c.onStateChange = fn
+}
+
+// getOrCreate returns the breaker for a backend, creating it if needed.
+// Caller must hold c.mu.
+func (c *CircuitBreakers) getOrCreate(backendID string) *circuitBreaker {
+	cb, exists := c.breakers[backendID]
+	if !exists {
+		cb = &circuitBreaker{state: CircuitClosed}
+		c.breakers[backendID] = cb
+	}
+	return cb
+}
+
+// transition moves a breaker to a new state and returns a function that
+// notifies the state change callback. The returned function must be called
+// after c.mu is released. Caller must hold c.mu.
+func (c *CircuitBreakers) transition(backendID string, cb *circuitBreaker, to CircuitState) func() {
+	from := cb.state
+	if from == to {
+		return func() {}
+	}
+
+	cb.state = to
+	cb.trialInFlight = false
+	if to == CircuitOpen {
+		cb.openedAt = c.now()
+	}
+
+	switch to {
+	case CircuitOpen:
+		logger.Warnf("Circuit breaker for backend %s opened: %s → %s (%d consecutive failures, retry in %v)",
+			backendID, from, to, cb.consecutiveFailures, c.config.Timeout)
+	case CircuitHalfOpen:
+		logger.Infof("Circuit breaker for backend %s half-open: %s → %s", backendID, from, to)
+	case CircuitClosed:
+		logger.Infof("Circuit breaker for backend %s closed: %s → %s", backendID, from, to)
+	}
+
+	fn := c.onStateChange
+	return func() {
+		if fn != nil {
+			fn(backendID, from, to)
+		}
+	}
+}
+
+// Allow reports whether a request to the backend may proceed.
+// Returns an error wrapping vmcp.ErrCircuitOpen if the circuit is open, or if it is
+// half-open and a trial request is already in flight.
+//
+// Every call that returns nil must be followed by exactly one call to
+// RecordSuccess, RecordFailure or RecordIgnored for the same backend.
+func (c *CircuitBreakers) Allow(backendID string) error {
+	c.mu.Lock()
+	cb := c.getOrCreate(backendID)
+
+	notify := func() {}
+	if cb.state == CircuitOpen && !c.now().Before(cb.openedAt.Add(c.config.Timeout)) {
+		notify = c.transition(backendID, cb, CircuitHalfOpen)
+	}
+
+	var err error
+	switch cb.state {
+	case CircuitClosed:
+		// Allowed
+	case CircuitHalfOpen:
+		if cb.trialInFlight {
+			err = fmt.Errorf("%w: backend %s is being probed, retry shortly", vmcp.ErrCircuitOpen, backendID)
+		} else {
+			cb.trialInFlight = true
+		}
+	case CircuitOpen:
+		retryIn := cb.openedAt.Add(c.config.Timeout).Sub(c.now()).Round(time.Second)
+		err = fmt.Errorf("%w: backend %s failed %d consecutive times, retry in %v",
+			vmcp.ErrCircuitOpen, backendID, cb.consecutiveFailures, retryIn)
+	}
+	c.mu.Unlock()
+
+	notify()
+	return err
+}
+
+// RecordSuccess records a successful call to the backend.
+// A success while half-open closes the circuit.
+func (c *CircuitBreakers) RecordSuccess(backendID string) {
+	c.mu.Lock()
+	cb := c.getOrCreate(backendID)
+	cb.consecutiveFailures = 0
+
+	notify := func() {}
+	if cb.state == CircuitHalfOpen {
+		notify = c.transition(backendID, cb, CircuitClosed)
+	}
+	c.mu.Unlock()
+
+	notify()
+}
+
+// RecordFailure records a failed call to the backend.
+// The circuit opens once the failure threshold is reached, or immediately if the
+// failure happened during a half-open trial.
+func (c *CircuitBreakers) RecordFailure(backendID string) {
+	c.mu.Lock()
+	cb := c.getOrCreate(backendID)
+	cb.consecutiveFailures++
+
+	notify := func() {}
+	switch cb.state {
+	case CircuitHalfOpen:
+		notify = c.transition(backendID, cb, CircuitOpen)
+	case CircuitClosed:
+		if cb.consecutiveFailures >= c.config.FailureThreshold {
+			notify = c.transition(backendID, cb, CircuitOpen)
+		}
+	case CircuitOpen:
+		// Already open - keep the original open timestamp
+	}
+	c.mu.Unlock()
+
+	notify()
+}
+
+// RecordIgnored releases a call admitted by Allow whose outcome says nothing
+// about backend availability (e.g. the caller cancelled the request).
+func (c *CircuitBreakers) RecordIgnored(backendID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if cb, exists := c.breakers[backendID]; exists && cb.state == CircuitHalfOpen {
+		cb.trialInFlight = false
+	}
+}
+
+// RecordHealthCheck feeds a health check result into the backend's breaker.
+//
+// A failed health check counts as a call failure. A successful health check moves
+// an open circuit to half-open without waiting for the timeout, so the next real
+// request probes the backend; it never closes the circuit on its own, because a
+// backend that answers capability listing may still hang on tool calls.
+func (c *CircuitBreakers) RecordHealthCheck(backendID string, status vmcp.BackendHealthStatus, err error) {
+	if err != nil {
+		// Authentication failures are not availability failures
+		if status == vmcp.BackendUnauthenticated {
+			return
+		}
+		c.RecordFailure(backendID)
+		return
+	}
+
+	c.mu.Lock()
+	cb := c.getOrCreate(backendID)
+
+	notify := func() {}
+	if cb.state == CircuitOpen {
+		notify = c.transition(backendID, cb, CircuitHalfOpen)
+	} else if cb.state == CircuitClosed {
+		cb.consecutiveFailures = 0
+	}
+	c.mu.Unlock()
+
+	notify()
+}
+
+// GetState returns the current state of a backend's breaker.
+// Backends that have never been seen are reported as closed.
+func (c *CircuitBreakers) GetState(backendID string) CircuitState {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cb, exists := c.breakers[backendID]
+	if !exists {
+		return CircuitClosed
+	}
+	return cb.state
+}
+
+// CheckHealth reports a backend whose circuit is open as unhealthy, so request routing
+// can avoid it without waiting for the call to fail fast. A half-open circuit, or an
+// open circuit whose timeout has elapsed, is reported as degraded so that it remains
+// eligible for a trial request. This lets the breakers act as a vmcp.HealthChecker.
+func (c *CircuitBreakers) CheckHealth(_ context.Context, target *vmcp.BackendTarget) (vmcp.BackendHealthStatus, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cb, exists := c.breakers[target.WorkloadID]
+	if !exists {
+		return vmcp.BackendHealthy, nil
+	}
+
+	switch cb.state {
+	case CircuitOpen:
+		if c.now().Before(cb.openedAt.Add(c.config.Timeout)) {
+			return vmcp.BackendUnhealthy, nil
+		}
+		return vmcp.BackendDegraded, nil
+	case CircuitHalfOpen:
+		return vmcp.BackendDegraded, nil
+	case CircuitClosed:
+		return vmcp.BackendHealthy, nil
+	}
+	return vmcp.BackendUnknown, nil
+}
+
+// GetAllStates returns a snapshot of all known breakers keyed by backend ID.
+func (c *CircuitBreakers) GetAllStates() map[string]CircuitBreakerState {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	result := make(map[string]CircuitBreakerState, len(c.breakers))
+	for backendID, cb := range c.breakers {
+		state := CircuitBreakerState{
+			State:               cb.state,
+			ConsecutiveFailures: cb.consecutiveFailures,
+			OpenedAt:            cb.openedAt,
+		}
+		if cb.state == CircuitOpen {
+			state.RetryAt = cb.openedAt.Add(c.config.Timeout)
+		}
+		result[backendID] = state
+	}
+	return result
+}
@@ -0,0 +1,109 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/stacklok/toolhive/pkg/vmcp"
+)
+
+// circuitBreakerClient decorates a vmcp.BackendClient with per-backend circuit breakers.
+// Calls to a backend whose circuit is open fail fast with an error wrapping both
+// vmcp.ErrBackendUnavailable and vmcp.ErrCircuitOpen.
+//
+// Health check requests (marked with WithHealthCheckMarker) bypass the breaker so the
+// health monitor can keep probing a backend while its circuit is open. Their results
+// are fed to the breakers by the Monitor instead.
+type circuitBreakerClient struct {
+	backendClient vmcp.BackendClient
+	breakers      *CircuitBreakers
+}
+
+var _ vmcp.BackendClient = (*circuitBreakerClient)(nil)
+
+// NewCircuitBreakerClient wraps a backend client with the given circuit breakers.
+func NewCircuitBreakerClient(backendClient vmcp.BackendClient, breakers *CircuitBreakers) vmcp.BackendClient {
+	return &circuitBreakerClient{
+		backendClient: backendClient,
+		breakers:      breakers,
+	}
+}
+
+// isCircuitFailure reports whether an error indicates the backend itself is failing.
+// Tool execution errors (the tool ran and reported an error), authentication errors
+// and caller cancellations do not count against the backend.
+func isCircuitFailure(err error) bool {
+	if errors.Is(err, vmcp.ErrToolExecutionFailed) ||
+		errors.Is(err, vmcp.ErrAuthenticationFailed) ||
+		errors.Is(err, vmcp.ErrAuthorizationFailed) ||
+		errors.Is(err, vmcp.ErrCancelled) ||
+		errors.Is(err, context.Canceled) {
+		return false
+	}
+	return true
+}
+
+// guard runs fn under the backend's circuit breaker and records its outcome.
+func guard[T any](
+	ctx context.Context, c *circuitBreakerClient, target *vmcp.BackendTarget, fn func() (T, error),
+) (T, error) {
+	if IsHealthCheck(ctx) {
+		return fn()
+	}
+
+	if err := c.breakers.Allow(target.WorkloadID); err != nil {
+		var zero T
+		return zero, fmt.Errorf("%w: %w", vmcp.ErrBackendUnavailable, err)
+	}
+
+	result, err := fn()
+	switch {
+	case err == nil:
+		c.breakers.RecordSuccess(target.WorkloadID)
+	case !isCircuitFailure(err):
+		if errors.Is(err, vmcp.ErrToolExecutionFailed) {
+			// The backend responded, so it is reachable
+			c.breakers.RecordSuccess(target.WorkloadID)
+		} else {
+			c.breakers.RecordIgnored(target.WorkloadID)
+		}
+	default:
+		c.breakers.RecordFailure(target.WorkloadID)
+	}
+	return result, err
+}
+
+// CallTool invokes a tool on the backend if its circuit allows it.
+func (c *circuitBreakerClient) CallTool(
+	ctx context.Context, target *vmcp.BackendTarget, toolName string, arguments map[string]any,
+) (map[string]any, error) {
+	return guard(ctx, c, target, func() (map[string]any, error) {
+		return c.backendClient.CallTool(ctx, target, toolName, arguments)
+	})
+}
+
+// ReadResource reads a resource from the backend if its circuit allows it.
+func (c *circuitBreakerClient) ReadResource(ctx context.Context, target *vmcp.BackendTarget, uri string) ([]byte, error) {
+	return guard(ctx, c, target, func() ([]byte, error) {
+		return c.backendClient.ReadResource(ctx, target, uri)
+	})
+}
+
+// GetPrompt retrieves a prompt from the backend if its circuit allows it.
+func (c *circuitBreakerClient) GetPrompt(
+	ctx context.Context, target *vmcp.BackendTarget, name string, arguments map[string]any,
+) (string, error) {
+	return guard(ctx, c, target, func() (string, error) {
+		return c.backendClient.GetPrompt(ctx, target, name, arguments)
+	})
+}
+
+// ListCapabilities queries the backend's capabilities if its circuit allows it.
+func (c *circuitBreakerClient) ListCapabilities(
+	ctx context.Context, target *vmcp.BackendTarget,
+) (*vmcp.CapabilityList, error) {
+	return guard(ctx, c, target, func() (*vmcp.CapabilityList, error) {
+		return c.backendClient.ListCapabilities(ctx, target)
+	})
+}
@@ -0,0 +1,162 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/stacklok/toolhive/pkg/vmcp"
+	"github.com/stacklok/toolhive/pkg/vmcp/mocks"
+)
+
+func TestCircuitBreakerClient_FailsFastWhenOpen(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+
+	mockClient := mocks.NewMockBackendClient(ctrl)
+	breakers, _ := newTestCircuitBreakers(t, 2, time.Minute)
+	client := NewCircuitBreakerClient(mockClient, breakers)
+
+	target := &vmcp.BackendTarget{WorkloadID: "backend-1", WorkloadName: "Backend 1"}
+	backendErr := fmt.Errorf("%w: connection refused", vmcp.ErrBackendUnavailable)
+
+	// Exactly two calls reach the backend; the third is rejected by the open circuit
+	mockClient.EXPECT().
+		CallTool(gomock.Any(), target, "fetch", gomock.Any()).
+		Return(nil, backendErr).
+		Times(2)
+
+	for i := 0; i < 2; i++ {
+		_, err := client.CallTool(context.Background(), target, "fetch", nil)
+		require.ErrorIs(t, err, vmcp.ErrBackendUnavailable)
+	}
+
+	_, err := client.CallTool(context.Background(), target, "fetch", nil)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, vmcp.ErrCircuitOpen))
+	assert.True(t, errors.Is(err, vmcp.ErrBackendUnavailable))
+}
+
+func TestCircuitBreakerClient_ErrorClassification(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		err           error
+		expectedState CircuitState
+	}{
+		{
+			name:          "backend unavailable opens circuit",
+			err:           fmt.Errorf("%w: connection refused", vmcp.ErrBackendUnavailable),
+			expectedState: CircuitOpen,
+		},
+		{
+			name:          "timeout opens circuit",
+			err:           fmt.Errorf("%w: deadline exceeded", vmcp.ErrTimeout),
+			expectedState: CircuitOpen,
+		},
+		{
+			name:          "tool execution error does not open circuit",
+			err:           fmt.Errorf("%w: bad input", vmcp.ErrToolExecutionFailed),
+			expectedState: CircuitClosed,
+		},
+		{
+			name:          "authentication error does not open circuit",
+			err:           fmt.Errorf("%w: 401", vmcp.ErrAuthenticationFailed),
+			expectedState: CircuitClosed,
+		},
+		{
+			name:          "cancellation does not open circuit",
+			err:           fmt.Errorf("%w: client went away", vmcp.ErrCancelled),
+			expectedState: CircuitClosed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			t.Cleanup(ctrl.Finish)
+
+			mockClient := mocks.NewMockBackendClient(ctrl)
+			breakers, _ := newTestCircuitBreakers(t, 1, time.Minute)
+			client := NewCircuitBreakerClient(mockClient, breakers)
+			target := &vmcp.BackendTarget{WorkloadID: "backend-1"}
+
+			mockClient.EXPECT().ReadResource(gomock.Any(), target, "file://x").Return(nil, tt.err)
+
+			_, err := client.ReadResource(context.Background(), target, "file://x")
+			require.ErrorIs(t, err, tt.err)
+			assert.Equal(t, tt.expectedState, breakers.GetState("backend-1"))
+		})
+	}
+}
+
+func TestCircuitBreakerClient_HealthChecksBypassBreaker(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+
+	mockClient := mocks.NewMockBackendClient(ctrl)
+	breakers, _ := newTestCircuitBreakers(t, 1, time.Hour)
+	client := NewCircuitBreakerClient(mockClient, breakers)
+	target := &vmcp.BackendTarget{WorkloadID: "backend-1"}
+
+	breakers.RecordFailure("backend-1")
+	require.Equal(t, CircuitOpen, breakers.GetState("backend-1"))
+
+	mockClient.EXPECT().
+		ListCapabilities(gomock.Any(), target).
+		Return(nil, fmt.Errorf("%w: still down", vmcp.ErrBackendUnavailable))
+
+	_, err := client.ListCapabilities(WithHealthCheckMarker(context.Background()), target)
+	require.Error(t, err)
+	assert.False(t, errors.Is(err, vmcp.ErrCircuitOpen), "health checks must reach the backend")
+
+	// Regular discovery calls are still rejected
+	_, err = client.ListCapabilities(context.Background(), target)
+	assert.True(t, errors.Is(err, vmcp.ErrCircuitOpen))
+}
+
+func TestMonitor_FeedsCircuitBreakers(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+
+	mockClient := mocks.NewMockBackendClient(ctrl)
+	backends := []vmcp.Backend{{ID: "backend-1", Name: "Backend 1", BaseURL: "http://localhost:8080"}}
+
+	mockClient.EXPECT().
+		ListCapabilities(gomock.Any(), gomock.Any()).
+		Return(nil, fmt.Errorf("%w: connection refused", vmcp.ErrBackendUnavailable)).
+		AnyTimes()
+
+	monitor, err := NewMonitor(mockClient, backends, MonitorConfig{
+		CheckInterval:      10 * time.Millisecond,
+		UnhealthyThreshold: 1,
+		Timeout:            time.Second,
+	})
+	require.NoError(t, err)
+
+	breakers, err := NewCircuitBreakers(CircuitBreakerConfig{FailureThreshold: 2, Timeout: time.Hour})
+	require.NoError(t, err)
+	monitor.SetCircuitBreakers(breakers)
+
+	require.NoError(t, monitor.Start(context.Background()))
+	t.Cleanup(func() { _ = monitor.Stop() })
+
+	require.Eventually(t, func() bool {
+		return breakers.GetState("backend-1") == CircuitOpen
+	}, 2*time.Second, 10*time.Millisecond)
+}
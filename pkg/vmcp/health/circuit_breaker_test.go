@@ -0,0 +1,284 @@
+package health
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stacklok/toolhive/pkg/vmcp"
+)
+
+// fakeClock is a manually advanced clock for circuit breaker tests.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func newTestCircuitBreakers(t *testing.T, threshold int, timeout time.Duration) (*CircuitBreakers, *fakeClock) {
+	t.Helper()
+	cb, err := NewCircuitBreakers(CircuitBreakerConfig{FailureThreshold: threshold, Timeout: timeout})
+	require.NoError(t, err)
+	clock := &fakeClock{now: time.Unix(1700000000, 0)}
+	cb.now = clock.Now
+	return cb, clock
+}
+
+func TestNewCircuitBreakers_Validation(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		config      CircuitBreakerConfig
+		expectError bool
+	}{
+		{
+			name:   "valid config",
+			config: CircuitBreakerConfig{FailureThreshold: 5, Timeout: time.Minute},
+		},
+		{
+			name:        "zero threshold",
+			config:      CircuitBreakerConfig{FailureThreshold: 0, Timeout: time.Minute},
+			expectError: true,
+		},
+		{
+			name:        "zero timeout",
+			config:      CircuitBreakerConfig{FailureThreshold: 5},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cb, err := NewCircuitBreakers(tt.config)
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Nil(t, cb)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, cb)
+			}
+		})
+	}
+}
+
+func TestCircuitBreakers_OpensAfterThreshold(t *testing.T) {
+	t.Parallel()
+
+	cb, _ := newTestCircuitBreakers(t, 3, time.Minute)
+
+	for i := 0; i < 2; i++ {
+		require.NoError(t, cb.Allow("backend-1"))
+		cb.RecordFailure("backend-1")
+		assert.Equal(t, CircuitClosed, cb.GetState("backend-1"))
+	}
+
+	require.NoError(t, cb.Allow("backend-1"))
+	cb.RecordFailure("backend-1")
+	assert.Equal(t, CircuitOpen, cb.GetState("backend-1"))
+
+	err := cb.Allow("backend-1")
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, vmcp.ErrCircuitOpen))
+
+	// Other backends are unaffected
+	assert.NoError(t, cb.Allow("backend-2"))
+	assert.Equal(t, CircuitClosed, cb.GetState("backend-2"))
+}
+
+func TestCircuitBreakers_SuccessResetsFailures(t *testing.T) {
+	t.Parallel()
+
+	cb, _ := newTestCircuitBreakers(t, 2, time.Minute)
+
+	cb.RecordFailure("backend-1")
+	cb.RecordSuccess("backend-1")
+	cb.RecordFailure("backend-1")
+
+	assert.Equal(t, CircuitClosed, cb.GetState("backend-1"))
+}
+
+func TestCircuitBreakers_HalfOpenTrial(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		trialSucceeds bool
+		expectedState CircuitState
+	}{
+		{name: "trial success closes circuit", trialSucceeds: true, expectedState: CircuitClosed},
+		{name: "trial failure reopens circuit", trialSucceeds: false, expectedState: CircuitOpen},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cb, clock := newTestCircuitBreakers(t, 1, time.Minute)
+			cb.RecordFailure("backend-1")
+			require.Equal(t, CircuitOpen, cb.GetState("backend-1"))
+
+			clock.Advance(30 * time.Second)
+			require.Error(t, cb.Allow("backend-1"), "circuit should stay open before timeout")
+
+			clock.Advance(30 * time.Second)
+			require.NoError(t, cb.Allow("backend-1"), "first request after timeout is the trial")
+			assert.Equal(t, CircuitHalfOpen, cb.GetState("backend-1"))
+
+			err := cb.Allow("backend-1")
+			require.Error(t, err, "only one trial request may be in flight")
+			assert.True(t, errors.Is(err, vmcp.ErrCircuitOpen))
+
+			if tt.trialSucceeds {
+				cb.RecordSuccess("backend-1")
+			} else {
+				cb.RecordFailure("backend-1")
+			}
+			assert.Equal(t, tt.expectedState, cb.GetState("backend-1"))
+		})
+	}
+}
+
+func TestCircuitBreakers_RecordIgnoredReleasesTrial(t *testing.T) {
+	t.Parallel()
+
+	cb, clock := newTestCircuitBreakers(t, 1, time.Minute)
+	cb.RecordFailure("backend-1")
+	clock.Advance(time.Minute)
+
+	require.NoError(t, cb.Allow("backend-1"))
+	cb.RecordIgnored("backend-1")
+
+	assert.Equal(t, CircuitHalfOpen, cb.GetState("backend-1"))
+	assert.NoError(t, cb.Allow("backend-1"), "a new trial is allowed after the previous one was ignored")
+}
+
+func TestCircuitBreakers_RecordHealthCheck(t *testing.T) {
+	t.Parallel()
+
+	t.Run("failed checks open the circuit", func(t *testing.T) {
+		t.Parallel()
+
+		cb, _ := newTestCircuitBreakers(t, 2, time.Minute)
+		cb.RecordHealthCheck("backend-1", vmcp.BackendUnhealthy, errors.New("connection refused"))
+		cb.RecordHealthCheck("backend-1", vmcp.BackendUnhealthy, errors.New("connection refused"))
+
+		assert.Equal(t, CircuitOpen, cb.GetState("backend-1"))
+	})
+
+	t.Run("authentication failures are ignored", func(t *testing.T) {
+		t.Parallel()
+
+		cb, _ := newTestCircuitBreakers(t, 1, time.Minute)
+		cb.RecordHealthCheck("backend-1", vmcp.BackendUnauthenticated, errors.New("401 unauthorized"))
+
+		assert.Equal(t, CircuitClosed, cb.GetState("backend-1"))
+	})
+
+	t.Run("successful check moves open circuit to half-open early", func(t *testing.T) {
+		t.Parallel()
+
+		cb, _ := newTestCircuitBreakers(t, 1, time.Hour)
+		cb.RecordFailure("backend-1")
+		require.Equal(t, CircuitOpen, cb.GetState("backend-1"))
+
+		cb.RecordHealthCheck("backend-1", vmcp.BackendHealthy, nil)
+
+		assert.Equal(t, CircuitHalfOpen, cb.GetState("backend-1"))
+		assert.NoError(t, cb.Allow("backend-1"))
+	})
+}
+
+func TestCircuitBreakers_StateChangeCallback(t *testing.T) {
+	t.Parallel()
+
+	cb, clock := newTestCircuitBreakers(t, 1, time.Minute)
+
+	type transition struct{ from, to CircuitState }
+	var transitions []transition
+	cb.OnStateChange(func(backendID string, from, to CircuitState) {
+		assert.Equal(t, "backend-1", backendID)
+		transitions = append(transitions, transition{from, to})
+	})
+
+	cb.RecordFailure("backend-1")
+	clock.Advance(time.Minute)
+	require.NoError(t, cb.Allow("backend-1"))
+	cb.RecordSuccess("backend-1")
+
+	assert.Equal(t, []transition{
+		{CircuitClosed, CircuitOpen},
+		{CircuitOpen, CircuitHalfOpen},
+		{CircuitHalfOpen, CircuitClosed},
+	}, transitions)
+}
+
+func TestCircuitBreakers_GetAllStates(t *testing.T) {
+	t.Parallel()
+
+	cb, clock := newTestCircuitBreakers(t, 1, time.Minute)
+	cb.RecordFailure("backend-1")
+	cb.RecordSuccess("backend-2")
+
+	states := cb.GetAllStates()
+	require.Len(t, states, 2)
+	assert.Equal(t, CircuitOpen, states["backend-1"].State)
+	assert.Equal(t, 1, states["backend-1"].ConsecutiveFailures)
+	assert.Equal(t, clock.Now().Add(time.Minute), states["backend-1"].RetryAt)
+	assert.Equal(t, CircuitClosed, states["backend-2"].State)
+	assert.True(t, states["backend-2"].RetryAt.IsZero())
+}
+
+func TestCircuitBreakers_CheckHealth(t *testing.T) {
+	t.Parallel()
+
+	cb, clock := newTestCircuitBreakers(t, 1, 30*time.Second)
+	target := &vmcp.BackendTarget{WorkloadID: "backend-1"}
+	ctx := t.Context()
+
+	// Unknown backends are healthy
+	status, err := cb.CheckHealth(ctx, target)
+	require.NoError(t, err)
+	assert.Equal(t, vmcp.BackendHealthy, status)
+
+	// Open circuit is unhealthy until the timeout elapses
+	cb.RecordFailure("backend-1")
+	status, err = cb.CheckHealth(ctx, target)
+	require.NoError(t, err)
+	assert.Equal(t, vmcp.BackendUnhealthy, status)
+
+	// Open circuit past the timeout is eligible for a trial
+	clock.Advance(30 * time.Second)
+	status, err = cb.CheckHealth(ctx, target)
+	require.NoError(t, err)
+	assert.Equal(t, vmcp.BackendDegraded, status)
+
+	// Half-open is degraded
+	require.NoError(t, cb.Allow("backend-1"))
+	status, err = cb.CheckHealth(ctx, target)
+	require.NoError(t, err)
+	assert.Equal(t, vmcp.BackendDegraded, status)
+
+	// Closed again after a successful trial
+	cb.RecordSuccess("backend-1")
+	status, err = cb.CheckHealth(ctx, target)
+	require.NoError(t, err)
+	assert.Equal(t, vmcp.BackendHealthy, status)
+}
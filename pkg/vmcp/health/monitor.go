@@ -66,6 +66,10 @@ type Monitor struct {
 
 	// stopped indicates if the monitor has been stopped (cannot be restarted).
 	stopped bool
+
+	// circuitBreakers receives health check results, if configured.
+	// Nil when circuit breaking is disabled.
+	circuitBreakers *CircuitBreakers
 }
 
 // MonitorConfig contains configuration for the health monitor.
@@ -134,6 +138,15 @@ func NewMonitor(
 	}, nil
 }
 
+// SetCircuitBreakers feeds health check results into the given circuit breakers.
+// Failed checks count as backend failures and successful checks let an open circuit
+// probe the backend early. Must be called before Start.
+func (m *Monitor) SetCircuitBreakers(breakers *CircuitBreakers) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.circuitBreakers = breakers
+}
+
 // Start begins health monitoring for all backends.
 // This spawns a background goroutine for each backend that performs periodic health checks.
 // Returns an error if the monitor is already started, has been stopped, or if the parent context is invalid.
@@ -256,6 +269,10 @@ func (m *Monitor) performHealthCheck(ctx context.Context, backend *vmcp.Backend)
 		// RecordSuccess will further check for recovering state (had recent failures)
 		m.statusTracker.RecordSuccess(backend.ID, backend.Name, status)
 	}
+
+	if m.circuitBreakers != nil {
+		m.circuitBreakers.RecordHealthCheck(backend.ID, status, err)
+	}
 }
 
 // GetBackendStatus returns the current health status for a backend.
@@ -290,6 +307,18 @@ func (m *Monitor) IsBackendHealthy(backendID string) bool {
 	return m.statusTracker.IsHealthy(backendID)
 }
 
+// CheckHealth reports the last observed health status of a backend without probing it.
+// This lets the monitor act as a vmcp.HealthChecker for request routing, where a live
+// health check per request would be too expensive. Backends that have not been checked
+// yet are reported as BackendUnknown.
+func (m *Monitor) CheckHealth(_ context.Context, target *vmcp.BackendTarget) (vmcp.BackendHealthStatus, error) {
+	status, exists := m.statusTracker.GetStatus(target.WorkloadID)
+	if !exists {
+		return vmcp.BackendUnknown, nil
+	}
+	return status, nil
+}
+
 // GetHealthSummary returns a summary of backend health for logging/monitoring.
 // Returns counts of healthy, degraded, unhealthy, and total backends.
 func (m *Monitor) GetHealthSummary() Summary {
@@ -0,0 +1,143 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	transportsession "github.com/stacklok/toolhive/pkg/transport/session"
+	"github.com/stacklok/toolhive/pkg/vmcp"
+)
+
+// SessionAffinityKey returns the key under which a session's backend is pinned within
+// a replica group. A session is pinned independently in each group it uses.
+func SessionAffinityKey(sessionID, group string) string {
+	return sessionID + "/" + group
+}
+
+// inMemorySessionAffinity keeps session affinity in process memory.
+// Suitable for single-instance deployments.
+type inMemorySessionAffinity struct {
+	mu      sync.RWMutex
+	targets map[string]*vmcp.BackendTarget
+}
+
+// NewInMemorySessionAffinity creates a session affinity provider backed by process memory.
+func NewInMemorySessionAffinity() SessionAffinityProvider {
+	return &inMemorySessionAffinity{
+		targets: make(map[string]*vmcp.BackendTarget),
+	}
+}
+
+// GetBackendForSession returns the pinned backend, or nil if the session is not pinned.
+func (p *inMemorySessionAffinity) GetBackendForSession(_ context.Context, sessionID string) (*vmcp.BackendTarget, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.targets[sessionID], nil
+}
+
+// SetBackendForSession pins a session to a backend, replacing any previous pin.
+func (p *inMemorySessionAffinity) SetBackendForSession(
+	_ context.Context, sessionID string, target *vmcp.BackendTarget,
+) error {
+	if sessionID == "" {
+		return fmt.Errorf("session ID cannot be empty")
+	}
+	if target == nil {
+		return fmt.Errorf("target cannot be nil")
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.targets[sessionID] = target
+	return nil
+}
+
+// RemoveSession clears a session's pin. It is not an error if the session is not pinned.
+func (p *inMemorySessionAffinity) RemoveSession(_ context.Context, sessionID string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.targets, sessionID)
+	return nil
+}
+
+const (
+	// affinityKeyPrefix namespaces affinity records in a storage shared with sessions.
+	affinityKeyPrefix = "vmcp-affinity:"
+
+	affinityWorkloadIDKey   = "workload_id"
+	affinityReplicaGroupKey = "replica_group"
+)
+
+// storageSessionAffinity keeps session affinity in a transport session storage backend,
+// so that every vMCP instance sharing the storage routes a session to the same backend.
+//
+// Only the backend's workload ID and replica group are stored. Callers must resolve the
+// returned target against the current routing table before using it.
+type storageSessionAffinity struct {
+	storage transportsession.Storage
+}
+
+// NewStorageSessionAffinity creates a session affinity provider backed by a session storage.
+// Affinity records are stored as sessions with a reserved ID prefix, and are refreshed on
+// every lookup so they expire together with idle client sessions.
+func NewStorageSessionAffinity(storage transportsession.Storage) SessionAffinityProvider {
+	return &storageSessionAffinity{storage: storage}
+}
+
+// GetBackendForSession returns the pinned backend, or nil if the session is not pinned.
+func (p *storageSessionAffinity) GetBackendForSession(ctx context.Context, sessionID string) (*vmcp.BackendTarget, error) {
+	record, err := p.storage.Load(ctx, affinityKeyPrefix+sessionID)
+	if errors.Is(err, transportsession.ErrSessionNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load session affinity: %w", err)
+	}
+
+	metadata := record.GetMetadata()
+	workloadID := metadata[affinityWorkloadIDKey]
+	if workloadID == "" {
+		return nil, nil
+	}
+
+	// Keep the record alive while the session is in use
+	record.Touch()
+	if err := p.storage.Store(ctx, record); err != nil {
+		return nil, fmt.Errorf("failed to refresh session affinity: %w", err)
+	}
+
+	return &vmcp.BackendTarget{
+		WorkloadID:   workloadID,
+		ReplicaGroup: metadata[affinityReplicaGroupKey],
+	}, nil
+}
+
+// SetBackendForSession pins a session to a backend, replacing any previous pin.
+func (p *storageSessionAffinity) SetBackendForSession(
+	ctx context.Context, sessionID string, target *vmcp.BackendTarget,
+) error {
+	if sessionID == "" {
+		return fmt.Errorf("session ID cannot be empty")
+	}
+	if target == nil {
+		return fmt.Errorf("target cannot be nil")
+	}
+
+	record := transportsession.NewProxySession(affinityKeyPrefix + sessionID)
+	record.SetMetadata(affinityWorkloadIDKey, target.WorkloadID)
+	record.SetMetadata(affinityReplicaGroupKey, target.ReplicaGroup)
+	if err := p.storage.Store(ctx, record); err != nil {
+		return fmt.Errorf("failed to store session affinity: %w", err)
+	}
+	return nil
+}
+
+// RemoveSession clears a session's pin. It is not an error if the session is not pinned.
+func (p *storageSessionAffinity) RemoveSession(ctx context.Context, sessionID string) error {
+	if err := p.storage.Delete(ctx, affinityKeyPrefix+sessionID); err != nil {
+		return fmt.Errorf("failed to remove session affinity: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+package router_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	transportsession "github.com/stacklok/toolhive/pkg/transport/session"
+	"github.com/stacklok/toolhive/pkg/vmcp"
+	vmcpmocks "github.com/stacklok/toolhive/pkg/vmcp/mocks"
+	"github.com/stacklok/toolhive/pkg/vmcp/router"
+	"github.com/stacklok/toolhive/pkg/vmcp/router/mocks"
+)
+
+// fakeClientSession is a minimal MCP client session carrying only an ID.
+type fakeClientSession struct {
+	id string
+}
+
+func (*fakeClientSession) Initialize()                                         {}
+func (*fakeClientSession) Initialized() bool                                   { return true }
+func (*fakeClientSession) NotificationChannel() chan<- mcp.JSONRPCNotification { return nil }
+func (s *fakeClientSession) SessionID() string                                 { return s.id }
+
+func withClientSession(ctx context.Context, sessionID string) context.Context {
+	return server.NewMCPServer("test", "1.0.0").WithContext(ctx, &fakeClientSession{id: sessionID})
+}
+
+func TestSessionAffinityProviders(t *testing.T) {
+	t.Parallel()
+
+	providers := map[string]func() router.SessionAffinityProvider{
+		"in-memory": router.NewInMemorySessionAffinity,
+		"storage": func() router.SessionAffinityProvider {
+			return router.NewStorageSessionAffinity(transportsession.NewLocalStorage())
+		},
+	}
+
+	for name, newProvider := range providers {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			provider := newProvider()
+			key := router.SessionAffinityKey("session-1", "fetch")
+
+			// Unpinned sessions return nil without error
+			target, err := provider.GetBackendForSession(ctx, key)
+			require.NoError(t, err)
+			assert.Nil(t, target)
+
+			require.NoError(t, provider.SetBackendForSession(ctx, key,
+				&vmcp.BackendTarget{WorkloadID: "fetch-a", ReplicaGroup: "fetch", BaseURL: "http://fetch-a"}))
+			target, err = provider.GetBackendForSession(ctx, key)
+			require.NoError(t, err)
+			require.NotNil(t, target)
+			assert.Equal(t, "fetch-a", target.WorkloadID)
+			assert.Equal(t, "fetch", target.ReplicaGroup)
+
+			// Re-pinning replaces the previous pin
+			require.NoError(t, provider.SetBackendForSession(ctx, key,
+				&vmcp.BackendTarget{WorkloadID: "fetch-b", ReplicaGroup: "fetch"}))
+			target, err = provider.GetBackendForSession(ctx, key)
+			require.NoError(t, err)
+			assert.Equal(t, "fetch-b", target.WorkloadID)
+
+			// Pins are scoped to the key
+			other, err := provider.GetBackendForSession(ctx, router.SessionAffinityKey("session-2", "fetch"))
+			require.NoError(t, err)
+			assert.Nil(t, other)
+
+			require.NoError(t, provider.RemoveSession(ctx, key))
+			target, err = provider.GetBackendForSession(ctx, key)
+			require.NoError(t, err)
+			assert.Nil(t, target)
+
+			// Removing an unpinned session is not an error
+			require.NoError(t, provider.RemoveSession(ctx, key))
+
+			assert.Error(t, provider.SetBackendForSession(ctx, "", &vmcp.BackendTarget{WorkloadID: "x"}))
+			assert.Error(t, provider.SetBackendForSession(ctx, key, nil))
+		})
+	}
+}
+
+func TestReplicaRouter_SessionAffinity(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	next := mocks.NewMockRouter(ctrl)
+	next.EXPECT().RouteTool(gomock.Any(), "tool").Return(newReplicaTarget("browser", "a", "b", "c"), nil).AnyTimes()
+
+	unhealthy := map[string]bool{}
+	checker := vmcpmocks.NewMockHealthChecker(ctrl)
+	checker.EXPECT().CheckHealth(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, target *vmcp.BackendTarget) (vmcp.BackendHealthStatus, error) {
+			if unhealthy[target.WorkloadID] {
+				return vmcp.BackendUnhealthy, nil
+			}
+			return vmcp.BackendHealthy, nil
+		}).AnyTimes()
+
+	provider := router.NewInMemorySessionAffinity()
+	r := router.NewReplicaRouter(next, nil,
+		router.WithHealthCheckers(checker),
+		router.WithSessionAffinity(provider, "browser"))
+
+	route := func(ctx context.Context) string {
+		t.Helper()
+		target, err := r.RouteTool(ctx, "tool")
+		require.NoError(t, err)
+		return target.WorkloadID
+	}
+
+	session1 := withClientSession(context.Background(), "session-1")
+	session2 := withClientSession(context.Background(), "session-2")
+
+	// Each session sticks to its first replica
+	pinned1 := route(session1)
+	pinned2 := route(session2)
+	assert.NotEqual(t, pinned1, pinned2, "round robin should place new sessions on different replicas")
+	for range 5 {
+		assert.Equal(t, pinned1, route(session1))
+		assert.Equal(t, pinned2, route(session2))
+	}
+
+	// Requests without a session are load balanced normally
+	seen := make(map[string]bool)
+	for range 3 {
+		seen[route(context.Background())] = true
+	}
+	assert.Len(t, seen, 3)
+
+	// An unhealthy pinned replica is replaced, and the new pin sticks
+	unhealthy[pinned1] = true
+	repinned := route(session1)
+	assert.NotEqual(t, pinned1, repinned)
+	stored, err := provider.GetBackendForSession(context.Background(), router.SessionAffinityKey("session-1", "browser"))
+	require.NoError(t, err)
+	assert.Equal(t, repinned, stored.WorkloadID)
+
+	unhealthy[pinned1] = false
+	assert.Equal(t, repinned, route(session1), "recovered replica should not steal pinned sessions")
+
+	// Releasing the session drops its pins
+	require.NoError(t, r.ReleaseSession(context.Background(), "session-1"))
+	stored, err = provider.GetBackendForSession(context.Background(), router.SessionAffinityKey("session-1", "browser"))
+	require.NoError(t, err)
+	assert.Nil(t, stored)
+}
+
+func TestReplicaRouter_SessionAffinityOnlyForConfiguredGroups(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	next := mocks.NewMockRouter(ctrl)
+	next.EXPECT().RouteTool(gomock.Any(), "tool").Return(newReplicaTarget("fetch", "a", "b"), nil).Times(2)
+
+	provider := mocks.NewMockSessionAffinityProvider(ctrl)
+	r := router.NewReplicaRouter(next, nil, router.WithSessionAffinity(provider, "browser"))
+
+	ctx := withClientSession(context.Background(), "session-1")
+	first, err := r.RouteTool(ctx, "tool")
+	require.NoError(t, err)
+	second, err := r.RouteTool(ctx, "tool")
+	require.NoError(t, err)
+	assert.NotEqual(t, first.WorkloadID, second.WorkloadID)
+}
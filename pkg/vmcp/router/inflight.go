@@ -0,0 +1,96 @@
+package router
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/stacklok/toolhive/pkg/vmcp"
+)
+
+// InFlightTracker counts outstanding requests per backend.
+// It is safe for concurrent use.
+type InFlightTracker struct {
+	counts sync.Map // map[string]*atomic.Int64
+}
+
+// NewInFlightTracker creates a new in-flight request tracker.
+func NewInFlightTracker() *InFlightTracker {
+	return &InFlightTracker{}
+}
+
+// counter returns the counter for a backend, creating it if needed.
+func (t *InFlightTracker) counter(backendID string) *atomic.Int64 {
+	if c, ok := t.counts.Load(backendID); ok {
+		return c.(*atomic.Int64)
+	}
+	c, _ := t.counts.LoadOrStore(backendID, &atomic.Int64{})
+	return c.(*atomic.Int64)
+}
+
+// Start records the start of a request to a backend.
+// The returned function must be called exactly once when the request completes.
+func (t *InFlightTracker) Start(backendID string) func() {
+	c := t.counter(backendID)
+	c.Add(1)
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { c.Add(-1) })
+	}
+}
+
+// Count returns the number of outstanding requests to a backend.
+func (t *InFlightTracker) Count(backendID string) int64 {
+	if c, ok := t.counts.Load(backendID); ok {
+		return c.(*atomic.Int64).Load()
+	}
+	return 0
+}
+
+// inFlightTrackingClient decorates a vmcp.BackendClient to count outstanding
+// requests per backend. Capability listing is not counted because it is used
+// for discovery and health checks rather than client traffic.
+type inFlightTrackingClient struct {
+	backendClient vmcp.BackendClient
+	tracker       *InFlightTracker
+}
+
+var _ vmcp.BackendClient = (*inFlightTrackingClient)(nil)
+
+// NewInFlightTrackingClient wraps a backend client so its requests are counted by the tracker.
+func NewInFlightTrackingClient(backendClient vmcp.BackendClient, tracker *InFlightTracker) vmcp.BackendClient {
+	return &inFlightTrackingClient{
+		backendClient: backendClient,
+		tracker:       tracker,
+	}
+}
+
+// CallTool invokes a tool on the backend while counting it as in flight.
+func (c *inFlightTrackingClient) CallTool(
+	ctx context.Context, target *vmcp.BackendTarget, toolName string, arguments map[string]any,
+) (map[string]any, error) {
+	defer c.tracker.Start(target.WorkloadID)()
+	return c.backendClient.CallTool(ctx, target, toolName, arguments)
+}
+
+// ReadResource reads a resource from the backend while counting it as in flight.
+func (c *inFlightTrackingClient) ReadResource(ctx context.Context, target *vmcp.BackendTarget, uri string) ([]byte, error) {
+	defer c.tracker.Start(target.WorkloadID)()
+	return c.backendClient.ReadResource(ctx, target, uri)
+}
+
+// GetPrompt retrieves a prompt from the backend while counting it as in flight.
+func (c *inFlightTrackingClient) GetPrompt(
+	ctx context.Context, target *vmcp.BackendTarget, name string, arguments map[string]any,
+) (string, error) {
+	defer c.tracker.Start(target.WorkloadID)()
+	return c.backendClient.GetPrompt(ctx, target, name, arguments)
+}
+
+// ListCapabilities queries the backend's capabilities without counting it as in flight.
+func (c *inFlightTrackingClient) ListCapabilities(
+	ctx context.Context, target *vmcp.BackendTarget,
+) (*vmcp.CapabilityList, error) {
+	return c.backendClient.ListCapabilities(ctx, target)
+}
@@ -0,0 +1,255 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/stacklok/toolhive/pkg/logger"
+	"github.com/stacklok/toolhive/pkg/vmcp"
+)
+
+// ReplicaRouter decorates a Router with load balancing across replica groups.
+//
+// The wrapped router resolves a capability to the replica group's primary target,
+// which lists the other replicas in BackendTarget.Replicas. The replica router
+// filters those candidates by health and lets the group's RoutingStrategy pick one.
+// Targets without replicas are returned unchanged.
+//
+// For groups with session affinity, the first replica selected for an MCP session is
+// pinned, and later requests in that session go to the same replica for as long as it
+// stays healthy. If the pinned replica becomes unavailable, the session is re-pinned to
+// a replica chosen by the group's strategy.
+type ReplicaRouter struct {
+	next Router
+
+	// strategies maps replica group names to their routing strategy.
+	strategies map[string]RoutingStrategy
+
+	// defaultStrategy is used for replica groups without a configured strategy.
+	defaultStrategy RoutingStrategy
+
+	// healthCheckers report the health of candidates. A candidate is excluded if any
+	// checker reports it as unhealthy or unauthenticated, or returns an error.
+	healthCheckers []vmcp.HealthChecker
+
+	// affinity stores session pins. Nil if no group uses session affinity.
+	affinity SessionAffinityProvider
+
+	// affinityGroups lists the replica groups that use session affinity.
+	affinityGroups map[string]bool
+}
+
+var _ Router = (*ReplicaRouter)(nil)
+
+// ReplicaRouterOption configures optional behavior of a ReplicaRouter.
+type ReplicaRouterOption func(*ReplicaRouter)
+
+// WithHealthCheckers sets the health sources used to filter candidates before selection.
+func WithHealthCheckers(checkers ...vmcp.HealthChecker) ReplicaRouterOption {
+	return func(r *ReplicaRouter) {
+		r.healthCheckers = append(r.healthCheckers, checkers...)
+	}
+}
+
+// WithSessionAffinity pins MCP sessions to a single replica in the given groups.
+func WithSessionAffinity(provider SessionAffinityProvider, groups ...string) ReplicaRouterOption {
+	return func(r *ReplicaRouter) {
+		r.affinity = provider
+		for _, group := range groups {
+			r.affinityGroups[group] = true
+		}
+	}
+}
+
+// NewReplicaRouter wraps a router with replica load balancing.
+//
+// Parameters:
+//   - next: Router that resolves capabilities to primary targets
+//   - strategies: Routing strategy per replica group name (groups not listed use round robin)
+//   - opts: Optional health checkers and session affinity
+func NewReplicaRouter(next Router, strategies map[string]RoutingStrategy, opts ...ReplicaRouterOption) *ReplicaRouter {
+	r := &ReplicaRouter{
+		next:            next,
+		strategies:      strategies,
+		defaultStrategy: NewRoundRobinStrategy(),
+		affinityGroups:  make(map[string]bool),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// RouteTool resolves a tool name to a backend target, selecting among replicas.
+func (r *ReplicaRouter) RouteTool(ctx context.Context, toolName string) (*vmcp.BackendTarget, error) {
+	target, err := r.next.RouteTool(ctx, toolName)
+	if err != nil {
+		return nil, err
+	}
+	return r.selectReplica(ctx, target)
+}
+
+// RouteResource resolves a resource URI to a backend target, selecting among replicas.
+func (r *ReplicaRouter) RouteResource(ctx context.Context, uri string) (*vmcp.BackendTarget, error) {
+	target, err := r.next.RouteResource(ctx, uri)
+	if err != nil {
+		return nil, err
+	}
+	return r.selectReplica(ctx, target)
+}
+
+// RoutePrompt resolves a prompt name to a backend target, selecting among replicas.
+func (r *ReplicaRouter) RoutePrompt(ctx context.Context, name string) (*vmcp.BackendTarget, error) {
+	target, err := r.next.RoutePrompt(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return r.selectReplica(ctx, target)
+}
+
+// candidates returns the target and its replicas.
+func candidates(target *vmcp.BackendTarget) []*vmcp.BackendTarget {
+	result := make([]*vmcp.BackendTarget, 0, len(target.Replicas)+1)
+	result = append(result, target)
+	return append(result, target.Replicas...)
+}
+
+// strategyFor returns the routing strategy for a replica group.
+func (r *ReplicaRouter) strategyFor(group string) RoutingStrategy {
+	if strategy, ok := r.strategies[group]; ok && strategy != nil {
+		return strategy
+	}
+	return r.defaultStrategy
+}
+
+// selectReplica picks one healthy target among a target and its replicas.
+func (r *ReplicaRouter) selectReplica(ctx context.Context, target *vmcp.BackendTarget) (*vmcp.BackendTarget, error) {
+	if len(target.Replicas) == 0 {
+		return target, nil
+	}
+
+	healthy := r.filterHealthy(ctx, candidates(target))
+	if len(healthy) == 0 {
+		return nil, fmt.Errorf("%w: replica group %s", ErrNoHealthyBackends, target.ReplicaGroup)
+	}
+
+	sessionID := r.affinitySessionID(ctx, target.ReplicaGroup)
+	if sessionID == "" {
+		return r.selectByStrategy(ctx, target, healthy)
+	}
+	return r.selectWithAffinity(ctx, target, healthy, sessionID)
+}
+
+// selectByStrategy picks a healthy replica using the group's routing strategy.
+func (r *ReplicaRouter) selectByStrategy(
+	ctx context.Context, target *vmcp.BackendTarget, healthy []*vmcp.BackendTarget,
+) (*vmcp.BackendTarget, error) {
+	selected, err := r.strategyFor(target.ReplicaGroup).SelectBackend(ctx, healthy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to select replica in group %s: %w", target.ReplicaGroup, err)
+	}
+
+	logger.Debugf("Selected replica %s in group %s (%d/%d healthy)",
+		selected.WorkloadID, target.ReplicaGroup, len(healthy), len(target.Replicas)+1)
+	return selected, nil
+}
+
+// selectWithAffinity returns the session's pinned replica if it is still healthy.
+// Otherwise it selects a replica by strategy and pins the session to it.
+func (r *ReplicaRouter) selectWithAffinity(
+	ctx context.Context, target *vmcp.BackendTarget, healthy []*vmcp.BackendTarget, sessionID string,
+) (*vmcp.BackendTarget, error) {
+	key := SessionAffinityKey(sessionID, target.ReplicaGroup)
+
+	pinned, err := r.affinity.GetBackendForSession(ctx, key)
+	if err != nil {
+		// Affinity is best effort: fall back to the strategy rather than failing the request
+		logger.Warnf("Failed to look up session affinity for group %s: %v", target.ReplicaGroup, err)
+	}
+	if pinned != nil {
+		for _, candidate := range healthy {
+			if candidate.WorkloadID == pinned.WorkloadID {
+				return candidate, nil
+			}
+		}
+	}
+
+	selected, err := r.selectByStrategy(ctx, target, healthy)
+	if err != nil {
+		return nil, err
+	}
+
+	if pinned != nil {
+		logger.Infof("Re-pinning session in replica group %s from unavailable replica %s to %s",
+			target.ReplicaGroup, pinned.WorkloadID, selected.WorkloadID)
+	}
+	if err := r.affinity.SetBackendForSession(ctx, key, selected); err != nil {
+		logger.Warnf("Failed to pin session to replica %s in group %s: %v",
+			selected.WorkloadID, target.ReplicaGroup, err)
+	}
+	return selected, nil
+}
+
+// affinitySessionID returns the MCP session ID of the request if the group uses
+// session affinity, or an empty string otherwise.
+func (r *ReplicaRouter) affinitySessionID(ctx context.Context, group string) string {
+	if r.affinity == nil || !r.affinityGroups[group] {
+		return ""
+	}
+	session := server.ClientSessionFromContext(ctx)
+	if session == nil {
+		return ""
+	}
+	return session.SessionID()
+}
+
+// ReleaseSession removes a session's pins in every replica group with session affinity.
+// It should be called when the MCP session ends or expires.
+func (r *ReplicaRouter) ReleaseSession(ctx context.Context, sessionID string) error {
+	if r.affinity == nil {
+		return nil
+	}
+
+	var errs []error
+	for group := range r.affinityGroups {
+		if err := r.affinity.RemoveSession(ctx, SessionAffinityKey(sessionID, group)); err != nil {
+			errs = append(errs, fmt.Errorf("replica group %s: %w", group, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// filterHealthy returns the candidates that no health checker reports as unavailable.
+// Backends with unknown health (not yet checked) are kept.
+func (r *ReplicaRouter) filterHealthy(ctx context.Context, all []*vmcp.BackendTarget) []*vmcp.BackendTarget {
+	if len(r.healthCheckers) == 0 {
+		return all
+	}
+
+	healthy := make([]*vmcp.BackendTarget, 0, len(all))
+	for _, candidate := range all {
+		if r.isAvailable(ctx, candidate) {
+			healthy = append(healthy, candidate)
+		}
+	}
+	return healthy
+}
+
+// isAvailable reports whether all health checkers consider the candidate usable.
+func (r *ReplicaRouter) isAvailable(ctx context.Context, candidate *vmcp.BackendTarget) bool {
+	for _, checker := range r.healthCheckers {
+		status, err := checker.CheckHealth(ctx, candidate)
+		if err != nil {
+			logger.Debugf("Excluding replica %s: health check error: %v", candidate.WorkloadID, err)
+			return false
+		}
+		if status == vmcp.BackendUnhealthy || status == vmcp.BackendUnauthenticated {
+			logger.Debugf("Excluding replica %s: status %s", candidate.WorkloadID, status)
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,149 @@
+package router_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/stacklok/toolhive/pkg/vmcp"
+	vmcpmocks "github.com/stacklok/toolhive/pkg/vmcp/mocks"
+	"github.com/stacklok/toolhive/pkg/vmcp/router"
+	"github.com/stacklok/toolhive/pkg/vmcp/router/mocks"
+)
+
+func newReplicaTarget(group string, ids ...string) *vmcp.BackendTarget {
+	primary := &vmcp.BackendTarget{WorkloadID: ids[0], ReplicaGroup: group}
+	for _, id := range ids[1:] {
+		primary.Replicas = append(primary.Replicas, &vmcp.BackendTarget{WorkloadID: id, ReplicaGroup: group})
+	}
+	return primary
+}
+
+func TestReplicaRouter_PassesThroughSingleTargets(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	next := mocks.NewMockRouter(ctrl)
+	target := &vmcp.BackendTarget{WorkloadID: "backend1"}
+	next.EXPECT().RouteTool(gomock.Any(), "tool").Return(target, nil)
+
+	// Targets without replicas never reach the health checkers
+	checker := vmcpmocks.NewMockHealthChecker(ctrl)
+
+	r := router.NewReplicaRouter(next, nil, router.WithHealthCheckers(checker))
+	got, err := r.RouteTool(context.Background(), "tool")
+	require.NoError(t, err)
+	assert.Same(t, target, got)
+}
+
+func TestReplicaRouter_PropagatesRouteErrors(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	next := mocks.NewMockRouter(ctrl)
+	next.EXPECT().RouteResource(gomock.Any(), "file:///x").Return(nil, router.ErrResourceNotFound)
+
+	r := router.NewReplicaRouter(next, nil)
+	_, err := r.RouteResource(context.Background(), "file:///x")
+	require.ErrorIs(t, err, router.ErrResourceNotFound)
+}
+
+func TestReplicaRouter_RoundRobinsAcrossReplicas(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	next := mocks.NewMockRouter(ctrl)
+	next.EXPECT().RoutePrompt(gomock.Any(), "prompt").
+		Return(newReplicaTarget("fetch", "a", "b", "c"), nil).Times(6)
+
+	r := router.NewReplicaRouter(next, nil)
+
+	var selected []string
+	for range 6 {
+		target, err := r.RoutePrompt(context.Background(), "prompt")
+		require.NoError(t, err)
+		assert.Equal(t, "fetch", target.ReplicaGroup)
+		selected = append(selected, target.WorkloadID)
+	}
+	assert.Equal(t, []string{"a", "b", "c", "a", "b", "c"}, selected)
+}
+
+func TestReplicaRouter_UsesGroupStrategy(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	next := mocks.NewMockRouter(ctrl)
+	next.EXPECT().RouteTool(gomock.Any(), "tool").Return(newReplicaTarget("fetch", "a", "b"), nil)
+
+	strategy := mocks.NewMockRoutingStrategy(ctrl)
+	strategy.EXPECT().SelectBackend(gomock.Any(), gomock.Len(2)).DoAndReturn(
+		func(_ context.Context, candidates []*vmcp.BackendTarget) (*vmcp.BackendTarget, error) {
+			return candidates[1], nil
+		})
+
+	r := router.NewReplicaRouter(next, map[string]router.RoutingStrategy{"fetch": strategy})
+	target, err := r.RouteTool(context.Background(), "tool")
+	require.NoError(t, err)
+	assert.Equal(t, "b", target.WorkloadID)
+}
+
+func TestReplicaRouter_SkipsUnavailableReplicas(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	next := mocks.NewMockRouter(ctrl)
+	next.EXPECT().RouteTool(gomock.Any(), "tool").
+		Return(newReplicaTarget("fetch", "unhealthy", "unauthenticated", "failing", "degraded", "healthy"), nil).
+		AnyTimes()
+
+	checker := vmcpmocks.NewMockHealthChecker(ctrl)
+	checker.EXPECT().CheckHealth(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, target *vmcp.BackendTarget) (vmcp.BackendHealthStatus, error) {
+			switch target.WorkloadID {
+			case "unhealthy":
+				return vmcp.BackendUnhealthy, nil
+			case "unauthenticated":
+				return vmcp.BackendUnauthenticated, nil
+			case "failing":
+				return vmcp.BackendUnknown, errors.New("check failed")
+			case "degraded":
+				return vmcp.BackendDegraded, nil
+			default:
+				return vmcp.BackendHealthy, nil
+			}
+		}).AnyTimes()
+
+	r := router.NewReplicaRouter(next, nil, router.WithHealthCheckers(checker))
+
+	seen := make(map[string]bool)
+	for range 4 {
+		target, err := r.RouteTool(context.Background(), "tool")
+		require.NoError(t, err)
+		seen[target.WorkloadID] = true
+	}
+	assert.Equal(t, map[string]bool{"degraded": true, "healthy": true}, seen)
+}
+
+func TestReplicaRouter_NoHealthyReplicas(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	next := mocks.NewMockRouter(ctrl)
+	next.EXPECT().RouteTool(gomock.Any(), "tool").Return(newReplicaTarget("fetch", "a", "b"), nil)
+
+	healthy := vmcpmocks.NewMockHealthChecker(ctrl)
+	healthy.EXPECT().CheckHealth(gomock.Any(), gomock.Any()).Return(vmcp.BackendHealthy, nil).AnyTimes()
+	unhealthy := vmcpmocks.NewMockHealthChecker(ctrl)
+	unhealthy.EXPECT().CheckHealth(gomock.Any(), gomock.Any()).Return(vmcp.BackendUnhealthy, nil).AnyTimes()
+
+	// A replica is excluded if any checker reports it as unavailable
+	r := router.NewReplicaRouter(next, nil, router.WithHealthCheckers(healthy, unhealthy))
+	target, err := r.RouteTool(context.Background(), "tool")
+	require.ErrorIs(t, err, router.ErrNoHealthyBackends)
+	assert.Contains(t, err.Error(), "fetch")
+	assert.Nil(t, target)
+}
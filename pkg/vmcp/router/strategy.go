@@ -0,0 +1,141 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"sync/atomic"
+
+	"github.com/stacklok/toolhive/pkg/vmcp"
+)
+
+// Routing strategy names, as used in AggregationConfig.ReplicaGroups.
+const (
+	// StrategyRoundRobin rotates through candidates in order.
+	StrategyRoundRobin = "round_robin"
+
+	// StrategyLeastInFlight picks the candidate with the fewest outstanding requests.
+	StrategyLeastInFlight = "least_in_flight"
+
+	// StrategyWeightedRandom picks a candidate at random, proportionally to its weight.
+	StrategyWeightedRandom = "weighted_random"
+)
+
+// NewRoutingStrategy creates a routing strategy by name.
+// An empty name selects round robin.
+//
+// Parameters:
+//   - name: Strategy name (round_robin, least_in_flight, weighted_random)
+//   - weights: Per-workload weights for weighted_random (missing workloads default to 1)
+//   - inFlight: Tracker of outstanding requests, required for least_in_flight
+func NewRoutingStrategy(name string, weights map[string]int, inFlight *InFlightTracker) (RoutingStrategy, error) {
+	switch name {
+	case "", StrategyRoundRobin:
+		return NewRoundRobinStrategy(), nil
+	case StrategyLeastInFlight:
+		if inFlight == nil {
+			return nil, fmt.Errorf("%s strategy requires an in-flight tracker", StrategyLeastInFlight)
+		}
+		return NewLeastInFlightStrategy(inFlight), nil
+	case StrategyWeightedRandom:
+		return NewWeightedRandomStrategy(weights), nil
+	default:
+		return nil, fmt.Errorf("unknown routing strategy: %s", name)
+	}
+}
+
+// roundRobinStrategy rotates through candidates.
+// The rotation counter is shared across calls, so with a stable candidate list
+// each candidate receives the same share of requests.
+type roundRobinStrategy struct {
+	next atomic.Uint64
+}
+
+// NewRoundRobinStrategy creates a strategy that rotates through candidates in order.
+func NewRoundRobinStrategy() RoutingStrategy {
+	return &roundRobinStrategy{}
+}
+
+// SelectBackend returns the next candidate in rotation.
+func (s *roundRobinStrategy) SelectBackend(_ context.Context, candidates []*vmcp.BackendTarget) (*vmcp.BackendTarget, error) {
+	if len(candidates) == 0 {
+		return nil, ErrNoHealthyBackends
+	}
+	n := s.next.Add(1) - 1
+	return candidates[n%uint64(len(candidates))], nil
+}
+
+// leastInFlightStrategy picks the candidate with the fewest outstanding requests.
+// Ties are broken in rotation so idle replicas share load evenly.
+type leastInFlightStrategy struct {
+	inFlight *InFlightTracker
+	next     atomic.Uint64
+}
+
+// NewLeastInFlightStrategy creates a strategy that picks the candidate with the fewest
+// outstanding requests, as counted by the given tracker.
+func NewLeastInFlightStrategy(inFlight *InFlightTracker) RoutingStrategy {
+	return &leastInFlightStrategy{inFlight: inFlight}
+}
+
+// SelectBackend returns the candidate with the fewest in-flight requests.
+func (s *leastInFlightStrategy) SelectBackend(_ context.Context, candidates []*vmcp.BackendTarget) (*vmcp.BackendTarget, error) {
+	if len(candidates) == 0 {
+		return nil, ErrNoHealthyBackends
+	}
+
+	offset := int((s.next.Add(1) - 1) % uint64(len(candidates)))
+	var selected *vmcp.BackendTarget
+	var lowest int64
+	for i := range candidates {
+		candidate := candidates[(offset+i)%len(candidates)]
+		count := s.inFlight.Count(candidate.WorkloadID)
+		if selected == nil || count < lowest {
+			selected = candidate
+			lowest = count
+		}
+	}
+	return selected, nil
+}
+
+// weightedRandomStrategy picks a candidate at random, proportionally to its weight.
+type weightedRandomStrategy struct {
+	weights map[string]int
+}
+
+// NewWeightedRandomStrategy creates a strategy that picks candidates at random,
+// proportionally to their weight. Workloads not present in weights, or with a
+// non-positive weight, have a weight of 1.
+func NewWeightedRandomStrategy(weights map[string]int) RoutingStrategy {
+	return &weightedRandomStrategy{weights: weights}
+}
+
+// weightOf returns the weight of a candidate.
+func (s *weightedRandomStrategy) weightOf(target *vmcp.BackendTarget) int {
+	if w, ok := s.weights[target.WorkloadID]; ok && w > 0 {
+		return w
+	}
+	return 1
+}
+
+// SelectBackend returns a random candidate, weighted by the configured weights.
+func (s *weightedRandomStrategy) SelectBackend(_ context.Context, candidates []*vmcp.BackendTarget) (*vmcp.BackendTarget, error) {
+	if len(candidates) == 0 {
+		return nil, ErrNoHealthyBackends
+	}
+
+	total := 0
+	for _, candidate := range candidates {
+		total += s.weightOf(candidate)
+	}
+
+	// Load balancing does not require a cryptographically secure source
+	pick := rand.IntN(total) //nolint:gosec // G404: not used for security purposes
+	for _, candidate := range candidates {
+		pick -= s.weightOf(candidate)
+		if pick < 0 {
+			return candidate, nil
+		}
+	}
+	return candidates[len(candidates)-1], nil
+}
@@ -0,0 +1,169 @@
+package router_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stacklok/toolhive/pkg/vmcp"
+	"github.com/stacklok/toolhive/pkg/vmcp/router"
+)
+
+func newCandidates(ids ...string) []*vmcp.BackendTarget {
+	targets := make([]*vmcp.BackendTarget, 0, len(ids))
+	for _, id := range ids {
+		targets = append(targets, &vmcp.BackendTarget{WorkloadID: id})
+	}
+	return targets
+}
+
+func TestNewRoutingStrategy(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		strategy      string
+		inFlight      *router.InFlightTracker
+		expectError   bool
+		errorContains string
+	}{
+		{name: "empty defaults to round robin", strategy: ""},
+		{name: "round robin", strategy: router.StrategyRoundRobin},
+		{name: "least in flight", strategy: router.StrategyLeastInFlight, inFlight: router.NewInFlightTracker()},
+		{name: "weighted random", strategy: router.StrategyWeightedRandom},
+		{
+			name:          "least in flight without tracker",
+			strategy:      router.StrategyLeastInFlight,
+			expectError:   true,
+			errorContains: "requires an in-flight tracker",
+		},
+		{
+			name:          "unknown strategy",
+			strategy:      "fastest",
+			expectError:   true,
+			errorContains: "unknown routing strategy",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			strategy, err := router.NewRoutingStrategy(tt.strategy, nil, tt.inFlight)
+			if tt.expectError {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorContains)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotNil(t, strategy)
+		})
+	}
+}
+
+func TestRoutingStrategies_NoCandidates(t *testing.T) {
+	t.Parallel()
+
+	strategies := map[string]router.RoutingStrategy{
+		"round_robin":     router.NewRoundRobinStrategy(),
+		"least_in_flight": router.NewLeastInFlightStrategy(router.NewInFlightTracker()),
+		"weighted_random": router.NewWeightedRandomStrategy(nil),
+	}
+
+	for name, strategy := range strategies {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			target, err := strategy.SelectBackend(context.Background(), nil)
+			require.ErrorIs(t, err, router.ErrNoHealthyBackends)
+			assert.Nil(t, target)
+		})
+	}
+}
+
+func TestRoundRobinStrategy_SelectBackend(t *testing.T) {
+	t.Parallel()
+
+	strategy := router.NewRoundRobinStrategy()
+	candidates := newCandidates("a", "b", "c")
+
+	var selected []string
+	for range 6 {
+		target, err := strategy.SelectBackend(context.Background(), candidates)
+		require.NoError(t, err)
+		selected = append(selected, target.WorkloadID)
+	}
+
+	assert.Equal(t, []string{"a", "b", "c", "a", "b", "c"}, selected)
+}
+
+func TestLeastInFlightStrategy_SelectBackend(t *testing.T) {
+	t.Parallel()
+
+	tracker := router.NewInFlightTracker()
+	strategy := router.NewLeastInFlightStrategy(tracker)
+	candidates := newCandidates("a", "b", "c")
+
+	doneA1 := tracker.Start("a")
+	doneA2 := tracker.Start("a")
+	doneC := tracker.Start("c")
+
+	// b is the only idle backend
+	for range 3 {
+		target, err := strategy.SelectBackend(context.Background(), candidates)
+		require.NoError(t, err)
+		assert.Equal(t, "b", target.WorkloadID)
+	}
+
+	// b is now busier than c
+	doneB1 := tracker.Start("b")
+	doneB2 := tracker.Start("b")
+	target, err := strategy.SelectBackend(context.Background(), candidates)
+	require.NoError(t, err)
+	assert.Equal(t, "c", target.WorkloadID)
+
+	doneA1()
+	doneA2()
+	doneA2() // Calling done more than once has no effect
+	doneB1()
+	doneB2()
+	doneC()
+	for _, id := range []string{"a", "b", "c"} {
+		assert.Equal(t, int64(0), tracker.Count(id))
+	}
+
+	// All idle: ties are broken in rotation
+	seen := make(map[string]bool)
+	for range 3 {
+		target, err := strategy.SelectBackend(context.Background(), candidates)
+		require.NoError(t, err)
+		seen[target.WorkloadID] = true
+	}
+	assert.Len(t, seen, 3)
+}
+
+func TestWeightedRandomStrategy_SelectBackend(t *testing.T) {
+	t.Parallel()
+
+	strategy := router.NewWeightedRandomStrategy(map[string]int{"heavy": 9, "off": 0})
+	candidates := newCandidates("heavy", "light")
+
+	counts := make(map[string]int)
+	const iterations = 2000
+	for range iterations {
+		target, err := strategy.SelectBackend(context.Background(), candidates)
+		require.NoError(t, err)
+		counts[target.WorkloadID]++
+	}
+
+	// heavy has weight 9, light defaults to 1: expect ~90% / ~10%
+	assert.InDelta(t, 0.9, float64(counts["heavy"])/iterations, 0.05)
+	assert.Positive(t, counts["light"])
+
+	// Non-positive weights default to 1
+	only, err := strategy.SelectBackend(context.Background(), newCandidates("off"))
+	require.NoError(t, err)
+	assert.Equal(t, "off", only.WorkloadID)
+}
@@ -100,6 +100,10 @@ func (f *DefaultHandlerFactory) CreateToolHandler(
 				logger.Debugf("Tool execution failed for %s: %v", toolName, err)
 				return mcp.NewToolResultError(err.Error()), nil
 			}
+			if errors.Is(err, vmcp.ErrCircuitOpen) {
+				logger.Debugf("Circuit open for tool %s: %v", toolName, err)
+				return mcp.NewToolResultError(fmt.Sprintf("Backend temporarily unavailable: %v", err)), nil
+			}
 			if errors.Is(err, vmcp.ErrBackendUnavailable) {
 				logger.Warnf("Backend unavailable for tool %s: %v", toolName, err)
 				return mcp.NewToolResultError(fmt.Sprintf("Backend unavailable: %v", err)), nil
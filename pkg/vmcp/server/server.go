@@ -20,12 +20,15 @@ import (
 	"github.com/stacklok/toolhive/pkg/audit"
 	"github.com/stacklok/toolhive/pkg/auth"
 	"github.com/stacklok/toolhive/pkg/logger"
+	"github.com/stacklok/toolhive/pkg/mcp"
+	"github.com/stacklok/toolhive/pkg/ratelimit"
 	"github.com/stacklok/toolhive/pkg/recovery"
 	"github.com/stacklok/toolhive/pkg/telemetry"
 	transportsession "github.com/stacklok/toolhive/pkg/transport/session"
 	"github.com/stacklok/toolhive/pkg/vmcp"
 	"github.com/stacklok/toolhive/pkg/vmcp/aggregator"
 	"github.com/stacklok/toolhive/pkg/vmcp/composer"
+	"github.com/stacklok/toolhive/pkg/vmcp/config"
 	"github.com/stacklok/toolhive/pkg/vmcp/discovery"
 	"github.com/stacklok/toolhive/pkg/vmcp/health"
 	"github.com/stacklok/toolhive/pkg/vmcp/router"
@@ -52,9 +55,9 @@ const (
 	// defaultShutdownTimeout is the maximum time to wait for graceful shutdown.
 	defaultShutdownTimeout = 10 * time.Second
 
-	// defaultSessionTTL is the default session time-to-live duration.
+	// DefaultSessionTTL is the default session time-to-live duration.
 	// Sessions that are inactive for this duration will be automatically cleaned up.
-	defaultSessionTTL = 30 * time.Minute
+	DefaultSessionTTL = 30 * time.Minute
 )
 
 //go:generate mockgen -destination=mocks/mock_watcher.go -package=mocks -source=server.go Watcher
@@ -111,10 +114,37 @@ type Config struct {
 	// Component should be set to "vmcp-server" to distinguish vMCP audit logs.
 	AuditConfig *audit.Config
 
+	// RateLimitConfig is the optional rate limit configuration.
+	// If nil, requests are not rate limited.
+	RateLimitConfig *ratelimit.Config
+
 	// HealthMonitorConfig is the optional health monitoring configuration.
 	// If nil, health monitoring is disabled.
 	HealthMonitorConfig *health.MonitorConfig
 
+	// CircuitBreakerConfig is the optional per-backend circuit breaker configuration.
+	// If nil, circuit breaking is disabled.
+	CircuitBreakerConfig *health.CircuitBreakerConfig
+
+	// ReplicaGroups is the optional list of backend replica groups to load balance across.
+	// If empty, every capability is routed to the single backend that serves it.
+	ReplicaGroups []*config.ReplicaGroupConfig
+
+	// SessionAffinityProvider stores session pins for replica groups with session affinity.
+	// If nil and any group enables session affinity, an in-memory provider is used.
+	SessionAffinityProvider router.SessionAffinityProvider
+
+	// SessionStorage is the optional session storage shared by vMCP replicas.
+	// When set, client sessions are recorded in it, and a replica that receives a request
+	// for a session initialized on another replica restores the session instead of
+	// rejecting it. Session affinity pins in the storage expire through the storage TTL.
+	SessionStorage transportsession.Storage
+
+	// WorkflowStateStore stores composite tool workflow state.
+	// If nil, an in-memory store is used and interrupted workflows cannot be resumed.
+	// When set, active workflows found in the store are recovered at startup.
+	WorkflowStateStore composer.WorkflowStateStore
+
 	// Watcher is the optional Kubernetes backend watcher for dynamic mode.
 	// Only set when running in K8s with outgoingAuth.source: discovered.
 	// Used for /readyz endpoint to gate readiness on cache sync.
@@ -162,6 +192,9 @@ type Server struct {
 	// The SDK does NOT manage sessions itself - it only provides the interface.
 	sessionManager *transportsession.Manager
 
+	// Store of client sessions shared by vMCP replicas, nil unless Config.SessionStorage is set
+	sharedSessions *sharedSessionStore
+
 	// Capability adapter for converting aggregator types to SDK types
 	capabilityAdapter *adapter.CapabilityAdapter
 
@@ -187,6 +220,19 @@ type Server struct {
 	// Lock for writes (initialization, disabling on start failure).
 	healthMonitor   *health.Monitor
 	healthMonitorMu sync.RWMutex
+
+	// circuitBreakers tracks per-backend circuit breaker state.
+	// Nil if circuit breaking is disabled. Safe for concurrent use.
+	circuitBreakers *health.CircuitBreakers
+
+	// rateLimiter enforces the configured rate limits on MCP requests.
+	// Nil if rate limiting is disabled.
+	rateLimiter *ratelimit.Limiter
+
+	// auditor and workflowAuditor write the audit events to the audit log and sinks.
+	// Nil if audit logging is disabled.
+	auditor         *audit.Auditor
+	workflowAuditor *audit.WorkflowAuditor
 }
 
 // New creates a new Virtual MCP Server instance.
@@ -221,7 +267,7 @@ func New(
 		cfg.Version = "0.1.0"
 	}
 	if cfg.SessionTTL == 0 {
-		cfg.SessionTTL = defaultSessionTTL
+		cfg.SessionTTL = DefaultSessionTTL
 	}
 
 	// Create hooks for SDK integration
@@ -244,6 +290,36 @@ func New(
 	// This provides SDK-agnostic elicitation with security validation
 	elicitationHandler := composer.NewDefaultElicitationHandler(sdkElicitationRequester)
 
+	// Decorate backend client with circuit breakers if configured.
+	// This happens before telemetry decoration so that fast-failed calls are
+	// still counted as backend errors in metrics and traces.
+	var circuitBreakers *health.CircuitBreakers
+	if cfg.CircuitBreakerConfig != nil {
+		var err error
+		circuitBreakers, err = health.NewCircuitBreakers(*cfg.CircuitBreakerConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create circuit breakers: %w", err)
+		}
+		backendClient = health.NewCircuitBreakerClient(backendClient, circuitBreakers)
+		logger.Infow("Circuit breaker enabled",
+			"failure_threshold", cfg.CircuitBreakerConfig.FailureThreshold,
+			"timeout", cfg.CircuitBreakerConfig.Timeout)
+
+		if cfg.TelemetryProvider != nil {
+			if err := monitorCircuitBreakers(cfg.TelemetryProvider.MeterProvider(), circuitBreakers); err != nil {
+				return nil, fmt.Errorf("failed to monitor circuit breakers: %w", err)
+			}
+		}
+	}
+
+	// Count in-flight requests per backend for least-in-flight replica routing.
+	// This wraps the circuit breaker so that fast-failed calls are not counted.
+	var inFlight *router.InFlightTracker
+	if len(cfg.ReplicaGroups) > 0 {
+		inFlight = router.NewInFlightTracker()
+		backendClient = router.NewInFlightTrackingClient(backendClient, inFlight)
+	}
+
 	// Decorate backend client with telemetry if provider is configured
 	// This must happen BEFORE creating the workflow engine so that workflow
 	// backend calls are instrumented when they occur during workflow execution.
@@ -263,6 +339,42 @@ func New(
 		}
 	}
 
+	// Create health monitor if configured
+	var healthMon *health.Monitor
+	if cfg.HealthMonitorConfig != nil {
+		// Get initial backends list from registry for health monitoring setup
+		initialBackends := backendRegistry.List(ctx)
+		var err error
+		healthMon, err = health.NewMonitor(backendClient, initialBackends, *cfg.HealthMonitorConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create health monitor: %w", err)
+		}
+		if circuitBreakers != nil {
+			healthMon.SetCircuitBreakers(circuitBreakers)
+		}
+		logger.Infow("Health monitoring enabled",
+			"check_interval", cfg.HealthMonitorConfig.CheckInterval,
+			"unhealthy_threshold", cfg.HealthMonitorConfig.UnhealthyThreshold,
+			"timeout", cfg.HealthMonitorConfig.Timeout,
+			"degraded_threshold", cfg.HealthMonitorConfig.DegradedThreshold)
+	} else {
+		logger.Info("Health monitoring disabled")
+	}
+
+	// Load balance across replica groups if configured.
+	// This must happen BEFORE creating the workflow engine and handler factory so
+	// that both route through the replica router.
+	var replicaRouter *router.ReplicaRouter
+	if len(cfg.ReplicaGroups) > 0 {
+		var err error
+		replicaRouter, err = newReplicaRouter(rt, cfg, inFlight, healthMon, circuitBreakers)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create replica router: %w", err)
+		}
+		rt = replicaRouter
+		logger.Infow("Replica load balancing enabled", "groups", len(cfg.ReplicaGroups))
+	}
+
 	// Create workflow auditor if audit config is provided
 	var workflowAuditor *audit.WorkflowAuditor
 	if cfg.AuditConfig != nil {
@@ -276,10 +388,23 @@ func New(
 
 	// Create workflow engine (composer) for executing composite tools
 	// The composer orchestrates multi-step workflows across backends
-	// Use in-memory state store with 5-minute cleanup interval and 1-hour max age for completed workflows
-	stateStore := composer.NewInMemoryStateStore(5*time.Minute, 1*time.Hour)
+	// Default to an in-memory state store with 5-minute cleanup interval and 1-hour max age for completed workflows
+	stateStore := cfg.WorkflowStateStore
+	if stateStore == nil {
+		stateStore = composer.NewInMemoryStateStore(5*time.Minute, 1*time.Hour)
+	}
 	workflowComposer := composer.NewWorkflowEngine(rt, backendClient, elicitationHandler, stateStore, workflowAuditor)
 
+	// Recover workflows interrupted by a previous shutdown so they can be resumed
+	if recoverer, ok := workflowComposer.(composer.WorkflowRecoverer); ok && cfg.WorkflowStateStore != nil {
+		recovered, err := recoverer.RecoverWorkflows(ctx)
+		if err != nil {
+			logger.Warnf("Failed to recover interrupted workflows: %v", err)
+		} else if recovered > 0 {
+			logger.Infof("Recovered %d interrupted workflow(s)", recovered)
+		}
+	}
+
 	// Validate workflows and create executors (fail fast on invalid workflows)
 	workflowDefs, workflowExecutors, err := validateAndCreateExecutors(workflowComposer, workflowDefs)
 	if err != nil {
@@ -302,30 +427,24 @@ func New(
 	// This enables type-safe access to routing tables while maintaining session lifecycle management
 	sessionManager := transportsession.NewManager(cfg.SessionTTL, vmcpsession.VMCPSessionFactory())
 
+	// Release replica session pins when client sessions expire. Pins in a shared session
+	// storage are left to expire through the storage TTL, as other replicas may still use them.
+	if replicaRouter != nil && cfg.SessionStorage == nil {
+		sessionManager.OnExpire(func(sessionID string) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := replicaRouter.ReleaseSession(ctx, sessionID); err != nil {
+				logger.Warnw("failed to release session affinity", "session_id", sessionID, "error", err)
+			}
+		})
+	}
+
 	// Create handler factory (used by adapter and for future dynamic registration)
 	handlerFactory := adapter.NewDefaultHandlerFactory(rt, backendClient)
 
 	// Create capability adapter (single source of truth for converting aggregator types to SDK types)
 	capabilityAdapter := adapter.NewCapabilityAdapter(handlerFactory)
 
-	// Create health monitor if configured
-	var healthMon *health.Monitor
-	if cfg.HealthMonitorConfig != nil {
-		// Get initial backends list from registry for health monitoring setup
-		initialBackends := backendRegistry.List(ctx)
-		healthMon, err = health.NewMonitor(backendClient, initialBackends, *cfg.HealthMonitorConfig)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create health monitor: %w", err)
-		}
-		logger.Infow("Health monitoring enabled",
-			"check_interval", cfg.HealthMonitorConfig.CheckInterval,
-			"unhealthy_threshold", cfg.HealthMonitorConfig.UnhealthyThreshold,
-			"timeout", cfg.HealthMonitorConfig.Timeout,
-			"degraded_threshold", cfg.HealthMonitorConfig.DegradedThreshold)
-	} else {
-		logger.Info("Health monitoring disabled")
-	}
-
 	// Create Server instance
 	srv := &Server{
 		config:            cfg,
@@ -341,6 +460,14 @@ func New(
 		workflowExecutors: workflowExecutors,
 		ready:             make(chan struct{}),
 		healthMonitor:     healthMon,
+		circuitBreakers:   circuitBreakers,
+		workflowAuditor:   workflowAuditor,
+	}
+
+	// Record client sessions in the shared session storage so that other replicas can restore them
+	if cfg.SessionStorage != nil {
+		srv.sharedSessions = &sharedSessionStore{storage: cfg.SessionStorage}
+		hooks.AddAfterInitialize(srv.recordSharedSession)
 	}
 
 	// Register OnRegisterSession hook to inject capabilities after SDK registers session.
@@ -440,13 +567,19 @@ func (s *Server) Start(ctx context.Context) error {
 	// Sessions are ENTIRELY managed by ToolHive's session.Manager (storage, TTL, cleanup).
 	// The SDK only calls our Generate/Validate/Terminate methods during MCP protocol flows.
 	sessionAdapter := newSessionIDAdapter(s.sessionManager)
+	sessionAdapter.sharedSessions = s.sharedSessions
 
 	// Create Streamable HTTP server with ToolHive session management
-	streamableServer := server.NewStreamableHTTPServer(
-		s.mcpServer,
+	streamableOpts := []server.StreamableHTTPOption{
 		server.WithEndpointPath(s.config.EndpointPath),
 		server.WithSessionIdManager(sessionAdapter),
-	)
+	}
+	if s.sharedSessions != nil {
+		// Lets initialize requests replayed to restore a session reuse the session's ID
+		streamableOpts = append(streamableOpts,
+			server.WithSessionIdManagerResolver(&sessionIDManagerResolver{adapter: sessionAdapter}))
+	}
+	streamableServer := server.NewStreamableHTTPServer(s.mcpServer, streamableOpts...)
 
 	// Create HTTP mux with separated authenticated and unauthenticated routes
 	mux := http.NewServeMux()
@@ -476,8 +609,8 @@ func (s *Server) Start(ctx context.Context) error {
 	}
 
 	// MCP endpoint - apply middleware chain (wrapping order, execution happens in reverse):
-	// Code wraps: auth → audit → discovery → backend enrichment → telemetry
-	// Execution order: telemetry → backend enrichment → discovery → audit → auth → handler
+	// Code wraps: auth → audit → rate limit → session restore → discovery → backend enrichment → telemetry
+	// Execution order: telemetry → backend enrichment → discovery → session restore → rate limit → audit → auth → handler
 	var mcpHandler http.Handler = streamableServer
 
 	if s.config.TelemetryProvider != nil {
@@ -499,6 +632,25 @@ func (s *Server) Start(ctx context.Context) error {
 	mcpHandler = discovery.Middleware(s.discoveryMgr, s.backendRegistry, s.sessionManager)(mcpHandler)
 	logger.Info("Discovery middleware enabled for lazy per-user capability discovery")
 
+	// Apply session restore middleware if sessions are shared (runs before discovery, so that the
+	// replayed initialize request of a restored session goes through capability discovery)
+	if s.sharedSessions != nil {
+		mcpHandler = s.sessionRestoreMiddleware(mcpHandler)
+		logger.Info("Session restore middleware enabled for sessions shared by vMCP replicas")
+	}
+
+	// Apply rate limit middleware if configured (runs after audit so that rejected requests
+	// are audited, and before discovery so that rejected requests do not reach backends)
+	if s.config.RateLimitConfig != nil {
+		limiter, err := ratelimit.NewLimiter(s.config.RateLimitConfig, s.config.Name)
+		if err != nil {
+			return fmt.Errorf("failed to create rate limiter: %w", err)
+		}
+		s.rateLimiter = limiter
+		mcpHandler = mcp.ParsingMiddleware(limiter.Middleware(mcpHandler))
+		logger.Info("Rate limit middleware enabled for MCP endpoints")
+	}
+
 	// Apply audit middleware if configured (runs after auth, before discovery)
 	if s.config.AuditConfig != nil {
 		auditor, err := audit.NewAuditorWithTransport(
@@ -508,6 +660,7 @@ func (s *Server) Start(ctx context.Context) error {
 		if err != nil {
 			return fmt.Errorf("failed to create auditor: %w", err)
 		}
+		s.auditor = auditor
 		mcpHandler = auditor.Middleware(mcpHandler)
 		logger.Info("Audit middleware enabled for MCP endpoints")
 	}
@@ -637,6 +790,25 @@ func (s *Server) Stop(ctx context.Context) error {
 		s.discoveryMgr.Stop()
 	}
 
+	// Stop removing idle rate limit buckets
+	if s.rateLimiter != nil {
+		if err := s.rateLimiter.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to stop rate limiter: %w", err))
+		}
+	}
+
+	// Flush the audit sinks after the HTTP server no longer produces audit events
+	if s.auditor != nil {
+		if err := s.auditor.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to close auditor: %w", err))
+		}
+	}
+	if s.workflowAuditor != nil {
+		if err := s.workflowAuditor.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to close workflow auditor: %w", err))
+		}
+	}
+
 	if len(errs) > 0 {
 		logger.Errorf("Errors during shutdown: %v", errs)
 		return errors.Join(errs...)
@@ -941,6 +1113,15 @@ func (s *Server) GetHealthSummary() health.Summary {
 	return healthMon.GetHealthSummary()
 }
 
+// GetCircuitBreakerStates returns the circuit breaker state of all backends that have been called.
+// Returns nil if circuit breaking is disabled.
+func (s *Server) GetCircuitBreakerStates() map[string]health.CircuitBreakerState {
+	if s.circuitBreakers == nil {
+		return nil
+	}
+	return s.circuitBreakers.GetAllStates()
+}
+
 // BackendHealthResponse represents the health status response for all backends.
 type BackendHealthResponse struct {
 	// MonitoringEnabled indicates if health monitoring is active.
@@ -953,6 +1134,10 @@ type BackendHealthResponse struct {
 	// Backends contains the detailed health state of each backend.
 	// Only populated if MonitoringEnabled is true.
 	Backends map[string]*health.State `json:"backends,omitempty"`
+
+	// CircuitBreakers contains the circuit breaker state of each backend.
+	// Only populated if circuit breaking is enabled.
+	CircuitBreakers map[string]health.CircuitBreakerState `json:"circuit_breakers,omitempty"`
 }
 
 // handleBackendHealth handles /api/backends/health HTTP requests.
@@ -974,6 +1159,7 @@ func (s *Server) handleBackendHealth(w http.ResponseWriter, _ *http.Request) {
 		response.Summary = &summary
 		response.Backends = s.GetAllBackendHealthStates()
 	}
+	response.CircuitBreakers = s.GetCircuitBreakerStates()
 
 	// Encode response before writing headers to ensure encoding succeeds
 	data, err := json.Marshal(response)
@@ -989,3 +1175,46 @@ func (s *Server) handleBackendHealth(w http.ResponseWriter, _ *http.Request) {
 		logger.Errorf("Failed to write backend health response: %v", err)
 	}
 }
+
+// newReplicaRouter wraps a router with load balancing across the configured replica groups.
+// The health monitor and circuit breakers, when enabled, are used to skip unavailable replicas.
+func newReplicaRouter(
+	rt router.Router,
+	cfg *Config,
+	inFlight *router.InFlightTracker,
+	healthMon *health.Monitor,
+	circuitBreakers *health.CircuitBreakers,
+) (*router.ReplicaRouter, error) {
+	strategies := make(map[string]router.RoutingStrategy, len(cfg.ReplicaGroups))
+	var affinityGroups []string
+	for _, group := range cfg.ReplicaGroups {
+		strategy, err := router.NewRoutingStrategy(group.Strategy, group.Weights, inFlight)
+		if err != nil {
+			return nil, fmt.Errorf("replica group %s: %w", group.Name, err)
+		}
+		strategies[group.Name] = strategy
+		if group.SessionAffinity {
+			affinityGroups = append(affinityGroups, group.Name)
+		}
+	}
+
+	var checkers []vmcp.HealthChecker
+	if healthMon != nil {
+		checkers = append(checkers, healthMon)
+	}
+	if circuitBreakers != nil {
+		checkers = append(checkers, circuitBreakers)
+	}
+	opts := []router.ReplicaRouterOption{router.WithHealthCheckers(checkers...)}
+
+	if len(affinityGroups) > 0 {
+		provider := cfg.SessionAffinityProvider
+		if provider == nil {
+			provider = router.NewInMemorySessionAffinity()
+		}
+		opts = append(opts, router.WithSessionAffinity(provider, affinityGroups...))
+		logger.Infow("Session affinity enabled", "groups", affinityGroups)
+	}
+
+	return router.NewReplicaRouter(rt, strategies, opts...), nil
+}
@@ -1,7 +1,9 @@
 package server
 
 import (
+	"context"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -27,6 +29,10 @@ import (
 // server bounded context per DDD principles.
 type sessionIDAdapter struct {
 	manager *session.Manager
+
+	// sharedSessions is the optional store of sessions shared by vMCP replicas.
+	// Terminated sessions are marked there so that no replica restores them.
+	sharedSessions *sharedSessionStore
 }
 
 // newSessionIDAdapter creates an adapter that bridges session.Manager
@@ -126,6 +132,15 @@ func (a *sessionIDAdapter) Terminate(sessionID string) (isNotAllowed bool, err e
 		return false, fmt.Errorf("empty session ID")
 	}
 
+	// Mark the session as terminated for other replicas, even if this replica does not know it
+	if a.sharedSessions != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := a.sharedSessions.terminate(ctx, sessionID); err != nil {
+			logger.Warnf("Failed to terminate shared session %s: %v", sessionID, err)
+		}
+	}
+
 	// Get the session to mark it as terminated
 	sess, exists := a.manager.Get(sessionID)
 	if !exists {
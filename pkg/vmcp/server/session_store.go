@@ -0,0 +1,260 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"golang.org/x/sync/singleflight"
+
+	"github.com/stacklok/toolhive/pkg/logger"
+	transportsession "github.com/stacklok/toolhive/pkg/transport/session"
+)
+
+const (
+	// sharedSessionKeyPrefix namespaces client session records in a storage shared with
+	// session affinity pins.
+	sharedSessionKeyPrefix = "vmcp-session:"
+
+	sharedSessionInitializeKey = "initialize"
+	sharedSessionTerminatedKey = "terminated"
+
+	// restoreRequestID is the JSON-RPC ID of the initialize request replayed to restore a session.
+	restoreRequestID = "vmcp-session-restore"
+)
+
+// errSessionNotShared is returned when a session is not found in the shared session storage.
+var errSessionNotShared = errors.New("session not found in shared session storage")
+
+// sharedSessionStore records client sessions in a session storage shared by vMCP replicas,
+// so that a replica can restore a session that was initialized on another replica.
+//
+// Only the parameters of the client's initialize request are stored. The routing table and
+// the session's capabilities are rebuilt by the restoring replica, because backend calls do
+// not keep backend sessions open: each call opens its own backend session.
+type sharedSessionStore struct {
+	storage transportsession.Storage
+}
+
+// record stores the initialize parameters of a client session, replacing any previous record.
+func (s *sharedSessionStore) record(ctx context.Context, sessionID string, params mcp.InitializeParams) error {
+	data, err := json.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("failed to serialize initialize parameters: %w", err)
+	}
+
+	rec := transportsession.NewProxySession(sharedSessionKeyPrefix + sessionID)
+	rec.SetMetadata(sharedSessionInitializeKey, string(data))
+	if err := s.storage.Store(ctx, rec); err != nil {
+		return fmt.Errorf("failed to store shared session: %w", err)
+	}
+	return nil
+}
+
+// load returns the initialize parameters of a client session and refreshes its record.
+// Returns errSessionNotShared if the session is unknown or was terminated.
+func (s *sharedSessionStore) load(ctx context.Context, sessionID string) (json.RawMessage, error) {
+	rec, err := s.storage.Load(ctx, sharedSessionKeyPrefix+sessionID)
+	if errors.Is(err, transportsession.ErrSessionNotFound) {
+		return nil, errSessionNotShared
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load shared session: %w", err)
+	}
+
+	metadata := rec.GetMetadata()
+	params := metadata[sharedSessionInitializeKey]
+	if params == "" || metadata[sharedSessionTerminatedKey] == "true" {
+		return nil, errSessionNotShared
+	}
+
+	// Keep the record alive while the session is in use
+	rec.Touch()
+	if err := s.storage.Store(ctx, rec); err != nil {
+		return nil, fmt.Errorf("failed to refresh shared session: %w", err)
+	}
+	return json.RawMessage(params), nil
+}
+
+// touch refreshes the record of a client session so that it expires together with idle sessions.
+func (s *sharedSessionStore) touch(ctx context.Context, sessionID string) error {
+	_, err := s.load(ctx, sessionID)
+	if errors.Is(err, errSessionNotShared) {
+		return nil
+	}
+	return err
+}
+
+// terminate marks a client session as terminated so that no replica restores it.
+func (s *sharedSessionStore) terminate(ctx context.Context, sessionID string) error {
+	rec, err := s.storage.Load(ctx, sharedSessionKeyPrefix+sessionID)
+	if errors.Is(err, transportsession.ErrSessionNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load shared session: %w", err)
+	}
+
+	rec.SetMetadata(sharedSessionTerminatedKey, "true")
+	if err := s.storage.Store(ctx, rec); err != nil {
+		return fmt.Errorf("failed to store shared session: %w", err)
+	}
+	return nil
+}
+
+// restoringSessionIDKey is the context key for the ID of a session being restored.
+type restoringSessionIDKey struct{}
+
+// sessionIDManagerResolver returns the session ID manager for a request. Initialize requests
+// replayed to restore a session get a manager that reuses the restored session's ID.
+type sessionIDManagerResolver struct {
+	adapter *sessionIDAdapter
+}
+
+// ResolveSessionIdManager implements server.SessionIdManagerResolver.
+//
+//nolint:revive // Method name is defined by the mark3labs SDK interface
+func (r *sessionIDManagerResolver) ResolveSessionIdManager(req *http.Request) server.SessionIdManager {
+	if sessionID, ok := req.Context().Value(restoringSessionIDKey{}).(string); ok {
+		return &restoringSessionIDAdapter{sessionIDAdapter: r.adapter, sessionID: sessionID}
+	}
+	return r.adapter
+}
+
+// restoringSessionIDAdapter registers a known session ID instead of generating a new one.
+type restoringSessionIDAdapter struct {
+	*sessionIDAdapter
+	sessionID string
+}
+
+// Generate registers the restored session with the session manager and returns its ID.
+func (a *restoringSessionIDAdapter) Generate() string {
+	if err := a.manager.AddWithID(a.sessionID); err != nil {
+		logger.Errorf("Failed to restore session %s: %v", a.sessionID, err)
+		return ""
+	}
+	return a.sessionID
+}
+
+// recordSharedSession records a client session in the shared session storage after it is
+// initialized. Registered as an SDK AfterInitialize hook.
+func (s *Server) recordSharedSession(ctx context.Context, _ any, message *mcp.InitializeRequest, _ *mcp.InitializeResult) {
+	session := server.ClientSessionFromContext(ctx)
+	if session == nil || session.SessionID() == "" {
+		return
+	}
+	if err := s.sharedSessions.record(ctx, session.SessionID(), message.Params); err != nil {
+		logger.Warnw("failed to record shared session", "session_id", session.SessionID(), "error", err)
+	}
+}
+
+// sessionRestoreMiddleware restores client sessions that were initialized on another vMCP replica.
+//
+// When a request carries a session ID that this replica does not know but the shared session
+// storage does, the client's initialize request is replayed through next with the caller's
+// identity, under the original session ID. Capability discovery and the OnRegisterSession hook
+// then rebuild the session exactly as for a new session, and the request proceeds normally.
+// Capabilities are rediscovered, so a restored session sees the current backends.
+func (s *Server) sessionRestoreMiddleware(next http.Handler) http.Handler {
+	var restores singleflight.Group
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sessionID := r.Header.Get("Mcp-Session-Id")
+		if sessionID == "" || r.Method == http.MethodDelete {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if _, ok := s.sessionManager.Get(sessionID); ok {
+			if err := s.sharedSessions.touch(r.Context(), sessionID); err != nil {
+				logger.Warnw("failed to refresh shared session", "session_id", sessionID, "error", err)
+			}
+		} else {
+			_, err, _ := restores.Do(sessionID, func() (any, error) {
+				return nil, s.restoreSession(r, next, sessionID)
+			})
+			switch {
+			case errors.Is(err, errSessionNotShared):
+				logger.Debugw("session not found in shared session storage", "session_id", sessionID)
+			case err != nil:
+				logger.Warnw("failed to restore session", "session_id", sessionID, "error", err)
+			default:
+				logger.Infow("restored session initialized on another replica", "session_id", sessionID)
+			}
+		}
+
+		// If the session could not be restored, the SDK rejects the unknown session ID
+		next.ServeHTTP(w, r)
+	})
+}
+
+// restoreSession replays the stored initialize request of a session through next.
+func (s *Server) restoreSession(r *http.Request, next http.Handler, sessionID string) error {
+	// A concurrent request may have restored the session already
+	if _, ok := s.sessionManager.Get(sessionID); ok {
+		return nil
+	}
+
+	params, err := s.sharedSessions.load(r.Context(), sessionID)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(map[string]any{
+		"jsonrpc": mcp.JSONRPC_VERSION,
+		"id":      restoreRequestID,
+		"method":  string(mcp.MethodInitialize),
+		"params":  params,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to build initialize request: %w", err)
+	}
+
+	req := r.Clone(context.WithValue(r.Context(), restoringSessionIDKey{}, sessionID))
+	req.Method = http.MethodPost
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	req.ContentLength = int64(len(body))
+	req.Header.Del("Mcp-Session-Id")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+
+	rec := &discardResponseWriter{header: http.Header{}}
+	next.ServeHTTP(rec, req)
+	if rec.status != 0 && rec.status != http.StatusOK {
+		return fmt.Errorf("initialize request failed with status %d", rec.status)
+	}
+	if _, ok := s.sessionManager.Get(sessionID); !ok {
+		return fmt.Errorf("session was not registered by the initialize request")
+	}
+	return nil
+}
+
+// discardResponseWriter records the status of a response and discards its body.
+type discardResponseWriter struct {
+	header http.Header
+	status int
+}
+
+func (w *discardResponseWriter) Header() http.Header { return w.header }
+
+func (w *discardResponseWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return len(b), nil
+}
+
+func (w *discardResponseWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+}
+
+// Flush implements http.Flusher for responses upgraded to SSE.
+func (*discardResponseWriter) Flush() {}
@@ -0,0 +1,186 @@
+package server_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/stacklok/toolhive/pkg/auth"
+	transportsession "github.com/stacklok/toolhive/pkg/transport/session"
+	"github.com/stacklok/toolhive/pkg/vmcp"
+	"github.com/stacklok/toolhive/pkg/vmcp/aggregator"
+	"github.com/stacklok/toolhive/pkg/vmcp/discovery"
+	"github.com/stacklok/toolhive/pkg/vmcp/mocks"
+	"github.com/stacklok/toolhive/pkg/vmcp/router"
+	"github.com/stacklok/toolhive/pkg/vmcp/server"
+	vmcpsession "github.com/stacklok/toolhive/pkg/vmcp/session"
+)
+
+// startReplica starts a vMCP server that serves a single backend tool and shares the given session storage.
+func startReplica(t *testing.T, sessionStorage transportsession.Storage) *server.Server {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	backendClient := mocks.NewMockBackendClient(ctrl)
+	backendClient.EXPECT().
+		ListCapabilities(gomock.Any(), gomock.Any()).
+		Return(&vmcp.CapabilityList{
+			Tools: []vmcp.Tool{{
+				Name:        "test_tool",
+				Description: "A test tool",
+				InputSchema: map[string]any{"type": "object"},
+				BackendID:   "test-backend",
+			}},
+		}, nil).
+		AnyTimes()
+	backendClient.EXPECT().
+		CallTool(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(map[string]any{"result": "success"}, nil).
+		AnyTimes()
+
+	backends := []vmcp.Backend{{
+		ID:            "test-backend",
+		Name:          "Test Backend",
+		BaseURL:       "http://test-backend:8080",
+		TransportType: "streamable-http",
+		HealthStatus:  vmcp.BackendHealthy,
+	}}
+
+	agg := aggregator.NewDefaultAggregator(backendClient, aggregator.NewPrefixConflictResolver("{workload}_"), nil)
+	discoveryMgr, err := discovery.NewManager(agg)
+	require.NoError(t, err)
+
+	identityMiddleware := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := auth.WithIdentity(r.Context(), &auth.Identity{Subject: "test-user"})
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+
+	srv, err := server.New(context.Background(), &server.Config{
+		Name:           "test-vmcp",
+		Host:           "127.0.0.1",
+		Port:           0,
+		SessionTTL:     5 * time.Minute,
+		AuthMiddleware: identityMiddleware,
+		SessionStorage: sessionStorage,
+	}, router.NewDefaultRouter(), backendClient, discoveryMgr, vmcp.NewImmutableRegistry(backends), nil)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	errCh := make(chan error, 1)
+	go func() {
+		if err := srv.Start(ctx); err != nil && !errors.Is(err, context.Canceled) {
+			errCh <- err
+		}
+	}()
+
+	select {
+	case <-srv.Ready():
+	case err := <-errCh:
+		t.Fatalf("Server failed to start: %v", err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Server timeout waiting for ready")
+	}
+	return srv
+}
+
+// postMCP sends a JSON-RPC request to a vMCP server and returns the response.
+func postMCP(t *testing.T, srv *server.Server, sessionID, method string, params map[string]any) *http.Response {
+	t.Helper()
+
+	body, err := json.Marshal(map[string]any{"jsonrpc": "2.0", "id": 1, "method": method, "params": params})
+	require.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodPost, "http://"+srv.Address()+"/mcp", bytes.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	if sessionID != "" {
+		req.Header.Set("Mcp-Session-Id", sessionID)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = resp.Body.Close() })
+	return resp
+}
+
+func TestSharedSessions_RestoreOnAnotherReplica(t *testing.T) {
+	t.Parallel()
+
+	sessionStorage := transportsession.NewLocalStorage()
+	t.Cleanup(func() { _ = sessionStorage.Close() })
+
+	replicaA := startReplica(t, sessionStorage)
+	replicaB := startReplica(t, sessionStorage)
+
+	// Initialize the session on replica A
+	resp := postMCP(t, replicaA, "", "initialize", map[string]any{
+		"protocolVersion": "2024-11-05",
+		"capabilities":    map[string]any{},
+		"clientInfo":      map[string]any{"name": "test-client", "version": "1.0.0"},
+	})
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	sessionID := resp.Header.Get("Mcp-Session-Id")
+	require.NotEmpty(t, sessionID)
+
+	// Replica B does not know the session, but restores it from the shared storage
+	_, ok := replicaB.SessionManager().Get(sessionID)
+	require.False(t, ok)
+
+	resp = postMCP(t, replicaB, sessionID, "tools/call", map[string]any{
+		"name":      "test-backend_test_tool",
+		"arguments": map[string]any{},
+	})
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+	assert.Contains(t, string(body), "success")
+	assert.NotContains(t, string(body), `"error"`)
+
+	sess, ok := replicaB.SessionManager().Get(sessionID)
+	require.True(t, ok, "restored session should be registered on replica B")
+	vmcpSess, ok := sess.(*vmcpsession.VMCPSession)
+	require.True(t, ok)
+	require.NotNil(t, vmcpSess.GetRoutingTable())
+	assert.Contains(t, vmcpSess.GetRoutingTable().Tools, "test-backend_test_tool")
+
+	// Terminating the session on replica B prevents other replicas from restoring it
+	req, err := http.NewRequest(http.MethodDelete, "http://"+replicaB.Address()+"/mcp", nil)
+	require.NoError(t, err)
+	req.Header.Set("Mcp-Session-Id", sessionID)
+	deleteResp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	_ = deleteResp.Body.Close()
+	require.Equal(t, http.StatusOK, deleteResp.StatusCode)
+
+	replicaC := startReplica(t, sessionStorage)
+	resp = postMCP(t, replicaC, sessionID, "tools/list", map[string]any{})
+	assert.NotEqual(t, http.StatusOK, resp.StatusCode)
+	_, ok = replicaC.SessionManager().Get(sessionID)
+	assert.False(t, ok)
+}
+
+func TestSharedSessions_UnknownSession(t *testing.T) {
+	t.Parallel()
+
+	sessionStorage := transportsession.NewLocalStorage()
+	t.Cleanup(func() { _ = sessionStorage.Close() })
+
+	replica := startReplica(t, sessionStorage)
+	resp := postMCP(t, replica, "unknown-session", "tools/list", map[string]any{})
+	assert.NotEqual(t, http.StatusOK, resp.StatusCode)
+	_, ok := replica.SessionManager().Get("unknown-session")
+	assert.False(t, ok)
+}
@@ -25,6 +25,10 @@ type BackendStatus struct {
 	Health    string `json:"health"`              // "healthy", "degraded", "unhealthy", "unknown"
 	Transport string `json:"transport"`           // MCP transport protocol
 	AuthType  string `json:"auth_type,omitempty"` // "unauthenticated", "header_injection", "token_exchange"
+
+	// CircuitState is the backend's circuit breaker state ("closed", "open", "half-open").
+	// Omitted when circuit breaking is disabled.
+	CircuitState string `json:"circuit_state,omitempty"`
 }
 
 // handleStatus handles /status HTTP requests for operational visibility.
@@ -68,6 +72,9 @@ func (s *Server) buildStatusResponse(ctx context.Context) StatusResponse {
 			Transport: backend.TransportType,
 			AuthType:  getAuthType(backend.AuthConfig),
 		}
+		if s.circuitBreakers != nil {
+			status.CircuitState = string(s.circuitBreakers.GetState(backend.ID))
+		}
 		backendStatuses = append(backendStatuses, status)
 
 		if backend.HealthStatus == vmcp.BackendHealthy {
@@ -11,6 +11,7 @@ import (
 	"go.opentelemetry.io/otel/trace"
 
 	"github.com/stacklok/toolhive/pkg/vmcp"
+	"github.com/stacklok/toolhive/pkg/vmcp/health"
 	"github.com/stacklok/toolhive/pkg/vmcp/server/adapter"
 )
 
@@ -146,6 +147,52 @@ func (t telemetryBackendClient) ListCapabilities(
 	return t.backendClient.ListCapabilities(ctx, target)
 }
 
+// circuitStateValues maps circuit states to gauge values.
+var circuitStateValues = map[health.CircuitState]int64{
+	health.CircuitClosed:   0,
+	health.CircuitHalfOpen: 1,
+	health.CircuitOpen:     2,
+}
+
+// monitorCircuitBreakers records circuit breaker state transitions and exposes the current
+// state of each backend's breaker as a gauge (0 = closed, 1 = half-open, 2 = open).
+func monitorCircuitBreakers(meterProvider metric.MeterProvider, breakers *health.CircuitBreakers) error {
+	meter := meterProvider.Meter(instrumentationName)
+
+	transitionsTotal, err := meter.Int64Counter(
+		"toolhive_vmcp_circuit_breaker_transitions",
+		metric.WithDescription("Total number of circuit breaker state transitions per backend"),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create circuit breaker transitions counter: %w", err)
+	}
+
+	_, err = meter.Int64ObservableGauge(
+		"toolhive_vmcp_circuit_breaker_state",
+		metric.WithDescription("Circuit breaker state per backend (0 = closed, 1 = half-open, 2 = open)"),
+		metric.WithInt64Callback(func(_ context.Context, observer metric.Int64Observer) error {
+			for backendID, state := range breakers.GetAllStates() {
+				observer.Observe(circuitStateValues[state.State],
+					metric.WithAttributes(attribute.String("target.workload_id", backendID)))
+			}
+			return nil
+		}),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create circuit breaker state gauge: %w", err)
+	}
+
+	breakers.OnStateChange(func(backendID string, from, to health.CircuitState) {
+		transitionsTotal.Add(context.Background(), 1, metric.WithAttributes(
+			attribute.String("target.workload_id", backendID),
+			attribute.String("from", string(from)),
+			attribute.String("to", string(to)),
+		))
+	})
+
+	return nil
+}
+
 // monitorWorkflowExecutors decorates workflow executors with telemetry recording.
 // It wraps each executor to emit metrics and traces for execution count, duration, and errors.
 func monitorWorkflowExecutors(
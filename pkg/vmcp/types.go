@@ -61,6 +61,15 @@ type BackendTarget struct {
 	// HealthStatus indicates the current health of the backend.
 	HealthStatus BackendHealthStatus
 
+	// ReplicaGroup is the name of the replica group this backend belongs to.
+	// Empty if the backend is not part of a replica group.
+	ReplicaGroup string
+
+	// Replicas lists the other backends in the same replica group that serve this capability.
+	// When non-empty, the router selects one target among this target and its replicas
+	// using the replica group's RoutingStrategy. Replicas never have nested Replicas.
+	Replicas []*BackendTarget
+
 	// Metadata stores additional backend-specific information.
 	Metadata map[string]string
 }
@@ -0,0 +1,165 @@
+// Package imageupdates checks if the registries have newer images for the images that workloads
+// run, and upgrades workloads to them.
+//
+// Workloads run the image their image reference pointed to when it was pulled. The checks compare
+// the digests of that image with the digests of the image the reference points to in the registry,
+// and record the result in the status of the workload, where `thv list`, the API and the MCP
+// server read it.
+package imageupdates
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/stacklok/toolhive/pkg/container"
+	"github.com/stacklok/toolhive/pkg/container/images"
+	rt "github.com/stacklok/toolhive/pkg/container/runtime"
+	"github.com/stacklok/toolhive/pkg/core"
+	thverrors "github.com/stacklok/toolhive/pkg/errors"
+	"github.com/stacklok/toolhive/pkg/labels"
+	"github.com/stacklok/toolhive/pkg/logger"
+	"github.com/stacklok/toolhive/pkg/runner"
+	"github.com/stacklok/toolhive/pkg/workloads/statuses"
+)
+
+// DefaultCheckInterval is the default interval of the scheduled image update checks
+const DefaultCheckInterval = 6 * time.Hour
+
+// ErrNoRegistryImage is returned when a workload does not run an image from a registry,
+// such as remote workloads and images built from protocol schemes
+var ErrNoRegistryImage = thverrors.New("workload does not run an image from a registry", http.StatusBadRequest)
+
+// Checker checks if the registries have newer images for the images of workloads
+type Checker struct {
+	runtime  rt.Runtime
+	statuses statuses.StatusManager
+	resolver images.DigestResolver
+}
+
+// NewChecker creates a Checker for the workloads of the current runtime
+func NewChecker(ctx context.Context) (*Checker, error) {
+	runtime, err := container.NewFactory().Create(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create container runtime: %w", err)
+	}
+	return NewCheckerFromRuntime(ctx, runtime)
+}
+
+// NewCheckerFromRuntime creates a Checker for the workloads of the provided runtime
+func NewCheckerFromRuntime(ctx context.Context, runtime rt.Runtime) (*Checker, error) {
+	statusManager, err := statuses.NewStatusManager(runtime)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create status manager: %w", err)
+	}
+	resolver, err := images.NewDigestResolver(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("image updates cannot be checked: %w", err)
+	}
+	return newChecker(runtime, statusManager, resolver), nil
+}
+
+func newChecker(runtime rt.Runtime, statusManager statuses.StatusManager, resolver images.DigestResolver) *Checker {
+	return &Checker{
+		runtime:  runtime,
+		statuses: statusManager,
+		resolver: resolver,
+	}
+}
+
+// CheckWorkload checks if the registry has a newer image for the image of a workload,
+// and records the result in the status of the workload
+func (c *Checker) CheckWorkload(ctx context.Context, workloadName string) (*core.ImageUpdate, error) {
+	info, err := c.runtime.GetWorkloadInfo(ctx, workloadName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workload %s: %w", workloadName, err)
+	}
+	return c.check(ctx, workloadName, info)
+}
+
+// CheckWorkloads checks the images of all the running workloads that run an image from a registry.
+// It returns the results of the successful checks by workload name, and the errors of the others.
+func (c *Checker) CheckWorkloads(ctx context.Context) (map[string]*core.ImageUpdate, error) {
+	containers, err := c.runtime.ListWorkloads(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workloads: %w", err)
+	}
+
+	results := make(map[string]*core.ImageUpdate)
+	var errs []error
+	for _, info := range containers {
+		if !info.IsRunning() {
+			continue
+		}
+		workloadName := labels.GetContainerBaseName(info.Labels)
+		if workloadName == "" {
+			workloadName = info.Name
+		}
+
+		update, err := c.check(ctx, workloadName, info)
+		if errors.Is(err, ErrNoRegistryImage) {
+			continue
+		}
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		results[workloadName] = update
+	}
+	return results, errors.Join(errs...)
+}
+
+// Schedule checks the images of the running workloads now and at every interval, until the context is done
+func (c *Checker) Schedule(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		results, err := c.CheckWorkloads(ctx)
+		if err != nil && ctx.Err() == nil {
+			logger.Warnf("Failed to check some workloads for image updates: %v", err)
+		}
+		for workloadName, update := range results {
+			if update.Available {
+				logger.Infof("A newer image is available for workload %s (%s), run 'thv upgrade %s' to upgrade it",
+					workloadName, update.Image, workloadName)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// check compares the digests of the image of a workload with the digests of the image its reference
+// points to in the registry
+func (c *Checker) check(ctx context.Context, workloadName string, info rt.ContainerInfo) (*core.ImageUpdate, error) {
+	if info.ImageID == "" || runner.IsLocallyBuiltImage(info.Image) {
+		return nil, fmt.Errorf("%w: %s", ErrNoRegistryImage, workloadName)
+	}
+
+	localDigests, err := c.resolver.LocalDigests(ctx, info.ImageID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check image of workload %s: %w", workloadName, err)
+	}
+	remoteDigests, err := c.resolver.RemoteDigests(ctx, info.Image)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check image of workload %s: %w", workloadName, err)
+	}
+
+	update := &core.ImageUpdate{
+		Image:        info.Image,
+		Available:    !images.IsUpToDate(localDigests, remoteDigests),
+		LatestDigest: remoteDigests[0],
+		CheckedAt:    time.Now().UTC(),
+	}
+	if err := c.statuses.SetWorkloadImageUpdate(ctx, workloadName, update); err != nil {
+		return nil, fmt.Errorf("failed to record image update of workload %s: %w", workloadName, err)
+	}
+	return update, nil
+}
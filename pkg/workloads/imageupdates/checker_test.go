@@ -0,0 +1,180 @@
+package imageupdates
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	rt "github.com/stacklok/toolhive/pkg/container/runtime"
+	runtimemocks "github.com/stacklok/toolhive/pkg/container/runtime/mocks"
+	"github.com/stacklok/toolhive/pkg/core"
+	"github.com/stacklok/toolhive/pkg/labels"
+	statusmocks "github.com/stacklok/toolhive/pkg/workloads/statuses/mocks"
+)
+
+// fakeResolver resolves digests from maps of image IDs and image references
+type fakeResolver struct {
+	local  map[string][]string
+	remote map[string][]string
+}
+
+func (f *fakeResolver) LocalDigests(_ context.Context, imageID string) ([]string, error) {
+	digests, ok := f.local[imageID]
+	if !ok {
+		return nil, errors.New("image not found")
+	}
+	return digests, nil
+}
+
+func (f *fakeResolver) RemoteDigests(_ context.Context, image string) ([]string, error) {
+	digests, ok := f.remote[image]
+	if !ok {
+		return nil, errors.New("manifest unknown")
+	}
+	return digests, nil
+}
+
+func newTestResolver() *fakeResolver {
+	return &fakeResolver{
+		local: map[string][]string{
+			"sha256:config-v1": {"sha256:config-v1", "sha256:index-v1"},
+			"sha256:config-v2": {"sha256:config-v2"},
+		},
+		remote: map[string][]string{
+			"ghcr.io/example/fetch:latest": {"sha256:index-v2", "sha256:manifest-v2", "sha256:config-v2"},
+			"ghcr.io/example/git:v1":       {"sha256:index-v1", "sha256:manifest-v1", "sha256:config-v1"},
+		},
+	}
+}
+
+func TestChecker_CheckWorkload(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		info          rt.ContainerInfo
+		wantAvailable bool
+		wantErr       error
+		wantErrText   string
+	}{
+		{
+			name:          "update available",
+			info:          rt.ContainerInfo{Image: "ghcr.io/example/fetch:latest", ImageID: "sha256:config-v1"},
+			wantAvailable: true,
+		},
+		{
+			name: "up to date by repository digest",
+			info: rt.ContainerInfo{Image: "ghcr.io/example/git:v1", ImageID: "sha256:config-v1"},
+		},
+		{
+			name: "up to date by image ID",
+			info: rt.ContainerInfo{Image: "ghcr.io/example/fetch:latest", ImageID: "sha256:config-v2"},
+		},
+		{
+			name:    "no image ID",
+			info:    rt.ContainerInfo{Image: "uvx://mcp-server-git"},
+			wantErr: ErrNoRegistryImage,
+		},
+		{
+			name:    "locally built image",
+			info:    rt.ContainerInfo{Image: "toolhivelocal/uvx-mcp-server-git:20250101", ImageID: "sha256:local"},
+			wantErr: ErrNoRegistryImage,
+		},
+		{
+			name:        "image not in registry",
+			info:        rt.ContainerInfo{Image: "ghcr.io/example/missing:v1", ImageID: "sha256:config-v1"},
+			wantErrText: "manifest unknown",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			runtime := runtimemocks.NewMockRuntime(ctrl)
+			statusManager := statusmocks.NewMockStatusManager(ctrl)
+
+			runtime.EXPECT().GetWorkloadInfo(gomock.Any(), "server").Return(tt.info, nil)
+			var recorded *core.ImageUpdate
+			if tt.wantErr == nil && tt.wantErrText == "" {
+				statusManager.EXPECT().SetWorkloadImageUpdate(gomock.Any(), "server", gomock.Any()).DoAndReturn(
+					func(_ context.Context, _ string, update *core.ImageUpdate) error {
+						recorded = update
+						return nil
+					})
+			}
+
+			checker := newChecker(runtime, statusManager, newTestResolver())
+			update, err := checker.CheckWorkload(t.Context(), "server")
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			if tt.wantErrText != "" {
+				require.ErrorContains(t, err, tt.wantErrText)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantAvailable, update.Available)
+			assert.Equal(t, tt.info.Image, update.Image)
+			assert.NotZero(t, update.CheckedAt)
+			assert.Same(t, update, recorded, "the result must be recorded in the status")
+		})
+	}
+}
+
+func TestChecker_CheckWorkloads(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	runtime := runtimemocks.NewMockRuntime(ctrl)
+	statusManager := statusmocks.NewMockStatusManager(ctrl)
+
+	runtime.EXPECT().ListWorkloads(gomock.Any()).Return([]rt.ContainerInfo{
+		{
+			Name:    "fetch",
+			Image:   "ghcr.io/example/fetch:latest",
+			ImageID: "sha256:config-v1",
+			State:   rt.WorkloadStatusRunning,
+			Labels:  map[string]string{labels.LabelBaseName: "fetch"},
+		},
+		{
+			Name:    "git",
+			Image:   "ghcr.io/example/git:v1",
+			ImageID: "sha256:config-v1",
+			State:   rt.WorkloadStatusRunning,
+		},
+		{
+			Name:    "stopped",
+			Image:   "ghcr.io/example/fetch:latest",
+			ImageID: "sha256:config-v1",
+			State:   rt.WorkloadStatusStopped,
+		},
+		{
+			Name:    "local",
+			Image:   "toolhivelocal/uvx-mcp-server-git:20250101",
+			ImageID: "sha256:local",
+			State:   rt.WorkloadStatusRunning,
+		},
+		{
+			Name:    "missing",
+			Image:   "ghcr.io/example/missing:v1",
+			ImageID: "sha256:config-v1",
+			State:   rt.WorkloadStatusRunning,
+		},
+	}, nil)
+	statusManager.EXPECT().SetWorkloadImageUpdate(gomock.Any(), "fetch", gomock.Any()).Return(nil)
+	statusManager.EXPECT().SetWorkloadImageUpdate(gomock.Any(), "git", gomock.Any()).Return(nil)
+
+	checker := newChecker(runtime, statusManager, newTestResolver())
+	results, err := checker.CheckWorkloads(t.Context())
+	require.ErrorContains(t, err, "failed to check image of workload missing")
+	require.Len(t, results, 2)
+	assert.True(t, results["fetch"].Available)
+	assert.Equal(t, "sha256:index-v2", results["fetch"].LatestDigest)
+	assert.False(t, results["git"].Available)
+}
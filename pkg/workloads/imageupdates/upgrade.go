@@ -0,0 +1,53 @@
+package imageupdates
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/stacklok/toolhive/pkg/logger"
+	"github.com/stacklok/toolhive/pkg/runner"
+	"github.com/stacklok/toolhive/pkg/runner/retriever"
+	"github.com/stacklok/toolhive/pkg/workloads"
+)
+
+// Upgrade recreates a workload with the same run configuration, running the image its image
+// reference points to in the registry. The provenance of the image is verified before it is
+// pulled, with the same verification types as `thv run`.
+func (c *Checker) Upgrade(ctx context.Context, manager workloads.Manager, workloadName string, verificationType string) error {
+	runConfig, err := runner.LoadState(ctx, workloadName)
+	if err != nil {
+		return fmt.Errorf("failed to load run configuration of workload %s: %w", workloadName, err)
+	}
+	if err := ValidateUpgrade(runConfig); err != nil {
+		return err
+	}
+
+	logger.Infof("Pulling the latest image %s for workload %s", runConfig.Image, workloadName)
+	if err := retriever.PullVerifiedImage(ctx, runConfig.Image, verificationType); err != nil {
+		return err
+	}
+
+	complete, err := manager.UpdateWorkload(ctx, workloadName, runConfig)
+	if err != nil {
+		return fmt.Errorf("failed to recreate workload %s: %w", workloadName, err)
+	}
+	if err := complete(); err != nil {
+		return fmt.Errorf("failed to recreate workload %s: %w", workloadName, err)
+	}
+
+	// Record that the workload now runs the latest image
+	if _, err := c.CheckWorkload(ctx, workloadName); err != nil {
+		logger.Warnf("Workload %s was upgraded but its image could not be checked: %v", workloadName, err)
+	}
+	return nil
+}
+
+// ValidateUpgrade returns ErrNoRegistryImage if the workload of a run configuration does not run an
+// image from a registry, so it cannot be upgraded
+func ValidateUpgrade(runConfig *runner.RunConfig) error {
+	image := runConfig.Image
+	if runConfig.RemoteURL != "" || image == "" || runner.IsImageProtocolScheme(image) || runner.IsLocallyBuiltImage(image) {
+		return fmt.Errorf("%w: %s", ErrNoRegistryImage, runConfig.Name)
+	}
+	return nil
+}
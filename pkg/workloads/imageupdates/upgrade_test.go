@@ -0,0 +1,49 @@
+package imageupdates
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stacklok/toolhive/pkg/runner"
+)
+
+func TestValidateUpgrade(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		config     *runner.RunConfig
+		upgradable bool
+	}{
+		{
+			name:       "registry image",
+			config:     &runner.RunConfig{Name: "fetch", Image: "ghcr.io/stackloklabs/mcp-fetch:latest"},
+			upgradable: true,
+		},
+		{
+			name:   "remote workload",
+			config: &runner.RunConfig{Name: "remote", RemoteURL: "https://mcp.example.com/mcp"},
+		},
+		{
+			name:   "protocol scheme",
+			config: &runner.RunConfig{Name: "git", Image: "uvx://mcp-server-git"},
+		},
+		{
+			name:   "locally built image",
+			config: &runner.RunConfig{Name: "git", Image: "toolhivelocal/stdio-mcp-server-git:20250101120000"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := ValidateUpgrade(tt.config)
+			if tt.upgradable {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, ErrNoRegistryImage)
+			}
+		})
+	}
+}
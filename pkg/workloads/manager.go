@@ -409,70 +409,36 @@ func (d *DefaultManager) RunWorkload(ctx context.Context, runConfig *runner.RunC
 		return fmt.Errorf("failed to create workload status: %w", err)
 	}
 
-	// Retry loop with exponential backoff for container restarts
-	maxRetries := 10 // Allow many retries for transient issues
-	retryDelay := 5 * time.Second
-
-	for attempt := 1; attempt <= maxRetries; attempt++ {
-		if attempt > 1 {
-			logger.Infof("Restart attempt %d/%d for %s after %v delay", attempt, maxRetries, runConfig.BaseName, retryDelay)
-			time.Sleep(retryDelay)
-
-			// Exponential backoff: 5s, 10s, 20s, 40s, 60s (capped)
-			retryDelay *= 2
-			if retryDelay > 60*time.Second {
-				retryDelay = 60 * time.Second
-			}
-		}
-
-		mcpRunner := runner.NewRunner(runConfig, d.statuses)
-		err := mcpRunner.Run(ctx)
-
-		if err != nil {
-			// Check if this is a "container exited, restart needed" error
-			if err.Error() == "container exited, restart needed" {
-				logger.Warnf("Container %s exited unexpectedly (attempt %d/%d). Restarting...",
-					runConfig.BaseName, attempt, maxRetries)
-
-				// Remove from client config so clients notice the restart
-				clientManager, clientErr := client.NewManager(ctx)
-				if clientErr == nil {
-					logger.Infof("Removing %s from client configurations before restart", runConfig.BaseName)
-					if removeErr := clientManager.RemoveServerFromClients(ctx, runConfig.BaseName, runConfig.Group); removeErr != nil {
-						logger.Warnf("Warning: Failed to remove from client config: %v", removeErr)
-					}
-				}
-
-				// Set status to starting (since we're restarting)
-				statusErr := d.statuses.SetWorkloadStatus(
-					ctx,
-					runConfig.BaseName,
-					rt.WorkloadStatusStarting,
-					"Container exited, restarting",
-				)
-				if statusErr != nil {
-					logger.Warnf("Failed to set workload %s status to starting: %v", runConfig.BaseName, statusErr)
-				}
+	return d.runWithRestarts(ctx, runConfig, func(ctx context.Context) error {
+		return runner.NewRunner(runConfig, d.statuses).Run(ctx)
+	})
+}
 
-				// If we haven't exhausted retries, continue the loop
-				if attempt < maxRetries {
-					continue
-				}
+// runWithRestarts runs a workload and restarts it according to its restart policy
+// whenever it exits unexpectedly. Each restart is recorded in the workload status.
+func (d *DefaultManager) runWithRestarts(
+	ctx context.Context, runConfig *runner.RunConfig, run func(context.Context) error,
+) error {
+	policy := runConfig.RestartPolicy
+	if policy == nil {
+		policy = runner.DefaultRestartPolicy()
+	}
+	if err := d.statuses.SetWorkloadRestarts(ctx, runConfig.BaseName, 0); err != nil {
+		logger.Warnf("Failed to reset restarts of workload %s: %v", runConfig.BaseName, err)
+	}
 
-				// Exhausted all retries
-				logger.Errorf("Failed to restart %s after %d attempts. Giving up.", runConfig.BaseName, maxRetries)
-				statusErr = d.statuses.SetWorkloadStatus(
-					ctx,
-					runConfig.BaseName,
-					rt.WorkloadStatusError,
-					"Failed to restart after container exit",
-				)
-				if statusErr != nil {
-					logger.Warnf("Failed to set workload %s status to error: %v", runConfig.BaseName, statusErr)
-				}
-				return fmt.Errorf("container restart failed after %d attempts", maxRetries)
-			}
+	restarts := 0
+	retries := 0
+	for {
+		startedAt := time.Now()
+		err := run(ctx)
+		if err == nil {
+			// Success - workload completed normally
+			return nil
+		}
 
+		var exitErr *runner.ContainerExitError
+		if !errors.As(err, &exitErr) {
 			// Some other error - don't retry
 			logger.Errorf("Workload %s failed with error: %v", runConfig.BaseName, err)
 			if statusErr := d.statuses.SetWorkloadStatus(ctx, runConfig.BaseName, rt.WorkloadStatusError, err.Error()); statusErr != nil {
@@ -481,12 +447,59 @@ func (d *DefaultManager) RunWorkload(ctx context.Context, runConfig *runner.RunC
 			return err
 		}
 
-		// Success - workload completed normally
-		return nil
+		exitContext := "Container exited"
+		if exitErr.ExitCode >= 0 {
+			exitContext = fmt.Sprintf("Container exited with code %d", exitErr.ExitCode)
+		}
+
+		if !policy.ShouldRestart(exitErr.ExitCode) {
+			logger.Infof("%s: %s, not restarting (restart policy: %s)", runConfig.BaseName, exitContext, policy.Policy)
+			if exitErr.ExitCode == 0 {
+				d.setStatusAfterExit(ctx, runConfig.BaseName, rt.WorkloadStatusStopped, exitContext)
+				return nil
+			}
+			d.setStatusAfterExit(ctx, runConfig.BaseName, rt.WorkloadStatusError, exitContext)
+			return fmt.Errorf("workload %s exited: %w", runConfig.BaseName, exitErr)
+		}
+
+		// A workload that ran long enough before exiting starts over with the initial backoff
+		if policy.ResetsRetries(time.Since(startedAt)) {
+			retries = 0
+		}
+		retries++
+		if policy.RetriesExhausted(retries) {
+			logger.Errorf("Failed to restart %s after %d attempts. Giving up.", runConfig.BaseName, policy.MaxRetries)
+			d.setStatusAfterExit(ctx, runConfig.BaseName, rt.WorkloadStatusError, "Failed to restart after container exit")
+			return fmt.Errorf("container restart failed after %d attempts", policy.MaxRetries)
+		}
+
+		delay := policy.Backoff(retries)
+		logger.Warnf("Container %s exited unexpectedly (attempt %d). Restarting in %v...", runConfig.BaseName, retries, delay)
+
+		// Set status to starting (since we're restarting) and record the restart
+		restarts++
+		d.setStatusAfterExit(ctx, runConfig.BaseName, rt.WorkloadStatusStarting,
+			fmt.Sprintf("%s, restarting in %v", exitContext, delay))
+		if err := d.statuses.SetWorkloadRestarts(ctx, runConfig.BaseName, restarts); err != nil {
+			logger.Warnf("Failed to record restart of workload %s: %v", runConfig.BaseName, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			logger.Infof("Restart of %s cancelled", runConfig.BaseName)
+			return nil
+		case <-time.After(delay):
+		}
 	}
+}
 
-	// Should not reach here, but just in case
-	return fmt.Errorf("unexpected end of retry loop for %s", runConfig.BaseName)
+// setStatusAfterExit sets the status of a workload after its container exited, logging failures.
+func (d *DefaultManager) setStatusAfterExit(
+	ctx context.Context, workloadName string, status rt.WorkloadStatus, contextMsg string,
+) {
+	if err := d.statuses.SetWorkloadStatus(ctx, workloadName, status, contextMsg); err != nil {
+		logger.Warnf("Failed to set workload %s status to %s: %v", workloadName, status, err)
+	}
 }
 
 // validateSecretParameters validates the secret parameters for a workload.
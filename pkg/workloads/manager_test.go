@@ -943,6 +943,7 @@ func TestDefaultManager_RunWorkload(t *testing.T) {
 			setupMocks: func(sm *statusMocks.MockStatusManager) {
 				// Expect starting status first, then error status when the runner fails
 				sm.EXPECT().SetWorkloadStatus(gomock.Any(), "test-workload", runtime.WorkloadStatusStarting, "").Return(nil)
+				sm.EXPECT().SetWorkloadRestarts(gomock.Any(), "test-workload", 0).Return(nil)
 				sm.EXPECT().SetWorkloadStatus(gomock.Any(), "test-workload", runtime.WorkloadStatusError, gomock.Any()).Return(nil)
 			},
 			expectError: true, // The runner will fail without proper setup
@@ -1680,6 +1681,11 @@ func TestDefaultManager_RunWorkload_ContainerExitHandling(t *testing.T) {
 		SetWorkloadStatus(gomock.Any(), "test-workload", runtime.WorkloadStatusStarting, "").
 		Return(nil)
 
+	// Expect the restarts of a previous run to be reset
+	mockStatusMgr.EXPECT().
+		SetWorkloadRestarts(gomock.Any(), "test-workload", 0).
+		Return(nil)
+
 	// Expect status to be set to error on failure
 	mockStatusMgr.EXPECT().
 		SetWorkloadStatus(gomock.Any(), "test-workload", runtime.WorkloadStatusError, gomock.Any()).
@@ -1703,6 +1709,101 @@ func TestDefaultManager_RunWorkload_ContainerExitHandling(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestDefaultManager_runWithRestarts(t *testing.T) {
+	t.Parallel()
+
+	exitWith := func(code int) error { return &runner.ContainerExitError{ExitCode: code} }
+
+	tests := []struct {
+		name             string
+		policy           *runner.RestartPolicy
+		results          []error
+		expectedErr      string
+		expectedRestarts []int
+		expectedStatus   runtime.WorkloadStatus
+	}{
+		{
+			name:             "never does not restart",
+			policy:           &runner.RestartPolicy{Policy: runner.RestartPolicyNever},
+			results:          []error{exitWith(1)},
+			expectedErr:      "exited",
+			expectedRestarts: []int{0},
+			expectedStatus:   runtime.WorkloadStatusError,
+		},
+		{
+			name:             "on-failure stops after a clean exit",
+			policy:           &runner.RestartPolicy{Policy: runner.RestartPolicyOnFailure},
+			results:          []error{exitWith(0)},
+			expectedRestarts: []int{0},
+			expectedStatus:   runtime.WorkloadStatusStopped,
+		},
+		{
+			name:             "on-failure restarts until retries are exhausted",
+			policy:           &runner.RestartPolicy{Policy: runner.RestartPolicyOnFailure, MaxRetries: 2, InitialBackoff: "1ms"},
+			results:          []error{exitWith(1), exitWith(1), exitWith(1)},
+			expectedErr:      "container restart failed after 2 attempts",
+			expectedRestarts: []int{0, 1, 2},
+			expectedStatus:   runtime.WorkloadStatusError,
+		},
+		{
+			name:             "always restarts after a clean exit",
+			policy:           &runner.RestartPolicy{Policy: runner.RestartPolicyAlways, InitialBackoff: "1ms"},
+			results:          []error{exitWith(0), exitWith(-1), nil},
+			expectedRestarts: []int{0, 1, 2},
+			expectedStatus:   runtime.WorkloadStatusStarting,
+		},
+		{
+			name:             "other errors are not retried",
+			policy:           &runner.RestartPolicy{Policy: runner.RestartPolicyAlways, InitialBackoff: "1ms"},
+			results:          []error{errors.New("boom")},
+			expectedErr:      "boom",
+			expectedRestarts: []int{0},
+			expectedStatus:   runtime.WorkloadStatusError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			mockStatusMgr := statusMocks.NewMockStatusManager(ctrl)
+
+			var restarts []int
+			var lastStatus runtime.WorkloadStatus
+			mockStatusMgr.EXPECT().SetWorkloadRestarts(gomock.Any(), "test-workload", gomock.Any()).
+				DoAndReturn(func(_ context.Context, _ string, count int) error {
+					restarts = append(restarts, count)
+					return nil
+				}).AnyTimes()
+			mockStatusMgr.EXPECT().SetWorkloadStatus(gomock.Any(), "test-workload", gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, _ string, status runtime.WorkloadStatus, _ string) error {
+					lastStatus = status
+					return nil
+				}).AnyTimes()
+
+			manager := &DefaultManager{statuses: mockStatusMgr}
+			runConfig := &runner.RunConfig{BaseName: "test-workload", RestartPolicy: tt.policy}
+
+			runs := 0
+			err := manager.runWithRestarts(context.Background(), runConfig, func(context.Context) error {
+				result := tt.results[runs]
+				runs++
+				return result
+			})
+
+			if tt.expectedErr != "" {
+				require.ErrorContains(t, err, tt.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, len(tt.results), runs)
+			assert.Equal(t, tt.expectedRestarts, restarts)
+			assert.Equal(t, tt.expectedStatus, lastStatus)
+		})
+	}
+}
+
 func TestDefaultManager_ListWorkloadsUsingSecret(t *testing.T) {
 	t.Parallel()
 
@@ -183,6 +183,9 @@ type workloadStatusFile struct {
 	CreatedAt     time.Time         `json:"created_at"`
 	UpdatedAt     time.Time         `json:"updated_at"`
 	ProcessID     int               `json:"process_id"`
+	RestartCount  int               `json:"restart_count,omitempty"`
+	LastRestartAt *time.Time        `json:"last_restart_at,omitempty"`
+	ImageUpdate   *core.ImageUpdate `json:"image_update,omitempty"`
 }
 
 // GetWorkload retrieves the status of a workload by its name.
@@ -208,6 +211,9 @@ func (f *fileStatusManager) GetWorkload(ctx context.Context, workloadName string
 		result.Status = statusFile.Status
 		result.StatusContext = statusFile.StatusContext
 		result.CreatedAt = statusFile.CreatedAt
+		result.Restarts = statusFile.RestartCount
+		result.LastRestartAt = statusFile.LastRestartAt
+		result.ImageUpdate = statusFile.ImageUpdate
 
 		fileFound = true
 
@@ -447,6 +453,83 @@ func (f *fileStatusManager) SetWorkloadPID(ctx context.Context, workloadName str
 	return err
 }
 
+// SetWorkloadRestarts records the number of restarts of a workload in its status file.
+// A non-zero count also records the time of the restart.
+// This method will do nothing if the workload does not exist.
+func (f *fileStatusManager) SetWorkloadRestarts(ctx context.Context, workloadName string, restarts int) error {
+	err := f.withFileLock(ctx, workloadName, func(statusFilePath string) error {
+		// Check if file exists
+		if _, err := os.Stat(statusFilePath); os.IsNotExist(err) {
+			// File doesn't exist, nothing to do
+			logger.Debugf("workload %s does not exist, skipping restarts update", workloadName)
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("failed to check status file for workload %s: %w", workloadName, err)
+		}
+
+		statusFile, err := f.readStatusFile(statusFilePath)
+		if err != nil {
+			return fmt.Errorf("failed to read existing status for workload %s: %w", workloadName, err)
+		}
+
+		now := time.Now()
+		statusFile.RestartCount = restarts
+		if restarts > 0 {
+			statusFile.LastRestartAt = &now
+		} else {
+			statusFile.LastRestartAt = nil
+		}
+		statusFile.UpdatedAt = now
+
+		if err = f.writeStatusFile(statusFilePath, *statusFile); err != nil {
+			return fmt.Errorf("failed to write updated restarts for workload %s: %w", workloadName, err)
+		}
+
+		logger.Debugf("workload %s restarts set to %d", workloadName, restarts)
+		return nil
+	})
+
+	if err != nil {
+		logger.Errorf("error updating workload %s restarts: %v", workloadName, err)
+	}
+	return err
+}
+
+// SetWorkloadImageUpdate records the result of the last image update check of a workload in its status file.
+// This method will do nothing if the workload does not exist.
+func (f *fileStatusManager) SetWorkloadImageUpdate(ctx context.Context, workloadName string, update *core.ImageUpdate) error {
+	err := f.withFileLock(ctx, workloadName, func(statusFilePath string) error {
+		// Check if file exists
+		if _, err := os.Stat(statusFilePath); os.IsNotExist(err) {
+			// File doesn't exist, nothing to do
+			logger.Debugf("workload %s does not exist, skipping image update", workloadName)
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("failed to check status file for workload %s: %w", workloadName, err)
+		}
+
+		statusFile, err := f.readStatusFile(statusFilePath)
+		if err != nil {
+			return fmt.Errorf("failed to read existing status for workload %s: %w", workloadName, err)
+		}
+
+		statusFile.ImageUpdate = update
+		statusFile.UpdatedAt = time.Now()
+
+		if err = f.writeStatusFile(statusFilePath, *statusFile); err != nil {
+			return fmt.Errorf("failed to write image update for workload %s: %w", workloadName, err)
+		}
+
+		logger.Debugf("workload %s image update recorded", workloadName)
+		return nil
+	})
+
+	if err != nil {
+		logger.Errorf("error updating workload %s image update: %v", workloadName, err)
+	}
+	return err
+}
+
 // ResetWorkloadPID resets the PID of a workload to 0.
 // This method will do nothing if the workload does not exist.
 func (f *fileStatusManager) ResetWorkloadPID(ctx context.Context, workloadName string) error {
@@ -721,6 +804,9 @@ func (f *fileStatusManager) getWorkloadsFromFiles() (map[string]workloadWithPID,
 				Status:        statusFile.Status,
 				StatusContext: statusFile.StatusContext,
 				CreatedAt:     statusFile.CreatedAt,
+				Restarts:      statusFile.RestartCount,
+				LastRestartAt: statusFile.LastRestartAt,
+				ImageUpdate:   statusFile.ImageUpdate,
 			}
 
 			// Check if this is a remote workload using the state package
@@ -818,6 +904,9 @@ func (f *fileStatusManager) handleRuntimeMismatch(
 	runtimeResult.Status = rt.WorkloadStatusUnhealthy
 	runtimeResult.StatusContext = contextMsg
 	runtimeResult.CreatedAt = result.CreatedAt // Keep the original file created time
+	runtimeResult.Restarts = result.Restarts
+	runtimeResult.LastRestartAt = result.LastRestartAt
+	runtimeResult.ImageUpdate = result.ImageUpdate
 	return runtimeResult, nil
 }
 
@@ -882,6 +971,9 @@ func (f *fileStatusManager) isProxyUnhealthy(
 	runtimeResult.Status = rt.WorkloadStatusUnhealthy
 	runtimeResult.StatusContext = contextMsg
 	runtimeResult.CreatedAt = result.CreatedAt // Keep the original file created time
+	runtimeResult.Restarts = result.Restarts
+	runtimeResult.LastRestartAt = result.LastRestartAt
+	runtimeResult.ImageUpdate = result.ImageUpdate
 	return runtimeResult, true
 }
 
@@ -896,6 +988,9 @@ func (*fileStatusManager) mergeHealthyWorkloadData(containerInfo rt.ContainerInf
 	runtimeResult.Status = result.Status               // Keep the file status (running)
 	runtimeResult.StatusContext = result.StatusContext // Keep the file status context
 	runtimeResult.CreatedAt = result.CreatedAt         // Keep the file created time
+	runtimeResult.Restarts = result.Restarts
+	runtimeResult.LastRestartAt = result.LastRestartAt
+	runtimeResult.ImageUpdate = result.ImageUpdate
 	return runtimeResult, nil
 }
 
@@ -914,6 +1009,9 @@ func (f *fileStatusManager) validateWorkloadInList(
 		runtimeWorkload.Status = fileWorkload.Status
 		runtimeWorkload.StatusContext = fileWorkload.StatusContext
 		runtimeWorkload.CreatedAt = fileWorkload.CreatedAt
+		runtimeWorkload.Restarts = fileWorkload.Restarts
+		runtimeWorkload.LastRestartAt = fileWorkload.LastRestartAt
+		runtimeWorkload.ImageUpdate = fileWorkload.ImageUpdate
 		return runtimeWorkload, nil
 	}
 
@@ -984,6 +1082,9 @@ func (f *fileStatusManager) mergeRuntimeAndFileWorkloads(
 					runtimeWorkload.Status = fileWorkload.Status
 					runtimeWorkload.StatusContext = fileWorkload.StatusContext
 					runtimeWorkload.CreatedAt = fileWorkload.CreatedAt
+					runtimeWorkload.Restarts = fileWorkload.Restarts
+					runtimeWorkload.LastRestartAt = fileWorkload.LastRestartAt
+					runtimeWorkload.ImageUpdate = fileWorkload.ImageUpdate
 					workloadMap[name] = runtimeWorkload
 				} else {
 					// Runtime workload not found, just use the file workload
@@ -1687,6 +1687,94 @@ func TestFileStatusManager_ResetWorkloadPID_WithSlashes(t *testing.T) {
 }
 
 // TestFileStatusManager_GetWorkload_PIDMigration tests PID migration from legacy PID files to status files
+func TestFileStatusManager_SetWorkloadRestarts(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	manager, mockRuntime, mockRunConfigStore := newTestFileStatusManager(t, ctrl)
+	ctx := context.Background()
+
+	mockRunConfigStore.EXPECT().Exists(gomock.Any(), "test-workload").Return(true, nil).AnyTimes()
+	mockRunConfigStore.EXPECT().GetReader(gomock.Any(), "test-workload").DoAndReturn(
+		func(_ context.Context, _ string) (io.ReadCloser, error) {
+			return io.NopCloser(strings.NewReader(`{"name": "test-workload", "transport": "sse"}`)), nil
+		}).AnyTimes()
+	mockRuntime.EXPECT().GetWorkloadInfo(gomock.Any(), "test-workload").
+		Return(rt.ContainerInfo{}, errors.New("workload not found")).AnyTimes()
+
+	// Recording restarts of a non-existent workload is a noop
+	require.NoError(t, manager.SetWorkloadRestarts(ctx, "test-workload", 1))
+	require.NoFileExists(t, filepath.Join(manager.baseDir, "test-workload.json"))
+
+	require.NoError(t, manager.SetWorkloadStatus(ctx, "test-workload", rt.WorkloadStatusStarting, "restarting"))
+	require.NoError(t, manager.SetWorkloadRestarts(ctx, "test-workload", 2))
+
+	workload, err := manager.GetWorkload(ctx, "test-workload")
+	require.NoError(t, err)
+	assert.Equal(t, 2, workload.Restarts)
+	require.NotNil(t, workload.LastRestartAt)
+	assert.WithinDuration(t, time.Now(), *workload.LastRestartAt, time.Minute)
+	assert.Equal(t, "restarting", workload.StatusContext, "the status must be preserved")
+
+	// Status updates preserve the restarts
+	require.NoError(t, manager.SetWorkloadStatus(ctx, "test-workload", rt.WorkloadStatusStopping, ""))
+	workload, err = manager.GetWorkload(ctx, "test-workload")
+	require.NoError(t, err)
+	assert.Equal(t, 2, workload.Restarts)
+
+	// Resetting the restarts clears the time of the last restart
+	require.NoError(t, manager.SetWorkloadRestarts(ctx, "test-workload", 0))
+	workload, err = manager.GetWorkload(ctx, "test-workload")
+	require.NoError(t, err)
+	assert.Zero(t, workload.Restarts)
+	assert.Nil(t, workload.LastRestartAt)
+}
+
+func TestFileStatusManager_SetWorkloadImageUpdate(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	manager, mockRuntime, mockRunConfigStore := newTestFileStatusManager(t, ctrl)
+	ctx := context.Background()
+
+	mockRunConfigStore.EXPECT().Exists(gomock.Any(), "test-workload").Return(true, nil).AnyTimes()
+	mockRunConfigStore.EXPECT().GetReader(gomock.Any(), "test-workload").DoAndReturn(
+		func(_ context.Context, _ string) (io.ReadCloser, error) {
+			return io.NopCloser(strings.NewReader(`{"name": "test-workload", "transport": "sse"}`)), nil
+		}).AnyTimes()
+	mockRuntime.EXPECT().GetWorkloadInfo(gomock.Any(), "test-workload").
+		Return(rt.ContainerInfo{}, errors.New("workload not found")).AnyTimes()
+
+	update := &core.ImageUpdate{
+		Image:        "ghcr.io/example/server:v1",
+		Available:    true,
+		LatestDigest: "sha256:abc",
+		CheckedAt:    time.Now().UTC().Truncate(time.Second),
+	}
+
+	// Recording the image update of a non-existent workload is a noop
+	require.NoError(t, manager.SetWorkloadImageUpdate(ctx, "test-workload", update))
+	require.NoFileExists(t, filepath.Join(manager.baseDir, "test-workload.json"))
+
+	require.NoError(t, manager.SetWorkloadStatus(ctx, "test-workload", rt.WorkloadStatusStarting, "starting"))
+	require.NoError(t, manager.SetWorkloadImageUpdate(ctx, "test-workload", update))
+
+	workload, err := manager.GetWorkload(ctx, "test-workload")
+	require.NoError(t, err)
+	require.NotNil(t, workload.ImageUpdate)
+	assert.Equal(t, *update, *workload.ImageUpdate)
+
+	// Status updates preserve the image update
+	require.NoError(t, manager.SetWorkloadStatus(ctx, "test-workload", rt.WorkloadStatusStopping, ""))
+	workload, err = manager.GetWorkload(ctx, "test-workload")
+	require.NoError(t, err)
+	assert.NotNil(t, workload.ImageUpdate)
+}
+
 func TestFileStatusManager_GetWorkload_PIDMigration(t *testing.T) {
 	t.Parallel()
 
@@ -129,6 +129,34 @@ func (mr *MockStatusManagerMockRecorder) SetWorkloadPID(ctx, workloadName, pid a
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetWorkloadPID", reflect.TypeOf((*MockStatusManager)(nil).SetWorkloadPID), ctx, workloadName, pid)
 }
 
+// SetWorkloadImageUpdate mocks base method.
+func (m *MockStatusManager) SetWorkloadImageUpdate(ctx context.Context, workloadName string, update *core.ImageUpdate) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetWorkloadImageUpdate", ctx, workloadName, update)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetWorkloadImageUpdate indicates an expected call of SetWorkloadImageUpdate.
+func (mr *MockStatusManagerMockRecorder) SetWorkloadImageUpdate(ctx, workloadName, update any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetWorkloadImageUpdate", reflect.TypeOf((*MockStatusManager)(nil).SetWorkloadImageUpdate), ctx, workloadName, update)
+}
+
+// SetWorkloadRestarts mocks base method.
+func (m *MockStatusManager) SetWorkloadRestarts(ctx context.Context, workloadName string, restarts int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetWorkloadRestarts", ctx, workloadName, restarts)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetWorkloadRestarts indicates an expected call of SetWorkloadRestarts.
+func (mr *MockStatusManagerMockRecorder) SetWorkloadRestarts(ctx, workloadName, restarts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetWorkloadRestarts", reflect.TypeOf((*MockStatusManager)(nil).SetWorkloadRestarts), ctx, workloadName, restarts)
+}
+
 // SetWorkloadStatus mocks base method.
 func (m *MockStatusManager) SetWorkloadStatus(ctx context.Context, workloadName string, status runtime.WorkloadStatus, contextMsg string) error {
 	m.ctrl.T.Helper()
@@ -50,3 +50,13 @@ func (*NoopStatusManager) ResetWorkloadPID(_ context.Context, _ string) error {
 func (*NoopStatusManager) GetWorkloadPID(_ context.Context, _ string) (int, error) {
 	return 0, nil
 }
+
+// SetWorkloadRestarts does nothing and returns nil.
+func (*NoopStatusManager) SetWorkloadRestarts(_ context.Context, _ string, _ int) error {
+	return nil
+}
+
+// SetWorkloadImageUpdate does nothing and returns nil.
+func (*NoopStatusManager) SetWorkloadImageUpdate(_ context.Context, _ string, _ *core.ImageUpdate) error {
+	return nil
+}
@@ -36,6 +36,13 @@ type StatusManager interface {
 	// GetWorkloadPID retrieves the PID of a workload by its name.
 	// Returns 0 if the workload does not exist or if PID is not available.
 	GetWorkloadPID(ctx context.Context, workloadName string) (int, error)
+	// SetWorkloadRestarts records the number of times a workload has been restarted
+	// after exiting unexpectedly. A non-zero count also records the time of the restart.
+	// This method will do nothing if the workload does not exist.
+	SetWorkloadRestarts(ctx context.Context, workloadName string, restarts int) error
+	// SetWorkloadImageUpdate records the result of the last check for a newer image of a workload.
+	// This method will do nothing if the workload does not exist.
+	SetWorkloadImageUpdate(ctx context.Context, workloadName string, update *core.ImageUpdate) error
 }
 
 // NewStatusManagerFromRuntime creates a new instance of StatusManager from an existing runtime.
@@ -148,3 +155,15 @@ func (*runtimeStatusManager) GetWorkloadPID(_ context.Context, workloadName stri
 	logger.Debugf("workload %s PID requested (noop for runtime status manager, returning 0)", workloadName)
 	return 0, nil
 }
+
+func (*runtimeStatusManager) SetWorkloadRestarts(_ context.Context, workloadName string, restarts int) error {
+	// Noop for runtime status manager - restarts are handled by the runtime
+	logger.Debugf("workload %s restarts set to %d (noop for runtime status manager)", workloadName, restarts)
+	return nil
+}
+
+func (*runtimeStatusManager) SetWorkloadImageUpdate(_ context.Context, workloadName string, _ *core.ImageUpdate) error {
+	// Noop for runtime status manager - images are not checked for updates in Kubernetes
+	logger.Debugf("workload %s image update set (noop for runtime status manager)", workloadName)
+	return nil
+}